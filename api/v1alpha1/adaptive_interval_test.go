@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAdaptiveIntervalValidation(t *testing.T) {
+	duration := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+	score := func(n int32) *int32 { return &n }
+
+	tests := []struct {
+		name     string
+		adaptive *AdaptiveInterval
+		wantErr  bool
+	}{
+		{name: "unset"},
+		{name: "max above the reconciliation interval", adaptive: &AdaptiveInterval{MaxInterval: metav1.Duration{Duration: time.Hour}}},
+		{
+			name:     "explicit bounds",
+			adaptive: &AdaptiveInterval{MinInterval: duration(time.Minute), MaxInterval: metav1.Duration{Duration: time.Hour}, StableScore: score(90)},
+		},
+		{name: "missing max", adaptive: &AdaptiveInterval{}, wantErr: true},
+		{name: "max below the reconciliation interval", adaptive: &AdaptiveInterval{MaxInterval: metav1.Duration{Duration: time.Minute}}, wantErr: true},
+		{
+			name:     "min above max",
+			adaptive: &AdaptiveInterval{MinInterval: duration(2 * time.Hour), MaxInterval: metav1.Duration{Duration: time.Hour}},
+			wantErr:  true,
+		},
+		{name: "zero min", adaptive: &AdaptiveInterval{MinInterval: duration(0), MaxInterval: metav1.Duration{Duration: time.Hour}}, wantErr: true},
+		{
+			name:     "stable score above 100",
+			adaptive: &AdaptiveInterval{MaxInterval: metav1.Duration{Duration: time.Hour}, StableScore: score(101)},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &OptimizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: DefaultNamespace},
+				Spec: OptimizationPolicySpec{
+					Mode: ModeAuto,
+					Selector: WorkloadSelector{
+						Namespaces: &NamespaceFilter{Allow: []string{DefaultNamespace}},
+					},
+					MetricsConfig: MetricsConfig{
+						Provider:   "prometheus",
+						Percentile: "P90",
+					},
+					ResourceBounds: ResourceBounds{
+						CPU:    ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+						Memory: ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("2Gi")},
+					},
+					ReconciliationInterval: metav1.Duration{Duration: 5 * time.Minute},
+					AdaptiveInterval:       tt.adaptive,
+				},
+			}
+
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyOrderValidation(t *testing.T) {
+	tests := []struct {
+		order   string
+		want    string
+		wantErr bool
+	}{
+		{order: "", want: ApplyOrderAlphaByName},
+		{order: ApplyOrderAlphaByName, want: ApplyOrderAlphaByName},
+		{order: ApplyOrderSmallestFirst, want: ApplyOrderSmallestFirst},
+		{order: ApplyOrderLargestSavingsFirst, want: ApplyOrderLargestSavingsFirst},
+		{order: "RiskiestFirst", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			policy := &OptimizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: DefaultNamespace},
+				Spec: OptimizationPolicySpec{
+					Mode: ModeAuto,
+					Selector: WorkloadSelector{
+						Namespaces: &NamespaceFilter{Allow: []string{DefaultNamespace}},
+					},
+					MetricsConfig: MetricsConfig{
+						Provider:   "prometheus",
+						Percentile: "P90",
+					},
+					ResourceBounds: ResourceBounds{
+						CPU:    ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+						Memory: ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("2Gi")},
+					},
+					UpdateStrategy: UpdateStrategy{
+						ApplyOrder: tt.order,
+					},
+				},
+			}
+
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := policy.Spec.UpdateStrategy.GetApplyOrder(); !tt.wantErr && got != tt.want {
+				t.Errorf("GetApplyOrder() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
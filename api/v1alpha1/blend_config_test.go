@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateBlendConfig(t *testing.T) {
+	weight := func(w float64) *float64 { return &w }
+
+	tests := []struct {
+		name          string
+		rollingWindow time.Duration
+		blend         *BlendConfig
+		wantErr       bool
+	}{
+		{name: "no blending", blend: nil, wantErr: false},
+		{
+			name:    "short window within default rolling window",
+			blend:   &BlendConfig{ShortWindow: metav1.Duration{Duration: time.Hour}},
+			wantErr: false,
+		},
+		{
+			name: "weighted rule with weight",
+			blend: &BlendConfig{
+				ShortWindow:     metav1.Duration{Duration: time.Hour},
+				ShortPercentile: "P90",
+				Rule:            BlendRuleWeighted,
+				ShortWeight:     weight(0.3),
+			},
+			wantErr: false,
+		},
+		{name: "missing short window", blend: &BlendConfig{}, wantErr: true},
+		{
+			name:          "short window not shorter than rolling window",
+			rollingWindow: 6 * time.Hour,
+			blend:         &BlendConfig{ShortWindow: metav1.Duration{Duration: 6 * time.Hour}},
+			wantErr:       true,
+		},
+		{
+			name:    "invalid short percentile",
+			blend:   &BlendConfig{ShortWindow: metav1.Duration{Duration: time.Hour}, ShortPercentile: "P95"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid rule",
+			blend:   &BlendConfig{ShortWindow: metav1.Duration{Duration: time.Hour}, Rule: "Min"},
+			wantErr: true,
+		},
+		{
+			name:    "weight above one",
+			blend:   &BlendConfig{ShortWindow: metav1.Duration{Duration: time.Hour}, ShortWeight: weight(1.5)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newContainerSelectorTestPolicy(ModeAuto)
+			policy.Spec.MetricsConfig.RollingWindow = metav1.Duration{Duration: tt.rollingWindow}
+			policy.Spec.MetricsConfig.Blend = tt.blend
+			err := policy.ValidateCreate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
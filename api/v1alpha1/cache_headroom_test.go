@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCacheHeadroomValidation(t *testing.T) {
+	percent := func(p int32) *int32 { return &p }
+	amount := func(s string) *resource.Quantity { q := resource.MustParse(s); return &q }
+
+	tests := []struct {
+		name     string
+		headroom *CacheHeadroom
+		wantErr  bool
+	}{
+		{name: "unset"},
+		{name: "percent", headroom: &CacheHeadroom{Percent: percent(25)}},
+		{name: "amount", headroom: &CacheHeadroom{Amount: amount("512Mi")}},
+		{name: "neither", headroom: &CacheHeadroom{}, wantErr: true},
+		{name: "both", headroom: &CacheHeadroom{Percent: percent(25), Amount: amount("512Mi")}, wantErr: true},
+		{name: "zero percent", headroom: &CacheHeadroom{Percent: percent(0)}, wantErr: true},
+		{name: "percent above 400", headroom: &CacheHeadroom{Percent: percent(401)}, wantErr: true},
+		{name: "zero amount", headroom: &CacheHeadroom{Amount: amount("0")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &OptimizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: DefaultNamespace},
+				Spec: OptimizationPolicySpec{
+					Mode: ModeAuto,
+					Selector: WorkloadSelector{
+						Namespaces: &NamespaceFilter{Allow: []string{DefaultNamespace}},
+					},
+					MetricsConfig: MetricsConfig{Provider: "prometheus", Percentile: "P90"},
+					ResourceBounds: ResourceBounds{
+						CPU:    ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+						Memory: ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("2Gi")},
+					},
+					CacheHeadroom: tt.headroom,
+				},
+			}
+
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCacheHeadroom_Headroom(t *testing.T) {
+	percent := int32(50)
+	amount := resource.MustParse("1Gi")
+	usage := resource.MustParse("256Mi")
+
+	if got := (*CacheHeadroom)(nil).Headroom(usage); !got.IsZero() {
+		t.Errorf("nil Headroom() = %s, want zero", got.String())
+	}
+	if got := (&CacheHeadroom{Percent: &percent}).Headroom(usage); got.Cmp(resource.MustParse("128Mi")) != 0 {
+		t.Errorf("Headroom() with 50%% = %s, want 128Mi", got.String())
+	}
+	if got := (&CacheHeadroom{Amount: &amount}).Headroom(usage); got.Cmp(amount) != 0 {
+		t.Errorf("Headroom() with an amount = %s, want 1Gi", got.String())
+	}
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newContainerSelectorTestPolicy(mode PolicyMode, selectors ...ContainerSelector) *OptimizationPolicy {
+	return &OptimizationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-policy",
+			Namespace: "default",
+		},
+		Spec: OptimizationPolicySpec{
+			Mode: mode,
+			Selector: WorkloadSelector{
+				WorkloadSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "test"},
+				},
+			},
+			MetricsConfig: MetricsConfig{
+				Provider: "prometheus",
+			},
+			ResourceBounds: ResourceBounds{
+				CPU: ResourceBound{
+					Min: resource.MustParse("100m"),
+					Max: resource.MustParse("4000m"),
+				},
+				Memory: ResourceBound{
+					Min: resource.MustParse("128Mi"),
+					Max: resource.MustParse("8Gi"),
+				},
+			},
+			ContainerSelectors: selectors,
+		},
+	}
+}
+
+func TestMatchContainerSelector(t *testing.T) {
+	tests := []struct {
+		name          string
+		selectors     []ContainerSelector
+		containerName string
+		wantMatched   bool
+		wantSelector  string
+	}{
+		{
+			name:          "no selectors matches every container",
+			containerName: "app",
+			wantMatched:   true,
+		},
+		{
+			name:          "exact name match",
+			selectors:     []ContainerSelector{{Name: "app"}},
+			containerName: "app",
+			wantMatched:   true,
+			wantSelector:  "app",
+		},
+		{
+			name:          "glob suffix match",
+			selectors:     []ContainerSelector{{Name: "*-sidecar"}},
+			containerName: "istio-sidecar",
+			wantMatched:   true,
+			wantSelector:  "*-sidecar",
+		},
+		{
+			name:          "glob character class match",
+			selectors:     []ContainerSelector{{Name: "worker-[0-9]"}},
+			containerName: "worker-3",
+			wantMatched:   true,
+			wantSelector:  "worker-[0-9]",
+		},
+		{
+			name:          "regex match",
+			selectors:     []ContainerSelector{{Name: "worker-[0-9]+", MatchType: ContainerMatchRegex}},
+			containerName: "worker-12",
+			wantMatched:   true,
+			wantSelector:  "worker-[0-9]+",
+		},
+		{
+			name:          "regex matches the whole container name",
+			selectors:     []ContainerSelector{{Name: "worker", MatchType: ContainerMatchRegex}},
+			containerName: "worker-12",
+			wantMatched:   false,
+		},
+		{
+			name:          "glob is not matched as a regex",
+			selectors:     []ContainerSelector{{Name: "worker-[0-9]+"}},
+			containerName: "worker-12",
+			wantMatched:   false,
+		},
+		{
+			name:          "containers matching nothing are not selected",
+			selectors:     []ContainerSelector{{Name: "app"}},
+			containerName: "envoy",
+			wantMatched:   false,
+		},
+		{
+			name: "first matching selector wins",
+			selectors: []ContainerSelector{
+				{Name: "app-*", Mode: ModeRecommend},
+				{Name: "*", Mode: ModeAuto},
+			},
+			containerName: "app-main",
+			wantMatched:   true,
+			wantSelector:  "app-*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newContainerSelectorTestPolicy(ModeAuto, tt.selectors...)
+			selector, matched := policy.MatchContainerSelector(tt.containerName)
+			if matched != tt.wantMatched {
+				t.Fatalf("MatchContainerSelector() matched = %v, want %v", matched, tt.wantMatched)
+			}
+			if tt.wantSelector == "" && selector != nil {
+				t.Errorf("MatchContainerSelector() selector = %q, want nil", selector.Name)
+			}
+			if tt.wantSelector != "" && (selector == nil || selector.Name != tt.wantSelector) {
+				t.Errorf("MatchContainerSelector() selector = %v, want %q", selector, tt.wantSelector)
+			}
+		})
+	}
+}
+
+func TestContainerSelector_RegexCompiledOnce(t *testing.T) {
+	selector := ContainerSelector{Name: "cache-[a-z]+", MatchType: ContainerMatchRegex}
+	first, err := selector.compileRegex()
+	if err != nil {
+		t.Fatalf("compileRegex() error = %v", err)
+	}
+	// A copy of the selector, e.g. from a later reconcile of the policy, reuses the compilation
+	copied := selector
+	if second, _ := copied.compileRegex(); second != first {
+		t.Error("compileRegex() compiled the same name again")
+	}
+	if !selector.Matches("cache-redis") || selector.Matches("cache-1") {
+		t.Error("Matches() did not use the compiled pattern")
+	}
+}
+
+func TestGetContainerMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		policyMode PolicyMode
+		selector   *ContainerSelector
+		want       PolicyMode
+	}{
+		{
+			name:       "nil selector uses policy mode",
+			policyMode: ModeAuto,
+			want:       ModeAuto,
+		},
+		{
+			name:       "empty selector mode uses policy mode",
+			policyMode: ModeRecommend,
+			selector:   &ContainerSelector{Name: "app"},
+			want:       ModeRecommend,
+		},
+		{
+			name:       "selector narrows Auto to Recommend",
+			policyMode: ModeAuto,
+			selector:   &ContainerSelector{Name: "sidecar", Mode: ModeRecommend},
+			want:       ModeRecommend,
+		},
+		{
+			name:       "selector narrows Auto to Disabled",
+			policyMode: ModeAuto,
+			selector:   &ContainerSelector{Name: "sidecar", Mode: ModeDisabled},
+			want:       ModeDisabled,
+		},
+		{
+			name:       "selector cannot widen Recommend to Auto",
+			policyMode: ModeRecommend,
+			selector:   &ContainerSelector{Name: "app", Mode: ModeAuto},
+			want:       ModeRecommend,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newContainerSelectorTestPolicy(tt.policyMode)
+			if got := policy.GetContainerMode(tt.selector); got != tt.want {
+				t.Errorf("GetContainerMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateContainerSelectors(t *testing.T) {
+	tests := []struct {
+		name      string
+		selectors []ContainerSelector
+		wantErr   bool
+	}{
+		{
+			name:      "valid glob selectors",
+			selectors: []ContainerSelector{{Name: "app"}, {Name: "*-sidecar", Mode: ModeRecommend}},
+			wantErr:   false,
+		},
+		{
+			name:      "empty name",
+			selectors: []ContainerSelector{{Name: ""}},
+			wantErr:   true,
+		},
+		{
+			name:      "malformed glob pattern",
+			selectors: []ContainerSelector{{Name: "app-["}},
+			wantErr:   true,
+		},
+		{
+			name:      "valid regex selector",
+			selectors: []ContainerSelector{{Name: "(app|web)-[a-z]+", MatchType: ContainerMatchRegex}},
+			wantErr:   false,
+		},
+		{
+			name:      "malformed regex",
+			selectors: []ContainerSelector{{Name: "app-(", MatchType: ContainerMatchRegex}},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid match type",
+			selectors: []ContainerSelector{{Name: "app", MatchType: "Prefix"}},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid mode",
+			selectors: []ContainerSelector{{Name: "app", Mode: "Sometimes"}},
+			wantErr:   true,
+		},
+		{
+			name: "per-container bounds with min greater than max",
+			selectors: []ContainerSelector{{
+				Name: "app",
+				ResourceBounds: &ResourceBounds{
+					CPU: ResourceBound{
+						Min: resource.MustParse("2"),
+						Max: resource.MustParse("1"),
+					},
+					Memory: ResourceBound{
+						Min: resource.MustParse("128Mi"),
+						Max: resource.MustParse("1Gi"),
+					},
+				},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newContainerSelectorTestPolicy(ModeAuto, tt.selectors...)
+			err := policy.ValidateCreate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsContainerExcluded(t *testing.T) {
+	policy := newContainerSelectorTestPolicy(ModeAuto)
+	policy.Spec.ExcludeContainers = []string{"vault-agent", "linkerd-*"}
+
+	tests := []struct {
+		container string
+		want      bool
+	}{
+		{container: "vault-agent", want: true},
+		{container: "linkerd-proxy", want: true},
+		{container: "linkerd-init", want: true},
+		{container: "app", want: false},
+		{container: "vault-agent-init", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.container, func(t *testing.T) {
+			if got := policy.IsContainerExcluded(tt.container); got != tt.want {
+				t.Errorf("IsContainerExcluded(%q) = %v, want %v", tt.container, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateExcludeContainers(t *testing.T) {
+	tests := []struct {
+		name    string
+		exclude []string
+		wantErr bool
+	}{
+		{name: "valid patterns", exclude: []string{"vault-agent", "istio-*"}, wantErr: false},
+		{name: "empty pattern", exclude: []string{""}, wantErr: true},
+		{name: "malformed glob pattern", exclude: []string{"sidecar-["}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newContainerSelectorTestPolicy(ModeAuto)
+			policy.Spec.ExcludeContainers = tt.exclude
+			err := policy.ValidateCreate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
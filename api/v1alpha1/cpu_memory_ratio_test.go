@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCPUMemoryRatioValidation(t *testing.T) {
+	millicores := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name    string
+		ratio   *CPUMemoryRatio
+		wantErr bool
+	}{
+		{name: "unset"},
+		{name: "band", ratio: &CPUMemoryRatio{MinMillicoresPerGiB: millicores(50), MaxMillicoresPerGiB: millicores(4000)}},
+		{name: "minimum only", ratio: &CPUMemoryRatio{MinMillicoresPerGiB: millicores(0)}},
+		{name: "minimum above maximum", ratio: &CPUMemoryRatio{MinMillicoresPerGiB: millicores(500), MaxMillicoresPerGiB: millicores(100)}, wantErr: true},
+		{name: "minimum above default maximum", ratio: &CPUMemoryRatio{MinMillicoresPerGiB: millicores(50000)}, wantErr: true},
+		{name: "negative minimum", ratio: &CPUMemoryRatio{MinMillicoresPerGiB: millicores(-1)}, wantErr: true},
+		{name: "zero maximum", ratio: &CPUMemoryRatio{MaxMillicoresPerGiB: millicores(0)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &OptimizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: DefaultNamespace},
+				Spec: OptimizationPolicySpec{
+					Mode: ModeAuto,
+					Selector: WorkloadSelector{
+						Namespaces: &NamespaceFilter{Allow: []string{DefaultNamespace}},
+					},
+					MetricsConfig: MetricsConfig{Provider: "prometheus", Percentile: "P90"},
+					ResourceBounds: ResourceBounds{
+						CPU:    ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+						Memory: ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("2Gi")},
+					},
+					CPUMemoryRatio: tt.ratio,
+				},
+			}
+
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
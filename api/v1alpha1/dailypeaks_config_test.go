@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func TestValidateDailyPeaksConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   string
+		dailyPeaks *DailyPeaksConfig
+		wantErr    bool
+	}{
+		{name: "no daily peaks", provider: "metrics-server", dailyPeaks: nil, wantErr: false},
+		{name: "defaults", provider: "prometheus", dailyPeaks: &DailyPeaksConfig{}, wantErr: false},
+		{name: "days and percentile", provider: "otel-promql", dailyPeaks: &DailyPeaksConfig{Days: 14, Percentile: "P90"}, wantErr: false},
+		{name: "too few days", provider: "prometheus", dailyPeaks: &DailyPeaksConfig{Days: 2}, wantErr: true},
+		{name: "too many days", provider: "prometheus", dailyPeaks: &DailyPeaksConfig{Days: 31}, wantErr: true},
+		{name: "invalid percentile", provider: "prometheus", dailyPeaks: &DailyPeaksConfig{Percentile: "P95"}, wantErr: true},
+		{name: "metrics-server keeps no history", provider: "metrics-server", dailyPeaks: &DailyPeaksConfig{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newContainerSelectorTestPolicy(ModeAuto)
+			policy.Spec.MetricsConfig.Provider = tt.provider
+			policy.Spec.MetricsConfig.DailyPeaks = tt.dailyPeaks
+			err := policy.ValidateCreate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDailyPeaksConfigDefaults(t *testing.T) {
+	config := &DailyPeaksConfig{}
+	if config.GetDays() != DefaultDailyPeakDays || config.GetPercentile() != "P99" {
+		t.Errorf("defaults = %d days at %s, want %d days at P99", config.GetDays(), config.GetPercentile(), DefaultDailyPeakDays)
+	}
+}
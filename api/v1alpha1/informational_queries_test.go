@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestValidateInformationalQueries(t *testing.T) {
+	tooMany := make([]InformationalQuery, 0, MaxInformationalQueries+1)
+	for i := 0; i <= MaxInformationalQueries; i++ {
+		tooMany = append(tooMany, InformationalQuery{Name: fmt.Sprintf("query-%d", i), Query: "up"})
+	}
+
+	tests := []struct {
+		name    string
+		queries []InformationalQuery
+		wantErr bool
+	}{
+		{
+			name:    "no queries",
+			queries: nil,
+			wantErr: false,
+		},
+		{
+			name: "valid queries",
+			queries: []InformationalQuery{
+				{Name: "request-rate", Query: `sum(rate(http_requests_total{namespace="$namespace"}[5m]))`},
+				{Name: "error-rate", Query: `sum(rate(http_errors_total{namespace="$namespace"}[5m]))`},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "too many queries",
+			queries: tooMany,
+			wantErr: true,
+		},
+		{
+			name: "duplicate names",
+			queries: []InformationalQuery{
+				{Name: "request-rate", Query: "up"},
+				{Name: "request-rate", Query: "up"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "empty name",
+			queries: []InformationalQuery{{Query: "up"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty query",
+			queries: []InformationalQuery{{Name: "request-rate", Query: " "}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateInformationalQueries(tt.queries)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateInformationalQueries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
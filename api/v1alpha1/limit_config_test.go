@@ -0,0 +1,227 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/utils/ptr"
+)
+
+func float64Ptr(value float64) *float64 {
+	return &value
+}
+
+func TestValidateLimitConfig(t *testing.T) {
+	tests := []struct {
+		name              string
+		strategy          UpdateStrategy
+		requestPercentile string
+		wantErr           bool
+	}{
+		{
+			name:              "no limit config",
+			strategy:          UpdateStrategy{UpdateRequestsOnly: true},
+			requestPercentile: "P90",
+			wantErr:           false,
+		},
+		{
+			name: "multiplier only",
+			strategy: UpdateStrategy{
+				UpdateRequestsOnly: true,
+				LimitConfig:        &LimitConfig{},
+			},
+			requestPercentile: "P90",
+			wantErr:           false,
+		},
+		{
+			name: "requests at P90 and limits at P99",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{MemoryLimitPercentile: "P99"},
+			},
+			requestPercentile: "P90",
+			wantErr:           false,
+		},
+		{
+			name: "same percentile for requests and limits",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{MemoryLimitPercentile: "P90"},
+			},
+			requestPercentile: "",
+			wantErr:           false,
+		},
+		{
+			name: "limit percentile lower than request percentile",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{MemoryLimitPercentile: "P90"},
+			},
+			requestPercentile: "P99",
+			wantErr:           true,
+		},
+		{
+			name: "limit percentile with updateRequestsOnly",
+			strategy: UpdateStrategy{
+				UpdateRequestsOnly: true,
+				LimitConfig:        &LimitConfig{MemoryLimitPercentile: "P99"},
+			},
+			requestPercentile: "P90",
+			wantErr:           true,
+		},
+		{
+			name: "limit percentile with removeLimits",
+			strategy: UpdateStrategy{
+				RemoveLimits: true,
+				LimitConfig:  &LimitConfig{MemoryLimitPercentile: "P99"},
+			},
+			requestPercentile: "P90",
+			wantErr:           true,
+		},
+		{
+			name: "unknown limit percentile",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{MemoryLimitPercentile: "P95"},
+			},
+			requestPercentile: "P90",
+			wantErr:           true,
+		},
+		{
+			name: "burst ratio limits with defaults",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{BurstRatioLimits: &BurstRatioLimits{}},
+			},
+			requestPercentile: "P90",
+			wantErr:           false,
+		},
+		{
+			name: "burst ratio limits with a single ratio",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{BurstRatioLimits: &BurstRatioLimits{MinRatio: float64Ptr(2.0), MaxRatio: float64Ptr(2.0)}},
+			},
+			requestPercentile: "P90",
+			wantErr:           false,
+		},
+		{
+			name: "burst ratio minimum above maximum",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{BurstRatioLimits: &BurstRatioLimits{MinRatio: float64Ptr(4.0), MaxRatio: float64Ptr(2.0)}},
+			},
+			requestPercentile: "P90",
+			wantErr:           true,
+		},
+		{
+			name: "burst ratio minimum above the default maximum",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{BurstRatioLimits: &BurstRatioLimits{MinRatio: float64Ptr(4.0)}},
+			},
+			requestPercentile: "P90",
+			wantErr:           true,
+		},
+		{
+			name: "burst ratio below one",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{BurstRatioLimits: &BurstRatioLimits{MinRatio: float64Ptr(0.5)}},
+			},
+			requestPercentile: "P90",
+			wantErr:           true,
+		},
+		{
+			name: "burst ratio above ten",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{BurstRatioLimits: &BurstRatioLimits{MaxRatio: float64Ptr(12.0)}},
+			},
+			requestPercentile: "P90",
+			wantErr:           true,
+		},
+		{
+			name: "burst ratio limits with updateRequestsOnly",
+			strategy: UpdateStrategy{
+				UpdateRequestsOnly: true,
+				LimitConfig:        &LimitConfig{BurstRatioLimits: &BurstRatioLimits{}},
+			},
+			requestPercentile: "P90",
+			wantErr:           true,
+		},
+		{
+			name: "burst ratio limits with removeLimits",
+			strategy: UpdateStrategy{
+				RemoveLimits: true,
+				LimitConfig:  &LimitConfig{BurstRatioLimits: &BurstRatioLimits{}},
+			},
+			requestPercentile: "P90",
+			wantErr:           true,
+		},
+		{
+			name: "burst ratio limits with a limit percentile",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{MemoryLimitPercentile: "P99", BurstRatioLimits: &BurstRatioLimits{}},
+			},
+			requestPercentile: "P90",
+			wantErr:           true,
+		},
+		{
+			name: "memory limit only",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{ManageCPULimit: ptr.To(false), MemoryLimitPercentile: "P99"},
+			},
+			requestPercentile: "P90",
+			wantErr:           false,
+		},
+		{
+			name: "limit percentile with the memory limit not managed",
+			strategy: UpdateStrategy{
+				LimitConfig: &LimitConfig{ManageMemoryLimit: ptr.To(false), MemoryLimitPercentile: "P99"},
+			},
+			requestPercentile: "P90",
+			wantErr:           true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLimitConfig(tt.strategy, tt.requestPercentile)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateLimitConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManagesLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     *LimitConfig
+		wantCPU    bool
+		wantMemory bool
+	}{
+		{name: "no limit config", wantCPU: true, wantMemory: true},
+		{name: "unset", config: &LimitConfig{}, wantCPU: true, wantMemory: true},
+		{name: "memory limit only", config: &LimitConfig{ManageCPULimit: ptr.To(false)}, wantCPU: false, wantMemory: true},
+		{name: "CPU limit only", config: &LimitConfig{ManageCPULimit: ptr.To(true), ManageMemoryLimit: ptr.To(false)}, wantCPU: true, wantMemory: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := UpdateStrategy{LimitConfig: tt.config}
+			if got := strategy.ManagesCPULimit(); got != tt.wantCPU {
+				t.Errorf("ManagesCPULimit() = %v, want %v", got, tt.wantCPU)
+			}
+			if got := strategy.ManagesMemoryLimit(); got != tt.wantMemory {
+				t.Errorf("ManagesMemoryLimit() = %v, want %v", got, tt.wantMemory)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMemoryPeakFloorValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		floor   *MemoryPeakFloor
+		wantErr bool
+	}{
+		{name: "unset"},
+		{name: "kept for the lifetime of the workload", floor: &MemoryPeakFloor{}},
+		{name: "retention above the rolling window", floor: &MemoryPeakFloor{Retention: &metav1.Duration{Duration: 30 * 24 * time.Hour}}},
+		{name: "retention equal to the rolling window", floor: &MemoryPeakFloor{Retention: &metav1.Duration{Duration: 24 * time.Hour}}},
+		{name: "retention below the rolling window", floor: &MemoryPeakFloor{Retention: &metav1.Duration{Duration: time.Hour}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &OptimizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: DefaultNamespace},
+				Spec: OptimizationPolicySpec{
+					Mode: ModeAuto,
+					Selector: WorkloadSelector{
+						Namespaces: &NamespaceFilter{Allow: []string{DefaultNamespace}},
+					},
+					MetricsConfig: MetricsConfig{
+						Provider:        "prometheus",
+						Percentile:      "P90",
+						RollingWindow:   metav1.Duration{Duration: 24 * time.Hour},
+						MemoryPeakFloor: tt.floor,
+					},
+					ResourceBounds: ResourceBounds{
+						CPU:    ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+						Memory: ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("2Gi")},
+					},
+				},
+			}
+
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMemoryPeakFloor_Expired(t *testing.T) {
+	observedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	lifetime := &MemoryPeakFloor{}
+	week := &MemoryPeakFloor{Retention: &metav1.Duration{Duration: 7 * 24 * time.Hour}}
+
+	if lifetime.Expired(observedAt, observedAt.AddDate(1, 0, 0)) {
+		t.Error("Expired() = true without a retention, want the peak kept for the lifetime of the workload")
+	}
+	if week.Expired(observedAt, observedAt.AddDate(0, 0, 7)) {
+		t.Error("Expired() = true at the end of the retention, want false")
+	}
+	if !week.Expired(observedAt, observedAt.AddDate(0, 0, 8)) {
+		t.Error("Expired() = false after the retention, want true")
+	}
+}
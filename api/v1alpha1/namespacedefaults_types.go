@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceDefaultsSpec defines per-namespace constraints applied on top of every matching policy
+type NamespaceDefaultsSpec struct {
+	// ResourceBounds tightens the resource bounds of every policy for workloads in this namespace.
+	// The namespace can only narrow a policy's bounds, never widen them.
+	// +required
+	ResourceBounds NamespaceResourceBounds `json:"resourceBounds"`
+}
+
+// NamespaceResourceBounds defines optional min/max constraints per resource type
+type NamespaceResourceBounds struct {
+	// CPU constrains CPU recommendations
+	// +optional
+	CPU *NamespaceResourceBound `json:"cpu,omitempty"`
+
+	// Memory constrains memory recommendations
+	// +optional
+	Memory *NamespaceResourceBound `json:"memory,omitempty"`
+}
+
+// NamespaceResourceBound defines an optional min and max for a single resource type
+type NamespaceResourceBound struct {
+	// Min raises the policy minimum when it is higher
+	// +optional
+	Min *resource.Quantity `json:"min,omitempty"`
+
+	// Max lowers the policy maximum when it is lower
+	// +optional
+	Max *resource.Quantity `json:"max,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,shortName=nsdefaults
+// +kubebuilder:printcolumn:name="CPU Max",type=string,JSONPath=`.spec.resourceBounds.cpu.max`
+// +kubebuilder:printcolumn:name="Memory Max",type=string,JSONPath=`.spec.resourceBounds.memory.max`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NamespaceDefaults is the Schema for the namespacedefaults API. It holds per-namespace
+// resource bounds (e.g. lower ceilings for dev namespaces) that OptiPod merges with the
+// bounds of every policy matching workloads in the namespace.
+type NamespaceDefaults struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the namespace constraints
+	// +required
+	Spec NamespaceDefaultsSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespaceDefaultsList contains a list of NamespaceDefaults
+type NamespaceDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []NamespaceDefaults `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespaceDefaults{}, &NamespaceDefaultsList{})
+}
+
+// Validate checks that each namespace bound is internally consistent
+func (d *NamespaceDefaults) Validate() error {
+	if err := validateNamespaceResourceBound(d.Spec.ResourceBounds.CPU, "cpu"); err != nil {
+		return err
+	}
+	return validateNamespaceResourceBound(d.Spec.ResourceBounds.Memory, "memory")
+}
+
+// validateNamespaceResourceBound validates a single optional namespace bound
+func validateNamespaceResourceBound(bound *NamespaceResourceBound, resourceName string) error {
+	if bound == nil {
+		return nil
+	}
+
+	if bound.Min != nil && bound.Min.Sign() <= 0 {
+		return fmt.Errorf("resourceBounds.%s.min must be greater than zero", resourceName)
+	}
+
+	if bound.Max != nil && bound.Max.Sign() <= 0 {
+		return fmt.Errorf("resourceBounds.%s.max must be greater than zero", resourceName)
+	}
+
+	if bound.Min != nil && bound.Max != nil && bound.Min.Cmp(*bound.Max) > 0 {
+		return fmt.Errorf("resourceBounds.%s.min (%s) must be less than or equal to max (%s)",
+			resourceName, bound.Min.String(), bound.Max.String())
+	}
+
+	return nil
+}
+
+// MergeResourceBounds tightens policy bounds with namespace bounds: the higher minimum and
+// the lower maximum win. It returns an error describing the conflict when the merged
+// minimum ends up above the merged maximum, since no recommendation could satisfy both.
+func MergeResourceBounds(bounds ResourceBounds, namespaceBounds NamespaceResourceBounds) (ResourceBounds, error) {
+	merged := *bounds.DeepCopy()
+
+	if err := tightenResourceBound(&merged.CPU, namespaceBounds.CPU, "cpu"); err != nil {
+		return bounds, err
+	}
+	if err := tightenResourceBound(&merged.Memory, namespaceBounds.Memory, "memory"); err != nil {
+		return bounds, err
+	}
+
+	return merged, nil
+}
+
+// tightenResourceBound narrows a single bound in place
+func tightenResourceBound(bound *ResourceBound, namespaceBound *NamespaceResourceBound, resourceName string) error {
+	if namespaceBound == nil {
+		return nil
+	}
+
+	if namespaceBound.Min != nil && namespaceBound.Min.Cmp(bound.Min) > 0 {
+		bound.Min = namespaceBound.Min.DeepCopy()
+	}
+	if namespaceBound.Max != nil && namespaceBound.Max.Cmp(bound.Max) < 0 {
+		bound.Max = namespaceBound.Max.DeepCopy()
+	}
+
+	if bound.Min.Cmp(bound.Max) > 0 {
+		return fmt.Errorf("namespace %s bounds conflict with policy bounds: merged min (%s) is greater than merged max (%s)",
+			resourceName, bound.Min.String(), bound.Max.String())
+	}
+
+	return nil
+}
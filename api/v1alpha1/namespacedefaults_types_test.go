@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func quantityPtr(value string) *resource.Quantity {
+	q := resource.MustParse(value)
+	return &q
+}
+
+func TestMergeResourceBounds(t *testing.T) {
+	policyBounds := ResourceBounds{
+		CPU:    ResourceBound{Min: resource.MustParse("100m"), Max: resource.MustParse("4")},
+		Memory: ResourceBound{Min: resource.MustParse("128Mi"), Max: resource.MustParse("8Gi")},
+	}
+
+	tests := []struct {
+		name          string
+		namespace     NamespaceResourceBounds
+		wantCPUMin    string
+		wantCPUMax    string
+		wantMemoryMin string
+		wantMemoryMax string
+		wantErr       bool
+	}{
+		{
+			name:          "no namespace bounds keeps policy bounds",
+			namespace:     NamespaceResourceBounds{},
+			wantCPUMin:    "100m",
+			wantCPUMax:    "4",
+			wantMemoryMin: "128Mi",
+			wantMemoryMax: "8Gi",
+		},
+		{
+			name: "lower namespace max wins",
+			namespace: NamespaceResourceBounds{
+				CPU:    &NamespaceResourceBound{Max: quantityPtr("1")},
+				Memory: &NamespaceResourceBound{Max: quantityPtr("2Gi")},
+			},
+			wantCPUMin:    "100m",
+			wantCPUMax:    "1",
+			wantMemoryMin: "128Mi",
+			wantMemoryMax: "2Gi",
+		},
+		{
+			name: "higher namespace min wins",
+			namespace: NamespaceResourceBounds{
+				CPU: &NamespaceResourceBound{Min: quantityPtr("500m")},
+			},
+			wantCPUMin:    "500m",
+			wantCPUMax:    "4",
+			wantMemoryMin: "128Mi",
+			wantMemoryMax: "8Gi",
+		},
+		{
+			name: "namespace cannot widen policy bounds",
+			namespace: NamespaceResourceBounds{
+				CPU:    &NamespaceResourceBound{Min: quantityPtr("10m"), Max: quantityPtr("16")},
+				Memory: &NamespaceResourceBound{Min: quantityPtr("64Mi"), Max: quantityPtr("32Gi")},
+			},
+			wantCPUMin:    "100m",
+			wantCPUMax:    "4",
+			wantMemoryMin: "128Mi",
+			wantMemoryMax: "8Gi",
+		},
+		{
+			name: "namespace max below policy min conflicts",
+			namespace: NamespaceResourceBounds{
+				Memory: &NamespaceResourceBound{Max: quantityPtr("64Mi")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := MergeResourceBounds(policyBounds, tt.namespace)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MergeResourceBounds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			checks := []struct {
+				field string
+				got   resource.Quantity
+				want  string
+			}{
+				{"cpu.min", merged.CPU.Min, tt.wantCPUMin},
+				{"cpu.max", merged.CPU.Max, tt.wantCPUMax},
+				{"memory.min", merged.Memory.Min, tt.wantMemoryMin},
+				{"memory.max", merged.Memory.Max, tt.wantMemoryMax},
+			}
+			for _, check := range checks {
+				if check.got.Cmp(resource.MustParse(check.want)) != 0 {
+					t.Errorf("%s = %s, want %s", check.field, check.got.String(), check.want)
+				}
+			}
+		})
+	}
+
+	// The input bounds must not be modified
+	if policyBounds.CPU.Max.Cmp(resource.MustParse("4")) != 0 {
+		t.Errorf("policy bounds were mutated: cpu.max = %s", policyBounds.CPU.Max.String())
+	}
+}
+
+func TestNamespaceDefaults_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		bounds  NamespaceResourceBounds
+		wantErr bool
+	}{
+		{name: "empty bounds", bounds: NamespaceResourceBounds{}, wantErr: false},
+		{name: "max only", bounds: NamespaceResourceBounds{CPU: &NamespaceResourceBound{Max: quantityPtr("2")}}, wantErr: false},
+		{
+			name:    "min above max",
+			bounds:  NamespaceResourceBounds{Memory: &NamespaceResourceBound{Min: quantityPtr("2Gi"), Max: quantityPtr("1Gi")}},
+			wantErr: true,
+		},
+		{name: "zero max", bounds: NamespaceResourceBounds{CPU: &NamespaceResourceBound{Max: quantityPtr("0")}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaults := &NamespaceDefaults{Spec: NamespaceDefaultsSpec{ResourceBounds: tt.bounds}}
+			if err := defaults.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -18,7 +18,13 @@ package v1alpha1
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -28,7 +34,9 @@ const (
 	// AnnotationManaged indicates the workload is managed by OptiPod
 	AnnotationManaged = "optipod.io/managed"
 
-	// AnnotationPolicy indicates which policy manages this workload
+	// AnnotationPolicy indicates which policy manages this workload, given as "<name>" or
+	// "<namespace>/<name>". OptiPod writes it with each recommendation. It also pins the workload:
+	// among the policies matching it, the named one handles it regardless of weights.
 	AnnotationPolicy = "optipod.io/policy"
 
 	// AnnotationLastRecommendation is the timestamp of the last recommendation
@@ -37,10 +45,75 @@ const (
 	// AnnotationLastApplied is the timestamp of the last applied change
 	AnnotationLastApplied = "optipod.io/last-applied"
 
+	// AnnotationLastApplyMethod is how the last applied change was rolled out: Recreate when the
+	// pods were recreated, InPlace when they were resized in-place
+	AnnotationLastApplyMethod = "optipod.io/last-apply-method"
+
+	// AnnotationLastRecreated is the timestamp of the last applied change that recreated the pods,
+	// which updateStrategy.minRecreateInterval is measured from. In-place resizes leave it alone.
+	AnnotationLastRecreated = "optipod.io/last-recreated"
+
 	// AnnotationRecommendationPrefix is the prefix for per-container recommendations
 	// Format: optipod.io/recommendation.<container-name>.cpu
 	//         optipod.io/recommendation.<container-name>.memory
 	AnnotationRecommendationPrefix = "optipod.io/recommendation"
+
+	// AnnotationProposedResources lists the requests proposed for approval when the policy
+	// requires approval. Format: <container>.cpu-request=<quantity>,<container>.memory-request=<quantity>
+	AnnotationProposedResources = "optipod.io/proposed-resources"
+
+	// AnnotationProposedHash identifies the proposal awaiting approval
+	AnnotationProposedHash = "optipod.io/proposed-hash"
+
+	// AnnotationApproved approves the proposal whose hash it is set to
+	AnnotationApproved = "optipod.io/approved"
+
+	// AnnotationUpdateMethod overrides the policy's update strategy for the annotated workload.
+	// One of UpdateMethodRecreate, UpdateMethodInPlace or UpdateMethodRequestsOnly.
+	AnnotationUpdateMethod = "optipod.io/update-method"
+
+	// AnnotationStabilityScore is the workload's stability score, from 0 (volatile) to 100 (steady)
+	AnnotationStabilityScore = "optipod.io/stability-score"
+
+	// AnnotationDecreaseObservations counts the consecutive reconciles each container resource was
+	// proposed a decrease, see UpdateStrategy.StableDecreaseObservations.
+	// Format: <container>.cpu=<count>,<container>.memory=<count>
+	AnnotationDecreaseObservations = "optipod.io/decrease-observations"
+
+	// AnnotationReconcileNow triggers an immediate reconcile of the annotated OptimizationPolicy
+	// whenever its value changes. The value is usually a timestamp, e.g. from `date +%s`.
+	AnnotationReconcileNow = "optipod.io/reconcile-now"
+
+	// AnnotationSafetyRampApplies counts the stable applies to the workload that lowered its
+	// safety factor, see MetricsConfig.SafetyRamp
+	AnnotationSafetyRampApplies = "optipod.io/safety-ramp-applies"
+
+	// AnnotationManagedByPolicy is the "<namespace>/<name>" of the OptimizationPolicy that
+	// currently owns the workload, i.e. the policy that won policy selection for it. It is removed
+	// once the policy no longer matches the workload or loses it to another policy.
+	AnnotationManagedByPolicy = "optipod.io/managed-by-policy"
+
+	// AnnotationMemoryPeaks retains the highest memory usage observed per container and when it
+	// was observed, see MetricsConfig.MemoryPeakFloor.
+	// Format: <container>=<bytes>@<unix seconds>,...
+	AnnotationMemoryPeaks = "optipod.io/memory-peaks"
+
+	// AnnotationPartitionFloor marks a StatefulSet whose partitioned rollout is in progress and
+	// holds the rollingUpdate partition it had before, which the rollout stops at. It is removed
+	// once the rollout reaches it, see UpdateStrategy.PartitionedRollout.
+	AnnotationPartitionFloor = "optipod.io/partition-floor"
+)
+
+// Values of the AnnotationUpdateMethod workload annotation
+const (
+	// UpdateMethodRecreate always applies changes by recreating pods, never in-place
+	UpdateMethodRecreate = "recreate"
+
+	// UpdateMethodInPlace only applies changes that can be made by an in-place resize
+	UpdateMethodInPlace = "in-place"
+
+	// UpdateMethodRequestsOnly only updates requests, leaving limits unchanged
+	UpdateMethodRequestsOnly = "requests-only"
 )
 
 // PolicyMode defines the operational mode of the optimization policy
@@ -63,6 +136,11 @@ type OptimizationPolicySpec struct {
 	// +kubebuilder:validation:Enum=Auto;Recommend;Disabled
 	Mode PolicyMode `json:"mode"`
 
+	// DryRun makes the policy recommend-only whatever its Mode, so a new policy can be tried out
+	// while the others keep applying. The operator-wide dry-run takes precedence.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
 	// Weight defines the priority of this policy when multiple policies match the same workload
 	// Higher weight policies take precedence. Default weight is 100.
 	// +kubebuilder:default=100
@@ -87,10 +165,379 @@ type OptimizationPolicySpec struct {
 	// +kubebuilder:validation:Required
 	UpdateStrategy UpdateStrategy `json:"updateStrategy"`
 
-	// ReconciliationInterval defines how often the policy is evaluated
-	// +kubebuilder:default="5m"
+	// OptimizeCPU controls whether CPU is sized. When false, the CPU requests and limits of
+	// workloads are left untouched and no CPU recommendation is computed.
+	// +kubebuilder:default=true
+	// +optional
+	OptimizeCPU *bool `json:"optimizeCPU,omitempty"`
+
+	// OptimizeMemory controls whether memory is sized. When false, the memory requests and
+	// limits of workloads are left untouched and no memory recommendation is computed.
+	// +kubebuilder:default=true
+	// +optional
+	OptimizeMemory *bool `json:"optimizeMemory,omitempty"`
+
+	// ContainerSelectors scopes which containers are processed and in what mode.
+	// Selectors are evaluated in order and the first match wins. When empty, all
+	// containers are processed with the policy mode and bounds. When set, containers
+	// matching no selector are left untouched.
+	// +optional
+	ContainerSelectors []ContainerSelector `json:"containerSelectors,omitempty"`
+
+	// ExcludeContainers lists container names (glob patterns such as "linkerd-*") that are
+	// never resized by this policy, even when other containers in the pod are processed.
+	// Exclusions take precedence over ContainerSelectors.
+	// +optional
+	ExcludeContainers []string `json:"excludeContainers,omitempty"`
+
+	// IncludeInitContainers also sizes run-once init containers. Native sidecars (init
+	// containers with restartPolicy Always) run for the pod's lifetime and are always sized
+	// like regular containers; other init containers are skipped unless this is set.
+	// +optional
+	IncludeInitContainers bool `json:"includeInitContainers,omitempty"`
+
+	// ReconciliationInterval defines how often the policy is evaluated. When unset, the
+	// operator's reconciliation interval is used, which follows configuration reloads.
 	// +optional
 	ReconciliationInterval metav1.Duration `json:"reconciliationInterval,omitempty"`
+
+	// AdaptiveInterval lets the time between reconciliations grow while the policy's workloads
+	// are stable and resets it as soon as they change. When unset, reconciliationInterval is
+	// used as is.
+	// +optional
+	AdaptiveInterval *AdaptiveInterval `json:"adaptiveInterval,omitempty"`
+
+	// MaxWorkloads caps the number of workloads this policy may modify. When the policy
+	// matches more workloads than the cap, discovery keeps one workload past the cap and
+	// only counts the rest, the policy falls back to recommend-only and reports a
+	// TooManyWorkloads condition. Defaults to the operator-wide cap.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxWorkloads *int32 `json:"maxWorkloads,omitempty"`
+
+	// StartupFloor enforces higher minimums for startup-heavy applications (e.g. JVM or
+	// Node apps) that need more resources while starting than their steady-state usage
+	// +optional
+	StartupFloor *StartupFloor `json:"startupFloor,omitempty"`
+
+	// ReplicaScaling sizes pods of horizontally scaled workloads from their total demand, so the
+	// per-pod request shrinks as an HPA adds replicas. Disabled by default.
+	// +optional
+	ReplicaScaling *ReplicaScaling `json:"replicaScaling,omitempty"`
+
+	// CPUMemoryRatio is the plausible band for the ratio of recommended CPU to recommended memory.
+	// A workload with a recommendation outside the band is not updated and is reported through
+	// a SuspiciousRecommendation condition, since such recommendations usually come from bad
+	// metrics. Defaults to a generous band when not set.
+	// +optional
+	CPUMemoryRatio *CPUMemoryRatio `json:"cpuMemoryRatio,omitempty"`
+
+	// MinStabilityScore keeps workloads whose stability score is below it in recommend-only,
+	// even when the policy is in Auto mode. Volatile usage, sparse metrics and restarts lower
+	// the score. When not set, no workload is held back.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MinStabilityScore *int32 `json:"minStabilityScore,omitempty"`
+
+	// NodeCapacityCap caps recommendations at the allocatable resources of the largest node the
+	// workload can be scheduled on, so a recommendation never describes a pod no node can run.
+	// Disabled by default.
+	// +optional
+	NodeCapacityCap *NodeCapacityCap `json:"nodeCapacityCap,omitempty"`
+
+	// RecommendationHysteresis keeps the last recorded recommendation while new recommendations
+	// stay within a band around it, so small usage changes do not rewrite the recommendation
+	// annotations. Disabled when not set.
+	// +optional
+	RecommendationHysteresis *RecommendationHysteresis `json:"recommendationHysteresis,omitempty"`
+
+	// CacheHeadroom adds memory on top of the recommendation for workloads that perform poorly
+	// without page cache, such as databases. The working set usage the recommendation is computed
+	// from excludes reclaimable cache, so without headroom the cache is squeezed out. Applied after
+	// the safety factor and clamped to the memory bounds. Disabled when not set.
+	// +optional
+	CacheHeadroom *CacheHeadroom `json:"cacheHeadroom,omitempty"`
+
+	// PartialMetrics defines what happens when only some containers of a workload have metrics,
+	// e.g. because an injected sidecar is not instrumented. By default the whole workload is
+	// skipped until every container has metrics.
+	// +optional
+	PartialMetrics *PartialMetrics `json:"partialMetrics,omitempty"`
+
+	// RemovedContainers defines what happens to the state OptiPod keeps for a container, such as
+	// its recommendation annotations and decrease observations, once the container is removed
+	// from the workload's pod template. Cleanup removes it; Retain keeps it, e.g. for a sidecar
+	// that is removed and added back.
+	// +kubebuilder:validation:Enum=Cleanup;Retain
+	// +kubebuilder:default=Cleanup
+	// +optional
+	RemovedContainers string `json:"removedContainers,omitempty"`
+}
+
+// Handling of the state kept for containers removed from a workload, see RemovedContainers
+const (
+	// RemovedContainersCleanup removes the state of removed containers
+	RemovedContainersCleanup = "Cleanup"
+	// RemovedContainersRetain keeps the state of removed containers
+	RemovedContainersRetain = "Retain"
+)
+
+// Actions for the containers without metrics of a workload whose other containers have metrics
+const (
+	// PartialMetricsSkipWorkload holds the whole workload until every container has metrics
+	PartialMetricsSkipWorkload = "SkipWorkload"
+	// PartialMetricsLeaveAsIs sizes the containers with metrics and leaves the others unchanged
+	PartialMetricsLeaveAsIs = "LeaveAsIs"
+	// PartialMetricsFloor sizes the containers with metrics and raises the requests of the others
+	// to a floor
+	PartialMetricsFloor = "Floor"
+)
+
+// PartialMetrics configures the handling of containers without metrics in a workload whose other
+// containers have metrics. A workload without metrics for any container is always skipped.
+type PartialMetrics struct {
+	// Action is SkipWorkload, LeaveAsIs or Floor
+	// +kubebuilder:validation:Enum=SkipWorkload;LeaveAsIs;Floor
+	// +kubebuilder:default=SkipWorkload
+	// +optional
+	Action string `json:"action,omitempty"`
+
+	// CPU is the floor CPU request of containers without metrics with the Floor action, defaults
+	// to resourceBounds.cpu.min. Requests above the floor are never lowered.
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory is the floor memory request of containers without metrics with the Floor action,
+	// defaults to resourceBounds.memory.min. Requests above the floor are never lowered.
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+}
+
+// GetAction returns the action for containers without metrics, SkipWorkload by default
+func (p *PartialMetrics) GetAction() string {
+	if p == nil || p.Action == "" {
+		return PartialMetricsSkipWorkload
+	}
+	return p.Action
+}
+
+// Floor returns the floor requests of containers without metrics under the given bounds
+func (p *PartialMetrics) Floor(bounds ResourceBounds) (cpu, memory resource.Quantity) {
+	cpu, memory = bounds.CPU.Min.DeepCopy(), bounds.Memory.Min.DeepCopy()
+	if p == nil {
+		return cpu, memory
+	}
+	if p.CPU != nil {
+		cpu = p.CPU.DeepCopy()
+	}
+	if p.Memory != nil {
+		memory = p.Memory.DeepCopy()
+	}
+	return cpu, memory
+}
+
+// CacheHeadroom configures the memory added for page cache. Exactly one of Percent and Amount is set.
+type CacheHeadroom struct {
+	// Percent adds this percentage of the observed memory usage as cache headroom
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=400
+	// +optional
+	Percent *int32 `json:"percent,omitempty"`
+
+	// Amount adds a fixed amount of memory as cache headroom (e.g. "512Mi")
+	// +optional
+	Amount *resource.Quantity `json:"amount,omitempty"`
+}
+
+// Headroom returns the memory added for page cache on top of a recommendation computed from
+// usage. A nil CacheHeadroom adds nothing.
+func (c *CacheHeadroom) Headroom(usage resource.Quantity) resource.Quantity {
+	if c == nil {
+		return resource.Quantity{}
+	}
+	if c.Amount != nil {
+		return c.Amount.DeepCopy()
+	}
+	if c.Percent != nil {
+		return *resource.NewQuantity(usage.Value()*int64(*c.Percent)/100, resource.BinarySI)
+	}
+	return resource.Quantity{}
+}
+
+// RecommendationHysteresis configures the band around the last recorded recommendation, per resource
+type RecommendationHysteresis struct {
+	// CPUPercent is the width of the CPU band, as a percentage of the last recorded CPU
+	// recommendation in either direction (default 5)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=50
+	// +optional
+	CPUPercent *int32 `json:"cpuPercent,omitempty"`
+
+	// MemoryPercent is the width of the memory band, as a percentage of the last recorded memory
+	// recommendation in either direction (default 5)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=50
+	// +optional
+	MemoryPercent *int32 `json:"memoryPercent,omitempty"`
+}
+
+// DefaultHysteresisPercent is the width of a hysteresis band that is not configured
+const DefaultHysteresisPercent int32 = 5
+
+// Band returns the CPU and memory band widths in percent, with defaults for unset values
+func (h *RecommendationHysteresis) Band() (cpuPercent, memoryPercent int32) {
+	cpuPercent, memoryPercent = DefaultHysteresisPercent, DefaultHysteresisPercent
+	if h == nil {
+		return cpuPercent, memoryPercent
+	}
+	if h.CPUPercent != nil {
+		cpuPercent = *h.CPUPercent
+	}
+	if h.MemoryPercent != nil {
+		memoryPercent = *h.MemoryPercent
+	}
+	return cpuPercent, memoryPercent
+}
+
+// NodeCapacityCap configures capping recommendations at node allocatable
+type NodeCapacityCap struct {
+	// Enabled turns on capping at node allocatable. Nodes are matched against the pod template's
+	// nodeSelector and required node affinity; unschedulable nodes are ignored. In a pool of
+	// different node sizes the largest matching node is used for each resource.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// HeadroomPercent is the share of the node's allocatable kept free for DaemonSet pods and
+	// other workloads. The cap is the rest of the allocatable, less the requests of the pod's
+	// other containers.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=90
+	// +kubebuilder:default=10
+	// +optional
+	HeadroomPercent *int32 `json:"headroomPercent,omitempty"`
+}
+
+// DefaultNodeCapacityHeadroomPercent is the headroom kept free on a node when none is configured
+const DefaultNodeCapacityHeadroomPercent int32 = 10
+
+// Headroom returns the configured headroom percent, or the default when it is not set
+func (c *NodeCapacityCap) Headroom() int32 {
+	if c == nil || c.HeadroomPercent == nil {
+		return DefaultNodeCapacityHeadroomPercent
+	}
+	return *c.HeadroomPercent
+}
+
+// Default CPU to memory ratio band, in millicores per GiB of memory
+const (
+	// DefaultMinCPUMillicoresPerGiB allows down to 1 core per TiB of memory
+	DefaultMinCPUMillicoresPerGiB int64 = 1
+	// DefaultMaxCPUMillicoresPerGiB allows up to 32 cores per GiB of memory
+	DefaultMaxCPUMillicoresPerGiB int64 = 32000
+)
+
+// CPUMemoryRatio bounds the ratio of recommended CPU to recommended memory, in millicores per GiB
+type CPUMemoryRatio struct {
+	// MinMillicoresPerGiB is the lowest plausible CPU per GiB of memory (default 1)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinMillicoresPerGiB *int64 `json:"minMillicoresPerGiB,omitempty"`
+
+	// MaxMillicoresPerGiB is the highest plausible CPU per GiB of memory (default 32000)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxMillicoresPerGiB *int64 `json:"maxMillicoresPerGiB,omitempty"`
+}
+
+// Band returns the minimum and maximum millicores per GiB, falling back to the defaults for
+// unset values. A nil CPUMemoryRatio returns the default band.
+func (r *CPUMemoryRatio) Band() (minRatio, maxRatio int64) {
+	minRatio, maxRatio = DefaultMinCPUMillicoresPerGiB, DefaultMaxCPUMillicoresPerGiB
+	if r == nil {
+		return minRatio, maxRatio
+	}
+	if r.MinMillicoresPerGiB != nil {
+		minRatio = *r.MinMillicoresPerGiB
+	}
+	if r.MaxMillicoresPerGiB != nil {
+		maxRatio = *r.MaxMillicoresPerGiB
+	}
+	return minRatio, maxRatio
+}
+
+// ReplicaScaling configures replica-aware sizing for workloads scaled by a HorizontalPodAutoscaler
+type ReplicaScaling struct {
+	// Enabled turns on replica-aware sizing. The per-pod usage is multiplied by the current
+	// replica count to estimate the total demand, which is then divided by the replica count
+	// the workload is converging to (the HPA's desired replicas, or spec.replicas without an HPA).
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// ScaleMemory also scales memory recommendations. By default only CPU is scaled, since
+	// memory usage often has a per-pod baseline that does not shrink as replicas are added.
+	// +optional
+	ScaleMemory bool `json:"scaleMemory,omitempty"`
+}
+
+// StartupFloor defines minimum requests enforced while a workload is starting up or restarting frequently
+type StartupFloor struct {
+	// CPU is the startup CPU floor. Recommendations are never below this value while the floor is active.
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory is the startup memory floor. Recommendations are never below this value while the floor is active.
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// Period is how long after workload creation the floor is enforced
+	// +kubebuilder:default="1h"
+	// +optional
+	Period metav1.Duration `json:"period,omitempty"`
+
+	// RestartThreshold also enforces the floor when a container has restarted at least this
+	// many times across the workload's pods within the metrics rolling window
+	// (0 = restarts are not considered)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RestartThreshold int32 `json:"restartThreshold,omitempty"`
+}
+
+// ContainerMatchType is how a container selector's name is matched against container names
+type ContainerMatchType string
+
+const (
+	// ContainerMatchGlob matches container names against a glob pattern
+	ContainerMatchGlob ContainerMatchType = "Glob"
+	// ContainerMatchRegex matches whole container names against a regular expression
+	ContainerMatchRegex ContainerMatchType = "Regex"
+)
+
+// ContainerSelector selects containers by name and overrides how they are processed
+type ContainerSelector struct {
+	// Name is the pattern matched against the container name: a glob pattern (e.g. "app",
+	// "*-sidecar") or, when MatchType is Regex, a regular expression (e.g. "worker-[0-9]+")
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// MatchType is how Name is matched. Glob matches a glob pattern; Regex matches a regular
+	// expression against the whole container name.
+	// +kubebuilder:validation:Enum=Glob;Regex
+	// +kubebuilder:default=Glob
+	// +optional
+	MatchType ContainerMatchType `json:"matchType,omitempty"`
+
+	// Mode overrides the policy mode for matching containers. It can only narrow the
+	// policy mode (e.g. Recommend under an Auto policy), never widen it.
+	// Defaults to the policy mode.
+	// +kubebuilder:validation:Enum=Auto;Recommend;Disabled
+	// +optional
+	Mode PolicyMode `json:"mode,omitempty"`
+
+	// ResourceBounds overrides the policy resource bounds for matching containers
+	// +optional
+	ResourceBounds *ResourceBounds `json:"resourceBounds,omitempty"`
 }
 
 // WorkloadSelector defines which workloads a policy applies to
@@ -148,7 +595,7 @@ const (
 type MetricsConfig struct {
 	// Provider specifies the metrics backend (e.g., "prometheus", "metrics-server")
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=prometheus;metrics-server;custom
+	// +kubebuilder:validation:Enum=prometheus;metrics-server;otel-promql;custom
 	Provider string `json:"provider"`
 
 	// RollingWindow defines the time period over which metrics are aggregated
@@ -167,6 +614,385 @@ type MetricsConfig struct {
 	// +kubebuilder:default=1.2
 	// +optional
 	SafetyFactor *float64 `json:"safetyFactor,omitempty"`
+
+	// SafetyRamp applies a higher safety factor the first time a workload is changed and lowers
+	// it toward SafetyFactor over the following applies, as the workload proves stable.
+	// When unset, every apply uses SafetyFactor.
+	// +optional
+	SafetyRamp *SafetyRamp `json:"safetyRamp,omitempty"`
+
+	// Blend additionally queries a short window and combines it with the rolling window,
+	// so recommendations react to recent spikes while staying anchored to the long-term baseline.
+	// When unset, only the rolling window is used.
+	// +optional
+	Blend *BlendConfig `json:"blend,omitempty"`
+
+	// DailyPeaks sizes to the median of recent daily usage peaks instead of the percentile over
+	// the rolling window, so one-off spikes do not drive steady sizing while typical daily peaks
+	// are still covered. Requires a metrics backend that keeps timestamped samples over days
+	// (prometheus or otel-promql). When unset, the rolling window percentile is used.
+	// +optional
+	DailyPeaks *DailyPeaksConfig `json:"dailyPeaks,omitempty"`
+
+	// ResourceStatistics configures per resource the statistic taken from the rolling window, in
+	// place of Percentile, and a reactive short window that can raise but never lower the
+	// recommendation. For example, memory can be sized to the maximum of a week while CPU reacts
+	// within minutes to sustained increases. Resources without an entry use Percentile.
+	// +optional
+	ResourceStatistics *ResourceStatistics `json:"resourceStatistics,omitempty"`
+
+	// InformationalQueries are PromQL queries evaluated for every workload and reported in the
+	// workload status for context, e.g. the request rate. They never affect recommendations.
+	// Requires a metrics backend with a PromQL query API (prometheus or otel-promql).
+	// +kubebuilder:validation:MaxItems=5
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	InformationalQueries []InformationalQuery `json:"informationalQueries,omitempty"`
+
+	// MaxMetricsAge is the age above which a container's newest usage sample is stale. A workload
+	// with stale metrics still gets recommendations, but they are not applied and the workload
+	// status reason starts with StaleMetrics. Providers that do not report the time of their
+	// samples are never stale. When unset, metrics of any age are applied.
+	// +optional
+	MaxMetricsAge *metav1.Duration `json:"maxMetricsAge,omitempty"`
+
+	// PriorityMemoryHeadroom gives pods of higher priority more memory headroom, lowering the
+	// risk that critical workloads are evicted or OOM killed. Memory is sized with the larger of
+	// SafetyFactor and the memorySafetyFactor of the entry matching the pod: an entry naming the
+	// pod's priorityClassName, otherwise the entry with the highest minPriority the pod's priority
+	// reaches. When unset, memory uses SafetyFactor like CPU.
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	PriorityMemoryHeadroom []PriorityMemoryHeadroom `json:"priorityMemoryHeadroom,omitempty"`
+
+	// MemoryPeakFloor retains the highest memory usage observed per container across reconciles
+	// and never recommends memory below it, so a rare but real peak is not forgotten once it
+	// leaves the rolling window. When unset, memory follows the rolling window only.
+	// +optional
+	MemoryPeakFloor *MemoryPeakFloor `json:"memoryPeakFloor,omitempty"`
+}
+
+// MemoryPeakFloor defines the retained memory peak used as a floor for memory recommendations
+type MemoryPeakFloor struct {
+	// Retention is how long a peak is kept once observed. A higher peak replaces it and restarts
+	// the retention; an expired peak is replaced by the highest usage of the rolling window. Must
+	// be at least the rolling window. When unset, the peak is kept for the lifetime of the workload.
+	// +optional
+	Retention *metav1.Duration `json:"retention,omitempty"`
+}
+
+// Expired reports whether a peak observed at observedAt is no longer retained at now
+func (m *MemoryPeakFloor) Expired(observedAt, now time.Time) bool {
+	return m.Retention != nil && now.Sub(observedAt) > m.Retention.Duration
+}
+
+// PriorityMemoryHeadroom maps a pod priority to the memory safety factor of its pods
+type PriorityMemoryHeadroom struct {
+	// PriorityClassName matches pods with this priorityClassName. Exactly one of
+	// PriorityClassName and MinPriority must be set.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// MinPriority matches pods whose priority is at least this value
+	// +optional
+	MinPriority *int32 `json:"minPriority,omitempty"`
+
+	// MemorySafetyFactor is the multiplier applied to the memory usage of matching pods. Must
+	// be >= 1.0.
+	// +kubebuilder:validation:Required
+	MemorySafetyFactor float64 `json:"memorySafetyFactor"`
+}
+
+// MemorySafetyFactor returns the memory safety factor of pods with the given priority class
+// name and priority (nil = unknown), and the entry that raised it above the safety factor, or
+// nil when the safety factor applies
+func (m MetricsConfig) MemorySafetyFactor(priorityClassName string, priority *int32) (float64, *PriorityMemoryHeadroom) {
+	safetyFactor := m.GetSafetyFactor()
+	var match *PriorityMemoryHeadroom
+	for i := range m.PriorityMemoryHeadroom {
+		entry := &m.PriorityMemoryHeadroom[i]
+		if entry.PriorityClassName != "" && entry.PriorityClassName == priorityClassName {
+			match = entry
+			break
+		}
+		if entry.MinPriority != nil && priority != nil && *priority >= *entry.MinPriority &&
+			(match == nil || *entry.MinPriority > *match.MinPriority) {
+			match = entry
+		}
+	}
+	if match == nil || match.MemorySafetyFactor <= safetyFactor {
+		return safetyFactor, nil
+	}
+	return match.MemorySafetyFactor, match
+}
+
+// SafetyRamp defines a safety factor that starts conservative and narrows with each stable apply
+type SafetyRamp struct {
+	// InitialSafetyFactor is the safety factor of the first apply to a workload. Must be at
+	// least metricsConfig.safetyFactor.
+	// +kubebuilder:validation:Required
+	InitialSafetyFactor float64 `json:"initialSafetyFactor"`
+
+	// Applies is the number of stable applies over which the safety factor is lowered, in equal
+	// steps, from InitialSafetyFactor to metricsConfig.safetyFactor (default 3)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=20
+	// +optional
+	Applies *int32 `json:"applies,omitempty"`
+
+	// MinStabilityScore is the stability score a workload needs when it is changed for the
+	// apply to count toward lowering the safety factor (default 70). Changes to less stable
+	// workloads keep the current safety factor.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MinStabilityScore *int32 `json:"minStabilityScore,omitempty"`
+}
+
+// AdaptiveInterval bounds a reconciliation interval that doubles with each reconciliation in
+// which the policy's workloads are stable
+type AdaptiveInterval struct {
+	// MinInterval is the interval used while any workload is volatile, was changed or failed,
+	// and the interval backing off starts from. Defaults to reconciliationInterval.
+	// +optional
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+
+	// MaxInterval is the longest interval a stable policy backs off to
+	// +kubebuilder:validation:Required
+	MaxInterval metav1.Duration `json:"maxInterval"`
+
+	// StableScore is the stability score every workload needs for the policy to count as
+	// stable (default 80)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	StableScore *int32 `json:"stableScore,omitempty"`
+}
+
+// DefaultAdaptiveIntervalStableScore is the stability score a workload needs by default to
+// count as stable for the adaptive reconciliation interval
+const DefaultAdaptiveIntervalStableScore int32 = 80
+
+// Bounds returns the minimum and maximum reconciliation interval, with the minimum defaulting
+// to base
+func (a *AdaptiveInterval) Bounds(base time.Duration) (minInterval, maxInterval time.Duration) {
+	minInterval = base
+	if a.MinInterval != nil {
+		minInterval = a.MinInterval.Duration
+	}
+	return minInterval, a.MaxInterval.Duration
+}
+
+// GetStableScore returns the stability score a workload needs to count as stable
+func (a *AdaptiveInterval) GetStableScore() int32 {
+	if a.StableScore == nil {
+		return DefaultAdaptiveIntervalStableScore
+	}
+	return *a.StableScore
+}
+
+// Defaults of a SafetyRamp
+const (
+	DefaultSafetyRampApplies           int32 = 3
+	DefaultSafetyRampMinStabilityScore int32 = 70
+)
+
+// Steps returns the number of stable applies to reach the target safety factor and the
+// stability score an apply needs to count, with defaults for unset values
+func (s *SafetyRamp) Steps() (applies, minStabilityScore int32) {
+	applies, minStabilityScore = DefaultSafetyRampApplies, DefaultSafetyRampMinStabilityScore
+	if s == nil {
+		return applies, minStabilityScore
+	}
+	if s.Applies != nil {
+		applies = *s.Applies
+	}
+	if s.MinStabilityScore != nil {
+		minStabilityScore = *s.MinStabilityScore
+	}
+	return applies, minStabilityScore
+}
+
+// SafetyFactor returns the safety factor after the given number of stable applies, lowered
+// linearly from InitialSafetyFactor to target
+func (s *SafetyRamp) SafetyFactor(target float64, stableApplies int32) float64 {
+	applies, _ := s.Steps()
+	if stableApplies >= applies {
+		return target
+	}
+	progress := float64(max(stableApplies, 0)) / float64(applies)
+	return s.InitialSafetyFactor - (s.InitialSafetyFactor-target)*progress
+}
+
+// Phase returns the SafetyRampPhase after the given number of stable applies
+func (s *SafetyRamp) Phase(stableApplies int32) string {
+	applies, _ := s.Steps()
+	switch {
+	case stableApplies <= 0:
+		return SafetyRampPhaseInitial
+	case stableApplies < applies:
+		return SafetyRampPhaseTightening
+	default:
+		return SafetyRampPhaseTarget
+	}
+}
+
+// Phases of a workload's safety ramp, see WorkloadStatus.SafetyRampPhase
+const (
+	// SafetyRampPhaseInitial uses the initial safety factor, until the first stable apply
+	SafetyRampPhaseInitial = "Initial"
+
+	// SafetyRampPhaseTightening lowers the safety factor with each stable apply
+	SafetyRampPhaseTightening = "Tightening"
+
+	// SafetyRampPhaseTarget uses metricsConfig.safetyFactor
+	SafetyRampPhaseTarget = "Target"
+)
+
+// MaxInformationalQueries is the number of informational queries a policy may define
+const MaxInformationalQueries = 5
+
+// InformationalQuery is a PromQL query whose result is reported for context only
+type InformationalQuery struct {
+	// Name identifies the query result in the workload status
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Query is an instant PromQL query returning a single value. The placeholders $namespace,
+	// $workload and $kind are replaced with the values of the workload being processed.
+	// Example: sum(rate(http_requests_total{namespace="$namespace",deployment="$workload"}[5m]))
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Query string `json:"query"`
+}
+
+// BlendRule defines how the short and long window percentiles are combined
+// +kubebuilder:validation:Enum=Max;Weighted
+type BlendRule string
+
+const (
+	// BlendRuleMax uses the larger of the short window and rolling window percentiles
+	BlendRuleMax BlendRule = "Max"
+	// BlendRuleWeighted uses a weighted average of the short window and rolling window percentiles
+	BlendRuleWeighted BlendRule = "Weighted"
+)
+
+// BlendConfig defines a short window that is blended with the rolling window
+type BlendConfig struct {
+	// ShortWindow is the recent time period queried in addition to the rolling window.
+	// Must be shorter than the rolling window.
+	// +kubebuilder:validation:Required
+	ShortWindow metav1.Duration `json:"shortWindow"`
+
+	// ShortPercentile is the percentile taken from the short window.
+	// The rolling window uses metricsConfig.percentile.
+	// +kubebuilder:validation:Enum=P50;P90;P99
+	// +kubebuilder:default="P99"
+	// +optional
+	ShortPercentile string `json:"shortPercentile,omitempty"`
+
+	// Rule defines how the two windows are combined. Defaults to Max.
+	// +kubebuilder:default="Max"
+	// +optional
+	Rule BlendRule `json:"rule,omitempty"`
+
+	// ShortWeight is the weight of the short window for the Weighted rule, between 0 and 1.
+	// The rolling window gets the remaining weight. Defaults to 0.5.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	ShortWeight *float64 `json:"shortWeight,omitempty"`
+}
+
+// StatisticMax selects the highest sample of a window. The percentiles P50, P90 and P99 are the
+// other statistics a ResourceStatistic accepts.
+const StatisticMax = "Max"
+
+// DefaultOverrideStatistic is the statistic of a reactive override window when none is set. The
+// median only rises when usage stays high for much of the window, so brief spikes do not trigger it.
+const DefaultOverrideStatistic = "P50"
+
+// ResourceStatistics holds the statistic configuration of each resource
+type ResourceStatistics struct {
+	// CPU configures the statistic CPU recommendations are computed from
+	// +optional
+	CPU *ResourceStatistic `json:"cpu,omitempty"`
+
+	// Memory configures the statistic memory recommendations are computed from
+	// +optional
+	Memory *ResourceStatistic `json:"memory,omitempty"`
+}
+
+// ResourceStatistic defines the primary statistic of a resource and its reactive override
+type ResourceStatistic struct {
+	// Statistic is taken from the rolling window in place of metricsConfig.percentile
+	// +kubebuilder:validation:Enum=P50;P90;P99;Max
+	// +optional
+	Statistic string `json:"statistic,omitempty"`
+
+	// Override raises the recommendation when a short window shows higher usage than the
+	// primary statistic. It never lowers the recommendation.
+	// +optional
+	Override *ReactiveOverride `json:"override,omitempty"`
+}
+
+// ReactiveOverride defines a short window that can only raise a recommendation
+type ReactiveOverride struct {
+	// Window is the recent time period queried in addition to the rolling window.
+	// Must be shorter than the rolling window.
+	// +kubebuilder:validation:Required
+	Window metav1.Duration `json:"window"`
+
+	// Statistic is taken from the short window (default P50)
+	// +kubebuilder:validation:Enum=P50;P90;P99;Max
+	// +optional
+	Statistic string `json:"statistic,omitempty"`
+}
+
+// GetStatistic returns the statistic taken from the override window, defaulting to DefaultOverrideStatistic
+func (o *ReactiveOverride) GetStatistic() string {
+	if o.Statistic != "" {
+		return o.Statistic
+	}
+	return DefaultOverrideStatistic
+}
+
+// DefaultDailyPeakDays is the number of recent days whose peaks are considered by default
+const DefaultDailyPeakDays = 7
+
+// DailyPeaksConfig defines sizing to the median of recent daily usage peaks
+type DailyPeaksConfig struct {
+	// Days is the number of recent days whose peaks are considered. Defaults to 7.
+	// +kubebuilder:validation:Minimum=3
+	// +kubebuilder:validation:Maximum=30
+	// +kubebuilder:default=7
+	// +optional
+	Days int32 `json:"days,omitempty"`
+
+	// Percentile is the usage percentile of each day taken as that day's peak
+	// +kubebuilder:validation:Enum=P50;P90;P99
+	// +kubebuilder:default="P99"
+	// +optional
+	Percentile string `json:"percentile,omitempty"`
+}
+
+// GetDays returns the number of days whose peaks are considered, defaulting to DefaultDailyPeakDays
+func (d *DailyPeaksConfig) GetDays() int {
+	if d.Days > 0 {
+		return int(d.Days)
+	}
+	return DefaultDailyPeakDays
+}
+
+// GetPercentile returns the percentile taken as the peak of each day, defaulting to P99
+func (d *DailyPeaksConfig) GetPercentile() string {
+	if d.Percentile != "" {
+		return d.Percentile
+	}
+	return "P99"
 }
 
 // ResourceBounds defines min/max constraints for CPU and memory
@@ -208,14 +1034,297 @@ type UpdateStrategy struct {
 	// +optional
 	UpdateRequestsOnly bool `json:"updateRequestsOnly,omitempty"`
 
+	// RemoveLimits sizes requests and removes the CPU and memory limits of containers,
+	// so workloads are neither CPU throttled nor capped by a memory limit.
+	// Takes precedence over updateRequestsOnly and limitConfig.
+	// +kubebuilder:default=false
+	// +optional
+	RemoveLimits bool `json:"removeLimits,omitempty"`
+
+	// AllowQoSClassChange lets updates change the QoS class of Guaranteed containers.
+	// By default, a container whose CPU and memory requests equal its limits gets its limits set
+	// to the new requests, whatever updateRequestsOnly, removeLimits and limitConfig say,
+	// so its pod stays Guaranteed.
+	// +kubebuilder:default=false
+	// +optional
+	AllowQoSClassChange bool `json:"allowQoSClassChange,omitempty"`
+
 	// UseServerSideApply enables Server-Side Apply for field-level ownership
 	// +kubebuilder:default=true
 	// +optional
 	UseServerSideApply *bool `json:"useServerSideApply,omitempty"`
 
+	// PatchMethodOverrides selects the patch method for the workloads of a kind in place of
+	// useServerSideApply, e.g. strategic merge patches for DaemonSets whose other controllers do
+	// not handle Server-Side Apply field ownership well. Kinds without an entry follow
+	// useServerSideApply. Pod-level resources are written with a strategic merge patch whatever
+	// the method.
+	// +listType=map
+	// +listMapKey=kind
+	// +optional
+	PatchMethodOverrides []PatchMethodOverride `json:"patchMethodOverrides,omitempty"`
+
+	// ApprovalRequired makes Auto mode wait for a human to approve each change. The proposed
+	// requests and their hash are written to the optipod.io/proposed-resources and
+	// optipod.io/proposed-hash annotations of the workload, and the change is applied once the
+	// optipod.io/approved annotation is set to that hash. A new proposal invalidates earlier approvals.
+	// +kubebuilder:default=false
+	// +optional
+	ApprovalRequired bool `json:"approvalRequired,omitempty"`
+
+	// ConvergenceRate moves requests gradually toward the recommendation. Each change covers this
+	// fraction of the remaining distance, so requests converge over several reconciles; a change
+	// within 2% of the recommendation goes straight to it. Unset or 1 applies the recommendation at once.
+	// Example: 0.5 halves the distance to the recommendation on every reconcile
+	// +kubebuilder:validation:ExclusiveMinimum=true
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	ConvergenceRate *float64 `json:"convergenceRate,omitempty"`
+
+	// PartitionedRollout rolls changes to StatefulSets with the RollingUpdate strategy out a few
+	// pods at a time. The change sets the StatefulSet's rollingUpdate partition so only the pods
+	// with the highest ordinals are updated, and every reconcile lowers the partition by
+	// PartitionStep once the pods it last released are ready on the new revision. Changes wait
+	// until a rollout in progress is complete. A partition set on the StatefulSet beforehand, e.g.
+	// for a manual canary, is kept: the rollout stops there.
+	// +kubebuilder:default=false
+	// +optional
+	PartitionedRollout bool `json:"partitionedRollout,omitempty"`
+
+	// PartitionStep is the number of pods a partitioned rollout updates at a time
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	PartitionStep *int32 `json:"partitionStep,omitempty"`
+
+	// OnDeleteRollout rolls changes to StatefulSets and DaemonSets with the OnDelete update
+	// strategy out by deleting their pods one at a time, since their controller does not replace
+	// pods when the pod template changes. Every reconcile evicts one pod still running the old
+	// requests once all pods are ready; evictions respect PodDisruptionBudgets. Deleting pods is
+	// disruptive, so it also needs allowRecreate. Without it, changes to such workloads are
+	// skipped.
+	// +kubebuilder:default=false
+	// +optional
+	OnDeleteRollout bool `json:"onDeleteRollout,omitempty"`
+
+	// StableDecreaseObservations holds back decreases until they have been proposed in this many
+	// consecutive reconciles. A reconcile that does not propose a decrease of a container's CPU or
+	// memory request starts its count over; the count is kept in the workload's
+	// optipod.io/decrease-observations annotation. Increases are applied at once. Unset or 1
+	// applies decreases at once.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StableDecreaseObservations *int32 `json:"stableDecreaseObservations,omitempty"`
+
+	// IncreaseOnly only ever raises requests. A recommendation is applied per resource only when
+	// it is higher than the current request; a lower one keeps the current request and is
+	// reported as a suppressed decrease. A container whose recommendation only lowers requests
+	// is left unchanged. Requests above resourceBounds max are kept too.
+	// +kubebuilder:default=false
+	// +optional
+	IncreaseOnly bool `json:"increaseOnly,omitempty"`
+
+	// AllowGitOpsManaged applies changes to workloads managed by a GitOps tool, recognized by the
+	// argocd.argoproj.io/, kustomize.toolkit.fluxcd.io/ and helm.toolkit.fluxcd.io/ labels and
+	// annotations these tools set. The tool's next sync would revert the changes, so such
+	// workloads are only recommended by default. Enable it when the tool leaves the resources
+	// alone, e.g. Argo CD with Server-Side Apply or ignoreDifferences.
+	// +kubebuilder:default=false
+	// +optional
+	AllowGitOpsManaged bool `json:"allowGitOpsManaged,omitempty"`
+
+	// InPlaceBounds limits in-place resizes to small changes. A change beyond them recreates the
+	// pods, which requires allowRecreate, and can be held for approval. Unset resizes changes of
+	// any size in-place.
+	// +optional
+	InPlaceBounds *InPlaceBounds `json:"inPlaceBounds,omitempty"`
+
+	// PodLevelResources sizes each pod as a whole instead of container by container, on clusters
+	// with the PodLevelResources feature (Kubernetes 1.34+). The containers' recommendations are
+	// aggregated into the pod template's spec.resources and their own CPU and memory requests and
+	// limits are removed, so they share the pod's budget. The change is written with a strategic
+	// merge patch whatever useServerSideApply says. On older clusters containers are sized
+	// individually.
+	// +optional
+	PodLevelResources *PodLevelResources `json:"podLevelResources,omitempty"`
+
+	// MinRecreateInterval is the shortest time between two changes to a workload that recreate
+	// its pods. A change that would recreate the pods sooner after the last one, as recorded in
+	// the workload's optipod.io/last-applied and optipod.io/last-apply-method annotations, waits
+	// until the interval has passed. In-place resizes are not held back. Unset does not limit
+	// recreates.
+	// +optional
+	MinRecreateInterval *metav1.Duration `json:"minRecreateInterval,omitempty"`
+
+	// ApplyOrder is the order workloads are processed and changed in, which decides the
+	// workloads changed first when a reconciliation runs out of its time budget. AlphaByName
+	// follows namespace and name; SmallestFirst starts with the workloads running the fewest
+	// pods, the smallest blast radius; LargestSavingsFirst starts with the workloads whose last
+	// recorded recommendations free the most CPU, then memory, across their pods.
+	// +kubebuilder:validation:Enum=AlphaByName;SmallestFirst;LargestSavingsFirst
+	// +kubebuilder:default=AlphaByName
+	// +optional
+	ApplyOrder string `json:"applyOrder,omitempty"`
+
+	// ApplyTolerance defines how close a recommendation may be to the current requests for the
+	// change to be a no-op. A container whose optimized requests are all within tolerance is
+	// not patched. Unset applies every change.
+	// +optional
+	ApplyTolerance *ApplyTolerance `json:"applyTolerance,omitempty"`
+
 	// LimitConfig defines how resource limits are calculated from recommendations
 	// +optional
-	LimitConfig *LimitConfig `json:"limitConfig,omitempty"`
+	LimitConfig *LimitConfig `json:"limitConfig,omitempty"`
+}
+
+// Patch methods changes are written with
+const (
+	// PatchMethodServerSideApply writes changes with Server-Side Apply, owning the fields it sets
+	PatchMethodServerSideApply = "ServerSideApply"
+	// PatchMethodStrategicMergePatch writes changes with a strategic merge patch
+	PatchMethodStrategicMergePatch = "StrategicMergePatch"
+)
+
+// PatchMethodOverride selects the patch method for the workloads of one kind
+type PatchMethodOverride struct {
+	// Kind is the workload kind the method is used for
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet
+	Kind WorkloadType `json:"kind"`
+
+	// Method is the patch method: ServerSideApply or StrategicMergePatch
+	// +kubebuilder:validation:Enum=ServerSideApply;StrategicMergePatch
+	Method string `json:"method"`
+}
+
+// ApplyTolerance configures the no-op tolerance around the current requests, per resource
+type ApplyTolerance struct {
+	// CPU is the tolerance of CPU requests
+	// +optional
+	CPU *ResourceTolerance `json:"cpu,omitempty"`
+
+	// Memory is the tolerance of memory requests
+	// +optional
+	Memory *ResourceTolerance `json:"memory,omitempty"`
+}
+
+// ResourceTolerance defines how far a recommended request may be from the current request, in
+// either direction, to be within tolerance. A change within either the absolute or the relative
+// tolerance is within tolerance; an unset tolerance tolerates no change.
+type ResourceTolerance struct {
+	// Absolute is the largest change within tolerance, e.g. 10m or 16Mi
+	// +optional
+	Absolute *resource.Quantity `json:"absolute,omitempty"`
+
+	// Percent is the largest change within tolerance, as a percentage of the current request
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Percent *int32 `json:"percent,omitempty"`
+}
+
+// Within reports whether recommended is within the tolerance of current
+func (t *ResourceTolerance) Within(current, recommended resource.Quantity) bool {
+	if t == nil {
+		return false
+	}
+	diff := recommended.MilliValue() - current.MilliValue()
+	if diff < 0 {
+		diff = -diff
+	}
+	if t.Absolute != nil && diff <= t.Absolute.MilliValue() {
+		return true
+	}
+	return t.Percent != nil && diff*100 <= current.MilliValue()*int64(*t.Percent)
+}
+
+// Orders workloads are processed and changed in, see ApplyOrder
+const (
+	// ApplyOrderAlphaByName processes workloads by namespace and name
+	ApplyOrderAlphaByName = "AlphaByName"
+	// ApplyOrderSmallestFirst processes the workloads running the fewest pods first
+	ApplyOrderSmallestFirst = "SmallestFirst"
+	// ApplyOrderLargestSavingsFirst processes the workloads with the largest expected savings first
+	ApplyOrderLargestSavingsFirst = "LargestSavingsFirst"
+)
+
+// GetApplyOrder returns the order workloads are processed and changed in, AlphaByName by default
+func (s UpdateStrategy) GetApplyOrder() string {
+	if s.ApplyOrder == "" {
+		return ApplyOrderAlphaByName
+	}
+	return s.ApplyOrder
+}
+
+// UsesServerSideApply returns true if changes to workloads of the kind are written with
+// Server-Side Apply: the kind's patch method override if there is one, useServerSideApply
+// otherwise, which defaults to true
+func (s UpdateStrategy) UsesServerSideApply(kind string) bool {
+	for _, override := range s.PatchMethodOverrides {
+		if string(override.Kind) == kind {
+			return override.Method == PatchMethodServerSideApply
+		}
+	}
+	return s.UseServerSideApply == nil || *s.UseServerSideApply
+}
+
+// ManagesCPULimit returns true unless limitConfig.manageCPULimit leaves CPU limits alone
+func (s UpdateStrategy) ManagesCPULimit() bool {
+	return s.LimitConfig == nil || s.LimitConfig.ManageCPULimit == nil || *s.LimitConfig.ManageCPULimit
+}
+
+// ManagesMemoryLimit returns true unless limitConfig.manageMemoryLimit leaves memory limits alone
+func (s UpdateStrategy) ManagesMemoryLimit() bool {
+	return s.LimitConfig == nil || s.LimitConfig.ManageMemoryLimit == nil || *s.LimitConfig.ManageMemoryLimit
+}
+
+// InPlaceBounds defines how large a change may be to be resized in-place. Changes are measured
+// per container against its current requests.
+type InPlaceBounds struct {
+	// MaxCPUChangePercent is the largest CPU request change, as a percentage of the current
+	// request in either direction, resized in-place. Unset does not limit CPU changes.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxCPUChangePercent *int32 `json:"maxCPUChangePercent,omitempty"`
+
+	// MaxMemoryChangePercent is the largest memory request change, as a percentage of the current
+	// request in either direction, resized in-place. Unset does not limit memory changes.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxMemoryChangePercent *int32 `json:"maxMemoryChangePercent,omitempty"`
+
+	// ApprovalBeyondBounds holds changes beyond the bounds for approval through the workload's
+	// optipod.io/approved annotation, as approvalRequired does for every change, before the
+	// pods are recreated
+	// +kubebuilder:default=false
+	// +optional
+	ApprovalBeyondBounds bool `json:"approvalBeyondBounds,omitempty"`
+}
+
+// Aggregations of container recommendations into pod-level resources
+const (
+	// PodAggregationSum sizes the pod for all of its containers peaking at the same time
+	PodAggregationSum = "Sum"
+	// PodAggregationMax sizes the pod for its largest container, for containers that take turns
+	PodAggregationMax = "Max"
+)
+
+// PodLevelResources defines how container recommendations are combined into pod-level resources.
+// Containers without a recommendation keep their own requests, which are added on top.
+type PodLevelResources struct {
+	// Aggregation combines the containers' recommendations: Sum adds them up, Max takes the largest
+	// +kubebuilder:validation:Enum=Sum;Max
+	// +kubebuilder:default=Sum
+	// +optional
+	Aggregation string `json:"aggregation,omitempty"`
+}
+
+// GetAggregation returns the aggregation of container recommendations, Sum by default
+func (p *PodLevelResources) GetAggregation() string {
+	if p == nil || p.Aggregation == "" {
+		return PodAggregationSum
+	}
+	return p.Aggregation
 }
 
 // LimitConfig defines how resource limits are calculated from recommendations
@@ -237,10 +1346,105 @@ type LimitConfig struct {
 	// +kubebuilder:validation:Maximum=10.0
 	// +optional
 	MemoryLimitMultiplier *float64 `json:"memoryLimitMultiplier,omitempty"`
+
+	// MemoryLimitPercentile derives the memory limit from a higher percentile of observed
+	// memory usage (with the safety factor applied) instead of multiplying the memory request.
+	// When set, MemoryLimitMultiplier is ignored. Must not be lower than metricsConfig.percentile
+	// and requires updateRequestsOnly to be false.
+	// Example: requests at P90 and limits at P99
+	// +kubebuilder:validation:Enum=P50;P90;P99
+	// +optional
+	MemoryLimitPercentile string `json:"memoryLimitPercentile,omitempty"`
+
+	// BurstRatioLimits derives the CPU and memory limits from the observed burst ratio instead of
+	// the multipliers: limit = request * clamp(P99 / P90, minRatio, maxRatio). Bursty workloads
+	// get proportionally higher limits and steady ones tight limits. When set, CPULimitMultiplier
+	// and MemoryLimitMultiplier are ignored. Cannot be combined with MemoryLimitPercentile and
+	// requires updateRequestsOnly to be false.
+	// +optional
+	BurstRatioLimits *BurstRatioLimits `json:"burstRatioLimits,omitempty"`
+
+	// ManageCPULimit lets optipod set and remove CPU limits. When false, CPU limits are never
+	// written: containers without one stay without, and existing ones keep their value, while
+	// CPU requests are still optimized. Guaranteed containers keep their limits equal to their
+	// requests unless allowQoSClassChange is set.
+	// Default: true
+	// +kubebuilder:default=true
+	// +optional
+	ManageCPULimit *bool `json:"manageCPULimit,omitempty"`
+
+	// ManageMemoryLimit lets optipod set and remove memory limits. When false, memory limits are
+	// never written, as for ManageCPULimit.
+	// Default: true
+	// +kubebuilder:default=true
+	// +optional
+	ManageMemoryLimit *bool `json:"manageMemoryLimit,omitempty"`
+}
+
+// Default band of the limit to request ratio derived from the observed burst ratio
+const (
+	// DefaultMinBurstRatio keeps the limits of steady workloads equal to their requests
+	DefaultMinBurstRatio = 1.0
+	// DefaultMaxBurstRatio allows limits of bursty workloads up to three times their requests
+	DefaultMaxBurstRatio = 3.0
+)
+
+// BurstRatioLimits bounds the limit to request ratio derived from the observed P99 / P90 burst ratio
+type BurstRatioLimits struct {
+	// MinRatio is the lowest limit to request ratio, used for steady workloads (default 1.0)
+	// +kubebuilder:validation:Minimum=1.0
+	// +kubebuilder:validation:Maximum=10.0
+	// +optional
+	MinRatio *float64 `json:"minRatio,omitempty"`
+
+	// MaxRatio is the highest limit to request ratio, used for the burstiest workloads (default 3.0)
+	// +kubebuilder:validation:Minimum=1.0
+	// +kubebuilder:validation:Maximum=10.0
+	// +optional
+	MaxRatio *float64 `json:"maxRatio,omitempty"`
+}
+
+// Band returns the minimum and maximum limit to request ratio, falling back to the defaults for
+// unset values
+func (b *BurstRatioLimits) Band() (minRatio, maxRatio float64) {
+	minRatio, maxRatio = DefaultMinBurstRatio, DefaultMaxBurstRatio
+	if b == nil {
+		return minRatio, maxRatio
+	}
+	if b.MinRatio != nil {
+		minRatio = *b.MinRatio
+	}
+	if b.MaxRatio != nil {
+		maxRatio = *b.MaxRatio
+	}
+	return minRatio, maxRatio
 }
 
+// PolicyPhase summarizes the state of a policy for display
+type PolicyPhase string
+
+const (
+	// PolicyPhaseActive means the policy applies recommendations to its workloads
+	PolicyPhaseActive PolicyPhase = "Active"
+	// PolicyPhaseRecommending means the policy only computes recommendations
+	// (Recommend mode, or Auto mode while the workload cap is exceeded)
+	PolicyPhaseRecommending PolicyPhase = "Recommending"
+	// PolicyPhaseIdle means the policy matches no workloads
+	PolicyPhaseIdle PolicyPhase = "Idle"
+	// PolicyPhaseDegraded means processing failed for at least one workload
+	PolicyPhaseDegraded PolicyPhase = "Degraded"
+	// PolicyPhaseDisabled means the policy is disabled
+	PolicyPhaseDisabled PolicyPhase = "Disabled"
+	// PolicyPhaseInvalid means the policy failed validation
+	PolicyPhaseInvalid PolicyPhase = "Invalid"
+)
+
 // OptimizationPolicyStatus defines the observed state of OptimizationPolicy.
 type OptimizationPolicyStatus struct {
+	// Phase summarizes the state of the policy
+	// +optional
+	Phase PolicyPhase `json:"phase,omitempty"`
+
 	// Conditions represent the current state of the OptimizationPolicy resource.
 	// +listType=map
 	// +listMapKey=type
@@ -255,13 +1459,126 @@ type OptimizationPolicyStatus struct {
 	// +optional
 	WorkloadsProcessed int `json:"workloadsProcessed,omitempty"`
 
+	// WorkloadsApplied is the count of workloads whose recommendations were applied in the last reconciliation
+	// +optional
+	WorkloadsApplied int `json:"workloadsApplied,omitempty"`
+
+	// WorkloadsSkipped is the count of workloads skipped in the last reconciliation
+	// +optional
+	WorkloadsSkipped int `json:"workloadsSkipped,omitempty"`
+
+	// WorkloadsPendingApproval is the number of workloads with a proposal awaiting approval
+	// +optional
+	WorkloadsPendingApproval int `json:"workloadsPendingApproval,omitempty"`
+
+	// WorkloadsQuarantined is the number of workloads skipped because they are quarantined
+	// +optional
+	WorkloadsQuarantined int `json:"workloadsQuarantined,omitempty"`
+
+	// ObservedGeneration is the policy generation the last reconciliation processed. A policy
+	// summary event is emitted when it changes.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// LastReconciliation is the timestamp of the last reconciliation
 	// +optional
 	LastReconciliation *metav1.Time `json:"lastReconciliation,omitempty"`
 
+	// NextReconciliation is the time the next reconciliation is scheduled for
+	// +optional
+	NextReconciliation *metav1.Time `json:"nextReconciliation,omitempty"`
+
+	// AdaptiveInterval is the reconciliation interval adaptiveInterval has backed off to,
+	// before jitter
+	// +optional
+	AdaptiveInterval *metav1.Duration `json:"adaptiveInterval,omitempty"`
+
+	// LastManualReconcile is the optipod.io/reconcile-now annotation value of the last
+	// reconcile it triggered
+	// +optional
+	LastManualReconcile string `json:"lastManualReconcile,omitempty"`
+
 	// WorkloadsByType provides breakdown of workloads by type
 	// +optional
 	WorkloadsByType *WorkloadTypeStatus `json:"workloadsByType,omitempty"`
+
+	// Checkpoint records the progress of a reconciliation that ran out of its time budget. The
+	// next reconciliation resumes after the last processed workload instead of starting over.
+	// It is cleared once every workload has been processed.
+	// +optional
+	Checkpoint *ReconcileCheckpoint `json:"checkpoint,omitempty"`
+
+	// FailingWorkloads lists the workloads whose processing failed in consecutive
+	// reconciliations. Once a workload reaches the operator's quarantine failure threshold it is
+	// skipped until its quarantine expires, with the quarantine doubling on every further failure.
+	// Entries are removed when the workload is processed successfully or the policy changes.
+	// +optional
+	FailingWorkloads []FailingWorkload `json:"failingWorkloads,omitempty"`
+}
+
+// FailingWorkload records the consecutive processing failures of a workload
+type FailingWorkload struct {
+	// Kind is the workload kind
+	Kind string `json:"kind"`
+
+	// Namespace is the workload namespace
+	Namespace string `json:"namespace"`
+
+	// Name is the workload name
+	Name string `json:"name"`
+
+	// ConsecutiveFailures is the number of reconciliations in a row the workload failed in
+	ConsecutiveFailures int32 `json:"consecutiveFailures"`
+
+	// LastError is the error of the last failure
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// QuarantinedUntil is when the workload is processed again, set once it reached the
+	// quarantine failure threshold
+	// +optional
+	QuarantinedUntil *metav1.Time `json:"quarantinedUntil,omitempty"`
+}
+
+// ReconcileCheckpoint records how far a reconciliation got through the policy's workloads.
+// Workloads are processed in namespace, name and kind order, so every workload up to and
+// including the last one has been processed.
+type ReconcileCheckpoint struct {
+	// LastNamespace is the namespace of the last processed workload
+	LastNamespace string `json:"lastNamespace"`
+
+	// LastName is the name of the last processed workload
+	LastName string `json:"lastName"`
+
+	// LastKind is the kind of the last processed workload
+	LastKind string `json:"lastKind"`
+
+	// PolicyGeneration is the policy generation the progress was made with. A checkpoint from
+	// another generation is discarded, since the policy changed.
+	PolicyGeneration int64 `json:"policyGeneration"`
+
+	// StartedAt is when the reconciliation being resumed started
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// Processed is the number of workloads processed so far
+	// +optional
+	Processed int `json:"processed,omitempty"`
+
+	// Applied is the number of workloads whose recommendations were applied so far
+	// +optional
+	Applied int `json:"applied,omitempty"`
+
+	// Skipped is the number of workloads skipped so far
+	// +optional
+	Skipped int `json:"skipped,omitempty"`
+
+	// Failed is the number of workloads that failed to process so far
+	// +optional
+	Failed int `json:"failed,omitempty"`
+
+	// PendingApproval is the number of workloads with a proposal awaiting approval so far
+	// +optional
+	PendingApproval int `json:"pendingApproval,omitempty"`
 }
 
 // WorkloadTypeStatus provides breakdown by workload type
@@ -313,6 +1630,11 @@ type WorkloadStatus struct {
 	// +optional
 	Reason string `json:"reason,omitempty"`
 
+	// ProposalHash identifies the proposal awaiting approval when the policy requires approval.
+	// Setting the optipod.io/approved annotation of the workload to it approves the proposal.
+	// +optional
+	ProposalHash string `json:"proposalHash,omitempty"`
+
 	// LastApplyMethod indicates the patch method used for the last update
 	// +optional
 	LastApplyMethod string `json:"lastApplyMethod,omitempty"`
@@ -320,6 +1642,128 @@ type WorkloadStatus struct {
 	// FieldOwnership indicates if OptipPod owns resource fields via SSA
 	// +optional
 	FieldOwnership bool `json:"fieldOwnership,omitempty"`
+
+	// PodResources are the pod-level resources of the workload's pod template when the policy
+	// sizes pods as a whole, see updateStrategy.podLevelResources
+	// +optional
+	PodResources *corev1.ResourceRequirements `json:"podResources,omitempty"`
+
+	// ExcludedContainers lists containers skipped because they match ExcludeContainers
+	// +optional
+	ExcludedContainers []string `json:"excludedContainers,omitempty"`
+
+	// RemovedContainers lists the containers no longer in the workload whose recommendation
+	// annotations and decrease observations were cleaned up, see the policy's removedContainers
+	// +optional
+	RemovedContainers []string `json:"removedContainers,omitempty"`
+
+	// InformationalMetrics contains the results of the policy's informational queries
+	// +optional
+	InformationalMetrics []InformationalMetric `json:"informationalMetrics,omitempty"`
+
+	// StabilityScore rates from 0 to 100 how steady the workload's usage is, from the spread of
+	// its usage, how many samples back it and how often its containers restarted. Steady
+	// workloads are the safest to switch to Auto mode.
+	// +optional
+	StabilityScore *int32 `json:"stabilityScore,omitempty"`
+
+	// PartitionedRollout is the progress of the StatefulSet's partitioned rollout when the policy
+	// rolls changes out one pod at a time and a rollout is in progress
+	// +optional
+	PartitionedRollout *PartitionedRolloutStatus `json:"partitionedRollout,omitempty"`
+
+	// OnDeleteRollout is the progress of the rollout of an OnDelete workload when the policy
+	// deletes its pods one at a time and a rollout is in progress
+	// +optional
+	OnDeleteRollout *OnDeleteRolloutStatus `json:"onDeleteRollout,omitempty"`
+
+	// SafetyRampPhase is where the workload is in metricsConfig.safetyRamp: Initial, Tightening
+	// or Target
+	// +optional
+	SafetyRampPhase string `json:"safetyRampPhase,omitempty"`
+
+	// SafetyRampApplies is the number of stable applies counted toward metricsConfig.safetyRamp
+	// +optional
+	SafetyRampApplies int32 `json:"safetyRampApplies,omitempty"`
+
+	// ContainersWithoutMetrics lists the containers whose metrics could not be collected and what
+	// was done with each, see partialMetrics
+	// +optional
+	ContainersWithoutMetrics []ContainerWithoutMetrics `json:"containersWithoutMetrics,omitempty"`
+}
+
+// Outcomes of a container without metrics
+const (
+	// OutcomeWorkloadSkipped means the whole workload was skipped for the missing metrics
+	OutcomeWorkloadSkipped = "WorkloadSkipped"
+	// OutcomeLeftAsIs means the container's requests were left unchanged
+	OutcomeLeftAsIs = "LeftAsIs"
+	// OutcomeRaisedToFloor means the container's requests were raised to the partialMetrics floor
+	OutcomeRaisedToFloor = "RaisedToFloor"
+)
+
+// ContainerWithoutMetrics records a container whose metrics could not be collected
+type ContainerWithoutMetrics struct {
+	// Container is the container name
+	// +kubebuilder:validation:Required
+	Container string `json:"container"`
+
+	// Outcome is WorkloadSkipped, LeftAsIs or RaisedToFloor
+	// +optional
+	Outcome string `json:"outcome,omitempty"`
+
+	// Reason describes why the metrics could not be collected
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// NoMatchingSeries is true when the metrics backend has no series at all for the
+	// container's pod, usually because the metric labels do not match the pod name
+	// +optional
+	NoMatchingSeries bool `json:"noMatchingSeries,omitempty"`
+}
+
+// PartitionedRolloutStatus is the progress of a partitioned StatefulSet rollout
+type PartitionedRolloutStatus struct {
+	// Partition is the StatefulSet's rollingUpdate partition. Pods with an ordinal at or above
+	// it are updated to the new resources.
+	Partition int32 `json:"partition"`
+
+	// Replicas is the number of replicas of the StatefulSet
+	Replicas int32 `json:"replicas"`
+}
+
+// OnDeleteRolloutStatus is the progress of the rollout of a workload with the OnDelete update
+// strategy, whose pods are deleted one at a time
+type OnDeleteRolloutStatus struct {
+	// UpdatedPods is the number of pods running the workload's current requests
+	UpdatedPods int32 `json:"updatedPods"`
+
+	// Pods is the number of pods of the workload
+	Pods int32 `json:"pods"`
+
+	// DisruptionBlocked is true when a PodDisruptionBudget refused the last eviction
+	// +optional
+	DisruptionBlocked bool `json:"disruptionBlocked,omitempty"`
+}
+
+// UpdatedReplicas returns the number of pods the rollout has reached so far
+func (p *PartitionedRolloutStatus) UpdatedReplicas() int32 {
+	return max(p.Replicas-p.Partition, 0)
+}
+
+// InformationalMetric is the result of an informational query for a workload
+type InformationalMetric struct {
+	// Name is the name of the informational query
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Value is the query result
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Error describes why the query could not be evaluated
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // ContainerRecommendation represents resource recommendations for a single container
@@ -339,13 +1783,170 @@ type ContainerRecommendation struct {
 	// Explanation describes how the recommendation was computed
 	// +optional
 	Explanation string `json:"explanation,omitempty"`
+
+	// AppliedCPU is the CPU request set by the last change when updateStrategy.convergenceRate
+	// is set. It lags behind CPU until requests have converged to the recommendation.
+	// +optional
+	AppliedCPU *resource.Quantity `json:"appliedCPU,omitempty"`
+
+	// AppliedMemory is the memory request set by the last change when
+	// updateStrategy.convergenceRate is set
+	// +optional
+	AppliedMemory *resource.Quantity `json:"appliedMemory,omitempty"`
+
+	// Converging is true while updateStrategy.convergenceRate is still moving the requests
+	// toward the recommendation
+	// +optional
+	Converging bool `json:"converging,omitempty"`
+
+	// ClampedToBound lists the resource bounds the recommendation was clamped to: CPUMin, CPUMax,
+	// MemoryMin or MemoryMax. Frequent max clamping suggests the bound is too low; frequent min
+	// clamping suggests the workload is over-requested.
+	// +optional
+	ClampedToBound []string `json:"clampedToBound,omitempty"`
+
+	// LowConfidence is true when the recommendation was computed from the usage of the whole pod,
+	// because the metrics backend has no per-container metrics. It is only computed for a
+	// container running alone in its pod or holding nearly all of the pod's requests.
+	// +optional
+	LowConfidence bool `json:"lowConfidence,omitempty"`
+
+	// DataQuality shows the inputs the recommendation was computed from, from the usage samples
+	// to the value before clamping, so an unexpected recommendation can be traced back
+	// +optional
+	DataQuality *RecommendationDataQuality `json:"dataQuality,omitempty"`
+
+	// Accuracy compares the usage observed since the workload's last applied change with the
+	// container's requests. It is set once a full metricsConfig.rollingWindow has passed since
+	// the change, so every usage sample was taken with the applied requests.
+	// +optional
+	Accuracy *RecommendationAccuracy `json:"accuracy,omitempty"`
+}
+
+// Verdicts of a RecommendationAccuracy
+const (
+	// AccuracyAccurate means the applied requests fit the observed usage
+	AccuracyAccurate = "Accurate"
+
+	// AccuracyOverProvisioned means the observed usage is below half of every applied request
+	AccuracyOverProvisioned = "OverProvisioned"
+
+	// AccuracyUnderProvisioned means the observed usage exceeds an applied request
+	AccuracyUnderProvisioned = "UnderProvisioned"
+)
+
+// RecommendationAccuracy measures how well the requests of the last applied change fit the
+// usage observed since
+type RecommendationAccuracy struct {
+	// AppliedAt is when the change was applied
+	AppliedAt metav1.Time `json:"appliedAt"`
+
+	// CPUUtilizationPercent is the CPU usage at the recommendation's percentile as a percentage
+	// of the CPU request
+	// +optional
+	CPUUtilizationPercent *int32 `json:"cpuUtilizationPercent,omitempty"`
+
+	// MemoryUtilizationPercent is the memory usage at the recommendation's percentile as a
+	// percentage of the memory request
+	// +optional
+	MemoryUtilizationPercent *int32 `json:"memoryUtilizationPercent,omitempty"`
+
+	// Verdict is Accurate, OverProvisioned when the usage is below half of every request, or
+	// UnderProvisioned when the usage exceeds a request
+	Verdict string `json:"verdict"`
+}
+
+// RecommendationDataQuality describes the usage metrics a container's recommendation was
+// computed from and how they were turned into the recommendation
+type RecommendationDataQuality struct {
+	// Percentile is the usage the recommendation was sized to, e.g. P90 percentile
+	// +optional
+	Percentile string `json:"percentile,omitempty"`
+
+	// SafetyFactor is the factor the usage was multiplied by
+	// +optional
+	SafetyFactor float64 `json:"safetyFactor,omitempty"`
+
+	// CPU describes the CPU usage the CPU recommendation was computed from
+	// +optional
+	CPU *ResourceDataQuality `json:"cpu,omitempty"`
+
+	// Memory describes the memory usage the memory recommendation was computed from
+	// +optional
+	Memory *ResourceDataQuality `json:"memory,omitempty"`
+}
+
+// ResourceDataQuality describes the usage samples of one resource and the values derived from them
+type ResourceDataQuality struct {
+	// Samples is the number of usage samples the percentiles were computed from
+	// +optional
+	Samples int32 `json:"samples,omitempty"`
+
+	// WindowCoveragePercent is the share of metricsConfig.rollingWindow the samples span.
+	// Usage observed over a small part of the window, e.g. of a new workload, is less
+	// representative.
+	// +optional
+	WindowCoveragePercent int32 `json:"windowCoveragePercent,omitempty"`
+
+	// PercentileValue is the observed usage at the chosen percentile
+	// +optional
+	PercentileValue *resource.Quantity `json:"percentileValue,omitempty"`
+
+	// Base is the usage the safety factor was applied to: the percentile value after daily
+	// peaks, window blending, reactive overrides, limit pressure and replica scaling
+	// +optional
+	Base *resource.Quantity `json:"base,omitempty"`
+
+	// SafetyFactor is the factor Base was multiplied by when it differs from the
+	// recommendation's safety factor, e.g. the memory safety factor of priorityMemoryHeadroom
+	// +optional
+	SafetyFactor float64 `json:"safetyFactor,omitempty"`
+
+	// Computed is the usage-derived value with the safety factor applied, before it was clamped
+	// to the resource bounds
+	// +optional
+	Computed *resource.Quantity `json:"computed,omitempty"`
+
+	// ClampedTo is the resource bound the computed value was clamped to, e.g. CPUMax; empty when
+	// the computed value is within the bounds
+	// +optional
+	ClampedTo string `json:"clampedTo,omitempty"`
+
+	// Final is the recommended request: the clamped value after startup floors and any other
+	// adjustment named in the explanation. It equals the container's CPU or Memory.
+	// +optional
+	Final *resource.Quantity `json:"final,omitempty"`
+
+	// LimitMultiplier is the limit to request ratio the limit is derived from, set by
+	// limitConfig or the observed burst ratio. It is unset when limits are not updated or the
+	// memory limit is derived from limitConfig.memoryLimitPercentile.
+	// +optional
+	LimitMultiplier float64 `json:"limitMultiplier,omitempty"`
 }
 
+// Resource bounds a recommendation can be clamped to, see ContainerRecommendation.ClampedToBound
+const (
+	BoundCPUMin    = "CPUMin"
+	BoundCPUMax    = "CPUMax"
+	BoundMemoryMin = "MemoryMin"
+	BoundMemoryMax = "MemoryMax"
+
+	// BoundNodeCapacity is reported when a recommendation is capped at node allocatable,
+	// see NodeCapacityCap
+	BoundNodeCapacity = "ExceedsNodeCapacity"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Namespaced,shortName=optpol
 // +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
-// +kubebuilder:printcolumn:name="Provider",type=string,JSONPath=`.spec.metricsConfig.provider`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Matched",type=integer,JSONPath=`.status.workloadsDiscovered`
+// +kubebuilder:printcolumn:name="Applied",type=integer,JSONPath=`.status.workloadsApplied`
+// +kubebuilder:printcolumn:name="Skipped",type=integer,JSONPath=`.status.workloadsSkipped`
+// +kubebuilder:printcolumn:name="Next Reconcile",type=string,JSONPath=`.status.nextReconciliation`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`,priority=1
+// +kubebuilder:printcolumn:name="Provider",type=string,JSONPath=`.spec.metricsConfig.provider`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // OptimizationPolicy is the Schema for the optimizationpolicies API
@@ -379,7 +1980,158 @@ func (r *OptimizationPolicy) GetWeight() int32 {
 	if r.Spec.Weight != nil {
 		return *r.Spec.Weight
 	}
-	return 100 // Default weight
+	return 100 // Default weight
+}
+
+// GetMaxWorkloads returns the workload cap for this policy, falling back to the
+// given operator default. A result of zero means no cap is enforced.
+func (r *OptimizationPolicy) GetMaxWorkloads(defaultMax int) int {
+	if r.Spec.MaxWorkloads != nil {
+		return int(*r.Spec.MaxWorkloads)
+	}
+	if defaultMax < 0 {
+		return 0
+	}
+	return defaultMax
+}
+
+// OptimizesCPU reports whether the policy sizes CPU, defaulting to true when unset
+func (r *OptimizationPolicy) OptimizesCPU() bool {
+	return r.Spec.OptimizeCPU == nil || *r.Spec.OptimizeCPU
+}
+
+// OptimizesMemory reports whether the policy sizes memory, defaulting to true when unset
+func (r *OptimizationPolicy) OptimizesMemory() bool {
+	return r.Spec.OptimizeMemory == nil || *r.Spec.OptimizeMemory
+}
+
+// GetRollingWindow returns the rolling window, defaulting to 24h when unset
+func (m MetricsConfig) GetRollingWindow() time.Duration {
+	if m.RollingWindow.Duration > 0 {
+		return m.RollingWindow.Duration
+	}
+	return 24 * time.Hour
+}
+
+// GetSafetyFactor returns the safety factor, defaulting to 1.2 when unset
+func (m MetricsConfig) GetSafetyFactor() float64 {
+	if m.SafetyFactor != nil {
+		return *m.SafetyFactor
+	}
+	return 1.2
+}
+
+// GetStartupFloorPeriod returns how long after workload creation the startup floor is enforced
+func (r *OptimizationPolicy) GetStartupFloorPeriod() time.Duration {
+	if r.Spec.StartupFloor == nil {
+		return 0
+	}
+	if r.Spec.StartupFloor.Period.Duration > 0 {
+		return r.Spec.StartupFloor.Period.Duration
+	}
+	return time.Hour
+}
+
+// GetPartitionStep returns the number of pods a partitioned rollout updates at a time, 1 by default
+func (r *OptimizationPolicy) GetPartitionStep() int64 {
+	if step := r.Spec.UpdateStrategy.PartitionStep; step != nil && *step > 0 {
+		return int64(*step)
+	}
+	return 1
+}
+
+// GetRemovedContainers returns the handling of removed containers' state, Cleanup by default
+func (r *OptimizationPolicy) GetRemovedContainers() string {
+	if r.Spec.RemovedContainers == "" {
+		return RemovedContainersCleanup
+	}
+	return r.Spec.RemovedContainers
+}
+
+// MatchContainerSelector returns the first container selector matching the given
+// container name. When no selectors are configured, it returns nil and true so that
+// all containers are processed with the policy defaults.
+func (r *OptimizationPolicy) MatchContainerSelector(containerName string) (*ContainerSelector, bool) {
+	if len(r.Spec.ContainerSelectors) == 0 {
+		return nil, true
+	}
+
+	for i := range r.Spec.ContainerSelectors {
+		selector := &r.Spec.ContainerSelectors[i]
+		if selector.Matches(containerName) {
+			return selector, true
+		}
+	}
+
+	return nil, false
+}
+
+// Matches returns true if the container name matches the selector's name pattern. A malformed
+// pattern matches nothing.
+func (s *ContainerSelector) Matches(containerName string) bool {
+	if s.MatchType == ContainerMatchRegex {
+		re, err := s.compileRegex()
+		return err == nil && re.MatchString(containerName)
+	}
+	matched, err := path.Match(s.Name, containerName)
+	return err == nil && matched
+}
+
+// compiledContainerRegex is the result of compiling a Regex selector name
+type compiledContainerRegex struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// containerRegexCache holds the compiled Regex selector names by name, so a pattern is compiled
+// once when the policy is validated rather than for every container on every reconcile
+var containerRegexCache sync.Map
+
+// compileRegex compiles the selector's name as a regular expression anchored to the whole
+// container name, reusing an earlier compilation of the same name
+func (s *ContainerSelector) compileRegex() (*regexp.Regexp, error) {
+	if cached, ok := containerRegexCache.Load(s.Name); ok {
+		compiled := cached.(compiledContainerRegex)
+		return compiled.re, compiled.err
+	}
+	re, err := regexp.Compile(`^(?:` + s.Name + `)$`)
+	containerRegexCache.Store(s.Name, compiledContainerRegex{re: re, err: err})
+	return re, err
+}
+
+// IsContainerExcluded returns true if the container name matches any ExcludeContainers pattern
+func (r *OptimizationPolicy) IsContainerExcluded(containerName string) bool {
+	for _, pattern := range r.Spec.ExcludeContainers {
+		if matched, err := path.Match(pattern, containerName); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// GetContainerMode returns the effective mode for a container matched by the given
+// selector. A selector can only narrow the policy mode (Auto > Recommend > Disabled).
+func (r *OptimizationPolicy) GetContainerMode(selector *ContainerSelector) PolicyMode {
+	if selector == nil || selector.Mode == "" {
+		return r.Spec.Mode
+	}
+
+	if modeRank(selector.Mode) < modeRank(r.Spec.Mode) {
+		return selector.Mode
+	}
+	return r.Spec.Mode
+}
+
+// modeRank orders modes from least to most permissive
+func modeRank(mode PolicyMode) int {
+	switch mode {
+	case ModeAuto:
+		return 2
+	case ModeRecommend:
+		return 1
+	default:
+		return 0
+	}
 }
 
 // InitializeWorkloadTypeStatus initializes the WorkloadsByType field if it's nil
@@ -540,16 +2292,625 @@ func (r *OptimizationPolicy) validateOptimizationPolicy() error {
 			r.Spec.ResourceBounds.Memory.Max.String())
 	}
 
+	// Validate optimized resources
+	if !r.OptimizesCPU() && !r.OptimizesMemory() {
+		return fmt.Errorf("at least one of optimizeCPU and optimizeMemory must be true")
+	}
+
 	// Validate safety factor
 	if r.Spec.MetricsConfig.SafetyFactor != nil && *r.Spec.MetricsConfig.SafetyFactor < 1.0 {
 		return fmt.Errorf("safety factor must be at least 1.0, got %f", *r.Spec.MetricsConfig.SafetyFactor)
 	}
 
+	// Validate the safety ramp
+	if err := validateSafetyRamp(r.Spec.MetricsConfig); err != nil {
+		return err
+	}
+
+	// Validate priority memory headroom
+	if err := validatePriorityMemoryHeadroom(r.Spec.MetricsConfig.PriorityMemoryHeadroom); err != nil {
+		return err
+	}
+
+	// Validate the metrics staleness threshold
+	if age := r.Spec.MetricsConfig.MaxMetricsAge; age != nil && age.Duration <= 0 {
+		return fmt.Errorf("metricsConfig.maxMetricsAge must be positive, got %s", age.Duration)
+	}
+
+	// Validate window blending
+	if err := validateBlendConfig(r.Spec.MetricsConfig); err != nil {
+		return err
+	}
+
+	// Validate daily peak sizing
+	if err := validateDailyPeaksConfig(r.Spec.MetricsConfig); err != nil {
+		return err
+	}
+
+	// Validate the memory peak floor
+	if floor := r.Spec.MetricsConfig.MemoryPeakFloor; floor != nil && floor.Retention != nil {
+		if rollingWindow := r.Spec.MetricsConfig.GetRollingWindow(); floor.Retention.Duration < rollingWindow {
+			return fmt.Errorf("metricsConfig.memoryPeakFloor.retention (%s) must be at least the rolling window (%s)",
+				floor.Retention.Duration, rollingWindow)
+		}
+	}
+
+	// Validate per-resource statistics
+	if err := validateResourceStatistics(r.Spec.MetricsConfig); err != nil {
+		return err
+	}
+
+	// Validate informational queries
+	if err := validateInformationalQueries(r.Spec.MetricsConfig.InformationalQueries); err != nil {
+		return err
+	}
+
 	// Validate weight
 	if r.Spec.Weight != nil && (*r.Spec.Weight < 1 || *r.Spec.Weight > 1000) {
 		return fmt.Errorf("weight must be between 1 and 1000, got %d", *r.Spec.Weight)
 	}
 
+	// Validate max workloads
+	if r.Spec.MaxWorkloads != nil && *r.Spec.MaxWorkloads < 1 {
+		return fmt.Errorf("maxWorkloads must be at least 1, got %d", *r.Spec.MaxWorkloads)
+	}
+
+	// Validate minimum stability score
+	if score := r.Spec.MinStabilityScore; score != nil && (*score < 0 || *score > 100) {
+		return fmt.Errorf("minStabilityScore must be between 0 and 100, got %d", *score)
+	}
+
+	// Validate adaptive reconciliation interval
+	if err := validateAdaptiveInterval(r.Spec.AdaptiveInterval, r.Spec.ReconciliationInterval.Duration); err != nil {
+		return err
+	}
+
+	// Validate node capacity headroom
+	if c := r.Spec.NodeCapacityCap; c != nil && c.HeadroomPercent != nil &&
+		(*c.HeadroomPercent < 0 || *c.HeadroomPercent > 90) {
+		return fmt.Errorf("nodeCapacityCap.headroomPercent must be between 0 and 90, got %d", *c.HeadroomPercent)
+	}
+
+	// Validate recommendation hysteresis
+	if h := r.Spec.RecommendationHysteresis; h != nil {
+		if cpuPercent, memoryPercent := h.Band(); cpuPercent < 0 || cpuPercent > 50 || memoryPercent < 0 || memoryPercent > 50 {
+			return fmt.Errorf("recommendationHysteresis percentages must be between 0 and 50, got cpuPercent %d and memoryPercent %d",
+				cpuPercent, memoryPercent)
+		}
+	}
+
+	// Validate cache headroom
+	if err := validateCacheHeadroom(r.Spec.CacheHeadroom); err != nil {
+		return err
+	}
+
+	// Validate partial metrics handling
+	if err := validatePartialMetrics(r.Spec.PartialMetrics); err != nil {
+		return err
+	}
+
+	// Validate removed container handling
+	if handling := r.GetRemovedContainers(); handling != RemovedContainersCleanup && handling != RemovedContainersRetain {
+		return fmt.Errorf("removedContainers must be Cleanup or Retain, got %q", handling)
+	}
+
+	// Validate apply order
+	switch order := r.Spec.UpdateStrategy.GetApplyOrder(); order {
+	case ApplyOrderAlphaByName, ApplyOrderSmallestFirst, ApplyOrderLargestSavingsFirst:
+	default:
+		return fmt.Errorf("updateStrategy.applyOrder must be AlphaByName, SmallestFirst or LargestSavingsFirst, got %q", order)
+	}
+
+	// Validate apply tolerance
+	if t := r.Spec.UpdateStrategy.ApplyTolerance; t != nil {
+		for _, resourceTolerance := range []struct {
+			name      string
+			tolerance *ResourceTolerance
+		}{{"cpu", t.CPU}, {"memory", t.Memory}} {
+			name, tolerance := resourceTolerance.name, resourceTolerance.tolerance
+			if tolerance == nil {
+				continue
+			}
+			if tolerance.Absolute != nil && tolerance.Absolute.Sign() < 0 {
+				return fmt.Errorf("updateStrategy.applyTolerance.%s.absolute must not be negative", name)
+			}
+			if tolerance.Percent != nil && (*tolerance.Percent < 0 || *tolerance.Percent > 100) {
+				return fmt.Errorf("updateStrategy.applyTolerance.%s.percent must be between 0 and 100, got %d", name, *tolerance.Percent)
+			}
+		}
+	}
+
+	// Validate convergence rate
+	if rate := r.Spec.UpdateStrategy.ConvergenceRate; rate != nil && (*rate <= 0 || *rate > 1) {
+		return fmt.Errorf("updateStrategy.convergenceRate must be greater than 0 and at most 1, got %g", *rate)
+	}
+
+	// Validate in-place bounds
+	if b := r.Spec.UpdateStrategy.InPlaceBounds; b != nil {
+		if (b.MaxCPUChangePercent != nil && *b.MaxCPUChangePercent < 1) || (b.MaxMemoryChangePercent != nil && *b.MaxMemoryChangePercent < 1) {
+			return fmt.Errorf("updateStrategy.inPlaceBounds change percentages must be at least 1")
+		}
+	}
+
+	// Validate pod-level resources
+	if p := r.Spec.UpdateStrategy.PodLevelResources; p != nil {
+		if aggregation := p.GetAggregation(); aggregation != PodAggregationSum && aggregation != PodAggregationMax {
+			return fmt.Errorf("updateStrategy.podLevelResources.aggregation must be Sum or Max, got %q", aggregation)
+		}
+	}
+
+	// Validate patch method overrides
+	if err := validatePatchMethodOverrides(r.Spec.UpdateStrategy.PatchMethodOverrides); err != nil {
+		return err
+	}
+
+	// Validate the minimum interval between recreates
+	if interval := r.Spec.UpdateStrategy.MinRecreateInterval; interval != nil && interval.Duration <= 0 {
+		return fmt.Errorf("updateStrategy.minRecreateInterval must be positive, got %s", interval.Duration)
+	}
+
+	// Validate limit configuration
+	if err := validateLimitConfig(r.Spec.UpdateStrategy, r.Spec.MetricsConfig.Percentile); err != nil {
+		return err
+	}
+
+	// Validate container selectors
+	for i, selector := range r.Spec.ContainerSelectors {
+		if err := validateContainerSelector(selector, fmt.Sprintf("containerSelectors[%d]", i)); err != nil {
+			return err
+		}
+	}
+
+	// Validate startup floor
+	if err := validateStartupFloor(r.Spec.StartupFloor, r.Spec.ResourceBounds); err != nil {
+		return err
+	}
+
+	// Validate CPU to memory ratio band
+	if err := validateCPUMemoryRatio(r.Spec.CPUMemoryRatio); err != nil {
+		return err
+	}
+
+	// Validate container exclusions
+	for i, pattern := range r.Spec.ExcludeContainers {
+		if pattern == "" {
+			return fmt.Errorf("excludeContainers[%d] must not be empty", i)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("excludeContainers[%d]: invalid glob pattern %q: %w", i, pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// validateInformationalQueries validates the informational queries and caps how many are allowed
+func validateInformationalQueries(queries []InformationalQuery) error {
+	if len(queries) > MaxInformationalQueries {
+		return fmt.Errorf("metricsConfig.informationalQueries allows at most %d queries, got %d",
+			MaxInformationalQueries, len(queries))
+	}
+
+	names := make(map[string]bool, len(queries))
+	for i, query := range queries {
+		if query.Name == "" {
+			return fmt.Errorf("metricsConfig.informationalQueries[%d].name must not be empty", i)
+		}
+		if names[query.Name] {
+			return fmt.Errorf("metricsConfig.informationalQueries[%d]: duplicate name %q", i, query.Name)
+		}
+		names[query.Name] = true
+
+		if strings.TrimSpace(query.Query) == "" {
+			return fmt.Errorf("metricsConfig.informationalQueries[%d].query must not be empty", i)
+		}
+	}
+
+	return nil
+}
+
+// validatePatchMethodOverrides validates the per-kind patch methods
+func validatePatchMethodOverrides(overrides []PatchMethodOverride) error {
+	kinds := make(map[WorkloadType]bool, len(overrides))
+	for i, override := range overrides {
+		field := fmt.Sprintf("updateStrategy.patchMethodOverrides[%d]", i)
+		if err := validateWorkloadType(override.Kind, field+".kind"); err != nil {
+			return err
+		}
+		if kinds[override.Kind] {
+			return fmt.Errorf("%s: duplicate kind %q", field, override.Kind)
+		}
+		kinds[override.Kind] = true
+
+		if override.Method != PatchMethodServerSideApply && override.Method != PatchMethodStrategicMergePatch {
+			return fmt.Errorf("%s.method must be ServerSideApply or StrategicMergePatch, got %q", field, override.Method)
+		}
+	}
+	return nil
+}
+
+// validateLimitConfig validates how limits are derived from recommendations
+func validateLimitConfig(strategy UpdateStrategy, requestPercentile string) error {
+	if err := validateBurstRatioLimits(strategy); err != nil {
+		return err
+	}
+
+	if strategy.LimitConfig == nil || strategy.LimitConfig.MemoryLimitPercentile == "" {
+		return nil
+	}
+
+	limitPercentile := strategy.LimitConfig.MemoryLimitPercentile
+	limitRank, ok := percentileRank(limitPercentile)
+	if !ok {
+		return fmt.Errorf("invalid updateStrategy.limitConfig.memoryLimitPercentile %q, must be one of: P50, P90, P99", limitPercentile)
+	}
+
+	if strategy.RemoveLimits {
+		return fmt.Errorf("updateStrategy.limitConfig.memoryLimitPercentile cannot be combined with updateStrategy.removeLimits")
+	}
+
+	if strategy.UpdateRequestsOnly {
+		return fmt.Errorf("updateStrategy.limitConfig.memoryLimitPercentile requires updateStrategy.updateRequestsOnly to be false")
+	}
+
+	if !strategy.ManagesMemoryLimit() {
+		return fmt.Errorf("updateStrategy.limitConfig.memoryLimitPercentile requires updateStrategy.limitConfig.manageMemoryLimit")
+	}
+
+	if requestPercentile == "" {
+		requestPercentile = "P90"
+	}
+	if requestRank, ok := percentileRank(requestPercentile); ok && limitRank < requestRank {
+		return fmt.Errorf("updateStrategy.limitConfig.memoryLimitPercentile (%s) must not be lower than metricsConfig.percentile (%s)",
+			limitPercentile, requestPercentile)
+	}
+
+	return nil
+}
+
+// validateBurstRatioLimits validates the band of burst-ratio-derived limits and that limits are
+// updated at all
+func validateBurstRatioLimits(strategy UpdateStrategy) error {
+	if strategy.LimitConfig == nil || strategy.LimitConfig.BurstRatioLimits == nil {
+		return nil
+	}
+
+	minRatio, maxRatio := strategy.LimitConfig.BurstRatioLimits.Band()
+	if minRatio < 1 || maxRatio > 10 {
+		return fmt.Errorf("updateStrategy.limitConfig.burstRatioLimits ratios must be between 1 and 10, got %g and %g", minRatio, maxRatio)
+	}
+	if minRatio > maxRatio {
+		return fmt.Errorf("updateStrategy.limitConfig.burstRatioLimits.minRatio (%g) must not be greater than maxRatio (%g)", minRatio, maxRatio)
+	}
+
+	if strategy.LimitConfig.MemoryLimitPercentile != "" {
+		return fmt.Errorf("updateStrategy.limitConfig.burstRatioLimits cannot be combined with memoryLimitPercentile")
+	}
+	if strategy.RemoveLimits {
+		return fmt.Errorf("updateStrategy.limitConfig.burstRatioLimits cannot be combined with updateStrategy.removeLimits")
+	}
+	if strategy.UpdateRequestsOnly {
+		return fmt.Errorf("updateStrategy.limitConfig.burstRatioLimits requires updateStrategy.updateRequestsOnly to be false")
+	}
+
+	return nil
+}
+
+// validateCacheHeadroom validates that cache headroom is set either as a percentage or an amount
+func validateCacheHeadroom(headroom *CacheHeadroom) error {
+	if headroom == nil {
+		return nil
+	}
+	if (headroom.Percent == nil) == (headroom.Amount == nil) {
+		return fmt.Errorf("cacheHeadroom must set exactly one of percent and amount")
+	}
+	if headroom.Percent != nil && (*headroom.Percent < 1 || *headroom.Percent > 400) {
+		return fmt.Errorf("cacheHeadroom.percent must be between 1 and 400, got %d", *headroom.Percent)
+	}
+	if headroom.Amount != nil && headroom.Amount.Sign() <= 0 {
+		return fmt.Errorf("cacheHeadroom.amount must be positive, got %s", headroom.Amount.String())
+	}
+	return nil
+}
+
+// validatePartialMetrics validates the action and floor for containers without metrics
+func validatePartialMetrics(partial *PartialMetrics) error {
+	if partial == nil {
+		return nil
+	}
+	switch action := partial.GetAction(); action {
+	case PartialMetricsSkipWorkload, PartialMetricsLeaveAsIs, PartialMetricsFloor:
+		if action != PartialMetricsFloor && (partial.CPU != nil || partial.Memory != nil) {
+			return fmt.Errorf("partialMetrics.cpu and memory require the Floor action")
+		}
+	default:
+		return fmt.Errorf("partialMetrics.action must be SkipWorkload, LeaveAsIs or Floor, got %q", action)
+	}
+	if partial.CPU != nil && partial.CPU.Sign() <= 0 {
+		return fmt.Errorf("partialMetrics.cpu must be positive, got %s", partial.CPU.String())
+	}
+	if partial.Memory != nil && partial.Memory.Sign() <= 0 {
+		return fmt.Errorf("partialMetrics.memory must be positive, got %s", partial.Memory.String())
+	}
+	return nil
+}
+
+// validateSafetyRamp validates that the safety ramp starts at or above the safety factor
+func validateSafetyRamp(metricsConfig MetricsConfig) error {
+	ramp := metricsConfig.SafetyRamp
+	if ramp == nil {
+		return nil
+	}
+	if target := metricsConfig.GetSafetyFactor(); ramp.InitialSafetyFactor < target {
+		return fmt.Errorf("metricsConfig.safetyRamp.initialSafetyFactor (%g) must be at least metricsConfig.safetyFactor (%g)",
+			ramp.InitialSafetyFactor, target)
+	}
+	if applies, minScore := ramp.Steps(); applies < 1 || applies > 20 || minScore < 0 || minScore > 100 {
+		return fmt.Errorf("metricsConfig.safetyRamp.applies must be between 1 and 20 and minStabilityScore between 0 and 100, got %d and %d",
+			applies, minScore)
+	}
+	return nil
+}
+
+// validateAdaptiveInterval validates that the adaptive interval bounds are positive and ordered
+func validateAdaptiveInterval(adaptive *AdaptiveInterval, base time.Duration) error {
+	if adaptive == nil {
+		return nil
+	}
+	minInterval, maxInterval := adaptive.Bounds(base)
+	if adaptive.MinInterval != nil && minInterval <= 0 {
+		return fmt.Errorf("adaptiveInterval.minInterval must be positive, got %s", minInterval)
+	}
+	if maxInterval <= 0 {
+		return fmt.Errorf("adaptiveInterval.maxInterval must be positive, got %s", maxInterval)
+	}
+	if minInterval > maxInterval {
+		return fmt.Errorf("adaptiveInterval.minInterval (%s) must not exceed adaptiveInterval.maxInterval (%s)",
+			minInterval, maxInterval)
+	}
+	if score := adaptive.GetStableScore(); score < 0 || score > 100 {
+		return fmt.Errorf("adaptiveInterval.stableScore must be between 0 and 100, got %d", score)
+	}
+	return nil
+}
+
+// validatePriorityMemoryHeadroom validates that each entry matches pods in one way, at most once,
+// with a safety factor of at least 1.0
+func validatePriorityMemoryHeadroom(entries []PriorityMemoryHeadroom) error {
+	classNames := make(map[string]bool, len(entries))
+	minPriorities := make(map[int32]bool, len(entries))
+	for i, entry := range entries {
+		if (entry.PriorityClassName == "") == (entry.MinPriority == nil) {
+			return fmt.Errorf("metricsConfig.priorityMemoryHeadroom[%d] must set exactly one of priorityClassName and minPriority", i)
+		}
+		if entry.MemorySafetyFactor < 1.0 {
+			return fmt.Errorf("metricsConfig.priorityMemoryHeadroom[%d].memorySafetyFactor must be at least 1.0, got %g",
+				i, entry.MemorySafetyFactor)
+		}
+		if entry.PriorityClassName != "" {
+			if classNames[entry.PriorityClassName] {
+				return fmt.Errorf("metricsConfig.priorityMemoryHeadroom lists priorityClassName %q more than once", entry.PriorityClassName)
+			}
+			classNames[entry.PriorityClassName] = true
+			continue
+		}
+		if minPriorities[*entry.MinPriority] {
+			return fmt.Errorf("metricsConfig.priorityMemoryHeadroom lists minPriority %d more than once", *entry.MinPriority)
+		}
+		minPriorities[*entry.MinPriority] = true
+	}
+	return nil
+}
+
+// validateBlendConfig validates that the short window fits inside the rolling window
+func validateBlendConfig(metricsConfig MetricsConfig) error {
+	blend := metricsConfig.Blend
+	if blend == nil {
+		return nil
+	}
+
+	if blend.ShortWindow.Duration <= 0 {
+		return fmt.Errorf("metricsConfig.blend.shortWindow is required and must be greater than zero")
+	}
+
+	if rollingWindow := metricsConfig.GetRollingWindow(); blend.ShortWindow.Duration >= rollingWindow {
+		return fmt.Errorf("metricsConfig.blend.shortWindow (%s) must be shorter than metricsConfig.rollingWindow (%s)",
+			blend.ShortWindow.Duration, rollingWindow)
+	}
+
+	if blend.ShortPercentile != "" {
+		if _, ok := percentileRank(blend.ShortPercentile); !ok {
+			return fmt.Errorf("invalid metricsConfig.blend.shortPercentile %q, must be one of: P50, P90, P99", blend.ShortPercentile)
+		}
+	}
+
+	if blend.Rule != "" && blend.Rule != BlendRuleMax && blend.Rule != BlendRuleWeighted {
+		return fmt.Errorf("invalid metricsConfig.blend.rule %q, must be one of: Max, Weighted", blend.Rule)
+	}
+
+	if blend.ShortWeight != nil && (*blend.ShortWeight < 0 || *blend.ShortWeight > 1) {
+		return fmt.Errorf("metricsConfig.blend.shortWeight must be between 0 and 1, got %f", *blend.ShortWeight)
+	}
+
+	return nil
+}
+
+// validateResourceStatistics validates the statistics of each resource and that override windows
+// are shorter than the rolling window
+func validateResourceStatistics(metricsConfig MetricsConfig) error {
+	statistics := metricsConfig.ResourceStatistics
+	if statistics == nil {
+		return nil
+	}
+
+	for _, entry := range []struct {
+		name      string
+		statistic *ResourceStatistic
+	}{{"cpu", statistics.CPU}, {"memory", statistics.Memory}} {
+		if entry.statistic == nil {
+			continue
+		}
+		field := "metricsConfig.resourceStatistics." + entry.name
+		if entry.statistic.Statistic != "" {
+			if !isValidStatistic(entry.statistic.Statistic) {
+				return fmt.Errorf("invalid %s.statistic %q, must be one of: P50, P90, P99, Max", field, entry.statistic.Statistic)
+			}
+			// Daily peak sizing replaces the rolling window statistic, so the two would conflict
+			if metricsConfig.DailyPeaks != nil {
+				return fmt.Errorf("%s.statistic cannot be combined with metricsConfig.dailyPeaks", field)
+			}
+		}
+
+		override := entry.statistic.Override
+		if override == nil {
+			continue
+		}
+		if override.Window.Duration <= 0 {
+			return fmt.Errorf("%s.override.window is required and must be greater than zero", field)
+		}
+		if rollingWindow := metricsConfig.GetRollingWindow(); override.Window.Duration >= rollingWindow {
+			return fmt.Errorf("%s.override.window (%s) must be shorter than metricsConfig.rollingWindow (%s)",
+				field, override.Window.Duration, rollingWindow)
+		}
+		if override.Statistic != "" && !isValidStatistic(override.Statistic) {
+			return fmt.Errorf("invalid %s.override.statistic %q, must be one of: P50, P90, P99, Max", field, override.Statistic)
+		}
+	}
+
+	return nil
+}
+
+// isValidStatistic reports whether statistic is a percentile or the maximum
+func isValidStatistic(statistic string) bool {
+	_, ok := percentileRank(statistic)
+	return ok || statistic == StatisticMax
+}
+
+// validateDailyPeaksConfig validates the daily peak sizing and that the provider keeps the samples it needs
+func validateDailyPeaksConfig(metricsConfig MetricsConfig) error {
+	dailyPeaks := metricsConfig.DailyPeaks
+	if dailyPeaks == nil {
+		return nil
+	}
+
+	if dailyPeaks.Days != 0 && (dailyPeaks.Days < 3 || dailyPeaks.Days > 30) {
+		return fmt.Errorf("metricsConfig.dailyPeaks.days must be between 3 and 30, got %d", dailyPeaks.Days)
+	}
+
+	if dailyPeaks.Percentile != "" {
+		if _, ok := percentileRank(dailyPeaks.Percentile); !ok {
+			return fmt.Errorf("invalid metricsConfig.dailyPeaks.percentile %q, must be one of: P50, P90, P99", dailyPeaks.Percentile)
+		}
+	}
+
+	if metricsConfig.Provider == "metrics-server" {
+		return fmt.Errorf("metricsConfig.dailyPeaks requires a provider that keeps samples over days (prometheus or otel-promql)")
+	}
+
+	return nil
+}
+
+// validateStartupFloor validates that startup floors fit within the resource bounds
+func validateStartupFloor(floor *StartupFloor, bounds ResourceBounds) error {
+	if floor == nil {
+		return nil
+	}
+
+	if floor.CPU == nil && floor.Memory == nil {
+		return fmt.Errorf("startupFloor must set at least one of cpu or memory")
+	}
+
+	if floor.CPU != nil && floor.CPU.Cmp(bounds.CPU.Max) > 0 {
+		return fmt.Errorf("startupFloor.cpu (%s) must be less than or equal to resourceBounds.cpu.max (%s)",
+			floor.CPU.String(), bounds.CPU.Max.String())
+	}
+
+	if floor.Memory != nil && floor.Memory.Cmp(bounds.Memory.Max) > 0 {
+		return fmt.Errorf("startupFloor.memory (%s) must be less than or equal to resourceBounds.memory.max (%s)",
+			floor.Memory.String(), bounds.Memory.Max.String())
+	}
+
+	if floor.Period.Duration < 0 {
+		return fmt.Errorf("startupFloor.period must not be negative")
+	}
+
+	if floor.RestartThreshold < 0 {
+		return fmt.Errorf("startupFloor.restartThreshold must not be negative, got %d", floor.RestartThreshold)
+	}
+
+	return nil
+}
+
+// validateCPUMemoryRatio validates the CPU to memory ratio band
+func validateCPUMemoryRatio(ratio *CPUMemoryRatio) error {
+	if ratio == nil {
+		return nil
+	}
+	if ratio.MinMillicoresPerGiB != nil && *ratio.MinMillicoresPerGiB < 0 {
+		return fmt.Errorf("cpuMemoryRatio.minMillicoresPerGiB must not be negative, got %d", *ratio.MinMillicoresPerGiB)
+	}
+	if ratio.MaxMillicoresPerGiB != nil && *ratio.MaxMillicoresPerGiB < 1 {
+		return fmt.Errorf("cpuMemoryRatio.maxMillicoresPerGiB must be at least 1, got %d", *ratio.MaxMillicoresPerGiB)
+	}
+	if minRatio, maxRatio := ratio.Band(); minRatio > maxRatio {
+		return fmt.Errorf("cpuMemoryRatio.minMillicoresPerGiB (%d) must be less than or equal to maxMillicoresPerGiB (%d)",
+			minRatio, maxRatio)
+	}
+	return nil
+}
+
+// percentileRank orders the supported percentiles from lowest to highest
+func percentileRank(percentile string) (int, bool) {
+	switch percentile {
+	case "P50":
+		return 0, true
+	case "P90":
+		return 1, true
+	case "P99":
+		return 2, true
+	default:
+		return 0, false
+	}
+}
+
+// validateContainerSelector validates a single container selector
+func validateContainerSelector(selector ContainerSelector, fieldName string) error {
+	if selector.Name == "" {
+		return fmt.Errorf("%s.name is required", fieldName)
+	}
+
+	switch selector.MatchType {
+	case "", ContainerMatchGlob:
+		if _, err := path.Match(selector.Name, ""); err != nil {
+			return fmt.Errorf("%s.name: invalid glob pattern %q: %w", fieldName, selector.Name, err)
+		}
+	case ContainerMatchRegex:
+		if _, err := selector.compileRegex(); err != nil {
+			return fmt.Errorf("%s.name: invalid regular expression %q: %w", fieldName, selector.Name, err)
+		}
+	default:
+		return fmt.Errorf("%s.matchType: invalid match type %q, must be one of: Glob, Regex", fieldName, selector.MatchType)
+	}
+
+	if selector.Mode != "" && selector.Mode != ModeAuto && selector.Mode != ModeRecommend && selector.Mode != ModeDisabled {
+		return fmt.Errorf("%s.mode: invalid mode %q, must be one of: Auto, Recommend, Disabled", fieldName, selector.Mode)
+	}
+
+	if selector.ResourceBounds != nil {
+		if selector.ResourceBounds.CPU.Min.Cmp(selector.ResourceBounds.CPU.Max) > 0 {
+			return fmt.Errorf("%s.resourceBounds: CPU min (%s) must be less than or equal to max (%s)",
+				fieldName,
+				selector.ResourceBounds.CPU.Min.String(),
+				selector.ResourceBounds.CPU.Max.String())
+		}
+		if selector.ResourceBounds.Memory.Min.Cmp(selector.ResourceBounds.Memory.Max) > 0 {
+			return fmt.Errorf("%s.resourceBounds: memory min (%s) must be less than or equal to max (%s)",
+				fieldName,
+				selector.ResourceBounds.Memory.Min.String(),
+				selector.ResourceBounds.Memory.Max.String())
+		}
+	}
+
 	return nil
 }
 
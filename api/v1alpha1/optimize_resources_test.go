@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOptimizedResources(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name           string
+		optimizeCPU    *bool
+		optimizeMemory *bool
+		wantCPU        bool
+		wantMemory     bool
+		wantErr        bool
+	}{
+		{name: "unset defaults to both", wantCPU: true, wantMemory: true},
+		{name: "both enabled", optimizeCPU: &enabled, optimizeMemory: &enabled, wantCPU: true, wantMemory: true},
+		{name: "CPU only", optimizeMemory: &disabled, wantCPU: true, wantMemory: false},
+		{name: "memory only", optimizeCPU: &disabled, wantCPU: false, wantMemory: true},
+		{name: "neither", optimizeCPU: &disabled, optimizeMemory: &disabled, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &OptimizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: DefaultNamespace},
+				Spec: OptimizationPolicySpec{
+					Mode: ModeAuto,
+					Selector: WorkloadSelector{
+						Namespaces: &NamespaceFilter{Allow: []string{DefaultNamespace}},
+					},
+					MetricsConfig: MetricsConfig{Provider: "prometheus", Percentile: "P90"},
+					ResourceBounds: ResourceBounds{
+						CPU:    ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+						Memory: ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("2Gi")},
+					},
+					OptimizeCPU:    tt.optimizeCPU,
+					OptimizeMemory: tt.optimizeMemory,
+				},
+			}
+
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if policy.OptimizesCPU() != tt.wantCPU || policy.OptimizesMemory() != tt.wantMemory {
+				t.Errorf("OptimizesCPU() = %v, OptimizesMemory() = %v, want %v and %v",
+					policy.OptimizesCPU(), policy.OptimizesMemory(), tt.wantCPU, tt.wantMemory)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPartialMetricsValidation(t *testing.T) {
+	quantity := func(s string) *resource.Quantity {
+		q := resource.MustParse(s)
+		return &q
+	}
+
+	tests := []struct {
+		name    string
+		config  *PartialMetrics
+		wantErr bool
+	}{
+		{name: "unset"},
+		{name: "default action", config: &PartialMetrics{}},
+		{name: "leave as is", config: &PartialMetrics{Action: PartialMetricsLeaveAsIs}},
+		{name: "floor from the bounds", config: &PartialMetrics{Action: PartialMetricsFloor}},
+		{name: "explicit floor", config: &PartialMetrics{Action: PartialMetricsFloor, CPU: quantity("25m"), Memory: quantity("32Mi")}},
+		{name: "unknown action", config: &PartialMetrics{Action: "Guess"}, wantErr: true},
+		{name: "floor without the Floor action", config: &PartialMetrics{Action: PartialMetricsLeaveAsIs, CPU: quantity("25m")}, wantErr: true},
+		{name: "zero floor", config: &PartialMetrics{Action: PartialMetricsFloor, Memory: quantity("0")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &OptimizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: DefaultNamespace},
+				Spec: OptimizationPolicySpec{
+					Mode: ModeAuto,
+					Selector: WorkloadSelector{
+						Namespaces: &NamespaceFilter{Allow: []string{DefaultNamespace}},
+					},
+					MetricsConfig: MetricsConfig{Provider: "prometheus", Percentile: "P90"},
+					ResourceBounds: ResourceBounds{
+						CPU:    ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+						Memory: ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("2Gi")},
+					},
+					PartialMetrics: tt.config,
+				},
+			}
+
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	bounds := ResourceBounds{
+		CPU:    ResourceBound{Min: resource.MustParse("50m")},
+		Memory: ResourceBound{Min: resource.MustParse("64Mi")},
+	}
+	cpu, memory := (&PartialMetrics{Action: PartialMetricsFloor, CPU: quantity("25m")}).Floor(bounds)
+	if cpu.String() != "25m" || memory.String() != "64Mi" {
+		t.Errorf("Floor() = %s, %s, want the explicit CPU floor and the memory bound", cpu.String(), memory.String())
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPatchMethodOverridesValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides []PatchMethodOverride
+		wantErr   bool
+	}{
+		{name: "unset"},
+		{
+			name: "one method per kind",
+			overrides: []PatchMethodOverride{
+				{Kind: WorkloadTypeDaemonSet, Method: PatchMethodStrategicMergePatch},
+				{Kind: WorkloadTypeStatefulSet, Method: PatchMethodServerSideApply},
+			},
+		},
+		{
+			name:      "unknown kind",
+			overrides: []PatchMethodOverride{{Kind: "CronJob", Method: PatchMethodServerSideApply}},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown method",
+			overrides: []PatchMethodOverride{{Kind: WorkloadTypeDaemonSet, Method: "JSONPatch"}},
+			wantErr:   true,
+		},
+		{
+			name: "duplicate kind",
+			overrides: []PatchMethodOverride{
+				{Kind: WorkloadTypeDaemonSet, Method: PatchMethodStrategicMergePatch},
+				{Kind: WorkloadTypeDaemonSet, Method: PatchMethodServerSideApply},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &OptimizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: DefaultNamespace},
+				Spec: OptimizationPolicySpec{
+					Mode: ModeAuto,
+					Selector: WorkloadSelector{
+						Namespaces: &NamespaceFilter{Allow: []string{DefaultNamespace}},
+					},
+					MetricsConfig: MetricsConfig{Provider: "prometheus", Percentile: "P90"},
+					ResourceBounds: ResourceBounds{
+						CPU:    ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+						Memory: ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("2Gi")},
+					},
+					UpdateStrategy: UpdateStrategy{PatchMethodOverrides: tt.overrides},
+				},
+			}
+
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUsesServerSideApply(t *testing.T) {
+	disabled := false
+	strategy := UpdateStrategy{
+		UseServerSideApply: &disabled,
+		PatchMethodOverrides: []PatchMethodOverride{
+			{Kind: WorkloadTypeStatefulSet, Method: PatchMethodServerSideApply},
+		},
+	}
+	if !strategy.UsesServerSideApply(string(WorkloadTypeStatefulSet)) {
+		t.Error("UsesServerSideApply(StatefulSet) = false, want the kind's override")
+	}
+	if strategy.UsesServerSideApply(string(WorkloadTypeDeployment)) {
+		t.Error("UsesServerSideApply(Deployment) = true, want useServerSideApply")
+	}
+	if !(UpdateStrategy{}).UsesServerSideApply(string(WorkloadTypeDeployment)) {
+		t.Error("UsesServerSideApply() = false without configuration, want the default")
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPriorityMemoryHeadroomValidation(t *testing.T) {
+	priority := func(p int32) *int32 { return &p }
+
+	tests := []struct {
+		name     string
+		headroom []PriorityMemoryHeadroom
+		wantErr  bool
+	}{
+		{name: "unset"},
+		{
+			name: "class names and thresholds",
+			headroom: []PriorityMemoryHeadroom{
+				{PriorityClassName: "system-cluster-critical", MemorySafetyFactor: 2.0},
+				{MinPriority: priority(1000), MemorySafetyFactor: 1.5},
+			},
+		},
+		{name: "neither class name nor threshold", headroom: []PriorityMemoryHeadroom{{MemorySafetyFactor: 1.5}}, wantErr: true},
+		{
+			name:     "both class name and threshold",
+			headroom: []PriorityMemoryHeadroom{{PriorityClassName: "critical", MinPriority: priority(1000), MemorySafetyFactor: 1.5}},
+			wantErr:  true,
+		},
+		{name: "safety factor below 1", headroom: []PriorityMemoryHeadroom{{PriorityClassName: "critical", MemorySafetyFactor: 0.8}}, wantErr: true},
+		{
+			name: "duplicate class name",
+			headroom: []PriorityMemoryHeadroom{
+				{PriorityClassName: "critical", MemorySafetyFactor: 1.5},
+				{PriorityClassName: "critical", MemorySafetyFactor: 2.0},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate threshold",
+			headroom: []PriorityMemoryHeadroom{
+				{MinPriority: priority(1000), MemorySafetyFactor: 1.5},
+				{MinPriority: priority(1000), MemorySafetyFactor: 2.0},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &OptimizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: DefaultNamespace},
+				Spec: OptimizationPolicySpec{
+					Mode: ModeAuto,
+					Selector: WorkloadSelector{
+						Namespaces: &NamespaceFilter{Allow: []string{DefaultNamespace}},
+					},
+					MetricsConfig: MetricsConfig{
+						Provider:               "prometheus",
+						Percentile:             "P90",
+						PriorityMemoryHeadroom: tt.headroom,
+					},
+					ResourceBounds: ResourceBounds{
+						CPU:    ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+						Memory: ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("2Gi")},
+					},
+				},
+			}
+
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRemovedContainersValidation(t *testing.T) {
+	tests := []struct {
+		handling string
+		want     string
+		wantErr  bool
+	}{
+		{handling: "", want: RemovedContainersCleanup},
+		{handling: RemovedContainersCleanup, want: RemovedContainersCleanup},
+		{handling: RemovedContainersRetain, want: RemovedContainersRetain},
+		{handling: "Delete", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.handling, func(t *testing.T) {
+			policy := &OptimizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: DefaultNamespace},
+				Spec: OptimizationPolicySpec{
+					Mode: ModeAuto,
+					Selector: WorkloadSelector{
+						Namespaces: &NamespaceFilter{Allow: []string{DefaultNamespace}},
+					},
+					MetricsConfig: MetricsConfig{
+						Provider:   "prometheus",
+						Percentile: "P90",
+					},
+					ResourceBounds: ResourceBounds{
+						CPU:    ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+						Memory: ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("2Gi")},
+					},
+					RemovedContainers: tt.handling,
+				},
+			}
+
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && policy.GetRemovedContainers() != tt.want {
+				t.Errorf("GetRemovedContainers() = %q, want %q", policy.GetRemovedContainers(), tt.want)
+			}
+		})
+	}
+}
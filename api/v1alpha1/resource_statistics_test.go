@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResourceStatisticsValidation(t *testing.T) {
+	override := func(window time.Duration, statistic string) *ReactiveOverride {
+		return &ReactiveOverride{Window: metav1.Duration{Duration: window}, Statistic: statistic}
+	}
+
+	tests := []struct {
+		name       string
+		statistics *ResourceStatistics
+		dailyPeaks *DailyPeaksConfig
+		wantErr    bool
+	}{
+		{name: "unset"},
+		{
+			name: "weekly max memory with a reactive CPU override",
+			statistics: &ResourceStatistics{
+				CPU:    &ResourceStatistic{Override: override(15*time.Minute, "")},
+				Memory: &ResourceStatistic{Statistic: StatisticMax},
+			},
+		},
+		{name: "invalid statistic", statistics: &ResourceStatistics{CPU: &ResourceStatistic{Statistic: "P75"}}, wantErr: true},
+		{
+			name:       "invalid override statistic",
+			statistics: &ResourceStatistics{Memory: &ResourceStatistic{Override: override(time.Hour, "Min")}},
+			wantErr:    true,
+		},
+		{
+			name:       "override without a window",
+			statistics: &ResourceStatistics{CPU: &ResourceStatistic{Override: override(0, "")}},
+			wantErr:    true,
+		},
+		{
+			name:       "override window not shorter than the rolling window",
+			statistics: &ResourceStatistics{CPU: &ResourceStatistic{Override: override(7*24*time.Hour, "")}},
+			wantErr:    true,
+		},
+		{
+			name:       "statistic with daily peaks",
+			statistics: &ResourceStatistics{Memory: &ResourceStatistic{Statistic: StatisticMax}},
+			dailyPeaks: &DailyPeaksConfig{},
+			wantErr:    true,
+		},
+		{
+			name:       "override with daily peaks",
+			statistics: &ResourceStatistics{CPU: &ResourceStatistic{Override: override(15*time.Minute, "")}},
+			dailyPeaks: &DailyPeaksConfig{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &OptimizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: DefaultNamespace},
+				Spec: OptimizationPolicySpec{
+					Mode: ModeAuto,
+					Selector: WorkloadSelector{
+						Namespaces: &NamespaceFilter{Allow: []string{DefaultNamespace}},
+					},
+					MetricsConfig: MetricsConfig{
+						Provider:           "prometheus",
+						RollingWindow:      metav1.Duration{Duration: 7 * 24 * time.Hour},
+						Percentile:         "P90",
+						DailyPeaks:         tt.dailyPeaks,
+						ResourceStatistics: tt.statistics,
+					},
+					ResourceBounds: ResourceBounds{
+						CPU:    ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+						Memory: ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("2Gi")},
+					},
+				},
+			}
+
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"math"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSafetyRampValidation(t *testing.T) {
+	count := func(n int32) *int32 { return &n }
+
+	tests := []struct {
+		name         string
+		safetyFactor *float64
+		ramp         *SafetyRamp
+		wantErr      bool
+	}{
+		{name: "unset"},
+		{name: "above the default safety factor", ramp: &SafetyRamp{InitialSafetyFactor: 1.5}},
+		{name: "equal to the safety factor", safetyFactor: float64Ptr(1.5), ramp: &SafetyRamp{InitialSafetyFactor: 1.5}},
+		{name: "below the safety factor", safetyFactor: float64Ptr(1.5), ramp: &SafetyRamp{InitialSafetyFactor: 1.3}, wantErr: true},
+		{name: "below the default safety factor", ramp: &SafetyRamp{InitialSafetyFactor: 1.1}, wantErr: true},
+		{name: "zero applies", ramp: &SafetyRamp{InitialSafetyFactor: 1.5, Applies: count(0)}, wantErr: true},
+		{name: "too many applies", ramp: &SafetyRamp{InitialSafetyFactor: 1.5, Applies: count(21)}, wantErr: true},
+		{name: "stability score above 100", ramp: &SafetyRamp{InitialSafetyFactor: 1.5, MinStabilityScore: count(101)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &OptimizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: DefaultNamespace},
+				Spec: OptimizationPolicySpec{
+					Mode: ModeAuto,
+					Selector: WorkloadSelector{
+						Namespaces: &NamespaceFilter{Allow: []string{DefaultNamespace}},
+					},
+					MetricsConfig: MetricsConfig{
+						Provider:     "prometheus",
+						Percentile:   "P90",
+						SafetyFactor: tt.safetyFactor,
+						SafetyRamp:   tt.ramp,
+					},
+					ResourceBounds: ResourceBounds{
+						CPU:    ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+						Memory: ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("2Gi")},
+					},
+				},
+			}
+
+			if err := policy.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSafetyRamp_SafetyFactor(t *testing.T) {
+	ramp := &SafetyRamp{InitialSafetyFactor: 1.8}
+
+	// The default three stable applies lower the safety factor from 1.8 to 1.2 in equal steps
+	want := []struct {
+		safetyFactor float64
+		phase        string
+	}{
+		{1.8, SafetyRampPhaseInitial},
+		{1.6, SafetyRampPhaseTightening},
+		{1.4, SafetyRampPhaseTightening},
+		{1.2, SafetyRampPhaseTarget},
+		{1.2, SafetyRampPhaseTarget},
+	}
+	for applies, w := range want {
+		if got := ramp.SafetyFactor(1.2, int32(applies)); math.Abs(got-w.safetyFactor) > 1e-9 {
+			t.Errorf("SafetyFactor(1.2, %d) = %v, want %v", applies, got, w.safetyFactor)
+		}
+		if got := ramp.Phase(int32(applies)); got != w.phase {
+			t.Errorf("Phase(%d) = %q, want %q", applies, got, w.phase)
+		}
+	}
+}
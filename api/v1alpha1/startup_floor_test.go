@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateStartupFloor(t *testing.T) {
+	cpu := resource.MustParse("1")
+	tooMuchCPU := resource.MustParse("8")
+	memory := resource.MustParse("1Gi")
+
+	tests := []struct {
+		name    string
+		floor   *StartupFloor
+		wantErr bool
+	}{
+		{name: "no startup floor", floor: nil, wantErr: false},
+		{name: "CPU and memory floors", floor: &StartupFloor{CPU: &cpu, Memory: &memory}, wantErr: false},
+		{name: "neither floor set", floor: &StartupFloor{RestartThreshold: 3}, wantErr: true},
+		{name: "CPU floor above max bound", floor: &StartupFloor{CPU: &tooMuchCPU}, wantErr: true},
+		{name: "negative restart threshold", floor: &StartupFloor{CPU: &cpu, RestartThreshold: -1}, wantErr: true},
+		{
+			name:    "negative period",
+			floor:   &StartupFloor{CPU: &cpu, Period: metav1.Duration{Duration: -time.Minute}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newContainerSelectorTestPolicy(ModeAuto)
+			policy.Spec.StartupFloor = tt.floor
+			err := policy.ValidateCreate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetStartupFloorPeriod(t *testing.T) {
+	cpu := resource.MustParse("1")
+	policy := newContainerSelectorTestPolicy(ModeAuto)
+
+	if got := policy.GetStartupFloorPeriod(); got != 0 {
+		t.Errorf("GetStartupFloorPeriod() without floor = %v, want 0", got)
+	}
+
+	policy.Spec.StartupFloor = &StartupFloor{CPU: &cpu}
+	if got := policy.GetStartupFloorPeriod(); got != time.Hour {
+		t.Errorf("GetStartupFloorPeriod() default = %v, want 1h", got)
+	}
+
+	policy.Spec.StartupFloor.Period = metav1.Duration{Duration: 30 * time.Minute}
+	if got := policy.GetStartupFloorPeriod(); got != 30*time.Minute {
+		t.Errorf("GetStartupFloorPeriod() = %v, want 30m", got)
+	}
+}
@@ -21,10 +21,158 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdaptiveInterval) DeepCopyInto(out *AdaptiveInterval) {
+	*out = *in
+	if in.MinInterval != nil {
+		in, out := &in.MinInterval, &out.MinInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	out.MaxInterval = in.MaxInterval
+	if in.StableScore != nil {
+		in, out := &in.StableScore, &out.StableScore
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdaptiveInterval.
+func (in *AdaptiveInterval) DeepCopy() *AdaptiveInterval {
+	if in == nil {
+		return nil
+	}
+	out := new(AdaptiveInterval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplyTolerance) DeepCopyInto(out *ApplyTolerance) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(ResourceTolerance)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(ResourceTolerance)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplyTolerance.
+func (in *ApplyTolerance) DeepCopy() *ApplyTolerance {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplyTolerance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlendConfig) DeepCopyInto(out *BlendConfig) {
+	*out = *in
+	out.ShortWindow = in.ShortWindow
+	if in.ShortWeight != nil {
+		in, out := &in.ShortWeight, &out.ShortWeight
+		*out = new(float64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlendConfig.
+func (in *BlendConfig) DeepCopy() *BlendConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BlendConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BurstRatioLimits) DeepCopyInto(out *BurstRatioLimits) {
+	*out = *in
+	if in.MinRatio != nil {
+		in, out := &in.MinRatio, &out.MinRatio
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxRatio != nil {
+		in, out := &in.MaxRatio, &out.MaxRatio
+		*out = new(float64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BurstRatioLimits.
+func (in *BurstRatioLimits) DeepCopy() *BurstRatioLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(BurstRatioLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUMemoryRatio) DeepCopyInto(out *CPUMemoryRatio) {
+	*out = *in
+	if in.MinMillicoresPerGiB != nil {
+		in, out := &in.MinMillicoresPerGiB, &out.MinMillicoresPerGiB
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxMillicoresPerGiB != nil {
+		in, out := &in.MaxMillicoresPerGiB, &out.MaxMillicoresPerGiB
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUMemoryRatio.
+func (in *CPUMemoryRatio) DeepCopy() *CPUMemoryRatio {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUMemoryRatio)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheHeadroom) DeepCopyInto(out *CacheHeadroom) {
+	*out = *in
+	if in.Percent != nil {
+		in, out := &in.Percent, &out.Percent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Amount != nil {
+		in, out := &in.Amount, &out.Amount
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheHeadroom.
+func (in *CacheHeadroom) DeepCopy() *CacheHeadroom {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheHeadroom)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerRecommendation) DeepCopyInto(out *ContainerRecommendation) {
 	*out = *in
@@ -33,239 +181,1086 @@ func (in *ContainerRecommendation) DeepCopyInto(out *ContainerRecommendation) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
-	if in.Memory != nil {
-		in, out := &in.Memory, &out.Memory
-		x := (*in).DeepCopy()
-		*out = &x
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.AppliedCPU != nil {
+		in, out := &in.AppliedCPU, &out.AppliedCPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.AppliedMemory != nil {
+		in, out := &in.AppliedMemory, &out.AppliedMemory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.ClampedToBound != nil {
+		in, out := &in.ClampedToBound, &out.ClampedToBound
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataQuality != nil {
+		in, out := &in.DataQuality, &out.DataQuality
+		*out = new(RecommendationDataQuality)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Accuracy != nil {
+		in, out := &in.Accuracy, &out.Accuracy
+		*out = new(RecommendationAccuracy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRecommendation.
+func (in *ContainerRecommendation) DeepCopy() *ContainerRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerSelector) DeepCopyInto(out *ContainerSelector) {
+	*out = *in
+	if in.ResourceBounds != nil {
+		in, out := &in.ResourceBounds, &out.ResourceBounds
+		*out = new(ResourceBounds)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerSelector.
+func (in *ContainerSelector) DeepCopy() *ContainerSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerWithoutMetrics) DeepCopyInto(out *ContainerWithoutMetrics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerWithoutMetrics.
+func (in *ContainerWithoutMetrics) DeepCopy() *ContainerWithoutMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerWithoutMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DailyPeaksConfig) DeepCopyInto(out *DailyPeaksConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DailyPeaksConfig.
+func (in *DailyPeaksConfig) DeepCopy() *DailyPeaksConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DailyPeaksConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailingWorkload) DeepCopyInto(out *FailingWorkload) {
+	*out = *in
+	if in.QuarantinedUntil != nil {
+		in, out := &in.QuarantinedUntil, &out.QuarantinedUntil
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailingWorkload.
+func (in *FailingWorkload) DeepCopy() *FailingWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(FailingWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InPlaceBounds) DeepCopyInto(out *InPlaceBounds) {
+	*out = *in
+	if in.MaxCPUChangePercent != nil {
+		in, out := &in.MaxCPUChangePercent, &out.MaxCPUChangePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxMemoryChangePercent != nil {
+		in, out := &in.MaxMemoryChangePercent, &out.MaxMemoryChangePercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InPlaceBounds.
+func (in *InPlaceBounds) DeepCopy() *InPlaceBounds {
+	if in == nil {
+		return nil
+	}
+	out := new(InPlaceBounds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InformationalMetric) DeepCopyInto(out *InformationalMetric) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InformationalMetric.
+func (in *InformationalMetric) DeepCopy() *InformationalMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(InformationalMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InformationalQuery) DeepCopyInto(out *InformationalQuery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InformationalQuery.
+func (in *InformationalQuery) DeepCopy() *InformationalQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(InformationalQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LimitConfig) DeepCopyInto(out *LimitConfig) {
+	*out = *in
+	if in.CPULimitMultiplier != nil {
+		in, out := &in.CPULimitMultiplier, &out.CPULimitMultiplier
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MemoryLimitMultiplier != nil {
+		in, out := &in.MemoryLimitMultiplier, &out.MemoryLimitMultiplier
+		*out = new(float64)
+		**out = **in
+	}
+	if in.BurstRatioLimits != nil {
+		in, out := &in.BurstRatioLimits, &out.BurstRatioLimits
+		*out = new(BurstRatioLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManageCPULimit != nil {
+		in, out := &in.ManageCPULimit, &out.ManageCPULimit
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManageMemoryLimit != nil {
+		in, out := &in.ManageMemoryLimit, &out.ManageMemoryLimit
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LimitConfig.
+func (in *LimitConfig) DeepCopy() *LimitConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LimitConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryPeakFloor) DeepCopyInto(out *MemoryPeakFloor) {
+	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryPeakFloor.
+func (in *MemoryPeakFloor) DeepCopy() *MemoryPeakFloor {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryPeakFloor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsConfig) DeepCopyInto(out *MetricsConfig) {
+	*out = *in
+	out.RollingWindow = in.RollingWindow
+	if in.SafetyFactor != nil {
+		in, out := &in.SafetyFactor, &out.SafetyFactor
+		*out = new(float64)
+		**out = **in
+	}
+	if in.SafetyRamp != nil {
+		in, out := &in.SafetyRamp, &out.SafetyRamp
+		*out = new(SafetyRamp)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Blend != nil {
+		in, out := &in.Blend, &out.Blend
+		*out = new(BlendConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DailyPeaks != nil {
+		in, out := &in.DailyPeaks, &out.DailyPeaks
+		*out = new(DailyPeaksConfig)
+		**out = **in
+	}
+	if in.ResourceStatistics != nil {
+		in, out := &in.ResourceStatistics, &out.ResourceStatistics
+		*out = new(ResourceStatistics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InformationalQueries != nil {
+		in, out := &in.InformationalQueries, &out.InformationalQueries
+		*out = make([]InformationalQuery, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxMetricsAge != nil {
+		in, out := &in.MaxMetricsAge, &out.MaxMetricsAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PriorityMemoryHeadroom != nil {
+		in, out := &in.PriorityMemoryHeadroom, &out.PriorityMemoryHeadroom
+		*out = make([]PriorityMemoryHeadroom, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MemoryPeakFloor != nil {
+		in, out := &in.MemoryPeakFloor, &out.MemoryPeakFloor
+		*out = new(MemoryPeakFloor)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsConfig.
+func (in *MetricsConfig) DeepCopy() *MetricsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceDefaults) DeepCopyInto(out *NamespaceDefaults) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceDefaults.
+func (in *NamespaceDefaults) DeepCopy() *NamespaceDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceDefaults) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceDefaultsList) DeepCopyInto(out *NamespaceDefaultsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespaceDefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceDefaultsList.
+func (in *NamespaceDefaultsList) DeepCopy() *NamespaceDefaultsList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceDefaultsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceDefaultsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceDefaultsSpec) DeepCopyInto(out *NamespaceDefaultsSpec) {
+	*out = *in
+	in.ResourceBounds.DeepCopyInto(&out.ResourceBounds)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceDefaultsSpec.
+func (in *NamespaceDefaultsSpec) DeepCopy() *NamespaceDefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceDefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceFilter) DeepCopyInto(out *NamespaceFilter) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceFilter.
+func (in *NamespaceFilter) DeepCopy() *NamespaceFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceResourceBound) DeepCopyInto(out *NamespaceResourceBound) {
+	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceResourceBound.
+func (in *NamespaceResourceBound) DeepCopy() *NamespaceResourceBound {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceResourceBound)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceResourceBounds) DeepCopyInto(out *NamespaceResourceBounds) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(NamespaceResourceBound)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(NamespaceResourceBound)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceResourceBounds.
+func (in *NamespaceResourceBounds) DeepCopy() *NamespaceResourceBounds {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceResourceBounds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCapacityCap) DeepCopyInto(out *NodeCapacityCap) {
+	*out = *in
+	if in.HeadroomPercent != nil {
+		in, out := &in.HeadroomPercent, &out.HeadroomPercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCapacityCap.
+func (in *NodeCapacityCap) DeepCopy() *NodeCapacityCap {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCapacityCap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnDeleteRolloutStatus) DeepCopyInto(out *OnDeleteRolloutStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnDeleteRolloutStatus.
+func (in *OnDeleteRolloutStatus) DeepCopy() *OnDeleteRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OnDeleteRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptimizationPolicy) DeepCopyInto(out *OptimizationPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationPolicy.
+func (in *OptimizationPolicy) DeepCopy() *OptimizationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(OptimizationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OptimizationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptimizationPolicyList) DeepCopyInto(out *OptimizationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OptimizationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationPolicyList.
+func (in *OptimizationPolicyList) DeepCopy() *OptimizationPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(OptimizationPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OptimizationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptimizationPolicySpec) DeepCopyInto(out *OptimizationPolicySpec) {
+	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+	in.Selector.DeepCopyInto(&out.Selector)
+	in.MetricsConfig.DeepCopyInto(&out.MetricsConfig)
+	in.ResourceBounds.DeepCopyInto(&out.ResourceBounds)
+	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
+	if in.OptimizeCPU != nil {
+		in, out := &in.OptimizeCPU, &out.OptimizeCPU
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OptimizeMemory != nil {
+		in, out := &in.OptimizeMemory, &out.OptimizeMemory
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ContainerSelectors != nil {
+		in, out := &in.ContainerSelectors, &out.ContainerSelectors
+		*out = make([]ContainerSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExcludeContainers != nil {
+		in, out := &in.ExcludeContainers, &out.ExcludeContainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.ReconciliationInterval = in.ReconciliationInterval
+	if in.AdaptiveInterval != nil {
+		in, out := &in.AdaptiveInterval, &out.AdaptiveInterval
+		*out = new(AdaptiveInterval)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxWorkloads != nil {
+		in, out := &in.MaxWorkloads, &out.MaxWorkloads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StartupFloor != nil {
+		in, out := &in.StartupFloor, &out.StartupFloor
+		*out = new(StartupFloor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaScaling != nil {
+		in, out := &in.ReplicaScaling, &out.ReplicaScaling
+		*out = new(ReplicaScaling)
+		**out = **in
+	}
+	if in.CPUMemoryRatio != nil {
+		in, out := &in.CPUMemoryRatio, &out.CPUMemoryRatio
+		*out = new(CPUMemoryRatio)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MinStabilityScore != nil {
+		in, out := &in.MinStabilityScore, &out.MinStabilityScore
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeCapacityCap != nil {
+		in, out := &in.NodeCapacityCap, &out.NodeCapacityCap
+		*out = new(NodeCapacityCap)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RecommendationHysteresis != nil {
+		in, out := &in.RecommendationHysteresis, &out.RecommendationHysteresis
+		*out = new(RecommendationHysteresis)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CacheHeadroom != nil {
+		in, out := &in.CacheHeadroom, &out.CacheHeadroom
+		*out = new(CacheHeadroom)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PartialMetrics != nil {
+		in, out := &in.PartialMetrics, &out.PartialMetrics
+		*out = new(PartialMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationPolicySpec.
+func (in *OptimizationPolicySpec) DeepCopy() *OptimizationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OptimizationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptimizationPolicyStatus) DeepCopyInto(out *OptimizationPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastReconciliation != nil {
+		in, out := &in.LastReconciliation, &out.LastReconciliation
+		*out = (*in).DeepCopy()
+	}
+	if in.NextReconciliation != nil {
+		in, out := &in.NextReconciliation, &out.NextReconciliation
+		*out = (*in).DeepCopy()
+	}
+	if in.AdaptiveInterval != nil {
+		in, out := &in.AdaptiveInterval, &out.AdaptiveInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.WorkloadsByType != nil {
+		in, out := &in.WorkloadsByType, &out.WorkloadsByType
+		*out = new(WorkloadTypeStatus)
+		**out = **in
+	}
+	if in.Checkpoint != nil {
+		in, out := &in.Checkpoint, &out.Checkpoint
+		*out = new(ReconcileCheckpoint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailingWorkloads != nil {
+		in, out := &in.FailingWorkloads, &out.FailingWorkloads
+		*out = make([]FailingWorkload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationPolicyStatus.
+func (in *OptimizationPolicyStatus) DeepCopy() *OptimizationPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OptimizationPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PartialMetrics) DeepCopyInto(out *PartialMetrics) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PartialMetrics.
+func (in *PartialMetrics) DeepCopy() *PartialMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(PartialMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PartitionedRolloutStatus) DeepCopyInto(out *PartitionedRolloutStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PartitionedRolloutStatus.
+func (in *PartitionedRolloutStatus) DeepCopy() *PartitionedRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PartitionedRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchMethodOverride) DeepCopyInto(out *PatchMethodOverride) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchMethodOverride.
+func (in *PatchMethodOverride) DeepCopy() *PatchMethodOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchMethodOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodLevelResources) DeepCopyInto(out *PodLevelResources) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodLevelResources.
+func (in *PodLevelResources) DeepCopy() *PodLevelResources {
+	if in == nil {
+		return nil
+	}
+	out := new(PodLevelResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriorityMemoryHeadroom) DeepCopyInto(out *PriorityMemoryHeadroom) {
+	*out = *in
+	if in.MinPriority != nil {
+		in, out := &in.MinPriority, &out.MinPriority
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityMemoryHeadroom.
+func (in *PriorityMemoryHeadroom) DeepCopy() *PriorityMemoryHeadroom {
+	if in == nil {
+		return nil
 	}
+	out := new(PriorityMemoryHeadroom)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRecommendation.
-func (in *ContainerRecommendation) DeepCopy() *ContainerRecommendation {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReactiveOverride) DeepCopyInto(out *ReactiveOverride) {
+	*out = *in
+	out.Window = in.Window
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReactiveOverride.
+func (in *ReactiveOverride) DeepCopy() *ReactiveOverride {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerRecommendation)
+	out := new(ReactiveOverride)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LimitConfig) DeepCopyInto(out *LimitConfig) {
+func (in *RecommendationAccuracy) DeepCopyInto(out *RecommendationAccuracy) {
 	*out = *in
-	if in.CPULimitMultiplier != nil {
-		in, out := &in.CPULimitMultiplier, &out.CPULimitMultiplier
-		*out = new(float64)
+	in.AppliedAt.DeepCopyInto(&out.AppliedAt)
+	if in.CPUUtilizationPercent != nil {
+		in, out := &in.CPUUtilizationPercent, &out.CPUUtilizationPercent
+		*out = new(int32)
 		**out = **in
 	}
-	if in.MemoryLimitMultiplier != nil {
-		in, out := &in.MemoryLimitMultiplier, &out.MemoryLimitMultiplier
-		*out = new(float64)
+	if in.MemoryUtilizationPercent != nil {
+		in, out := &in.MemoryUtilizationPercent, &out.MemoryUtilizationPercent
+		*out = new(int32)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LimitConfig.
-func (in *LimitConfig) DeepCopy() *LimitConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecommendationAccuracy.
+func (in *RecommendationAccuracy) DeepCopy() *RecommendationAccuracy {
 	if in == nil {
 		return nil
 	}
-	out := new(LimitConfig)
+	out := new(RecommendationAccuracy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetricsConfig) DeepCopyInto(out *MetricsConfig) {
+func (in *RecommendationDataQuality) DeepCopyInto(out *RecommendationDataQuality) {
 	*out = *in
-	out.RollingWindow = in.RollingWindow
-	if in.SafetyFactor != nil {
-		in, out := &in.SafetyFactor, &out.SafetyFactor
-		*out = new(float64)
-		**out = **in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(ResourceDataQuality)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(ResourceDataQuality)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsConfig.
-func (in *MetricsConfig) DeepCopy() *MetricsConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecommendationDataQuality.
+func (in *RecommendationDataQuality) DeepCopy() *RecommendationDataQuality {
 	if in == nil {
 		return nil
 	}
-	out := new(MetricsConfig)
+	out := new(RecommendationDataQuality)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespaceFilter) DeepCopyInto(out *NamespaceFilter) {
+func (in *RecommendationHysteresis) DeepCopyInto(out *RecommendationHysteresis) {
 	*out = *in
-	if in.Allow != nil {
-		in, out := &in.Allow, &out.Allow
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.CPUPercent != nil {
+		in, out := &in.CPUPercent, &out.CPUPercent
+		*out = new(int32)
+		**out = **in
 	}
-	if in.Deny != nil {
-		in, out := &in.Deny, &out.Deny
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.MemoryPercent != nil {
+		in, out := &in.MemoryPercent, &out.MemoryPercent
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceFilter.
-func (in *NamespaceFilter) DeepCopy() *NamespaceFilter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecommendationHysteresis.
+func (in *RecommendationHysteresis) DeepCopy() *RecommendationHysteresis {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespaceFilter)
+	out := new(RecommendationHysteresis)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OptimizationPolicy) DeepCopyInto(out *OptimizationPolicy) {
+func (in *ReconcileCheckpoint) DeepCopyInto(out *ReconcileCheckpoint) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationPolicy.
-func (in *OptimizationPolicy) DeepCopy() *OptimizationPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileCheckpoint.
+func (in *ReconcileCheckpoint) DeepCopy() *ReconcileCheckpoint {
 	if in == nil {
 		return nil
 	}
-	out := new(OptimizationPolicy)
+	out := new(ReconcileCheckpoint)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OptimizationPolicy) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaScaling) DeepCopyInto(out *ReplicaScaling) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaScaling.
+func (in *ReplicaScaling) DeepCopy() *ReplicaScaling {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(ReplicaScaling)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OptimizationPolicyList) DeepCopyInto(out *OptimizationPolicyList) {
+func (in *ResourceBound) DeepCopyInto(out *ResourceBound) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]OptimizationPolicy, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out.Min = in.Min.DeepCopy()
+	out.Max = in.Max.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBound.
+func (in *ResourceBound) DeepCopy() *ResourceBound {
+	if in == nil {
+		return nil
 	}
+	out := new(ResourceBound)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationPolicyList.
-func (in *OptimizationPolicyList) DeepCopy() *OptimizationPolicyList {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceBounds) DeepCopyInto(out *ResourceBounds) {
+	*out = *in
+	in.CPU.DeepCopyInto(&out.CPU)
+	in.Memory.DeepCopyInto(&out.Memory)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBounds.
+func (in *ResourceBounds) DeepCopy() *ResourceBounds {
 	if in == nil {
 		return nil
 	}
-	out := new(OptimizationPolicyList)
+	out := new(ResourceBounds)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OptimizationPolicyList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceDataQuality) DeepCopyInto(out *ResourceDataQuality) {
+	*out = *in
+	if in.PercentileValue != nil {
+		in, out := &in.PercentileValue, &out.PercentileValue
+		x := (*in).DeepCopy()
+		*out = &x
 	}
-	return nil
+	if in.Base != nil {
+		in, out := &in.Base, &out.Base
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Computed != nil {
+		in, out := &in.Computed, &out.Computed
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Final != nil {
+		in, out := &in.Final, &out.Final
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDataQuality.
+func (in *ResourceDataQuality) DeepCopy() *ResourceDataQuality {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceDataQuality)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OptimizationPolicySpec) DeepCopyInto(out *OptimizationPolicySpec) {
+func (in *ResourceStatistic) DeepCopyInto(out *ResourceStatistic) {
 	*out = *in
-	if in.Weight != nil {
-		in, out := &in.Weight, &out.Weight
-		*out = new(int32)
+	if in.Override != nil {
+		in, out := &in.Override, &out.Override
+		*out = new(ReactiveOverride)
 		**out = **in
 	}
-	in.Selector.DeepCopyInto(&out.Selector)
-	in.MetricsConfig.DeepCopyInto(&out.MetricsConfig)
-	in.ResourceBounds.DeepCopyInto(&out.ResourceBounds)
-	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
-	out.ReconciliationInterval = in.ReconciliationInterval
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationPolicySpec.
-func (in *OptimizationPolicySpec) DeepCopy() *OptimizationPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceStatistic.
+func (in *ResourceStatistic) DeepCopy() *ResourceStatistic {
 	if in == nil {
 		return nil
 	}
-	out := new(OptimizationPolicySpec)
+	out := new(ResourceStatistic)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OptimizationPolicyStatus) DeepCopyInto(out *OptimizationPolicyStatus) {
+func (in *ResourceStatistics) DeepCopyInto(out *ResourceStatistics) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(ResourceStatistic)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.LastReconciliation != nil {
-		in, out := &in.LastReconciliation, &out.LastReconciliation
-		*out = (*in).DeepCopy()
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(ResourceStatistic)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.WorkloadsByType != nil {
-		in, out := &in.WorkloadsByType, &out.WorkloadsByType
-		*out = new(WorkloadTypeStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceStatistics.
+func (in *ResourceStatistics) DeepCopy() *ResourceStatistics {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceStatistics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceTolerance) DeepCopyInto(out *ResourceTolerance) {
+	*out = *in
+	if in.Absolute != nil {
+		in, out := &in.Absolute, &out.Absolute
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Percent != nil {
+		in, out := &in.Percent, &out.Percent
+		*out = new(int32)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationPolicyStatus.
-func (in *OptimizationPolicyStatus) DeepCopy() *OptimizationPolicyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceTolerance.
+func (in *ResourceTolerance) DeepCopy() *ResourceTolerance {
 	if in == nil {
 		return nil
 	}
-	out := new(OptimizationPolicyStatus)
+	out := new(ResourceTolerance)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceBound) DeepCopyInto(out *ResourceBound) {
+func (in *SafetyRamp) DeepCopyInto(out *SafetyRamp) {
 	*out = *in
-	out.Min = in.Min.DeepCopy()
-	out.Max = in.Max.DeepCopy()
+	if in.Applies != nil {
+		in, out := &in.Applies, &out.Applies
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinStabilityScore != nil {
+		in, out := &in.MinStabilityScore, &out.MinStabilityScore
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBound.
-func (in *ResourceBound) DeepCopy() *ResourceBound {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SafetyRamp.
+func (in *SafetyRamp) DeepCopy() *SafetyRamp {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceBound)
+	out := new(SafetyRamp)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceBounds) DeepCopyInto(out *ResourceBounds) {
+func (in *StartupFloor) DeepCopyInto(out *StartupFloor) {
 	*out = *in
-	in.CPU.DeepCopyInto(&out.CPU)
-	in.Memory.DeepCopyInto(&out.Memory)
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	out.Period = in.Period
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBounds.
-func (in *ResourceBounds) DeepCopy() *ResourceBounds {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupFloor.
+func (in *StartupFloor) DeepCopy() *StartupFloor {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceBounds)
+	out := new(StartupFloor)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -278,6 +1273,46 @@ func (in *UpdateStrategy) DeepCopyInto(out *UpdateStrategy) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.PatchMethodOverrides != nil {
+		in, out := &in.PatchMethodOverrides, &out.PatchMethodOverrides
+		*out = make([]PatchMethodOverride, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConvergenceRate != nil {
+		in, out := &in.ConvergenceRate, &out.ConvergenceRate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.PartitionStep != nil {
+		in, out := &in.PartitionStep, &out.PartitionStep
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StableDecreaseObservations != nil {
+		in, out := &in.StableDecreaseObservations, &out.StableDecreaseObservations
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InPlaceBounds != nil {
+		in, out := &in.InPlaceBounds, &out.InPlaceBounds
+		*out = new(InPlaceBounds)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodLevelResources != nil {
+		in, out := &in.PodLevelResources, &out.PodLevelResources
+		*out = new(PodLevelResources)
+		**out = **in
+	}
+	if in.MinRecreateInterval != nil {
+		in, out := &in.MinRecreateInterval, &out.MinRecreateInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ApplyTolerance != nil {
+		in, out := &in.ApplyTolerance, &out.ApplyTolerance
+		*out = new(ApplyTolerance)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.LimitConfig != nil {
 		in, out := &in.LimitConfig, &out.LimitConfig
 		*out = new(LimitConfig)
@@ -348,6 +1383,46 @@ func (in *WorkloadStatus) DeepCopyInto(out *WorkloadStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PodResources != nil {
+		in, out := &in.PodResources, &out.PodResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExcludedContainers != nil {
+		in, out := &in.ExcludedContainers, &out.ExcludedContainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedContainers != nil {
+		in, out := &in.RemovedContainers, &out.RemovedContainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InformationalMetrics != nil {
+		in, out := &in.InformationalMetrics, &out.InformationalMetrics
+		*out = make([]InformationalMetric, len(*in))
+		copy(*out, *in)
+	}
+	if in.StabilityScore != nil {
+		in, out := &in.StabilityScore, &out.StabilityScore
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PartitionedRollout != nil {
+		in, out := &in.PartitionedRollout, &out.PartitionedRollout
+		*out = new(PartitionedRolloutStatus)
+		**out = **in
+	}
+	if in.OnDeleteRollout != nil {
+		in, out := &in.OnDeleteRollout, &out.OnDeleteRollout
+		*out = new(OnDeleteRolloutStatus)
+		**out = **in
+	}
+	if in.ContainersWithoutMetrics != nil {
+		in, out := &in.ContainersWithoutMetrics, &out.ContainersWithoutMetrics
+		*out = make([]ContainerWithoutMetrics, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadStatus.
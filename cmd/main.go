@@ -17,9 +17,14 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -33,6 +38,8 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -41,11 +48,17 @@ import (
 
 	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
 	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/audit"
+	optipodcache "github.com/optipod/optipod/internal/cache"
 	"github.com/optipod/optipod/internal/config"
 	"github.com/optipod/optipod/internal/controller"
+	"github.com/optipod/optipod/internal/dashboard"
+	optipoddiscovery "github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/fleet"
 	"github.com/optipod/optipod/internal/metrics"
 	"github.com/optipod/optipod/internal/observability"
 	"github.com/optipod/optipod/internal/recommendation"
+	"github.com/optipod/optipod/internal/report"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -73,6 +86,8 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var listMatchesPolicy, listMatchesFile string
+	var fleet fleetReportOptions
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -88,21 +103,95 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&listMatchesPolicy, "list-matches", "",
+		"Print the workloads matched by an existing policy (namespace/name) as JSON and exit without starting the manager.")
+	flag.StringVar(&listMatchesFile, "list-matches-file", "",
+		"Print the workloads matched by the policy manifest at this path (- for stdin) as JSON and exit.")
+	flag.StringVar(&fleet.contexts, "fleet-report-contexts", "",
+		"Comma-separated kubeconfig contexts to print a combined read-only recommendation report for, then exit.")
+	flag.StringVar(&fleet.policy, "fleet-report-policy", "",
+		"Policy (namespace/name) present in every cluster that the fleet report sizes workloads with.")
+	flag.StringVar(&fleet.policyFile, "fleet-report-policy-file", "",
+		"Policy manifest (- for stdin) the fleet report applies to every cluster.")
+	flag.StringVar(&fleet.format, "fleet-report-format", "csv", "Format of the fleet report: csv or json.")
+	flag.StringVar(&fleet.metricsURLs, "fleet-report-metrics-urls", "",
+		"Comma-separated context=url pairs overriding the Prometheus or OpenTelemetry URL per cluster in the fleet report.")
+	flag.StringVar(&fleet.container, "fleet-report-container", "",
+		"Limit the fleet report to the container with this name. Defaults to every container.")
 	opts := zap.Options{
 		Development: true,
 	}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	// Sample repetitive log lines so large reconciles do not flood the logging pipeline
+	initial, thereafter, logSamplingErr := operatorConfig.GetLogSampling()
+	if logSamplingErr == nil && initial > 0 {
+		opts.ZapOpts = append(opts.ZapOpts, observability.LogSampling(initial, thereafter))
+	}
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	if logSamplingErr != nil {
+		setupLog.Error(logSamplingErr, "invalid log sampling")
+		os.Exit(1)
+	}
+
+	// Selector dry-run: list matched workloads and exit without reconciling anything
+	if listMatchesPolicy != "" || listMatchesFile != "" {
+		if err := listMatches(context.Background(), listMatchesPolicy, listMatchesFile, operatorConfig.GetExcludedNamespaces()); err != nil {
+			setupLog.Error(err, "unable to list matched workloads")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Fleet report: recommend for workloads across clusters and exit without changing anything
+	if fleet.contexts != "" {
+		if err := fleetReport(context.Background(), operatorConfig, fleet); err != nil {
+			setupLog.Error(err, "unable to build the fleet report")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
 	// Log operator configuration
 	setupLog.Info("OptiPod operator configuration",
 		"dry-run", operatorConfig.IsDryRun(),
+		"optimization-paused", operatorConfig.IsOptimizationPaused(),
 		"metrics-provider", operatorConfig.GetMetricsProvider(),
 		"prometheus-url", operatorConfig.GetPrometheusURL(),
+		"otel-url", operatorConfig.GetOTelURL(),
+		"metrics-server-mode", operatorConfig.GetMetricsServerMode(),
+		"restart-aware-memory", operatorConfig.IsRestartAwareMemoryEnabled(),
+		"startup-exclusion-period", operatorConfig.GetStartupExclusionPeriod(),
+		"outlier-trim-fraction", operatorConfig.GetOutlierTrimFraction(),
+		"recommendation-cache-ttl", operatorConfig.GetRecommendationCacheTTL(),
 		"leader-election", operatorConfig.IsLeaderElectionEnabled(),
 		"reconciliation-interval", operatorConfig.GetReconciliationInterval(),
+		"event-aggregation-window", operatorConfig.GetEventAggregationWindow(),
+		"default-max-workloads", operatorConfig.GetDefaultMaxWorkloads(),
+		"dry-run-report-interval", operatorConfig.GetDryRunReportInterval(),
+		"max-concurrent-reconciles", operatorConfig.GetMaxConcurrentReconciles(),
+		"discovery-page-size", operatorConfig.GetDiscoveryPageSize(),
+		"excluded-namespaces", operatorConfig.GetExcludedNamespaces(),
+		"reconcile-time-budget", operatorConfig.GetReconcileTimeBudget(),
+		"patch-conflict-attempts", operatorConfig.PatchConflictAttempts,
+		"patch-conflict-backoff", operatorConfig.PatchConflictBackoff,
+		"quarantine-failure-threshold", operatorConfig.QuarantineFailureThreshold,
+		"quarantine-backoff", operatorConfig.QuarantineBackoff,
+		"quarantine-max-backoff", operatorConfig.QuarantineMaxBackoff,
+		"max-cpu-increase-per-interval", operatorConfig.MaxCPUIncreasePerInterval,
+		"max-memory-increase-per-interval", operatorConfig.MaxMemoryIncreasePerInterval,
+		"annotation-templates", operatorConfig.AnnotationTemplates,
+		"reload-configmap", operatorConfig.ReloadConfigMapName,
+		"recommendation-rules-configmap", operatorConfig.RecommendationRulesConfigMap,
+		"audit-sink", operatorConfig.AuditSink,
+		"audit-configmap-max-entries", operatorConfig.GetAuditConfigMapMaxEntries(),
+		"dashboard-api", operatorConfig.IsDashboardAPIEnabled(),
+		"metrics-recovery-check-interval", operatorConfig.GetMetricsRecoveryCheckInterval(),
+		"export-vpa-recommendations", operatorConfig.IsVPAExportEnabled(),
+		"log-sampling-initial", operatorConfig.LogSamplingInitial,
+		"log-sampling-thereafter", operatorConfig.LogSamplingThereafter,
+		"feature-detection-refresh-interval", operatorConfig.GetFeatureDetectionRefreshInterval(),
 	)
 
 	// Register OptiPod Prometheus metrics
@@ -223,54 +312,33 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create discovery client for feature detection
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	// Create discovery client for feature detection, caching its answers so feature detection
+	// does not call the API server on every reconcile
+	uncachedDiscoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
 	if err != nil {
 		setupLog.Error(err, "unable to create discovery client")
 		os.Exit(1)
 	}
+	discoveryClient := optipodcache.NewDiscoveryCache(uncachedDiscoveryClient,
+		operatorConfig.GetFeatureDetectionRefreshInterval())
 
 	// Initialize metrics provider based on operator configuration
-	var metricsProvider metrics.MetricsProvider
-	providerType := metrics.ProviderType(operatorConfig.GetMetricsProvider())
-
-	switch providerType {
-	case metrics.ProviderTypePrometheus:
-		metricsProvider, err = metrics.NewProvider(metrics.ProviderConfig{
-			Type:          metrics.ProviderTypePrometheus,
-			PrometheusURL: operatorConfig.GetPrometheusURL(),
-		})
-	case metrics.ProviderTypeMetricsServer:
-		metricsProvider, err = metrics.NewProvider(metrics.ProviderConfig{
-			Type:             metrics.ProviderTypeMetricsServer,
-			Clientset:        clientset,
-			MetricsClientset: metricsClientset,
-			MaxSamples:       operatorConfig.GetMetricsMaxSamples(),
-			SampleInterval:   operatorConfig.GetMetricsSampleInterval(),
-		})
-	default:
-		// Default to metrics-server with fallback
-		setupLog.Info("Unknown metrics provider, defaulting to metrics-server",
-			"provider", operatorConfig.GetMetricsProvider())
-		metricsProvider, err = metrics.NewProvider(metrics.ProviderConfig{
-			Type:             metrics.ProviderTypeMetricsServer,
-			Clientset:        clientset,
-			MetricsClientset: metricsClientset,
-			MaxSamples:       operatorConfig.GetMetricsMaxSamples(),
-			SampleInterval:   operatorConfig.GetMetricsSampleInterval(),
-		})
-	}
-
+	metricsProvider, err := newMetricsProvider(operatorConfig, clientset, metricsClientset, "")
 	if err != nil {
 		setupLog.Error(err, "unable to create metrics provider")
 		os.Exit(1)
 	}
+	if metrics.MetricsServerMode(operatorConfig.GetMetricsServerMode()) == metrics.MetricsServerModeInstantaneous {
+		setupLog.Info("metrics-server provider uses instantaneous readings; percentiles reflect a single sample " +
+			"and are not recommended for production sizing")
+	}
 
 	// Initialize recommendation engine
 	recommendationEngine := recommendation.NewEngine()
 
 	// Initialize application engine with global dry-run setting from operator config
 	applicationEngine := application.NewEngine(mgr.GetClient(), dynamicClient, discoveryClient, operatorConfig.IsDryRun())
+	applicationEngine.SetPatchRetry(operatorConfig.GetPatchConflictRetry())
 
 	// Initialize workload processor
 	workloadProcessor := controller.NewWorkloadProcessor(
@@ -280,19 +348,188 @@ func main() {
 		mgr.GetClient(),
 	)
 
-	// Create event recorder
-	eventRecorder := observability.NewEventRecorder(mgr.GetEventRecorderFor("optimizationpolicy-controller"))
+	// Gate writes on holding the leader lease, so a former leader's writes are rejected
+	leaderTracker := controller.NewLeaderTracker(mgr.Elected())
+	if err := mgr.Add(leaderTracker); err != nil {
+		setupLog.Error(err, "unable to set up leadership tracking")
+		os.Exit(1)
+	}
+	workloadProcessor.SetLeaderTracker(leaderTracker)
+
+	// Hold CPU requests of workloads KEDA scales on CPU; inactive until the KEDA CRD is installed
+	workloadProcessor.SetScaledObjectFinder(controller.NewScaledObjectFinder(dynamicClient, discoveryClient))
+
+	// Optionally export recommendations to VerticalPodAutoscalers; inactive until the VPA CRD is installed
+	var vpaExporter *controller.VPAExporter
+	if operatorConfig.IsVPAExportEnabled() {
+		vpaExporter = controller.NewVPAExporter(dynamicClient, discoveryClient, operatorConfig.IsDryRun())
+		workloadProcessor.SetVPAExporter(vpaExporter)
+	}
+
+	// Optionally reuse the recommendations of containers whose inputs did not change
+	if ttl := operatorConfig.GetRecommendationCacheTTL(); ttl > 0 {
+		workloadProcessor.SetRecommendationCache(optipodcache.NewRecommendationCache(ttl))
+	}
+
+	// Optionally cap the requests all policies together may add per reconciliation interval
+	maxCPUIncrease, maxMemoryIncrease, err := operatorConfig.GetIncreaseBudget()
+	if err != nil {
+		setupLog.Error(err, "invalid increase budget")
+		os.Exit(1)
+	}
+	// The budget is kept even while unlimited, so a configuration reload can set limits later
+	increaseBudget := controller.NewIncreaseBudget(maxCPUIncrease, maxMemoryIncrease, operatorConfig.GetReconciliationInterval)
+	workloadProcessor.SetIncreaseBudget(increaseBudget)
+
+	// Optionally mirror recommendations under custom annotation keys; templates are validated here
+	annotationTemplates, err := controller.ParseAnnotationTemplates(operatorConfig.AnnotationTemplates)
+	if err != nil {
+		setupLog.Error(err, "invalid annotation templates")
+		os.Exit(1)
+	}
+	workloadProcessor.SetAnnotationTemplates(annotationTemplates)
+
+	// Optionally keep an audit trail of every applied change in an external sink or changelog ConfigMaps
+	if sinkType, sinkURL := operatorConfig.GetAuditSink(); sinkType != "" {
+		sink, err := audit.NewSink(sinkType, audit.SinkOptions{
+			URL:        sinkURL,
+			Client:     mgr.GetClient(),
+			Reader:     mgr.GetAPIReader(),
+			MaxEntries: operatorConfig.GetAuditConfigMapMaxEntries(),
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to create audit sink")
+			os.Exit(1)
+		}
+		auditLogger := audit.NewLogger(sink, audit.DefaultBufferSize)
+		if err := mgr.Add(auditLogger); err != nil {
+			setupLog.Error(err, "unable to set up audit trail")
+			os.Exit(1)
+		}
+		workloadProcessor.SetAuditLogger(auditLogger)
+	}
 
+	// Recommendations are collected when they feed the dry-run impact report or the recording rules
+	dryRunReportEnabled := operatorConfig.IsDryRun() && operatorConfig.GetDryRunReportInterval() > 0
+	rulesNamespace, rulesName := operatorConfig.GetRecommendationRulesTarget()
+	var impactCollector *report.Collector
+	if dryRunReportEnabled || rulesName != "" {
+		impactCollector = report.NewCollector()
+		workloadProcessor.SetImpactCollector(impactCollector)
+	}
+
+	// In dry-run mode, periodically summarize the cluster-wide impact of the recommendations
+	if dryRunReportEnabled {
+		reportNamespace, reportName := operatorConfig.GetDryRunReportTarget()
+		if err := mgr.Add(report.NewReporter(
+			impactCollector,
+			mgr.GetClient(),
+			mgr.GetAPIReader(),
+			operatorConfig.GetDryRunReportInterval(),
+			reportNamespace,
+			reportName,
+		)); err != nil {
+			setupLog.Error(err, "unable to set up dry-run impact report")
+			os.Exit(1)
+		}
+	}
+
+	// Optionally publish recommendations as Prometheus recording rules for historical queries
+	if rulesName != "" {
+		if err := mgr.Add(report.NewRulesWriter(
+			impactCollector,
+			mgr.GetClient(),
+			mgr.GetAPIReader(),
+			operatorConfig.GetRecommendationRulesInterval(),
+			rulesNamespace,
+			rulesName,
+		)); err != nil {
+			setupLog.Error(err, "unable to set up recommendation recording rules")
+			os.Exit(1)
+		}
+	}
+
+	// Optionally serve the dashboard API from the metrics server. It relies on the metrics
+	// server's authentication and authorization, so it is never served without them.
+	if operatorConfig.IsDashboardAPIEnabled() {
+		if metricsAddr == "0" || !secureMetrics {
+			setupLog.Error(errors.New("the dashboard API requires --metrics-bind-address and --metrics-secure"),
+				"unable to set up dashboard API")
+			os.Exit(1)
+		}
+		dashboardStore := dashboard.NewStore(dashboard.DefaultRetention, dashboard.DefaultHistorySize)
+		workloadProcessor.SetDashboardStore(dashboardStore)
+		if err := mgr.AddMetricsServerExtraHandler(dashboard.PathPrefix,
+			dashboard.NewHandler(mgr.GetClient(), dashboardStore)); err != nil {
+			setupLog.Error(err, "unable to set up dashboard API")
+			os.Exit(1)
+		}
+	}
+
+	// Reconcile policies with workloads skipped for missing metrics as soon as the backend recovers
+	var metricsRecovery *controller.MetricsRecoveryWatcher
+	if interval := operatorConfig.GetMetricsRecoveryCheckInterval(); interval > 0 {
+		metricsRecovery = controller.NewMetricsRecoveryWatcher(metricsProvider, interval)
+		if err := mgr.Add(metricsRecovery); err != nil {
+			setupLog.Error(err, "unable to set up metrics recovery watcher")
+			os.Exit(1)
+		}
+		workloadProcessor.SetMetricsRecoveryWatcher(metricsRecovery)
+	}
+
+	// Hold Auto mode changes back until metrics are flowing after startup
+	workloadProcessor.SetAutoReadinessGate(controller.NewAutoReadinessGate(metricsProvider))
+
+	// Create event recorder that aggregates repeated identical events
+	aggregatingRecorder := observability.NewAggregatingRecorder(
+		mgr.GetEventRecorderFor("optimizationpolicy-controller"),
+		operatorConfig.GetEventAggregationWindow(),
+	)
+	eventRecorder := observability.NewEventRecorder(aggregatingRecorder)
+	workloadProcessor.SetEventRecorder(eventRecorder)
+
+	quarantineThreshold, quarantineBackoff, quarantineMaxBackoff := operatorConfig.GetQuarantine()
 	if err := (&controller.OptimizationPolicyReconciler{
-		Client:            mgr.GetClient(),
-		Scheme:            mgr.GetScheme(),
-		Recorder:          mgr.GetEventRecorderFor("optimizationpolicy-controller"),
-		WorkloadProcessor: workloadProcessor,
-		EventRecorder:     eventRecorder,
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		Recorder:                aggregatingRecorder,
+		WorkloadProcessor:       workloadProcessor,
+		EventRecorder:           eventRecorder,
+		OperatorConfig:          operatorConfig,
+		MaxConcurrentReconciles: operatorConfig.GetMaxConcurrentReconciles(),
+		APIReader:               mgr.GetAPIReader(),
+		DiscoveryPageSize:       int64(operatorConfig.GetDiscoveryPageSize()),
+		ExcludedNamespaces:      operatorConfig.GetExcludedNamespaces(),
+		LeaderTracker:           leaderTracker,
+		ReconcileBudget:         operatorConfig.GetReconcileTimeBudget(),
+		MetricsRecovery:         metricsRecovery,
+		Quarantine: controller.QuarantineConfig{
+			FailureThreshold: quarantineThreshold,
+			Backoff:          quarantineBackoff,
+			MaxBackoff:       quarantineMaxBackoff,
+		},
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OptimizationPolicy")
 		os.Exit(1)
 	}
+
+	// Optionally hot-reload safe-to-change configuration from a watched ConfigMap
+	if reloadNamespace, reloadName := operatorConfig.GetReloadConfigMap(); reloadName != "" {
+		reloader := config.NewReloader(operatorConfig, mgr.GetAPIReader(), reloadNamespace, reloadName)
+		reloader.OnReload(func(c *config.OperatorConfig) {
+			applicationEngine.SetDryRun(c.IsDryRun())
+			if vpaExporter != nil {
+				vpaExporter.SetDryRun(c.IsDryRun())
+			}
+			// The reload validated the budget, so it cannot fail here
+			maxCPUIncrease, maxMemoryIncrease, _ := c.GetIncreaseBudget()
+			increaseBudget.SetLimits(maxCPUIncrease, maxMemoryIncrease)
+		})
+		if err := mgr.Add(reloader); err != nil {
+			setupLog.Error(err, "unable to set up configuration reload")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -310,3 +547,199 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// newMetricsProvider creates the metrics provider selected by the operator configuration.
+// metricsURL, when set, replaces the configured Prometheus or OpenTelemetry backend URL.
+func newMetricsProvider(
+	operatorConfig *config.OperatorConfig,
+	clientset kubernetes.Interface,
+	metricsClientset metricsclientset.Interface,
+	metricsURL string,
+) (metrics.MetricsProvider, error) {
+	switch metrics.ProviderType(operatorConfig.GetMetricsProvider()) {
+	case metrics.ProviderTypePrometheus:
+		prometheusURL := operatorConfig.GetPrometheusURL()
+		if metricsURL != "" {
+			prometheusURL = metricsURL
+		}
+		return metrics.NewProvider(metrics.ProviderConfig{
+			Type:                   metrics.ProviderTypePrometheus,
+			PrometheusURL:          prometheusURL,
+			RestartAwareMemory:     operatorConfig.IsRestartAwareMemoryEnabled(),
+			StartupExclusionPeriod: operatorConfig.GetStartupExclusionPeriod(),
+			OutlierTrimFraction:    operatorConfig.GetOutlierTrimFraction(),
+		})
+	case metrics.ProviderTypeOTelPromQL:
+		otelURL := operatorConfig.GetOTelURL()
+		if metricsURL != "" {
+			otelURL = metricsURL
+		}
+		return metrics.NewProvider(metrics.ProviderConfig{
+			Type:                   metrics.ProviderTypeOTelPromQL,
+			OTelURL:                otelURL,
+			OTelHealthPath:         operatorConfig.GetOTelHealthPath(),
+			RestartAwareMemory:     operatorConfig.IsRestartAwareMemoryEnabled(),
+			StartupExclusionPeriod: operatorConfig.GetStartupExclusionPeriod(),
+			OutlierTrimFraction:    operatorConfig.GetOutlierTrimFraction(),
+		})
+	case metrics.ProviderTypeMetricsServer:
+	default:
+		// Default to metrics-server with fallback
+		setupLog.Info("Unknown metrics provider, defaulting to metrics-server",
+			"provider", operatorConfig.GetMetricsProvider())
+	}
+	return metrics.NewProvider(metrics.ProviderConfig{
+		Type:                metrics.ProviderTypeMetricsServer,
+		Clientset:           clientset,
+		MetricsClientset:    metricsClientset,
+		MaxSamples:          operatorConfig.GetMetricsMaxSamples(),
+		SampleInterval:      operatorConfig.GetMetricsSampleInterval(),
+		MetricsServerMode:   metrics.MetricsServerMode(operatorConfig.GetMetricsServerMode()),
+		RestartAwareMemory:  operatorConfig.IsRestartAwareMemoryEnabled(),
+		OutlierTrimFraction: operatorConfig.GetOutlierTrimFraction(),
+	})
+}
+
+// listMatches runs workload discovery for a single policy, read either from the cluster or from
+// a manifest, and prints the matched workloads as JSON. No metrics are queried and nothing is applied.
+// Namespaces in excludedNamespaces are skipped as they are by the controller.
+func listMatches(ctx context.Context, policyRef, policyFile string, excludedNamespaces []string) error {
+	if policyRef != "" && policyFile != "" {
+		return fmt.Errorf("--list-matches and --list-matches-file are mutually exclusive")
+	}
+
+	k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	var policy *optipodv1alpha1.OptimizationPolicy
+	if policyRef != "" {
+		policy, err = optipoddiscovery.GetPolicy(ctx, k8sClient, policyRef)
+	} else {
+		policy, err = decodePolicyFile(policyFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	result, err := optipoddiscovery.ListMatchedWorkloads(ctx, k8sClient, policy, optipoddiscovery.Options{
+		ExcludedNamespaces: excludedNamespaces,
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// fleetReportOptions are the flags of the fleet report
+type fleetReportOptions struct {
+	contexts    string
+	policy      string
+	policyFile  string
+	format      string
+	metricsURLs string
+	container   string
+}
+
+// fleetReport computes recommendations in every kubeconfig context and prints them as one CSV or
+// JSON report with a cluster column. Policies run in Recommend mode without writing annotations,
+// so no cluster is changed.
+func fleetReport(ctx context.Context, operatorConfig *config.OperatorConfig, opts fleetReportOptions) error {
+	if (opts.policy == "") == (opts.policyFile == "") {
+		return fmt.Errorf("exactly one of --fleet-report-policy and --fleet-report-policy-file is required")
+	}
+	if opts.format != "csv" && opts.format != "json" {
+		return fmt.Errorf("--fleet-report-format must be csv or json, got %q", opts.format)
+	}
+
+	metricsURLs := make(map[string]string)
+	for _, pair := range strings.Split(opts.metricsURLs, ",") {
+		if pair == "" {
+			continue
+		}
+		contextName, url, ok := strings.Cut(pair, "=")
+		if !ok || contextName == "" || url == "" {
+			return fmt.Errorf("invalid --fleet-report-metrics-urls entry %q: expected context=url", pair)
+		}
+		metricsURLs[contextName] = url
+	}
+
+	fleetOpts := fleet.Options{
+		PolicyRef: opts.policy,
+		Discovery: optipoddiscovery.Options{ExcludedNamespaces: operatorConfig.GetExcludedNamespaces()},
+		Container: opts.container,
+	}
+	if opts.policyFile != "" {
+		policy, err := decodePolicyFile(opts.policyFile)
+		if err != nil {
+			return err
+		}
+		fleetOpts.Policy = policy
+	}
+
+	var clusters []fleet.Cluster
+	for _, contextName := range strings.Split(opts.contexts, ",") {
+		if contextName == "" {
+			continue
+		}
+		cluster, err := newFleetCluster(operatorConfig, contextName, metricsURLs[contextName])
+		if err != nil {
+			return fmt.Errorf("context %s: %w", contextName, err)
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	// Clusters that fail are reported after the rows of the others are written
+	rows, reportErr := fleet.Recommend(ctx, clusters, fleetOpts)
+	var err error
+	if opts.format == "json" {
+		err = fleet.WriteJSON(os.Stdout, rows)
+	} else {
+		err = fleet.WriteCSV(os.Stdout, rows)
+	}
+	return errors.Join(err, reportErr)
+}
+
+// newFleetCluster connects to the cluster of a kubeconfig context
+func newFleetCluster(operatorConfig *config.OperatorConfig, contextName, metricsURL string) (fleet.Cluster, error) {
+	restConfig, err := ctrlconfig.GetConfigWithContext(contextName)
+	if err != nil {
+		return fleet.Cluster{}, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return fleet.Cluster{}, fmt.Errorf("failed to create client: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fleet.Cluster{}, fmt.Errorf("failed to create clientset: %w", err)
+	}
+	metricsClientset, err := metricsclientset.NewForConfig(restConfig)
+	if err != nil {
+		return fleet.Cluster{}, fmt.Errorf("failed to create metrics clientset: %w", err)
+	}
+	metricsProvider, err := newMetricsProvider(operatorConfig, clientset, metricsClientset, metricsURL)
+	if err != nil {
+		return fleet.Cluster{}, fmt.Errorf("failed to create metrics provider: %w", err)
+	}
+	return fleet.Cluster{Name: contextName, Client: k8sClient, MetricsProvider: metricsProvider}, nil
+}
+
+// decodePolicyFile reads a policy manifest from a file, or from stdin when path is "-"
+func decodePolicyFile(path string) (*optipodv1alpha1.OptimizationPolicy, error) {
+	if path == "-" {
+		return optipoddiscovery.DecodePolicy(os.Stdin)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open policy file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return optipoddiscovery.DecodePolicy(f)
+}
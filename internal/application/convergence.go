@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// convergenceEpsilon is the distance to the recommendation, relative to the recommendation,
+// below which a converging request goes straight to the recommendation
+const convergenceEpsilon = 0.02
+
+// convergenceTarget returns the recommendation to apply in this reconcile when the policy sets
+// a convergence rate. Each optimized request moves the configured fraction of the distance from
+// its current value toward the recommendation, never past it. The second result is true when
+// every request reaches the recommendation.
+//
+// Without a convergence rate, for urgent recommendations, or when the container has no current
+// request, the recommendation is returned unchanged.
+func convergenceTarget(
+	currentResources map[string]corev1.ResourceRequirements,
+	containerName string,
+	rec *recommendation.Recommendation,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) (*recommendation.Recommendation, bool) {
+	rate := policy.Spec.UpdateStrategy.ConvergenceRate
+	if rate == nil || *rate >= 1 || rec.Urgent {
+		return rec, true
+	}
+	current := currentResources[containerName]
+
+	target := *rec
+	converged := true
+	if policy.OptimizesCPU() {
+		if currentCPU, ok := current.Requests[corev1.ResourceCPU]; ok {
+			var reached bool
+			target.CPU, reached = convergeQuantity(currentCPU, rec.CPU, *rate, true)
+			converged = converged && reached
+		}
+	}
+	if policy.OptimizesMemory() {
+		if currentMemory, ok := current.Requests[corev1.ResourceMemory]; ok {
+			var reached bool
+			target.Memory, reached = convergeQuantity(currentMemory, rec.Memory, *rate, false)
+			converged = converged && reached
+		}
+
+		// A percentile-derived memory limit converges along with the request, and never
+		// drops below it
+		if !rec.MemoryLimit.IsZero() {
+			if currentLimit, ok := current.Limits[corev1.ResourceMemory]; ok {
+				target.MemoryLimit, _ = convergeQuantity(currentLimit, rec.MemoryLimit, *rate, false)
+			}
+			if target.MemoryLimit.Cmp(target.Memory) < 0 {
+				target.MemoryLimit = target.Memory.DeepCopy()
+			}
+		}
+	}
+
+	return &target, converged
+}
+
+// convergeQuantity moves current the given fraction of the way to target. It returns the target
+// itself, and true, once the remaining distance is within convergenceEpsilon of the target or
+// the step would not change the value. CPU quantities are stepped in millicores.
+func convergeQuantity(current, target resource.Quantity, rate float64, milli bool) (resource.Quantity, bool) {
+	from, to := current.Value(), target.Value()
+	if milli {
+		from, to = current.MilliValue(), target.MilliValue()
+	}
+
+	distance := to - from
+	if math.Abs(float64(distance)) <= convergenceEpsilon*float64(to) {
+		return target.DeepCopy(), true
+	}
+
+	// Round toward the current value so a step never overshoots the target
+	step := int64(float64(distance) * rate)
+	if step == 0 {
+		return target.DeepCopy(), true
+	}
+
+	if milli {
+		return *resource.NewMilliQuantity(from+step, target.Format), false
+	}
+	return *resource.NewQuantity(from+step, target.Format), false
+}
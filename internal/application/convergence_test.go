@@ -0,0 +1,209 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// maxConvergenceSteps bounds the reconciles needed to converge at the slowest tested rate
+const maxConvergenceSteps = 200
+
+func TestConvergeQuantity(t *testing.T) {
+	tests := []struct {
+		name          string
+		current       string
+		target        string
+		milli         bool
+		want          string
+		wantConverged bool
+	}{
+		{name: "CPU decrease halfway", current: "1000m", target: "200m", milli: true, want: "600m"},
+		{name: "CPU increase halfway", current: "200m", target: "1000m", milli: true, want: "600m"},
+		{name: "memory decrease halfway", current: "1Gi", target: "512Mi", want: "768Mi"},
+		{name: "within epsilon", current: "1010m", target: "1000m", milli: true, want: "1000m", wantConverged: true},
+		{name: "already converged", current: "512Mi", target: "512Mi", want: "512Mi", wantConverged: true},
+		{name: "step rounds to zero", current: "3m", target: "2m", milli: true, want: "2m", wantConverged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, converged := convergeQuantity(resource.MustParse(tt.current), resource.MustParse(tt.target), 0.5, tt.milli)
+			if got.Cmp(resource.MustParse(tt.want)) != 0 || converged != tt.wantConverged {
+				t.Errorf("convergeQuantity() = (%s, %v), want (%s, %v)", got.String(), converged, tt.want, tt.wantConverged)
+			}
+		})
+	}
+}
+
+func TestConvergenceTarget_WithoutRate(t *testing.T) {
+	rec := createMockRecommendation()
+	policy := createMockPolicy(true, false)
+
+	target, converged := convergenceTarget(nil, "test-container", rec, policy)
+	if target != rec || !converged {
+		t.Errorf("convergenceTarget() = (%+v, %v), want the recommendation unchanged", target, converged)
+	}
+}
+
+func TestConvergenceTarget_UrgentAppliedInFull(t *testing.T) {
+	rate := 0.5
+	policy := createMockPolicy(true, false)
+	policy.Spec.UpdateStrategy.ConvergenceRate = &rate
+	current := map[string]corev1.ResourceRequirements{
+		"test-container": {
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+		},
+	}
+	rec := &recommendation.Recommendation{Memory: resource.MustParse("640Mi"), Urgent: true}
+
+	target, converged := convergenceTarget(current, "test-container", rec, policy)
+	if target != rec || !converged {
+		t.Errorf("convergenceTarget() = (%+v, %v), want the urgent recommendation unchanged", target, converged)
+	}
+}
+
+func TestConvergenceTarget_MemoryLimitNotBelowRequest(t *testing.T) {
+	rate := 0.5
+	policy := createMockPolicy(true, false)
+	policy.Spec.UpdateStrategy.ConvergenceRate = &rate
+	current := map[string]corev1.ResourceRequirements{
+		"test-container": {
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		},
+	}
+	rec := &recommendation.Recommendation{
+		Memory:      resource.MustParse("256Mi"),
+		MemoryLimit: resource.MustParse("300Mi"),
+	}
+
+	target, _ := convergenceTarget(current, "test-container", rec, policy)
+	if target.Memory.Cmp(resource.MustParse("640Mi")) != 0 {
+		t.Errorf("memory request = %s, want 640Mi", target.Memory.String())
+	}
+	if target.MemoryLimit.Cmp(target.Memory) < 0 {
+		t.Errorf("memory limit %s is below the memory request %s", target.MemoryLimit.String(), target.Memory.String())
+	}
+}
+
+// For any current requests, recommendation and convergence rate, repeatedly applying the
+// recommendation moves requests monotonically toward it without overshooting, and reaches it
+// within a bounded number of reconciles.
+func TestProperty_ConvergenceIsMonotonicWithoutOvershoot(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("requests converge monotonically to the recommendation", prop.ForAll(
+		func(currentMillis, recMillis, currentMiB, recMiB int64, ratePercent int) bool {
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			engine := newFakeClusterEngine(c, kindDeployment)
+			newFakeClusterDeployment(t, c,
+				corev1.ResourceList{
+					corev1.ResourceCPU:    *resource.NewMilliQuantity(currentMillis, resource.DecimalSI),
+					corev1.ResourceMemory: *resource.NewQuantity(currentMiB*1024*1024, resource.BinarySI),
+				},
+				nil,
+			)
+
+			rec := &recommendation.Recommendation{
+				CPU:    *resource.NewMilliQuantity(recMillis, resource.DecimalSI),
+				Memory: *resource.NewQuantity(recMiB*1024*1024, resource.BinarySI),
+			}
+			rate := float64(ratePercent) / 100
+			policy := newResourceOptimizationPolicy(true, true, true)
+			policy.Spec.UpdateStrategy.ConvergenceRate = &rate
+
+			previous := map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    *resource.NewMilliQuantity(currentMillis, resource.DecimalSI),
+				corev1.ResourceMemory: *resource.NewQuantity(currentMiB*1024*1024, resource.BinarySI),
+			}
+			want := map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    rec.CPU,
+				corev1.ResourceMemory: rec.Memory,
+			}
+
+			for step := 0; step < maxConvergenceSteps; step++ {
+				workload, _, err := getFakeClusterWorkload(c)
+				if err != nil {
+					t.Logf("failed to get workload: %v", err)
+					return false
+				}
+				result, err := engine.Apply(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
+				if err != nil {
+					t.Logf("failed to apply: %v", err)
+					return false
+				}
+
+				_, after, err := getFakeClusterWorkload(c)
+				if err != nil {
+					t.Logf("failed to get workload: %v", err)
+					return false
+				}
+				requests := after.Spec.Template.Spec.Containers[0].Resources.Requests
+				for name, target := range want {
+					got := requests[name]
+					before := previous[name]
+					// Each step stays between the previous request and the recommendation
+					if !between(got, before, target) {
+						t.Logf("step %d: %s request %s is not between %s and %s", step, name, got.String(), before.String(), target.String())
+						return false
+					}
+					previous[name] = got
+				}
+
+				if result.Containers["test-container"].Converged {
+					for name, target := range want {
+						got := requests[name]
+						if got.Cmp(target) != 0 {
+							t.Logf("converged with %s request %s, want %s", name, got.String(), target.String())
+							return false
+						}
+					}
+					return true
+				}
+			}
+
+			t.Logf("did not converge within %d reconciles", maxConvergenceSteps)
+			return false
+		},
+		gen.Int64Range(100, 4000),
+		gen.Int64Range(100, 4000),
+		gen.Int64Range(128, 8192),
+		gen.Int64Range(128, 8192),
+		gen.IntRange(10, 100),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// between reports whether q lies between a and b, inclusive, in either order
+func between(q, a, b resource.Quantity) bool {
+	if a.Cmp(b) > 0 {
+		a, b = b, a
+	}
+	return q.Cmp(a) >= 0 && q.Cmp(b) <= 0
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"fmt"
+	"time"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// workloadApplyMethod returns how a change to the containers is rolled out: Recreate when any
+// container's change recreates the pods, otherwise InPlace when any is resized in-place. It is
+// empty when the changes do not say.
+func workloadApplyMethod(changes []ContainerChange) ApplyMethod {
+	var method ApplyMethod
+	for _, change := range changes {
+		switch change.Method {
+		case Recreate:
+			return Recreate
+		case InPlace:
+			method = InPlace
+		}
+	}
+	return method
+}
+
+// recreateCooldown returns a skip decision when the workload's pods were last recreated less than
+// the policy's minimum recreate interval before now, or nil when they may be recreated. The last
+// recreate is read from the workload's last-recreated annotation, which in-place resizes leave
+// alone, so they do not end the cooldown.
+func recreateCooldown(workload *Workload, policy *optipodv1alpha1.OptimizationPolicy, now time.Time) *ApplyDecision {
+	interval := policy.Spec.UpdateStrategy.MinRecreateInterval
+	if interval == nil || workload == nil || workload.Object == nil {
+		return nil
+	}
+
+	lastRecreated, err := time.Parse(time.RFC3339, workload.Object.GetAnnotations()[optipodv1alpha1.AnnotationLastRecreated])
+	if err != nil {
+		return nil
+	}
+
+	next := lastRecreated.Add(interval.Duration)
+	if !now.Before(next) {
+		return nil
+	}
+	return &ApplyDecision{
+		CanApply: false,
+		Method:   Skip,
+		Reason: fmt.Sprintf("Recreate cooldown active: pods were recreated at %s, next recreate allowed after %s",
+			lastRecreated.UTC().Format(time.RFC3339), next.UTC().Format(time.RFC3339)),
+		Cause: ErrRecreateCooldown,
+	}
+}
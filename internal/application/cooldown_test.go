@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// newCooldownTestWorkload returns a Burstable workload whose pods were last recreated and then
+// resized in-place the given times ago; zero means never
+func newCooldownTestWorkload(recreatedAgo, resizedAgo time.Duration) *Workload {
+	workload := createMockWorkloadWithResources(map[string]interface{}{
+		"requests": map[string]interface{}{"cpu": "250m", "memory": "256Mi"},
+	})
+	annotations := make(map[string]string)
+	if recreatedAgo > 0 {
+		recreated := time.Now().Add(-recreatedAgo).UTC().Format(time.RFC3339)
+		annotations[optipodv1alpha1.AnnotationLastApplied] = recreated
+		annotations[optipodv1alpha1.AnnotationLastApplyMethod] = string(Recreate)
+		annotations[optipodv1alpha1.AnnotationLastRecreated] = recreated
+	}
+	if resizedAgo > 0 {
+		annotations[optipodv1alpha1.AnnotationLastApplied] = time.Now().Add(-resizedAgo).UTC().Format(time.RFC3339)
+		annotations[optipodv1alpha1.AnnotationLastApplyMethod] = string(InPlace)
+	}
+	workload.Object.SetAnnotations(annotations)
+	return workload
+}
+
+func TestCanApply_RecreateCooldown(t *testing.T) {
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("300m"), Memory: resource.MustParse("300Mi")}
+
+	tests := []struct {
+		name         string
+		allowInPlace bool
+		recreatedAgo time.Duration
+		resizedAgo   time.Duration
+		wantCanApply bool
+		wantMethod   ApplyMethod
+	}{
+		{
+			name:         "a recreate within the interval is held back",
+			recreatedAgo: 10 * time.Minute,
+			wantMethod:   Skip,
+		},
+		{
+			name:         "a recreate after the interval is allowed",
+			recreatedAgo: 2 * time.Hour,
+			wantCanApply: true,
+			wantMethod:   Recreate,
+		},
+		{
+			name:         "an in-place resize since the last recreate does not end the interval",
+			recreatedAgo: 30 * time.Minute,
+			resizedAgo:   10 * time.Minute,
+			wantMethod:   Skip,
+		},
+		{
+			name:         "a recreate after only in-place resizes is allowed",
+			resizedAgo:   10 * time.Minute,
+			wantCanApply: true,
+			wantMethod:   Recreate,
+		},
+		{
+			name:         "an in-place resize within the interval is allowed",
+			allowInPlace: true,
+			recreatedAgo: 10 * time.Minute,
+			wantCanApply: true,
+			wantMethod:   InPlace,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &Engine{
+				discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}},
+			}
+			policy := createMockPolicy(tt.allowInPlace, true)
+			policy.Spec.UpdateStrategy.MinRecreateInterval = &metav1.Duration{Duration: time.Hour}
+
+			decision, err := engine.CanApply(context.Background(), newCooldownTestWorkload(tt.recreatedAgo, tt.resizedAgo), "test-container", rec, policy)
+			if err != nil {
+				t.Fatalf("CanApply() error = %v", err)
+			}
+			if decision.CanApply != tt.wantCanApply || decision.Method != tt.wantMethod {
+				t.Errorf("CanApply() = %v with %s (%s), want %v with %s",
+					decision.CanApply, decision.Method, decision.Reason, tt.wantCanApply, tt.wantMethod)
+			}
+			if wantCooldown := tt.wantMethod == Skip; errors.Is(decision.Cause, ErrRecreateCooldown) != wantCooldown {
+				t.Errorf("Cause = %v, want recreate cooldown %v", decision.Cause, wantCooldown)
+			}
+		})
+	}
+}
+
+func TestLastAppliedAnnotations_Method(t *testing.T) {
+	engine := &Engine{}
+	policy := createMockPolicy(true, true)
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("300m"), Memory: resource.MustParse("300Mi")}
+
+	tests := []struct {
+		name    string
+		methods []ApplyMethod
+		want    string
+	}{
+		{name: "in-place", methods: []ApplyMethod{InPlace, InPlace}, want: string(InPlace)},
+		{name: "any recreate recreates the pods", methods: []ApplyMethod{InPlace, Recreate}, want: string(Recreate)},
+		{name: "unknown", methods: []ApplyMethod{""}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := make([]ContainerChange, 0, len(tt.methods))
+			for _, method := range tt.methods {
+				changes = append(changes, ContainerChange{Container: "test-container", Recommendation: rec, Method: method})
+			}
+
+			patch, err := engine.buildResourcePatch(createMockWorkloadWithResources(nil), changes[:1], policy)
+			if err != nil {
+				t.Fatalf("buildResourcePatch() error = %v", err)
+			}
+			annotations := lastAppliedAnnotations(changes)
+			if got, _ := annotations[optipodv1alpha1.AnnotationLastApplyMethod].(string); got != tt.want {
+				t.Errorf("last-apply-method = %q, want %q", got, tt.want)
+			}
+			if _, recreated := annotations[optipodv1alpha1.AnnotationLastRecreated]; recreated != (tt.want == string(Recreate)) {
+				t.Errorf("last-recreated set = %v, want it set only when the pods are recreated", recreated)
+			}
+
+			var decoded struct {
+				Metadata struct {
+					Annotations map[string]string `json:"annotations"`
+				} `json:"metadata"`
+			}
+			if err := json.Unmarshal(patch, &decoded); err != nil {
+				t.Fatalf("failed to decode patch: %v", err)
+			}
+			if got := decoded.Metadata.Annotations[optipodv1alpha1.AnnotationLastApplyMethod]; got != string(tt.methods[0]) {
+				t.Errorf("patched last-apply-method = %q, want %q", got, tt.methods[0])
+			}
+		})
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -65,6 +67,26 @@ type ApplyDecision struct {
 	CanApply bool
 	Method   ApplyMethod
 	Reason   string
+
+	// Cause classifies why the change is skipped when it is blocked for safety
+	// (e.g. ErrUnsafeMemoryDecrease); nil otherwise
+	Cause error
+
+	// RestartsContainer is true when the in-place resize restarts the container because its
+	// resizePolicy for a changed resource is RestartContainer
+	RestartsContainer bool
+
+	// BeyondInPlaceBounds is true when the change exceeds updateStrategy.inPlaceBounds, so the
+	// pods are recreated instead of resized in-place
+	BeyondInPlaceBounds bool
+
+	// InvalidUpdateMethod is the value of the workload's update method annotation when it is not
+	// a known update method and the policy's update strategy was used instead; empty otherwise
+	InvalidUpdateMethod string
+
+	// SuppressedDecreases lists the resources whose request the recommendation would lower and
+	// updateStrategy.increaseOnly keeps at its current value
+	SuppressedDecreases []corev1.ResourceName
 }
 
 // Workload represents a Kubernetes workload resource
@@ -73,6 +95,10 @@ type Workload struct {
 	Namespace string
 	Name      string
 	Object    *unstructured.Unstructured
+
+	// EffectiveResources holds per-container resources after namespace LimitRange defaults
+	// are applied. When set for a container, it is used instead of the resources in the spec.
+	EffectiveResources map[string]corev1.ResourceRequirements
 }
 
 // Engine handles application of resource recommendations to workloads
@@ -80,7 +106,15 @@ type Engine struct {
 	client          client.Client
 	dynamicClient   dynamic.Interface
 	discoveryClient discovery.DiscoveryInterface
-	dryRun          bool
+
+	// dryRunMu guards dryRun, which can be changed at runtime by a configuration reload
+	dryRunMu sync.RWMutex
+	dryRun   bool
+
+	// patchAttempts is the number of times a patch failing with a transient error is attempted,
+	// and patchBackoff the base delay between attempts (see SetPatchRetry)
+	patchAttempts int
+	patchBackoff  time.Duration
 }
 
 // NewEngine creates a new application engine
@@ -90,13 +124,30 @@ func NewEngine(c client.Client, dynamicClient dynamic.Interface, discoveryClient
 		dynamicClient:   dynamicClient,
 		discoveryClient: discoveryClient,
 		dryRun:          dryRun,
+		patchAttempts:   DefaultPatchAttempts,
+		patchBackoff:    DefaultPatchBackoff,
 	}
 }
 
+// SetDryRun enables or disables global dry-run mode at runtime
+func (e *Engine) SetDryRun(dryRun bool) {
+	e.dryRunMu.Lock()
+	defer e.dryRunMu.Unlock()
+	e.dryRun = dryRun
+}
+
+// isDryRun returns true if global dry-run mode is enabled
+func (e *Engine) isDryRun() bool {
+	e.dryRunMu.RLock()
+	defer e.dryRunMu.RUnlock()
+	return e.dryRun
+}
+
 // CanApply determines if changes can be applied to a workload
 func (e *Engine) CanApply(
 	ctx context.Context,
 	workload *Workload,
+	containerName string,
 	rec *recommendation.Recommendation,
 	policy *optipodv1alpha1.OptimizationPolicy,
 ) (*ApplyDecision, error) {
@@ -118,7 +169,7 @@ func (e *Engine) CanApply(
 	}
 
 	// Check global dry-run
-	if e.dryRun {
+	if e.isDryRun() {
 		return &ApplyDecision{
 			CanApply: false,
 			Method:   Skip,
@@ -126,18 +177,122 @@ func (e *Engine) CanApply(
 		}, nil
 	}
 
+	// Check policy dry-run
+	if policy.Spec.DryRun {
+		return &ApplyDecision{
+			CanApply: false,
+			Method:   Skip,
+			Reason:   "Policy dry-run is enabled",
+		}, nil
+	}
+
+	// The workload's update method annotation takes precedence over the policy's update strategy
+	policy, invalidMethod := withUpdateMethodOverride(workload, policy)
+	if invalidMethod != "" {
+		ctrl.LoggerFrom(ctx).Info("Ignoring invalid update method annotation, using the policy's update strategy",
+			"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+			"annotation", optipodv1alpha1.AnnotationUpdateMethod,
+			"value", invalidMethod)
+	}
+
+	// With increaseOnly, requests the recommendation would lower keep their current value
+	var suppressed []corev1.ResourceName
+	if policy.Spec.UpdateStrategy.IncreaseOnly {
+		currentResources, err := e.getCurrentResources(workload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current resources: %w", err)
+		}
+		rec, suppressed = increaseOnlyTarget(currentResources, containerName, rec, policy)
+		if len(suppressed) > 0 && !raisesRequests(currentResources[containerName], rec, policy) {
+			decision := decreaseSuppressedDecision(containerName, suppressed)
+			decision.InvalidUpdateMethod = invalidMethod
+			return decision, nil
+		}
+	}
+
+	// A recommendation within tolerance of the current requests is not worth a patch
+	if policy.Spec.UpdateStrategy.ApplyTolerance != nil {
+		currentResources, err := e.getCurrentResources(workload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current resources: %w", err)
+		}
+		if withinApplyTolerance(currentResources, containerName, rec, policy) {
+			decision := withinToleranceDecision(containerName)
+			decision.InvalidUpdateMethod = invalidMethod
+			decision.SuppressedDecreases = suppressed
+			return decision, nil
+		}
+	}
+
+	// Pods of OnDelete workloads keep their resources until they are deleted
+	if decision := onDeleteDecision(workload, policy); decision != nil {
+		decision.InvalidUpdateMethod = invalidMethod
+		decision.SuppressedDecreases = suppressed
+		return decision, nil
+	}
+
+	decision, err := e.decide(ctx, workload, containerName, rec, policy)
+	if decision != nil && decision.CanApply && onDeleteStrategy(workload) {
+		decision.Method = Recreate
+		decision.Reason = "OnDelete update strategy, pods are deleted one at a time"
+	}
+	if decision != nil && decision.CanApply && decision.Method == Recreate {
+		if cooldown := recreateCooldown(workload, policy, time.Now()); cooldown != nil {
+			decision = cooldown
+		}
+	}
+	if decision != nil {
+		decision.InvalidUpdateMethod = invalidMethod
+		decision.SuppressedDecreases = suppressed
+	}
+	return decision, err
+}
+
+// decide determines whether and how changes can be applied under the effective update strategy
+func (e *Engine) decide(
+	ctx context.Context,
+	workload *Workload,
+	containerName string,
+	rec *recommendation.Recommendation,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) (*ApplyDecision, error) {
 	// Get current container resources
 	currentResources, err := e.getCurrentResources(workload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current resources: %w", err)
 	}
 
+	// After an in-place resize the running pods differ from the template, so their resources
+	// are the true current values. Without consistent running pods the template is used.
+	live, ok, err := e.livePodResources(ctx, workload)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Info("Failed to read resources of running pods, using the pod template",
+			"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+			"error", err.Error())
+	} else if ok {
+		for name, reqs := range live {
+			if _, inTemplate := currentResources[name]; inTemplate {
+				currentResources[name] = reqs
+			}
+		}
+
+		// Patching again would be a no-op resize of every pod
+		if reqs, ok := live[containerName]; ok && sameResources(reqs, e.proposedResources(reqs, rec, policy)) {
+			return &ApplyDecision{
+				CanApply: false,
+				Method:   Skip,
+				Reason:   "Running pods already have the recommended resources",
+			}, nil
+		}
+	}
+
 	// Check for memory decrease safety
-	if e.isUnsafeMemoryDecrease(currentResources, rec) {
+	if policy.OptimizesMemory() && e.isUnsafeMemoryDecrease(currentResources, rec) {
 		return &ApplyDecision{
 			CanApply: false,
 			Method:   Skip,
 			Reason:   "Memory decrease could cause pod eviction or OOM",
+			Cause:    ErrUnsafeMemoryDecrease,
 		}, nil
 	}
 
@@ -149,11 +304,67 @@ func (e *Engine) CanApply(
 
 	// Determine apply method based on support and policy
 	if inPlaceSupported && policy.Spec.UpdateStrategy.AllowInPlaceResize {
-		// In-place is supported and allowed - prefer it
+		// Large changes recreate the pods instead of resizing them in-place
+		if exceeded := inPlaceBoundsExceeded(currentResources, containerName, rec, policy); exceeded != "" {
+			if !policy.Spec.UpdateStrategy.AllowRecreate {
+				return &ApplyDecision{
+					CanApply: false,
+					Method:   Skip,
+					Reason:   fmt.Sprintf("Change exceeds the in-place bounds (%s) and recreate is not allowed", exceeded),
+				}, nil
+			}
+			return &ApplyDecision{
+				CanApply:            true,
+				Method:              Recreate,
+				BeyondInPlaceBounds: true,
+				Reason:              fmt.Sprintf("Change exceeds the in-place bounds (%s), using recreate strategy", exceeded),
+			}, nil
+		}
+
+		conflict := e.inPlaceResizeConflict(currentResources, containerName, rec, policy)
+		if conflict == "" {
+			// A resize that restarts the container disrupts it, so it needs the same permission
+			// as recreating the pods
+			if restarts := e.resizeRestartResources(workload, currentResources, containerName, rec, policy); len(restarts) > 0 {
+				if !policy.Spec.UpdateStrategy.AllowRecreate {
+					return &ApplyDecision{
+						CanApply: false,
+						Method:   Skip,
+						Reason: fmt.Sprintf("In-place resize would restart container %s (%v resizePolicy is RestartContainer) "+
+							"and disruptive updates are not allowed", containerName, restarts),
+						Cause: ErrDisruptiveResize,
+					}, nil
+				}
+				return &ApplyDecision{
+					CanApply:          true,
+					Method:            InPlace,
+					RestartsContainer: true,
+					Reason: fmt.Sprintf("In-place resize restarts container %s (%v resizePolicy is RestartContainer), "+
+						"allowed because recreate is allowed", containerName, restarts),
+				}, nil
+			}
+
+			// In-place is supported and allowed - prefer it
+			return &ApplyDecision{
+				CanApply: true,
+				Method:   InPlace,
+				Reason:   "In-place resize is supported and allowed",
+			}, nil
+		}
+
+		// The API server would reject the in-place resize - degrade to recreate if allowed
+		if policy.Spec.UpdateStrategy.AllowRecreate {
+			return &ApplyDecision{
+				CanApply: true,
+				Method:   Recreate,
+				Reason:   fmt.Sprintf("In-place resize would be rejected (%s), using recreate strategy", conflict),
+			}, nil
+		}
+
 		return &ApplyDecision{
-			CanApply: true,
-			Method:   InPlace,
-			Reason:   "In-place resize is supported and allowed",
+			CanApply: false,
+			Method:   Skip,
+			Reason:   fmt.Sprintf("In-place resize would be rejected (%s) and recreate is not allowed", conflict),
 		}, nil
 	}
 
@@ -177,21 +388,9 @@ func (e *Engine) CanApply(
 
 // detectInPlaceResize detects if in-place pod resize is supported
 func (e *Engine) detectInPlaceResize(ctx context.Context) (bool, error) { //nolint:unparam // ctx may be used in future
-	// Get server version
-	serverVersion, err := e.discoveryClient.ServerVersion()
-	if err != nil {
-		return false, fmt.Errorf("failed to get server version: %w", err)
-	}
-
-	// Parse version
-	major, err := strconv.Atoi(serverVersion.Major)
-	if err != nil {
-		return false, fmt.Errorf("failed to parse major version: %w", err)
-	}
-
-	minor, err := strconv.Atoi(strings.TrimSuffix(serverVersion.Minor, "+"))
+	major, minor, err := e.serverVersion()
 	if err != nil {
-		return false, fmt.Errorf("failed to parse minor version: %w", err)
+		return false, err
 	}
 
 	// In-place resize is available in Kubernetes 1.29+ with feature gate
@@ -210,6 +409,26 @@ func (e *Engine) detectInPlaceResize(ctx context.Context) (bool, error) { //noli
 	return false, nil
 }
 
+// serverVersion returns the major and minor version of the Kubernetes API server
+func (e *Engine) serverVersion() (int, int, error) {
+	serverVersion, err := e.discoveryClient.ServerVersion()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get server version: %w", err)
+	}
+
+	major, err := strconv.Atoi(serverVersion.Major)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse major version: %w", err)
+	}
+
+	minor, err := strconv.Atoi(strings.TrimSuffix(serverVersion.Minor, "+"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse minor version: %w", err)
+	}
+
+	return major, minor, nil
+}
+
 // getCurrentResources extracts current resource requirements from a workload
 func (e *Engine) getCurrentResources(workload *Workload) (map[string]corev1.ResourceRequirements, error) {
 	resources := make(map[string]corev1.ResourceRequirements)
@@ -233,6 +452,18 @@ func (e *Engine) getCurrentResources(workload *Workload) (map[string]corev1.Reso
 		return nil, fmt.Errorf("failed to extract containers: %w", err)
 	}
 
+	// Native sidecars run alongside the containers for the lifetime of the pod. Run-once init
+	// containers have finished before the others start, so they are left out.
+	initContainers, err := templateContainers(workload.Object, fieldInitContainers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract init containers: %w", err)
+	}
+	for _, c := range initContainers {
+		if container, ok := c.(map[string]interface{}); ok && isNativeSidecar(container) {
+			containers = append(containers, container)
+		}
+	}
+
 	for _, c := range containers {
 		container, ok := c.(map[string]interface{})
 		if !ok {
@@ -280,6 +511,10 @@ func (e *Engine) getCurrentResources(workload *Workload) (map[string]corev1.Reso
 			}
 		}
 
+		if effective, ok := workload.EffectiveResources[name]; ok {
+			reqs = effective
+		}
+
 		resources[name] = reqs
 	}
 
@@ -303,62 +538,256 @@ func (e *Engine) isUnsafeMemoryDecrease(
 	return false
 }
 
+// ContainerChange is the recommendation to apply to one container of a workload
+type ContainerChange struct {
+	Container      string
+	Recommendation *recommendation.Recommendation
+
+	// Method is how CanApply decided the change is applied. It is recorded in the workload's
+	// last-apply-method annotation, which the recreate cooldown is measured from.
+	Method ApplyMethod
+
+	// Unchanged keeps the container in a Server-Side Apply with its current requests and managed
+	// limits, e.g. when its recommendation is within tolerance or only lowers requests under
+	// increaseOnly. Leaving it out of the apply would drop the fields optipod owns on it.
+	// Recommendation is not used, and other patch methods leave the container alone.
+	Unchanged bool
+
+	// keepGuaranteed sets the container's limits to its new requests, see keepsGuaranteed
+	keepGuaranteed bool
+}
+
+// ContainerResult is the outcome of applying a recommendation to one container
+type ContainerResult struct {
+	// Applied is the recommendation that was applied. It is an intermediate step toward the
+	// recommendation while the policy's convergence rate moves requests gradually.
+	Applied *recommendation.Recommendation
+
+	// Converged is false while further reconciles are needed to reach the recommendation
+	Converged bool
+
+	// RestartsContainer is true when the in-place resize of the applied requests restarts the
+	// container, see ApplyDecision.RestartsContainer
+	RestartsContainer bool
+
+	// QoSDowngraded is true when the apply makes a Guaranteed container Burstable, which the
+	// policy allows with allowQoSClassChange
+	QoSDowngraded bool
+}
+
 // ApplyResult contains information about the apply operation
 type ApplyResult struct {
 	Method         string // "ServerSideApply" or "StrategicMergePatch"
 	FieldOwnership bool   // true if SSA was used
+
+	// Containers holds the outcome for each container of the apply, by container name
+	Containers map[string]ContainerResult
+
+	// PartitionedRollout is the progress of the partitioned rollout the apply started, nil when
+	// the change rolls all pods at once
+	PartitionedRollout *optipodv1alpha1.PartitionedRolloutStatus
+
+	// OnDeleteRollout is true when the workload has the OnDelete update strategy and its pods are
+	// deleted one at a time on the following reconciles to roll the change out
+	OnDeleteRollout bool
+
+	// PodResources holds the pod-level resources the apply set, nil when the containers were
+	// sized individually
+	PodResources *corev1.ResourceRequirements
+
+	// PodResourcesUnchanged is true when nothing was patched because the pod-level resources for
+	// the changes are within tolerance of the pod template's. PodResources then holds the pod
+	// template's resources.
+	PodResourcesUnchanged bool
 }
 
-// Apply applies resource recommendations using the configured patch strategy
+// Apply applies the recommendations of a workload's containers using the configured patch
+// strategy. All containers are updated by a single patch, so a workload is never left with only
+// some of its containers resized and its pods are rolled at most once.
+//
+// A patch that conflicts with a concurrent writer is retried within the conflict retry budget,
+// each time with the patch rebuilt from the latest version of the workload.
 func (e *Engine) Apply(
 	ctx context.Context,
 	workload *Workload,
-	containerName string,
-	rec *recommendation.Recommendation,
+	changes []ContainerChange,
 	policy *optipodv1alpha1.OptimizationPolicy,
 ) (*ApplyResult, error) {
-	// Determine if SSA should be used (default to true if not specified)
-	useSSA := true
-	if policy.Spec.UpdateStrategy.UseServerSideApply != nil {
-		useSSA = *policy.Spec.UpdateStrategy.UseServerSideApply
+	return e.retryTransient(ctx, workload, func(workload *Workload) (*ApplyResult, error) {
+		return e.apply(ctx, workload, changes, policy)
+	})
+}
+
+// apply makes a single attempt at applying the changes to the workload as it was read
+func (e *Engine) apply(
+	ctx context.Context,
+	workload *Workload,
+	changes []ContainerChange,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) (*ApplyResult, error) {
+	// Apply with the same update strategy CanApply decided under
+	policy, _ = withUpdateMethodOverride(workload, policy)
+
+	// Determine if SSA should be used for the workload's kind (default to true if not specified)
+	useSSA := policy.Spec.UpdateStrategy.UsesServerSideApply(workload.Kind)
+
+	// With a convergence rate, only a step toward each recommendation is applied. The current
+	// resources also tell which containers are Guaranteed and which in-place resizes restart a
+	// container.
+	currentResources, err := e.getCurrentResources(workload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current resources: %w", err)
+	}
+
+	running := e.runningResources(ctx, workload, currentResources)
+
+	result := &ApplyResult{Containers: make(map[string]ContainerResult, len(changes))}
+	targets := make([]ContainerChange, 0, len(changes))
+	for _, change := range changes {
+		if change.Unchanged {
+			targets = append(targets, ContainerChange{
+				Container:      change.Container,
+				Unchanged:      true,
+				keepGuaranteed: keepsGuaranteed(running[change.Container], policy),
+			})
+			continue
+		}
+
+		target, converged := change.Recommendation, true
+		if policy.Spec.UpdateStrategy.ConvergenceRate != nil {
+			target, converged = convergenceTarget(currentResources, change.Container, change.Recommendation, policy)
+			if !converged {
+				ctrl.LoggerFrom(ctx).Info("Converging toward recommendation",
+					"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+					"container", change.Container,
+					"cpu", target.CPU.String(),
+					"memory", target.Memory.String(),
+				)
+			}
+		}
+		target, _ = increaseOnlyTarget(currentResources, change.Container, target, policy)
+		current := running[change.Container]
+		targets = append(targets, ContainerChange{
+			Container:      change.Container,
+			Recommendation: target,
+			Method:         change.Method,
+			keepGuaranteed: keepsGuaranteed(current, policy),
+		})
+
+		downgraded := isGuaranteedContainer(current) && !isGuaranteedContainer(e.proposedResources(current, target, policy))
+		if downgraded {
+			ctrl.LoggerFrom(ctx).Info("Changing the QoS class of a Guaranteed container to Burstable, as the policy allows",
+				"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+				"container", change.Container,
+			)
+		}
+		result.Containers[change.Container] = ContainerResult{Applied: target, Converged: converged, QoSDowngraded: downgraded}
+	}
+	e.markContainerRestarts(ctx, workload, currentResources, result, policy)
+	if partition, _, ok := startPartition(workload, policy); ok {
+		result.PartitionedRollout = &optipodv1alpha1.PartitionedRolloutStatus{
+			Partition: int32(partition),
+			Replicas:  int32(statefulSetReplicas(workload.Object)),
+		}
+	}
+	result.OnDeleteRollout = onDeleteRollout(workload, policy)
+
+	// Pod-level resources are set together with removing the containers' own, which only a
+	// strategic merge patch can do for values other field managers own
+	if pod := e.podResources(ctx, workload, currentResources, targets, policy); pod != nil {
+		// The containers no longer have their own requests to compare against, so the tolerance
+		// is checked at the pod level
+		if current, ok := podResourcesWithinTolerance(workload, currentResources, targets, pod, policy); ok {
+			result.PodResources = current
+			result.PodResourcesUnchanged = true
+			return result, nil
+		}
+		if err := e.applyPodResources(ctx, workload, targets, pod, policy); err != nil {
+			return nil, err
+		}
+		result.Method = "StrategicMergePatch"
+		result.FieldOwnership = false
+		result.PodResources = pod
+		return result, nil
+	}
+
+	// Container requests may not exceed the limits the apply leaves in place
+	for i, target := range targets {
+		if target.Unchanged {
+			continue
+		}
+		rec, capped := keptLimitsTarget(currentResources[target.Container], target.Recommendation, policy, target.keepGuaranteed)
+		if len(capped) == 0 {
+			continue
+		}
+		ctrl.LoggerFrom(ctx).Info("Capping requests at the container's limits, which the policy does not update",
+			"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+			"container", target.Container,
+			"resources", capped,
+		)
+		targets[i].Recommendation = rec
+		containerResult := result.Containers[target.Container]
+		containerResult.Applied = rec
+		result.Containers[target.Container] = containerResult
 	}
 
 	if useSSA {
-		err := e.ApplyWithSSA(ctx, workload, containerName, rec, policy)
+		err = e.ApplyWithSSA(ctx, workload, targets, policy)
 		if err != nil {
 			return nil, err
 		}
-		return &ApplyResult{
-			Method:         "ServerSideApply",
-			FieldOwnership: true,
-		}, nil
+		result.Method = "ServerSideApply"
+		result.FieldOwnership = true
+		return result, nil
 	}
 
 	// Fall back to Strategic Merge Patch
-	err := e.ApplyWithStrategicMerge(ctx, workload, containerName, rec, policy)
+	err = e.ApplyWithStrategicMerge(ctx, workload, targets, policy)
 	if err != nil {
 		return nil, err
 	}
-	return &ApplyResult{
-		Method:         "StrategicMergePatch",
-		FieldOwnership: false,
-	}, nil
+	result.Method = "StrategicMergePatch"
+	result.FieldOwnership = false
+	return result, nil
+}
+
+// describeChanges summarizes container changes for logging, e.g. "app cpu=250m memory=256Mi"
+func describeChanges(changes []ContainerChange) []string {
+	descriptions := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if change.Unchanged {
+			descriptions = append(descriptions, change.Container+" unchanged")
+			continue
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s cpu=%s memory=%s",
+			change.Container, change.Recommendation.CPU.String(), change.Recommendation.Memory.String()))
+	}
+	return descriptions
 }
 
 // ApplyWithStrategicMerge applies resource recommendations using Strategic Merge Patch
 func (e *Engine) ApplyWithStrategicMerge(
 	ctx context.Context,
 	workload *Workload,
-	containerName string,
-	rec *recommendation.Recommendation,
+	changes []ContainerChange,
 	policy *optipodv1alpha1.OptimizationPolicy,
 ) error {
 	// Build JSON patch for resource requests
-	patch, err := e.buildResourcePatch(workload, containerName, rec, policy)
+	patch, err := e.buildResourcePatch(workload, changes, policy)
 	if err != nil {
 		return fmt.Errorf("failed to build patch: %w", err)
 	}
 
+	return e.patchWorkload(ctx, workload, patch, policy)
+}
+
+// patchWorkload sends a strategic merge patch to the workload, classifying the errors it fails with
+func (e *Engine) patchWorkload(
+	ctx context.Context,
+	workload *Workload,
+	patch []byte,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) error {
 	// Get the appropriate GVR for the workload
 	gvr, err := e.getGVR(workload.Kind)
 	if err != nil {
@@ -384,9 +813,18 @@ func (e *Engine) ApplyWithStrategicMerge(
 			"failure",
 			"StrategicMergePatch",
 		)
-		// Check for RBAC errors
+		// Classify conflict, quota, RBAC and validation errors
+		if errors.IsConflict(err) {
+			return fmt.Errorf("%w: the workload was modified concurrently: %w", ErrPatchConflict, err)
+		}
+		if isQuotaExceeded(err) {
+			return fmt.Errorf("%w: patch would exceed a resource quota: %w", ErrQuotaExceeded, err)
+		}
 		if errors.IsForbidden(err) {
-			return fmt.Errorf("RBAC: insufficient permissions to update workload: %w", err)
+			return fmt.Errorf("%w: insufficient permissions to update workload: %w", ErrRBACDenied, err)
+		}
+		if errors.IsInvalid(err) {
+			return fmt.Errorf("failed to patch workload: %w: %w", ErrInvalidPatch, err)
 		}
 		return fmt.Errorf("failed to patch workload: %w", err)
 	}
@@ -404,12 +842,12 @@ func (e *Engine) ApplyWithStrategicMerge(
 	return nil
 }
 
-// ApplyWithSSA applies resource recommendations using Server-Side Apply
+// ApplyWithSSA applies resource recommendations using Server-Side Apply. The resources of all
+// containers are sent in a single apply request.
 func (e *Engine) ApplyWithSSA(
 	ctx context.Context,
 	workload *Workload,
-	containerName string,
-	rec *recommendation.Recommendation,
+	changes []ContainerChange,
 	policy *optipodv1alpha1.OptimizationPolicy,
 ) error {
 	log := ctrl.LoggerFrom(ctx)
@@ -417,15 +855,13 @@ func (e *Engine) ApplyWithSSA(
 	log.Info("Applying resource changes using Server-Side Apply",
 		"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
 		"kind", workload.Kind,
-		"container", containerName,
+		"containers", describeChanges(changes),
 		"fieldManager", "optipod",
 		"force", true,
-		"cpu", rec.CPU.String(),
-		"memory", rec.Memory.String(),
 	)
 
 	// Build SSA patch
-	patch, err := e.buildSSAPatch(workload, containerName, rec, policy)
+	patch, err := e.buildSSAPatch(workload, changes, policy)
 	if err != nil {
 		log.Error(err, "Failed to build SSA patch",
 			"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
@@ -444,7 +880,7 @@ func (e *Engine) ApplyWithSSA(
 	}
 
 	// Apply using Server-Side Apply
-	_, err = e.dynamicClient.Resource(gvr).Namespace(workload.Namespace).Patch(
+	applied, err := e.dynamicClient.Resource(gvr).Namespace(workload.Namespace).Patch(
 		ctx,
 		workload.Name,
 		types.ApplyPatchType,
@@ -472,11 +908,40 @@ func (e *Engine) ApplyWithSSA(
 		return e.handleSSAError(err)
 	}
 
+	for _, change := range changes {
+		// Limits owned by another field manager survive an apply that omits them
+		if policy.Spec.UpdateStrategy.RemoveLimits && !change.keepGuaranteed && !change.Unchanged {
+			if err := e.removeRemainingLimits(ctx, gvr, workload, applied, change.Container, policy); err != nil {
+				observability.RecordSSAPatch(
+					policy.Name,
+					workload.Namespace,
+					workload.Name,
+					workload.Kind,
+					"failure",
+					"ServerSideApply",
+				)
+				return err
+			}
+		}
+
+		// Resources the policy does not optimize, and limits it does not manage, are omitted from
+		// the apply, which drops them when optipod owned them from an earlier apply
+		if err := e.restoreUntouchedResources(ctx, gvr, workload, applied, change, policy); err != nil {
+			observability.RecordSSAPatch(
+				policy.Name,
+				workload.Namespace,
+				workload.Name,
+				workload.Kind,
+				"failure",
+				"ServerSideApply",
+			)
+			return err
+		}
+	}
+
 	log.Info("Successfully applied resource changes via SSA",
 		"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
-		"container", containerName,
-		"cpu", rec.CPU.String(),
-		"memory", rec.Memory.String(),
+		"containers", describeChanges(changes),
 	)
 
 	// Record successful SSA patch
@@ -492,19 +957,27 @@ func (e *Engine) ApplyWithSSA(
 	return nil
 }
 
-// handleSSAError processes SSA-specific errors and provides helpful messages
+// handleSSAError processes SSA-specific errors and provides helpful messages.
+// Known causes are wrapped with the matching classified error.
 func (e *Engine) handleSSAError(err error) error {
+	if errors.HasStatusCause(err, metav1.CauseTypeFieldManagerConflict) {
+		return fmt.Errorf("%w: another field manager owns these fields. "+
+			"This may indicate a configuration issue. Error: %w", ErrSSAConflict, err)
+	}
 	if errors.IsConflict(err) {
-		return fmt.Errorf("SSA conflict: another field manager owns these fields. "+
-			"This may indicate a configuration issue. Error: %w", err)
+		return fmt.Errorf("%w: the workload was modified since it was read: %w", ErrPatchConflict, err)
+	}
+
+	if isQuotaExceeded(err) {
+		return fmt.Errorf("%w: Server-Side Apply would exceed a resource quota: %w", ErrQuotaExceeded, err)
 	}
 
 	if errors.IsForbidden(err) {
-		return fmt.Errorf("RBAC: insufficient permissions for Server-Side Apply: %w", err)
+		return fmt.Errorf("%w: insufficient permissions for Server-Side Apply: %w", ErrRBACDenied, err)
 	}
 
 	if errors.IsInvalid(err) {
-		return fmt.Errorf("SSA patch validation failed: %w", err)
+		return fmt.Errorf("SSA %w: %w", ErrInvalidPatch, err)
 	}
 
 	return fmt.Errorf("SSA patch failed: %w", err)
@@ -531,10 +1004,29 @@ func (e *Engine) calculateLimits(rec *recommendation.Recommendation, policy *opt
 		}
 	}
 
+	// Ratios derived from the observed burst ratio replace the multipliers
+	if rec.CPULimitRatio > 0 {
+		cpuMultiplier = rec.CPULimitRatio
+	}
+	if rec.MemoryLimitRatio > 0 {
+		memoryMultiplier = rec.MemoryLimitRatio
+	}
+
 	// Calculate limits by multiplying recommendations
 	cpuLimit := multiplyQuantity(rec.CPU, cpuMultiplier)
 	memoryLimit := multiplyQuantity(rec.Memory, memoryMultiplier)
 
+	// A percentile-derived memory limit replaces the multiplier-based one
+	if !rec.MemoryLimit.IsZero() {
+		memoryLimit = rec.MemoryLimit.DeepCopy()
+	}
+
+	// Never set a memory limit below observed P99 usage, as that would cause OOM kills.
+	// CPU is compressible, so its limit needs no such floor.
+	if !rec.ObservedMemoryP99.IsZero() && memoryLimit.Cmp(rec.ObservedMemoryP99) < 0 {
+		memoryLimit = rec.ObservedMemoryP99.DeepCopy()
+	}
+
 	return cpuLimit, memoryLimit
 }
 
@@ -557,34 +1049,85 @@ func multiplyQuantity(q resource.Quantity, factor float64) resource.Quantity {
 	}
 }
 
-// buildResourcePatch builds a JSON patch for updating resource requests
+// buildResourcePatch builds a JSON patch for updating the resource requests of the changed containers
 func (e *Engine) buildResourcePatch(
 	workload *Workload,
-	containerName string,
-	rec *recommendation.Recommendation,
+	changes []ContainerChange,
 	policy *optipodv1alpha1.OptimizationPolicy,
 ) ([]byte, error) {
-	// Extract containers
-	var containers []interface{}
-	var err error
-
 	switch workload.Kind {
-	case kindDeployment:
-		containers, _, err = unstructured.NestedSlice(workload.Object.Object, "spec", "template", "spec", "containers")
-	case kindStatefulSet:
-		containers, _, err = unstructured.NestedSlice(workload.Object.Object, "spec", "template", "spec", "containers")
-	case kindDaemonSet:
-		containers, _, err = unstructured.NestedSlice(workload.Object.Object, "spec", "template", "spec", "containers")
+	case kindDeployment, kindStatefulSet, kindDaemonSet:
 	default:
 		return nil, fmt.Errorf("unsupported workload kind: %s", workload.Kind)
 	}
 
+	recs := make(map[string]*recommendation.Recommendation, len(changes))
+	guaranteed := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		// A strategic merge patch keeps what it does not mention, so unchanged containers are left out
+		if change.Unchanged {
+			continue
+		}
+		recs[change.Container] = change.Recommendation
+		guaranteed[change.Container] = change.keepGuaranteed
+	}
+
+	// Find and update the target containers. Init containers, including native sidecars, are
+	// only part of the patch when one of them changes.
+	spec := make(map[string]interface{}, 2)
+	for _, field := range sizedContainerFields {
+		containers, err := templateContainers(workload.Object, field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s: %w", field, err)
+		}
+		if e.updateContainerResources(containers, recs, guaranteed, policy) || field == fieldContainers {
+			spec[field] = containers
+		}
+	}
+
+	for _, change := range changes {
+		if _, missing := recs[change.Container]; missing && !change.Unchanged {
+			return nil, fmt.Errorf("container %s not found in workload", change.Container)
+		}
+	}
+
+	// Build the patch; the last-applied annotation is part of the same request so a
+	// patched template always carries it
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": lastAppliedAnnotations(changes),
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": spec,
+			},
+		},
+	}
+	// A partitioned rollout only updates the pods with the highest ordinals at first
+	if partition, floor, ok := startPartition(workload, policy); ok {
+		withPartition(patch, partition, floor)
+	}
+
+	// Convert to JSON
+	patchUnstructured := &unstructured.Unstructured{Object: patch}
+	patchBytes, err := patchUnstructured.MarshalJSON()
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract containers: %w", err)
+		return nil, fmt.Errorf("failed to encode patch: %w", err)
 	}
 
-	// Find and update the target container
-	found := false
+	return patchBytes, nil
+}
+
+// updateContainerResources sets the resources of each container with a recommendation in recs,
+// removing it from recs. The limits of the containers in guaranteed are set to their requests.
+// It reports whether any container was updated.
+func (e *Engine) updateContainerResources(
+	containers []interface{},
+	recs map[string]*recommendation.Recommendation,
+	guaranteed map[string]bool,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) bool {
+	updated := false
 	for i, c := range containers {
 		container, ok := c.(map[string]interface{})
 		if !ok {
@@ -592,58 +1135,59 @@ func (e *Engine) buildResourcePatch(
 		}
 
 		name, _, _ := unstructured.NestedString(container, "name")
-		if name != containerName {
+		rec, ok := recs[name]
+		if !ok {
 			continue
 		}
 
-		found = true
+		delete(recs, name)
 
 		// Build new resources map with only what we want to update
 		resourcesMap := make(map[string]interface{})
 
-		// Always update requests
-		requestsMap := make(map[string]interface{})
-		requestsMap["cpu"] = rec.CPU.String()
-		requestsMap["memory"] = rec.Memory.String()
-		resourcesMap["requests"] = requestsMap
-
-		// Update limits only if configured to do so
-		if !policy.Spec.UpdateStrategy.UpdateRequestsOnly {
+		// Always update requests of the resources the policy optimizes
+		resourcesMap["requests"] = optimizedValues(policy, rec.CPU.String(), rec.Memory.String())
+
+		// Remove or update limits only if configured to do so. A strategic merge patch keeps
+		// keys it does not mention, so removed limits are set to null explicitly and kept limits
+		// are left out.
+		if guaranteed[name] {
+			resourcesMap["limits"] = optimizedValues(policy, rec.CPU.String(), rec.Memory.String())
+		} else if policy.Spec.UpdateStrategy.RemoveLimits {
+			if limits := limitValues(policy, nil, nil); len(limits) > 0 {
+				resourcesMap["limits"] = limits
+			}
+		} else if !policy.Spec.UpdateStrategy.UpdateRequestsOnly && !rec.KeepLimits {
 			cpuLimit, memoryLimit := e.calculateLimits(rec, policy)
-			limitsMap := make(map[string]interface{})
-			limitsMap["cpu"] = cpuLimit.String()
-			limitsMap["memory"] = memoryLimit.String()
-			resourcesMap["limits"] = limitsMap
+			if limits := limitValues(policy, cpuLimit.String(), memoryLimit.String()); len(limits) > 0 {
+				resourcesMap["limits"] = limits
+			}
 		}
 
 		container["resources"] = resourcesMap
 		containers[i] = container
-		break
+		updated = true
 	}
+	return updated
+}
 
-	if !found {
-		return nil, fmt.Errorf("container %s not found in workload", containerName)
+// lastAppliedAnnotations returns the annotations recording when and how resources were last
+// applied. They are sent in the same patch as the resource change, so the change and its
+// annotations are written atomically.
+func lastAppliedAnnotations(changes []ContainerChange) map[string]interface{} {
+	now := time.Now().UTC().Format(time.RFC3339)
+	annotations := map[string]interface{}{
+		optipodv1alpha1.AnnotationLastApplied: now,
 	}
-
-	// Build the patch
-	patch := map[string]interface{}{
-		"spec": map[string]interface{}{
-			"template": map[string]interface{}{
-				"spec": map[string]interface{}{
-					"containers": containers,
-				},
-			},
-		},
+	method := workloadApplyMethod(changes)
+	if method != "" {
+		annotations[optipodv1alpha1.AnnotationLastApplyMethod] = string(method)
 	}
-
-	// Convert to JSON
-	patchUnstructured := &unstructured.Unstructured{Object: patch}
-	patchBytes, err := patchUnstructured.MarshalJSON()
-	if err != nil {
-		return nil, fmt.Errorf("failed to encode patch: %w", err)
+	// Only a change that recreates the pods starts a recreate cooldown
+	if method == Recreate {
+		annotations[optipodv1alpha1.AnnotationLastRecreated] = now
 	}
-
-	return patchBytes, nil
+	return annotations
 }
 
 // getGVR returns the GroupVersionResource for a workload kind
@@ -677,31 +1221,76 @@ func (e *Engine) getKind(workloadKind string) string {
 	return workloadKind
 }
 
-// buildSSAPatch constructs a Server-Side Apply patch containing only resource fields
+// buildSSAPatch constructs a Server-Side Apply patch containing only the resource fields of the
+// changed containers, each identified by name.
+//
+// An apply sets exactly the fields optipod owns, so every container optipod manages must be in
+// the same patch: containers left out would lose the resources optipod applied to them earlier.
+// Unchanged containers are applied with their current values for that reason.
 func (e *Engine) buildSSAPatch(
 	workload *Workload,
-	containerName string,
-	rec *recommendation.Recommendation,
+	changes []ContainerChange,
 	policy *optipodv1alpha1.OptimizationPolicy,
 ) ([]byte, error) {
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("no container changes to apply")
+	}
+
 	// Determine kind (API version is always apps/v1 for workloads)
 	kind := e.getKind(workload.Kind)
 
-	// Build resources map
-	resources := map[string]interface{}{
-		"requests": map[string]interface{}{
-			"cpu":    rec.CPU.String(),
-			"memory": rec.Memory.String(),
-		},
-	}
+	// Each container goes into the pod template list holding it, so native sidecars and other
+	// init containers are applied under initContainers. An apply would add a container it does
+	// not find, e.g. an ephemeral container, so only containers of the template are applied.
+	containers := make(map[string][]interface{}, 2)
+	for _, change := range changes {
+		if _, ok := findTemplateContainer(workload.Object, change.Container); !ok {
+			return nil, fmt.Errorf("container %s not found in workload", change.Container)
+		}
+		field := containerField(workload.Object, change.Container)
+		if change.Unchanged {
+			containers[field] = append(containers[field], map[string]interface{}{
+				"name":      change.Container,
+				"resources": currentResourceValues(workload, change, policy),
+			})
+			continue
+		}
+		rec := change.Recommendation
+
+		// Build resources map. Resources the policy does not optimize are left out entirely.
+		resources := map[string]interface{}{
+			"requests": optimizedValues(policy, rec.CPU.String(), rec.Memory.String()),
+		}
 
-	// Include limits if configured
-	if !policy.Spec.UpdateStrategy.UpdateRequestsOnly {
-		cpuLimit, memoryLimit := e.calculateLimits(rec, policy)
-		resources["limits"] = map[string]interface{}{
-			"cpu":    cpuLimit.String(),
-			"memory": memoryLimit.String(),
+		// Include limits if configured. With removeLimits they are left out, which releases
+		// optipod's ownership and removes limits no other field manager owns; limits limitConfig
+		// does not manage are restored after the apply. Guaranteed containers are limited to
+		// their requests, and a recommendation keeping the limits applies the current ones.
+		switch {
+		case change.keepGuaranteed:
+			resources["limits"] = optimizedValues(policy, rec.CPU.String(), rec.Memory.String())
+		case policy.Spec.UpdateStrategy.UpdateRequestsOnly || policy.Spec.UpdateStrategy.RemoveLimits:
+		case rec.KeepLimits:
+			current := containerResourceFields(workload.Object, change.Container)
+			if limits := presentValues(limitValues(policy, nil, nil), current["limits"]); len(limits) > 0 {
+				resources["limits"] = limits
+			}
+		default:
+			cpuLimit, memoryLimit := e.calculateLimits(rec, policy)
+			if limits := limitValues(policy, cpuLimit.String(), memoryLimit.String()); len(limits) > 0 {
+				resources["limits"] = limits
+			}
 		}
+
+		containers[field] = append(containers[field], map[string]interface{}{
+			"name":      change.Container,
+			"resources": resources,
+		})
+	}
+
+	spec := make(map[string]interface{}, len(containers))
+	for field, list := range containers {
+		spec[field] = list
 	}
 
 	// Build minimal patch with only resource fields
@@ -709,22 +1298,26 @@ func (e *Engine) buildSSAPatch(
 		"apiVersion": "apps/v1",
 		"kind":       kind,
 		"metadata": map[string]interface{}{
-			"name":      workload.Name,
-			"namespace": workload.Namespace,
+			"name":        workload.Name,
+			"namespace":   workload.Namespace,
+			"annotations": lastAppliedAnnotations(changes),
 		},
 		"spec": map[string]interface{}{
 			"template": map[string]interface{}{
-				"spec": map[string]interface{}{
-					"containers": []map[string]interface{}{
-						{
-							"name":      containerName,
-							"resources": resources,
-						},
-					},
-				},
+				"spec": spec,
 			},
 		},
 	}
+	// A partitioned rollout only updates the pods with the highest ordinals at first
+	if partition, floor, ok := startPartition(workload, policy); ok {
+		withPartition(patch, partition, floor)
+	}
+
+	// The apply is rejected if the workload changed since it was read, so a replica that lost
+	// leadership without noticing yet cannot overwrite the changes of the new leader
+	if resourceVersion := workload.Object.GetResourceVersion(); resourceVersion != "" {
+		patch["metadata"].(map[string]interface{})["resourceVersion"] = resourceVersion
+	}
 
 	// Serialize to JSON
 	patchUnstructured := &unstructured.Unstructured{Object: patch}
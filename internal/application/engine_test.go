@@ -19,12 +19,16 @@ package application
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,6 +40,7 @@ import (
 	"k8s.io/client-go/dynamic"
 
 	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
 	"github.com/optipod/optipod/internal/recommendation"
 )
 
@@ -152,7 +157,7 @@ func TestProperty_InPlaceResizePreference(t *testing.T) {
 			// Create a mock recommendation
 			rec := createMockRecommendation()
 
-			decision, err := engine.CanApply(context.Background(), workload, rec, policy)
+			decision, err := engine.CanApply(context.Background(), workload, "test-container", rec, policy)
 			if err != nil {
 				return false
 			}
@@ -203,7 +208,7 @@ func TestProperty_InPlaceResizePreference(t *testing.T) {
 			// Create a mock recommendation
 			rec := createMockRecommendation()
 
-			decision, err := engine.CanApply(context.Background(), workload, rec, policy)
+			decision, err := engine.CanApply(context.Background(), workload, "test-container", rec, policy)
 			if err != nil {
 				return false
 			}
@@ -293,6 +298,20 @@ func createMockWorkload() *Workload {
 	}
 }
 
+// createMockWorkloadWithResources returns the mock workload with the resources of its container
+// replaced, or removed when resources is nil
+func createMockWorkloadWithResources(resources map[string]interface{}) *Workload {
+	workload := createMockWorkload()
+	containers, _, _ := unstructured.NestedSlice(workload.Object.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	delete(container, "resources")
+	if resources != nil {
+		container["resources"] = resources
+	}
+	_ = unstructured.SetNestedSlice(workload.Object.Object, containers, "spec", "template", "spec", "containers")
+	return workload
+}
+
 func createMockRecommendation() *recommendation.Recommendation {
 	return &recommendation.Recommendation{
 		CPU:         resource.MustParse("600m"),
@@ -364,7 +383,7 @@ func TestProperty_UpdatesPreserveLimits(t *testing.T) {
 			engine := &Engine{}
 
 			// Build patch
-			patch, err := engine.buildResourcePatch(workload, "test-container", rec, policy)
+			patch, err := engine.buildResourcePatch(workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -456,7 +475,7 @@ func TestProperty_UpdatesPreserveLimits(t *testing.T) {
 			engine := &Engine{}
 
 			// Build patch
-			patch, err := engine.buildResourcePatch(workload, "test-container", rec, policy)
+			patch, err := engine.buildResourcePatch(workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -553,15 +572,14 @@ func TestProperty_RBACRespect(t *testing.T) {
 			rec := createMockRecommendation()
 			policy := createMockPolicy(true, false)
 
-			_, err := engine.Apply(context.Background(), workload, "test-container", rec, policy)
+			_, err := engine.Apply(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 
 			// When error code is 403, should get RBAC error
 			if errorCode == 403 {
 				if err == nil {
 					return false
 				}
-				// Check that error message mentions RBAC
-				return contains(err.Error(), "RBAC") || contains(err.Error(), "insufficient permissions")
+				return stderrors.Is(err, ErrRBACDenied)
 			}
 
 			// For other error codes, behavior may vary
@@ -648,7 +666,7 @@ func TestProperty_SSAPatchStructure(t *testing.T) {
 			engine := &Engine{}
 
 			// Build SSA patch
-			patch, err := engine.buildSSAPatch(workload, "test-container", rec, policy)
+			patch, err := engine.buildSSAPatch(workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -716,16 +734,16 @@ func TestProperty_SSAPatchStructure(t *testing.T) {
 }
 
 // Feature: server-side-apply-support, Property 4: Container identification in patch
-// For any container name, the SSA patch should correctly identify the container by name
-// in the containers array
+// For any set of container names, the SSA patch should identify every container by name in the
+// containers array and carry each container's own recommendation
 // Validates: Requirements 3.3
 func TestProperty_ContainerIdentification(t *testing.T) {
 	properties := gopter.NewProperties(nil)
 
-	properties.Property("SSA patch identifies container by name", prop.ForAll(
-		func(containerName string, cpuReq, memReq int64) bool {
+	properties.Property("SSA patch identifies every container by name", prop.ForAll(
+		func(baseName string, count int, cpuReq, memReq int64) bool {
 			// Generate valid container names (alphanumeric and hyphens)
-			if containerName == "" || len(containerName) > 63 {
+			if baseName == "" || len(baseName) > 60 {
 				return true // Skip invalid names
 			}
 
@@ -734,7 +752,29 @@ func TestProperty_ContainerIdentification(t *testing.T) {
 				return true // Skip invalid values
 			}
 
-			// Create workload with the specific container name
+			// Create a workload with count containers, each with its own recommendation
+			workloadContainers := make([]interface{}, 0, count)
+			changes := make([]ContainerChange, 0, count)
+			for i := range count {
+				name := fmt.Sprintf("%s-%d", baseName, i)
+				workloadContainers = append(workloadContainers, map[string]interface{}{
+					"name": name,
+					"resources": map[string]interface{}{
+						"requests": map[string]interface{}{
+							"cpu":    "500m",
+							"memory": "512Mi",
+						},
+					},
+				})
+				changes = append(changes, ContainerChange{
+					Container: name,
+					Recommendation: &recommendation.Recommendation{
+						CPU:         resource.MustParse(fmt.Sprintf("%dm", cpuReq+int64(i))),
+						Memory:      resource.MustParse(fmt.Sprintf("%dMi", memReq)),
+						Explanation: "Test recommendation",
+					},
+				})
+			}
 			workload := &Workload{
 				Kind:      "Deployment",
 				Namespace: "default",
@@ -744,17 +784,7 @@ func TestProperty_ContainerIdentification(t *testing.T) {
 						"spec": map[string]interface{}{
 							"template": map[string]interface{}{
 								"spec": map[string]interface{}{
-									"containers": []interface{}{
-										map[string]interface{}{
-											"name": containerName,
-											"resources": map[string]interface{}{
-												"requests": map[string]interface{}{
-													"cpu":    "500m",
-													"memory": "512Mi",
-												},
-											},
-										},
-									},
+									"containers": workloadContainers,
 								},
 							},
 						},
@@ -762,20 +792,13 @@ func TestProperty_ContainerIdentification(t *testing.T) {
 				},
 			}
 
-			// Create recommendation
-			rec := &recommendation.Recommendation{
-				CPU:         resource.MustParse(fmt.Sprintf("%dm", cpuReq)),
-				Memory:      resource.MustParse(fmt.Sprintf("%dMi", memReq)),
-				Explanation: "Test recommendation",
-			}
-
 			// Create policy
 			policy := createMockPolicy(true, false)
 
 			engine := &Engine{}
 
 			// Build SSA patch
-			patch, err := engine.buildSSAPatch(workload, containerName, rec, policy)
+			patch, err := engine.buildSSAPatch(workload, changes, policy)
 			if err != nil {
 				return false
 			}
@@ -786,25 +809,33 @@ func TestProperty_ContainerIdentification(t *testing.T) {
 				return false
 			}
 
-			// Extract containers from patch
+			// Extract containers from patch; every container is in the single patch
 			containers, _, _ := unstructured.NestedSlice(patchObj, "spec", "template", "spec", "containers")
-			if len(containers) == 0 {
+			if len(containers) != count {
 				return false
 			}
 
-			container := containers[0].(map[string]interface{})
+			for i, c := range containers {
+				container := c.(map[string]interface{})
 
-			// Verify container name matches
-			name, ok := container["name"].(string)
-			if !ok {
-				return false
-			}
+				// Verify container name matches
+				name, ok := container["name"].(string)
+				if !ok || name != changes[i].Container {
+					return false
+				}
 
-			return name == containerName
+				// Verify the container carries its own recommendation
+				cpu, _, _ := unstructured.NestedString(container, "resources", "requests", "cpu")
+				if cpu != changes[i].Recommendation.CPU.String() {
+					return false
+				}
+			}
+			return true
 		},
 		gen.AlphaString().SuchThat(func(s string) bool {
-			return len(s) > 0 && len(s) <= 63
+			return len(s) > 0 && len(s) <= 60
 		}),
+		gen.IntRange(1, 6),
 		gen.Int64Range(100, 4000),
 		gen.Int64Range(128, 8192),
 	))
@@ -812,6 +843,61 @@ func TestProperty_ContainerIdentification(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+// Feature: server-side-apply-support, Property 4b: Multi-container atomic apply
+// For any number of managed containers, Apply sends a single Server-Side Apply request covering
+// all of them and reports the applied recommendation of each
+func TestProperty_MultiContainerSingleApply(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("all containers are applied in one request", prop.ForAll(
+		func(count int, cpuReq int64) bool {
+			workload := createMockWorkload()
+			containers := make([]interface{}, 0, count)
+			changes := make([]ContainerChange, 0, count)
+			for i := range count {
+				name := fmt.Sprintf("container-%d", i)
+				containers = append(containers, map[string]interface{}{"name": name})
+				changes = append(changes, ContainerChange{
+					Container: name,
+					Recommendation: &recommendation.Recommendation{
+						CPU:    *resource.NewMilliQuantity(cpuReq+int64(i), resource.DecimalSI),
+						Memory: resource.MustParse("256Mi"),
+					},
+				})
+			}
+			_ = unstructured.SetNestedSlice(workload.Object.Object, containers, "spec", "template", "spec", "containers")
+
+			dynamicClient := &mockDynamicClientWithResult{result: workload.Object}
+			engine := &Engine{dynamicClient: dynamicClient}
+
+			result, err := engine.Apply(context.Background(), workload, changes, createMockPolicy(true, false))
+			if err != nil || len(dynamicClient.calls) != 1 || dynamicClient.calls[0].patchType != types.ApplyPatchType {
+				return false
+			}
+
+			var patchObj map[string]interface{}
+			if err := json.Unmarshal(dynamicClient.calls[0].data, &patchObj); err != nil {
+				return false
+			}
+			patched, _, _ := unstructured.NestedSlice(patchObj, "spec", "template", "spec", "containers")
+			if len(patched) != count || len(result.Containers) != count {
+				return false
+			}
+			for _, change := range changes {
+				applied, ok := result.Containers[change.Container]
+				if !ok || !applied.Converged || applied.Applied.CPU.Cmp(change.Recommendation.CPU) != 0 {
+					return false
+				}
+			}
+			return true
+		},
+		gen.IntRange(1, 6),
+		gen.Int64Range(100, 4000),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
 // Feature: server-side-apply-support, Property 5: Conditional limits inclusion
 // For any recommendation, if updateRequestsOnly=true, the patch should not include limits;
 // if false, it should include both requests and limits
@@ -843,7 +929,7 @@ func TestProperty_ConditionalLimits(t *testing.T) {
 			engine := &Engine{}
 
 			// Build SSA patch
-			patch, err := engine.buildSSAPatch(workload, "test-container", rec, policy)
+			patch, err := engine.buildSSAPatch(workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -904,7 +990,7 @@ func TestProperty_ConditionalLimits(t *testing.T) {
 			engine := &Engine{}
 
 			// Build SSA patch
-			patch, err := engine.buildSSAPatch(workload, "test-container", rec, policy)
+			patch, err := engine.buildSSAPatch(workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -1011,7 +1097,7 @@ func TestProperty_JSONSerialization(t *testing.T) {
 			engine := &Engine{}
 
 			// Build SSA patch
-			patch, err := engine.buildSSAPatch(workload, "test-container", rec, policy)
+			patch, err := engine.buildSSAPatch(workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -1086,7 +1172,7 @@ func TestProperty_SSAFieldManager(t *testing.T) {
 			policy.Spec.UpdateStrategy.UpdateRequestsOnly = updateRequestsOnly
 
 			// Apply with SSA
-			err := engine.ApplyWithSSA(context.Background(), workload, "test-container", rec, policy)
+			err := engine.ApplyWithSSA(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -1193,7 +1279,7 @@ func TestProperty_SSAForceFlag(t *testing.T) {
 			policy.Spec.UpdateStrategy.UpdateRequestsOnly = updateRequestsOnly
 
 			// Apply with SSA
-			err := engine.ApplyWithSSA(context.Background(), workload, "test-container", rec, policy)
+			err := engine.ApplyWithSSA(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -1229,10 +1315,16 @@ func TestSSAErrorHandling(t *testing.T) {
 	}{
 		{
 			name:          "conflict error",
-			err:           errors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, "test-deployment", fmt.Errorf("field manager conflict")),
+			err:           newFieldManagerConflict("test-deployment"),
 			expectedMsg:   "SSA conflict",
 			shouldContain: []string{"SSA conflict", "another field manager owns these fields"},
 		},
+		{
+			name:          "stale resourceVersion",
+			err:           errors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, "test-deployment", fmt.Errorf("the object has been modified")),
+			expectedMsg:   "patch conflict",
+			shouldContain: []string{"patch conflict", "modified since it was read"},
+		},
 		{
 			name:          "forbidden error",
 			err:           errors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "test-deployment", fmt.Errorf("insufficient permissions")),
@@ -1310,7 +1402,7 @@ func TestSSAErrorHandlingIntegration(t *testing.T) {
 			rec := createMockRecommendation()
 			policy := createMockPolicy(true, false)
 
-			err := engine.ApplyWithSSA(context.Background(), workload, "test-container", rec, policy)
+			err := engine.ApplyWithSSA(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err == nil {
 				t.Errorf("expected error, got nil")
 				return
@@ -1353,7 +1445,7 @@ type mockResourceInterfaceWithError struct {
 func (m *mockResourceInterfaceWithError) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
 	switch m.errorType {
 	case "conflict":
-		return nil, errors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, name, fmt.Errorf("field manager conflict"))
+		return nil, newFieldManagerConflict(name)
 	case "forbidden":
 		return nil, errors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, name, fmt.Errorf("insufficient permissions"))
 	case "invalid":
@@ -1406,7 +1498,7 @@ func TestProperty_ConfigurationDeterminesPatchMethod(t *testing.T) {
 			policy.Spec.UpdateStrategy.UseServerSideApply = &useSSA
 
 			// Apply
-			result, err := engine.Apply(context.Background(), workload, "test-container", rec, policy)
+			result, err := engine.Apply(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -1459,7 +1551,7 @@ func TestProperty_ConfigurationDeterminesPatchMethod(t *testing.T) {
 			policy.Spec.UpdateStrategy.UseServerSideApply = &useSSA
 
 			// Apply
-			result, err := engine.Apply(context.Background(), workload, "test-container", rec, policy)
+			result, err := engine.Apply(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -1521,7 +1613,7 @@ func TestProperty_DefaultToSSA(t *testing.T) {
 			policy.Spec.UpdateStrategy.UseServerSideApply = nil
 
 			// Apply
-			result, err := engine.Apply(context.Background(), workload, "test-container", rec, policy)
+			result, err := engine.Apply(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -1586,7 +1678,7 @@ func TestProperty_SSALoggingIncludesFieldManager(t *testing.T) {
 			ctx := context.Background()
 
 			// Apply with SSA - this will trigger logging
-			err := engine.ApplyWithSSA(ctx, workload, "test-container", rec, policy)
+			err := engine.ApplyWithSSA(ctx, workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -1762,7 +1854,7 @@ func TestProperty_PatchContainsCalculatedLimits(t *testing.T) {
 			engine := &Engine{}
 
 			// Build SSA patch
-			patch, err := engine.buildSSAPatch(workload, "test-container", rec, policy)
+			patch, err := engine.buildSSAPatch(workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
 			if err != nil {
 				return false
 			}
@@ -1861,3 +1953,300 @@ func TestProperty_MultiplierBoundsHandled(t *testing.T) {
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
+
+// Feature: limit-configuration, Property 5: Memory limit never falls below observed P99
+// For any recommendation, multiplier, and observed P99 memory usage, the calculated memory
+// limit should be at least the observed P99 and at least the multiplied recommendation,
+// while the CPU limit is unaffected by the floor
+func TestProperty_MemoryLimitFloorAtObservedP99(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("memory limit is at least observed P99", prop.ForAll(
+		func(cpuReq, memReq, memP99 int64, memMult float64) bool {
+			rec := &recommendation.Recommendation{
+				CPU:               resource.MustParse(fmt.Sprintf("%dm", cpuReq)),
+				Memory:            resource.MustParse(fmt.Sprintf("%dMi", memReq)),
+				ObservedMemoryP99: resource.MustParse(fmt.Sprintf("%dMi", memP99)),
+			}
+
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = false
+			policy.Spec.UpdateStrategy.LimitConfig = &optipodv1alpha1.LimitConfig{
+				MemoryLimitMultiplier: &memMult,
+			}
+
+			engine := &Engine{}
+			cpuLimit, memoryLimit := engine.calculateLimits(rec, policy)
+
+			// Memory limit is floored at observed P99
+			if memoryLimit.Cmp(rec.ObservedMemoryP99) < 0 {
+				return false
+			}
+
+			// Memory limit is never lowered below the multiplied recommendation
+			multiplied := int64(float64(rec.Memory.Value()) * memMult)
+			if memoryLimit.Value() < multiplied {
+				return false
+			}
+
+			// CPU limit is not floored (default multiplier 1.0)
+			return cpuLimit.MilliValue() == rec.CPU.MilliValue()
+		},
+		gen.Int64Range(100, 4000),
+		gen.Int64Range(128, 8192),
+		gen.Int64Range(64, 16384),
+		gen.Float64Range(1.0, 10.0),
+	))
+
+	properties.Property("memory limit is unchanged when P99 is not observed", prop.ForAll(
+		func(memReq int64) bool {
+			rec := &recommendation.Recommendation{
+				CPU:    resource.MustParse("100m"),
+				Memory: resource.MustParse(fmt.Sprintf("%dMi", memReq)),
+			}
+
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = false
+
+			engine := &Engine{}
+			_, memoryLimit := engine.calculateLimits(rec, policy)
+
+			return memoryLimit.Value() == int64(float64(rec.Memory.Value())*1.1)
+		},
+		gen.Int64Range(128, 8192),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// Property: Percentile-derived memory limits replace the multiplier
+// For any recommendation carrying a percentile-derived memory limit, calculateLimits uses it
+// instead of the memory multiplier while still honouring the observed P99 floor
+func TestProperty_MemoryLimitFromPercentile(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("percentile-derived memory limit is used as is", prop.ForAll(
+		func(memReq, memLimit int64) bool {
+			rec := &recommendation.Recommendation{
+				CPU:         resource.MustParse("100m"),
+				Memory:      resource.MustParse(fmt.Sprintf("%dMi", memReq)),
+				MemoryLimit: resource.MustParse(fmt.Sprintf("%dMi", memReq+memLimit)),
+			}
+
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = false
+
+			engine := &Engine{}
+			_, memoryLimit := engine.calculateLimits(rec, policy)
+
+			return memoryLimit.Cmp(rec.MemoryLimit) == 0
+		},
+		gen.Int64Range(128, 8192),
+		gen.Int64Range(0, 8192),
+	))
+
+	properties.Property("observed P99 still floors a percentile-derived limit", prop.ForAll(
+		func(memReq, p99Extra int64) bool {
+			rec := &recommendation.Recommendation{
+				CPU:               resource.MustParse("100m"),
+				Memory:            resource.MustParse(fmt.Sprintf("%dMi", memReq)),
+				MemoryLimit:       resource.MustParse(fmt.Sprintf("%dMi", memReq)),
+				ObservedMemoryP99: resource.MustParse(fmt.Sprintf("%dMi", memReq+p99Extra)),
+			}
+
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = false
+
+			engine := &Engine{}
+			_, memoryLimit := engine.calculateLimits(rec, policy)
+
+			return memoryLimit.Cmp(rec.ObservedMemoryP99) == 0
+		},
+		gen.Int64Range(128, 8192),
+		gen.Int64Range(1, 8192),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// Property: A fixed burst ratio matches the multiplier mode
+// For any observed usage, limits derived from a burst ratio band of a single ratio m equal the
+// limits derived from limit multipliers of m
+func TestProperty_BurstRatioLimitsMatchMultipliers(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("a single-ratio band behaves like the multipliers", prop.ForAll(
+		func(cpuP90, memP90, p99Extra int64, ratio float64) bool {
+			containerMetrics := &metrics.ContainerMetrics{
+				CPU: metrics.ResourceMetrics{
+					P50:     *resource.NewMilliQuantity(cpuP90/2, resource.DecimalSI),
+					P90:     *resource.NewMilliQuantity(cpuP90, resource.DecimalSI),
+					P99:     *resource.NewMilliQuantity(cpuP90+p99Extra, resource.DecimalSI),
+					Samples: 100,
+				},
+				Memory: metrics.ResourceMetrics{
+					P50:     *resource.NewQuantity(memP90/2, resource.BinarySI),
+					P90:     *resource.NewQuantity(memP90, resource.BinarySI),
+					P99:     *resource.NewQuantity(memP90+p99Extra*1024*1024, resource.BinarySI),
+					Samples: 100,
+				},
+			}
+
+			multiplierPolicy := createMockPolicy(true, false)
+			multiplierPolicy.Spec.UpdateStrategy.UpdateRequestsOnly = false
+			multiplierPolicy.Spec.ResourceBounds.CPU.Max = resource.MustParse("100")
+			multiplierPolicy.Spec.ResourceBounds.Memory.Max = resource.MustParse("1Ti")
+			burstPolicy := multiplierPolicy.DeepCopy()
+			multiplierPolicy.Spec.UpdateStrategy.LimitConfig = &optipodv1alpha1.LimitConfig{
+				CPULimitMultiplier:    &ratio,
+				MemoryLimitMultiplier: &ratio,
+			}
+			burstPolicy.Spec.UpdateStrategy.LimitConfig = &optipodv1alpha1.LimitConfig{
+				BurstRatioLimits: &optipodv1alpha1.BurstRatioLimits{MinRatio: &ratio, MaxRatio: &ratio},
+			}
+
+			multiplierRec, err := recommendation.NewEngine().ComputeRecommendation(containerMetrics, multiplierPolicy)
+			if err != nil {
+				return false
+			}
+			burstRec, err := recommendation.NewEngine().ComputeRecommendation(containerMetrics, burstPolicy)
+			if err != nil {
+				return false
+			}
+
+			engine := &Engine{}
+			multiplierCPU, multiplierMemory := engine.calculateLimits(multiplierRec, multiplierPolicy)
+			burstCPU, burstMemory := engine.calculateLimits(burstRec, burstPolicy)
+			return burstCPU.Cmp(multiplierCPU) == 0 && burstMemory.Cmp(multiplierMemory) == 0
+		},
+		gen.Int64Range(10, 10000),
+		gen.Int64Range(64*1024*1024, 1024*1024*1024),
+		gen.Int64Range(0, 5000),
+		gen.Float64Range(1.0, 10.0),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestGetCurrentResources_EffectiveResources(t *testing.T) {
+	workload := createMockWorkload()
+	containers, _, _ := unstructured.NestedSlice(workload.Object.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	container["resources"] = map[string]interface{}{}
+	_ = unstructured.SetNestedSlice(workload.Object.Object, containers, "spec", "template", "spec", "containers")
+
+	engine := &Engine{}
+
+	// Without effective resources, an empty resource block has no requests or limits
+	resources, err := engine.getCurrentResources(workload)
+	if err != nil {
+		t.Fatalf("getCurrentResources() error = %v", err)
+	}
+	if len(resources["test-container"].Limits) != 0 {
+		t.Errorf("expected no limits, got %v", resources["test-container"].Limits)
+	}
+
+	// LimitRange-defaulted values are used when provided
+	workload.EffectiveResources = map[string]corev1.ResourceRequirements{
+		"test-container": {
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		},
+	}
+	resources, err = engine.getCurrentResources(workload)
+	if err != nil {
+		t.Fatalf("getCurrentResources() error = %v", err)
+	}
+	memoryLimit := resources["test-container"].Limits[corev1.ResourceMemory]
+	if memoryLimit.Cmp(resource.MustParse("1Gi")) != 0 {
+		t.Errorf("expected effective memory limit 1Gi, got %s", memoryLimit.String())
+	}
+
+	// The memory decrease safety check sees the defaulted limit
+	rec := &recommendation.Recommendation{
+		CPU:    resource.MustParse("100m"),
+		Memory: resource.MustParse("512Mi"),
+	}
+	if !engine.isUnsafeMemoryDecrease(resources, rec) {
+		t.Error("expected a recommendation below the LimitRange default limit to be flagged as unsafe")
+	}
+}
+
+func TestBuildPatch_LastAppliedAnnotation(t *testing.T) {
+	engine := &Engine{}
+	policy := createMockPolicy(true, false)
+
+	builders := map[string]func() ([]byte, error){
+		"server-side apply": func() ([]byte, error) {
+			return engine.buildSSAPatch(createMockWorkload(), []ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, policy)
+		},
+		"strategic merge": func() ([]byte, error) {
+			return engine.buildResourcePatch(createMockWorkload(), []ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, policy)
+		},
+	}
+
+	for name, build := range builders {
+		t.Run(name, func(t *testing.T) {
+			patch, err := build()
+			if err != nil {
+				t.Fatalf("failed to build patch: %v", err)
+			}
+
+			var patchObj map[string]interface{}
+			if err := json.Unmarshal(patch, &patchObj); err != nil {
+				t.Fatalf("failed to parse patch: %v", err)
+			}
+
+			// The annotation travels in the same patch as the resources, so both are written atomically
+			value, found, _ := unstructured.NestedString(patchObj, "metadata", "annotations", optipodv1alpha1.AnnotationLastApplied)
+			if !found {
+				t.Fatalf("patch does not set the %s annotation: %s", optipodv1alpha1.AnnotationLastApplied, patch)
+			}
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				t.Errorf("annotation value %q is not an RFC3339 timestamp: %v", value, err)
+			}
+
+			containers, _, _ := unstructured.NestedSlice(patchObj, "spec", "template", "spec", "containers")
+			if len(containers) != 1 {
+				t.Errorf("patch has %d containers, want 1", len(containers))
+			}
+		})
+	}
+}
+
+func TestCanApply_DryRun(t *testing.T) {
+	tests := []struct {
+		name          string
+		globalDryRun  bool
+		policyDryRun  bool
+		wantCanApply  bool
+		wantReasonHas string
+	}{
+		{name: "neither", wantCanApply: true},
+		{name: "policy dry-run", policyDryRun: true, wantReasonHas: "Policy dry-run"},
+		{name: "global dry-run", globalDryRun: true, wantReasonHas: "Global dry-run"},
+		{name: "global dry-run takes precedence", globalDryRun: true, policyDryRun: true, wantReasonHas: "Global dry-run"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &Engine{
+				discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}},
+			}
+			engine.SetDryRun(tt.globalDryRun)
+			policy := createMockPolicy(true, true)
+			policy.Spec.DryRun = tt.policyDryRun
+
+			decision, err := engine.CanApply(context.Background(), createMockWorkload(), "test-container", createMockRecommendation(), policy)
+			if err != nil {
+				t.Fatalf("CanApply() error = %v", err)
+			}
+			if decision.CanApply != tt.wantCanApply {
+				t.Errorf("CanApply() = %v (%s), want %v", decision.CanApply, decision.Reason, tt.wantCanApply)
+			}
+			if !tt.wantCanApply && (decision.Method != Skip || !strings.Contains(decision.Reason, tt.wantReasonHas)) {
+				t.Errorf("decision = %s (%s), want skipped for %q", decision.Method, decision.Reason, tt.wantReasonHas)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"errors"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Errors returned by Apply and ApplyWithSSA wrap one of these when the cause is known, so callers
+// can classify them with errors.Is. Their messages are part of the human-readable error text.
+var (
+	// ErrRBACDenied means optipod lacks the permissions to change the workload
+	ErrRBACDenied = errors.New("RBAC")
+
+	// ErrSSAConflict means another field manager owns the fields of a Server-Side Apply
+	ErrSSAConflict = errors.New("SSA conflict")
+
+	// ErrPatchConflict means a strategic merge patch conflicted with a concurrent change to the workload
+	ErrPatchConflict = errors.New("patch conflict")
+
+	// ErrInvalidPatch means the API server rejected the patch as invalid
+	ErrInvalidPatch = errors.New("patch validation failed")
+
+	// ErrQuotaExceeded means the change would exceed a ResourceQuota of the namespace
+	ErrQuotaExceeded = errors.New("quota exceeded")
+
+	// ErrUnsafeMemoryDecrease means the recommended memory is below a current memory limit,
+	// which could cause OOM kills or evictions. It is the Cause of a skip decision, not an error.
+	ErrUnsafeMemoryDecrease = errors.New("unsafe memory decrease")
+
+	// ErrDisruptiveResize means an in-place resize would restart the container because of its
+	// resizePolicy while the update strategy does not allow disruptive updates. It is the Cause
+	// of a skip decision, not an error.
+	ErrDisruptiveResize = errors.New("disruptive resize")
+
+	// ErrRecreateCooldown means recreating the pods is held back because they were recreated
+	// less than updateStrategy.minRecreateInterval ago. It is the Cause of a skip decision, not
+	// an error.
+	ErrRecreateCooldown = errors.New("recreate cooldown")
+
+	// ErrDecreaseSuppressed means the change would only lower requests, which
+	// updateStrategy.increaseOnly suppresses. It is the Cause of a skip decision, not an error.
+	ErrDecreaseSuppressed = errors.New("decrease suppressed")
+
+	// ErrWithinTolerance means the recommendation is within updateStrategy.applyTolerance of the
+	// current requests, so the change is a no-op. It is the Cause of a skip decision, not an error.
+	ErrWithinTolerance = errors.New("within tolerance")
+
+	// ErrOnDeleteStrategy means the workload's OnDelete update strategy would not roll the change
+	// out to its pods and the policy does not delete them. It is the Cause of a skip decision,
+	// not an error.
+	ErrOnDeleteStrategy = errors.New("OnDelete update strategy")
+)
+
+// errorClasses maps each classified error to its status condition reason and metric error type
+var errorClasses = []struct {
+	err       error
+	reason    string
+	errorType string
+}{
+	{ErrRBACDenied, "RBACDenied", "rbac_denied"},
+	{ErrSSAConflict, "SSAConflict", "ssa_conflict"},
+	{ErrPatchConflict, "PatchConflict", "patch_conflict"},
+	{ErrInvalidPatch, "InvalidPatch", "invalid_patch"},
+	{ErrQuotaExceeded, "QuotaExceeded", "quota_exceeded"},
+	{ErrUnsafeMemoryDecrease, "UnsafeMemoryDecrease", "unsafe_memory_decrease"},
+	{ErrDisruptiveResize, "DisruptiveResize", "disruptive_resize"},
+	{ErrRecreateCooldown, "RecreateCooldown", "recreate_cooldown"},
+	{ErrDecreaseSuppressed, "DecreaseSuppressed", "decrease_suppressed"},
+	{ErrWithinTolerance, "WithinTolerance", "within_tolerance"},
+	{ErrOnDeleteStrategy, "OnDeleteStrategy", "ondelete_strategy"},
+}
+
+// ErrorReason returns the status condition reason for an application engine error, or an empty
+// string when the error is not classified
+func ErrorReason(err error) string {
+	for _, class := range errorClasses {
+		if errors.Is(err, class.err) {
+			return class.reason
+		}
+	}
+	return ""
+}
+
+// ErrorType returns the metric error type for an application engine error, or an empty string
+// when the error is not classified
+func ErrorType(err error) string {
+	for _, class := range errorClasses {
+		if errors.Is(err, class.err) {
+			return class.errorType
+		}
+	}
+	return ""
+}
+
+// isQuotaExceeded reports whether the API server rejected a request because it would exceed a
+// ResourceQuota. The quota admission plugin reports this as Forbidden, and only its message
+// tells it apart from an RBAC denial.
+func isQuotaExceeded(err error) bool {
+	return apierrors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota")
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var deploymentsResource = schema.GroupResource{Group: "apps", Resource: "deployments"}
+
+// newFieldManagerConflict returns the error of a Server-Side Apply whose fields another field
+// manager owns
+func newFieldManagerConflict(name string) *apierrors.StatusError {
+	err := apierrors.NewConflict(deploymentsResource, name, fmt.Errorf("field manager conflict"))
+	err.ErrStatus.Details.Causes = []metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldManagerConflict,
+		Message: `conflict with "kubectl-edit"`,
+		Field:   ".spec.template.spec.containers[name=\"app\"].resources.requests.cpu",
+	}}
+	return err
+}
+
+func TestHandleSSAError_Classification(t *testing.T) {
+	engine := &Engine{}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantErr    error
+		wantReason string
+		wantType   string
+	}{
+		{
+			name:       "conflict",
+			err:        newFieldManagerConflict("web"),
+			wantErr:    ErrSSAConflict,
+			wantReason: "SSAConflict",
+			wantType:   "ssa_conflict",
+		},
+		{
+			// The apply carries the resourceVersion it was built from
+			name:       "workload modified since it was read",
+			err:        apierrors.NewConflict(deploymentsResource, "web", fmt.Errorf("the object has been modified")),
+			wantErr:    ErrPatchConflict,
+			wantReason: "PatchConflict",
+			wantType:   "patch_conflict",
+		},
+		{
+			name:       "forbidden",
+			err:        apierrors.NewForbidden(deploymentsResource, "web", fmt.Errorf("user cannot patch resource")),
+			wantErr:    ErrRBACDenied,
+			wantReason: "RBACDenied",
+			wantType:   "rbac_denied",
+		},
+		{
+			name: "quota exceeded",
+			err: apierrors.NewForbidden(deploymentsResource, "web",
+				fmt.Errorf("exceeded quota: compute, requested: limits.memory=2Gi, used: limits.memory=7Gi, limited: limits.memory=8Gi")),
+			wantErr:    ErrQuotaExceeded,
+			wantReason: "QuotaExceeded",
+			wantType:   "quota_exceeded",
+		},
+		{
+			name:       "invalid",
+			err:        apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "web", nil),
+			wantErr:    ErrInvalidPatch,
+			wantReason: "InvalidPatch",
+			wantType:   "invalid_patch",
+		},
+		{
+			name: "unclassified",
+			err:  fmt.Errorf("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := engine.handleSSAError(tt.err)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("handleSSAError() = %v, want it to wrap %v", err, tt.wantErr)
+			}
+			// The API error stays available to callers
+			if !errors.Is(err, tt.err) {
+				t.Errorf("handleSSAError() = %v, want it to wrap the original error", err)
+			}
+			if got := ErrorReason(err); got != tt.wantReason {
+				t.Errorf("ErrorReason() = %q, want %q", got, tt.wantReason)
+			}
+			if got := ErrorType(err); got != tt.wantType {
+				t.Errorf("ErrorType() = %q, want %q", got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestApply_ClassifiesStrategicMergeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		apiErr  error
+		wantErr error
+	}{
+		{
+			name:    "forbidden",
+			apiErr:  apierrors.NewForbidden(deploymentsResource, "test-deployment", fmt.Errorf("user cannot patch resource")),
+			wantErr: ErrRBACDenied,
+		},
+		{
+			name:    "quota exceeded",
+			apiErr:  apierrors.NewForbidden(deploymentsResource, "test-deployment", fmt.Errorf("exceeded quota: compute")),
+			wantErr: ErrQuotaExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &Engine{dynamicClient: &mockDynamicClientWithResult{err: tt.apiErr}}
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.UseServerSideApply = new(bool)
+
+			_, err := engine.Apply(context.Background(), createMockWorkload(), []ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, policy)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Apply() error = %v, want it to wrap %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCanApply_UnsafeMemoryDecreaseCause(t *testing.T) {
+	engine := &Engine{}
+	workload := createMockWorkload()
+	rec := createMockRecommendation()
+	rec.Memory = resource.MustParse("1Mi")
+
+	decision, err := engine.CanApply(context.Background(), workload, "test-container", rec, createMockPolicy(true, false))
+	if err != nil {
+		t.Fatalf("CanApply() error = %v", err)
+	}
+	if decision.CanApply || !errors.Is(decision.Cause, ErrUnsafeMemoryDecrease) {
+		t.Errorf("CanApply() = %+v, want a skip caused by ErrUnsafeMemoryDecrease", decision)
+	}
+	if ErrorReason(decision.Cause) != "UnsafeMemoryDecrease" {
+		t.Errorf("ErrorReason() = %q, want UnsafeMemoryDecrease", ErrorReason(decision.Cause))
+	}
+}
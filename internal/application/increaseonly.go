@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// increaseOnlyTarget returns the recommendation to apply when the policy only increases
+// requests: each optimized request the recommendation would lower keeps its current value. The
+// second result lists the resources whose decrease is suppressed.
+//
+// Without increaseOnly, or when the container has no current request of a resource, the
+// recommendation is kept.
+func increaseOnlyTarget(
+	currentResources map[string]corev1.ResourceRequirements,
+	containerName string,
+	rec *recommendation.Recommendation,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) (*recommendation.Recommendation, []corev1.ResourceName) {
+	if !policy.Spec.UpdateStrategy.IncreaseOnly {
+		return rec, nil
+	}
+	current := currentResources[containerName]
+
+	target := *rec
+	var suppressed []corev1.ResourceName
+	if policy.OptimizesCPU() {
+		if currentCPU, ok := current.Requests[corev1.ResourceCPU]; ok && rec.CPU.Cmp(currentCPU) < 0 {
+			target.CPU = currentCPU.DeepCopy()
+			suppressed = append(suppressed, corev1.ResourceCPU)
+		}
+	}
+	if policy.OptimizesMemory() {
+		if currentMemory, ok := current.Requests[corev1.ResourceMemory]; ok && rec.Memory.Cmp(currentMemory) < 0 {
+			target.Memory = currentMemory.DeepCopy()
+			suppressed = append(suppressed, corev1.ResourceMemory)
+
+			// A percentile-derived memory limit never drops below the kept request
+			if !target.MemoryLimit.IsZero() && target.MemoryLimit.Cmp(target.Memory) < 0 {
+				target.MemoryLimit = target.Memory.DeepCopy()
+			}
+		}
+	}
+	if len(suppressed) == 0 {
+		return rec, nil
+	}
+	return &target, suppressed
+}
+
+// raisesRequests returns true if the recommendation raises any optimized request of the container
+// above its current value, or sets a request the container does not have
+func raisesRequests(
+	current corev1.ResourceRequirements,
+	rec *recommendation.Recommendation,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) bool {
+	raises := func(name corev1.ResourceName, value resource.Quantity) bool {
+		currentValue, ok := current.Requests[name]
+		return !ok || value.Cmp(currentValue) > 0
+	}
+	return (policy.OptimizesCPU() && raises(corev1.ResourceCPU, rec.CPU)) ||
+		(policy.OptimizesMemory() && raises(corev1.ResourceMemory, rec.Memory))
+}
+
+// decreaseSuppressedDecision returns a skip decision for a container whose recommendation only
+// lowers requests, which increaseOnly suppresses
+func decreaseSuppressedDecision(containerName string, suppressed []corev1.ResourceName) *ApplyDecision {
+	names := make([]string, 0, len(suppressed))
+	for _, name := range suppressed {
+		names = append(names, string(name))
+	}
+	return &ApplyDecision{
+		CanApply:            false,
+		Method:              Skip,
+		Reason:              fmt.Sprintf("Decrease suppressed: increaseOnly keeps the %s request(s) of container %s", strings.Join(names, ", "), containerName),
+		Cause:               ErrDecreaseSuppressed,
+		SuppressedDecreases: suppressed,
+	}
+}
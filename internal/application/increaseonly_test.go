@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/version"
+
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// patchedRequests returns the requests of the container in a patch
+func patchedRequests(patch []byte, containerName string) (corev1.ResourceList, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(patch, &obj); err != nil {
+		return nil, err
+	}
+	containers, _, _ := unstructured.NestedSlice(obj, "spec", "template", "spec", "containers")
+	for _, c := range containers {
+		container, _ := c.(map[string]interface{})
+		if container["name"] != containerName {
+			continue
+		}
+		requests := corev1.ResourceList{}
+		values, _, _ := unstructured.NestedStringMap(container, "resources", "requests")
+		for name, value := range values {
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, err
+			}
+			requests[corev1.ResourceName(name)] = quantity
+		}
+		return requests, nil
+	}
+	return nil, fmt.Errorf("container %s not in patch", containerName)
+}
+
+// Property: With increaseOnly, no patch ever lowers a request, whichever patch method is used
+func TestProperty_IncreaseOnlyNeverDecreases(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("patched requests are never below the current requests", prop.ForAll(
+		func(currentCPU, currentMemory, recCPU, recMemory int64, useSSA bool) bool {
+			workload := createMockWorkloadWithResources(map[string]interface{}{
+				"requests": map[string]interface{}{
+					"cpu":    fmt.Sprintf("%dm", currentCPU),
+					"memory": fmt.Sprintf("%dMi", currentMemory),
+				},
+			})
+			dynamicClient := &mockDynamicClientWithResult{result: workload.Object}
+			engine := &Engine{dynamicClient: dynamicClient}
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.IncreaseOnly = true
+			policy.Spec.UpdateStrategy.UseServerSideApply = &useSSA
+
+			rec := &recommendation.Recommendation{
+				CPU:    resource.MustParse(fmt.Sprintf("%dm", recCPU)),
+				Memory: resource.MustParse(fmt.Sprintf("%dMi", recMemory)),
+			}
+			if _, err := engine.Apply(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy); err != nil {
+				return false
+			}
+			if len(dynamicClient.calls) == 0 {
+				return false
+			}
+
+			requests, err := patchedRequests(dynamicClient.calls[0].data, "test-container")
+			if err != nil {
+				return false
+			}
+			cpu, memory := requests[corev1.ResourceCPU], requests[corev1.ResourceMemory]
+			return cpu.MilliValue() == max(currentCPU, recCPU) && memory.Value() == max(currentMemory, recMemory)*1024*1024
+		},
+		gen.Int64Range(100, 4000),
+		gen.Int64Range(128, 8192),
+		gen.Int64Range(100, 4000),
+		gen.Int64Range(128, 8192),
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestCanApply_IncreaseOnly(t *testing.T) {
+	tests := []struct {
+		name           string
+		cpu, memory    string
+		wantCanApply   bool
+		wantSuppressed []corev1.ResourceName
+	}{
+		{name: "increases are applied", cpu: "300m", memory: "300Mi", wantCanApply: true},
+		{
+			name:           "a decrease is suppressed alongside an increase",
+			cpu:            "300m",
+			memory:         "128Mi",
+			wantCanApply:   true,
+			wantSuppressed: []corev1.ResourceName{corev1.ResourceMemory},
+		},
+		{
+			name:           "only decreases skip the container",
+			cpu:            "100m",
+			memory:         "128Mi",
+			wantSuppressed: []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory},
+		},
+		{
+			name:           "a decrease with an unchanged request skips the container",
+			cpu:            "250m",
+			memory:         "128Mi",
+			wantSuppressed: []corev1.ResourceName{corev1.ResourceMemory},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &Engine{
+				discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}},
+			}
+			workload := createMockWorkloadWithResources(map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "250m", "memory": "256Mi"},
+			})
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.IncreaseOnly = true
+			rec := &recommendation.Recommendation{CPU: resource.MustParse(tt.cpu), Memory: resource.MustParse(tt.memory)}
+
+			decision, err := engine.CanApply(context.Background(), workload, "test-container", rec, policy)
+			if err != nil {
+				t.Fatalf("CanApply() error = %v", err)
+			}
+			if decision.CanApply != tt.wantCanApply {
+				t.Errorf("CanApply() = %v (%s), want %v", decision.CanApply, decision.Reason, tt.wantCanApply)
+			}
+			if !tt.wantCanApply && !errors.Is(decision.Cause, ErrDecreaseSuppressed) {
+				t.Errorf("Cause = %v, want ErrDecreaseSuppressed", decision.Cause)
+			}
+			if !slices.Equal(decision.SuppressedDecreases, tt.wantSuppressed) {
+				t.Errorf("SuppressedDecreases = %v, want %v", decision.SuppressedDecreases, tt.wantSuppressed)
+			}
+		})
+	}
+}
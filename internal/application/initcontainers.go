@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// fieldContainers and fieldInitContainers are the pod template lists holding containers
+	fieldContainers     = "containers"
+	fieldInitContainers = "initContainers"
+)
+
+// sizedContainerFields are the pod template lists whose containers are sized. Ephemeral
+// containers, such as those added by kubectl debug, are never sized nor patched.
+var sizedContainerFields = []string{fieldContainers, fieldInitContainers}
+
+// templateContainers returns the containers of a pod template list of a workload. Lists other
+// than sizedContainerFields are refused, so ephemeral containers are never read for patching.
+func templateContainers(obj *unstructured.Unstructured, field string) ([]interface{}, error) {
+	if !slices.Contains(sizedContainerFields, field) {
+		return nil, fmt.Errorf("pod template list %s is not sized", field)
+	}
+	containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", field)
+	return containers, err
+}
+
+// containerField returns the pod template list holding the named container. Native sidecars
+// and other init containers are in initContainers; anything else, including a container that
+// is not in the template, is in containers.
+func containerField(obj *unstructured.Unstructured, containerName string) string {
+	if obj == nil {
+		return fieldContainers
+	}
+	initContainers, _ := templateContainers(obj, fieldInitContainers)
+	for _, c := range initContainers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(container, "name"); name == containerName {
+			return fieldInitContainers
+		}
+	}
+	return fieldContainers
+}
+
+// findTemplateContainer returns the named container of a workload's pod template, looking in
+// both containers and initContainers
+func findTemplateContainer(obj *unstructured.Unstructured, containerName string) (map[string]interface{}, bool) {
+	containers, _ := templateContainers(obj, containerField(obj, containerName))
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(container, "name"); name == containerName {
+			return container, true
+		}
+	}
+	return nil, false
+}
+
+// isNativeSidecar reports whether an init container is a native sidecar, which keeps running
+// for the lifetime of the pod instead of running to completion before the other containers
+func isNativeSidecar(container map[string]interface{}) bool {
+	policy, _, _ := unstructured.NestedString(container, "restartPolicy")
+	return policy == string(corev1.ContainerRestartPolicyAlways)
+}
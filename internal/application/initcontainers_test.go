@@ -0,0 +1,195 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// newInitContainerTestWorkload returns the mock workload with a run-once init container named
+// "migrate" and a native sidecar named "proxy"
+func newInitContainerTestWorkload() *Workload {
+	workload := createMockWorkload()
+	_ = unstructured.SetNestedSlice(workload.Object.Object, []interface{}{
+		map[string]interface{}{
+			"name":      "migrate",
+			"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "100m", "memory": "64Mi"}},
+		},
+		map[string]interface{}{
+			"name":          "proxy",
+			"restartPolicy": "Always",
+			"resources":     map[string]interface{}{"requests": map[string]interface{}{"cpu": "50m", "memory": "32Mi"}},
+		},
+	}, "spec", "template", "spec", "initContainers")
+	return workload
+}
+
+// patchedContainerNames returns the names of the containers in a pod template list of a patch
+func patchedContainerNames(t *testing.T, patch []byte, field string) []string {
+	t.Helper()
+	var obj map[string]interface{}
+	if err := json.Unmarshal(patch, &obj); err != nil {
+		t.Fatalf("failed to decode patch: %v", err)
+	}
+	containers, _, _ := unstructured.NestedSlice(obj, "spec", "template", "spec", field)
+	names := make([]string, 0, len(containers))
+	for _, c := range containers {
+		name, _, _ := unstructured.NestedString(c.(map[string]interface{}), "name")
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestContainerField(t *testing.T) {
+	workload := newInitContainerTestWorkload()
+	tests := map[string]string{
+		"test-container": fieldContainers,
+		"migrate":        fieldInitContainers,
+		"proxy":          fieldInitContainers,
+		"missing":        fieldContainers,
+	}
+	for name, want := range tests {
+		if got := containerField(workload.Object, name); got != want {
+			t.Errorf("containerField(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGetCurrentResources_NativeSidecars(t *testing.T) {
+	resources, err := (&Engine{}).getCurrentResources(newInitContainerTestWorkload())
+	if err != nil {
+		t.Fatalf("getCurrentResources() error = %v", err)
+	}
+	if _, ok := resources["proxy"]; !ok {
+		t.Error("the native sidecar is missing from the current resources")
+	}
+	if _, ok := resources["migrate"]; ok {
+		t.Error("the run-once init container must not be part of the current resources")
+	}
+}
+
+func TestBuildPatches_InitContainers(t *testing.T) {
+	engine := &Engine{}
+	policy := createMockPolicy(true, true)
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("80m"), Memory: resource.MustParse("48Mi")}
+
+	tests := []struct {
+		name          string
+		changes       []string
+		wantContainer []string
+		wantInit      []string
+	}{
+		{
+			name:          "only regular containers leave init containers out",
+			changes:       []string{"test-container"},
+			wantContainer: []string{"test-container"},
+		},
+		{
+			name:          "native sidecar is patched under initContainers",
+			changes:       []string{"test-container", "proxy"},
+			wantContainer: []string{"test-container"},
+			wantInit:      []string{"proxy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := make([]ContainerChange, 0, len(tt.changes))
+			for _, name := range tt.changes {
+				changes = append(changes, ContainerChange{Container: name, Recommendation: rec})
+			}
+
+			ssaPatch, err := engine.buildSSAPatch(newInitContainerTestWorkload(), changes, policy)
+			if err != nil {
+				t.Fatalf("buildSSAPatch() error = %v", err)
+			}
+			if got := patchedContainerNames(t, ssaPatch, fieldContainers); !slices.Equal(got, tt.wantContainer) {
+				t.Errorf("SSA patch containers = %v, want %v", got, tt.wantContainer)
+			}
+			if got := patchedContainerNames(t, ssaPatch, fieldInitContainers); !slices.Equal(got, tt.wantInit) {
+				t.Errorf("SSA patch initContainers = %v, want %v", got, tt.wantInit)
+			}
+
+			// A strategic merge patch carries the whole list holding a changed container
+			mergePatch, err := engine.buildResourcePatch(newInitContainerTestWorkload(), changes, policy)
+			if err != nil {
+				t.Fatalf("buildResourcePatch() error = %v", err)
+			}
+			wantInit := []string(nil)
+			if len(tt.wantInit) > 0 {
+				wantInit = []string{"migrate", "proxy"}
+			}
+			if got := patchedContainerNames(t, mergePatch, fieldInitContainers); !slices.Equal(got, wantInit) {
+				t.Errorf("merge patch initContainers = %v, want %v", got, wantInit)
+			}
+		})
+	}
+}
+
+func TestBuildPatches_EphemeralContainers(t *testing.T) {
+	engine := &Engine{}
+	policy := createMockPolicy(true, true)
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("80m"), Memory: resource.MustParse("48Mi")}
+
+	// kubectl debug adds ephemeral containers, which are never sized nor patched
+	workload := newInitContainerTestWorkload()
+	_ = unstructured.SetNestedSlice(workload.Object.Object, []interface{}{
+		map[string]interface{}{"name": "debugger", "image": "busybox"},
+	}, "spec", "template", "spec", "ephemeralContainers")
+
+	if _, err := templateContainers(workload.Object, "ephemeralContainers"); err == nil {
+		t.Error("templateContainers() read ephemeral containers, want an error")
+	}
+	resources, err := engine.getCurrentResources(workload)
+	if err != nil {
+		t.Fatalf("getCurrentResources() error = %v", err)
+	}
+	if _, ok := resources["debugger"]; ok {
+		t.Error("the ephemeral container must not be part of the current resources")
+	}
+
+	changes := []ContainerChange{{Container: "test-container", Recommendation: rec}}
+	ssaPatch, err := engine.buildSSAPatch(workload, changes, policy)
+	if err != nil {
+		t.Fatalf("buildSSAPatch() error = %v", err)
+	}
+	mergePatch, err := engine.buildResourcePatch(workload, changes, policy)
+	if err != nil {
+		t.Fatalf("buildResourcePatch() error = %v", err)
+	}
+	for name, patch := range map[string][]byte{"SSA": ssaPatch, "strategic merge": mergePatch} {
+		if got := patchedContainerNames(t, patch, "ephemeralContainers"); len(got) != 0 {
+			t.Errorf("%s patch ephemeralContainers = %v, want none", name, got)
+		}
+	}
+
+	// A change naming the ephemeral container is refused rather than adding it as a container
+	debug := []ContainerChange{{Container: "debugger", Recommendation: rec}}
+	if _, err := engine.buildSSAPatch(workload, debug, policy); err == nil {
+		t.Error("buildSSAPatch() patched the ephemeral container, want an error")
+	}
+	if _, err := engine.buildResourcePatch(workload, debug, policy); err == nil {
+		t.Error("buildResourcePatch() patched the ephemeral container, want an error")
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// inPlaceResizeConflict returns a description of why the API server would reject an in-place
+// resize of the container with the recommended resources, or an empty string if there is none.
+//
+// The pod QoS class is immutable, so a resize is rejected when it changes the class. Guaranteed
+// containers keep requests == limits unless the policy allows QoS class changes, in which case
+// changing only their requests, e.g. with updateRequestsOnly, makes the pod Burstable. Setting
+// requests on a BestEffort pod makes it Burstable as well.
+func (e *Engine) inPlaceResizeConflict(
+	currentResources map[string]corev1.ResourceRequirements,
+	containerName string,
+	rec *recommendation.Recommendation,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) string {
+	current, ok := currentResources[containerName]
+	if !ok {
+		return ""
+	}
+	proposed := e.proposedResources(current, rec, policy)
+
+	resized := make(map[string]corev1.ResourceRequirements, len(currentResources))
+	for name, reqs := range currentResources {
+		resized[name] = reqs
+	}
+	resized[containerName] = proposed
+
+	currentQOS := podQOSClass(currentResources)
+	resizedQOS := podQOSClass(resized)
+	if currentQOS != resizedQOS {
+		reason := fmt.Sprintf("pod QoS class would change from %s to %s", currentQOS, resizedQOS)
+		if currentQOS == corev1.PodQOSGuaranteed && policy.Spec.UpdateStrategy.UpdateRequestsOnly {
+			reason += fmt.Sprintf(" because updateRequestsOnly leaves the limits of container %s unchanged", containerName)
+		}
+		return reason
+	}
+
+	return ""
+}
+
+// resizeRestartResources returns the resources an in-place resize of the container changes and
+// whose resizePolicy is RestartContainer. Resizing any of them restarts the container, so the
+// resize is disruptive. Resources without a resize policy are resized without a restart.
+func (e *Engine) resizeRestartResources(
+	workload *Workload,
+	currentResources map[string]corev1.ResourceRequirements,
+	containerName string,
+	rec *recommendation.Recommendation,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) []corev1.ResourceName {
+	current, ok := currentResources[containerName]
+	if !ok || workload.Object == nil {
+		return nil
+	}
+	container, ok := findTemplateContainer(workload.Object, containerName)
+	if !ok {
+		return nil
+	}
+	proposed := e.proposedResources(current, rec, policy)
+
+	var restarts []corev1.ResourceName
+	resizePolicies, _, _ := unstructured.NestedSlice(container, "resizePolicy")
+	for _, p := range resizePolicies {
+		resizePolicy, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		restartPolicy, _, _ := unstructured.NestedString(resizePolicy, "restartPolicy")
+		if restartPolicy != string(corev1.RestartContainer) {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(resizePolicy, "resourceName")
+		resourceName := corev1.ResourceName(name)
+		if !sameResourceQuantity(current.Requests, proposed.Requests, resourceName) ||
+			!sameResourceQuantity(current.Limits, proposed.Limits, resourceName) {
+			restarts = append(restarts, resourceName)
+		}
+	}
+	return restarts
+}
+
+// markContainerRestarts flags the containers of an apply whose in-place resize restarts them.
+// Nothing is flagged when the pods are recreated instead, which restarts every container anyway.
+func (e *Engine) markContainerRestarts(
+	ctx context.Context,
+	workload *Workload,
+	currentResources map[string]corev1.ResourceRequirements,
+	result *ApplyResult,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) {
+	if !policy.Spec.UpdateStrategy.AllowInPlaceResize {
+		return
+	}
+
+	var restarting []string
+	for name, container := range result.Containers {
+		if len(e.resizeRestartResources(workload, currentResources, name, container.Applied, policy)) > 0 {
+			restarting = append(restarting, name)
+		}
+	}
+	if len(restarting) == 0 {
+		return
+	}
+	if supported, err := e.detectInPlaceResize(ctx); err != nil || !supported {
+		return
+	}
+	for _, name := range restarting {
+		container := result.Containers[name]
+		container.RestartsContainer = true
+		result.Containers[name] = container
+	}
+}
+
+// proposedResources returns the resources of a container once the recommendation is applied.
+// Resources the policy does not optimize keep their current requests and limits.
+func (e *Engine) proposedResources(
+	current corev1.ResourceRequirements,
+	rec *recommendation.Recommendation,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) corev1.ResourceRequirements {
+	proposed := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
+	}
+	for name, quantity := range current.Requests {
+		proposed.Requests[name] = quantity
+	}
+	for name, quantity := range current.Limits {
+		proposed.Limits[name] = quantity
+	}
+
+	cpuLimit, memoryLimit := e.calculateLimits(rec, policy)
+	if policy.OptimizesCPU() {
+		setProposedResource(&proposed, corev1.ResourceCPU, rec.CPU, cpuLimit, rec, policy)
+	}
+	if policy.OptimizesMemory() {
+		setProposedResource(&proposed, corev1.ResourceMemory, rec.Memory, memoryLimit, rec, policy)
+	}
+
+	// Guaranteed containers keep their limits equal to their requests
+	if keepsGuaranteed(current, policy) {
+		if policy.OptimizesCPU() {
+			proposed.Limits[corev1.ResourceCPU] = rec.CPU
+		}
+		if policy.OptimizesMemory() {
+			proposed.Limits[corev1.ResourceMemory] = rec.Memory
+		}
+	}
+	return proposed
+}
+
+// setProposedResource sets the request of a resource and updates or removes its limit as the
+// policy update strategy asks. A limit limitConfig does not manage keeps its current value, as
+// does one the recommendation keeps.
+func setProposedResource(
+	proposed *corev1.ResourceRequirements,
+	name corev1.ResourceName,
+	request, limit resource.Quantity,
+	rec *recommendation.Recommendation,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) {
+	proposed.Requests[name] = request
+	switch {
+	case !managesLimit(policy, name):
+	case policy.Spec.UpdateStrategy.RemoveLimits:
+		delete(proposed.Limits, name)
+	case !policy.Spec.UpdateStrategy.UpdateRequestsOnly && !rec.KeepLimits:
+		proposed.Limits[name] = limit
+	}
+}
+
+// podQOSClass computes the QoS class of a pod from the CPU and memory resources of its
+// containers, following the rules the API server uses. A missing request defaults to the limit.
+func podQOSClass(resources map[string]corev1.ResourceRequirements) corev1.PodQOSClass {
+	hasResources := false
+	guaranteed := true
+
+	for _, reqs := range resources {
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			limit, hasLimit := reqs.Limits[name]
+			request, hasRequest := reqs.Requests[name]
+			if !hasRequest && hasLimit {
+				request, hasRequest = limit, true
+			}
+
+			if (hasRequest && !request.IsZero()) || (hasLimit && !limit.IsZero()) {
+				hasResources = true
+			}
+			if !hasRequest || !hasLimit || request.Cmp(limit) != 0 {
+				guaranteed = false
+			}
+		}
+	}
+
+	switch {
+	case !hasResources:
+		return corev1.PodQOSBestEffort
+	case guaranteed:
+		return corev1.PodQOSGuaranteed
+	default:
+		return corev1.PodQOSBurstable
+	}
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/version"
+
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+func TestCanApply_InPlaceResizeConflicts(t *testing.T) {
+	guaranteed := map[string]interface{}{
+		"requests": map[string]interface{}{"cpu": "500m", "memory": "512Mi"},
+		"limits":   map[string]interface{}{"cpu": "500m", "memory": "512Mi"},
+	}
+	burstable := map[string]interface{}{
+		"requests": map[string]interface{}{"cpu": "250m", "memory": "256Mi"},
+		"limits":   map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+	}
+
+	tests := []struct {
+		name               string
+		resources          map[string]interface{}
+		rec                *recommendation.Recommendation
+		updateRequestsOnly bool
+		removeLimits       bool
+		allowQoSChange     bool
+		allowRecreate      bool
+		wantCanApply       bool
+		wantMethod         ApplyMethod
+		wantReason         string
+	}{
+		{
+			name:               "guaranteed requests-only resize keeps the limits equal and stays in-place",
+			resources:          guaranteed,
+			rec:                &recommendation.Recommendation{CPU: resource.MustParse("600m"), Memory: resource.MustParse("600Mi")},
+			updateRequestsOnly: true,
+			allowRecreate:      false,
+			wantCanApply:       true,
+			wantMethod:         InPlace,
+		},
+		{
+			name:          "guaranteed resize with removeLimits keeps the limits equal and stays in-place",
+			resources:     guaranteed,
+			rec:           &recommendation.Recommendation{CPU: resource.MustParse("400m"), Memory: resource.MustParse("600Mi")},
+			removeLimits:  true,
+			allowRecreate: false,
+			wantCanApply:  true,
+			wantMethod:    InPlace,
+		},
+		{
+			name:               "guaranteed requests-only resize falls back to recreate",
+			resources:          guaranteed,
+			rec:                &recommendation.Recommendation{CPU: resource.MustParse("600m"), Memory: resource.MustParse("600Mi")},
+			updateRequestsOnly: true,
+			allowQoSChange:     true,
+			allowRecreate:      true,
+			wantCanApply:       true,
+			wantMethod:         Recreate,
+			wantReason:         "pod QoS class would change from Guaranteed to Burstable because updateRequestsOnly",
+		},
+		{
+			name:               "guaranteed requests-only resize is skipped without recreate",
+			resources:          guaranteed,
+			rec:                &recommendation.Recommendation{CPU: resource.MustParse("600m"), Memory: resource.MustParse("600Mi")},
+			updateRequestsOnly: true,
+			allowQoSChange:     true,
+			allowRecreate:      false,
+			wantCanApply:       false,
+			wantMethod:         Skip,
+			wantReason:         "recreate is not allowed",
+		},
+		{
+			name:               "guaranteed resize matching the limits stays in-place",
+			resources:          guaranteed,
+			rec:                &recommendation.Recommendation{CPU: resource.MustParse("500m"), Memory: resource.MustParse("512Mi")},
+			updateRequestsOnly: true,
+			allowRecreate:      false,
+			wantCanApply:       true,
+			wantMethod:         InPlace,
+		},
+		{
+			name:               "guaranteed resize with limit headroom falls back to recreate",
+			resources:          guaranteed,
+			rec:                &recommendation.Recommendation{CPU: resource.MustParse("600m"), Memory: resource.MustParse("600Mi")},
+			updateRequestsOnly: false,
+			allowQoSChange:     true,
+			allowRecreate:      true,
+			wantCanApply:       true,
+			wantMethod:         Recreate,
+			wantReason:         "pod QoS class would change from Guaranteed to Burstable",
+		},
+		{
+			name:           "removing the limits of a guaranteed pod falls back to recreate",
+			resources:      guaranteed,
+			rec:            &recommendation.Recommendation{CPU: resource.MustParse("500m"), Memory: resource.MustParse("512Mi")},
+			removeLimits:   true,
+			allowQoSChange: true,
+			allowRecreate:  true,
+			wantCanApply:   true,
+			wantMethod:     Recreate,
+			wantReason:     "pod QoS class would change from Guaranteed to Burstable",
+		},
+		{
+			name:               "best-effort resize falls back to recreate",
+			resources:          nil,
+			rec:                &recommendation.Recommendation{CPU: resource.MustParse("100m"), Memory: resource.MustParse("128Mi")},
+			updateRequestsOnly: true,
+			allowRecreate:      true,
+			wantCanApply:       true,
+			wantMethod:         Recreate,
+			wantReason:         "pod QoS class would change from BestEffort to Burstable",
+		},
+		{
+			name:               "burstable requests-only resize stays in-place",
+			resources:          burstable,
+			rec:                &recommendation.Recommendation{CPU: resource.MustParse("300m"), Memory: resource.MustParse("1Gi")},
+			updateRequestsOnly: true,
+			allowRecreate:      false,
+			wantCanApply:       true,
+			wantMethod:         InPlace,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &Engine{
+				discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}},
+			}
+
+			policy := createMockPolicy(true, tt.allowRecreate)
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = tt.updateRequestsOnly
+			policy.Spec.UpdateStrategy.RemoveLimits = tt.removeLimits
+			policy.Spec.UpdateStrategy.AllowQoSClassChange = tt.allowQoSChange
+
+			decision, err := engine.CanApply(context.Background(), createMockWorkloadWithResources(tt.resources), "test-container", tt.rec, policy)
+			if err != nil {
+				t.Fatalf("CanApply() error = %v", err)
+			}
+
+			if decision.CanApply != tt.wantCanApply || decision.Method != tt.wantMethod {
+				t.Errorf("CanApply() = (%v, %s), want (%v, %s); reason: %s",
+					decision.CanApply, decision.Method, tt.wantCanApply, tt.wantMethod, decision.Reason)
+			}
+			if tt.wantReason != "" && !strings.Contains(decision.Reason, tt.wantReason) {
+				t.Errorf("CanApply() reason = %q, want it to contain %q", decision.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestPodQOSClass(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources map[string]corev1.ResourceRequirements
+		want      corev1.PodQOSClass
+	}{
+		{
+			name:      "no resources",
+			resources: map[string]corev1.ResourceRequirements{"app": {}},
+			want:      corev1.PodQOSBestEffort,
+		},
+		{
+			name: "limits only default the requests",
+			resources: map[string]corev1.ResourceRequirements{"app": {
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			}},
+			want: corev1.PodQOSGuaranteed,
+		},
+		{
+			name: "one burstable container makes the pod burstable",
+			resources: map[string]corev1.ResourceRequirements{
+				"app": {
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+				"sidecar": {
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+				},
+			},
+			want: corev1.PodQOSBurstable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := podQOSClass(tt.resources); got != tt.want {
+				t.Errorf("podQOSClass() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// newResizePolicyTestWorkload returns a Burstable workload whose container has a resizePolicy
+// entry for each resource in restartPolicies
+func newResizePolicyTestWorkload(restartPolicies map[corev1.ResourceName]corev1.ResourceResizeRestartPolicy) *Workload {
+	workload := createMockWorkloadWithResources(map[string]interface{}{
+		"requests": map[string]interface{}{"cpu": "250m", "memory": "256Mi"},
+	})
+	var resizePolicy []interface{}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if restartPolicy, ok := restartPolicies[name]; ok {
+			resizePolicy = append(resizePolicy, map[string]interface{}{
+				"resourceName":  string(name),
+				"restartPolicy": string(restartPolicy),
+			})
+		}
+	}
+	if resizePolicy != nil {
+		containers, _, _ := unstructured.NestedSlice(workload.Object.Object, "spec", "template", "spec", "containers")
+		containers[0].(map[string]interface{})["resizePolicy"] = resizePolicy
+		_ = unstructured.SetNestedSlice(workload.Object.Object, containers, "spec", "template", "spec", "containers")
+	}
+	return workload
+}
+
+func TestCanApply_ResizePolicy(t *testing.T) {
+	restart, notRequired := corev1.RestartContainer, corev1.NotRequired
+	cpuOnly := &recommendation.Recommendation{CPU: resource.MustParse("300m"), Memory: resource.MustParse("256Mi")}
+	memoryOnly := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse("300Mi")}
+	both := &recommendation.Recommendation{CPU: resource.MustParse("300m"), Memory: resource.MustParse("300Mi")}
+
+	tests := []struct {
+		name          string
+		cpuPolicy     *corev1.ResourceResizeRestartPolicy
+		memoryPolicy  *corev1.ResourceResizeRestartPolicy
+		rec           *recommendation.Recommendation
+		allowRecreate bool
+		wantCanApply  bool
+		wantRestarts  bool
+	}{
+		{name: "no resize policy", rec: both, wantCanApply: true},
+		{name: "both NotRequired", cpuPolicy: &notRequired, memoryPolicy: &notRequired, rec: both, wantCanApply: true},
+		{name: "memory RestartContainer, CPU change only", memoryPolicy: &restart, rec: cpuOnly, wantCanApply: true},
+		{name: "memory RestartContainer, memory change without recreate", memoryPolicy: &restart, rec: memoryOnly},
+		{
+			name: "memory RestartContainer, memory change with recreate", memoryPolicy: &restart, rec: memoryOnly,
+			allowRecreate: true, wantCanApply: true, wantRestarts: true,
+		},
+		{name: "CPU RestartContainer, memory change only", cpuPolicy: &restart, memoryPolicy: &notRequired, rec: memoryOnly, wantCanApply: true},
+		{name: "CPU RestartContainer, CPU change without recreate", cpuPolicy: &restart, rec: cpuOnly},
+		{
+			name: "both RestartContainer with recreate", cpuPolicy: &restart, memoryPolicy: &restart, rec: both,
+			allowRecreate: true, wantCanApply: true, wantRestarts: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restartPolicies := map[corev1.ResourceName]corev1.ResourceResizeRestartPolicy{}
+			if tt.cpuPolicy != nil {
+				restartPolicies[corev1.ResourceCPU] = *tt.cpuPolicy
+			}
+			if tt.memoryPolicy != nil {
+				restartPolicies[corev1.ResourceMemory] = *tt.memoryPolicy
+			}
+			engine := &Engine{
+				discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}},
+			}
+			policy := createMockPolicy(true, tt.allowRecreate)
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = true
+
+			decision, err := engine.CanApply(context.Background(), newResizePolicyTestWorkload(restartPolicies), "test-container", tt.rec, policy)
+			if err != nil {
+				t.Fatalf("CanApply() error = %v", err)
+			}
+			if decision.CanApply != tt.wantCanApply || decision.RestartsContainer != tt.wantRestarts {
+				t.Errorf("CanApply() = %v, restarts %v (%s), want %v, restarts %v",
+					decision.CanApply, decision.RestartsContainer, decision.Reason, tt.wantCanApply, tt.wantRestarts)
+			}
+			if decision.CanApply && decision.Method != InPlace {
+				t.Errorf("Method = %s, want %s", decision.Method, InPlace)
+			}
+			if !decision.CanApply && decision.Cause != ErrDisruptiveResize {
+				t.Errorf("Cause = %v, want %v", decision.Cause, ErrDisruptiveResize)
+			}
+		})
+	}
+}
+
+func TestMarkContainerRestarts(t *testing.T) {
+	workload := newResizePolicyTestWorkload(map[corev1.ResourceName]corev1.ResourceResizeRestartPolicy{
+		corev1.ResourceMemory: corev1.RestartContainer,
+	})
+	engine := &Engine{
+		discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}},
+	}
+	current, err := engine.getCurrentResources(workload)
+	if err != nil {
+		t.Fatalf("getCurrentResources() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		memory       string
+		inPlace      bool
+		wantRestarts bool
+	}{
+		{memory: "300Mi", inPlace: true, wantRestarts: true},
+		{memory: "256Mi", inPlace: true, wantRestarts: false},
+		{memory: "300Mi", inPlace: false, wantRestarts: false},
+	} {
+		policy := createMockPolicy(tt.inPlace, true)
+		policy.Spec.UpdateStrategy.UpdateRequestsOnly = true
+		rec := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse(tt.memory)}
+		result := &ApplyResult{Containers: map[string]ContainerResult{"test-container": {Applied: rec, Converged: true}}}
+
+		engine.markContainerRestarts(context.Background(), workload, current, result, policy)
+		if got := result.Containers["test-container"].RestartsContainer; got != tt.wantRestarts {
+			t.Errorf("memory %s with in-place %v: RestartsContainer = %v, want %v", tt.memory, tt.inPlace, got, tt.wantRestarts)
+		}
+	}
+}
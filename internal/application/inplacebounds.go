@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// inPlaceBoundsExceeded describes the request changes of the container that exceed the policy's
+// in-place bounds, or returns an empty string if there are none. With a convergence rate, the
+// step that would be applied is measured rather than the full recommendation. Setting a request
+// the container does not have yet always exceeds the bounds.
+func inPlaceBoundsExceeded(
+	currentResources map[string]corev1.ResourceRequirements,
+	containerName string,
+	rec *recommendation.Recommendation,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) string {
+	bounds := policy.Spec.UpdateStrategy.InPlaceBounds
+	current, ok := currentResources[containerName]
+	if bounds == nil || !ok {
+		return ""
+	}
+	target := rec
+	if policy.Spec.UpdateStrategy.ConvergenceRate != nil {
+		target, _ = convergenceTarget(currentResources, containerName, rec, policy)
+	}
+
+	var exceeded []string
+	check := func(resourceName corev1.ResourceName, optimized bool, recommended resource.Quantity, maxPercent *int32) {
+		if !optimized || maxPercent == nil || recommended.IsZero() {
+			return
+		}
+		request, ok := current.Requests[resourceName]
+		if !ok || request.IsZero() {
+			exceeded = append(exceeded, fmt.Sprintf("%s request set to %s", resourceName, recommended.String()))
+			return
+		}
+		if change := changePercent(request, recommended); change > float64(*maxPercent) {
+			exceeded = append(exceeded, fmt.Sprintf("%s request changes by %.0f%%, more than %d%%", resourceName, change, *maxPercent))
+		}
+	}
+	check(corev1.ResourceCPU, policy.OptimizesCPU(), target.CPU, bounds.MaxCPUChangePercent)
+	check(corev1.ResourceMemory, policy.OptimizesMemory(), target.Memory, bounds.MaxMemoryChangePercent)
+
+	return strings.Join(exceeded, ", ")
+}
+
+// changePercent returns the change from current to proposed as a percentage of current
+func changePercent(current, proposed resource.Quantity) float64 {
+	return math.Abs(float64(proposed.MilliValue()-current.MilliValue())) * 100 / float64(current.MilliValue())
+}
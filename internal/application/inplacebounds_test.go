@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/version"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+func TestCanApply_InPlaceBounds(t *testing.T) {
+	int32Ptr := func(v int32) *int32 { return &v }
+	rate := 0.25
+
+	tests := []struct {
+		name            string
+		cpu             string
+		memory          string
+		allowRecreate   bool
+		convergenceRate *float64
+		noCPURequest    bool
+		wantCanApply    bool
+		wantMethod      ApplyMethod
+		wantBeyond      bool
+	}{
+		{
+			name:          "small change resizes in-place",
+			cpu:           "275m",
+			memory:        "280Mi",
+			allowRecreate: true,
+			wantCanApply:  true,
+			wantMethod:    InPlace,
+		},
+		{
+			name:          "large CPU change recreates",
+			cpu:           "500m",
+			memory:        "256Mi",
+			allowRecreate: true,
+			wantCanApply:  true,
+			wantMethod:    Recreate,
+			wantBeyond:    true,
+		},
+		{
+			name:          "large memory decrease recreates",
+			cpu:           "250m",
+			memory:        "128Mi",
+			allowRecreate: true,
+			wantCanApply:  true,
+			wantMethod:    Recreate,
+			wantBeyond:    true,
+		},
+		{
+			name:          "large change without recreate is skipped",
+			cpu:           "500m",
+			memory:        "256Mi",
+			allowRecreate: false,
+			wantCanApply:  false,
+			wantMethod:    Skip,
+		},
+		{
+			name:            "a small convergence step of a large change resizes in-place",
+			cpu:             "350m",
+			memory:          "256Mi",
+			allowRecreate:   true,
+			convergenceRate: &rate,
+			wantCanApply:    true,
+			wantMethod:      InPlace,
+		},
+		{
+			name:          "setting a missing request recreates",
+			cpu:           "250m",
+			memory:        "256Mi",
+			allowRecreate: true,
+			noCPURequest:  true,
+			wantCanApply:  true,
+			wantMethod:    Recreate,
+			wantBeyond:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &Engine{
+				discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}},
+			}
+			requests := map[string]interface{}{"cpu": "250m", "memory": "256Mi"}
+			if tt.noCPURequest {
+				delete(requests, "cpu")
+			}
+			workload := createMockWorkloadWithResources(map[string]interface{}{"requests": requests})
+
+			policy := createMockPolicy(true, tt.allowRecreate)
+			policy.Spec.UpdateStrategy.ConvergenceRate = tt.convergenceRate
+			policy.Spec.UpdateStrategy.InPlaceBounds = &optipodv1alpha1.InPlaceBounds{
+				MaxCPUChangePercent:    int32Ptr(20),
+				MaxMemoryChangePercent: int32Ptr(20),
+			}
+			rec := &recommendation.Recommendation{CPU: resource.MustParse(tt.cpu), Memory: resource.MustParse(tt.memory)}
+
+			decision, err := engine.CanApply(context.Background(), workload, "test-container", rec, policy)
+			if err != nil {
+				t.Fatalf("CanApply() error = %v", err)
+			}
+			if decision.CanApply != tt.wantCanApply || decision.Method != tt.wantMethod || decision.BeyondInPlaceBounds != tt.wantBeyond {
+				t.Errorf("CanApply() = %v with %s, beyond bounds %v (%s), want %v with %s, beyond bounds %v",
+					decision.CanApply, decision.Method, decision.BeyondInPlaceBounds, decision.Reason,
+					tt.wantCanApply, tt.wantMethod, tt.wantBeyond)
+			}
+		})
+	}
+}
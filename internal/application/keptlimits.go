@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// keepsLimit reports whether an apply leaves the current limit of a resource in place: with
+// updateRequestsOnly or a recommendation keeping the limits, unless removeLimits removes it, or
+// when limitConfig does not manage it. The limits of a container kept Guaranteed follow its
+// requests, so they are never kept.
+func keepsLimit(
+	policy *optipodv1alpha1.OptimizationPolicy,
+	name corev1.ResourceName,
+	rec *recommendation.Recommendation,
+	keepGuaranteed bool,
+) bool {
+	if keepGuaranteed {
+		return false
+	}
+	if !managesLimit(policy, name) {
+		return true
+	}
+	return (policy.Spec.UpdateStrategy.UpdateRequestsOnly || rec.KeepLimits) && !policy.Spec.UpdateStrategy.RemoveLimits
+}
+
+// keptLimitsTarget returns the recommendation to apply with each optimized request capped at the
+// container's current limit when the apply leaves that limit in place, since the API server
+// rejects requests above limits. The second result lists the capped resources.
+func keptLimitsTarget(
+	current corev1.ResourceRequirements,
+	rec *recommendation.Recommendation,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	keepGuaranteed bool,
+) (*recommendation.Recommendation, []corev1.ResourceName) {
+	target := *rec
+	var capped []corev1.ResourceName
+	if limit, ok := current.Limits[corev1.ResourceCPU]; ok && policy.OptimizesCPU() &&
+		keepsLimit(policy, corev1.ResourceCPU, rec, keepGuaranteed) && target.CPU.Cmp(limit) > 0 {
+		target.CPU = limit.DeepCopy()
+		capped = append(capped, corev1.ResourceCPU)
+	}
+	if limit, ok := current.Limits[corev1.ResourceMemory]; ok && policy.OptimizesMemory() &&
+		keepsLimit(policy, corev1.ResourceMemory, rec, keepGuaranteed) && target.Memory.Cmp(limit) > 0 {
+		target.Memory = limit.DeepCopy()
+		capped = append(capped, corev1.ResourceMemory)
+	}
+	if len(capped) == 0 {
+		return rec, nil
+	}
+	return &target, capped
+}
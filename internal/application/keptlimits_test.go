@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+func TestKeptLimitsTarget(t *testing.T) {
+	current := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	// The recommendation exceeds both limits, e.g. sized from limit-based utilization
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("1200m"), Memory: resource.MustParse("1200Mi")}
+
+	tests := []struct {
+		name           string
+		strategy       func(*optipodv1alpha1.UpdateStrategy)
+		keepGuaranteed bool
+		keepLimits     bool
+		wantCapped     []corev1.ResourceName
+	}{
+		{
+			name:       "updateRequestsOnly caps requests at the kept limits",
+			strategy:   func(s *optipodv1alpha1.UpdateStrategy) { s.UpdateRequestsOnly = true },
+			wantCapped: []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory},
+		},
+		{
+			name:     "updated limits follow the requests",
+			strategy: func(s *optipodv1alpha1.UpdateStrategy) { s.UpdateRequestsOnly = false },
+		},
+		{
+			name:     "removed limits do not cap",
+			strategy: func(s *optipodv1alpha1.UpdateStrategy) { s.UpdateRequestsOnly = true; s.RemoveLimits = true },
+		},
+		{
+			name: "limits limitConfig leaves alone cap",
+			strategy: func(s *optipodv1alpha1.UpdateStrategy) {
+				s.UpdateRequestsOnly = false
+				s.LimitConfig = &optipodv1alpha1.LimitConfig{ManageMemoryLimit: ptr.To(false)}
+			},
+			wantCapped: []corev1.ResourceName{corev1.ResourceMemory},
+		},
+		{
+			name:       "a recommendation keeping the limits caps requests at them",
+			strategy:   func(s *optipodv1alpha1.UpdateStrategy) { s.UpdateRequestsOnly = false },
+			keepLimits: true,
+			wantCapped: []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory},
+		},
+		{
+			name:           "limits of Guaranteed containers follow the requests",
+			strategy:       func(s *optipodv1alpha1.UpdateStrategy) { s.UpdateRequestsOnly = true },
+			keepGuaranteed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createMockPolicy(true, false)
+			tt.strategy(&policy.Spec.UpdateStrategy)
+
+			rec := rec
+			if tt.keepLimits {
+				rec = &recommendation.Recommendation{CPU: rec.CPU, Memory: rec.Memory, KeepLimits: true}
+			}
+
+			target, capped := keptLimitsTarget(current, rec, policy, tt.keepGuaranteed)
+			if !slices.Equal(capped, tt.wantCapped) {
+				t.Fatalf("capped = %v, want %v", capped, tt.wantCapped)
+			}
+			for _, name := range capped {
+				got, limit := target.CPU, current.Limits[name]
+				if name == corev1.ResourceMemory {
+					got = target.Memory
+				}
+				if got.Cmp(limit) != 0 {
+					t.Errorf("%s request = %s, want the %s limit", name, got.String(), limit.String())
+				}
+			}
+			if len(capped) == 0 && target != rec {
+				t.Errorf("target = %+v, want the recommendation unchanged", target)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// removeRemainingLimits removes the CPU and memory limits of a container that are still set after
+// a Server-Side Apply without limits. Limits of resources the policy does not optimize, and those
+// limitConfig does not manage, are kept.
+//
+// Omitting a field from an apply only removes it when no other field manager owns it, so limits
+// set by kubectl, Helm or another controller would otherwise be left in place. They are removed
+// with a strategic merge patch, which drops the fields from every manager's ownership.
+func (e *Engine) removeRemainingLimits(
+	ctx context.Context,
+	gvr schema.GroupVersionResource,
+	workload *Workload,
+	applied *unstructured.Unstructured,
+	containerName string,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) error {
+	if applied == nil || !hasOptimizedLimit(applied, containerName, policy) {
+		return nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	log.Info("Removing limits owned by another field manager",
+		"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+		"container", containerName,
+	)
+
+	patch, err := buildRemoveLimitsPatch(containerField(applied, containerName), containerName, policy)
+	if err != nil {
+		return fmt.Errorf("failed to build limit removal patch: %w", err)
+	}
+
+	_, err = e.dynamicClient.Resource(gvr).Namespace(workload.Namespace).Patch(
+		ctx,
+		workload.Name,
+		types.StrategicMergePatchType,
+		patch,
+		metav1.PatchOptions{FieldManager: "optipod"},
+	)
+	if err != nil {
+		if errors.IsForbidden(err) {
+			return fmt.Errorf("%w: insufficient permissions to remove limits: %w", ErrRBACDenied, err)
+		}
+		return fmt.Errorf("failed to remove limits of container %s: %w", containerName, err)
+	}
+
+	return nil
+}
+
+// hasOptimizedLimit reports whether the named container of a workload sets a limit optipod
+// manages on a resource the policy optimizes
+func hasOptimizedLimit(obj *unstructured.Unstructured, containerName string, policy *optipodv1alpha1.OptimizationPolicy) bool {
+	container, ok := findTemplateContainer(obj, containerName)
+	if !ok {
+		return false
+	}
+
+	limits, _, _ := unstructured.NestedMap(container, "resources", "limits")
+	for name := range limitValues(policy, nil, nil) {
+		if _, ok := limits[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// buildRemoveLimitsPatch builds a strategic merge patch that deletes the CPU and memory limits of
+// a container the policy optimizes, as far as limitConfig manages them. Other limits, such as
+// ephemeral storage or extended resources, are kept. field is the pod template list holding the container.
+func buildRemoveLimitsPatch(field, containerName string, policy *optipodv1alpha1.OptimizationPolicy) ([]byte, error) {
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					field: []interface{}{
+						map[string]interface{}{
+							"name": containerName,
+							"resources": map[string]interface{}{
+								"limits": limitValues(policy, nil, nil),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	patchUnstructured := &unstructured.Unstructured{Object: patch}
+	return patchUnstructured.MarshalJSON()
+}
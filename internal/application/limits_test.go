@@ -0,0 +1,285 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/dynamic"
+)
+
+// newRemoveLimitsTestWorkload returns a deployment whose container has CPU, memory and
+// ephemeral storage limits
+func newRemoveLimitsTestWorkload() *Workload {
+	workload := createMockWorkload()
+	_ = unstructured.SetNestedField(workload.Object.Object, "apps/v1", "apiVersion")
+	_ = unstructured.SetNestedField(workload.Object.Object, "Deployment", "kind")
+
+	containers, _, _ := unstructured.NestedSlice(workload.Object.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	_ = unstructured.SetNestedField(container, "1Gi", "resources", "limits", "ephemeral-storage")
+	_ = unstructured.SetNestedSlice(workload.Object.Object, []interface{}{container}, "spec", "template", "spec", "containers")
+
+	return workload
+}
+
+// containerResources returns the requests and limits of the first container of a deployment
+func containerResources(t *testing.T, obj map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	t.Helper()
+
+	containers, _, _ := unstructured.NestedSlice(obj, "spec", "template", "spec", "containers")
+	if len(containers) != 1 {
+		t.Fatalf("got %d containers, want 1", len(containers))
+	}
+	container := containers[0].(map[string]interface{})
+	requests, _, _ := unstructured.NestedMap(container, "resources", "requests")
+	limits, _, _ := unstructured.NestedMap(container, "resources", "limits")
+	return requests, limits
+}
+
+func TestBuildResourcePatch_RemoveLimits(t *testing.T) {
+	engine := &Engine{}
+	workload := newRemoveLimitsTestWorkload()
+	policy := createMockPolicy(true, false)
+	policy.Spec.UpdateStrategy.UpdateRequestsOnly = false
+	policy.Spec.UpdateStrategy.RemoveLimits = true
+
+	patch, err := engine.buildResourcePatch(workload, []ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, policy)
+	if err != nil {
+		t.Fatalf("failed to build patch: %v", err)
+	}
+
+	// Apply the patch the way the API server does
+	original, err := workload.Object.MarshalJSON()
+	if err != nil {
+		t.Fatalf("failed to encode workload: %v", err)
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, patch, appsv1.Deployment{})
+	if err != nil {
+		t.Fatalf("failed to apply patch: %v", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(patched, &result); err != nil {
+		t.Fatalf("failed to parse patched workload: %v", err)
+	}
+
+	requests, limits := containerResources(t, result)
+	if requests["cpu"] != createMockRecommendation().CPU.String() || requests["memory"] != createMockRecommendation().Memory.String() {
+		t.Errorf("requests = %v, want the recommendation", requests)
+	}
+	if _, ok := limits["cpu"]; ok {
+		t.Errorf("CPU limit was not removed: %v", limits)
+	}
+	if _, ok := limits["memory"]; ok {
+		t.Errorf("memory limit was not removed: %v", limits)
+	}
+	if limits["ephemeral-storage"] != "1Gi" {
+		t.Errorf("ephemeral storage limit = %v, want it kept", limits["ephemeral-storage"])
+	}
+}
+
+func TestBuildSSAPatch_RemoveLimits(t *testing.T) {
+	engine := &Engine{}
+	policy := createMockPolicy(true, false)
+	policy.Spec.UpdateStrategy.UpdateRequestsOnly = false
+	policy.Spec.UpdateStrategy.RemoveLimits = true
+
+	patch, err := engine.buildSSAPatch(newRemoveLimitsTestWorkload(), []ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, policy)
+	if err != nil {
+		t.Fatalf("failed to build SSA patch: %v", err)
+	}
+
+	var patchObj map[string]interface{}
+	if err := json.Unmarshal(patch, &patchObj); err != nil {
+		t.Fatalf("failed to parse patch: %v", err)
+	}
+
+	// Limits are left out of the apply so optipod no longer owns them
+	requests, limits := containerResources(t, patchObj)
+	if requests["cpu"] != createMockRecommendation().CPU.String() || requests["memory"] != createMockRecommendation().Memory.String() {
+		t.Errorf("requests = %v, want the recommendation", requests)
+	}
+	if limits != nil {
+		t.Errorf("limits = %v, want none in the apply", limits)
+	}
+}
+
+func TestBuildPatches_KeepLimits(t *testing.T) {
+	engine := &Engine{}
+	policy := createMockPolicy(true, false)
+	policy.Spec.UpdateStrategy.UpdateRequestsOnly = false
+	rec := createMockRecommendation()
+	rec.KeepLimits = true
+	changes := []ContainerChange{{Container: "test-container", Recommendation: rec}}
+
+	// The Server-Side Apply patch applies the current limits, so optipod keeps owning them
+	ssaPatch, err := engine.buildSSAPatch(newRemoveLimitsTestWorkload(), changes, policy)
+	if err != nil {
+		t.Fatalf("failed to build SSA patch: %v", err)
+	}
+	var patchObj map[string]interface{}
+	if err := json.Unmarshal(ssaPatch, &patchObj); err != nil {
+		t.Fatalf("failed to parse patch: %v", err)
+	}
+	_, limits := containerResources(t, patchObj)
+	if limits["cpu"] != "1000m" || limits["memory"] != "1Gi" {
+		t.Errorf("SSA limits = %v, want the current limits", limits)
+	}
+
+	// The strategic merge patch leaves them alone
+	workload := newRemoveLimitsTestWorkload()
+	patch, err := engine.buildResourcePatch(workload, changes, policy)
+	if err != nil {
+		t.Fatalf("failed to build patch: %v", err)
+	}
+	original, err := workload.Object.MarshalJSON()
+	if err != nil {
+		t.Fatalf("failed to encode workload: %v", err)
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, patch, appsv1.Deployment{})
+	if err != nil {
+		t.Fatalf("failed to apply patch: %v", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(patched, &result); err != nil {
+		t.Fatalf("failed to parse patched workload: %v", err)
+	}
+	requests, limits := containerResources(t, result)
+	if requests["cpu"] != rec.CPU.String() || requests["memory"] != rec.Memory.String() {
+		t.Errorf("requests = %v, want the recommendation", requests)
+	}
+	if limits["cpu"] != "1000m" || limits["memory"] != "1Gi" {
+		t.Errorf("limits = %v, want the current limits", limits)
+	}
+}
+
+// patchCall is a patch sent to the API server
+type patchCall struct {
+	patchType types.PatchType
+	data      []byte
+}
+
+// mockDynamicClientWithResult records patches and returns a fixed object, or a fixed error,
+// for every patch
+type mockDynamicClientWithResult struct {
+	dynamic.Interface
+	dynamic.NamespaceableResourceInterface
+	result *unstructured.Unstructured
+	err    error
+	calls  []patchCall
+}
+
+func (m *mockDynamicClientWithResult) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return m
+}
+
+func (m *mockDynamicClientWithResult) Namespace(ns string) dynamic.ResourceInterface {
+	return m
+}
+
+func (m *mockDynamicClientWithResult) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	m.calls = append(m.calls, patchCall{patchType: pt, data: data})
+	return m.result, m.err
+}
+
+func TestApplyWithSSA_RemoveLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		result    *unstructured.Unstructured
+		wantCalls []types.PatchType
+	}{
+		{
+			name:      "limits owned only by optipod are removed by the apply",
+			result:    createMockWorkloadWithoutLimits(),
+			wantCalls: []types.PatchType{types.ApplyPatchType},
+		},
+		{
+			name:      "limits owned by another field manager are removed explicitly",
+			result:    newRemoveLimitsTestWorkload().Object,
+			wantCalls: []types.PatchType{types.ApplyPatchType, types.StrategicMergePatchType},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := &mockDynamicClientWithResult{result: tt.result}
+			engine := &Engine{dynamicClient: dynamicClient}
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.RemoveLimits = true
+
+			err := engine.ApplyWithSSA(context.Background(), newRemoveLimitsTestWorkload(), []ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, policy)
+			if err != nil {
+				t.Fatalf("ApplyWithSSA() error = %v", err)
+			}
+
+			if len(dynamicClient.calls) != len(tt.wantCalls) {
+				t.Fatalf("sent %d patches, want %d", len(dynamicClient.calls), len(tt.wantCalls))
+			}
+			for i, call := range dynamicClient.calls {
+				if call.patchType != tt.wantCalls[i] {
+					t.Errorf("patch %d type = %s, want %s", i, call.patchType, tt.wantCalls[i])
+				}
+			}
+
+			if len(dynamicClient.calls) < 2 {
+				return
+			}
+
+			// The removal deletes only the CPU and memory limits
+			patched, err := strategicpatch.StrategicMergePatch(mustMarshal(t, tt.result), dynamicClient.calls[1].data, appsv1.Deployment{})
+			if err != nil {
+				t.Fatalf("failed to apply limit removal patch: %v", err)
+			}
+			var result map[string]interface{}
+			if err := json.Unmarshal(patched, &result); err != nil {
+				t.Fatalf("failed to parse patched workload: %v", err)
+			}
+			_, limits := containerResources(t, result)
+			if len(limits) != 1 || limits["ephemeral-storage"] != "1Gi" {
+				t.Errorf("limits after removal = %v, want only ephemeral-storage", limits)
+			}
+		})
+	}
+}
+
+// createMockWorkloadWithoutLimits returns the mock deployment with its limits removed
+func createMockWorkloadWithoutLimits() *unstructured.Unstructured {
+	obj := createMockWorkload().Object
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	unstructured.RemoveNestedField(container, "resources", "limits")
+	_ = unstructured.SetNestedSlice(obj.Object, []interface{}{container}, "spec", "template", "spec", "containers")
+	return obj
+}
+
+func mustMarshal(t *testing.T, obj *unstructured.Unstructured) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		t.Fatalf("failed to encode object: %v", err)
+	}
+	return data
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// livePodResources returns the resources the running pods of a workload actually have, per
+// container. After an in-place resize these differ from the workload's pod template.
+//
+// A container's resources are taken from its status when the kubelet reports them, and from the
+// pod spec otherwise. The second result is false when there are no running pods or the pods
+// disagree (e.g. during a rollout), in which case the template is the better estimate.
+func (e *Engine) livePodResources(ctx context.Context, workload *Workload) (map[string]corev1.ResourceRequirements, bool, error) {
+	if e.client == nil || workload.Object == nil {
+		return nil, false, nil
+	}
+
+	pods, err := e.workloadPods(ctx, workload.Namespace, workload.Object)
+	if err != nil || pods == nil {
+		return nil, false, err
+	}
+
+	var live map[string]corev1.ResourceRequirements
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		resources := podResources(pod)
+		if live == nil {
+			live = resources
+			continue
+		}
+		if !sameContainerResources(live, resources) {
+			return nil, false, nil
+		}
+	}
+
+	return live, live != nil, nil
+}
+
+// workloadPods lists the pods matching the workload's selector. It returns nil when the workload
+// has no selector.
+func (e *Engine) workloadPods(ctx context.Context, namespace string, obj *unstructured.Unstructured) ([]corev1.Pod, error) {
+	selector, err := workloadSelector(obj)
+	if err != nil || selector == nil {
+		return nil, err
+	}
+
+	podList := &corev1.PodList{}
+	if err := e.client.List(ctx, podList,
+		client.InNamespace(namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	return podList.Items, nil
+}
+
+// workloadSelector returns the workload's pod selector, or nil when it has none
+func workloadSelector(obj *unstructured.Unstructured) (labels.Selector, error) {
+	selectorMap, found, err := unstructured.NestedMap(obj.Object, "spec", "selector")
+	if err != nil || !found {
+		return nil, err
+	}
+	labelSelector := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selectorMap, labelSelector); err != nil {
+		return nil, fmt.Errorf("failed to parse workload selector: %w", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert label selector: %w", err)
+	}
+	return selector, nil
+}
+
+// podResources returns the resources of each container of a pod, preferring the resources the
+// kubelet reports in the container status over the desired resources in the spec. Native
+// sidecars are included; run-once init containers are not.
+func podResources(pod *corev1.Pod) map[string]corev1.ResourceRequirements {
+	resources := make(map[string]corev1.ResourceRequirements, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		resources[container.Name] = container.Resources
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			resources[container.Name] = container.Resources
+		}
+	}
+	statuses := slices.Concat(pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses)
+	for _, status := range statuses {
+		if _, ok := resources[status.Name]; ok && status.Resources != nil {
+			resources[status.Name] = *status.Resources
+		}
+	}
+	return resources
+}
+
+// sameContainerResources reports whether two sets of container resources have the same CPU and
+// memory requests and limits
+func sameContainerResources(a, b map[string]corev1.ResourceRequirements) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, reqs := range a {
+		other, ok := b[name]
+		if !ok || !sameResources(reqs, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameResources reports whether two resource requirements have the same CPU and memory requests
+// and limits
+func sameResources(a, b corev1.ResourceRequirements) bool {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if !sameResourceQuantity(a.Requests, b.Requests, name) || !sameResourceQuantity(a.Limits, b.Limits, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameResourceQuantity reports whether a resource is unset in both lists or set to equal quantities
+func sameResourceQuantity(a, b corev1.ResourceList, name corev1.ResourceName) bool {
+	aQuantity, aOK := a[name]
+	bQuantity, bOK := b[name]
+	if aOK != bOK {
+		return false
+	}
+	return !aOK || aQuantity.Cmp(bQuantity) == 0
+}
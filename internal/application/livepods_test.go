@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// newLivePodTestWorkload returns a workload whose template requests 500m CPU and 512Mi memory
+// and selects pods labeled app=test
+func newLivePodTestWorkload() *Workload {
+	workload := createMockWorkloadWithResources(map[string]interface{}{
+		"requests": map[string]interface{}{"cpu": "500m", "memory": "512Mi"},
+	})
+	workload.Object.Object["spec"].(map[string]interface{})["selector"] = map[string]interface{}{
+		"matchLabels": map[string]interface{}{"app": "test"},
+	}
+	return workload
+}
+
+// newLivePod returns a running pod of the test workload. Status resources, when set, are what
+// the kubelet reports after an in-place resize.
+func newLivePod(name string, spec, status corev1.ResourceList) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "test"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test-container", Resources: corev1.ResourceRequirements{Requests: spec}}},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "test-container"}},
+		},
+	}
+	if status != nil {
+		pod.Status.ContainerStatuses[0].Resources = &corev1.ResourceRequirements{Requests: status}
+	}
+	return pod
+}
+
+func resourceList(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func TestCanApply_LivePodResources(t *testing.T) {
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse("256Mi")}
+
+	tests := []struct {
+		name         string
+		pods         []client.Object
+		wantCanApply bool
+		wantReason   string
+	}{
+		{
+			name:         "no running pods compares against the template",
+			wantCanApply: true,
+		},
+		{
+			name:         "pods resized in-place to the recommendation are not patched again",
+			pods:         []client.Object{newLivePod("web-0", resourceList("250m", "256Mi"), nil)},
+			wantCanApply: false,
+			wantReason:   "Running pods already have the recommended resources",
+		},
+		{
+			name: "status resources take precedence over the pod spec",
+			pods: []client.Object{
+				newLivePod("web-0", resourceList("500m", "512Mi"), resourceList("250m", "256Mi")),
+			},
+			wantCanApply: false,
+			wantReason:   "Running pods already have the recommended resources",
+		},
+		{
+			name:         "a pending resize is still applied",
+			pods:         []client.Object{newLivePod("web-0", resourceList("250m", "256Mi"), resourceList("500m", "512Mi"))},
+			wantCanApply: true,
+		},
+		{
+			name: "pods that disagree fall back to the template",
+			pods: []client.Object{
+				newLivePod("web-0", resourceList("250m", "256Mi"), nil),
+				newLivePod("web-1", resourceList("500m", "512Mi"), nil),
+			},
+			wantCanApply: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &Engine{
+				client:          fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tt.pods...).Build(),
+				discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}},
+			}
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = true
+
+			decision, err := engine.CanApply(context.Background(), newLivePodTestWorkload(), "test-container", rec, policy)
+			if err != nil {
+				t.Fatalf("CanApply() error = %v", err)
+			}
+			if decision.CanApply != tt.wantCanApply {
+				t.Errorf("CanApply() = %v (%s), want %v", decision.CanApply, decision.Reason, tt.wantCanApply)
+			}
+			if tt.wantReason != "" && decision.Reason != tt.wantReason {
+				t.Errorf("CanApply() reason = %q, want %q", decision.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestCanApply_LivePodResourcesDecideInPlaceResize(t *testing.T) {
+	// The template is Burstable, but the running pod was resized in-place to Guaranteed, so a
+	// requests-only change would change its QoS class where the policy allows it
+	pod := newLivePod("web-0", nil, nil)
+	pod.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+		Requests: resourceList("1", "1Gi"),
+		Limits:   resourceList("1", "1Gi"),
+	}
+	engine := &Engine{
+		client:          fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod).Build(),
+		discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}},
+	}
+	policy := createMockPolicy(true, false)
+	policy.Spec.UpdateStrategy.UpdateRequestsOnly = true
+	policy.Spec.UpdateStrategy.AllowQoSClassChange = true
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("500m"), Memory: resource.MustParse("1Gi")}
+
+	decision, err := engine.CanApply(context.Background(), newLivePodTestWorkload(), "test-container", rec, policy)
+	if err != nil {
+		t.Fatalf("CanApply() error = %v", err)
+	}
+	want := fmt.Sprintf("pod QoS class would change from %s to %s", corev1.PodQOSGuaranteed, corev1.PodQOSBurstable)
+	if decision.CanApply || !strings.Contains(decision.Reason, want) {
+		t.Errorf("CanApply() = %+v, want a skip because %s", decision, want)
+	}
+}
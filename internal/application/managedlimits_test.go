@@ -0,0 +1,262 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// newMemoryLimitOnlyPolicy returns a policy that optimizes both resources but manages only the
+// memory limit
+func newMemoryLimitOnlyPolicy(removeLimits bool) *optipodv1alpha1.OptimizationPolicy {
+	policy := newResourceOptimizationPolicy(true, true, false)
+	policy.Spec.UpdateStrategy.RemoveLimits = removeLimits
+	policy.Spec.UpdateStrategy.LimitConfig.ManageCPULimit = ptr.To(false)
+	return policy
+}
+
+// Feature: managed-limits, Property 1: CPU limit never written
+// For any recommendation, whether or not the container has a CPU limit, and whether limits are
+// updated or removed, the patches never write the CPU limit while they write the memory limit.
+func TestProperty_UnmanagedCPULimitNeverWritten(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("the patches write the memory limit and never the CPU limit", prop.ForAll(
+		func(cpuMillis, memoryMiB int64, hasCPULimit, removeLimits bool) bool {
+			engine := &Engine{}
+			workload := newRemoveLimitsTestWorkload()
+			if !hasCPULimit {
+				unstructured.RemoveNestedField(workload.Object.Object, "spec", "template", "spec", "containers")
+				_ = unstructured.SetNestedSlice(workload.Object.Object, []interface{}{map[string]interface{}{
+					"name": "test-container",
+					"resources": map[string]interface{}{
+						"requests": map[string]interface{}{"cpu": "500m", "memory": "512Mi"},
+						"limits":   map[string]interface{}{"memory": "1Gi"},
+					},
+				}}, "spec", "template", "spec", "containers")
+			}
+			policy := newMemoryLimitOnlyPolicy(removeLimits)
+			rec := &recommendation.Recommendation{
+				CPU:    *resource.NewMilliQuantity(cpuMillis, resource.DecimalSI),
+				Memory: *resource.NewQuantity(memoryMiB*1024*1024, resource.BinarySI),
+			}
+			changes := []ContainerChange{{Container: "test-container", Recommendation: rec}}
+			_, memoryLimit := engine.calculateLimits(rec, policy)
+
+			// The Server-Side Apply patch leaves the CPU limit out and sets the memory limit
+			// unless limits are removed
+			ssaPatch, err := engine.buildSSAPatch(workload, changes, policy)
+			if err != nil {
+				t.Logf("failed to build SSA patch: %v", err)
+				return false
+			}
+			var applied map[string]interface{}
+			if err := json.Unmarshal(ssaPatch, &applied); err != nil {
+				t.Logf("failed to parse SSA patch: %v", err)
+				return false
+			}
+			_, limits := containerResources(t, applied)
+			if _, ok := limits["cpu"]; ok {
+				t.Logf("SSA patch writes the CPU limit: %v", limits)
+				return false
+			}
+			if _, ok := limits["memory"]; ok == removeLimits {
+				t.Logf("SSA patch limits = %v with removeLimits %v", limits, removeLimits)
+				return false
+			}
+
+			// The strategic merge patch keeps the CPU limit as it was and sets or removes the
+			// memory limit
+			patch, err := engine.buildResourcePatch(workload, changes, policy)
+			if err != nil {
+				t.Logf("failed to build patch: %v", err)
+				return false
+			}
+			original, err := workload.Object.MarshalJSON()
+			if err != nil {
+				t.Logf("failed to encode workload: %v", err)
+				return false
+			}
+			patched, err := strategicpatch.StrategicMergePatch(original, patch, appsv1.Deployment{})
+			if err != nil {
+				t.Logf("failed to apply patch: %v", err)
+				return false
+			}
+			var result map[string]interface{}
+			if err := json.Unmarshal(patched, &result); err != nil {
+				t.Logf("failed to parse patched workload: %v", err)
+				return false
+			}
+			requests, limits := containerResources(t, result)
+			if cpu, ok := limits["cpu"]; ok != hasCPULimit || (ok && cpu != "1000m") {
+				t.Logf("CPU limit = %v, want it unchanged", limits["cpu"])
+				return false
+			}
+			if removeLimits {
+				if _, ok := limits["memory"]; ok {
+					t.Logf("memory limit = %v, want it removed", limits["memory"])
+					return false
+				}
+			} else if limits["memory"] != memoryLimit.String() {
+				t.Logf("memory limit = %v, want %s", limits["memory"], memoryLimit.String())
+				return false
+			}
+			if requests["cpu"] != rec.CPU.String() {
+				t.Logf("CPU request = %v, want the recommendation %s", requests["cpu"], rec.CPU.String())
+				return false
+			}
+			return true
+		},
+		gen.Int64Range(100, 900),
+		gen.Int64Range(128, 8192),
+		gen.Bool(),
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// Feature: managed-limits, Property 2: CPU limit preserved through SSA
+// For any workload, applying with Server-Side Apply while CPU limits are not managed leaves the
+// CPU limit exactly as it was, even when optipod owned it from an earlier apply, and sets the
+// memory limit.
+func TestProperty_UnmanagedCPULimitPreservedThroughSSA(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("the CPU limit is unchanged by the apply", prop.ForAll(
+		func(cpuMillis, memoryMiB int64, hasCPULimit, previouslyManaged bool) bool {
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			engine := newFakeClusterEngine(c, kindDeployment)
+
+			limits := corev1.ResourceList{corev1.ResourceMemory: *resource.NewQuantity(2*memoryMiB*1024*1024, resource.BinarySI)}
+			if hasCPULimit {
+				limits[corev1.ResourceCPU] = *resource.NewMilliQuantity(2*cpuMillis, resource.DecimalSI)
+			}
+			newFakeClusterDeployment(t, c,
+				corev1.ResourceList{
+					corev1.ResourceCPU:    *resource.NewMilliQuantity(cpuMillis, resource.DecimalSI),
+					corev1.ResourceMemory: *resource.NewQuantity(memoryMiB*1024*1024, resource.BinarySI),
+				},
+				limits,
+			)
+
+			rec := &recommendation.Recommendation{
+				CPU:    *resource.NewMilliQuantity(cpuMillis+50, resource.DecimalSI),
+				Memory: *resource.NewQuantity((memoryMiB+64)*1024*1024, resource.BinarySI),
+			}
+
+			// An earlier apply managing both limits makes optipod the owner of the CPU limit
+			if previouslyManaged {
+				workload, _, err := getFakeClusterWorkload(c)
+				if err != nil {
+					t.Logf("failed to get workload: %v", err)
+					return false
+				}
+				if err := engine.ApplyWithSSA(context.Background(), workload,
+					[]ContainerChange{{Container: "test-container", Recommendation: rec}}, newResourceOptimizationPolicy(true, true, false)); err != nil {
+					t.Logf("failed to apply both limits: %v", err)
+					return false
+				}
+				rec = &recommendation.Recommendation{
+					CPU:    *resource.NewMilliQuantity(cpuMillis+100, resource.DecimalSI),
+					Memory: *resource.NewQuantity((memoryMiB+128)*1024*1024, resource.BinarySI),
+				}
+			}
+
+			workload, before, err := getFakeClusterWorkload(c)
+			if err != nil {
+				t.Logf("failed to get workload: %v", err)
+				return false
+			}
+
+			policy := newMemoryLimitOnlyPolicy(false)
+			if err := engine.ApplyWithSSA(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy); err != nil {
+				t.Logf("failed to apply: %v", err)
+				return false
+			}
+
+			_, after, err := getFakeClusterWorkload(c)
+			if err != nil {
+				t.Logf("failed to get workload: %v", err)
+				return false
+			}
+
+			beforeLimits := before.Spec.Template.Spec.Containers[0].Resources.Limits
+			afterLimits := after.Spec.Template.Spec.Containers[0].Resources.Limits
+			beforeCPU, hadCPU := beforeLimits[corev1.ResourceCPU]
+			afterCPU, hasCPU := afterLimits[corev1.ResourceCPU]
+			if hadCPU != hasCPU || beforeCPU.Cmp(afterCPU) != 0 {
+				t.Logf("CPU limit changed from %s to %s", beforeCPU.String(), afterCPU.String())
+				return false
+			}
+
+			_, wantMemory := engine.calculateLimits(rec, policy)
+			if got := afterLimits[corev1.ResourceMemory]; got.Cmp(wantMemory) != 0 {
+				t.Logf("memory limit = %s, want %s", got.String(), wantMemory.String())
+				return false
+			}
+			return true
+		},
+		gen.Int64Range(100, 4000),
+		gen.Int64Range(128, 8192),
+		gen.Bool(),
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestProposedResources_UnmanagedLimitKept(t *testing.T) {
+	engine := &Engine{}
+	current := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse("256Mi")}
+
+	policy := newResourceOptimizationPolicy(true, true, false)
+	policy.Spec.UpdateStrategy.LimitConfig.ManageMemoryLimit = ptr.To(false)
+	proposed := engine.proposedResources(current, rec, policy)
+
+	cpuLimit, _ := engine.calculateLimits(rec, policy)
+	if got := proposed.Limits[corev1.ResourceCPU]; got.Cmp(cpuLimit) != 0 {
+		t.Errorf("CPU limit = %s, want %s", got.String(), cpuLimit.String())
+	}
+	if got := proposed.Limits[corev1.ResourceMemory]; got.Cmp(resource.MustParse("1Gi")) != 0 {
+		t.Errorf("memory limit = %s, want the current 1Gi", got.String())
+	}
+	if got := proposed.Requests[corev1.ResourceMemory]; got.Cmp(rec.Memory) != 0 {
+		t.Errorf("memory request = %s, want the recommendation %s", got.String(), rec.Memory.String())
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// onDeleteStrategy reports whether the workload is a StatefulSet or DaemonSet with the OnDelete
+// update strategy, whose pods are only replaced when they are deleted
+func onDeleteStrategy(workload *Workload) bool {
+	if (workload.Kind != kindStatefulSet && workload.Kind != kindDaemonSet) || workload.Object == nil {
+		return false
+	}
+	strategyType, _, _ := unstructured.NestedString(workload.Object.Object, "spec", "updateStrategy", "type")
+	return strategyType == string(appsv1.OnDeleteStatefulSetStrategyType)
+}
+
+// onDeleteRollout reports whether the policy rolls changes to the OnDelete workload out by
+// deleting its pods one at a time
+func onDeleteRollout(workload *Workload, policy *optipodv1alpha1.OptimizationPolicy) bool {
+	return onDeleteStrategy(workload) &&
+		policy.Spec.UpdateStrategy.OnDeleteRollout &&
+		policy.Spec.UpdateStrategy.AllowRecreate
+}
+
+// onDeleteDecision returns the skip decision for a change to an OnDelete workload whose pods the
+// policy does not delete, or nil when the change can go ahead
+func onDeleteDecision(workload *Workload, policy *optipodv1alpha1.OptimizationPolicy) *ApplyDecision {
+	if !onDeleteStrategy(workload) || onDeleteRollout(workload, policy) {
+		return nil
+	}
+	reason := fmt.Sprintf("%s uses the OnDelete update strategy, so its pods would keep their resources; "+
+		"set updateStrategy.onDeleteRollout to delete them one at a time", workload.Kind)
+	if policy.Spec.UpdateStrategy.OnDeleteRollout {
+		reason = fmt.Sprintf("%s uses the OnDelete update strategy and deleting its pods is disruptive, "+
+			"but recreate is not allowed", workload.Kind)
+	}
+	return &ApplyDecision{
+		CanApply: false,
+		Method:   Skip,
+		Reason:   reason,
+		Cause:    ErrOnDeleteStrategy,
+	}
+}
+
+// AdvanceOnDeleteRollout moves the rollout of an OnDelete workload forward. Once all of its pods
+// are ready, one pod that does not run the workload's update revision is evicted so its controller
+// recreates it from the template. Pods are compared by their controller-revision-hash label rather
+// than their resources, which mutating admission webhooks or LimitRanges may have changed.
+// Evictions respect PodDisruptionBudgets: a refused eviction is retried on the next reconcile.
+//
+// It returns the progress of the rollout, or nil when the policy does not delete the workload's
+// pods, its controller has not observed the latest change yet, or every pod already runs the
+// update revision.
+func (e *Engine) AdvanceOnDeleteRollout(
+	ctx context.Context,
+	workload *Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) (*optipodv1alpha1.OnDeleteRolloutStatus, error) {
+	policy, _ = withUpdateMethodOverride(workload, policy)
+	if !onDeleteRollout(workload, policy) || e.client == nil {
+		return nil, nil
+	}
+
+	gvr, err := e.getGVR(workload.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GVR: %w", err)
+	}
+	current, err := e.dynamicClient.Resource(gvr).Namespace(workload.Namespace).Get(ctx, workload.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workload: %w", err)
+	}
+	updateRevision, err := e.onDeleteUpdateRevision(ctx, workload.Kind, current)
+	if err != nil || updateRevision == "" {
+		return nil, err
+	}
+	pods, err := e.workloadPods(ctx, workload.Namespace, current)
+	if err != nil {
+		return nil, err
+	}
+
+	var outdated []*corev1.Pod
+	settled := true
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || !podReady(pod) {
+			settled = false
+		}
+		if pod.DeletionTimestamp == nil && pod.Labels[appsv1.ControllerRevisionHashLabelKey] != updateRevision {
+			outdated = append(outdated, pod)
+		}
+	}
+	if len(outdated) == 0 {
+		return nil, nil
+	}
+
+	desired := onDeleteDesiredPods(workload.Kind, current)
+	progress := &optipodv1alpha1.OnDeleteRolloutStatus{
+		UpdatedPods: int32(max(desired-len(outdated), 0)),
+		Pods:        int32(desired),
+	}
+
+	// A pod is deleted only once the previous one has been recreated and is ready
+	if !settled || len(pods) < desired || e.isDryRun() {
+		return progress, nil
+	}
+
+	slices.SortFunc(outdated, func(a, b *corev1.Pod) int { return strings.Compare(a.Name, b.Name) })
+	pod := outdated[0]
+	eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+	if err := e.client.SubResource("eviction").Create(ctx, pod, eviction); err != nil {
+		switch {
+		case errors.IsTooManyRequests(err):
+			// A PodDisruptionBudget does not allow the disruption right now
+			progress.DisruptionBlocked = true
+			return progress, nil
+		case errors.IsNotFound(err):
+			return progress, nil
+		case errors.IsForbidden(err):
+			return nil, fmt.Errorf("%w: insufficient permissions to evict pod %s: %w", ErrRBACDenied, pod.Name, err)
+		}
+		return nil, fmt.Errorf("failed to evict pod %s: %w", pod.Name, err)
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Evicted pod to roll out the OnDelete workload",
+		"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+		"pod", pod.Name,
+		"updatedPods", progress.UpdatedPods,
+		"pods", progress.Pods,
+	)
+	return progress, nil
+}
+
+// onDeleteDesiredPods returns the number of pods an OnDelete workload should have: the replicas
+// of a StatefulSet, or the nodes a DaemonSet is scheduled on
+func onDeleteDesiredPods(kind string, obj *unstructured.Unstructured) int {
+	if kind == kindDaemonSet {
+		desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+		return int(desired)
+	}
+	return int(statefulSetReplicas(obj))
+}
+
+// onDeleteUpdateRevision returns the revision the pods of an OnDelete workload are updated to, as
+// found in their controller-revision-hash label: the StatefulSet's update revision, or the hash of
+// the DaemonSet's newest ControllerRevision. It is empty until the controller has observed the
+// workload's latest generation, as the revision may be stale before then.
+func (e *Engine) onDeleteUpdateRevision(ctx context.Context, kind string, obj *unstructured.Unstructured) (string, error) {
+	observed, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if observed < obj.GetGeneration() {
+		return "", nil
+	}
+	if kind == kindStatefulSet {
+		updateRevision, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+		return updateRevision, nil
+	}
+
+	selector, err := workloadSelector(obj)
+	if err != nil || selector == nil {
+		return "", err
+	}
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := e.client.List(ctx, revisions,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return "", fmt.Errorf("failed to list controller revisions: %w", err)
+	}
+	var newest *appsv1.ControllerRevision
+	for i := range revisions.Items {
+		revision := &revisions.Items[i]
+		if metav1.IsControlledBy(revision, obj) && (newest == nil || revision.Revision > newest.Revision) {
+			newest = revision
+		}
+	}
+	if newest == nil {
+		return "", nil
+	}
+	return newest.Labels[appsv1.ControllerRevisionHashLabelKey], nil
+}
+
+// podReady reports whether the pod's Ready condition is true
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,309 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// onDeleteTestRevision is the revision the pods of the OnDelete test StatefulSet run before the
+// rollout; the StatefulSet's update revision is partitionTestRevision
+const onDeleteTestRevision = "test-sts-rev1"
+
+// onDeletePodExists reports whether the StatefulSet's pod with the given ordinal exists
+func onDeletePodExists(t *testing.T, c client.Client, ordinal int) bool {
+	t.Helper()
+
+	err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: fmt.Sprintf("test-sts-%d", ordinal)}, &corev1.Pod{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		t.Fatalf("failed to get pod: %v", err)
+	}
+	return err == nil
+}
+
+// newOnDeleteTestEngine returns an engine on a fake cluster with a 3-replica OnDelete StatefulSet
+// whose pods run the revision before its update revision
+func newOnDeleteTestEngine(t *testing.T, funcs interceptor.Funcs) (*Engine, client.Client) {
+	t.Helper()
+
+	c := interceptor.NewClient(fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(), funcs)
+	engine := newFakeClusterEngine(c, kindStatefulSet)
+	engine.discoveryClient = &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}}
+	newFakeClusterStatefulSet(t, c, 3, appsv1.OnDeleteStatefulSetStrategyType)
+	for ordinal := range 3 {
+		setPartitionPod(t, c, ordinal, onDeleteTestRevision, true)
+	}
+	return engine, c
+}
+
+func TestCanApply_OnDeleteStrategy(t *testing.T) {
+	tests := []struct {
+		name          string
+		strategy      appsv1.StatefulSetUpdateStrategyType
+		rollout       bool
+		allowRecreate bool
+		wantMethod    ApplyMethod
+		wantCause     error
+	}{
+		{name: "OnDelete without opt-in is skipped", strategy: appsv1.OnDeleteStatefulSetStrategyType,
+			allowRecreate: true, wantMethod: Skip, wantCause: ErrOnDeleteStrategy},
+		{name: "OnDelete rollout without recreate is skipped", strategy: appsv1.OnDeleteStatefulSetStrategyType,
+			rollout: true, wantMethod: Skip, wantCause: ErrOnDeleteStrategy},
+		{name: "OnDelete rollout recreates the pods", strategy: appsv1.OnDeleteStatefulSetStrategyType,
+			rollout: true, allowRecreate: true, wantMethod: Recreate},
+		{name: "RollingUpdate is not affected", strategy: appsv1.RollingUpdateStatefulSetStrategyType,
+			wantMethod: InPlace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			engine := &Engine{
+				client:          c,
+				discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}},
+			}
+			newFakeClusterStatefulSet(t, c, 3, tt.strategy)
+
+			policy := newResourceOptimizationPolicy(true, true, true)
+			policy.Spec.UpdateStrategy.OnDeleteRollout = tt.rollout
+			policy.Spec.UpdateStrategy.AllowRecreate = tt.allowRecreate
+			rec := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse("512Mi")}
+
+			workload, _ := getFakeClusterStatefulSet(t, c)
+			decision, err := engine.CanApply(context.Background(), workload, "test-container", rec, policy)
+			if err != nil {
+				t.Fatalf("CanApply() error = %v", err)
+			}
+			if decision.Method != tt.wantMethod {
+				t.Errorf("Method = %s (%s), want %s", decision.Method, decision.Reason, tt.wantMethod)
+			}
+			if decision.CanApply != (tt.wantMethod != Skip) {
+				t.Errorf("CanApply = %v, want %v", decision.CanApply, tt.wantMethod != Skip)
+			}
+			if !errors.Is(decision.Cause, tt.wantCause) || (tt.wantCause == nil && decision.Cause != nil) {
+				t.Errorf("Cause = %v, want %v", decision.Cause, tt.wantCause)
+			}
+		})
+	}
+}
+
+func TestAdvanceOnDeleteRollout_DeletesOnePodAtATime(t *testing.T) {
+	ctx := context.Background()
+	engine, c := newOnDeleteTestEngine(t, interceptor.Funcs{})
+
+	policy := newResourceOptimizationPolicy(true, true, true)
+	policy.Spec.UpdateStrategy.OnDeleteRollout = true
+	policy.Spec.UpdateStrategy.AllowRecreate = true
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse("512Mi")}
+
+	workload, _ := getFakeClusterStatefulSet(t, c)
+	result, err := engine.Apply(ctx, workload, []ContainerChange{{Container: "test-container", Recommendation: rec, Method: Recreate}}, policy)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if !result.OnDeleteRollout {
+		t.Fatal("OnDeleteRollout = false, want the pods deleted one at a time")
+	}
+
+	for ordinal := range 3 {
+		// The next pod is only deleted once the previous one is back and ready
+		if ordinal > 0 {
+			if progress, err := engine.AdvanceOnDeleteRollout(ctx, workload, policy); err != nil || progress == nil {
+				t.Fatalf("AdvanceOnDeleteRollout() = %+v, %v while pod %d is recreated, want progress", progress, err, ordinal-1)
+			}
+			if !onDeletePodExists(t, c, ordinal) {
+				t.Fatalf("pod %d was deleted before pod %d was recreated", ordinal, ordinal-1)
+			}
+			setPartitionPod(t, c, ordinal-1, partitionTestRevision, false)
+			if progress, _ := engine.AdvanceOnDeleteRollout(ctx, workload, policy); progress == nil || !onDeletePodExists(t, c, ordinal) {
+				t.Fatalf("pod %d was deleted while pod %d was not ready", ordinal, ordinal-1)
+			}
+			setPartitionPod(t, c, ordinal-1, partitionTestRevision, true)
+		}
+
+		progress, err := engine.AdvanceOnDeleteRollout(ctx, workload, policy)
+		if err != nil {
+			t.Fatalf("AdvanceOnDeleteRollout() error = %v", err)
+		}
+		if progress == nil || progress.UpdatedPods != int32(ordinal) || progress.Pods != 3 {
+			t.Fatalf("progress = %+v, want %d of 3 pods updated", progress, ordinal)
+		}
+		if onDeletePodExists(t, c, ordinal) {
+			t.Fatalf("pod %d was not evicted", ordinal)
+		}
+	}
+
+	// Once every pod runs the update revision the rollout is complete
+	setPartitionPod(t, c, 2, partitionTestRevision, true)
+	if progress, err := engine.AdvanceOnDeleteRollout(ctx, workload, policy); err != nil || progress != nil {
+		t.Errorf("AdvanceOnDeleteRollout() = %+v, %v after the rollout, want nil", progress, err)
+	}
+}
+
+func TestAdvanceOnDeleteRollout_RespectsDisruptionBudget(t *testing.T) {
+	ctx := context.Background()
+	engine, c := newOnDeleteTestEngine(t, interceptor.Funcs{
+		SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+			return apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)
+		},
+	})
+
+	policy := newResourceOptimizationPolicy(true, true, true)
+	policy.Spec.UpdateStrategy.OnDeleteRollout = true
+	policy.Spec.UpdateStrategy.AllowRecreate = true
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse("512Mi")}
+
+	workload, _ := getFakeClusterStatefulSet(t, c)
+	if _, err := engine.Apply(ctx, workload, []ContainerChange{{Container: "test-container", Recommendation: rec, Method: Recreate}}, policy); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	progress, err := engine.AdvanceOnDeleteRollout(ctx, workload, policy)
+	if err != nil {
+		t.Fatalf("AdvanceOnDeleteRollout() error = %v", err)
+	}
+	if progress == nil || !progress.DisruptionBlocked || progress.UpdatedPods != 0 {
+		t.Fatalf("progress = %+v, want the eviction blocked by the disruption budget", progress)
+	}
+	if !onDeletePodExists(t, c, 0) {
+		t.Error("pod 0 was deleted although the disruption budget refused the eviction")
+	}
+}
+
+func TestAdvanceOnDeleteRollout_NotUsed(t *testing.T) {
+	tests := []struct {
+		name          string
+		rollout       bool
+		allowRecreate bool
+	}{
+		{name: "policy does not opt in", allowRecreate: true},
+		{name: "recreate not allowed", rollout: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			engine, c := newOnDeleteTestEngine(t, interceptor.Funcs{})
+
+			// The template changed, e.g. by hand, but the policy does not delete the pods
+			policy := newResourceOptimizationPolicy(true, true, true)
+			rec := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse("512Mi")}
+			workload, _ := getFakeClusterStatefulSet(t, c)
+			if _, err := engine.Apply(ctx, workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			policy.Spec.UpdateStrategy.OnDeleteRollout = tt.rollout
+			policy.Spec.UpdateStrategy.AllowRecreate = tt.allowRecreate
+			if progress, err := engine.AdvanceOnDeleteRollout(ctx, workload, policy); err != nil || progress != nil {
+				t.Errorf("AdvanceOnDeleteRollout() = %+v, %v, want nil", progress, err)
+			}
+			for ordinal := range 3 {
+				if !onDeletePodExists(t, c, ordinal) {
+					t.Errorf("pod %d was deleted", ordinal)
+				}
+			}
+		})
+	}
+}
+
+func TestAdvanceOnDeleteRollout_WaitsForObservedGeneration(t *testing.T) {
+	ctx := context.Background()
+	engine, c := newOnDeleteTestEngine(t, interceptor.Funcs{})
+
+	// The StatefulSet controller has not seen the latest spec, so its update revision may be stale
+	_, statefulSet := getFakeClusterStatefulSet(t, c)
+	statefulSet.Generation = 2
+	statefulSet.Status.ObservedGeneration = 1
+	if err := c.Update(ctx, statefulSet); err != nil {
+		t.Fatalf("failed to update statefulset: %v", err)
+	}
+
+	policy := newResourceOptimizationPolicy(true, true, true)
+	policy.Spec.UpdateStrategy.OnDeleteRollout = true
+	policy.Spec.UpdateStrategy.AllowRecreate = true
+	workload, _ := getFakeClusterStatefulSet(t, c)
+	if progress, err := engine.AdvanceOnDeleteRollout(ctx, workload, policy); err != nil || progress != nil {
+		t.Errorf("AdvanceOnDeleteRollout() = %+v, %v, want nil", progress, err)
+	}
+	for ordinal := range 3 {
+		if !onDeletePodExists(t, c, ordinal) {
+			t.Errorf("pod %d was deleted", ordinal)
+		}
+	}
+}
+
+func TestOnDeleteUpdateRevision_DaemonSet(t *testing.T) {
+	daemonSet := &unstructured.Unstructured{}
+	daemonSet.SetAPIVersion("apps/v1")
+	daemonSet.SetKind(kindDaemonSet)
+	daemonSet.SetName("test-ds")
+	daemonSet.SetNamespace("default")
+	daemonSet.SetUID("test-ds-uid")
+	if err := unstructured.SetNestedStringMap(daemonSet.Object, map[string]string{"app": "test"}, "spec", "selector", "matchLabels"); err != nil {
+		t.Fatalf("failed to set selector: %v", err)
+	}
+
+	controllerRevision := func(name string, revision int64, ownerUID types.UID) *appsv1.ControllerRevision {
+		return &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-ds-" + name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "test", appsv1.ControllerRevisionHashLabelKey: name},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1", Kind: kindDaemonSet, Name: "test-ds", UID: ownerUID, Controller: ptr.To(true),
+				}},
+			},
+			Revision: revision,
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		controllerRevision("rev1", 1, "test-ds-uid"),
+		controllerRevision("rev3", 3, "test-ds-uid"),
+		controllerRevision("rev2", 2, "test-ds-uid"),
+		// A revision of another DaemonSet with overlapping labels is not considered
+		controllerRevision("other", 4, "other-ds-uid"),
+	).Build()
+	engine := &Engine{client: c}
+
+	revision, err := engine.onDeleteUpdateRevision(context.Background(), kindDaemonSet, daemonSet)
+	if err != nil {
+		t.Fatalf("onDeleteUpdateRevision() error = %v", err)
+	}
+	if revision != "rev3" {
+		t.Errorf("onDeleteUpdateRevision() = %q, want the newest revision rev3", revision)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// partitionedRollout reports whether changes to the workload are rolled out one pod at a time
+// through the StatefulSet's rollingUpdate partition. StatefulSets with the OnDelete strategy are
+// rolled out by deleting their pods instead, see AdvanceOnDeleteRollout.
+func partitionedRollout(workload *Workload, policy *optipodv1alpha1.OptimizationPolicy) bool {
+	if !policy.Spec.UpdateStrategy.PartitionedRollout || workload.Kind != kindStatefulSet || workload.Object == nil {
+		return false
+	}
+	strategyType, _, _ := unstructured.NestedString(workload.Object.Object, "spec", "updateStrategy", "type")
+	return strategyType == "" || strategyType == string(appsv1.RollingUpdateStatefulSetStrategyType)
+}
+
+// statefulSetReplicas returns the desired replicas of a StatefulSet, which default to 1
+func statefulSetReplicas(obj *unstructured.Unstructured) int64 {
+	replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if err != nil || !found {
+		return 1
+	}
+	return replicas
+}
+
+// currentPartition returns the rollingUpdate partition of a StatefulSet, 0 when unset
+func currentPartition(obj *unstructured.Unstructured) int64 {
+	partition, _, _ := unstructured.NestedInt64(obj.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+	return max(partition, 0)
+}
+
+// startPartition returns the partition a partitioned rollout of the workload starts at, so only
+// the policy's partition step of pods with the highest ordinals is updated first, and the
+// partition the StatefulSet had before, which the rollout stops at. The last result is false when
+// the change is not rolled out through the partition, or the first step already reaches the
+// partition the StatefulSet had.
+func startPartition(workload *Workload, policy *optipodv1alpha1.OptimizationPolicy) (int64, int64, bool) {
+	if !partitionedRollout(workload, policy) {
+		return 0, 0, false
+	}
+	floor := currentPartition(workload.Object)
+	start := statefulSetReplicas(workload.Object) - policy.GetPartitionStep()
+	if start <= floor {
+		return 0, 0, false
+	}
+	return start, floor, true
+}
+
+// withPartition adds the rollingUpdate partition a rollout starts at to a workload patch, and
+// the partition the rollout stops at to its annotations
+func withPartition(patch map[string]interface{}, partition, floor int64) {
+	metadata := patch["metadata"].(map[string]interface{})
+	annotations := metadata["annotations"].(map[string]interface{})
+	annotations[optipodv1alpha1.AnnotationPartitionFloor] = strconv.FormatInt(floor, 10)
+
+	spec := patch["spec"].(map[string]interface{})
+	spec["updateStrategy"] = map[string]interface{}{
+		"rollingUpdate": map[string]interface{}{
+			"partition": partition,
+		},
+	}
+}
+
+// AdvancePartition moves a partitioned StatefulSet rollout forward. Once the pods the partition
+// last released are ready on the StatefulSet's update revision, the partition is lowered by the
+// policy's partition step, so the StatefulSet controller rolls the next pods. The rollout ends at
+// the partition the StatefulSet had before it started.
+//
+// It returns the progress of the rollout, or nil when the policy does not roll the workload out
+// through its partition or no rollout is in progress.
+func (e *Engine) AdvancePartition(
+	ctx context.Context,
+	workload *Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) (*optipodv1alpha1.PartitionedRolloutStatus, error) {
+	if !partitionedRollout(workload, policy) {
+		return nil, nil
+	}
+
+	gvr, err := e.getGVR(workload.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GVR: %w", err)
+	}
+	current, err := e.dynamicClient.Resource(gvr).Namespace(workload.Namespace).Get(ctx, workload.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workload: %w", err)
+	}
+
+	// Only rollouts OptiPod started carry their floor; a partition without one is the user's
+	floorValue, started := current.GetAnnotations()[optipodv1alpha1.AnnotationPartitionFloor]
+	if !started {
+		return nil, nil
+	}
+	floor, err := strconv.ParseInt(floorValue, 10, 64)
+	if err != nil || floor < 0 {
+		floor = 0
+	}
+	partition := currentPartition(current)
+	replicas := statefulSetReplicas(current)
+	progress := &optipodv1alpha1.PartitionedRolloutStatus{Partition: int32(partition), Replicas: int32(replicas)}
+
+	// The partition was lowered to or past the floor by hand, which ends the rollout
+	if partition <= floor {
+		if e.isDryRun() {
+			return nil, nil
+		}
+		patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:null}}}`, optipodv1alpha1.AnnotationPartitionFloor)
+		return nil, e.patchPartition(ctx, gvr, workload, patch)
+	}
+
+	// A partition beyond the last ordinal, e.g. after a scale down, has no pod to wait for
+	step := policy.GetPartitionStep()
+	next := max(min(partition, replicas)-step, floor)
+	for ordinal := partition; ordinal < min(partition+step, replicas); ordinal++ {
+		ready, err := e.partitionPodReady(ctx, current, ordinal)
+		if err != nil {
+			return nil, err
+		}
+		if !ready {
+			return progress, nil
+		}
+	}
+
+	if e.isDryRun() {
+		return progress, nil
+	}
+
+	// Reaching the floor completes the rollout, which drops the annotation marking it
+	patch := fmt.Sprintf(`{"spec":{"updateStrategy":{"rollingUpdate":{"partition":%d}}}}`, next)
+	if next == floor {
+		patch = fmt.Sprintf(`{"metadata":{"annotations":{%q:null}},"spec":{"updateStrategy":{"rollingUpdate":{"partition":%d}}}}`,
+			optipodv1alpha1.AnnotationPartitionFloor, next)
+	}
+	if err := e.patchPartition(ctx, gvr, workload, patch); err != nil {
+		return nil, err
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Advanced partitioned rollout",
+		"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+		"partition", next,
+		"replicas", replicas,
+	)
+	progress.Partition = int32(next)
+	return progress, nil
+}
+
+// patchPartition merge patches the partition of a partitioned rollout
+func (e *Engine) patchPartition(ctx context.Context, gvr schema.GroupVersionResource, workload *Workload, patch string) error {
+	_, err := e.dynamicClient.Resource(gvr).Namespace(workload.Namespace).Patch(
+		ctx,
+		workload.Name,
+		types.MergePatchType,
+		[]byte(patch),
+		metav1.PatchOptions{FieldManager: FieldManagerName},
+	)
+	if err != nil {
+		if errors.IsForbidden(err) {
+			return fmt.Errorf("%w: insufficient permissions to update workload: %w", ErrRBACDenied, err)
+		}
+		return fmt.Errorf("failed to patch partition: %w", err)
+	}
+	return nil
+}
+
+// partitionPodReady reports whether the StatefulSet's pod with the given ordinal runs the update
+// revision and is ready. Until the StatefulSet controller has observed the latest spec, the
+// update revision may be stale, so no pod is considered updated.
+func (e *Engine) partitionPodReady(ctx context.Context, statefulSet *unstructured.Unstructured, ordinal int64) (bool, error) {
+	observed, _, _ := unstructured.NestedInt64(statefulSet.Object, "status", "observedGeneration")
+	if observed < statefulSet.GetGeneration() {
+		return false, nil
+	}
+	updateRevision, _, _ := unstructured.NestedString(statefulSet.Object, "status", "updateRevision")
+	if updateRevision == "" || e.client == nil {
+		return false, nil
+	}
+
+	pod := &corev1.Pod{}
+	key := client.ObjectKey{Namespace: statefulSet.GetNamespace(), Name: fmt.Sprintf("%s-%d", statefulSet.GetName(), ordinal)}
+	if err := e.client.Get(ctx, key, pod); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get pod %s: %w", key.Name, err)
+	}
+	if pod.DeletionTimestamp != nil || pod.Labels[appsv1.ControllerRevisionHashLabelKey] != updateRevision {
+		return false, nil
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
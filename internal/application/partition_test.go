@@ -0,0 +1,289 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+const partitionTestRevision = "test-sts-rev2"
+
+// newFakeClusterStatefulSet creates a StatefulSet with the given replicas and update strategy
+func newFakeClusterStatefulSet(t *testing.T, c client.Client, replicas int32, strategy appsv1.StatefulSetUpdateStrategyType) {
+	t.Helper()
+
+	template := newFakeClusterPodTemplate(resourceList("500m", "512Mi"), nil)
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sts", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:       ptr.To(replicas),
+			Selector:       &metav1.LabelSelector{MatchLabels: template.Labels},
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: strategy},
+			Template:       template,
+		},
+		Status: appsv1.StatefulSetStatus{UpdateRevision: partitionTestRevision},
+	}
+	if err := c.Create(context.Background(), statefulSet, client.FieldOwner("kubectl")); err != nil {
+		t.Fatalf("failed to create statefulset: %v", err)
+	}
+}
+
+// getFakeClusterStatefulSet reads the StatefulSet back as the workload optipod applies to
+func getFakeClusterStatefulSet(t *testing.T, c client.Client) (*Workload, *appsv1.StatefulSet) {
+	t.Helper()
+
+	statefulSet := &appsv1.StatefulSet{}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "test-sts"}, statefulSet); err != nil {
+		t.Fatalf("failed to get statefulset: %v", err)
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statefulSet)
+	if err != nil {
+		t.Fatalf("failed to convert statefulset: %v", err)
+	}
+	return &Workload{
+		Kind:      kindStatefulSet,
+		Namespace: "default",
+		Name:      "test-sts",
+		Object:    &unstructured.Unstructured{Object: obj},
+	}, statefulSet
+}
+
+// setPartitionPod creates or replaces the StatefulSet's pod with the given ordinal
+func setPartitionPod(t *testing.T, c client.Client, ordinal int, revision string, ready bool) {
+	t.Helper()
+
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("test-sts-%d", ordinal),
+			Namespace: "default",
+			Labels:    map[string]string{"app": "test", appsv1.ControllerRevisionHashLabelKey: revision},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: readyStatus}},
+		},
+	}
+	_ = c.Delete(context.Background(), pod)
+	if err := c.Create(context.Background(), pod); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+}
+
+func partitionOf(statefulSet *appsv1.StatefulSet) *int32 {
+	if statefulSet.Spec.UpdateStrategy.RollingUpdate == nil {
+		return nil
+	}
+	return statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition
+}
+
+func TestPartitionedRollout_AdvancesOnePodAtATime(t *testing.T) {
+	for _, method := range []struct {
+		name   string
+		useSSA bool
+	}{{name: "ServerSideApply", useSSA: true}, {name: "StrategicMergePatch", useSSA: false}} {
+		t.Run(method.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			engine := newFakeClusterEngine(c, kindStatefulSet)
+			newFakeClusterStatefulSet(t, c, 3, appsv1.RollingUpdateStatefulSetStrategyType)
+
+			policy := newResourceOptimizationPolicy(true, true, true)
+			policy.Spec.UpdateStrategy.PartitionedRollout = true
+			policy.Spec.UpdateStrategy.UseServerSideApply = ptr.To(method.useSSA)
+			rec := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse("256Mi")}
+
+			workload, _ := getFakeClusterStatefulSet(t, c)
+			result, err := engine.Apply(ctx, workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if result.PartitionedRollout == nil || result.PartitionedRollout.Partition != 2 || result.PartitionedRollout.Replicas != 3 {
+				t.Fatalf("PartitionedRollout = %+v, want partition 2 of 3 replicas", result.PartitionedRollout)
+			}
+
+			// The template change and the partition are written together
+			workload, statefulSet := getFakeClusterStatefulSet(t, c)
+			if got := statefulSet.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]; got.Cmp(rec.CPU) != 0 {
+				t.Errorf("CPU request = %s, want %s", got.String(), rec.CPU.String())
+			}
+			if got := partitionOf(statefulSet); got == nil || *got != 2 {
+				t.Fatalf("partition = %v, want 2", got)
+			}
+
+			// The partition is held while the updated pod is not ready on the new revision
+			setPartitionPod(t, c, 2, "test-sts-rev1", true)
+			progress, err := engine.AdvancePartition(ctx, workload, policy)
+			if err != nil {
+				t.Fatalf("AdvancePartition() error = %v", err)
+			}
+			if progress == nil || progress.Partition != 2 {
+				t.Fatalf("progress = %+v with the pod on the old revision, want partition 2", progress)
+			}
+			setPartitionPod(t, c, 2, partitionTestRevision, false)
+			if progress, _ = engine.AdvancePartition(ctx, workload, policy); progress == nil || progress.Partition != 2 {
+				t.Fatalf("progress = %+v with the pod not ready, want partition 2", progress)
+			}
+
+			// Each ready pod lowers the partition by one until every pod is updated
+			for ordinal := 2; ordinal > 0; ordinal-- {
+				setPartitionPod(t, c, ordinal, partitionTestRevision, true)
+				progress, err := engine.AdvancePartition(ctx, workload, policy)
+				if err != nil {
+					t.Fatalf("AdvancePartition() error = %v", err)
+				}
+				if progress == nil || progress.Partition != int32(ordinal-1) || progress.UpdatedReplicas() != int32(4-ordinal) {
+					t.Fatalf("progress = %+v after pod %d became ready, want partition %d", progress, ordinal, ordinal-1)
+				}
+				_, statefulSet = getFakeClusterStatefulSet(t, c)
+				if got := partitionOf(statefulSet); got == nil || *got != int32(ordinal-1) {
+					t.Fatalf("partition = %v, want %d", got, ordinal-1)
+				}
+			}
+
+			// Once the partition is 0 the rollout is complete
+			if progress, err := engine.AdvancePartition(ctx, workload, policy); err != nil || progress != nil {
+				t.Errorf("AdvancePartition() = %+v, %v after the rollout, want nil", progress, err)
+			}
+		})
+	}
+}
+
+func TestPartitionedRollout_KeepsUserPartition(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	engine := newFakeClusterEngine(c, kindStatefulSet)
+	newFakeClusterStatefulSet(t, c, 5, appsv1.RollingUpdateStatefulSetStrategyType)
+	setUserPartition := func(partition int32) {
+		t.Helper()
+		_, statefulSet := getFakeClusterStatefulSet(t, c)
+		statefulSet.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To(partition)}
+		if err := c.Update(ctx, statefulSet); err != nil {
+			t.Fatalf("failed to set partition: %v", err)
+		}
+	}
+	// A canary holds the pods below ordinal 1 on the old revision
+	setUserPartition(1)
+
+	policy := newResourceOptimizationPolicy(true, true, true)
+	policy.Spec.UpdateStrategy.PartitionedRollout = true
+	policy.Spec.UpdateStrategy.PartitionStep = ptr.To[int32](2)
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse("256Mi")}
+
+	workload, _ := getFakeClusterStatefulSet(t, c)
+	result, err := engine.Apply(ctx, workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if result.PartitionedRollout == nil || result.PartitionedRollout.Partition != 3 {
+		t.Fatalf("PartitionedRollout = %+v, want the first step of 2 pods at partition 3", result.PartitionedRollout)
+	}
+	if _, statefulSet := getFakeClusterStatefulSet(t, c); statefulSet.Annotations[optipodv1alpha1.AnnotationPartitionFloor] != "1" {
+		t.Fatalf("annotations = %v, want the user partition recorded as the floor", statefulSet.Annotations)
+	}
+
+	// Both pods of the step must be ready before the partition moves
+	setPartitionPod(t, c, 4, partitionTestRevision, true)
+	setPartitionPod(t, c, 3, partitionTestRevision, false)
+	if progress, _ := engine.AdvancePartition(ctx, workload, policy); progress == nil || progress.Partition != 3 {
+		t.Fatalf("progress = %+v with pod 3 not ready, want partition 3", progress)
+	}
+
+	// The next step stops at the user partition, which completes the rollout
+	setPartitionPod(t, c, 3, partitionTestRevision, true)
+	if progress, err := engine.AdvancePartition(ctx, workload, policy); err != nil || progress == nil || progress.Partition != 1 {
+		t.Fatalf("AdvancePartition() = %+v, %v, want partition 1", progress, err)
+	}
+	_, statefulSet := getFakeClusterStatefulSet(t, c)
+	if got := partitionOf(statefulSet); got == nil || *got != 1 {
+		t.Fatalf("partition = %v, want the user partition 1", got)
+	}
+	if _, ok := statefulSet.Annotations[optipodv1alpha1.AnnotationPartitionFloor]; ok {
+		t.Errorf("annotations = %v, want the floor removed after the rollout", statefulSet.Annotations)
+	}
+
+	// A partition the user sets afterwards is left alone
+	setUserPartition(3)
+	if progress, err := engine.AdvancePartition(ctx, workload, policy); err != nil || progress != nil {
+		t.Errorf("AdvancePartition() = %+v, %v for a user partition, want nil", progress, err)
+	}
+	if _, statefulSet := getFakeClusterStatefulSet(t, c); *partitionOf(statefulSet) != 3 {
+		t.Errorf("partition = %d, want the user partition 3", *partitionOf(statefulSet))
+	}
+}
+
+func TestPartitionedRollout_NotUsed(t *testing.T) {
+	tests := []struct {
+		name        string
+		replicas    int32
+		strategy    appsv1.StatefulSetUpdateStrategyType
+		partitioned bool
+		step        int32
+	}{
+		{name: "policy does not partition", replicas: 3, strategy: appsv1.RollingUpdateStatefulSetStrategyType},
+		{name: "OnDelete strategy", replicas: 3, strategy: appsv1.OnDeleteStatefulSetStrategyType, partitioned: true},
+		{name: "single replica", replicas: 1, strategy: appsv1.RollingUpdateStatefulSetStrategyType, partitioned: true},
+		{name: "step covers all replicas", replicas: 2, strategy: appsv1.RollingUpdateStatefulSetStrategyType, partitioned: true, step: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			engine := newFakeClusterEngine(c, kindStatefulSet)
+			newFakeClusterStatefulSet(t, c, tt.replicas, tt.strategy)
+
+			policy := newResourceOptimizationPolicy(true, true, true)
+			policy.Spec.UpdateStrategy.PartitionedRollout = tt.partitioned
+			if tt.step > 0 {
+				policy.Spec.UpdateStrategy.PartitionStep = ptr.To(tt.step)
+			}
+			rec := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse("256Mi")}
+
+			workload, _ := getFakeClusterStatefulSet(t, c)
+			result, err := engine.Apply(ctx, workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if result.PartitionedRollout != nil {
+				t.Errorf("PartitionedRollout = %+v, want all pods rolled at once", result.PartitionedRollout)
+			}
+			if _, statefulSet := getFakeClusterStatefulSet(t, c); partitionOf(statefulSet) != nil && *partitionOf(statefulSet) != 0 {
+				t.Errorf("partition = %d, want none", *partitionOf(statefulSet))
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+func TestApply_PatchMethodOverrides(t *testing.T) {
+	overrides := []optipodv1alpha1.PatchMethodOverride{
+		{Kind: optipodv1alpha1.WorkloadTypeDaemonSet, Method: optipodv1alpha1.PatchMethodStrategicMergePatch},
+		{Kind: optipodv1alpha1.WorkloadTypeStatefulSet, Method: optipodv1alpha1.PatchMethodServerSideApply},
+	}
+
+	tests := []struct {
+		name          string
+		kind          string
+		useSSA        bool
+		wantMethod    string
+		wantPatchType types.PatchType
+	}{
+		{
+			name:          "kind override replaces server-side apply",
+			kind:          kindDaemonSet,
+			useSSA:        true,
+			wantMethod:    optipodv1alpha1.PatchMethodStrategicMergePatch,
+			wantPatchType: types.StrategicMergePatchType,
+		},
+		{
+			name:          "kind override replaces strategic merge",
+			kind:          kindStatefulSet,
+			useSSA:        false,
+			wantMethod:    optipodv1alpha1.PatchMethodServerSideApply,
+			wantPatchType: types.ApplyPatchType,
+		},
+		{
+			name:          "kind without override follows the policy",
+			kind:          kindDeployment,
+			useSSA:        true,
+			wantMethod:    optipodv1alpha1.PatchMethodServerSideApply,
+			wantPatchType: types.ApplyPatchType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capturedPatchType types.PatchType
+			engine := &Engine{
+				dynamicClient: &mockDynamicClientWithCapture{
+					capturedPatchOptions: &metav1.PatchOptions{},
+					capturedPatchType:    &capturedPatchType,
+				},
+			}
+			workload := createMockWorkload()
+			workload.Kind = tt.kind
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.UseServerSideApply = &tt.useSSA
+			policy.Spec.UpdateStrategy.PatchMethodOverrides = overrides
+
+			rec := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse("256Mi")}
+			result, err := engine.Apply(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if result.Method != tt.wantMethod || capturedPatchType != tt.wantPatchType {
+				t.Errorf("Apply() used %s with patch type %s, want %s with %s",
+					result.Method, capturedPatchType, tt.wantMethod, tt.wantPatchType)
+			}
+		})
+	}
+}
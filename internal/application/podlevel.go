@@ -0,0 +1,334 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// detectPodLevelResources detects if pod-level resources are supported. The PodLevelResources
+// feature is enabled by default from Kubernetes 1.34, when it became beta.
+func (e *Engine) detectPodLevelResources(ctx context.Context) (bool, error) { //nolint:unparam // ctx may be used in future
+	major, minor, err := e.serverVersion()
+	if err != nil {
+		return false, err
+	}
+	return major > 1 || (major == 1 && minor >= 34), nil
+}
+
+// podResources returns the pod-level resources for the changes when the policy sizes pods as a
+// whole, or nil when the containers are sized individually. Clusters without pod-level resources
+// fall back to sizing containers individually.
+//
+// The changes' recommendations are aggregated as the policy says, and the requests of containers
+// without a change are added on top, as they keep their own.
+func (e *Engine) podResources(
+	ctx context.Context,
+	workload *Workload,
+	current map[string]corev1.ResourceRequirements,
+	changes []ContainerChange,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) *corev1.ResourceRequirements {
+	config := policy.Spec.UpdateStrategy.PodLevelResources
+	if config == nil || len(changes) == 0 {
+		return nil
+	}
+
+	supported, err := e.detectPodLevelResources(ctx)
+	if err != nil || !supported {
+		ctrl.LoggerFrom(ctx).Info("Pod-level resources are not supported by the cluster, sizing containers individually",
+			"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+			"error", err,
+		)
+		return nil
+	}
+
+	aggregation := config.GetAggregation()
+	var cpu, memory, cpuLimit, memoryLimit resource.Quantity
+	guaranteed := true
+	changed := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		if change.Unchanged {
+			continue
+		}
+		changed[change.Container] = true
+		guaranteed = guaranteed && change.keepGuaranteed
+
+		rec := change.Recommendation
+		limitCPU, limitMemory := e.calculateLimits(rec, policy)
+		if rec.KeepLimits {
+			limitCPU = containerLimit(current[change.Container], corev1.ResourceCPU)
+			limitMemory = containerLimit(current[change.Container], corev1.ResourceMemory)
+		}
+		aggregate(&cpu, rec.CPU, aggregation)
+		aggregate(&memory, rec.Memory, aggregation)
+		aggregate(&cpuLimit, limitCPU, aggregation)
+		aggregate(&memoryLimit, limitMemory, aggregation)
+	}
+
+	// Containers without a change keep their requests, and count their limits where they have one
+	for name, resources := range current {
+		if changed[name] {
+			continue
+		}
+		cpu.Add(resources.Requests[corev1.ResourceCPU])
+		memory.Add(resources.Requests[corev1.ResourceMemory])
+		cpuLimit.Add(containerLimit(resources, corev1.ResourceCPU))
+		memoryLimit.Add(containerLimit(resources, corev1.ResourceMemory))
+	}
+
+	pod := &corev1.ResourceRequirements{Requests: optimizedResourceList(policy, cpu, memory)}
+	switch {
+	case guaranteed:
+		pod.Limits = optimizedResourceList(policy, cpu, memory)
+	case policy.Spec.UpdateStrategy.RemoveLimits, policy.Spec.UpdateStrategy.UpdateRequestsOnly:
+	default:
+		pod.Limits = optimizedResourceList(policy, cpuLimit, memoryLimit)
+		for name := range pod.Limits {
+			if !managesLimit(policy, name) {
+				delete(pod.Limits, name)
+			}
+		}
+	}
+	return pod
+}
+
+// containerLimit returns a container's limit of a resource, or its request when it has none
+func containerLimit(resources corev1.ResourceRequirements, name corev1.ResourceName) resource.Quantity {
+	if limit, ok := resources.Limits[name]; ok {
+		return limit
+	}
+	return resources.Requests[name]
+}
+
+// podResourcesWithinTolerance reports whether the pod-level resources for the changes are within
+// updateStrategy.applyTolerance of the pod template's, or equal to them without a tolerance, and
+// the changed containers no longer have requests of their own. Patching would then not change
+// the pods, or not by enough to be worth a rollout. The pod template's resources are returned.
+func podResourcesWithinTolerance(
+	workload *Workload,
+	current map[string]corev1.ResourceRequirements,
+	changes []ContainerChange,
+	pod *corev1.ResourceRequirements,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) (*corev1.ResourceRequirements, bool) {
+	template, ok := templatePodResources(workload)
+	if !ok {
+		return nil, false
+	}
+	for _, change := range changes {
+		if change.Unchanged {
+			continue
+		}
+		for name := range optimizedResourceList(policy, resource.Quantity{}, resource.Quantity{}) {
+			if _, ok := current[change.Container].Requests[name]; ok {
+				return nil, false
+			}
+		}
+	}
+
+	tolerance := policy.Spec.UpdateStrategy.ApplyTolerance
+	for name, recommended := range pod.Requests {
+		value, ok := template.Requests[name]
+		if !ok {
+			return nil, false
+		}
+		if value.Cmp(recommended) == 0 {
+			continue
+		}
+		if tolerance == nil || !resourceTolerance(tolerance, name).Within(value, recommended) {
+			return nil, false
+		}
+	}
+
+	// Limits follow the requests, so they only need to match when no tolerance is configured
+	if tolerance == nil {
+		for name := range limitValues(policy, nil, nil) {
+			limit, ok := pod.Limits[corev1.ResourceName(name)]
+			value, set := template.Limits[corev1.ResourceName(name)]
+			if ok != set || (ok && value.Cmp(limit) != 0) {
+				return nil, false
+			}
+		}
+	}
+	return template, true
+}
+
+// templatePodResources returns the pod-level resources of the workload's pod template, if it has any
+func templatePodResources(workload *Workload) (*corev1.ResourceRequirements, bool) {
+	if workload.Object == nil {
+		return nil, false
+	}
+	fields, ok, err := unstructured.NestedMap(workload.Object.Object, "spec", "template", "spec", "resources")
+	if err != nil || !ok {
+		return nil, false
+	}
+	resources := &corev1.ResourceRequirements{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(fields, resources); err != nil {
+		return nil, false
+	}
+	return resources, len(resources.Requests) > 0
+}
+
+// resourceTolerance returns the tolerance of a resource's requests
+func resourceTolerance(tolerance *optipodv1alpha1.ApplyTolerance, name corev1.ResourceName) *optipodv1alpha1.ResourceTolerance {
+	if name == corev1.ResourceCPU {
+		return tolerance.CPU
+	}
+	return tolerance.Memory
+}
+
+// aggregate combines a container's quantity into the pod's total
+func aggregate(total *resource.Quantity, q resource.Quantity, aggregation string) {
+	if aggregation == optipodv1alpha1.PodAggregationMax {
+		if q.Cmp(*total) > 0 {
+			*total = q.DeepCopy()
+		}
+		return
+	}
+	total.Add(q)
+}
+
+// optimizedResourceList returns the CPU and memory quantities of the resources the policy optimizes
+func optimizedResourceList(policy *optipodv1alpha1.OptimizationPolicy, cpu, memory resource.Quantity) corev1.ResourceList {
+	list := make(corev1.ResourceList, 2)
+	if policy.OptimizesCPU() {
+		list[corev1.ResourceCPU] = cpu
+	}
+	if policy.OptimizesMemory() {
+		list[corev1.ResourceMemory] = memory
+	}
+	return list
+}
+
+// applyPodResources sets the pod-level resources of a workload and removes the CPU and memory
+// requests and limits of the changed containers in a single strategic merge patch
+func (e *Engine) applyPodResources(
+	ctx context.Context,
+	workload *Workload,
+	changes []ContainerChange,
+	pod *corev1.ResourceRequirements,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) error {
+	ctrl.LoggerFrom(ctx).Info("Applying pod-level resources",
+		"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+		"aggregation", policy.Spec.UpdateStrategy.PodLevelResources.GetAggregation(),
+		"requests", pod.Requests,
+		"limits", pod.Limits,
+	)
+
+	patch, err := buildPodResourcePatch(workload, changes, pod, policy)
+	if err != nil {
+		return fmt.Errorf("failed to build patch: %w", err)
+	}
+	return e.patchWorkload(ctx, workload, patch, policy)
+}
+
+// buildPodResourcePatch builds a strategic merge patch that sets the pod template's resources to
+// pod and removes the CPU and memory requests and limits of the changed containers.
+//
+// Container limits are removed along with the requests, as Kubernetes would otherwise default a
+// container's requests to its limits. Pod limits are only written when pod has them; with
+// removeLimits they are removed. Pod limits limitConfig does not manage are left alone.
+func buildPodResourcePatch(
+	workload *Workload,
+	changes []ContainerChange,
+	pod *corev1.ResourceRequirements,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) ([]byte, error) {
+	switch workload.Kind {
+	case kindDeployment, kindStatefulSet, kindDaemonSet:
+	default:
+		return nil, fmt.Errorf("unsupported workload kind: %s", workload.Kind)
+	}
+
+	spec := make(map[string]interface{}, 3)
+	for _, change := range changes {
+		if change.Unchanged {
+			continue
+		}
+		if _, ok := findTemplateContainer(workload.Object, change.Container); !ok {
+			return nil, fmt.Errorf("container %s not found in workload", change.Container)
+		}
+		field := containerField(workload.Object, change.Container)
+		list, _ := spec[field].([]interface{})
+		spec[field] = append(list, map[string]interface{}{
+			"name": change.Container,
+			"resources": map[string]interface{}{
+				"requests": optimizedValues(policy, nil, nil),
+				"limits":   optimizedValues(policy, nil, nil),
+			},
+		})
+	}
+
+	resources := map[string]interface{}{
+		"requests": optimizedValues(policy, quantityString(pod.Requests, corev1.ResourceCPU), quantityString(pod.Requests, corev1.ResourceMemory)),
+	}
+	if pod.Limits != nil {
+		limits := optimizedValues(policy, quantityString(pod.Limits, corev1.ResourceCPU), quantityString(pod.Limits, corev1.ResourceMemory))
+		for name := range limits {
+			if _, ok := pod.Limits[corev1.ResourceName(name)]; !ok {
+				delete(limits, name)
+			}
+		}
+		if len(limits) > 0 {
+			resources["limits"] = limits
+		}
+	} else if policy.Spec.UpdateStrategy.RemoveLimits {
+		if limits := limitValues(policy, nil, nil); len(limits) > 0 {
+			resources["limits"] = limits
+		}
+	}
+	spec["resources"] = resources
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": lastAppliedAnnotations(changes),
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": spec,
+			},
+		},
+	}
+	// A partitioned rollout only updates the pods with the highest ordinals at first
+	if partition, floor, ok := startPartition(workload, policy); ok {
+		withPartition(patch, partition, floor)
+	}
+
+	patchUnstructured := &unstructured.Unstructured{Object: patch}
+	patchBytes, err := patchUnstructured.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode patch: %w", err)
+	}
+	return patchBytes, nil
+}
+
+// quantityString returns the string form of a resource in list
+func quantityString(list corev1.ResourceList, name corev1.ResourceName) string {
+	q := list[name]
+	return q.String()
+}
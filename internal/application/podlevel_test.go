@@ -0,0 +1,343 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/version"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// For any Kubernetes cluster version 1.34 or higher, pod-level resources are detected as
+// supported, as the PodLevelResources feature is enabled by default from 1.34.
+func TestProperty_PodLevelResourcesDetection(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	detect := func(minor int) (bool, error) {
+		engine := &Engine{
+			discoveryClient: &mockDiscoveryClient{
+				serverVersion: &version.Info{Major: "1", Minor: fmt.Sprintf("%d+", minor)},
+			},
+		}
+		return engine.detectPodLevelResources(context.Background())
+	}
+
+	properties.Property("versions >= 1.34 should support pod-level resources", prop.ForAll(
+		func(minor int) bool {
+			supported, err := detect(minor)
+			return err == nil && supported
+		},
+		gen.IntRange(34, 50),
+	))
+
+	properties.Property("versions < 1.34 should not support pod-level resources", prop.ForAll(
+		func(minor int) bool {
+			supported, err := detect(minor)
+			return err == nil && !supported
+		},
+		gen.IntRange(20, 33),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestDetectPodLevelResources_InvalidVersion(t *testing.T) {
+	engine := &Engine{
+		discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "x"}},
+	}
+	if supported, err := engine.detectPodLevelResources(context.Background()); err == nil || supported {
+		t.Errorf("detectPodLevelResources() = %v, %v, want an error", supported, err)
+	}
+}
+
+// newPodLevelTestWorkload returns the mock workload with a second container "log-shipper" and a
+// "metrics" container that has no recommendation
+func newPodLevelTestWorkload() *Workload {
+	workload := createMockWorkload()
+	containers, _ := templateContainers(workload.Object, fieldContainers)
+	containers = append(containers,
+		map[string]interface{}{
+			"name":      "log-shipper",
+			"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "200m", "memory": "256Mi"}},
+		},
+		map[string]interface{}{
+			"name":      "metrics",
+			"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "50m", "memory": "32Mi"}},
+		},
+	)
+	_ = unstructured.SetNestedSlice(workload.Object.Object, containers, "spec", "template", "spec", "containers")
+	return workload
+}
+
+func TestApply_PodLevelResources(t *testing.T) {
+	changes := []ContainerChange{
+		{Container: "test-container", Recommendation: createMockRecommendation()},
+		{Container: "log-shipper", Recommendation: &recommendation.Recommendation{
+			CPU:    resource.MustParse("100m"),
+			Memory: resource.MustParse("128Mi"),
+		}},
+	}
+
+	tests := []struct {
+		name        string
+		minor       string
+		aggregation string
+		wantPatch   types.PatchType
+		wantCPU     string
+		wantMemory  string
+	}{
+		{
+			name:        "sum of the recommendations and the requests of other containers",
+			minor:       "34",
+			aggregation: optipodv1alpha1.PodAggregationSum,
+			wantPatch:   types.StrategicMergePatchType,
+			wantCPU:     "750m",
+			wantMemory:  "1360Mi",
+		},
+		{
+			name:        "largest recommendation and the requests of other containers",
+			minor:       "35",
+			aggregation: optipodv1alpha1.PodAggregationMax,
+			wantPatch:   types.StrategicMergePatchType,
+			wantCPU:     "650m",
+			wantMemory:  "1232Mi",
+		},
+		{
+			name:        "unsupported clusters size containers individually",
+			minor:       "33",
+			aggregation: optipodv1alpha1.PodAggregationSum,
+			wantPatch:   types.ApplyPatchType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload := newPodLevelTestWorkload()
+			dynamicClient := &mockDynamicClientWithResult{result: workload.Object}
+			engine := &Engine{
+				dynamicClient:   dynamicClient,
+				discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: tt.minor}},
+			}
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.PodLevelResources = &optipodv1alpha1.PodLevelResources{Aggregation: tt.aggregation}
+
+			result, err := engine.Apply(context.Background(), workload, changes, policy)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if len(dynamicClient.calls) != 1 || dynamicClient.calls[0].patchType != tt.wantPatch {
+				t.Fatalf("sent %+v, want a single %s patch", dynamicClient.calls, tt.wantPatch)
+			}
+			if tt.wantCPU == "" {
+				if result.PodResources != nil {
+					t.Errorf("PodResources = %+v, want nil", result.PodResources)
+				}
+				return
+			}
+			if result.PodResources == nil || result.Method != "StrategicMergePatch" {
+				t.Fatalf("Apply() = %s with pod resources %+v, want pod-level resources by strategic merge patch",
+					result.Method, result.PodResources)
+			}
+
+			patched, err := strategicpatch.StrategicMergePatch(mustMarshal(t, workload.Object), dynamicClient.calls[0].data, appsv1.Deployment{})
+			if err != nil {
+				t.Fatalf("failed to apply patch: %v", err)
+			}
+			var deployment appsv1.Deployment
+			if err := json.Unmarshal(patched, &deployment); err != nil {
+				t.Fatalf("failed to parse patched workload: %v", err)
+			}
+
+			podSpec := deployment.Spec.Template.Spec
+			if podSpec.Resources == nil {
+				t.Fatal("pod-level resources were not set")
+			}
+			if cpu := podSpec.Resources.Requests.Cpu(); cpu.Cmp(resource.MustParse(tt.wantCPU)) != 0 {
+				t.Errorf("pod CPU request = %s, want %s", cpu, tt.wantCPU)
+			}
+			if memory := podSpec.Resources.Requests.Memory(); memory.Cmp(resource.MustParse(tt.wantMemory)) != 0 {
+				t.Errorf("pod memory request = %s, want %s", memory, tt.wantMemory)
+			}
+			if len(podSpec.Resources.Limits) != 0 {
+				t.Errorf("pod limits = %v, want none with updateRequestsOnly", podSpec.Resources.Limits)
+			}
+
+			// The changed containers share the pod's budget, the other keeps its requests
+			for _, container := range podSpec.Containers {
+				resources := container.Resources
+				if container.Name == "metrics" {
+					if len(resources.Requests) != 2 {
+						t.Errorf("metrics requests = %v, want them kept", resources.Requests)
+					}
+					continue
+				}
+				if len(resources.Requests) != 0 || len(resources.Limits) != 0 {
+					t.Errorf("%s resources = %+v, want CPU and memory removed", container.Name, resources)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildPodResourcePatch_Limits(t *testing.T) {
+	changes := []ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation(), keepGuaranteed: true}}
+	current := map[string]corev1.ResourceRequirements{}
+	engine := &Engine{discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "34"}}}
+
+	tests := []struct {
+		name       string
+		guaranteed bool
+		strategy   func(*optipodv1alpha1.UpdateStrategy)
+		wantLimits interface{}
+	}{
+		{
+			name:       "limits are aggregated like requests",
+			strategy:   func(s *optipodv1alpha1.UpdateStrategy) { s.UpdateRequestsOnly = false },
+			wantLimits: map[string]interface{}{"cpu": "600m", "memory": "1320Mi"},
+		},
+		{
+			name:       "guaranteed containers keep the pod guaranteed",
+			guaranteed: true,
+			strategy:   func(s *optipodv1alpha1.UpdateStrategy) {},
+			wantLimits: map[string]interface{}{"cpu": "600m", "memory": "1200Mi"},
+		},
+		{
+			name:       "removeLimits removes the pod limits",
+			strategy:   func(s *optipodv1alpha1.UpdateStrategy) { s.RemoveLimits = true },
+			wantLimits: map[string]interface{}{"cpu": nil, "memory": nil},
+		},
+		{
+			name:     "updateRequestsOnly leaves the pod limits alone",
+			strategy: func(s *optipodv1alpha1.UpdateStrategy) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.PodLevelResources = &optipodv1alpha1.PodLevelResources{}
+			tt.strategy(&policy.Spec.UpdateStrategy)
+			changes[0].keepGuaranteed = tt.guaranteed
+
+			workload := createMockWorkload()
+			pod := engine.podResources(context.Background(), workload, current, changes, policy)
+			if pod == nil {
+				t.Fatal("podResources() = nil, want pod-level resources")
+			}
+			patch, err := buildPodResourcePatch(workload, changes, pod, policy)
+			if err != nil {
+				t.Fatalf("buildPodResourcePatch() error = %v", err)
+			}
+
+			var obj map[string]interface{}
+			if err := json.Unmarshal(patch, &obj); err != nil {
+				t.Fatalf("failed to decode patch: %v", err)
+			}
+			limits, found, _ := unstructured.NestedFieldNoCopy(obj, "spec", "template", "spec", "resources", "limits")
+			if tt.wantLimits == nil {
+				if found {
+					t.Errorf("pod limits = %v, want them left out of the patch", limits)
+				}
+				return
+			}
+			if fmt.Sprint(limits) != fmt.Sprint(tt.wantLimits) {
+				t.Errorf("pod limits = %v, want %v", limits, tt.wantLimits)
+			}
+		})
+	}
+}
+
+func TestApply_PodLevelResourcesWithinTolerance(t *testing.T) {
+	changes := func(cpu string) []ContainerChange {
+		return []ContainerChange{{Container: "test-container", Recommendation: &recommendation.Recommendation{
+			CPU:    resource.MustParse(cpu),
+			Memory: resource.MustParse("1200Mi"),
+		}}}
+	}
+
+	tests := []struct {
+		name        string
+		cpu         string
+		tolerance   *optipodv1alpha1.ApplyTolerance
+		wantPatched bool
+	}{
+		{name: "the applied recommendation is not patched again", cpu: "600m"},
+		{name: "a changed recommendation is patched", cpu: "650m", wantPatched: true},
+		{
+			name: "a change within tolerance of the pod's resources is not patched",
+			cpu:  "650m",
+			tolerance: &optipodv1alpha1.ApplyTolerance{
+				CPU:    &optipodv1alpha1.ResourceTolerance{Absolute: resource.NewMilliQuantity(100, resource.DecimalSI)},
+				Memory: &optipodv1alpha1.ResourceTolerance{Absolute: resource.NewQuantity(0, resource.BinarySI)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload := createMockWorkload()
+			dynamicClient := &mockDynamicClientWithResult{result: workload.Object}
+			engine := &Engine{
+				dynamicClient:   dynamicClient,
+				discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "34"}},
+			}
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.PodLevelResources = &optipodv1alpha1.PodLevelResources{}
+
+			// The first apply moves the container's requests to the pod
+			if _, err := engine.Apply(context.Background(), workload, changes("600m"), policy); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			patched, err := strategicpatch.StrategicMergePatch(mustMarshal(t, workload.Object), dynamicClient.calls[0].data, appsv1.Deployment{})
+			if err != nil {
+				t.Fatalf("failed to apply patch: %v", err)
+			}
+			if err := json.Unmarshal(patched, &workload.Object.Object); err != nil {
+				t.Fatalf("failed to parse patched workload: %v", err)
+			}
+
+			policy.Spec.UpdateStrategy.ApplyTolerance = tt.tolerance
+			dynamicClient = &mockDynamicClientWithResult{result: workload.Object}
+			engine.dynamicClient = dynamicClient
+			result, err := engine.Apply(context.Background(), workload, changes(tt.cpu), policy)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if patched := len(dynamicClient.calls) > 0; patched != tt.wantPatched || result.PodResourcesUnchanged == tt.wantPatched {
+				t.Errorf("patched = %t (unchanged %t), want patched %t", patched, result.PodResourcesUnchanged, tt.wantPatched)
+			}
+			if cpu := result.PodResources.Requests.Cpu(); !tt.wantPatched && cpu.Cmp(resource.MustParse("600m")) != 0 {
+				t.Errorf("PodResources CPU = %s, want the pod template's 600m", cpu)
+			}
+		})
+	}
+}
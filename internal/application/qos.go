@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// isGuaranteedContainer reports whether a container has CPU and memory limits equal to its
+// requests, which its pod needs to be Guaranteed. A missing request defaults to the limit.
+func isGuaranteedContainer(resources corev1.ResourceRequirements) bool {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		limit, ok := resources.Limits[name]
+		if !ok || limit.IsZero() {
+			return false
+		}
+		if request, ok := resources.Requests[name]; ok && request.Cmp(limit) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// keepsGuaranteed reports whether an update limits the optimized resources of the container to
+// their new requests, so the container stays Guaranteed. This is the default for Guaranteed
+// containers unless the policy allows QoS class changes.
+func keepsGuaranteed(current corev1.ResourceRequirements, policy *optipodv1alpha1.OptimizationPolicy) bool {
+	return !policy.Spec.UpdateStrategy.AllowQoSClassChange && isGuaranteedContainer(current)
+}
+
+// runningResources returns the current resources with those of the running pods when they were
+// consistently resized in-place, which is how CanApply sees them
+func (e *Engine) runningResources(
+	ctx context.Context,
+	workload *Workload,
+	current map[string]corev1.ResourceRequirements,
+) map[string]corev1.ResourceRequirements {
+	live, ok, err := e.livePodResources(ctx, workload)
+	if err != nil || !ok {
+		return current
+	}
+	running := maps.Clone(current)
+	for name, reqs := range live {
+		if _, inTemplate := running[name]; inTemplate {
+			running[name] = reqs
+		}
+	}
+	return running
+}
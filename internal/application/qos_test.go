@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+func TestIsGuaranteedContainer(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources corev1.ResourceRequirements
+		want      bool
+	}{
+		{
+			name:      "requests equal to limits",
+			resources: corev1.ResourceRequirements{Requests: resourceList("500m", "512Mi"), Limits: resourceList("500m", "512Mi")},
+			want:      true,
+		},
+		{
+			name:      "limits without requests",
+			resources: corev1.ResourceRequirements{Limits: resourceList("500m", "512Mi")},
+			want:      true,
+		},
+		{
+			name:      "limits above requests",
+			resources: corev1.ResourceRequirements{Requests: resourceList("250m", "512Mi"), Limits: resourceList("500m", "512Mi")},
+		},
+		{
+			name: "no CPU limit",
+			resources: corev1.ResourceRequirements{
+				Requests: resourceList("500m", "512Mi"),
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+		},
+		{name: "no resources"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isGuaranteedContainer(tt.resources); got != tt.want {
+				t.Errorf("isGuaranteedContainer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply_GuaranteedContainers(t *testing.T) {
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("400m"), Memory: resource.MustParse("768Mi")}
+
+	tests := []struct {
+		name               string
+		useSSA             bool
+		updateRequestsOnly bool
+		removeLimits       bool
+		allowQoSChange     bool
+		wantLimits         corev1.ResourceList
+		wantQoS            corev1.PodQOSClass
+	}{
+		{
+			name:               "requests-only apply keeps the limits equal",
+			useSSA:             true,
+			updateRequestsOnly: true,
+			wantLimits:         resourceList("400m", "768Mi"),
+			wantQoS:            corev1.PodQOSGuaranteed,
+		},
+		{
+			name:       "limit multipliers are not applied",
+			useSSA:     true,
+			wantLimits: resourceList("400m", "768Mi"),
+			wantQoS:    corev1.PodQOSGuaranteed,
+		},
+		{
+			name:         "limits are not removed by a strategic merge patch",
+			removeLimits: true,
+			wantLimits:   resourceList("400m", "768Mi"),
+			wantQoS:      corev1.PodQOSGuaranteed,
+		},
+		{
+			name:               "the QoS class changes when the policy allows it",
+			useSSA:             true,
+			updateRequestsOnly: true,
+			allowQoSChange:     true,
+			wantLimits:         resourceList("500m", "512Mi"),
+			wantQoS:            corev1.PodQOSBurstable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			engine := newFakeClusterEngine(c, kindDeployment)
+			newFakeClusterDeployment(t, c, resourceList("500m", "512Mi"), resourceList("500m", "512Mi"))
+			workload, _, err := getFakeClusterWorkload(c)
+			if err != nil {
+				t.Fatalf("failed to get deployment: %v", err)
+			}
+
+			policy := createMockPolicy(false, true)
+			policy.Spec.UpdateStrategy.UseServerSideApply = &tt.useSSA
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = tt.updateRequestsOnly
+			policy.Spec.UpdateStrategy.RemoveLimits = tt.removeLimits
+			policy.Spec.UpdateStrategy.AllowQoSClassChange = tt.allowQoSChange
+
+			result, err := engine.Apply(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			_, deployment, err := getFakeClusterWorkload(c)
+			if err != nil {
+				t.Fatalf("failed to get deployment: %v", err)
+			}
+			resources := deployment.Spec.Template.Spec.Containers[0].Resources
+			for name, want := range tt.wantLimits {
+				if got := resources.Limits[name]; got.Cmp(want) != 0 {
+					t.Errorf("%s limit = %s, want %s", name, got.String(), want.String())
+				}
+			}
+			if qos := podQOSClass(map[string]corev1.ResourceRequirements{"test-container": resources}); qos != tt.wantQoS {
+				t.Errorf("QoS class = %s, want %s", qos, tt.wantQoS)
+			}
+			if downgraded := result.Containers["test-container"].QoSDowngraded; downgraded != (tt.wantQoS == corev1.PodQOSBurstable) {
+				t.Errorf("QoSDowngraded = %v, want %v", downgraded, tt.wantQoS == corev1.PodQOSBurstable)
+			}
+		})
+	}
+}
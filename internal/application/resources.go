@@ -0,0 +1,251 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// optimizedValues returns the patch fields of the resources the policy optimizes. A resource
+// the policy does not optimize is left out, so the patch never touches it.
+func optimizedValues(policy *optipodv1alpha1.OptimizationPolicy, cpu, memory interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, 2)
+	if policy.OptimizesCPU() {
+		values[string(corev1.ResourceCPU)] = cpu
+	}
+	if policy.OptimizesMemory() {
+		values[string(corev1.ResourceMemory)] = memory
+	}
+	return values
+}
+
+// limitValues returns the patch fields of the limits optipod manages: those of the resources the
+// policy optimizes, less those limitConfig leaves alone
+func limitValues(policy *optipodv1alpha1.OptimizationPolicy, cpu, memory interface{}) map[string]interface{} {
+	values := optimizedValues(policy, cpu, memory)
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if !managesLimit(policy, name) {
+			delete(values, string(name))
+		}
+	}
+	return values
+}
+
+// currentResourceValues returns the patch fields of an unchanged container's current requests of
+// the resources the policy optimizes and of the limits optipod would apply to it. Values the
+// container does not have are left out.
+func currentResourceValues(workload *Workload, change ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) map[string]interface{} {
+	current := containerResourceFields(workload.Object, change.Container)
+	resources := make(map[string]interface{}, 2)
+	if requests := presentValues(optimizedValues(policy, nil, nil), current["requests"]); len(requests) > 0 {
+		resources["requests"] = requests
+	}
+
+	var limits map[string]interface{}
+	if change.keepGuaranteed {
+		limits = optimizedValues(policy, nil, nil)
+	} else if !policy.Spec.UpdateStrategy.UpdateRequestsOnly && !policy.Spec.UpdateStrategy.RemoveLimits {
+		limits = limitValues(policy, nil, nil)
+	}
+	if limits = presentValues(limits, current["limits"]); len(limits) > 0 {
+		resources["limits"] = limits
+	}
+	return resources
+}
+
+// presentValues sets each key of fields to its value in current, removing the keys current lacks
+func presentValues(fields, current map[string]interface{}) map[string]interface{} {
+	for name := range fields {
+		value, ok := current[name]
+		if !ok || value == nil {
+			delete(fields, name)
+			continue
+		}
+		fields[name] = value
+	}
+	return fields
+}
+
+// managesLimit reports whether limitConfig lets optipod set and remove the limit of a resource
+func managesLimit(policy *optipodv1alpha1.OptimizationPolicy, name corev1.ResourceName) bool {
+	switch name {
+	case corev1.ResourceCPU:
+		return policy.Spec.UpdateStrategy.ManagesCPULimit()
+	case corev1.ResourceMemory:
+		return policy.Spec.UpdateStrategy.ManagesMemoryLimit()
+	}
+	return false
+}
+
+// untouchedResources returns the resources the policy does not optimize
+func untouchedResources(policy *optipodv1alpha1.OptimizationPolicy) []corev1.ResourceName {
+	var names []corev1.ResourceName
+	if !policy.OptimizesCPU() {
+		names = append(names, corev1.ResourceCPU)
+	}
+	if !policy.OptimizesMemory() {
+		names = append(names, corev1.ResourceMemory)
+	}
+	return names
+}
+
+// untouchedLimits returns the resources whose limits optipod leaves alone: those the policy does
+// not optimize and those limitConfig does not manage
+func untouchedLimits(policy *optipodv1alpha1.OptimizationPolicy) []corev1.ResourceName {
+	names := untouchedResources(policy)
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if !managesLimit(policy, name) && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// restoreUntouchedResources puts back the requests and limits of resources the policy does not
+// optimize, and the limits limitConfig does not manage, when a Server-Side Apply changed them.
+// The limits of a container kept Guaranteed follow its requests, so they are not restored.
+//
+// The apply leaves these resources out, which removes them when optipod was their only field
+// manager, for example after an earlier apply made while the policy still optimized them.
+// They are restored to their values before the apply with a strategic merge patch, whose
+// ownership is separate from optipod's applied fields, so later applies keep them.
+func (e *Engine) restoreUntouchedResources(
+	ctx context.Context,
+	gvr schema.GroupVersionResource,
+	workload *Workload,
+	applied *unstructured.Unstructured,
+	change ContainerChange,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) error {
+	containerName := change.Container
+	untouched := map[string][]corev1.ResourceName{
+		"requests": untouchedResources(policy),
+		"limits":   untouchedLimits(policy),
+	}
+	if change.keepGuaranteed {
+		untouched["limits"] = untouched["requests"]
+	}
+	if applied == nil || workload.Object == nil || len(untouched["limits"]) == 0 {
+		return nil
+	}
+
+	before := containerResourceFields(workload.Object, containerName)
+	after := containerResourceFields(applied, containerName)
+
+	resources := make(map[string]interface{})
+	for _, field := range []string{"requests", "limits"} {
+		restored := make(map[string]interface{})
+		for _, name := range untouched[field] {
+			value := before[field][string(name)]
+			if !sameQuantity(value, after[field][string(name)]) {
+				// A nil value removes a resource that was not set before the apply
+				restored[string(name)] = value
+			}
+		}
+		if len(restored) > 0 {
+			resources[field] = restored
+		}
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	log.Info("Restoring resources the policy leaves alone",
+		"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+		"container", containerName,
+		"requests", untouched["requests"],
+		"limits", untouched["limits"],
+	)
+
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					containerField(workload.Object, containerName): []interface{}{
+						map[string]interface{}{
+							"name":      containerName,
+							"resources": resources,
+						},
+					},
+				},
+			},
+		},
+	}
+	patchBytes, err := (&unstructured.Unstructured{Object: patch}).MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to build resource restore patch: %w", err)
+	}
+
+	_, err = e.dynamicClient.Resource(gvr).Namespace(workload.Namespace).Patch(
+		ctx,
+		workload.Name,
+		types.StrategicMergePatchType,
+		patchBytes,
+		metav1.PatchOptions{FieldManager: "optipod"},
+	)
+	if err != nil {
+		if errors.IsForbidden(err) {
+			return fmt.Errorf("%w: insufficient permissions to restore resources: %w", ErrRBACDenied, err)
+		}
+		return fmt.Errorf("failed to restore resources of container %s: %w", containerName, err)
+	}
+
+	return nil
+}
+
+// containerResourceFields returns the requests and limits of the named container of a workload
+func containerResourceFields(obj *unstructured.Unstructured, containerName string) map[string]map[string]interface{} {
+	fields := make(map[string]map[string]interface{}, 2)
+
+	if container, ok := findTemplateContainer(obj, containerName); ok {
+		fields["requests"], _, _ = unstructured.NestedMap(container, "resources", "requests")
+		fields["limits"], _, _ = unstructured.NestedMap(container, "resources", "limits")
+	}
+
+	return fields
+}
+
+// sameQuantity reports whether two resource values of an unstructured object are equal. Values
+// are compared as quantities, since the API server normalizes them (e.g. "0.5" to "500m").
+func sameQuantity(a, b interface{}) bool {
+	aString, aOK := a.(string)
+	bString, bOK := b.(string)
+	if !aOK || !bOK {
+		return a == nil && b == nil
+	}
+
+	aQuantity, aErr := resource.ParseQuantity(aString)
+	bQuantity, bErr := resource.ParseQuantity(bString)
+	if aErr != nil || bErr != nil {
+		return aString == bString
+	}
+	return aQuantity.Cmp(bQuantity) == 0
+}
@@ -0,0 +1,418 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// newResourceOptimizationPolicy returns a policy that optimizes only the given resources
+func newResourceOptimizationPolicy(optimizeCPU, optimizeMemory, updateRequestsOnly bool) *optipodv1alpha1.OptimizationPolicy {
+	policy := createMockPolicy(true, false)
+	policy.Spec.OptimizeCPU = &optimizeCPU
+	policy.Spec.OptimizeMemory = &optimizeMemory
+	policy.Spec.UpdateStrategy.UpdateRequestsOnly = updateRequestsOnly
+	return policy
+}
+
+func TestBuildPatch_SkipsResourcesNotOptimized(t *testing.T) {
+	engine := &Engine{}
+
+	tests := []struct {
+		name           string
+		optimizeCPU    bool
+		optimizeMemory bool
+		wantResources  []string
+	}{
+		{name: "both resources", optimizeCPU: true, optimizeMemory: true, wantResources: []string{"cpu", "memory"}},
+		{name: "CPU only", optimizeCPU: true, optimizeMemory: false, wantResources: []string{"cpu"}},
+		{name: "memory only", optimizeCPU: false, optimizeMemory: true, wantResources: []string{"memory"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newResourceOptimizationPolicy(tt.optimizeCPU, tt.optimizeMemory, false)
+
+			ssaPatch, err := engine.buildSSAPatch(createMockWorkload(), []ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, policy)
+			if err != nil {
+				t.Fatalf("failed to build SSA patch: %v", err)
+			}
+			mergePatch, err := engine.buildResourcePatch(createMockWorkload(), []ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, policy)
+			if err != nil {
+				t.Fatalf("failed to build patch: %v", err)
+			}
+
+			for _, patch := range [][]byte{ssaPatch, mergePatch} {
+				var patchObj map[string]interface{}
+				if err := json.Unmarshal(patch, &patchObj); err != nil {
+					t.Fatalf("failed to parse patch: %v", err)
+				}
+
+				requests, limits := containerResources(t, patchObj)
+				for _, fields := range []map[string]interface{}{requests, limits} {
+					if len(fields) != len(tt.wantResources) {
+						t.Errorf("patch sets %v, want only %v", fields, tt.wantResources)
+					}
+					for _, name := range tt.wantResources {
+						if _, ok := fields[name]; !ok {
+							t.Errorf("patch sets %v, want %s", fields, name)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// fakeClusterDynamicClient serves dynamic client patches from a controller-runtime fake client,
+// which implements Server-Side Apply field ownership like the API server
+type fakeClusterDynamicClient struct {
+	dynamic.Interface
+	dynamic.NamespaceableResourceInterface
+	client client.Client
+	gvk    schema.GroupVersionKind
+	ns     string
+}
+
+func (f *fakeClusterDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return f
+}
+
+func (f *fakeClusterDynamicClient) Namespace(ns string) dynamic.ResourceInterface {
+	return &fakeClusterDynamicClient{client: f.client, gvk: f.gvk, ns: ns}
+}
+
+func (f *fakeClusterDynamicClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(f.gvk)
+	obj.SetNamespace(f.ns)
+	obj.SetName(name)
+
+	opts := []client.PatchOption{client.FieldOwner(options.FieldManager)}
+	if options.Force != nil && *options.Force {
+		opts = append(opts, client.ForceOwnership)
+	}
+	if err := f.client.Patch(ctx, obj, client.RawPatch(pt, data), opts...); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (f *fakeClusterDynamicClient) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(f.gvk)
+	if err := f.client.Get(ctx, client.ObjectKey{Namespace: f.ns, Name: name}, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// newFakeClusterEngine returns an engine that reads from and patches workloads of the given kind
+// in the fake cluster c
+func newFakeClusterEngine(c client.Client, kind string) *Engine {
+	return &Engine{client: c, dynamicClient: &fakeClusterDynamicClient{
+		client: c,
+		gvk:    appsv1.SchemeGroupVersion.WithKind(kind),
+	}}
+}
+
+// newFakeClusterPodTemplate returns the pod template of the fake cluster workloads, labeled
+// app=test, whose single container has the given requests and limits
+func newFakeClusterPodTemplate(requests, limits corev1.ResourceList) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:      "test-container",
+			Image:     "nginx",
+			Resources: corev1.ResourceRequirements{Requests: requests, Limits: limits},
+		}}},
+	}
+}
+
+// newFakeClusterDeployment creates a deployment owned by kubectl with the given requests and limits
+func newFakeClusterDeployment(t *testing.T, c client.Client, requests, limits corev1.ResourceList) {
+	t.Helper()
+
+	template := newFakeClusterPodTemplate(requests, limits)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: template.Labels},
+			Template: template,
+		},
+	}
+	if err := c.Create(context.Background(), deployment, client.FieldOwner("kubectl")); err != nil {
+		t.Fatalf("failed to create deployment: %v", err)
+	}
+}
+
+// getFakeClusterWorkload reads the deployment back as the workload optipod applies to
+func getFakeClusterWorkload(c client.Client) (*Workload, *appsv1.Deployment, error) {
+	deployment := &appsv1.Deployment{}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "test-deployment"}, deployment); err != nil {
+		return nil, nil, err
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Workload{
+		Kind:      kindDeployment,
+		Namespace: "default",
+		Name:      "test-deployment",
+		Object:    &unstructured.Unstructured{Object: obj},
+	}, deployment, nil
+}
+
+// Feature: per-resource-optimization, Property 1: Untouched resource preserved through SSA
+// For any workload and any policy that optimizes only one of CPU and memory, applying a
+// recommendation with Server-Side Apply leaves the requests and limits of the other resource
+// exactly as they were, even when optipod owned them from an earlier apply of both resources.
+func TestProperty_UntouchedResourcePreservedThroughSSA(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("the resource the policy does not optimize is unchanged by the apply", prop.ForAll(
+		func(cpuMillis, memoryMiB int64, optimizeCPU, previouslyOptimized, updateRequestsOnly bool) bool {
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			engine := newFakeClusterEngine(c, kindDeployment)
+
+			newFakeClusterDeployment(t, c,
+				corev1.ResourceList{
+					corev1.ResourceCPU:    *resource.NewMilliQuantity(cpuMillis, resource.DecimalSI),
+					corev1.ResourceMemory: *resource.NewQuantity(memoryMiB*1024*1024, resource.BinarySI),
+				},
+				corev1.ResourceList{
+					corev1.ResourceCPU:    *resource.NewMilliQuantity(2*cpuMillis, resource.DecimalSI),
+					corev1.ResourceMemory: *resource.NewQuantity(2*memoryMiB*1024*1024, resource.BinarySI),
+				},
+			)
+
+			rec := &recommendation.Recommendation{
+				CPU:    *resource.NewMilliQuantity(cpuMillis+50, resource.DecimalSI),
+				Memory: *resource.NewQuantity((memoryMiB+64)*1024*1024, resource.BinarySI),
+			}
+
+			// An earlier apply of both resources makes optipod the owner of the untouched one
+			if previouslyOptimized {
+				workload, _, err := getFakeClusterWorkload(c)
+				if err != nil {
+					t.Logf("failed to get workload: %v", err)
+					return false
+				}
+				if err := engine.ApplyWithSSA(context.Background(), workload,
+					[]ContainerChange{{Container: "test-container", Recommendation: rec}}, newResourceOptimizationPolicy(true, true, false)); err != nil {
+					t.Logf("failed to apply both resources: %v", err)
+					return false
+				}
+				rec = &recommendation.Recommendation{
+					CPU:    *resource.NewMilliQuantity(cpuMillis+100, resource.DecimalSI),
+					Memory: *resource.NewQuantity((memoryMiB+128)*1024*1024, resource.BinarySI),
+				}
+			}
+
+			workload, before, err := getFakeClusterWorkload(c)
+			if err != nil {
+				t.Logf("failed to get workload: %v", err)
+				return false
+			}
+
+			policy := newResourceOptimizationPolicy(optimizeCPU, !optimizeCPU, updateRequestsOnly)
+			if err := engine.ApplyWithSSA(context.Background(), workload, []ContainerChange{{Container: "test-container", Recommendation: rec}}, policy); err != nil {
+				t.Logf("failed to apply: %v", err)
+				return false
+			}
+
+			_, after, err := getFakeClusterWorkload(c)
+			if err != nil {
+				t.Logf("failed to get workload: %v", err)
+				return false
+			}
+
+			untouched, optimized, want := corev1.ResourceMemory, corev1.ResourceCPU, rec.CPU
+			if !optimizeCPU {
+				untouched, optimized, want = corev1.ResourceCPU, corev1.ResourceMemory, rec.Memory
+			}
+
+			beforeResources := before.Spec.Template.Spec.Containers[0].Resources
+			afterResources := after.Spec.Template.Spec.Containers[0].Resources
+			for _, check := range []struct {
+				field         string
+				before, after corev1.ResourceList
+			}{
+				{"requests", beforeResources.Requests, afterResources.Requests},
+				{"limits", beforeResources.Limits, afterResources.Limits},
+			} {
+				beforeValue, hadValue := check.before[untouched]
+				afterValue, hasValue := check.after[untouched]
+				if hadValue != hasValue || beforeValue.Cmp(afterValue) != 0 {
+					t.Logf("%s %s changed from %s to %s", untouched, check.field, beforeValue.String(), afterValue.String())
+					return false
+				}
+			}
+
+			got := afterResources.Requests[optimized]
+			if got.Cmp(want) != 0 {
+				t.Logf("%s request = %s, want the recommendation %s", optimized, got.String(), want.String())
+				return false
+			}
+			return true
+		},
+		gen.Int64Range(100, 4000),
+		gen.Int64Range(128, 8192),
+		gen.Bool(),
+		gen.Bool(),
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestInPlaceResizeConflict_KeepsResourcesNotOptimized(t *testing.T) {
+	engine := &Engine{}
+
+	// A Guaranteed container stays Guaranteed when only CPU is resized with its limit,
+	// since the untouched memory request and limit keep matching
+	current := map[string]corev1.ResourceRequirements{
+		"test-container": {
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+		},
+	}
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("250m")}
+	policy := newResourceOptimizationPolicy(true, false, false)
+
+	if conflict := engine.inPlaceResizeConflict(current, "test-container", rec, policy); conflict != "" {
+		t.Errorf("inPlaceResizeConflict() = %q, want none", conflict)
+	}
+}
+
+func TestApplyWithSSA_KeepsUnchangedContainerResources(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	engine := newFakeClusterEngine(c, kindDeployment)
+	newFakeClusterDeployment(t, c,
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+	)
+	policy := newResourceOptimizationPolicy(true, true, false)
+
+	// The first apply makes optipod the owner of the container's requests and limits
+	workload, _, err := getFakeClusterWorkload(c)
+	if err != nil {
+		t.Fatalf("failed to get workload: %v", err)
+	}
+	if err := engine.ApplyWithSSA(context.Background(), workload,
+		[]ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, policy); err != nil {
+		t.Fatalf("failed to apply: %v", err)
+	}
+
+	workload, before, err := getFakeClusterWorkload(c)
+	if err != nil {
+		t.Fatalf("failed to get workload: %v", err)
+	}
+	if err := engine.ApplyWithSSA(context.Background(), workload,
+		[]ContainerChange{{Container: "test-container", Unchanged: true}}, policy); err != nil {
+		t.Fatalf("failed to apply the unchanged container: %v", err)
+	}
+
+	_, after, err := getFakeClusterWorkload(c)
+	if err != nil {
+		t.Fatalf("failed to get workload: %v", err)
+	}
+	beforeResources := before.Spec.Template.Spec.Containers[0].Resources
+	afterResources := after.Spec.Template.Spec.Containers[0].Resources
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		for field, lists := range map[string][2]corev1.ResourceList{
+			"requests": {beforeResources.Requests, afterResources.Requests},
+			"limits":   {beforeResources.Limits, afterResources.Limits},
+		} {
+			beforeValue := lists[0][name]
+			afterValue, ok := lists[1][name]
+			if !ok || afterValue.Cmp(beforeValue) != 0 {
+				t.Errorf("%s %s = %s (set %t), want it kept at %s", name, field, afterValue.String(), ok, beforeValue.String())
+			}
+		}
+	}
+}
+
+// A replica that lost leadership without noticing yet applies from the workload it read before
+// the new leader changed it. The apply carries the resourceVersion it was built from, so the API
+// server rejects it as a conflict.
+func TestBuildSSAPatch_CarriesResourceVersion(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	engine := newFakeClusterEngine(c, kindDeployment)
+	newFakeClusterDeployment(t, c,
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("512Mi")}, nil)
+	policy := newResourceOptimizationPolicy(true, true, true)
+
+	workload, deployment, err := getFakeClusterWorkload(c)
+	if err != nil {
+		t.Fatalf("failed to get workload: %v", err)
+	}
+	patch, err := engine.buildSSAPatch(workload,
+		[]ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, policy)
+	if err != nil {
+		t.Fatalf("failed to build SSA patch: %v", err)
+	}
+	applied := &unstructured.Unstructured{}
+	if err := applied.UnmarshalJSON(patch); err != nil {
+		t.Fatalf("failed to parse patch: %v", err)
+	}
+	if got := applied.GetResourceVersion(); got == "" || got != deployment.ResourceVersion {
+		t.Errorf("patch resourceVersion = %q, want %q", got, deployment.ResourceVersion)
+	}
+
+	// A workload built without a resourceVersion is applied unconditionally
+	patch, err = engine.buildSSAPatch(createMockWorkload(),
+		[]ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, policy)
+	if err != nil {
+		t.Fatalf("failed to build SSA patch: %v", err)
+	}
+	applied = &unstructured.Unstructured{}
+	if err := applied.UnmarshalJSON(patch); err != nil {
+		t.Fatalf("failed to parse patch: %v", err)
+	}
+	if got := applied.GetResourceVersion(); got != "" {
+		t.Errorf("patch resourceVersion = %q, want none", got)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Defaults of the patch retry budget
+const (
+	// DefaultPatchAttempts is the number of times a patch failing with a transient error is
+	// attempted in total
+	DefaultPatchAttempts = 3
+
+	// DefaultPatchBackoff is the base delay before retrying a failed patch
+	DefaultPatchBackoff = 100 * time.Millisecond
+
+	// maxRetryBackoff caps the delay before a single retry, before jitter
+	maxRetryBackoff = 5 * time.Second
+)
+
+// SetPatchRetry sets how many times a patch failing with a transient error is attempted in
+// total, and the base delay between attempts. The delay doubles with every retry and is jittered
+// by up to 100%, so retries of many workloads do not hit a recovering API server at once.
+// One attempt disables retries.
+func (e *Engine) SetPatchRetry(attempts int, backoff time.Duration) {
+	e.patchAttempts = attempts
+	e.patchBackoff = backoff
+}
+
+// isRetryable reports whether an apply failed for a reason that may pass on its own: the API
+// server timed out, throttled the request or was briefly unavailable, or a strategic merge patch
+// or Server-Side Apply conflicted with a concurrent change. Server-Side Apply forces ownership
+// of its fields, so it only conflicts when the workload changed since it was read. Other
+// failures, such as RBAC denials or invalid patches, would fail the same way again and are never
+// retried.
+func isRetryable(err error) bool {
+	return errors.Is(err, ErrPatchConflict) ||
+		apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) || apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) || apierrors.IsInternalError(err)
+}
+
+// retryDelay returns the jittered delay before the given retry, starting at 1
+func retryDelay(base time.Duration, retry int) time.Duration {
+	delay := base
+	for i := 1; i < retry && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return wait.Jitter(min(delay, maxRetryBackoff), 1.0)
+}
+
+// retryTransient runs attempt until it succeeds, fails with an error that is not retryable, or
+// the retry budget is spent. Every retry re-reads the workload, so the patch is rebuilt from its
+// latest version.
+func (e *Engine) retryTransient(
+	ctx context.Context,
+	workload *Workload,
+	attempt func(workload *Workload) (*ApplyResult, error),
+) (*ApplyResult, error) {
+	for retry := 0; ; retry++ {
+		result, err := attempt(workload)
+		if err == nil || !isRetryable(err) || retry+1 >= e.patchAttempts {
+			if err != nil && retry > 0 && isRetryable(err) {
+				return nil, fmt.Errorf("gave up after %d attempts: %w", retry+1, err)
+			}
+			return result, err
+		}
+
+		delay := retryDelay(e.patchBackoff, retry+1)
+		ctrl.LoggerFrom(ctx).Info("Patch failed with a transient error, retrying with the latest workload",
+			"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+			"attempt", retry+1,
+			"delay", delay,
+			"error", err.Error(),
+		)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, errors.Join(err, ctx.Err())
+		}
+
+		if workload, err = e.refreshWorkload(ctx, workload); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// refreshWorkload returns the workload with its object read again from the API server
+func (e *Engine) refreshWorkload(ctx context.Context, workload *Workload) (*Workload, error) {
+	gvr, err := e.getGVR(workload.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GVR: %w", err)
+	}
+	latest, err := e.dynamicClient.Resource(gvr).Namespace(workload.Namespace).Get(ctx, workload.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-read workload before retrying the patch: %w", err)
+	}
+	refreshed := *workload
+	refreshed.Object = latest
+	return &refreshed, nil
+}
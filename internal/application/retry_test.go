@@ -0,0 +1,225 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// conflictingDynamicClient fails the first transient patches with an unavailable API server and
+// the next conflicts patches with a conflict, or every patch with err when set, and serves the
+// latest workload version on reads
+type conflictingDynamicClient struct {
+	dynamic.Interface
+	dynamic.NamespaceableResourceInterface
+	transient int
+	conflicts int
+	err       error
+	latest    *unstructured.Unstructured
+
+	// calls records "patch" and "get" in the order they were made
+	calls []string
+}
+
+func (m *conflictingDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return m
+}
+
+func (m *conflictingDynamicClient) Namespace(ns string) dynamic.ResourceInterface {
+	return m
+}
+
+func (m *conflictingDynamicClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	m.calls = append(m.calls, "patch")
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.transient > 0 {
+		m.transient--
+		return nil, apierrors.NewServiceUnavailable("etcdserver: leader changed")
+	}
+	if m.conflicts > 0 {
+		m.conflicts--
+		return nil, apierrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, name,
+			errors.New("the object has been modified"))
+	}
+	return m.latest, nil
+}
+
+func (m *conflictingDynamicClient) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	m.calls = append(m.calls, "get")
+	return m.latest.DeepCopy(), nil
+}
+
+func TestApply_PatchRetry(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "test-deployment",
+		errors.New("user cannot patch resource"))
+
+	tests := []struct {
+		name          string
+		useSSA        bool
+		attempts      int
+		transient     int
+		conflicts     int
+		err           error
+		wantCalls     []string
+		wantErr       error
+		wantTransient bool
+	}{
+		{
+			name:      "transient server errors succeed on retry",
+			useSSA:    true,
+			attempts:  3,
+			transient: 2,
+			wantCalls: []string{"patch", "get", "patch", "get", "patch"},
+		},
+		{
+			name:      "transient strategic merge conflict succeeds on retry",
+			attempts:  3,
+			conflicts: 1,
+			wantCalls: []string{"patch", "get", "patch"},
+		},
+		{
+			name:          "transient server errors beyond the budget fail",
+			useSSA:        true,
+			attempts:      2,
+			transient:     5,
+			wantCalls:     []string{"patch", "get", "patch"},
+			wantTransient: true,
+		},
+		{
+			name:      "strategic merge conflicts beyond the budget fail",
+			attempts:  2,
+			conflicts: 5,
+			wantCalls: []string{"patch", "get", "patch"},
+			wantErr:   ErrPatchConflict,
+		},
+		{
+			name:          "a single attempt does not retry",
+			useSSA:        true,
+			attempts:      1,
+			transient:     1,
+			wantCalls:     []string{"patch"},
+			wantTransient: true,
+		},
+		{
+			// The apply carries the resourceVersion it was built from, so it is rebuilt from the
+			// latest version of the workload
+			name:      "SSA on a modified workload succeeds on retry",
+			useSSA:    true,
+			attempts:  3,
+			conflicts: 1,
+			wantCalls: []string{"patch", "get", "patch"},
+		},
+		{
+			// Server-Side Apply forces ownership, so a field manager conflict is not a race that a
+			// retry resolves
+			name:      "SSA field manager conflicts are not retried",
+			useSSA:    true,
+			attempts:  3,
+			err:       newFieldManagerConflict("test-deployment"),
+			wantCalls: []string{"patch"},
+			wantErr:   ErrSSAConflict,
+		},
+		{
+			name:      "RBAC denials are never retried",
+			useSSA:    true,
+			attempts:  3,
+			err:       forbidden,
+			wantCalls: []string{"patch"},
+			wantErr:   ErrRBACDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := &conflictingDynamicClient{
+				transient: tt.transient,
+				conflicts: tt.conflicts,
+				err:       tt.err,
+				latest:    createMockWorkload().Object,
+			}
+			engine := &Engine{dynamicClient: dynamicClient}
+			engine.SetPatchRetry(tt.attempts, time.Millisecond)
+
+			policy := createMockPolicy(true, false)
+			policy.Spec.UpdateStrategy.UseServerSideApply = &tt.useSSA
+
+			result, err := engine.Apply(context.Background(), createMockWorkload(),
+				[]ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, policy)
+			if tt.wantTransient {
+				if !apierrors.IsServiceUnavailable(err) {
+					t.Fatalf("Apply() error = %v, want the transient error", err)
+				}
+			} else if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && result == nil {
+				t.Fatal("Apply() returned no result")
+			}
+			if !slices.Equal(dynamicClient.calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", dynamicClient.calls, tt.wantCalls)
+			}
+			if tt.wantErr != nil && ErrorReason(err) == "" {
+				t.Errorf("ErrorReason(%v) is empty, want the failure classified for the ApplyFailed condition", err)
+			}
+		})
+	}
+}
+
+func TestApply_PatchRetryStopsWithContext(t *testing.T) {
+	dynamicClient := &conflictingDynamicClient{transient: 5, latest: createMockWorkload().Object}
+	engine := &Engine{dynamicClient: dynamicClient}
+	engine.SetPatchRetry(5, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := engine.Apply(ctx, createMockWorkload(),
+		[]ContainerChange{{Container: "test-container", Recommendation: createMockRecommendation()}}, createMockPolicy(true, false))
+	if !errors.Is(err, context.DeadlineExceeded) || !apierrors.IsServiceUnavailable(err) {
+		t.Errorf("Apply() error = %v, want the transient error and the context error", err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		retry    int
+		minDelay time.Duration
+	}{
+		{retry: 1, minDelay: 100 * time.Millisecond},
+		{retry: 2, minDelay: 200 * time.Millisecond},
+		{retry: 3, minDelay: 400 * time.Millisecond},
+		{retry: 10, minDelay: maxRetryBackoff},
+	}
+	for _, tt := range tests {
+		// Jitter adds up to 100% of the delay
+		if delay := retryDelay(100*time.Millisecond, tt.retry); delay < tt.minDelay || delay > 2*tt.minDelay {
+			t.Errorf("retryDelay(retry %d) = %s, want between %s and %s", tt.retry, delay, tt.minDelay, 2*tt.minDelay)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// withinApplyTolerance reports whether every request of the container the policy optimizes is
+// within updateStrategy.applyTolerance of its current value. A request the container does not
+// have yet is never within tolerance.
+func withinApplyTolerance(
+	currentResources map[string]corev1.ResourceRequirements,
+	containerName string,
+	rec *recommendation.Recommendation,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) bool {
+	tolerance := policy.Spec.UpdateStrategy.ApplyTolerance
+	if tolerance == nil {
+		return false
+	}
+	current := currentResources[containerName]
+
+	within := func(name corev1.ResourceName, recommended resource.Quantity, t *optipodv1alpha1.ResourceTolerance) bool {
+		currentValue, ok := current.Requests[name]
+		return ok && t.Within(currentValue, recommended)
+	}
+	if policy.OptimizesCPU() && !within(corev1.ResourceCPU, rec.CPU, tolerance.CPU) {
+		return false
+	}
+	if policy.OptimizesMemory() && !within(corev1.ResourceMemory, rec.Memory, tolerance.Memory) {
+		return false
+	}
+	return policy.OptimizesCPU() || policy.OptimizesMemory()
+}
+
+// withinToleranceDecision is the skip decision for a container whose recommendation is within
+// the apply tolerance of its current requests
+func withinToleranceDecision(containerName string) *ApplyDecision {
+	return &ApplyDecision{
+		CanApply: false,
+		Method:   Skip,
+		Reason:   fmt.Sprintf("Recommendation within tolerance of the current requests of container %s", containerName),
+		Cause:    ErrWithinTolerance,
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/utils/ptr"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// toleranceTestResources returns the current resources of a container requesting the CPU in
+// millicores and the memory in MiB
+func toleranceTestResources(cpuMillis, memoryMiB int64) map[string]corev1.ResourceRequirements {
+	return map[string]corev1.ResourceRequirements{
+		"test-container": {Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    *resource.NewMilliQuantity(cpuMillis, resource.DecimalSI),
+			corev1.ResourceMemory: *resource.NewQuantity(memoryMiB*1024*1024, resource.BinarySI),
+		}},
+	}
+}
+
+// Feature: apply-tolerance, Property 1: Absolute tolerance boundary
+// For any current CPU request and absolute tolerance, a recommendation at most the tolerance
+// away in either direction is a no-op, and one a millicore further is applied.
+func TestProperty_AbsoluteToleranceBoundary(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("changes up to the absolute tolerance are within tolerance", prop.ForAll(
+		func(currentMillis, toleranceMillis int64, increase bool) bool {
+			policy := newResourceOptimizationPolicy(true, false, true)
+			policy.Spec.UpdateStrategy.ApplyTolerance = &optipodv1alpha1.ApplyTolerance{
+				CPU: &optipodv1alpha1.ResourceTolerance{Absolute: resource.NewMilliQuantity(toleranceMillis, resource.DecimalSI)},
+			}
+			current := toleranceTestResources(currentMillis, 256)
+
+			sign := int64(-1)
+			if increase {
+				sign = 1
+			}
+			at := &recommendation.Recommendation{CPU: *resource.NewMilliQuantity(currentMillis+sign*toleranceMillis, resource.DecimalSI)}
+			beyond := &recommendation.Recommendation{CPU: *resource.NewMilliQuantity(currentMillis+sign*(toleranceMillis+1), resource.DecimalSI)}
+			return withinApplyTolerance(current, "test-container", at, policy) &&
+				!withinApplyTolerance(current, "test-container", beyond, policy)
+		},
+		gen.Int64Range(1000, 8000),
+		gen.Int64Range(0, 500),
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// Feature: apply-tolerance, Property 2: Relative tolerance boundary
+// For any current memory request and percentage, a recommendation at most that percentage of
+// the current request away is a no-op, and one a byte further is applied.
+func TestProperty_RelativeToleranceBoundary(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("changes up to the relative tolerance are within tolerance", prop.ForAll(
+		func(currentMiB int64, percent int32, increase bool) bool {
+			policy := newResourceOptimizationPolicy(false, true, true)
+			policy.Spec.UpdateStrategy.ApplyTolerance = &optipodv1alpha1.ApplyTolerance{
+				Memory: &optipodv1alpha1.ResourceTolerance{Percent: ptr.To(percent)},
+			}
+			current := toleranceTestResources(500, currentMiB)
+
+			currentBytes := currentMiB * 1024 * 1024
+			limit := currentBytes * int64(percent) / 100
+			sign := int64(-1)
+			if increase {
+				sign = 1
+			}
+			at := &recommendation.Recommendation{Memory: *resource.NewQuantity(currentBytes+sign*limit, resource.BinarySI)}
+			beyond := &recommendation.Recommendation{Memory: *resource.NewQuantity(currentBytes+sign*(limit+1), resource.BinarySI)}
+			return withinApplyTolerance(current, "test-container", at, policy) &&
+				!withinApplyTolerance(current, "test-container", beyond, policy)
+		},
+		gen.Int64Range(64, 8192),
+		gen.Int32Range(0, 50),
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestWithinApplyTolerance(t *testing.T) {
+	current := toleranceTestResources(250, 256)
+	tolerance := &optipodv1alpha1.ApplyTolerance{
+		CPU:    &optipodv1alpha1.ResourceTolerance{Absolute: ptr.To(resource.MustParse("10m"))},
+		Memory: &optipodv1alpha1.ResourceTolerance{Percent: ptr.To(int32(5))},
+	}
+
+	tests := []struct {
+		name      string
+		tolerance *optipodv1alpha1.ApplyTolerance
+		cpu       string
+		memory    string
+		want      bool
+	}{
+		{name: "both requests within tolerance", tolerance: tolerance, cpu: "255m", memory: "260Mi", want: true},
+		{name: "CPU beyond tolerance", tolerance: tolerance, cpu: "200m", memory: "256Mi", want: false},
+		{name: "memory beyond tolerance", tolerance: tolerance, cpu: "250m", memory: "300Mi", want: false},
+		{
+			name:      "a resource without a tolerance tolerates no change",
+			tolerance: &optipodv1alpha1.ApplyTolerance{CPU: tolerance.CPU},
+			cpu:       "250m",
+			memory:    "257Mi",
+			want:      false,
+		},
+		{
+			name: "either the absolute or the relative tolerance suffices",
+			tolerance: &optipodv1alpha1.ApplyTolerance{
+				CPU:    &optipodv1alpha1.ResourceTolerance{Absolute: ptr.To(resource.MustParse("1m")), Percent: ptr.To(int32(10))},
+				Memory: tolerance.Memory,
+			},
+			cpu:    "270m",
+			memory: "256Mi",
+			want:   true,
+		},
+		{name: "no tolerance applies every change", cpu: "250m", memory: "256Mi", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createMockPolicy(true, true)
+			policy.Spec.UpdateStrategy.ApplyTolerance = tt.tolerance
+			rec := &recommendation.Recommendation{CPU: resource.MustParse(tt.cpu), Memory: resource.MustParse(tt.memory)}
+
+			if got := withinApplyTolerance(current, "test-container", rec, policy); got != tt.want {
+				t.Errorf("withinApplyTolerance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A request the container does not have yet is never within tolerance
+	policy := createMockPolicy(true, true)
+	policy.Spec.UpdateStrategy.ApplyTolerance = tolerance
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("250m"), Memory: resource.MustParse("256Mi")}
+	if withinApplyTolerance(map[string]corev1.ResourceRequirements{}, "test-container", rec, policy) {
+		t.Error("withinApplyTolerance() = true for a container without requests")
+	}
+}
+
+func TestCanApply_WithinTolerance(t *testing.T) {
+	engine := &Engine{
+		discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}},
+	}
+	workload := createMockWorkloadWithResources(map[string]interface{}{
+		"requests": map[string]interface{}{"cpu": "250m", "memory": "256Mi"},
+	})
+	policy := createMockPolicy(true, true)
+	policy.Spec.UpdateStrategy.ApplyTolerance = &optipodv1alpha1.ApplyTolerance{
+		CPU:    &optipodv1alpha1.ResourceTolerance{Absolute: ptr.To(resource.MustParse("10m"))},
+		Memory: &optipodv1alpha1.ResourceTolerance{Percent: ptr.To(int32(5))},
+	}
+
+	within := &recommendation.Recommendation{CPU: resource.MustParse("245m"), Memory: resource.MustParse("250Mi")}
+	decision, err := engine.CanApply(context.Background(), workload, "test-container", within, policy)
+	if err != nil {
+		t.Fatalf("CanApply() error = %v", err)
+	}
+	if decision.CanApply || !errors.Is(decision.Cause, ErrWithinTolerance) {
+		t.Errorf("CanApply() = %v (%s), want a within tolerance no-op", decision.CanApply, decision.Reason)
+	}
+
+	beyond := &recommendation.Recommendation{CPU: resource.MustParse("300m"), Memory: resource.MustParse("250Mi")}
+	decision, err = engine.CanApply(context.Background(), workload, "test-container", beyond, policy)
+	if err != nil {
+		t.Fatalf("CanApply() error = %v", err)
+	}
+	if !decision.CanApply {
+		t.Errorf("CanApply() = false (%s), want a change beyond tolerance applied", decision.Reason)
+	}
+}
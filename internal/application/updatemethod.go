@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// withUpdateMethodOverride returns the policy with its update strategy overridden by the
+// workload's update method annotation. The policy itself is never modified.
+//
+// The second result is the annotation value when it is not a known update method, in which case
+// the policy is returned unchanged; it is empty otherwise.
+func withUpdateMethodOverride(workload *Workload, policy *optipodv1alpha1.OptimizationPolicy) (*optipodv1alpha1.OptimizationPolicy, string) {
+	if workload == nil || workload.Object == nil {
+		return policy, ""
+	}
+	method, ok := workload.Object.GetAnnotations()[optipodv1alpha1.AnnotationUpdateMethod]
+	if !ok {
+		return policy, ""
+	}
+
+	override := policy.DeepCopy()
+	strategy := &override.Spec.UpdateStrategy
+	switch method {
+	case optipodv1alpha1.UpdateMethodRecreate:
+		strategy.AllowInPlaceResize = false
+		strategy.AllowRecreate = true
+	case optipodv1alpha1.UpdateMethodInPlace:
+		strategy.AllowInPlaceResize = true
+		strategy.AllowRecreate = false
+	case optipodv1alpha1.UpdateMethodRequestsOnly:
+		strategy.UpdateRequestsOnly = true
+		strategy.RemoveLimits = false
+	default:
+		return policy, method
+	}
+	return override, ""
+}
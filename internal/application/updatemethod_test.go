@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/version"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// newUpdateMethodTestWorkload returns a Burstable workload with the update method annotation set
+// to method, or without it when method is empty
+func newUpdateMethodTestWorkload(method string) *Workload {
+	workload := createMockWorkloadWithResources(map[string]interface{}{
+		"requests": map[string]interface{}{"cpu": "250m", "memory": "256Mi"},
+	})
+	if method != "" {
+		workload.Object.SetAnnotations(map[string]string{optipodv1alpha1.AnnotationUpdateMethod: method})
+	}
+	return workload
+}
+
+func TestCanApply_UpdateMethodAnnotation(t *testing.T) {
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("300m"), Memory: resource.MustParse("300Mi")}
+
+	tests := []struct {
+		name          string
+		method        string
+		allowInPlace  bool
+		allowRecreate bool
+		wantCanApply  bool
+		wantMethod    ApplyMethod
+		wantInvalid   string
+	}{
+		{
+			name:          "no annotation follows the policy",
+			allowInPlace:  true,
+			allowRecreate: true,
+			wantCanApply:  true,
+			wantMethod:    InPlace,
+		},
+		{
+			name:          "recreate overrides an in-place preferring policy",
+			method:        optipodv1alpha1.UpdateMethodRecreate,
+			allowInPlace:  true,
+			allowRecreate: false,
+			wantCanApply:  true,
+			wantMethod:    Recreate,
+		},
+		{
+			name:          "in-place overrides a recreate-only policy",
+			method:        optipodv1alpha1.UpdateMethodInPlace,
+			allowInPlace:  false,
+			allowRecreate: true,
+			wantCanApply:  true,
+			wantMethod:    InPlace,
+		},
+		{
+			name:          "requests-only keeps the policy's method",
+			method:        optipodv1alpha1.UpdateMethodRequestsOnly,
+			allowInPlace:  false,
+			allowRecreate: true,
+			wantCanApply:  true,
+			wantMethod:    Recreate,
+		},
+		{
+			name:          "an invalid value falls back to the policy",
+			method:        "rolling",
+			allowInPlace:  false,
+			allowRecreate: true,
+			wantCanApply:  true,
+			wantMethod:    Recreate,
+			wantInvalid:   "rolling",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &Engine{
+				discoveryClient: &mockDiscoveryClient{serverVersion: &version.Info{Major: "1", Minor: "33"}},
+			}
+			policy := createMockPolicy(tt.allowInPlace, tt.allowRecreate)
+
+			decision, err := engine.CanApply(context.Background(), newUpdateMethodTestWorkload(tt.method), "test-container", rec, policy)
+			if err != nil {
+				t.Fatalf("CanApply() error = %v", err)
+			}
+			if decision.CanApply != tt.wantCanApply || decision.Method != tt.wantMethod {
+				t.Errorf("CanApply() = %v with %s (%s), want %v with %s",
+					decision.CanApply, decision.Method, decision.Reason, tt.wantCanApply, tt.wantMethod)
+			}
+			if decision.InvalidUpdateMethod != tt.wantInvalid {
+				t.Errorf("InvalidUpdateMethod = %q, want %q", decision.InvalidUpdateMethod, tt.wantInvalid)
+			}
+		})
+	}
+}
+
+func TestWithUpdateMethodOverride(t *testing.T) {
+	tests := []struct {
+		method                                      string
+		wantInPlace, wantRecreate, wantRequestsOnly bool
+	}{
+		{method: optipodv1alpha1.UpdateMethodRecreate, wantInPlace: false, wantRecreate: true, wantRequestsOnly: false},
+		{method: optipodv1alpha1.UpdateMethodInPlace, wantInPlace: true, wantRecreate: false, wantRequestsOnly: false},
+		{method: optipodv1alpha1.UpdateMethodRequestsOnly, wantInPlace: true, wantRecreate: true, wantRequestsOnly: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			policy := createMockPolicy(true, true)
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = false
+
+			effective, invalid := withUpdateMethodOverride(newUpdateMethodTestWorkload(tt.method), policy)
+			if invalid != "" {
+				t.Fatalf("withUpdateMethodOverride() reported %q as invalid", invalid)
+			}
+			strategy := effective.Spec.UpdateStrategy
+			if strategy.AllowInPlaceResize != tt.wantInPlace || strategy.AllowRecreate != tt.wantRecreate ||
+				strategy.UpdateRequestsOnly != tt.wantRequestsOnly {
+				t.Errorf("strategy = in-place %v, recreate %v, requests-only %v, want %v, %v, %v",
+					strategy.AllowInPlaceResize, strategy.AllowRecreate, strategy.UpdateRequestsOnly,
+					tt.wantInPlace, tt.wantRecreate, tt.wantRequestsOnly)
+			}
+
+			// The override applies to this workload only
+			if !policy.Spec.UpdateStrategy.AllowInPlaceResize || !policy.Spec.UpdateStrategy.AllowRecreate ||
+				policy.Spec.UpdateStrategy.UpdateRequestsOnly {
+				t.Errorf("policy strategy was modified: %+v", policy.Spec.UpdateStrategy)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records every resource change OptiPod makes to an external sink or a changelog
+// ConfigMap per namespace, so there is a durable trail of changes beyond the ephemeral Kubernetes
+// Events.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/optipod/optipod/internal/observability"
+)
+
+// Sink types accepted by NewSink
+const (
+	SinkStdout    = "stdout"
+	SinkHTTP      = "http"
+	SinkConfigMap = "configmap"
+)
+
+// Actions recorded in the audit trail
+const (
+	ActionApply = "Apply"
+)
+
+// Results recorded in the audit trail
+const (
+	ResultSuccess = "Success"
+	ResultFailure = "Failure"
+)
+
+const (
+	// DefaultBufferSize is the number of records buffered before new records are dropped
+	DefaultBufferSize = 1000
+
+	// drainTimeout bounds how long buffered records are flushed to the sink on shutdown
+	drainTimeout = 5 * time.Second
+)
+
+// Resources holds the request values of a container before or after a change. Resources the
+// policy does not optimize are left empty.
+type Resources struct {
+	CPU    string `json:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty"`
+}
+
+// Record is a single resource change made to a workload container
+type Record struct {
+	Timestamp time.Time `json:"timestamp"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+
+	// Policy is the namespace/name of the OptimizationPolicy that made the change
+	Policy string `json:"policy"`
+
+	WorkloadKind      string `json:"workloadKind"`
+	WorkloadNamespace string `json:"workloadNamespace"`
+	WorkloadName      string `json:"workloadName"`
+	Container         string `json:"container"`
+
+	Old Resources `json:"old"`
+	New Resources `json:"new"`
+
+	// Method is the patch method ("ServerSideApply" or "StrategicMergePatch") of a successful change
+	Method string `json:"method,omitempty"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Sink stores audit records
+type Sink interface {
+	Write(ctx context.Context, record Record) error
+}
+
+// SinkOptions configures the sink created by NewSink
+type SinkOptions struct {
+	// URL is where the HTTP sink posts records
+	URL string
+
+	// Client writes the changelog ConfigMaps of the configmap sink
+	Client client.Client
+
+	// Reader reads the changelog ConfigMaps back. It should bypass the manager's cache, as the
+	// operator may not list or watch ConfigMaps.
+	Reader client.Reader
+
+	// MaxEntries is the number of records the configmap sink keeps per namespace
+	MaxEntries int
+}
+
+// NewSink creates the sink of the given type
+func NewSink(sinkType string, opts SinkOptions) (Sink, error) {
+	switch sinkType {
+	case SinkStdout:
+		return NewStdoutSink(), nil
+	case SinkHTTP:
+		if opts.URL == "" {
+			return nil, fmt.Errorf("the %s audit sink requires a URL", SinkHTTP)
+		}
+		return NewHTTPSink(opts.URL), nil
+	case SinkConfigMap:
+		if opts.Client == nil || opts.Reader == nil {
+			return nil, fmt.Errorf("the %s audit sink requires a Kubernetes client and reader", SinkConfigMap)
+		}
+		return NewConfigMapSink(opts.Client, opts.Reader, opts.MaxEntries), nil
+	default:
+		return nil, fmt.Errorf("unknown audit sink %q (supported: %s, %s, %s)", sinkType, SinkStdout, SinkHTTP, SinkConfigMap)
+	}
+}
+
+// Logger hands records to a sink in the background. Recording never blocks: when the buffer is
+// full the record is dropped and counted in the optipod_audit_records_dropped_total metric.
+// It implements manager.Runnable so it can be added to the controller manager.
+type Logger struct {
+	sink    Sink
+	records chan Record
+}
+
+// NewLogger creates a logger writing to sink with room for bufferSize pending records
+func NewLogger(sink Sink, bufferSize int) *Logger {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Logger{
+		sink:    sink,
+		records: make(chan Record, bufferSize),
+	}
+}
+
+// Record queues a record for the sink, stamping it with the current time when unset.
+// A nil logger records nothing.
+func (l *Logger) Record(record Record) {
+	if l == nil {
+		return
+	}
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now().UTC()
+	}
+	select {
+	case l.records <- record:
+	default:
+		observability.AuditRecordsDropped.WithLabelValues("buffer_full").Inc()
+	}
+}
+
+// Start writes queued records to the sink until the context is cancelled, then flushes the
+// records still buffered for a short while
+func (l *Logger) Start(ctx context.Context) error {
+	for {
+		select {
+		case record := <-l.records:
+			l.write(ctx, record)
+		case <-ctx.Done():
+			drainCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), drainTimeout)
+			defer cancel()
+			for {
+				select {
+				case record := <-l.records:
+					l.write(drainCtx, record)
+				default:
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false so records queued on a replica are always written,
+// including while it steps down
+func (l *Logger) NeedLeaderElection() bool {
+	return false
+}
+
+// write hands a record to the sink; failures are logged and counted, never retried
+func (l *Logger) write(ctx context.Context, record Record) {
+	if err := l.sink.Write(ctx, record); err != nil {
+		observability.AuditRecordsDropped.WithLabelValues("sink_error").Inc()
+		logf.FromContext(ctx).Error(err, "Failed to write audit record",
+			"workload", fmt.Sprintf("%s/%s", record.WorkloadNamespace, record.WorkloadName),
+			"container", record.Container)
+	}
+}
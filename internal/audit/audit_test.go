@@ -0,0 +1,208 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/optipod/optipod/internal/observability"
+)
+
+func newTestRecord() Record {
+	return Record{
+		Timestamp:         time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Actor:             "optipod",
+		Action:            ActionApply,
+		Policy:            "optipod-system/web",
+		WorkloadKind:      "Deployment",
+		WorkloadNamespace: "default",
+		WorkloadName:      "web",
+		Container:         "app",
+		Old:               Resources{CPU: "500m", Memory: "512Mi"},
+		New:               Resources{CPU: "250m", Memory: "256Mi"},
+		Method:            "ServerSideApply",
+		Result:            ResultSuccess,
+	}
+}
+
+// recordingSink keeps the records written to it and fails with err when set
+type recordingSink struct {
+	mu      sync.Mutex
+	records []Record
+	err     error
+}
+
+func (s *recordingSink) Write(_ context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *recordingSink) written() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Record(nil), s.records...)
+}
+
+func TestStdoutSink(t *testing.T) {
+	var out bytes.Buffer
+	sink := &StdoutSink{out: &out}
+
+	if err := sink.Write(context.Background(), newTestRecord()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := sink.Write(context.Background(), newTestRecord()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("wrote %d lines, want one JSON line per record:\n%s", len(lines), out.String())
+	}
+	var decoded Record
+	if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil {
+		t.Fatalf("line is not JSON: %v", err)
+	}
+	if decoded != newTestRecord() {
+		t.Errorf("decoded %+v, want %+v", decoded, newTestRecord())
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var received Record
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with content type %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL)
+	if err := sink.Write(context.Background(), newTestRecord()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if received != newTestRecord() {
+		t.Errorf("received %+v, want %+v", received, newTestRecord())
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := sink.Write(context.Background(), newTestRecord()); err == nil {
+		t.Error("Write() error = nil, want an error for a 503 response")
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	if _, err := NewSink(SinkStdout, SinkOptions{}); err != nil {
+		t.Errorf("NewSink(stdout) error = %v", err)
+	}
+	if _, err := NewSink(SinkHTTP, SinkOptions{URL: "http://audit.example.com"}); err != nil {
+		t.Errorf("NewSink(http) error = %v", err)
+	}
+	if _, err := NewSink(SinkHTTP, SinkOptions{}); err == nil {
+		t.Error("NewSink(http) without a URL succeeded")
+	}
+	if _, err := NewSink(SinkConfigMap, SinkOptions{}); err == nil {
+		t.Error("NewSink(configmap) without a client succeeded")
+	}
+	if _, err := NewSink("syslog", SinkOptions{}); err == nil {
+		t.Error("NewSink(syslog) succeeded for an unknown sink")
+	}
+}
+
+func TestLogger_NeverBlocks(t *testing.T) {
+	logger := NewLogger(&recordingSink{}, 1)
+	dropped := testutil.ToFloat64(observability.AuditRecordsDropped.WithLabelValues("buffer_full"))
+
+	// Nothing drains the buffer, so the second record must be dropped rather than block
+	done := make(chan struct{})
+	go func() {
+		logger.Record(newTestRecord())
+		logger.Record(newTestRecord())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Record() blocked on a full buffer")
+	}
+
+	if got := testutil.ToFloat64(observability.AuditRecordsDropped.WithLabelValues("buffer_full")); got != dropped+1 {
+		t.Errorf("buffer_full drops = %v, want %v", got, dropped+1)
+	}
+
+	var nilLogger *Logger
+	nilLogger.Record(newTestRecord())
+}
+
+func TestLogger_WritesAndDrainsOnShutdown(t *testing.T) {
+	sink := &recordingSink{}
+	logger := NewLogger(sink, 10)
+
+	record := newTestRecord()
+	record.Timestamp = time.Time{}
+	logger.Record(record)
+	logger.Record(record)
+
+	// Records queued before shutdown are still written
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := logger.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	written := sink.written()
+	if len(written) != 2 {
+		t.Fatalf("wrote %d records, want 2", len(written))
+	}
+	if written[0].Timestamp.IsZero() {
+		t.Error("record was not timestamped")
+	}
+}
+
+func TestLogger_SinkFailure(t *testing.T) {
+	logger := NewLogger(&recordingSink{err: errors.New("unavailable")}, 10)
+	failed := testutil.ToFloat64(observability.AuditRecordsDropped.WithLabelValues("sink_error"))
+
+	logger.Record(newTestRecord())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = logger.Start(ctx)
+
+	if got := testutil.ToFloat64(observability.AuditRecordsDropped.WithLabelValues("sink_error")); got != failed+1 {
+		t.Errorf("sink_error drops = %v, want %v", got, failed+1)
+	}
+}
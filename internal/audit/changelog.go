@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ChangelogConfigMapName is the ConfigMap the configmap sink keeps in each namespace with changes
+	ChangelogConfigMapName = "optipod-changelog"
+
+	// ChangelogDataKey holds the changelog as JSON lines, oldest first
+	ChangelogDataKey = "changelog.jsonl"
+
+	// DefaultChangelogMaxEntries is the number of records kept per namespace when not configured
+	DefaultChangelogMaxEntries = 100
+
+	// maxChangelogBytes caps the changelog well below the 1MiB ConfigMap limit, whatever the
+	// number of entries
+	maxChangelogBytes = 256 * 1024
+)
+
+// ConfigMapSink keeps a rolling changelog of the records of each namespace in a ConfigMap in that
+// namespace. It needs no infrastructure outside the cluster, and unlike Events the changelog does
+// not expire; the oldest records are dropped once the changelog is full.
+type ConfigMapSink struct {
+	client     client.Client
+	reader     client.Reader
+	maxEntries int
+}
+
+// NewConfigMapSink creates a sink keeping up to maxEntries records per namespace. The changelogs
+// are read through reader, which should bypass the manager's cache.
+func NewConfigMapSink(c client.Client, reader client.Reader, maxEntries int) *ConfigMapSink {
+	if maxEntries <= 0 {
+		maxEntries = DefaultChangelogMaxEntries
+	}
+	return &ConfigMapSink{client: c, reader: reader, maxEntries: maxEntries}
+}
+
+// Write appends the record to the changelog of the workload's namespace, creating the ConfigMap
+// on the first record
+func (s *ConfigMapSink) Write(ctx context.Context, record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	key := client.ObjectKey{Namespace: record.WorkloadNamespace, Name: ChangelogConfigMapName}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap := &corev1.ConfigMap{}
+		err := s.reader.Get(ctx, key, configMap)
+		if apierrors.IsNotFound(err) {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: key.Namespace,
+					Name:      key.Name,
+					Labels: map[string]string{
+						"app.kubernetes.io/managed-by": "optipod",
+					},
+				},
+				Data: map[string]string{ChangelogDataKey: appendChangelog("", string(line), s.maxEntries)},
+			}
+			if err := s.client.Create(ctx, configMap); err != nil {
+				return fmt.Errorf("failed to create changelog ConfigMap %s: %w", key, err)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get changelog ConfigMap %s: %w", key, err)
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data[ChangelogDataKey] = appendChangelog(configMap.Data[ChangelogDataKey], string(line), s.maxEntries)
+		if err := s.client.Update(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to update changelog ConfigMap %s: %w", key, err)
+		}
+		return nil
+	})
+}
+
+// appendChangelog appends a JSON line to the changelog and rotates out the oldest entries beyond
+// maxEntries or maxChangelogBytes. The newest entry is always kept.
+func appendChangelog(changelog, line string, maxEntries int) string {
+	entries := strings.Split(strings.TrimSuffix(changelog, "\n"), "\n")
+	if changelog == "" {
+		entries = nil
+	}
+	entries = append(entries, line)
+
+	if len(entries) > maxEntries {
+		entries = entries[len(entries)-maxEntries:]
+	}
+	size := 0
+	for _, entry := range entries {
+		size += len(entry) + 1
+	}
+	for len(entries) > 1 && size > maxChangelogBytes {
+		size -= len(entries[0]) + 1
+		entries = entries[1:]
+	}
+	return strings.Join(entries, "\n") + "\n"
+}
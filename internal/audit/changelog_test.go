@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// readChangelog returns the records in the changelog ConfigMap of a namespace, oldest first
+func readChangelog(t *testing.T, c client.Client, namespace string) []Record {
+	t.Helper()
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: ChangelogConfigMapName}, configMap); err != nil {
+		t.Fatalf("failed to get changelog: %v", err)
+	}
+	var records []Record
+	for _, line := range strings.Split(strings.TrimSuffix(configMap.Data[ChangelogDataKey], "\n"), "\n") {
+		var record Record
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to decode changelog line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestConfigMapSink_Rotation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	// The operator may not list or watch ConfigMaps, so reading through the cached client fails
+	cachedClient := interceptor.NewClient(fakeClient, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			return apierrors.NewForbidden(corev1.Resource("configmaps"), key.Name, errors.New("cannot list configmaps"))
+		},
+	})
+	sink := NewConfigMapSink(cachedClient, fakeClient, 3)
+
+	// Five changes in default and one in another namespace
+	containers := []string{"c0", "c1", "c2", "c3", "c4"}
+	for _, container := range containers {
+		record := newTestRecord()
+		record.Container = container
+		if err := sink.Write(context.Background(), record); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	other := newTestRecord()
+	other.WorkloadNamespace = "payments"
+	if err := sink.Write(context.Background(), other); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	// Only the three most recent records of default are kept, oldest first
+	records := readChangelog(t, fakeClient, "default")
+	var got []string
+	for _, record := range records {
+		got = append(got, record.Container)
+	}
+	if strings.Join(got, ",") != "c2,c3,c4" {
+		t.Errorf("changelog containers = %v, want c2,c3,c4", got)
+	}
+	if records[0].Old.CPU != "500m" || records[0].New.CPU != "250m" || records[0].Timestamp.IsZero() {
+		t.Errorf("record = %+v, want the before and after values with a timestamp", records[0])
+	}
+
+	// Each namespace has its own changelog
+	if records := readChangelog(t, fakeClient, "payments"); len(records) != 1 {
+		t.Errorf("payments changelog has %d records, want 1", len(records))
+	}
+}
+
+func TestAppendChangelog(t *testing.T) {
+	changelog := appendChangelog("", "a", 2)
+	changelog = appendChangelog(changelog, "b", 2)
+	if changelog != "a\nb\n" {
+		t.Errorf("changelog = %q, want both entries", changelog)
+	}
+	if changelog = appendChangelog(changelog, "c", 2); changelog != "b\nc\n" {
+		t.Errorf("changelog = %q, want the oldest entry rotated out", changelog)
+	}
+
+	// The byte cap rotates entries out before the entry count is reached
+	large := strings.Repeat("x", maxChangelogBytes/2)
+	changelog = appendChangelog("", large, 100)
+	changelog = appendChangelog(changelog, large, 100)
+	changelog = appendChangelog(changelog, "small", 100)
+	if changelog != large+"\nsmall\n" {
+		t.Errorf("changelog has %d bytes, want the oldest large entry rotated out", len(changelog))
+	}
+
+	// The newest entry is kept even when it exceeds the cap on its own
+	huge := strings.Repeat("x", maxChangelogBytes+1)
+	if changelog = appendChangelog(changelog, huge, 100); changelog != huge+"\n" {
+		t.Errorf("changelog has %d bytes, want only the newest entry", len(changelog))
+	}
+}
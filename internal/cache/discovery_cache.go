@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+)
+
+// DiscoveryCache is a discovery client that caches the cluster's feature detection answers, the
+// server version and the resources served per group version, so feature detection does not call
+// the API server on every reconcile. Answers are refreshed once they are older than the refresh
+// interval, which picks up cluster upgrades and CRDs installed or removed later.
+//
+// A group version the server does not serve is cached like any other answer; other errors are
+// not cached, so the next call tries again.
+type DiscoveryCache struct {
+	discovery.DiscoveryInterface
+
+	refreshInterval time.Duration
+
+	mu        sync.Mutex
+	version   *versionCacheEntry
+	resources map[string]*resourcesCacheEntry
+}
+
+type versionCacheEntry struct {
+	info      *version.Info
+	timestamp time.Time
+}
+
+type resourcesCacheEntry struct {
+	resources *metav1.APIResourceList
+	err       error
+	timestamp time.Time
+}
+
+// NewDiscoveryCache creates a cache in front of the discovery client, refreshing answers older
+// than refreshInterval. A zero refresh interval disables caching.
+func NewDiscoveryCache(client discovery.DiscoveryInterface, refreshInterval time.Duration) *DiscoveryCache {
+	return &DiscoveryCache{
+		DiscoveryInterface: client,
+		refreshInterval:    refreshInterval,
+		resources:          make(map[string]*resourcesCacheEntry),
+	}
+}
+
+// ServerVersion returns the cached server version, retrieving it when it is missing or stale
+func (c *DiscoveryCache) ServerVersion() (*version.Info, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.version != nil && c.fresh(c.version.timestamp) {
+		return c.version.info, nil
+	}
+
+	info, err := c.DiscoveryInterface.ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	c.version = &versionCacheEntry{info: info, timestamp: time.Now()}
+	return info, nil
+}
+
+// ServerResourcesForGroupVersion returns the cached resources of the group version, retrieving
+// them when they are missing or stale
+func (c *DiscoveryCache) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.resources[groupVersion]; ok && c.fresh(entry.timestamp) {
+		return entry.resources, entry.err
+	}
+
+	resources, err := c.DiscoveryInterface.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	c.resources[groupVersion] = &resourcesCacheEntry{resources: resources, err: err, timestamp: time.Now()}
+	return resources, err
+}
+
+// Invalidate drops all cached answers, so the next calls retrieve them from the API server
+func (c *DiscoveryCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version = nil
+	c.resources = make(map[string]*resourcesCacheEntry)
+}
+
+// fresh reports whether an answer retrieved at timestamp can still be used
+func (c *DiscoveryCache) fresh(timestamp time.Time) bool {
+	return time.Since(timestamp) < c.refreshInterval
+}
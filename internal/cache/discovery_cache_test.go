@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newFakeDiscovery returns a discovery client serving Kubernetes 1.33 and the KEDA API
+func newFakeDiscovery() *fake.FakeDiscovery {
+	return &fake.FakeDiscovery{
+		Fake: &k8stesting.Fake{
+			Resources: []*metav1.APIResourceList{{
+				GroupVersion: "keda.sh/v1alpha1",
+				APIResources: []metav1.APIResource{{Name: "scaledobjects"}},
+			}},
+		},
+		FakedServerVersion: &version.Info{Major: "1", Minor: "33"},
+	}
+}
+
+func TestDiscoveryCache_CachesAnswers(t *testing.T) {
+	client := newFakeDiscovery()
+	c := NewDiscoveryCache(client, time.Hour)
+
+	for range 3 {
+		info, err := c.ServerVersion()
+		if err != nil || info.Minor != "33" {
+			t.Fatalf("ServerVersion() = %v, %v, want 1.33", info, err)
+		}
+		if _, err := c.ServerResourcesForGroupVersion("keda.sh/v1alpha1"); err != nil {
+			t.Fatalf("ServerResourcesForGroupVersion() error = %v", err)
+		}
+		// A group version that is not served is an answer too
+		if _, err := c.ServerResourcesForGroupVersion("autoscaling.k8s.io/v1"); !apierrors.IsNotFound(err) {
+			t.Fatalf("ServerResourcesForGroupVersion() error = %v, want NotFound", err)
+		}
+	}
+
+	if got := len(client.Actions()); got != 3 {
+		t.Errorf("discovery calls = %d, want one per answer", got)
+	}
+}
+
+func TestDiscoveryCache_Refresh(t *testing.T) {
+	client := newFakeDiscovery()
+	c := NewDiscoveryCache(client, time.Hour)
+
+	if _, err := c.ServerVersion(); err != nil {
+		t.Fatalf("ServerVersion() error = %v", err)
+	}
+
+	// The cluster is upgraded; the cached version is used until it is stale
+	client.FakedServerVersion = &version.Info{Major: "1", Minor: "34"}
+	if info, _ := c.ServerVersion(); info.Minor != "33" {
+		t.Errorf("ServerVersion() = 1.%s before the refresh interval, want the cached 1.33", info.Minor)
+	}
+
+	c.version.timestamp = time.Now().Add(-2 * time.Hour)
+	if info, _ := c.ServerVersion(); info.Minor != "34" {
+		t.Errorf("ServerVersion() = 1.%s after the refresh interval, want 1.34", info.Minor)
+	}
+
+	// Invalidating drops the answers right away
+	client.FakedServerVersion = &version.Info{Major: "1", Minor: "35"}
+	c.Invalidate()
+	if info, _ := c.ServerVersion(); info.Minor != "35" {
+		t.Errorf("ServerVersion() = 1.%s after Invalidate(), want 1.35", info.Minor)
+	}
+
+	// Without a refresh interval every call reaches the API server
+	uncached := NewDiscoveryCache(client, 0)
+	calls := len(client.Actions())
+	_, _ = uncached.ServerVersion()
+	_, _ = uncached.ServerVersion()
+	if got := len(client.Actions()) - calls; got != 2 {
+		t.Errorf("discovery calls without caching = %d, want 2", got)
+	}
+}
+
+func TestDiscoveryCache_ErrorsAreNotCached(t *testing.T) {
+	client := newFakeDiscovery()
+	failing := true
+	client.PrependReactor("get", "version", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if failing {
+			return true, nil, errors.New("connection refused")
+		}
+		return false, nil, nil
+	})
+	c := NewDiscoveryCache(client, time.Hour)
+
+	if _, err := c.ServerVersion(); err == nil {
+		t.Fatal("ServerVersion() error = nil, want the API server error")
+	}
+	failing = false
+	if info, err := c.ServerVersion(); err != nil || info.Minor != "33" {
+		t.Errorf("ServerVersion() = %v, %v after the API server recovered, want 1.33", info, err)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/optipod/optipod/internal/metrics"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// RecommendationCache caches the last recommendation computed for each workload container, so
+// a container whose inputs did not change is not recomputed on every reconcile
+type RecommendationCache struct {
+	mu      sync.RWMutex
+	entries map[string]*recommendationCacheEntry
+	ttl     time.Duration
+}
+
+type recommendationCacheEntry struct {
+	fingerprint    string
+	usage          recommendation.WindowedMetrics
+	recommendation *recommendation.Recommendation
+	timestamp      time.Time
+}
+
+// NewRecommendationCache creates a new recommendation cache with the specified TTL
+func NewRecommendationCache(ttl time.Duration) *RecommendationCache {
+	return &RecommendationCache{
+		entries: make(map[string]*recommendationCacheEntry),
+		ttl:     ttl,
+	}
+}
+
+// Get returns a copy of the recommendation cached for a container if it has not expired, was
+// computed with the same fingerprint (e.g. the same pod template and policy), and every usage
+// statistic of windowed is within cpuPercent or memoryPercent of the one it was computed from
+func (c *RecommendationCache) Get(key, fingerprint string, windowed *recommendation.WindowedMetrics, cpuPercent, memoryPercent int32) (*recommendation.Recommendation, bool) {
+	if windowed == nil || windowed.DailyPeaks != nil {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, exists := c.entries[key]
+	if !exists || entry.fingerprint != fingerprint {
+		return nil, false
+	}
+
+	// Check if entry has expired
+	if time.Since(entry.timestamp) > c.ttl {
+		return nil, false
+	}
+
+	pairs := []struct{ cached, current *metrics.ContainerMetrics }{
+		{entry.usage.Long, windowed.Long},
+		{entry.usage.Short, windowed.Short},
+		{entry.usage.CPUOverride, windowed.CPUOverride},
+		{entry.usage.MemoryOverride, windowed.MemoryOverride},
+	}
+	for _, pair := range pairs {
+		if !usageWithinBand(pair.cached, pair.current, cpuPercent, memoryPercent) {
+			return nil, false
+		}
+	}
+
+	return copyRecommendation(entry.recommendation), true
+}
+
+// Set stores the recommendation computed for a container from windowed. Recommendations sized
+// to daily peaks are not cached, since the days they cover change independently of the usage.
+func (c *RecommendationCache) Set(key, fingerprint string, windowed *recommendation.WindowedMetrics, rec *recommendation.Recommendation) {
+	if windowed == nil || windowed.DailyPeaks != nil || rec == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = &recommendationCacheEntry{
+		fingerprint:    fingerprint,
+		usage:          *windowed,
+		recommendation: copyRecommendation(rec),
+		timestamp:      time.Now(),
+	}
+}
+
+// Invalidate removes the cached recommendation of a container
+func (c *RecommendationCache) Invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+// InvalidateAll clears the entire cache
+func (c *RecommendationCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*recommendationCacheEntry)
+}
+
+// usageWithinBand reports whether both metrics are missing, or every CPU and memory statistic
+// of current is within the band around cached
+func usageWithinBand(cached, current *metrics.ContainerMetrics, cpuPercent, memoryPercent int32) bool {
+	if cached == nil || current == nil {
+		return cached == current
+	}
+	return resourceWithinBand(cached.CPU, current.CPU, cpuPercent) &&
+		resourceWithinBand(cached.Memory, current.Memory, memoryPercent)
+}
+
+// resourceWithinBand reports whether each percentile and the maximum of current is within
+// percent of cached
+func resourceWithinBand(cached, current metrics.ResourceMetrics, percent int32) bool {
+	within := func(cachedValue, currentValue resource.Quantity) bool {
+		diff := currentValue.MilliValue() - cachedValue.MilliValue()
+		if diff < 0 {
+			diff = -diff
+		}
+		return diff*100 <= cachedValue.MilliValue()*int64(percent)
+	}
+	return within(cached.P50, current.P50) && within(cached.P90, current.P90) &&
+		within(cached.P99, current.P99) && within(cached.Max, current.Max)
+}
+
+// copyRecommendation returns a deep copy of rec, so callers adjusting the cached recommendation
+// do not change the cache
+func copyRecommendation(rec *recommendation.Recommendation) *recommendation.Recommendation {
+	copied := *rec
+	copied.CPU = rec.CPU.DeepCopy()
+	copied.Memory = rec.Memory.DeepCopy()
+	copied.ObservedMemoryP99 = rec.ObservedMemoryP99.DeepCopy()
+	copied.MemoryLimit = rec.MemoryLimit.DeepCopy()
+	copied.ClampedToBound = slices.Clone(rec.ClampedToBound)
+	copied.DataQuality = rec.DataQuality.DeepCopy()
+	return &copied
+}
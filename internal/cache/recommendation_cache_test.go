@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/optipod/optipod/internal/metrics"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// newRecommendationCacheUsage returns usage whose statistics are all cpu and memory
+func newRecommendationCacheUsage(cpu, memory string) *recommendation.WindowedMetrics {
+	stats := func(value string) metrics.ResourceMetrics {
+		q := resource.MustParse(value)
+		return metrics.ResourceMetrics{P50: q, P90: q, P99: q, Max: q, Samples: 100}
+	}
+	return &recommendation.WindowedMetrics{Long: &metrics.ContainerMetrics{CPU: stats(cpu), Memory: stats(memory)}}
+}
+
+func TestRecommendationCache(t *testing.T) {
+	const key = "default/Deployment/web/app"
+	rec := &recommendation.Recommendation{
+		CPU:            resource.MustParse("500m"),
+		Memory:         resource.MustParse("512Mi"),
+		ClampedToBound: []string{"cpu-min"},
+	}
+
+	tests := []struct {
+		name        string
+		fingerprint string
+		usage       *recommendation.WindowedMetrics
+		wantHit     bool
+	}{
+		{name: "unchanged usage", fingerprint: "abc", usage: newRecommendationCacheUsage("400m", "400Mi"), wantHit: true},
+		{name: "usage within the band", fingerprint: "abc", usage: newRecommendationCacheUsage("415m", "390Mi"), wantHit: true},
+		{name: "CPU outside the band", fingerprint: "abc", usage: newRecommendationCacheUsage("430m", "400Mi")},
+		{name: "memory outside the band", fingerprint: "abc", usage: newRecommendationCacheUsage("400m", "360Mi")},
+		{name: "changed fingerprint", fingerprint: "def", usage: newRecommendationCacheUsage("400m", "400Mi")},
+		{
+			name:        "short window added",
+			fingerprint: "abc",
+			usage: &recommendation.WindowedMetrics{
+				Long:  newRecommendationCacheUsage("400m", "400Mi").Long,
+				Short: newRecommendationCacheUsage("400m", "400Mi").Long,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewRecommendationCache(time.Minute)
+			c.Set(key, "abc", newRecommendationCacheUsage("400m", "400Mi"), rec)
+
+			cached, hit := c.Get(key, tt.fingerprint, tt.usage, 5, 5)
+			if hit != tt.wantHit {
+				t.Fatalf("Get() hit = %v, want %v", hit, tt.wantHit)
+			}
+			if !hit {
+				return
+			}
+			if cached.CPU.Cmp(rec.CPU) != 0 || cached.Memory.Cmp(rec.Memory) != 0 {
+				t.Errorf("Get() = %s/%s, want %s/%s", cached.CPU.String(), cached.Memory.String(), rec.CPU.String(), rec.Memory.String())
+			}
+
+			// Callers adjust the recommendation they get, which must not change the cache
+			cached.CPU = resource.MustParse("1")
+			cached.ClampedToBound[0] = "changed"
+			again, _ := c.Get(key, tt.fingerprint, tt.usage, 5, 5)
+			if again.CPU.Cmp(rec.CPU) != 0 || again.ClampedToBound[0] != "cpu-min" {
+				t.Errorf("cached recommendation was modified through a returned copy: %+v", again)
+			}
+		})
+	}
+}
+
+func TestRecommendationCache_Expiry(t *testing.T) {
+	const key = "default/Deployment/web/app"
+	usage := newRecommendationCacheUsage("400m", "400Mi")
+	rec := &recommendation.Recommendation{CPU: resource.MustParse("500m"), Memory: resource.MustParse("512Mi")}
+
+	c := NewRecommendationCache(10 * time.Millisecond)
+	c.Set(key, "abc", usage, rec)
+	if _, hit := c.Get(key, "abc", usage, 5, 5); !hit {
+		t.Fatal("Get() missed a fresh entry")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, hit := c.Get(key, "abc", usage, 5, 5); hit {
+		t.Error("Get() hit an expired entry")
+	}
+
+	c.Set(key, "abc", usage, rec)
+	c.Invalidate(key)
+	if _, hit := c.Get(key, "abc", usage, 5, 5); hit {
+		t.Error("Get() hit an invalidated entry")
+	}
+}
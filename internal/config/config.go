@@ -18,20 +18,59 @@ package config
 
 import (
 	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
 	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/optipod/optipod/internal/observability"
 )
 
+// DefaultExcludedNamespaces are the Kubernetes system namespaces that are not optimized unless a
+// policy opts into them. The operator's own namespace is excluded along with them by default.
+const DefaultExcludedNamespaces = "kube-system,kube-public,kube-node-lease"
+
+// DefaultOperatorNamespace is the namespace the operator is assumed to run in when POD_NAMESPACE is
+// not set
+const DefaultOperatorNamespace = "optipod-system"
+
+// OperatorNamespace returns the namespace the operator runs in, as set in the POD_NAMESPACE
+// environment variable through the downward API, or DefaultOperatorNamespace
+func OperatorNamespace() string {
+	if namespace := strings.TrimSpace(os.Getenv("POD_NAMESPACE")); namespace != "" {
+		return namespace
+	}
+	return DefaultOperatorNamespace
+}
+
 // OperatorConfig holds global configuration for the OptiPod operator
 type OperatorConfig struct {
+	// mu guards the fields that can be hot-reloaded from the config ConfigMap
+	mu sync.RWMutex
+
 	// DryRun enables global dry-run mode where recommendations are computed but never applied
 	DryRun bool
 
+	// OptimizationPaused is the cluster-wide emergency brake: while set, every policy is
+	// recommend-only and reports an OptimizationPaused condition
+	OptimizationPaused bool
+
 	// DefaultMetricsProvider specifies the default metrics backend to use
 	DefaultMetricsProvider string
 
 	// PrometheusURL is the URL for the Prometheus server (if using Prometheus provider)
 	PrometheusURL string
 
+	// OTelURL is the Prometheus-compatible query API URL of the backend storing OpenTelemetry
+	// metrics (if using the otel-promql provider)
+	OTelURL string
+
+	// OTelHealthPath is the health endpoint of the OpenTelemetry metrics backend
+	OTelHealthPath string
+
 	// LeaderElection enables leader election for high availability
 	LeaderElection bool
 
@@ -49,20 +88,197 @@ type OperatorConfig struct {
 
 	// MetricsSampleInterval is the interval between samples in seconds (0 = use default)
 	MetricsSampleInterval int
+
+	// MetricsServerMode selects sampled percentiles or the instantaneous reading for the metrics-server provider
+	MetricsServerMode string
+
+	// RestartAwareMemory computes memory percentiles per segment between container restarts
+	RestartAwareMemory bool
+
+	// EventAggregationWindow is the window in which identical Kubernetes Events are aggregated (0 = disabled)
+	EventAggregationWindow time.Duration
+
+	// DefaultMaxWorkloads is the default cap on workloads a policy may modify (0 = unlimited)
+	DefaultMaxWorkloads int
+
+	// DryRunReportInterval is the interval between dry-run impact reports (0 = disabled)
+	DryRunReportInterval time.Duration
+
+	// DryRunReportNamespace is the namespace of the ConfigMap holding the dry-run impact report
+	DryRunReportNamespace string
+
+	// DryRunReportConfigMap is the name of the ConfigMap holding the dry-run impact report (empty = log only)
+	DryRunReportConfigMap string
+
+	// MaxConcurrentReconciles is the number of OptimizationPolicies reconciled in parallel
+	MaxConcurrentReconciles int
+
+	// DiscoveryPageSize is the number of objects per page when listing workloads (0 = list from the cache in one call)
+	DiscoveryPageSize int
+
+	// ExcludedNamespaces is a comma-separated list of namespaces never discovered unless a policy
+	// names them in its namespace allow list (empty = no namespace is excluded)
+	ExcludedNamespaces string
+
+	// MaxCPUIncreasePerInterval caps the CPU requests all policies together may add per
+	// reconciliation interval, as a quantity (empty = unlimited)
+	MaxCPUIncreasePerInterval string
+
+	// MaxMemoryIncreasePerInterval caps the memory requests all policies together may add per
+	// reconciliation interval, as a quantity (empty = unlimited)
+	MaxMemoryIncreasePerInterval string
+
+	// AnnotationTemplates are semicolon-separated key=value Go templates of annotations written with
+	// each recommendation in addition to the canonical optipod.io ones (empty = none)
+	AnnotationTemplates string
+
+	// ReloadConfigMapNamespace is the namespace of the ConfigMap watched for configuration changes
+	ReloadConfigMapNamespace string
+
+	// ReloadConfigMapName is the name of the ConfigMap watched for configuration changes (empty = disabled)
+	ReloadConfigMapName string
+
+	// RecommendationRulesNamespace is the namespace of the ConfigMap holding recommendation recording rules
+	RecommendationRulesNamespace string
+
+	// RecommendationRulesConfigMap is the name of the ConfigMap holding recommendation recording rules (empty = disabled)
+	RecommendationRulesConfigMap string
+
+	// RecommendationRulesInterval is the interval between recording rule ConfigMap writes
+	RecommendationRulesInterval time.Duration
+
+	// ReconcileTimeBudget is how long a reconciliation may process workloads before it checkpoints and requeues (0 = unlimited)
+	ReconcileTimeBudget time.Duration
+
+	// PatchConflictAttempts is how many times a workload patch that fails with a transient error,
+	// such as an API server timeout or a strategic merge patch conflict, is attempted in total
+	// (1 = no retries)
+	PatchConflictAttempts int
+
+	// PatchConflictBackoff is the base delay between attempts of a failed patch, doubled and
+	// jittered on every retry
+	PatchConflictBackoff time.Duration
+
+	// QuarantineFailureThreshold is the number of consecutive reconciliations a workload may fail
+	// in before it is quarantined (0 = disabled)
+	QuarantineFailureThreshold int
+
+	// QuarantineBackoff is how long a workload is first quarantined for, doubled on every further failure
+	QuarantineBackoff time.Duration
+
+	// QuarantineMaxBackoff caps how long a workload is quarantined for
+	QuarantineMaxBackoff time.Duration
+
+	// AuditSink is where an audit record of every applied change is written: stdout, http or
+	// configmap (empty = disabled)
+	AuditSink string
+
+	// AuditHTTPURL is the URL audit records are posted to with the http audit sink
+	AuditHTTPURL string
+
+	// AuditConfigMapMaxEntries is the number of records the configmap audit sink keeps per namespace
+	AuditConfigMapMaxEntries int
+
+	// DashboardAPI serves the read-only dashboard JSON API from the secure metrics server
+	DashboardAPI bool
+
+	// MetricsRecoveryCheckInterval is the interval between metrics backend health checks that reconcile
+	// policies with workloads skipped for missing metrics on recovery (0 = disabled)
+	MetricsRecoveryCheckInterval time.Duration
+
+	// StartupExclusionPeriod is how long after each container start usage samples are left out of
+	// the percentiles recommendations are computed from (0 = disabled)
+	StartupExclusionPeriod time.Duration
+
+	// OutlierTrimFraction is the share of the highest usage samples discarded as outliers before
+	// the percentiles recommendations are computed from (0 = disabled)
+	OutlierTrimFraction float64
+
+	// RecommendationCacheTTL is how long the recommendation computed for a container is reused while
+	// its workload's pod template, policy and usage are unchanged (0 = disabled)
+	RecommendationCacheTTL time.Duration
+
+	// ExportVPARecommendations writes recommendations to the status of VerticalPodAutoscaler objects,
+	// so VPA-aware tooling can read them
+	ExportVPARecommendations bool
+
+	// LogSamplingInitial is how many log lines with the same message and level are written per
+	// second before sampling starts (0 = sampling disabled)
+	LogSamplingInitial int
+
+	// LogSamplingThereafter is the sampling rate once LogSamplingInitial is reached: every
+	// LogSamplingThereafter-th line is written (at least 1)
+	LogSamplingThereafter int
+
+	// FeatureDetectionRefreshInterval is how long cluster feature detection answers, such as the
+	// server version and installed CRDs, are cached before they are retrieved again (0 = not cached)
+	FeatureDetectionRefreshInterval time.Duration
 }
 
 // NewOperatorConfig creates a new OperatorConfig with default values
 func NewOperatorConfig() *OperatorConfig {
+	namespace := OperatorNamespace()
 	return &OperatorConfig{
-		DryRun:                 false,
-		DefaultMetricsProvider: "metrics-server",
-		PrometheusURL:          "http://prometheus:9090",
-		LeaderElection:         false,
-		MetricsAddr:            ":8080",
-		ProbeAddr:              ":8081",
-		ReconciliationInterval: 5 * time.Minute,
-		MetricsMaxSamples:      0, // 0 = use default (10 for production)
-		MetricsSampleInterval:  0, // 0 = use default (15 seconds)
+		DryRun:                  false,
+		OptimizationPaused:      false,
+		DefaultMetricsProvider:  "metrics-server",
+		PrometheusURL:           "http://prometheus:9090",
+		OTelURL:                 "http://otel-query:9090",
+		OTelHealthPath:          "/-/healthy",
+		LeaderElection:          false,
+		MetricsAddr:             ":8080",
+		ProbeAddr:               ":8081",
+		ReconciliationInterval:  5 * time.Minute,
+		MetricsMaxSamples:       0, // 0 = use default (10 for production)
+		MetricsSampleInterval:   0, // 0 = use default (15 seconds)
+		MetricsServerMode:       "sampled",
+		RestartAwareMemory:      false,
+		EventAggregationWindow:  observability.DefaultEventAggregationWindow,
+		DefaultMaxWorkloads:     0, // 0 = unlimited
+		DryRunReportInterval:    5 * time.Minute,
+		DryRunReportNamespace:   namespace,
+		DryRunReportConfigMap:   "optipod-dry-run-report",
+		MaxConcurrentReconciles: 1,
+		DiscoveryPageSize:       0, // 0 = unpaginated cache listing
+		ExcludedNamespaces:      DefaultExcludedNamespaces + "," + namespace,
+		// The increase budget is opt-in
+		MaxCPUIncreasePerInterval:    "",
+		MaxMemoryIncreasePerInterval: "",
+		AnnotationTemplates:          "",
+		// Hot-reload is opt-in
+		ReloadConfigMapNamespace: namespace,
+		ReloadConfigMapName:      "",
+		// Recording rule output is opt-in
+		RecommendationRulesNamespace: namespace,
+		RecommendationRulesConfigMap: "",
+		RecommendationRulesInterval:  5 * time.Minute,
+		ReconcileTimeBudget:          0, // 0 = unlimited
+		PatchConflictAttempts:        3,
+		PatchConflictBackoff:         100 * time.Millisecond,
+		// Persistently failing workloads are retried with backoff instead of every interval
+		QuarantineFailureThreshold: 5,
+		QuarantineBackoff:          10 * time.Minute,
+		QuarantineMaxBackoff:       6 * time.Hour,
+		// The audit trail is opt-in
+		AuditSink:                "",
+		AuditHTTPURL:             "",
+		AuditConfigMapMaxEntries: 100,
+		DashboardAPI:             false,
+		// Policies skipped for missing metrics are reconciled when the backend recovers
+		MetricsRecoveryCheckInterval: 30 * time.Second,
+		// Startup usage counts toward recommendations unless an exclusion period is set
+		StartupExclusionPeriod: 0,
+		// All samples count toward the percentiles unless outlier trimming is set
+		OutlierTrimFraction: 0,
+		// Recommendations are recomputed on every reconcile unless caching is enabled
+		RecommendationCacheTTL: 0,
+		// The VPA export is opt-in
+		ExportVPARecommendations: false,
+		// Log sampling is opt-in
+		LogSamplingInitial:    0,
+		LogSamplingThereafter: 100,
+		// Cluster upgrades and newly installed CRDs are picked up within the refresh interval
+		FeatureDetectionRefreshInterval: 10 * time.Minute,
 	}
 }
 
@@ -70,10 +286,17 @@ func NewOperatorConfig() *OperatorConfig {
 func (c *OperatorConfig) BindFlags() {
 	flag.BoolVar(&c.DryRun, "dry-run", c.DryRun,
 		"Enable global dry-run mode. When enabled, OptiPod computes recommendations but never applies them.")
+	flag.BoolVar(&c.OptimizationPaused, "optimization-paused", c.OptimizationPaused,
+		"Pause optimization cluster-wide: every policy only recommends and reports an OptimizationPaused "+
+			"condition until cleared. Can be changed at runtime through the reload ConfigMap.")
 	flag.StringVar(&c.DefaultMetricsProvider, "metrics-provider", c.DefaultMetricsProvider,
-		"Default metrics provider to use (metrics-server, prometheus, or custom)")
+		"Default metrics provider to use (metrics-server, prometheus, otel-promql, or custom)")
 	flag.StringVar(&c.PrometheusURL, "prometheus-url", c.PrometheusURL,
 		"URL for Prometheus server (used when metrics-provider is prometheus)")
+	flag.StringVar(&c.OTelURL, "otel-url", c.OTelURL,
+		"Prometheus-compatible query API URL of the backend storing OpenTelemetry metrics (used when metrics-provider is otel-promql)")
+	flag.StringVar(&c.OTelHealthPath, "otel-health-path", c.OTelHealthPath,
+		"Health endpoint path of the OpenTelemetry metrics backend")
 	flag.BoolVar(&c.LeaderElection, "leader-elect", c.LeaderElection,
 		"Enable leader election for controller manager")
 	flag.DurationVar(&c.ReconciliationInterval, "reconciliation-interval", c.ReconciliationInterval,
@@ -82,13 +305,118 @@ func (c *OperatorConfig) BindFlags() {
 		"Maximum number of samples to collect for metrics (0 = use default: 10 for production, 3 for tests)")
 	flag.IntVar(&c.MetricsSampleInterval, "metrics-sample-interval", c.MetricsSampleInterval,
 		"Interval between samples in seconds (0 = use default: 15 seconds)")
+	flag.StringVar(&c.MetricsServerMode, "metrics-server-mode", c.MetricsServerMode,
+		"How the metrics-server provider builds percentiles: sampled, or instantaneous (P50=P90=P99=current reading, "+
+			"not recommended for production sizing)")
+	flag.BoolVar(&c.RestartAwareMemory, "restart-aware-memory", c.RestartAwareMemory,
+		"Compute memory percentiles per segment between container restarts and take the highest, "+
+			"so the low usage after a restart does not pull recommendations down")
+	flag.DurationVar(&c.EventAggregationWindow, "event-aggregation-window", c.EventAggregationWindow,
+		"Window in which identical Kubernetes Events are aggregated into a single event with a count (0 = disabled)")
+	flag.IntVar(&c.DefaultMaxWorkloads, "default-max-workloads", c.DefaultMaxWorkloads,
+		"Default cap on workloads a policy may modify before falling back to recommend-only (0 = unlimited)")
+	flag.DurationVar(&c.DryRunReportInterval, "dry-run-report-interval", c.DryRunReportInterval,
+		"Interval between cluster-wide impact reports written in dry-run mode (0 = disabled)")
+	flag.StringVar(&c.DryRunReportNamespace, "dry-run-report-namespace", c.DryRunReportNamespace,
+		"Namespace of the ConfigMap the dry-run impact report is written to")
+	flag.StringVar(&c.DryRunReportConfigMap, "dry-run-report-configmap", c.DryRunReportConfigMap,
+		"Name of the ConfigMap the dry-run impact report is written to (empty = log the report only)")
+	flag.IntVar(&c.MaxConcurrentReconciles, "max-concurrent-reconciles", c.MaxConcurrentReconciles,
+		"Maximum number of OptimizationPolicies reconciled in parallel")
+	flag.IntVar(&c.DiscoveryPageSize, "discovery-page-size", c.DiscoveryPageSize,
+		"Objects per page when listing workloads; pages are read from the API server instead of the cache "+
+			"to bound memory on large clusters (0 = list from the cache in one call)")
+	flag.StringVar(&c.ExcludedNamespaces, "excluded-namespaces", c.ExcludedNamespaces,
+		"Comma-separated namespaces never optimized unless a policy lists them in its namespace allow list "+
+			"(empty = no namespace is excluded)")
+	flag.StringVar(&c.MaxCPUIncreasePerInterval, "max-cpu-increase-per-interval", c.MaxCPUIncreasePerInterval,
+		"CPU requests all policies together may add per reconciliation interval, e.g. 20; further increases "+
+			"are deferred to the next interval (empty = unlimited)")
+	flag.StringVar(&c.MaxMemoryIncreasePerInterval, "max-memory-increase-per-interval", c.MaxMemoryIncreasePerInterval,
+		"Memory requests all policies together may add per reconciliation interval, e.g. 64Gi; further increases "+
+			"are deferred to the next interval (empty = unlimited)")
+	flag.StringVar(&c.AnnotationTemplates, "annotation-templates", c.AnnotationTemplates,
+		"Semicolon-separated key=value Go templates of annotations written with each container recommendation, "+
+			"e.g. 'cost.example.com/{{.Container}}-cpu={{.CPURequest}}' (empty = only the optipod.io annotations)")
+	flag.StringVar(&c.ReloadConfigMapNamespace, "reload-configmap-namespace", c.ReloadConfigMapNamespace,
+		"Namespace of the ConfigMap watched for live configuration changes")
+	flag.StringVar(&c.ReloadConfigMapName, "reload-configmap", c.ReloadConfigMapName,
+		"Name of the ConfigMap watched for live configuration changes (empty = hot-reload disabled)")
+	flag.StringVar(&c.RecommendationRulesNamespace, "recommendation-rules-namespace", c.RecommendationRulesNamespace,
+		"Namespace of the ConfigMap recommendation recording rules are written to")
+	flag.StringVar(&c.RecommendationRulesConfigMap, "recommendation-rules-configmap", c.RecommendationRulesConfigMap,
+		"Name of the ConfigMap recommendations are written to as Prometheus recording rules (empty = disabled)")
+	flag.DurationVar(&c.RecommendationRulesInterval, "recommendation-rules-interval", c.RecommendationRulesInterval,
+		"Interval between writes of the recommendation recording rules ConfigMap")
+	flag.DurationVar(&c.ReconcileTimeBudget, "reconcile-time-budget", c.ReconcileTimeBudget,
+		"How long a reconciliation may process workloads before it checkpoints its progress in the policy status "+
+			"and requeues to continue after the last processed workload (0 = unlimited)")
+	flag.IntVar(&c.PatchConflictAttempts, "patch-conflict-attempts", c.PatchConflictAttempts,
+		"Times a workload patch failing with a transient error (API server timeout, throttling or unavailability, "+
+			"or a strategic merge patch conflict) is attempted, re-reading the workload before each retry (1 = no retries)")
+	flag.DurationVar(&c.PatchConflictBackoff, "patch-conflict-backoff", c.PatchConflictBackoff,
+		"Base delay between attempts of a failed workload patch, doubled and jittered on every retry")
+	flag.IntVar(&c.QuarantineFailureThreshold, "quarantine-failure-threshold", c.QuarantineFailureThreshold,
+		"Consecutive reconciliations a workload may fail in before it is quarantined and skipped until its "+
+			"quarantine expires; the policy changing releases it (0 = disabled)")
+	flag.DurationVar(&c.QuarantineBackoff, "quarantine-backoff", c.QuarantineBackoff,
+		"How long a workload is first quarantined for, doubled on every further failure")
+	flag.DurationVar(&c.QuarantineMaxBackoff, "quarantine-max-backoff", c.QuarantineMaxBackoff,
+		"Longest a workload is quarantined for")
+	flag.StringVar(&c.AuditSink, "audit-sink", c.AuditSink,
+		"Sink for the audit trail of applied changes: stdout (JSON lines), http or configmap (a changelog "+
+			"ConfigMap per namespace) (empty = disabled)")
+	flag.StringVar(&c.AuditHTTPURL, "audit-http-url", c.AuditHTTPURL,
+		"URL audit records are posted to as JSON (used when audit-sink is http)")
+	flag.IntVar(&c.AuditConfigMapMaxEntries, "audit-configmap-max-entries", c.AuditConfigMapMaxEntries,
+		"Number of records kept in the changelog ConfigMap of each namespace before the oldest are rotated out "+
+			"(used when audit-sink is configmap)")
+	flag.BoolVar(&c.DashboardAPI, "dashboard-api", c.DashboardAPI,
+		"Serve a read-only JSON API of policies, recommendations, savings and recent changes under "+
+			"/dashboard/v1/ on the metrics server; requires --metrics-secure")
+	flag.DurationVar(&c.MetricsRecoveryCheckInterval, "metrics-recovery-check-interval", c.MetricsRecoveryCheckInterval,
+		"Interval between metrics backend health checks; when the backend recovers, policies with workloads "+
+			"skipped for missing metrics are reconciled right away (0 = wait for the next scheduled reconcile)")
+	flag.DurationVar(&c.StartupExclusionPeriod, "startup-exclusion-period", c.StartupExclusionPeriod,
+		"Leave usage samples taken within this period after each container start out of recommendations, "+
+			"so startup spikes (e.g. JVM warm-up) do not inflate steady-state requests; Prometheus and "+
+			"otel-promql providers only (0 = disabled)")
+	flag.Float64Var(&c.OutlierTrimFraction, "outlier-trim-fraction", c.OutlierTrimFraction,
+		"Share of the highest usage samples, up to 0.25, discarded as outliers before computing percentiles; "+
+			"legitimate rare peaks are discarded too, which can under-size workloads (0 = disabled)")
+	flag.DurationVar(&c.RecommendationCacheTTL, "recommendation-cache-ttl", c.RecommendationCacheTTL,
+		"Reuse the recommendation computed for a container for up to this long while its workload's pod "+
+			"template, its policy and its usage (within the hysteresis band) are unchanged (0 = disabled)")
+	flag.BoolVar(&c.ExportVPARecommendations, "export-vpa-recommendations", c.ExportVPARecommendations,
+		"Write recommendations to the status of a VerticalPodAutoscaler per workload, in recommendation-only "+
+			"mode, for VPA-aware tooling; ignored until the VPA CRD is installed")
+	flag.IntVar(&c.LogSamplingInitial, "log-sampling-initial", c.LogSamplingInitial,
+		"Log lines with the same message and level written per second before the rest are sampled; "+
+			"warn and error lines are never sampled (0 = sampling disabled)")
+	flag.IntVar(&c.LogSamplingThereafter, "log-sampling-thereafter", c.LogSamplingThereafter,
+		"Once log-sampling-initial is reached, write every Nth line with the same message and level in that second "+
+			"(at least 1)")
+	flag.DurationVar(&c.FeatureDetectionRefreshInterval, "feature-detection-refresh-interval",
+		c.FeatureDetectionRefreshInterval,
+		"How long cluster feature detection answers (server version, in-place resize and pod-level resources "+
+			"support, installed KEDA and VPA CRDs) are cached before they are detected again, so cluster "+
+			"upgrades are picked up (0 = detect on every use)")
 }
 
 // IsDryRun returns true if global dry-run mode is enabled
 func (c *OperatorConfig) IsDryRun() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.DryRun
 }
 
+// IsOptimizationPaused returns true if optimization is paused cluster-wide
+func (c *OperatorConfig) IsOptimizationPaused() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.OptimizationPaused
+}
+
 // GetMetricsProvider returns the configured metrics provider type
 func (c *OperatorConfig) GetMetricsProvider() string {
 	return c.DefaultMetricsProvider
@@ -99,6 +427,16 @@ func (c *OperatorConfig) GetPrometheusURL() string {
 	return c.PrometheusURL
 }
 
+// GetOTelURL returns the OpenTelemetry metrics backend URL
+func (c *OperatorConfig) GetOTelURL() string {
+	return c.OTelURL
+}
+
+// GetOTelHealthPath returns the OpenTelemetry metrics backend health endpoint path
+func (c *OperatorConfig) GetOTelHealthPath() string {
+	return c.OTelHealthPath
+}
+
 // IsLeaderElectionEnabled returns true if leader election is enabled
 func (c *OperatorConfig) IsLeaderElectionEnabled() bool {
 	return c.LeaderElection
@@ -106,6 +444,8 @@ func (c *OperatorConfig) IsLeaderElectionEnabled() bool {
 
 // GetReconciliationInterval returns the default reconciliation interval
 func (c *OperatorConfig) GetReconciliationInterval() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.ReconciliationInterval
 }
 
@@ -118,3 +458,174 @@ func (c *OperatorConfig) GetMetricsMaxSamples() int {
 func (c *OperatorConfig) GetMetricsSampleInterval() int {
 	return c.MetricsSampleInterval
 }
+
+// GetMetricsServerMode returns how the metrics-server provider builds percentiles
+func (c *OperatorConfig) GetMetricsServerMode() string {
+	return c.MetricsServerMode
+}
+
+// IsDashboardAPIEnabled returns true if the dashboard API is served
+func (c *OperatorConfig) IsDashboardAPIEnabled() bool {
+	return c.DashboardAPI
+}
+
+// IsRestartAwareMemoryEnabled returns true if memory percentiles are computed per restart segment
+func (c *OperatorConfig) IsRestartAwareMemoryEnabled() bool {
+	return c.RestartAwareMemory
+}
+
+// GetEventAggregationWindow returns the window in which identical events are aggregated
+func (c *OperatorConfig) GetEventAggregationWindow() time.Duration {
+	return c.EventAggregationWindow
+}
+
+// GetDefaultMaxWorkloads returns the default cap on workloads a policy may modify
+func (c *OperatorConfig) GetDefaultMaxWorkloads() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.DefaultMaxWorkloads
+}
+
+// GetDryRunReportInterval returns the interval between dry-run impact reports
+func (c *OperatorConfig) GetDryRunReportInterval() time.Duration {
+	return c.DryRunReportInterval
+}
+
+// GetDryRunReportTarget returns the namespace and name of the dry-run impact report ConfigMap
+func (c *OperatorConfig) GetDryRunReportTarget() (string, string) {
+	return c.DryRunReportNamespace, c.DryRunReportConfigMap
+}
+
+// GetMaxConcurrentReconciles returns the number of OptimizationPolicies reconciled in parallel
+func (c *OperatorConfig) GetMaxConcurrentReconciles() int {
+	return c.MaxConcurrentReconciles
+}
+
+// GetDiscoveryPageSize returns the number of objects per page when listing workloads
+func (c *OperatorConfig) GetDiscoveryPageSize() int {
+	return c.DiscoveryPageSize
+}
+
+// GetExcludedNamespaces returns the namespaces excluded from discovery unless a policy allows them
+func (c *OperatorConfig) GetExcludedNamespaces() []string {
+	var namespaces []string
+	for _, ns := range strings.Split(c.ExcludedNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// GetIncreaseBudget returns the CPU and memory requests all policies together may add per
+// reconciliation interval; a zero quantity is unlimited
+func (c *OperatorConfig) GetIncreaseBudget() (resource.Quantity, resource.Quantity, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var maxCPU, maxMemory resource.Quantity
+	if c.MaxCPUIncreasePerInterval != "" {
+		q, err := resource.ParseQuantity(c.MaxCPUIncreasePerInterval)
+		if err != nil {
+			return maxCPU, maxMemory, fmt.Errorf("invalid max-cpu-increase-per-interval %q: %w", c.MaxCPUIncreasePerInterval, err)
+		}
+		maxCPU = q
+	}
+	if c.MaxMemoryIncreasePerInterval != "" {
+		q, err := resource.ParseQuantity(c.MaxMemoryIncreasePerInterval)
+		if err != nil {
+			return maxCPU, maxMemory, fmt.Errorf("invalid max-memory-increase-per-interval %q: %w", c.MaxMemoryIncreasePerInterval, err)
+		}
+		maxMemory = q
+	}
+	if maxCPU.Sign() < 0 || maxMemory.Sign() < 0 {
+		return maxCPU, maxMemory, fmt.Errorf("the increase budget must not be negative")
+	}
+	return maxCPU, maxMemory, nil
+}
+
+// GetReloadConfigMap returns the namespace and name of the ConfigMap watched for configuration changes
+func (c *OperatorConfig) GetReloadConfigMap() (string, string) {
+	return c.ReloadConfigMapNamespace, c.ReloadConfigMapName
+}
+
+// GetRecommendationRulesTarget returns the namespace and name of the recommendation recording rules ConfigMap
+func (c *OperatorConfig) GetRecommendationRulesTarget() (string, string) {
+	return c.RecommendationRulesNamespace, c.RecommendationRulesConfigMap
+}
+
+// GetRecommendationRulesInterval returns the interval between recording rule ConfigMap writes
+func (c *OperatorConfig) GetRecommendationRulesInterval() time.Duration {
+	return c.RecommendationRulesInterval
+}
+
+// GetReconcileTimeBudget returns how long a reconciliation may process workloads before it checkpoints
+func (c *OperatorConfig) GetReconcileTimeBudget() time.Duration {
+	return c.ReconcileTimeBudget
+}
+
+// GetPatchConflictRetry returns the number of attempts and the base backoff of workload patches
+// failing with a transient error. Fewer than one attempt is treated as one.
+func (c *OperatorConfig) GetPatchConflictRetry() (int, time.Duration) {
+	return max(c.PatchConflictAttempts, 1), c.PatchConflictBackoff
+}
+
+// GetQuarantine returns the consecutive failure threshold workloads are quarantined at, and the
+// initial and maximum quarantine; quarantine is disabled when the threshold is zero
+func (c *OperatorConfig) GetQuarantine() (int, time.Duration, time.Duration) {
+	return c.QuarantineFailureThreshold, c.QuarantineBackoff, c.QuarantineMaxBackoff
+}
+
+// GetMetricsRecoveryCheckInterval returns the interval between metrics backend health checks
+func (c *OperatorConfig) GetMetricsRecoveryCheckInterval() time.Duration {
+	return c.MetricsRecoveryCheckInterval
+}
+
+// GetStartupExclusionPeriod returns how long after each container start usage samples are
+// left out of recommendations
+func (c *OperatorConfig) GetStartupExclusionPeriod() time.Duration {
+	return c.StartupExclusionPeriod
+}
+
+// GetOutlierTrimFraction returns the share of the highest usage samples discarded as outliers
+func (c *OperatorConfig) GetOutlierTrimFraction() float64 {
+	return c.OutlierTrimFraction
+}
+
+// GetRecommendationCacheTTL returns how long a container's recommendation is reused while its
+// inputs are unchanged
+func (c *OperatorConfig) GetRecommendationCacheTTL() time.Duration {
+	return c.RecommendationCacheTTL
+}
+
+// IsVPAExportEnabled returns true if recommendations are exported to VerticalPodAutoscaler objects
+func (c *OperatorConfig) IsVPAExportEnabled() bool {
+	return c.ExportVPARecommendations
+}
+
+// GetLogSampling returns the log sampling initial count and rate; sampling is disabled when
+// initial is zero. A rate below 1 is rejected, as it would drop every line past the initial ones.
+func (c *OperatorConfig) GetLogSampling() (int, int, error) {
+	if c.LogSamplingInitial < 0 {
+		return 0, 0, fmt.Errorf("log-sampling-initial must not be negative, got %d", c.LogSamplingInitial)
+	}
+	if c.LogSamplingInitial > 0 && c.LogSamplingThereafter < 1 {
+		return 0, 0, fmt.Errorf("log-sampling-thereafter must be at least 1, got %d", c.LogSamplingThereafter)
+	}
+	return c.LogSamplingInitial, c.LogSamplingThereafter, nil
+}
+
+// GetFeatureDetectionRefreshInterval returns how long cluster feature detection answers are cached
+func (c *OperatorConfig) GetFeatureDetectionRefreshInterval() time.Duration {
+	return c.FeatureDetectionRefreshInterval
+}
+
+// GetAuditSink returns the audit sink type and, for the http sink, its URL
+func (c *OperatorConfig) GetAuditSink() (string, string) {
+	return c.AuditSink, c.AuditHTTPURL
+}
+
+// GetAuditConfigMapMaxEntries returns the number of records the changelog ConfigMap of a namespace keeps
+func (c *OperatorConfig) GetAuditConfigMapMaxEntries() int {
+	return c.AuditConfigMapMaxEntries
+}
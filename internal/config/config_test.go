@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestGetExcludedNamespaces(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{
+			name:  "system namespaces",
+			value: DefaultExcludedNamespaces,
+			want:  []string{"kube-system", "kube-public", "kube-node-lease"},
+		},
+		{
+			name:  "whitespace and empty entries are ignored",
+			value: " kube-system, ,monitoring ",
+			want:  []string{"kube-system", "monitoring"},
+		},
+		{
+			name:  "empty excludes nothing",
+			value: "",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewOperatorConfig()
+			c.ExcludedNamespaces = tt.value
+			if got := c.GetExcludedNamespaces(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetExcludedNamespaces() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewOperatorConfig_OperatorNamespace(t *testing.T) {
+	tests := []struct {
+		name         string
+		podNamespace string
+		want         string
+	}{
+		{name: "defaults without POD_NAMESPACE", podNamespace: "", want: DefaultOperatorNamespace},
+		{name: "follows POD_NAMESPACE", podNamespace: "platform-tools", want: "platform-tools"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("POD_NAMESPACE", tt.podNamespace)
+			c := NewOperatorConfig()
+
+			wantExcluded := []string{"kube-system", "kube-public", "kube-node-lease", tt.want}
+			if got := c.GetExcludedNamespaces(); !reflect.DeepEqual(got, wantExcluded) {
+				t.Errorf("GetExcludedNamespaces() = %v, want %v", got, wantExcluded)
+			}
+			for field, got := range map[string]string{
+				"DryRunReportNamespace":        c.DryRunReportNamespace,
+				"ReloadConfigMapNamespace":     c.ReloadConfigMapNamespace,
+				"RecommendationRulesNamespace": c.RecommendationRulesNamespace,
+			} {
+				if got != tt.want {
+					t.Errorf("%s = %q, want %q", field, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetIncreaseBudget(t *testing.T) {
+	c := NewOperatorConfig()
+	cpu, memory, err := c.GetIncreaseBudget()
+	if err != nil || !cpu.IsZero() || !memory.IsZero() {
+		t.Errorf("GetIncreaseBudget() = (%s, %s, %v), want an unlimited budget by default", cpu.String(), memory.String(), err)
+	}
+
+	c.MaxCPUIncreasePerInterval = "20"
+	c.MaxMemoryIncreasePerInterval = "64Gi"
+	cpu, memory, err = c.GetIncreaseBudget()
+	if err != nil || cpu.String() != "20" || memory.String() != "64Gi" {
+		t.Errorf("GetIncreaseBudget() = (%s, %s, %v), want (20, 64Gi)", cpu.String(), memory.String(), err)
+	}
+
+	c.MaxMemoryIncreasePerInterval = "lots"
+	if _, _, err := c.GetIncreaseBudget(); err == nil {
+		t.Error("GetIncreaseBudget() accepted an invalid quantity")
+	}
+}
+
+func TestGetLogSampling(t *testing.T) {
+	defaultCommandLine := flag.CommandLine
+	t.Cleanup(func() { flag.CommandLine = defaultCommandLine })
+	flag.CommandLine = flag.NewFlagSet("optipod", flag.ContinueOnError)
+
+	c := NewOperatorConfig()
+	if initial, _, err := c.GetLogSampling(); err != nil || initial != 0 {
+		t.Errorf("GetLogSampling() initial = %d, error = %v by default, want sampling disabled", initial, err)
+	}
+
+	c.BindFlags()
+	if err := flag.CommandLine.Parse([]string{"--log-sampling-initial=10", "--log-sampling-thereafter=50"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if initial, thereafter, err := c.GetLogSampling(); err != nil || initial != 10 || thereafter != 50 {
+		t.Errorf("GetLogSampling() = %d, %d, %v, want 10, 50", initial, thereafter, err)
+	}
+
+	// A rate of 0 would drop every line past the initial ones
+	c.LogSamplingThereafter = 0
+	if _, _, err := c.GetLogSampling(); err == nil {
+		t.Error("GetLogSampling() accepted log-sampling-thereafter=0")
+	}
+	c.LogSamplingInitial = -1
+	if _, _, err := c.GetLogSampling(); err == nil {
+		t.Error("GetLogSampling() accepted a negative log-sampling-initial")
+	}
+
+	// The rate does not matter while sampling is disabled
+	c.LogSamplingInitial = 0
+	if _, _, err := c.GetLogSampling(); err != nil {
+		t.Errorf("GetLogSampling() error = %v with sampling disabled", err)
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DefaultReloadPollInterval is how often the watched ConfigMap is checked for changes
+const DefaultReloadPollInterval = 30 * time.Second
+
+// ConfigMap keys that can be changed without restarting the operator
+const (
+	KeyDryRun                 = "dry-run"
+	KeyOptimizationPaused     = "optimization-paused"
+	KeyReconciliationInterval = "reconciliation-interval"
+	KeyDefaultMaxWorkloads    = "default-max-workloads"
+	KeyMaxCPUIncrease         = "max-cpu-increase-per-interval"
+	KeyMaxMemoryIncrease      = "max-memory-increase-per-interval"
+)
+
+// ErrRestartRequired is reported for ConfigMap keys that are only read at startup
+var ErrRestartRequired = errors.New("cannot be changed without restarting the operator")
+
+// ReloadResult describes the outcome of applying ConfigMap data to the live configuration
+type ReloadResult struct {
+	// Changed lists the keys whose values were updated
+	Changed []string
+
+	// Ignored lists the keys that cannot be changed at runtime and were left untouched
+	Ignored []string
+
+	// Errors holds an error wrapping ErrRestartRequired for each ignored key
+	Errors []error
+}
+
+// ApplyConfigMapData updates the hot-reloadable fields from ConfigMap data.
+// All values are validated before any field is changed, so an invalid ConfigMap
+// leaves the live configuration untouched.
+func (c *OperatorConfig) ApplyConfigMapData(data map[string]string) (*ReloadResult, error) {
+	result := &ReloadResult{}
+
+	c.mu.RLock()
+	dryRun := c.DryRun
+	paused := c.OptimizationPaused
+	interval := c.ReconciliationInterval
+	maxWorkloads := c.DefaultMaxWorkloads
+	maxCPUIncrease := c.MaxCPUIncreasePerInterval
+	maxMemoryIncrease := c.MaxMemoryIncreasePerInterval
+	c.mu.RUnlock()
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := data[key]
+		switch key {
+		case KeyDryRun:
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+			}
+			if parsed != dryRun {
+				dryRun = parsed
+				result.Changed = append(result.Changed, key)
+			}
+		case KeyOptimizationPaused:
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+			}
+			if parsed != paused {
+				paused = parsed
+				result.Changed = append(result.Changed, key)
+			}
+		case KeyReconciliationInterval:
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+			}
+			if parsed <= 0 {
+				return nil, fmt.Errorf("invalid %s value %q: must be positive", key, value)
+			}
+			if parsed != interval {
+				interval = parsed
+				result.Changed = append(result.Changed, key)
+			}
+		case KeyDefaultMaxWorkloads:
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+			}
+			if parsed < 0 {
+				return nil, fmt.Errorf("invalid %s value %q: must not be negative", key, value)
+			}
+			if parsed != maxWorkloads {
+				maxWorkloads = parsed
+				result.Changed = append(result.Changed, key)
+			}
+		case KeyMaxCPUIncrease, KeyMaxMemoryIncrease:
+			if value != "" {
+				parsed, err := resource.ParseQuantity(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+				}
+				if parsed.Sign() < 0 {
+					return nil, fmt.Errorf("invalid %s value %q: must not be negative", key, value)
+				}
+			}
+			current := &maxCPUIncrease
+			if key == KeyMaxMemoryIncrease {
+				current = &maxMemoryIncrease
+			}
+			if value != *current {
+				*current = value
+				result.Changed = append(result.Changed, key)
+			}
+		default:
+			// Everything else (leader election, provider, bind addresses) is only read at startup
+			result.Ignored = append(result.Ignored, key)
+			result.Errors = append(result.Errors, fmt.Errorf("%s %w", key, ErrRestartRequired))
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.DryRun = dryRun
+	c.OptimizationPaused = paused
+	c.ReconciliationInterval = interval
+	c.DefaultMaxWorkloads = maxWorkloads
+	c.MaxCPUIncreasePerInterval = maxCPUIncrease
+	c.MaxMemoryIncreasePerInterval = maxMemoryIncrease
+
+	return result, nil
+}
+
+// Reloader watches a ConfigMap and applies its changes to the live OperatorConfig.
+// It implements manager.Runnable so it can be added to the controller manager.
+type Reloader struct {
+	config       *OperatorConfig
+	reader       client.Reader
+	namespace    string
+	name         string
+	pollInterval time.Duration
+
+	lastResourceVersion string
+	onReload            []func(*OperatorConfig)
+}
+
+// NewReloader creates a reloader for the given ConfigMap. The reader should not be
+// cache-backed so that only the single ConfigMap is read.
+func NewReloader(config *OperatorConfig, reader client.Reader, namespace, name string) *Reloader {
+	return &Reloader{
+		config:       config,
+		reader:       reader,
+		namespace:    namespace,
+		name:         name,
+		pollInterval: DefaultReloadPollInterval,
+	}
+}
+
+// OnReload registers a callback invoked after a ConfigMap change has been applied
+func (r *Reloader) OnReload(fn func(*OperatorConfig)) {
+	r.onReload = append(r.onReload, fn)
+}
+
+// Start polls the ConfigMap until the context is cancelled
+func (r *Reloader) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Reload(ctx); err != nil {
+			log.Error(err, "Failed to reload operator configuration",
+				"configMap", r.namespace+"/"+r.name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns false so every replica keeps its configuration current
+func (r *Reloader) NeedLeaderElection() bool {
+	return false
+}
+
+// Reload reads the ConfigMap and applies it if it changed since the last reload
+func (r *Reloader) Reload(ctx context.Context) error {
+	log := logf.FromContext(ctx)
+
+	configMap := &corev1.ConfigMap{}
+	err := r.reader.Get(ctx, client.ObjectKey{Namespace: r.namespace, Name: r.name}, configMap)
+	if apierrors.IsNotFound(err) {
+		// Nothing to reload; keep the current configuration
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get config ConfigMap: %w", err)
+	}
+
+	if configMap.ResourceVersion != "" && configMap.ResourceVersion == r.lastResourceVersion {
+		return nil
+	}
+
+	result, err := r.config.ApplyConfigMapData(configMap.Data)
+	if err != nil {
+		return err
+	}
+	r.lastResourceVersion = configMap.ResourceVersion
+
+	if len(result.Errors) > 0 {
+		log.Error(errors.Join(result.Errors...), "Ignoring configuration keys that require a restart to change",
+			"configMap", r.namespace+"/"+r.name, "keys", result.Ignored)
+	}
+
+	if len(result.Changed) == 0 {
+		return nil
+	}
+
+	maxCPUIncrease, maxMemoryIncrease, _ := r.config.GetIncreaseBudget()
+	log.Info("Reloaded operator configuration",
+		"configMap", r.namespace+"/"+r.name,
+		"changed", result.Changed,
+		"dry-run", r.config.IsDryRun(),
+		"optimization-paused", r.config.IsOptimizationPaused(),
+		"reconciliation-interval", r.config.GetReconciliationInterval(),
+		"default-max-workloads", r.config.GetDefaultMaxWorkloads(),
+		"max-cpu-increase-per-interval", maxCPUIncrease.String(),
+		"max-memory-increase-per-interval", maxMemoryIncrease.String())
+
+	for _, fn := range r.onReload {
+		fn(r.config)
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestApplyConfigMapData(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         map[string]string
+		wantErr      bool
+		wantChanged  []string
+		wantIgnored  []string
+		wantCPU      string
+		wantDryRun   bool
+		wantInterval time.Duration
+		wantMax      int
+		wantPaused   bool
+	}{
+		{
+			name: "updates reloadable fields",
+			data: map[string]string{
+				KeyDryRun:                 "true",
+				KeyReconciliationInterval: "10m",
+				KeyDefaultMaxWorkloads:    "25",
+			},
+			wantChanged:  []string{KeyDefaultMaxWorkloads, KeyDryRun, KeyReconciliationInterval},
+			wantDryRun:   true,
+			wantInterval: 10 * time.Minute,
+			wantMax:      25,
+		},
+		{
+			name: "ignores immutable fields",
+			data: map[string]string{
+				"leader-election":  "false",
+				"metrics-provider": "prometheus",
+				KeyDryRun:          "false",
+			},
+			wantIgnored:  []string{"leader-election", "metrics-provider"},
+			wantDryRun:   false,
+			wantInterval: 5 * time.Minute,
+		},
+		{
+			name: "rejects invalid values without partial updates",
+			data: map[string]string{
+				KeyDryRun:                 "true",
+				KeyReconciliationInterval: "soon",
+			},
+			wantErr:      true,
+			wantDryRun:   false,
+			wantInterval: 5 * time.Minute,
+		},
+		{
+			name: "updates the increase budget",
+			data: map[string]string{
+				KeyMaxCPUIncrease:    "8",
+				KeyMaxMemoryIncrease: "",
+			},
+			wantChanged:  []string{KeyMaxCPUIncrease},
+			wantCPU:      "8",
+			wantInterval: 5 * time.Minute,
+		},
+		{
+			name:         "rejects an invalid increase budget",
+			data:         map[string]string{KeyMaxMemoryIncrease: "lots"},
+			wantErr:      true,
+			wantInterval: 5 * time.Minute,
+		},
+		{
+			name:         "rejects a negative increase budget",
+			data:         map[string]string{KeyMaxCPUIncrease: "-1"},
+			wantErr:      true,
+			wantInterval: 5 * time.Minute,
+		},
+		{
+			name:         "pauses optimization",
+			data:         map[string]string{KeyOptimizationPaused: "true"},
+			wantChanged:  []string{KeyOptimizationPaused},
+			wantInterval: 5 * time.Minute,
+			wantPaused:   true,
+		},
+		{
+			name:         "rejects an invalid pause value",
+			data:         map[string]string{KeyOptimizationPaused: "yes please"},
+			wantErr:      true,
+			wantInterval: 5 * time.Minute,
+		},
+		{
+			name:         "rejects non-positive interval",
+			data:         map[string]string{KeyReconciliationInterval: "0s"},
+			wantErr:      true,
+			wantInterval: 5 * time.Minute,
+		},
+		{
+			name:         "rejects negative max workloads",
+			data:         map[string]string{KeyDefaultMaxWorkloads: "-1"},
+			wantErr:      true,
+			wantInterval: 5 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewOperatorConfig()
+
+			result, err := cfg.ApplyConfigMapData(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyConfigMapData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if !reflect.DeepEqual(result.Changed, tt.wantChanged) {
+					t.Errorf("Changed = %v, want %v", result.Changed, tt.wantChanged)
+				}
+				if !reflect.DeepEqual(result.Ignored, tt.wantIgnored) {
+					t.Errorf("Ignored = %v, want %v", result.Ignored, tt.wantIgnored)
+				}
+				if len(result.Errors) != len(tt.wantIgnored) {
+					t.Errorf("Errors = %v, want one per ignored key", result.Errors)
+				}
+				for _, err := range result.Errors {
+					if !errors.Is(err, ErrRestartRequired) {
+						t.Errorf("error %v does not wrap ErrRestartRequired", err)
+					}
+				}
+			}
+
+			if cfg.IsDryRun() != tt.wantDryRun {
+				t.Errorf("IsDryRun() = %v, want %v", cfg.IsDryRun(), tt.wantDryRun)
+			}
+			if cfg.GetReconciliationInterval() != tt.wantInterval {
+				t.Errorf("GetReconciliationInterval() = %v, want %v", cfg.GetReconciliationInterval(), tt.wantInterval)
+			}
+			if cfg.GetDefaultMaxWorkloads() != tt.wantMax {
+				t.Errorf("GetDefaultMaxWorkloads() = %v, want %v", cfg.GetDefaultMaxWorkloads(), tt.wantMax)
+			}
+			maxCPU, _, err := cfg.GetIncreaseBudget()
+			if err != nil {
+				t.Fatalf("GetIncreaseBudget() error = %v", err)
+			}
+			if got, want := maxCPU.String(), cmp.Or(tt.wantCPU, "0"); got != want {
+				t.Errorf("CPU increase budget = %s, want %s", got, want)
+			}
+			if cfg.IsOptimizationPaused() != tt.wantPaused {
+				t.Errorf("IsOptimizationPaused() = %v, want %v", cfg.IsOptimizationPaused(), tt.wantPaused)
+			}
+		})
+	}
+}
+
+func TestReloader_Reload(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "optipod-config", Namespace: "optipod-system"},
+		Data:       map[string]string{KeyDryRun: "true"},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+
+	cfg := NewOperatorConfig()
+	reloader := NewReloader(cfg, k8sClient, "optipod-system", "optipod-config")
+
+	reloads := 0
+	reloader.OnReload(func(c *OperatorConfig) {
+		reloads++
+		if !c.IsDryRun() {
+			t.Error("expected callback to observe the reloaded dry-run value")
+		}
+	})
+
+	ctx := context.Background()
+	if err := reloader.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if !cfg.IsDryRun() {
+		t.Error("expected dry-run to be enabled after reload")
+	}
+	if reloads != 1 {
+		t.Errorf("expected 1 reload callback, got %d", reloads)
+	}
+
+	// An unchanged ConfigMap does not trigger callbacks
+	if err := reloader.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if reloads != 1 {
+		t.Errorf("expected no callback for unchanged ConfigMap, got %d", reloads)
+	}
+
+	// An invalid update leaves the live configuration untouched
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap); err != nil {
+		t.Fatalf("failed to get ConfigMap: %v", err)
+	}
+	configMap.Data = map[string]string{KeyDryRun: "maybe"}
+	if err := k8sClient.Update(ctx, configMap); err != nil {
+		t.Fatalf("failed to update ConfigMap: %v", err)
+	}
+	if err := reloader.Reload(ctx); err == nil {
+		t.Error("expected an error for an invalid dry-run value")
+	}
+	if !cfg.IsDryRun() {
+		t.Error("expected dry-run to remain enabled after an invalid reload")
+	}
+}
+
+func TestReloader_MissingConfigMap(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	cfg := NewOperatorConfig()
+	reloader := NewReloader(cfg, k8sClient, "optipod-system", "missing")
+
+	if err := reloader.Reload(context.Background()); err != nil {
+		t.Errorf("expected a missing ConfigMap to be ignored, got %v", err)
+	}
+	if cfg.IsDryRun() {
+		t.Error("expected configuration to be unchanged")
+	}
+}
+
+func TestApplyConfigMapData_ConcurrentReads(t *testing.T) {
+	cfg := NewOperatorConfig()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, _ = cfg.ApplyConfigMapData(map[string]string{KeyDryRun: "true"})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = cfg.IsDryRun()
+			_ = cfg.GetReconciliationInterval()
+			_ = cfg.GetDefaultMaxWorkloads()
+		}()
+	}
+	wg.Wait()
+
+	if !cfg.IsDryRun() {
+		t.Error("expected dry-run to be enabled")
+	}
+}
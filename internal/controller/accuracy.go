@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/observability"
+)
+
+// overProvisionedUtilizationPercent is the utilization below which applied requests are flagged
+// as over-provisioned, when every measured resource is below it
+const overProvisionedUtilizationPercent = 50
+
+// underProvisionedUtilizationPercent is the utilization above which an applied request is
+// flagged as under-provisioned
+const underProvisionedUtilizationPercent = 100
+
+// recommendationAccuracy compares the usage a recommendation was computed from with the
+// container's requests. It returns nil until a full rolling window has passed since the
+// workload's last applied change, since the usage would still include samples taken with the
+// requests before it, and for workloads never changed.
+func recommendationAccuracy(
+	annotations map[string]string,
+	dataQuality *optipodv1alpha1.RecommendationDataQuality,
+	requests corev1.ResourceList,
+	window time.Duration,
+	now time.Time,
+) *optipodv1alpha1.RecommendationAccuracy {
+	if dataQuality == nil {
+		return nil
+	}
+	appliedAt, err := time.Parse(time.RFC3339, annotations[optipodv1alpha1.AnnotationLastApplied])
+	if err != nil || now.Sub(appliedAt) < window {
+		return nil
+	}
+
+	accuracy := &optipodv1alpha1.RecommendationAccuracy{
+		AppliedAt:                metav1.NewTime(appliedAt),
+		CPUUtilizationPercent:    utilizationPercent(dataQuality.CPU, requests.Cpu()),
+		MemoryUtilizationPercent: utilizationPercent(dataQuality.Memory, requests.Memory()),
+		Verdict:                  optipodv1alpha1.AccuracyAccurate,
+	}
+	var measured []int32
+	for _, percent := range []*int32{accuracy.CPUUtilizationPercent, accuracy.MemoryUtilizationPercent} {
+		if percent != nil {
+			measured = append(measured, *percent)
+		}
+	}
+	if len(measured) == 0 {
+		return nil
+	}
+
+	overProvisioned := true
+	for _, percent := range measured {
+		if percent > underProvisionedUtilizationPercent {
+			accuracy.Verdict = optipodv1alpha1.AccuracyUnderProvisioned
+			return accuracy
+		}
+		overProvisioned = overProvisioned && percent < overProvisionedUtilizationPercent
+	}
+	if overProvisioned {
+		accuracy.Verdict = optipodv1alpha1.AccuracyOverProvisioned
+	}
+	return accuracy
+}
+
+// utilizationPercent returns the usage at the recommendation's percentile as a percentage of the
+// request, or nil when either is unknown
+func utilizationPercent(usage *optipodv1alpha1.ResourceDataQuality, request *resource.Quantity) *int32 {
+	if usage == nil || usage.PercentileValue == nil || request.IsZero() {
+		return nil
+	}
+	ratio := float64(usage.PercentileValue.MilliValue()) / float64(request.MilliValue())
+	percent := int32(min(math.Round(100*ratio), math.MaxInt32))
+	return &percent
+}
+
+// recordAccuracy exports the utilization of the container's applied requests
+func recordAccuracy(
+	policy *optipodv1alpha1.OptimizationPolicy,
+	workload *discovery.Workload,
+	container string,
+	accuracy *optipodv1alpha1.RecommendationAccuracy,
+) {
+	for resourceName, percent := range map[corev1.ResourceName]*int32{
+		corev1.ResourceCPU:    accuracy.CPUUtilizationPercent,
+		corev1.ResourceMemory: accuracy.MemoryUtilizationPercent,
+	} {
+		if percent == nil {
+			continue
+		}
+		observability.RecommendationUtilization.
+			WithLabelValues(policy.Name, workload.Namespace, workload.Name, workload.Kind, container, string(resourceName)).
+			Set(float64(*percent) / 100)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/observability"
+)
+
+func TestRecommendationAccuracy(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	appliedTwoDaysAgo := map[string]string{
+		optipodv1alpha1.AnnotationLastApplied: now.Add(-48 * time.Hour).Format(time.RFC3339),
+	}
+	usage := func(cpu, memory string) *optipodv1alpha1.RecommendationDataQuality {
+		cpuValue, memoryValue := resource.MustParse(cpu), resource.MustParse(memory)
+		return &optipodv1alpha1.RecommendationDataQuality{
+			CPU:    &optipodv1alpha1.ResourceDataQuality{PercentileValue: &cpuValue},
+			Memory: &optipodv1alpha1.ResourceDataQuality{PercentileValue: &memoryValue},
+		}
+	}
+	requests := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("200m"),
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		usage       *optipodv1alpha1.RecommendationDataQuality
+		requests    corev1.ResourceList
+		wantNil     bool
+		wantCPU     int32
+		wantMemory  int32
+		wantVerdict string
+	}{
+		{
+			name:     "never applied",
+			usage:    usage("150m", "200Mi"),
+			requests: requests,
+			wantNil:  true,
+		},
+		{
+			name: "window still includes usage from before the change",
+			annotations: map[string]string{
+				optipodv1alpha1.AnnotationLastApplied: now.Add(-12 * time.Hour).Format(time.RFC3339),
+			},
+			usage:    usage("150m", "200Mi"),
+			requests: requests,
+			wantNil:  true,
+		},
+		{
+			name:        "usage fits the applied requests",
+			annotations: appliedTwoDaysAgo,
+			usage:       usage("160m", "192Mi"),
+			requests:    requests,
+			wantCPU:     80,
+			wantMemory:  75,
+			wantVerdict: optipodv1alpha1.AccuracyAccurate,
+		},
+		{
+			name:        "usage well below every request is over-provisioned",
+			annotations: appliedTwoDaysAgo,
+			usage:       usage("50m", "64Mi"),
+			requests:    requests,
+			wantCPU:     25,
+			wantMemory:  25,
+			wantVerdict: optipodv1alpha1.AccuracyOverProvisioned,
+		},
+		{
+			name:        "one resource below half is not over-provisioned",
+			annotations: appliedTwoDaysAgo,
+			usage:       usage("50m", "192Mi"),
+			requests:    requests,
+			wantCPU:     25,
+			wantMemory:  75,
+			wantVerdict: optipodv1alpha1.AccuracyAccurate,
+		},
+		{
+			name:        "usage above a request is under-provisioned",
+			annotations: appliedTwoDaysAgo,
+			usage:       usage("50m", "384Mi"),
+			requests:    requests,
+			wantCPU:     25,
+			wantMemory:  150,
+			wantVerdict: optipodv1alpha1.AccuracyUnderProvisioned,
+		},
+		{
+			name:        "resources without a request are not measured",
+			annotations: appliedTwoDaysAgo,
+			usage:       usage("300m", "64Mi"),
+			requests:    corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			wantMemory:  25,
+			wantVerdict: optipodv1alpha1.AccuracyOverProvisioned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accuracy := recommendationAccuracy(tt.annotations, tt.usage, tt.requests, 24*time.Hour, now)
+			if tt.wantNil {
+				if accuracy != nil {
+					t.Errorf("recommendationAccuracy() = %+v, want nil", accuracy)
+				}
+				return
+			}
+			if accuracy == nil {
+				t.Fatal("recommendationAccuracy() = nil, want a measurement")
+			}
+			if got := ptrValue(accuracy.CPUUtilizationPercent); got != tt.wantCPU {
+				t.Errorf("CPU utilization = %d%%, want %d%%", got, tt.wantCPU)
+			}
+			if got := ptrValue(accuracy.MemoryUtilizationPercent); got != tt.wantMemory {
+				t.Errorf("memory utilization = %d%%, want %d%%", got, tt.wantMemory)
+			}
+			if accuracy.Verdict != tt.wantVerdict {
+				t.Errorf("verdict = %s, want %s", accuracy.Verdict, tt.wantVerdict)
+			}
+			if !accuracy.AppliedAt.Time.Equal(now.Add(-48 * time.Hour)) {
+				t.Errorf("appliedAt = %v, want the last-applied annotation", accuracy.AppliedAt)
+			}
+		})
+	}
+}
+
+// ptrValue returns the value of p, or zero when it is nil
+func ptrValue(p *int32) int32 {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+func TestProcessWorkload_RecommendationAccuracy(t *testing.T) {
+	workload := createTestWorkload(TestContainerName)
+	deployment := workload.Object.(*appsv1.Deployment)
+	deployment.Annotations = map[string]string{
+		optipodv1alpha1.AnnotationLastApplied: time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339),
+	}
+	deployment.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("100m"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+
+	processor := createTestProcessor(&recordingApplicationEngine{}, nil)
+	status, err := processor.ProcessWorkload(context.Background(), workload, createTestPolicy(optipodv1alpha1.ModeRecommend))
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	// The P90 usage of 200m CPU and 256Mi memory against requests of 100m and 1Gi
+	if len(status.Recommendations) != 1 {
+		t.Fatalf("got %d recommendations, want 1", len(status.Recommendations))
+	}
+	accuracy := status.Recommendations[0].Accuracy
+	if accuracy == nil || ptrValue(accuracy.CPUUtilizationPercent) != 200 || ptrValue(accuracy.MemoryUtilizationPercent) != 25 ||
+		accuracy.Verdict != optipodv1alpha1.AccuracyUnderProvisioned {
+		t.Fatalf("accuracy = %+v, want 200%% CPU and 25%% memory utilization, under-provisioned", accuracy)
+	}
+
+	gauge := observability.RecommendationUtilization.WithLabelValues("test-policy", TestNamespace, TestWorkloadName,
+		KindDeployment, TestContainerName, string(corev1.ResourceCPU))
+	if got := testutil.ToFloat64(gauge); got != 2 {
+		t.Errorf("optipod_recommendation_utilization_ratio = %v, want 2", got)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// nextAdaptiveInterval returns the interval to the next reconciliation of a policy with an
+// adaptiveInterval, or zero for policies without one. A reconciliation in which no workload
+// was changed or failed and every scored workload reached the stable score doubles the
+// previous interval up to the maximum; any other reconciliation returns to the minimum, as does
+// the first reconciliation of a changed policy, whose stability is not known yet.
+func (r *OptimizationPolicyReconciler) nextAdaptiveInterval(pol *optipodv1alpha1.OptimizationPolicy, summary *reconcileSummary) time.Duration {
+	adaptive := pol.Spec.AdaptiveInterval
+	if adaptive == nil || pol.Spec.Mode == optipodv1alpha1.ModeDisabled {
+		return 0
+	}
+	minInterval, maxInterval := adaptive.Bounds(r.baseReconciliationInterval(pol))
+
+	if !summary.stable(adaptive.GetStableScore()) || pol.Status.AdaptiveInterval == nil ||
+		pol.Generation != pol.Status.ObservedGeneration {
+		return minInterval
+	}
+	next := 2 * pol.Status.AdaptiveInterval.Duration
+	if next < minInterval {
+		return minInterval
+	}
+	if next > maxInterval {
+		return maxInterval
+	}
+	return next
+}
+
+// stable reports whether nothing was changed or failed in the reconciliation and no scored
+// workload is below stableScore
+func (s *reconcileSummary) stable(stableScore int32) bool {
+	if s.Applied > 0 || s.Failed > 0 {
+		return false
+	}
+	return s.LowestStabilityScore == nil || *s.LowestStabilityScore >= stableScore
+}
+
+// adaptiveIntervalChanged reports whether the stored adaptive interval differs from interval
+func adaptiveIntervalChanged(stored *metav1.Duration, interval time.Duration) bool {
+	if stored == nil {
+		return interval > 0
+	}
+	return stored.Duration != interval
+}
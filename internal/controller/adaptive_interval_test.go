@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/config"
+)
+
+// stableSummary returns the summary of a reconciliation that changed nothing and whose
+// workloads all scored score
+func stableSummary(score int32) *reconcileSummary {
+	summary := &reconcileSummary{}
+	for range 3 {
+		summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusRecommended, StabilityScore: &score}, nil)
+	}
+	summary.Discovered = summary.Processed
+	return summary
+}
+
+func TestNextAdaptiveInterval_BacksOffWhileStable(t *testing.T) {
+	reconciler := &OptimizationPolicyReconciler{}
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.ReconciliationInterval = metav1.Duration{Duration: 5 * time.Minute}
+	policy.Spec.AdaptiveInterval = &optipodv1alpha1.AdaptiveInterval{MaxInterval: metav1.Duration{Duration: 30 * time.Minute}}
+
+	// Each stable reconciliation doubles the interval from the minimum up to the maximum
+	want := []time.Duration{5 * time.Minute, 10 * time.Minute, 20 * time.Minute, 30 * time.Minute, 30 * time.Minute}
+	for i, w := range want {
+		got := reconciler.nextAdaptiveInterval(policy, stableSummary(95))
+		if got != w {
+			t.Fatalf("reconciliation %d: interval = %v, want %v", i, got, w)
+		}
+		policy.Status.AdaptiveInterval = &metav1.Duration{Duration: got}
+	}
+}
+
+func TestNextAdaptiveInterval_SpeedsUpOnChange(t *testing.T) {
+	backedOff := 40 * time.Minute
+
+	tests := []struct {
+		name    string
+		summary func() *reconcileSummary
+	}{
+		{
+			name: "a workload was changed",
+			summary: func() *reconcileSummary {
+				summary := stableSummary(95)
+				score := int32(95)
+				summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusApplied, StabilityScore: &score}, nil)
+				return summary
+			},
+		},
+		{
+			name:    "a workload is volatile",
+			summary: func() *reconcileSummary { return stableSummary(60) },
+		},
+		{
+			name: "a workload failed",
+			summary: func() *reconcileSummary {
+				summary := stableSummary(95)
+				summary.record(nil, errors.New("conflict"))
+				return summary
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler := &OptimizationPolicyReconciler{}
+			policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+			policy.Spec.AdaptiveInterval = &optipodv1alpha1.AdaptiveInterval{
+				MinInterval: &metav1.Duration{Duration: 2 * time.Minute},
+				MaxInterval: metav1.Duration{Duration: time.Hour},
+			}
+			policy.Status.AdaptiveInterval = &metav1.Duration{Duration: backedOff}
+
+			if got := reconciler.nextAdaptiveInterval(policy, tt.summary()); got != 2*time.Minute {
+				t.Errorf("interval = %v, want the 2m minimum", got)
+			}
+		})
+	}
+}
+
+func TestNextAdaptiveInterval_ResetsOnPolicyChange(t *testing.T) {
+	reconciler := &OptimizationPolicyReconciler{}
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.AdaptiveInterval = &optipodv1alpha1.AdaptiveInterval{
+		MinInterval: &metav1.Duration{Duration: 2 * time.Minute},
+		MaxInterval: metav1.Duration{Duration: time.Hour},
+	}
+	policy.Generation = 3
+	policy.Status.ObservedGeneration = 3
+	policy.Status.AdaptiveInterval = &metav1.Duration{Duration: 20 * time.Minute}
+
+	if got := reconciler.nextAdaptiveInterval(policy, stableSummary(95)); got != 40*time.Minute {
+		t.Fatalf("interval = %v, want the backoff doubled to 40m", got)
+	}
+
+	// A changed spec is reconciled again at the minimum, however stable its workloads were
+	policy.Generation = 4
+	if got := reconciler.nextAdaptiveInterval(policy, stableSummary(95)); got != 2*time.Minute {
+		t.Errorf("interval = %v after a policy change, want the 2m minimum", got)
+	}
+}
+
+func TestCalculateRequeueInterval_AdaptiveBounds(t *testing.T) {
+	reconciler := &OptimizationPolicyReconciler{}
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.Mode = optipodv1alpha1.ModeRecommend
+	policy.Spec.AdaptiveInterval = &optipodv1alpha1.AdaptiveInterval{MaxInterval: metav1.Duration{Duration: time.Hour}}
+
+	// The adaptive interval replaces the mode based scaling, with up to 10% jitter
+	summary := stableSummary(95)
+	summary.AdaptiveInterval = time.Hour
+	for range 20 {
+		if got := reconciler.calculateRequeueInterval(policy, summary); got < time.Hour || got > 66*time.Minute {
+			t.Fatalf("calculateRequeueInterval() = %v, want between 1h and 1h6m", got)
+		}
+	}
+
+	// Without an adaptive interval the policy keeps its fixed schedule
+	policy.Spec.AdaptiveInterval = nil
+	if got := reconciler.nextAdaptiveInterval(policy, summary); got != 0 {
+		t.Errorf("nextAdaptiveInterval() = %v without adaptiveInterval, want 0", got)
+	}
+}
+
+func TestBaseReconciliationInterval_FallsBackToOperatorConfig(t *testing.T) {
+	operatorConfig := config.NewOperatorConfig()
+	reconciler := &OptimizationPolicyReconciler{OperatorConfig: operatorConfig}
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.ReconciliationInterval = metav1.Duration{}
+
+	// A policy without an interval follows the operator's, including reloads
+	if _, err := operatorConfig.ApplyConfigMapData(map[string]string{config.KeyReconciliationInterval: "2m"}); err != nil {
+		t.Fatalf("ApplyConfigMapData() error = %v", err)
+	}
+	if got := reconciler.baseReconciliationInterval(policy); got != 2*time.Minute {
+		t.Errorf("baseReconciliationInterval() = %v, want the reloaded 2m", got)
+	}
+
+	// The policy's own interval takes precedence
+	policy.Spec.ReconciliationInterval = metav1.Duration{Duration: 10 * time.Minute}
+	if got := reconciler.baseReconciliationInterval(policy); got != 10*time.Minute {
+		t.Errorf("baseReconciliationInterval() = %v, want the policy's 10m", got)
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// reservedAnnotationPrefix is the prefix of the canonical annotations, which custom templates
+// must not write since OptiPod reads some of them back (e.g. last-applied, approved)
+const reservedAnnotationPrefix = "optipod.io/"
+
+// AnnotationTemplateData is the data an annotation template is rendered with, once per
+// recommended container. Resources the recommendation does not cover are empty.
+type AnnotationTemplateData struct {
+	Kind      string
+	Namespace string
+	Workload  string
+	Container string
+	Policy    string
+
+	CPURequest    string
+	MemoryRequest string
+	CPULimit      string
+	MemoryLimit   string
+
+	StabilityScore int32
+}
+
+// AnnotationTemplate writes a recommendation under a custom annotation key, in addition to the
+// canonical optipod.io annotations
+type AnnotationTemplate struct {
+	key   *template.Template
+	value *template.Template
+}
+
+// ParseAnnotationTemplates parses annotation templates given as semicolon-separated
+// key=value pairs of Go templates, e.g.
+//
+//	cost.example.com/{{.Container}}-cpu={{.CPURequest}};cost.example.com/{{.Container}}-memory={{.MemoryRequest}}
+//
+// Every template is rendered with sample data, so templates referencing unknown fields, rendering
+// an invalid or reserved annotation key, or rendering the same key for every container are
+// rejected before they are used.
+func ParseAnnotationTemplates(spec string) ([]AnnotationTemplate, error) {
+	var templates []AnnotationTemplate
+	for _, entry := range strings.Split(spec, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		keyText, valueText, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("annotation template %q is not a key=value pair", entry)
+		}
+
+		key, err := template.New("key").Option("missingkey=error").Parse(strings.TrimSpace(keyText))
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation key template %q: %w", keyText, err)
+		}
+		value, err := template.New("value").Option("missingkey=error").Parse(valueText)
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation value template %q: %w", valueText, err)
+		}
+
+		t := AnnotationTemplate{key: key, value: value}
+		if err := t.validate(); err != nil {
+			return nil, fmt.Errorf("annotation template %q: %w", entry, err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// Render returns the annotation key and value for data. The value is empty when the template
+// renders nothing, e.g. for a resource without a recommendation.
+func (t AnnotationTemplate) Render(data AnnotationTemplateData) (string, string, error) {
+	var key, value bytes.Buffer
+	if err := t.key.Execute(&key, data); err != nil {
+		return "", "", fmt.Errorf("failed to render key: %w", err)
+	}
+	if err := t.value.Execute(&value, data); err != nil {
+		return "", "", fmt.Errorf("failed to render value: %w", err)
+	}
+
+	if errs := validation.IsQualifiedName(key.String()); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid annotation key %q: %s", key.String(), strings.Join(errs, "; "))
+	}
+	if strings.HasPrefix(key.String(), reservedAnnotationPrefix) {
+		return "", "", fmt.Errorf("annotation key %q uses the reserved %s prefix", key.String(), reservedAnnotationPrefix)
+	}
+	return key.String(), value.String(), nil
+}
+
+// validate renders the template with sample data for two containers. Each container must get
+// its own key, otherwise the containers of a workload would overwrite each other's annotation.
+func (t AnnotationTemplate) validate() error {
+	data := sampleAnnotationTemplateData()
+	key, _, err := t.Render(data)
+	if err != nil {
+		return err
+	}
+	data.Container = "sidecar"
+	other, _, err := t.Render(data)
+	if err != nil {
+		return err
+	}
+	if key == other {
+		return fmt.Errorf("annotation key %q does not include {{.Container}}", key)
+	}
+	return nil
+}
+
+// sampleAnnotationTemplateData returns data with every field set, for validating templates
+func sampleAnnotationTemplateData() AnnotationTemplateData {
+	return AnnotationTemplateData{
+		Kind:           "Deployment",
+		Namespace:      "default",
+		Workload:       "web",
+		Container:      "app",
+		Policy:         "default-policy",
+		CPURequest:     "250m",
+		MemoryRequest:  "256Mi",
+		CPULimit:       "250m",
+		MemoryLimit:    "282Mi",
+		StabilityScore: 80,
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+func TestParseAnnotationTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    int
+		wantErr string
+	}{
+		{name: "empty", spec: ""},
+		{
+			name: "key and value templates",
+			spec: "cost.example.com/{{.Container}}-cpu={{.CPURequest}}; cost.example.com/{{.Container}}-memory={{.MemoryRequest}}",
+			want: 2,
+		},
+		{name: "value with an equals sign", spec: "example.com/{{.Container}}-sizing=cpu={{.CPURequest}}", want: 1},
+		{name: "not a pair", spec: "example.com/cpu", wantErr: "not a key=value pair"},
+		{name: "unparsable template", spec: "example.com/{{.Container}}-cpu={{.CPURequest", wantErr: "invalid annotation value template"},
+		{name: "unknown field", spec: "example.com/{{.Container}}-cpu={{.CPU}}", wantErr: "failed to render value"},
+		{name: "invalid key", spec: "example.com/{{.Container}} cpu={{.CPURequest}}", wantErr: "invalid annotation key"},
+		{name: "reserved prefix", spec: "optipod.io/{{.Container}}-cpu={{.CPURequest}}", wantErr: "reserved"},
+		{name: "key without the container", spec: "example.com/cpu={{.CPURequest}}", wantErr: "does not include {{.Container}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, err := ParseAnnotationTemplates(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseAnnotationTemplates() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAnnotationTemplates() error = %v", err)
+			}
+			if len(templates) != tt.want {
+				t.Errorf("parsed %d templates, want %d", len(templates), tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessWorkload_AnnotationTemplates(t *testing.T) {
+	ctx := context.Background()
+	workload := createTestWorkload(TestContainerName)
+	deployment := workload.Object.(*appsv1.Deployment)
+	pod := createTestPod(TestPodName)
+	fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(deployment.DeepCopy(), pod).Build()
+
+	templates, err := ParseAnnotationTemplates("cost.example.com/{{.Container}}-cpu={{.CPURequest}};" +
+		"cost.example.com/{{.Container}}-limit={{.CPULimit}};cost.example.com/{{.Container}}-owner={{.Policy}}/{{.Workload}}")
+	if err != nil {
+		t.Fatalf("ParseAnnotationTemplates() error = %v", err)
+	}
+	processor := createTestProcessor(&recordingApplicationEngine{}, fakeClient)
+	processor.SetAnnotationTemplates(templates)
+
+	if _, err := processor.ProcessWorkload(ctx, workload, createTestPolicy(optipodv1alpha1.ModeRecommend)); err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	stored := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), stored); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	annotations := stored.Annotations
+	if got := annotations["cost.example.com/"+TestContainerName+"-cpu"]; got != "240m" {
+		t.Errorf("cpu annotation = %q, want 240m", got)
+	}
+	if got := annotations["cost.example.com/"+TestContainerName+"-owner"]; got != "test-policy/"+TestWorkloadName {
+		t.Errorf("owner annotation = %q, want test-policy/%s", got, TestWorkloadName)
+	}
+
+	// Limits are not recommended with updateRequestsOnly, so the limit annotation renders empty and is left out
+	if got, ok := annotations["cost.example.com/"+TestContainerName+"-limit"]; ok {
+		t.Errorf("limit annotation = %q, want it left out", got)
+	}
+
+	// The canonical annotations are still written
+	if annotations[optipodv1alpha1.AnnotationRecommendationPrefix+"."+TestContainerName+".cpu-request"] != "240m" {
+		t.Errorf("canonical annotations = %v, want them written alongside the custom ones", annotations)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+)
+
+// orderWorkloads sorts the discovered workloads into the policy's apply order. Workloads that
+// compare equal keep the namespace and name order of discovery.
+func orderWorkloads(workloads []discovery.Workload, order string) {
+	key := func(w *discovery.Workload) string { return workloadKey(w.Kind, w.Namespace, w.Name) }
+
+	switch order {
+	case optipodv1alpha1.ApplyOrderSmallestFirst:
+		pods := make(map[string]int64, len(workloads))
+		for i := range workloads {
+			pods[key(&workloads[i])] = workloadPods(&workloads[i])
+		}
+		sort.SliceStable(workloads, func(i, j int) bool {
+			return pods[key(&workloads[i])] < pods[key(&workloads[j])]
+		})
+	case optipodv1alpha1.ApplyOrderLargestSavingsFirst:
+		savings := make(map[string]expectedSavings, len(workloads))
+		for i := range workloads {
+			savings[key(&workloads[i])] = workloadExpectedSavings(&workloads[i])
+		}
+		sort.SliceStable(workloads, func(i, j int) bool {
+			sa, sb := savings[key(&workloads[i])], savings[key(&workloads[j])]
+			if sa.cpuMillis != sb.cpuMillis {
+				return sa.cpuMillis > sb.cpuMillis
+			}
+			return sa.memoryBytes > sb.memoryBytes
+		})
+	}
+}
+
+// expectedSavings are the requests a workload's last recorded recommendations free across its
+// pods, negative when they need more than is requested
+type expectedSavings struct {
+	cpuMillis   int64
+	memoryBytes int64
+}
+
+// workloadExpectedSavings estimates the savings of a workload from the recommendations recorded
+// in its annotations by the previous reconciliation. Containers without a recorded
+// recommendation count as saving nothing.
+func workloadExpectedSavings(workload *discovery.Workload) expectedSavings {
+	podSpec, err := workloadPodSpec(workload)
+	if err != nil {
+		return expectedSavings{}
+	}
+
+	var savings expectedSavings
+	annotations := workload.Object.GetAnnotations()
+	for _, container := range podSpec.Containers {
+		requests := container.Resources.Requests
+		if recorded, ok := recordedRecommendation(annotations, container.Name, string(corev1.ResourceCPU)); ok {
+			savings.cpuMillis += requests.Cpu().MilliValue() - recorded.MilliValue()
+		}
+		if recorded, ok := recordedRecommendation(annotations, container.Name, string(corev1.ResourceMemory)); ok {
+			savings.memoryBytes += requests.Memory().Value() - recorded.Value()
+		}
+	}
+	pods := workloadPods(workload)
+	savings.cpuMillis *= pods
+	savings.memoryBytes *= pods
+	return savings
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+)
+
+// newApplyOrderTestWorkload returns a deployment running replicas pods of a container
+// requesting 1 CPU, with recommendedCPU recorded as its last recommendation unless empty
+func newApplyOrderTestWorkload(name string, replicas int32, recommendedCPU string) discovery.Workload {
+	workload := createTestWorkload(TestContainerName)
+	workload.Name = name
+	deployment := workload.Object.(*appsv1.Deployment)
+	deployment.Name = name
+	deployment.Spec.Replicas = ptr.To(replicas)
+	deployment.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1"),
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+	if recommendedCPU != "" {
+		deployment.Annotations = map[string]string{
+			optipodv1alpha1.AnnotationRecommendationPrefix + "." + TestContainerName + ".cpu-request": recommendedCPU,
+		}
+	}
+	return *workload
+}
+
+func TestOrderWorkloads(t *testing.T) {
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{order: optipodv1alpha1.ApplyOrderAlphaByName, want: []string{"a", "b", "c", "d"}},
+		{order: optipodv1alpha1.ApplyOrderSmallestFirst, want: []string{"b", "d", "c", "a"}},
+		{order: optipodv1alpha1.ApplyOrderLargestSavingsFirst, want: []string{"b", "a", "c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			// Discovery returns the workloads by namespace and name
+			workloads := []discovery.Workload{
+				newApplyOrderTestWorkload("a", 5, "900m"), // saves 500m
+				newApplyOrderTestWorkload("b", 1, "100m"), // saves 900m
+				newApplyOrderTestWorkload("c", 3, ""),     // saves nothing
+				newApplyOrderTestWorkload("d", 1, "2"),    // needs 1 more CPU
+			}
+
+			orderWorkloads(workloads, tt.order)
+
+			names := make([]string, 0, len(workloads))
+			for _, workload := range workloads {
+				names = append(names, workload.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("orderWorkloads(%s) = %v, want %v", tt.order, names, tt.want)
+			}
+		})
+	}
+}
+
+func TestResumePosition(t *testing.T) {
+	workloads := []discovery.Workload{
+		newApplyOrderTestWorkload("b", 1, ""),
+		newApplyOrderTestWorkload("c", 3, ""),
+		newApplyOrderTestWorkload("a", 5, ""),
+	}
+	checkpoint := func(name string) *optipodv1alpha1.ReconcileCheckpoint {
+		return &optipodv1alpha1.ReconcileCheckpoint{LastNamespace: TestNamespace, LastName: name, LastKind: KindDeployment}
+	}
+
+	if got := resumePosition(workloads, nil, optipodv1alpha1.ApplyOrderSmallestFirst); got != 0 {
+		t.Errorf("resumePosition() = %d without a checkpoint, want 0", got)
+	}
+	// The workloads up to the last processed one are skipped in apply order
+	if got := resumePosition(workloads, checkpoint("c"), optipodv1alpha1.ApplyOrderSmallestFirst); got != 2 {
+		t.Errorf("resumePosition() = %d, want 2", got)
+	}
+	// A last processed workload that is gone restarts the reconciliation
+	if got := resumePosition(workloads, checkpoint("gone"), optipodv1alpha1.ApplyOrderSmallestFirst); got != 0 {
+		t.Errorf("resumePosition() = %d for a deleted workload, want 0", got)
+	}
+
+	// By name, the workloads sorting before the last processed one are skipped
+	workloads = []discovery.Workload{workloads[2], workloads[0], workloads[1]}
+	if got := resumePosition(workloads, checkpoint("b"), optipodv1alpha1.ApplyOrderAlphaByName); got != 2 {
+		t.Errorf("resumePosition() = %d by name, want 2", got)
+	}
+}
+
+func TestReconcile_ApplyOrder(t *testing.T) {
+	ctx := context.Background()
+
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.UpdateStrategy.ApplyOrder = optipodv1alpha1.ApplyOrderSmallestFirst
+	objects := createTestObjects(3)
+	for i, replicas := range []int32{4, 1, 2} {
+		objects[i+1].(*appsv1.Deployment).Spec.Replicas = ptr.To(replicas)
+	}
+	appEngine := &workloadRecordingApplicationEngine{}
+	reconciler, _ := createTestReconciler(appEngine, append(objects, policy)...)
+
+	if _, err := reconciler.processWorkloadsWithPolicySelection(ctx, policy); err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+
+	want := []string{TestWorkloadName + "-1", TestWorkloadName + "-2", TestWorkloadName + "-0"}
+	if !slices.Equal(appEngine.appliedWorkloads, want) {
+		t.Errorf("applied workloads = %v, want the smallest first %v", appEngine.appliedWorkloads, want)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// proposalHashLength is the number of hex characters of the proposal hash, short enough to copy
+// into an annotation by hand
+const proposalHashLength = 16
+
+// Requests that need approval are rounded up to these steps, so the proposal, and an approval of
+// it, is not invalidated by every small move of the usage the recommendation follows
+const (
+	proposalCPUStepMillis  = 10
+	proposalMemoryStepByte = 16 * 1024 * 1024
+)
+
+// roundForProposal rounds the requests of a recommendation up to the proposal steps. Changes that
+// need approval are applied rounded, so an approved proposal is exactly what is applied.
+func roundForProposal(rec *recommendation.Recommendation) {
+	if !rec.CPU.IsZero() {
+		millis := (rec.CPU.MilliValue() + proposalCPUStepMillis - 1) / proposalCPUStepMillis * proposalCPUStepMillis
+		rec.CPU = *resource.NewMilliQuantity(millis, resource.DecimalSI)
+	}
+	if !rec.Memory.IsZero() {
+		bytes := (rec.Memory.Value() + proposalMemoryStepByte - 1) / proposalMemoryStepByte * proposalMemoryStepByte
+		rec.Memory = *resource.NewQuantity(bytes, resource.BinarySI)
+	}
+}
+
+// buildProposal describes the requests the changes would apply and returns the description with
+// its hash. Containers kept unchanged are not part of it. The hash changes whenever any proposed
+// request does.
+func buildProposal(changes []application.ContainerChange) (string, string) {
+	var parts []string
+	for _, change := range changes {
+		if change.Unchanged {
+			continue
+		}
+		if rec := change.Recommendation; !rec.CPU.IsZero() {
+			parts = append(parts, fmt.Sprintf("%s.cpu-request=%s", change.Container, rec.CPU.String()))
+		}
+		if rec := change.Recommendation; !rec.Memory.IsZero() {
+			parts = append(parts, fmt.Sprintf("%s.memory-request=%s", change.Container, rec.Memory.String()))
+		}
+	}
+
+	proposal := strings.Join(parts, ",")
+	sum := sha256.Sum256([]byte(proposal))
+	return proposal, hex.EncodeToString(sum[:])[:proposalHashLength]
+}
+
+// isApproved reports whether the workload's approval annotation matches the proposal hash
+func isApproved(workload *discovery.Workload, hash string) bool {
+	obj, ok := workload.Object.(client.Object)
+	if !ok {
+		return false
+	}
+	return obj.GetAnnotations()[optipodv1alpha1.AnnotationApproved] == hash
+}
+
+// awaitApproval reports whether the changes are still waiting for approval, in which case they
+// are proposed through the workload's annotations and the status says how to approve them
+func (wp *WorkloadProcessor) awaitApproval(
+	ctx context.Context,
+	workload *discovery.Workload,
+	status *optipodv1alpha1.WorkloadStatus,
+	changes []application.ContainerChange,
+) (bool, error) {
+	proposal, hash := buildProposal(changes)
+	if isApproved(workload, hash) {
+		return false, nil
+	}
+	if err := wp.proposeForApproval(ctx, workload, proposal, hash); err != nil {
+		status.Status = StatusError
+		status.Reason = fmt.Sprintf("Failed to propose changes for approval: %v", err)
+		return false, err
+	}
+	status.Status = StatusPendingApproval
+	status.ProposalHash = hash
+	status.Reason = fmt.Sprintf("Waiting for approval: set annotation %s=%s on the workload to apply %s",
+		optipodv1alpha1.AnnotationApproved, hash, proposal)
+	return true, nil
+}
+
+// proposeForApproval records the proposal in the workload's annotations. A proposal that differs
+// from the recorded one removes the approval annotation, so an approval never carries over to
+// a different change.
+func (wp *WorkloadProcessor) proposeForApproval(ctx context.Context, workload *discovery.Workload, proposal, hash string) error {
+	if wp.client == nil {
+		return nil
+	}
+
+	obj, err := wp.getWorkloadObject(workload)
+	if err != nil {
+		return fmt.Errorf("failed to get workload object: %w", err)
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations[optipodv1alpha1.AnnotationProposedHash] == hash &&
+		annotations[optipodv1alpha1.AnnotationProposedResources] == proposal {
+		return nil
+	}
+
+	// The patch is rejected if the workload changed since it was read
+	patchBase := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	updated := make(map[string]string, len(annotations)+2)
+	for key, value := range annotations {
+		updated[key] = value
+	}
+	updated[optipodv1alpha1.AnnotationProposedResources] = proposal
+	updated[optipodv1alpha1.AnnotationProposedHash] = hash
+	delete(updated, optipodv1alpha1.AnnotationApproved)
+	obj.SetAnnotations(updated)
+
+	if err := wp.leaderTracker.checkLeader(); err != nil {
+		return err
+	}
+	if err := wp.client.Patch(ctx, obj, patchBase); err != nil {
+		return fmt.Errorf("failed to record proposal: %w", err)
+	}
+
+	logf.FromContext(ctx).Info("Proposed resource change for approval",
+		"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+		"proposal", proposal,
+		"hash", hash)
+	return nil
+}
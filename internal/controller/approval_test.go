@@ -0,0 +1,213 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+func TestBuildProposal(t *testing.T) {
+	changes := []application.ContainerChange{
+		{Container: "app", Recommendation: &recommendation.Recommendation{
+			CPU:    resource.MustParse("250m"),
+			Memory: resource.MustParse("256Mi"),
+		}},
+		{Container: "sidecar", Unchanged: true},
+	}
+
+	proposal, hash := buildProposal(changes)
+	if proposal != "app.cpu-request=250m,app.memory-request=256Mi" {
+		t.Errorf("proposal = %q, want only the requests of the changed container", proposal)
+	}
+	if len(hash) != proposalHashLength {
+		t.Errorf("hash = %q, want %d characters", hash, proposalHashLength)
+	}
+
+	// The hash binds an approval to the exact requests proposed
+	changed := []application.ContainerChange{{Container: "app", Recommendation: &recommendation.Recommendation{
+		CPU:    resource.MustParse("300m"),
+		Memory: resource.MustParse("256Mi"),
+	}}}
+	if _, changedHash := buildProposal(changed); changedHash == hash {
+		t.Errorf("hash %q did not change with the proposed requests", hash)
+	}
+}
+
+func TestRoundForProposal(t *testing.T) {
+	// Recommendations that moved a little with the usage round to the same proposal
+	var hashes []string
+	for _, usage := range []string{"322122547", "322200000", "323000000"} {
+		rec := &recommendation.Recommendation{CPU: resource.MustParse("241m"), Memory: resource.MustParse(usage)}
+		roundForProposal(rec)
+		if rec.CPU.String() != "250m" || rec.Memory.String() != "320Mi" {
+			t.Errorf("rounded %s = cpu %s, memory %s, want 250m and 320Mi", usage, rec.CPU.String(), rec.Memory.String())
+		}
+		_, hash := buildProposal([]application.ContainerChange{{Container: "app", Recommendation: rec}})
+		hashes = append(hashes, hash)
+	}
+	if hashes[0] != hashes[1] || hashes[1] != hashes[2] {
+		t.Errorf("hashes = %v, want the same proposal", hashes)
+	}
+}
+
+func TestProcessWorkload_ApprovalRequired(t *testing.T) {
+	ctx := context.Background()
+	workload := createTestWorkload(TestContainerName)
+	deployment := workload.Object.(*appsv1.Deployment)
+	pod := createTestPod(TestPodName)
+	fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(deployment.DeepCopy(), pod).Build()
+
+	appEngine := &recordingApplicationEngine{}
+	processor := createTestProcessor(appEngine, fakeClient)
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.UpdateStrategy.ApprovalRequired = true
+
+	// Without an approval the change is only proposed
+	status, err := processor.ProcessWorkload(ctx, workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusPendingApproval || status.ProposalHash == "" || len(appEngine.appliedContainers) != 0 {
+		t.Fatalf("status = %q (%s) with hash %q and applied %v, want a pending proposal and nothing applied",
+			status.Status, status.Reason, status.ProposalHash, appEngine.appliedContainers)
+	}
+
+	stored := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), stored); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if stored.Annotations[optipodv1alpha1.AnnotationProposedHash] != status.ProposalHash ||
+		stored.Annotations[optipodv1alpha1.AnnotationProposedResources] == "" {
+		t.Fatalf("annotations = %v, want the proposal and its hash", stored.Annotations)
+	}
+
+	// Approving the proposal applies it
+	stored.Annotations[optipodv1alpha1.AnnotationApproved] = status.ProposalHash
+	if err := fakeClient.Update(ctx, stored); err != nil {
+		t.Fatalf("failed to approve: %v", err)
+	}
+	status, err = processor.ProcessWorkload(ctx, workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusApplied || len(appEngine.appliedContainers) != 1 {
+		t.Errorf("status = %q with applied %v, want the approved proposal applied", status.Status, appEngine.appliedContainers)
+	}
+}
+
+func TestProposeForApproval_NewProposalInvalidatesApproval(t *testing.T) {
+	ctx := context.Background()
+	workload := createTestWorkload(TestContainerName)
+	deployment := workload.Object.(*appsv1.Deployment)
+	deployment.Annotations = map[string]string{
+		optipodv1alpha1.AnnotationProposedResources: "test-container.cpu-request=250m",
+		optipodv1alpha1.AnnotationProposedHash:      "oldhash",
+		optipodv1alpha1.AnnotationApproved:          "oldhash",
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(deployment.DeepCopy()).Build()
+	processor := createTestProcessor(&recordingApplicationEngine{}, fakeClient)
+
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if err := processor.proposeForApproval(ctx, workload, "test-container.cpu-request=300m", "newhash"); err != nil {
+		t.Fatalf("proposeForApproval() error = %v", err)
+	}
+
+	stored := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), stored); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if _, approved := stored.Annotations[optipodv1alpha1.AnnotationApproved]; approved {
+		t.Errorf("annotations = %v, want the earlier approval removed", stored.Annotations)
+	}
+	if stored.Annotations[optipodv1alpha1.AnnotationProposedHash] != "newhash" {
+		t.Errorf("proposed hash = %q, want newhash", stored.Annotations[optipodv1alpha1.AnnotationProposedHash])
+	}
+}
+
+// beyondBoundsApplicationEngine decides every change is too large to resize in-place
+type beyondBoundsApplicationEngine struct {
+	recordingApplicationEngine
+}
+
+func (m *beyondBoundsApplicationEngine) CanApply(ctx context.Context, workload *application.Workload, containerName string, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyDecision, error) {
+	return &application.ApplyDecision{
+		CanApply:            true,
+		Method:              application.Recreate,
+		BeyondInPlaceBounds: true,
+		Reason:              "Test decision",
+	}, nil
+}
+
+func TestProcessWorkload_ApprovalBeyondInPlaceBounds(t *testing.T) {
+	for _, approvalBeyondBounds := range []bool{false, true} {
+		ctx := context.Background()
+		workload := createTestWorkload(TestContainerName)
+		deployment := workload.Object.(*appsv1.Deployment)
+		pod := createTestPod(TestPodName)
+		fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(deployment.DeepCopy(), pod).Build()
+
+		appEngine := &beyondBoundsApplicationEngine{}
+		processor := createTestProcessor(appEngine, fakeClient)
+		policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+		policy.Spec.UpdateStrategy.InPlaceBounds = &optipodv1alpha1.InPlaceBounds{ApprovalBeyondBounds: approvalBeyondBounds}
+
+		status, err := processor.ProcessWorkload(ctx, workload, policy)
+		if err != nil {
+			t.Fatalf("ProcessWorkload() error = %v", err)
+		}
+
+		// Without approvalBeyondBounds, large changes recreate the pods right away
+		if !approvalBeyondBounds {
+			if status.Status != StatusApplied || len(appEngine.appliedContainers) != 1 {
+				t.Errorf("status = %q (%s), want the change applied", status.Status, status.Reason)
+			}
+			continue
+		}
+		if status.Status != StatusPendingApproval || status.ProposalHash == "" || len(appEngine.appliedContainers) != 0 {
+			t.Errorf("status = %q (%s) with applied %v, want the large change held for approval",
+				status.Status, status.Reason, appEngine.appliedContainers)
+		}
+	}
+}
+
+func TestProcessWorkload_ApprovalNotAskedWithinTolerance(t *testing.T) {
+	appEngine := &toleratingApplicationEngine{withinTolerance: map[string]bool{TestContainerName: true}}
+	processor := createTestProcessor(appEngine, nil)
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.UpdateStrategy.ApprovalRequired = true
+
+	status, err := processor.ProcessWorkload(context.Background(), createTestWorkload(TestContainerName), policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusRecommended || status.ProposalHash != "" {
+		t.Errorf("status = %q (%s) with hash %q, want no proposal for a change within tolerance",
+			status.Status, status.Reason, status.ProposalHash)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/audit"
+)
+
+// memoryAuditSink keeps the audit records written to it
+type memoryAuditSink struct {
+	mu      sync.Mutex
+	records []audit.Record
+}
+
+func (s *memoryAuditSink) Write(_ context.Context, record audit.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+// flushAuditLogger writes the records queued in the logger to its sink
+func flushAuditLogger(t *testing.T, logger *audit.Logger) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := logger.Start(ctx); err != nil {
+		t.Fatalf("audit logger Start() error = %v", err)
+	}
+}
+
+func TestProcessWorkload_AuditRecords(t *testing.T) {
+	tests := []struct {
+		name       string
+		appEngine  ApplicationEngine
+		wantResult string
+		wantMethod string
+	}{
+		{name: "successful apply", appEngine: &recordingApplicationEngine{}, wantResult: audit.ResultSuccess, wantMethod: "ServerSideApply"},
+		{name: "failed apply", appEngine: &failingApplicationEngine{err: errors.New("conflict")}, wantResult: audit.ResultFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &memoryAuditSink{}
+			logger := audit.NewLogger(sink, 10)
+			processor := createTestProcessor(tt.appEngine, nil)
+			processor.SetAuditLogger(logger)
+
+			workload := createTestWorkload(TestContainerName)
+			deployment := workload.Object.(*appsv1.Deployment)
+			deployment.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}
+
+			_, _ = processor.ProcessWorkload(context.Background(), workload, createTestPolicy(optipodv1alpha1.ModeAuto))
+			flushAuditLogger(t, logger)
+
+			if len(sink.records) != 1 {
+				t.Fatalf("got %d audit records, want 1: %+v", len(sink.records), sink.records)
+			}
+			record := sink.records[0]
+			if record.Result != tt.wantResult || record.Method != tt.wantMethod || record.Action != audit.ActionApply {
+				t.Errorf("record = %s %s via %q, want %s via %q", record.Action, record.Result, record.Method, tt.wantResult, tt.wantMethod)
+			}
+			if record.Policy != TestNamespace+"/test-policy" || record.WorkloadName != TestWorkloadName ||
+				record.Container != TestContainerName || record.Actor == "" || record.Timestamp.IsZero() {
+				t.Errorf("record identifies %+v, want the policy, workload, container and actor", record)
+			}
+			if record.Old.CPU != "1" || record.Old.Memory != "1Gi" || record.New.CPU == "" || record.New.Memory == "" {
+				t.Errorf("record old = %+v, new = %+v, want the previous requests and the recommendation", record.Old, record.New)
+			}
+		})
+	}
+}
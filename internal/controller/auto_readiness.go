@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/optipod/optipod/internal/metrics"
+	"github.com/optipod/optipod/internal/observability"
+)
+
+// autoReadinessRequeueDelay is how soon a policy whose changes were held by the readiness gate is
+// reconciled again
+const autoReadinessRequeueDelay = 30 * time.Second
+
+// AutoReadinessGate holds Auto mode changes back after startup until the metrics backend has
+// warmed up: its health check passes and at least one metrics fetch has succeeded. Until then
+// workloads are only given recommendations. Once open, the gate stays open; later outages are
+// handled per workload as missing metrics.
+type AutoReadinessGate struct {
+	provider metrics.MetricsProvider
+	fetched  atomic.Bool
+	ready    atomic.Bool
+}
+
+// NewAutoReadinessGate creates a closed gate for the metrics backend
+func NewAutoReadinessGate(provider metrics.MetricsProvider) *AutoReadinessGate {
+	observability.AutoReady.Set(0)
+	return &AutoReadinessGate{provider: provider}
+}
+
+// recordMetricsFetch records that metrics were fetched from the backend
+func (g *AutoReadinessGate) recordMetricsFetch() {
+	if g == nil {
+		return
+	}
+	g.fetched.Store(true)
+}
+
+// Ready reports whether Auto mode may apply changes. The backend is health checked once metrics
+// have been fetched, until the check passes. A nil gate is always ready.
+func (g *AutoReadinessGate) Ready(ctx context.Context) bool {
+	if g == nil || g.ready.Load() {
+		return true
+	}
+	if !g.fetched.Load() {
+		return false
+	}
+	if err := g.provider.HealthCheck(ctx); err != nil {
+		logf.FromContext(ctx).Info("Metrics backend is not healthy yet, holding Auto mode changes", "error", err.Error())
+		return false
+	}
+	if g.ready.CompareAndSwap(false, true) {
+		observability.AutoReady.Set(1)
+		logf.FromContext(ctx).Info("Metrics are flowing, Auto mode changes are no longer held")
+	}
+	return true
+}
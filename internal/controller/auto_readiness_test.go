@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/observability"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+func TestAutoReadinessGate(t *testing.T) {
+	ctx := context.Background()
+	provider := &flakyHealthMetricsProvider{healthErr: errors.New("connection refused")}
+	gate := NewAutoReadinessGate(provider)
+
+	// Healthy or not, the gate stays closed until metrics have been fetched
+	if gate.Ready(ctx) {
+		t.Fatal("Ready() = true before any metrics fetch, want false")
+	}
+	provider.healthErr = nil
+	if gate.Ready(ctx) {
+		t.Fatal("Ready() = true before any metrics fetch of a healthy backend, want false")
+	}
+
+	// A fetch alone is not enough while the health check fails
+	gate.recordMetricsFetch()
+	provider.healthErr = errors.New("connection refused")
+	if gate.Ready(ctx) || testutil.ToFloat64(observability.AutoReady) != 0 {
+		t.Fatalf("Ready() = true or optipod_auto_ready = %v with a failing health check, want closed",
+			testutil.ToFloat64(observability.AutoReady))
+	}
+
+	provider.healthErr = nil
+	if !gate.Ready(ctx) || testutil.ToFloat64(observability.AutoReady) != 1 {
+		t.Fatalf("Ready() = false or optipod_auto_ready = %v once healthy with metrics fetched, want open",
+			testutil.ToFloat64(observability.AutoReady))
+	}
+
+	// Once open, later outages do not close the gate again
+	provider.healthErr = errors.New("connection refused")
+	if !gate.Ready(ctx) {
+		t.Error("Ready() = false after the gate opened, want it to stay open")
+	}
+
+	var noGate *AutoReadinessGate
+	if !noGate.Ready(ctx) {
+		t.Error("Ready() = false for a nil gate, want true")
+	}
+}
+
+func TestProcessWorkload_AutoReadinessGate(t *testing.T) {
+	ctx := context.Background()
+	workload := createTestWorkload("app")
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+
+	// The backend returns metrics but does not pass its health check yet
+	provider := &flakyHealthMetricsProvider{mockMetricsProvider: *createTestMetricsProvider(), healthErr: errors.New("not ready")}
+	appEngine := &recordingApplicationEngine{}
+	processor := NewWorkloadProcessor(provider, recommendation.NewEngine(), appEngine, nil)
+	processor.SetAutoReadinessGate(NewAutoReadinessGate(provider))
+
+	status, err := processor.ProcessWorkload(ctx, workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusAwaitingMetricsReady || !strings.Contains(status.Reason, "metrics backend to become ready") {
+		t.Errorf("status = %q (%s), want %q", status.Status, status.Reason, StatusAwaitingMetricsReady)
+	}
+	if len(appEngine.appliedContainers) != 0 || len(status.Recommendations) != 1 {
+		t.Errorf("applied %v with %d recommendations, want the recommendation reported but not applied",
+			appEngine.appliedContainers, len(status.Recommendations))
+	}
+
+	// Recommend mode is unaffected by the gate
+	recommendPolicy := createTestPolicy(optipodv1alpha1.ModeRecommend)
+	if status, err := processor.ProcessWorkload(ctx, workload, recommendPolicy); err != nil || status.Status != StatusRecommended {
+		t.Errorf("ProcessWorkload() in Recommend mode = %v, %v, want %q", status, err, StatusRecommended)
+	}
+
+	// Once the backend is healthy, the change is applied
+	provider.healthErr = nil
+	status, err = processor.ProcessWorkload(ctx, workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusApplied || len(appEngine.appliedContainers) != 1 {
+		t.Errorf("status = %q with %v applied, want %q", status.Status, appEngine.appliedContainers, StatusApplied)
+	}
+}
+
+func TestReconcileSummary_MetricsWarmingUp(t *testing.T) {
+	summary := &reconcileSummary{Discovered: 3}
+	summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusApplied}, nil)
+	if condition := summary.metricsWarmingUpCondition(); condition != nil {
+		t.Fatalf("metricsWarmingUpCondition() = %+v without held changes, want nil", condition)
+	}
+
+	summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusAwaitingMetricsReady}, nil)
+	summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusAwaitingMetricsReady}, nil)
+	condition := summary.metricsWarmingUpCondition()
+	if condition == nil || condition.Type != ConditionTypeMetricsWarmingUp || condition.Status != metav1.ConditionTrue ||
+		!strings.HasPrefix(condition.Message, "Changes to 2 workload(s) are held") {
+		t.Errorf("metricsWarmingUpCondition() = %+v, want a True condition for 2 workloads", condition)
+	}
+
+	// Held changes are retried soon
+	reconciler := &OptimizationPolicyReconciler{}
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.ReconciliationInterval = metav1.Duration{Duration: 10 * time.Minute}
+	if got := reconciler.calculateRequeueInterval(policy, summary); got != autoReadinessRequeueDelay {
+		t.Errorf("calculateRequeueInterval() = %v, want %v while changes are held", got, autoReadinessRequeueDelay)
+	}
+}
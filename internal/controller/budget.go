@@ -0,0 +1,249 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/observability"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// IncreaseBudget caps the requests all policies together may add to the cluster per interval.
+// Increases are reserved workload by workload; once a reservation would exceed the budget, the
+// workload's increase is deferred until the budget resets at the start of the next interval.
+// An increase larger than the whole budget never fits and is held until the limits are raised.
+// Decreases never consume budget. It is safe for concurrent use across reconciles.
+type IncreaseBudget struct {
+	// maxCPU and maxMemory are the requests that may be added per interval (zero = unlimited)
+	maxCPU    resource.Quantity
+	maxMemory resource.Quantity
+
+	// interval returns the budget interval, read on every reservation so it follows config reloads
+	interval func() time.Duration
+	now      func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	usedCPU     resource.Quantity
+	usedMemory  resource.Quantity
+}
+
+// NewIncreaseBudget creates a budget allowing maxCPU and maxMemory of added requests per interval.
+// A zero maximum leaves that resource unlimited.
+func NewIncreaseBudget(maxCPU, maxMemory resource.Quantity, interval func() time.Duration) *IncreaseBudget {
+	b := &IncreaseBudget{
+		maxCPU:    maxCPU,
+		maxMemory: maxMemory,
+		interval:  interval,
+		now:       time.Now,
+	}
+	b.windowStart = b.now()
+	b.updateMetrics()
+	return b
+}
+
+// SetLimits changes the requests that may be added per interval, e.g. after a configuration
+// reload. What the current interval has used so far counts against the new limits.
+func (b *IncreaseBudget) SetLimits(maxCPU, maxMemory resource.Quantity) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.maxCPU = maxCPU
+	b.maxMemory = maxMemory
+	if maxCPU.IsZero() {
+		observability.IncreaseBudgetRemaining.DeleteLabelValues(string(corev1.ResourceCPU))
+	}
+	if maxMemory.IsZero() {
+		observability.IncreaseBudgetRemaining.DeleteLabelValues(string(corev1.ResourceMemory))
+	}
+	b.updateMetrics()
+}
+
+// Reserve consumes cpu and memory from the budget and returns true, or returns false and consumes
+// nothing when either would exceed what is left in the current interval. A nil budget allows
+// every increase.
+func (b *IncreaseBudget) Reserve(cpu, memory resource.Quantity) bool {
+	if b == nil || (cpu.Sign() <= 0 && memory.Sign() <= 0) {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.resetIfExpired()
+	if exceeds(b.usedCPU, cpu, b.maxCPU) || exceeds(b.usedMemory, memory, b.maxMemory) {
+		return false
+	}
+	b.usedCPU.Add(cpu)
+	b.usedMemory.Add(memory)
+	b.updateMetrics()
+	return true
+}
+
+// ExceedsLimits reports whether cpu or memory alone is larger than the whole budget, so it cannot
+// be reserved in any interval until the limits are raised. A nil budget has no limits.
+func (b *IncreaseBudget) ExceedsLimits(cpu, memory resource.Quantity) bool {
+	if b == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return exceeds(resource.Quantity{}, cpu, b.maxCPU) || exceeds(resource.Quantity{}, memory, b.maxMemory)
+}
+
+// Release returns a reservation whose increase was not applied
+func (b *IncreaseBudget) Release(cpu, memory resource.Quantity) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.usedCPU.Sub(cpu)
+	b.usedMemory.Sub(memory)
+	if b.usedCPU.Sign() < 0 {
+		b.usedCPU = resource.Quantity{}
+	}
+	if b.usedMemory.Sign() < 0 {
+		b.usedMemory = resource.Quantity{}
+	}
+	b.updateMetrics()
+}
+
+// resetIfExpired starts a new interval with the full budget once the current one has ended
+func (b *IncreaseBudget) resetIfExpired() {
+	now := b.now()
+	if interval := b.interval(); interval > 0 && now.Sub(b.windowStart) < interval {
+		return
+	}
+	b.windowStart = now
+	b.usedCPU = resource.Quantity{}
+	b.usedMemory = resource.Quantity{}
+	b.updateMetrics()
+}
+
+// updateMetrics reports the remaining budget of each limited resource
+func (b *IncreaseBudget) updateMetrics() {
+	if !b.maxCPU.IsZero() {
+		remaining := b.maxCPU.DeepCopy()
+		remaining.Sub(b.usedCPU)
+		observability.IncreaseBudgetRemaining.WithLabelValues(string(corev1.ResourceCPU)).Set(max(remaining.AsApproximateFloat64(), 0))
+	}
+	if !b.maxMemory.IsZero() {
+		remaining := b.maxMemory.DeepCopy()
+		remaining.Sub(b.usedMemory)
+		observability.IncreaseBudgetRemaining.WithLabelValues(string(corev1.ResourceMemory)).Set(max(remaining.AsApproximateFloat64(), 0))
+	}
+}
+
+// exceeds reports whether adding amount to used goes over limit; a zero limit is unlimited
+func exceeds(used, amount, limit resource.Quantity) bool {
+	if limit.IsZero() || amount.Sign() <= 0 {
+		return false
+	}
+	total := used.DeepCopy()
+	total.Add(amount)
+	return total.Cmp(limit) > 0
+}
+
+// requestIncrease returns the CPU and memory the changes add to the cluster: the increase of each
+// container's requests over its current requests, times the number of pods the workload runs.
+// Lowered requests do not offset raised ones.
+func requestIncrease(
+	workload *discovery.Workload,
+	changes map[string]*recommendation.Recommendation,
+	current map[string]corev1.ResourceRequirements,
+) (resource.Quantity, resource.Quantity) {
+	var cpu, memory resource.Quantity
+	for container, rec := range changes {
+		requests := current[container].Requests
+		if increase := rec.CPU.DeepCopy(); !rec.CPU.IsZero() {
+			increase.Sub(*requests.Cpu())
+			if increase.Sign() > 0 {
+				cpu.Add(increase)
+			}
+		}
+		if increase := rec.Memory.DeepCopy(); !rec.Memory.IsZero() {
+			increase.Sub(*requests.Memory())
+			if increase.Sign() > 0 {
+				memory.Add(increase)
+			}
+		}
+	}
+
+	pods := workloadPods(workload)
+	return *resource.NewMilliQuantity(cpu.MilliValue()*pods, resource.DecimalSI),
+		*resource.NewQuantity(memory.Value()*pods, resource.BinarySI)
+}
+
+// holdIncreases keeps the requests the changes would raise at their current values, so only the
+// decreases go ahead, and reports whether any request is still lowered
+func holdIncreases(changes map[string]*recommendation.Recommendation, current map[string]corev1.ResourceRequirements) bool {
+	lowered := false
+	for container, rec := range changes {
+		requests := current[container].Requests
+		if !rec.CPU.IsZero() {
+			switch rec.CPU.Cmp(*requests.Cpu()) {
+			case 1:
+				rec.CPU = requests.Cpu().DeepCopy()
+			case -1:
+				lowered = true
+			}
+		}
+		if !rec.Memory.IsZero() {
+			switch rec.Memory.Cmp(*requests.Memory()) {
+			case 1:
+				rec.Memory = requests.Memory().DeepCopy()
+			case -1:
+				lowered = true
+			}
+		}
+	}
+	return lowered
+}
+
+// workloadPods returns the number of pods a workload runs: its desired replicas, or for a
+// DaemonSet the number of nodes it is scheduled on. It is at least 1.
+func workloadPods(workload *discovery.Workload) int64 {
+	pods := int64(1)
+	switch obj := workload.Object.(type) {
+	case *appsv1.Deployment:
+		if obj.Spec.Replicas != nil {
+			pods = int64(*obj.Spec.Replicas)
+		}
+	case *appsv1.StatefulSet:
+		if obj.Spec.Replicas != nil {
+			pods = int64(*obj.Spec.Replicas)
+		}
+	case *appsv1.DaemonSet:
+		pods = int64(obj.Status.DesiredNumberScheduled)
+	}
+	if pods < 1 {
+		pods = 1
+	}
+	return pods
+}
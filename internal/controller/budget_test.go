@@ -0,0 +1,219 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/observability"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// newTestIncreaseBudget returns a budget with a 5 minute interval and a clock the test advances
+func newTestIncreaseBudget(maxCPU, maxMemory string) (*IncreaseBudget, *time.Time) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	budget := NewIncreaseBudget(resource.MustParse(maxCPU), resource.MustParse(maxMemory),
+		func() time.Duration { return 5 * time.Minute })
+	budget.now = func() time.Time { return now }
+	budget.windowStart = now
+	return budget, &now
+}
+
+func TestIncreaseBudget(t *testing.T) {
+	budget, now := newTestIncreaseBudget("2", "4Gi")
+
+	if !budget.Reserve(resource.MustParse("1500m"), resource.MustParse("1Gi")) {
+		t.Fatal("Reserve() = false for an increase within the budget")
+	}
+	if got := testutil.ToFloat64(observability.IncreaseBudgetRemaining.WithLabelValues("cpu")); got != 0.5 {
+		t.Errorf("remaining CPU = %v, want 0.5", got)
+	}
+
+	// An increase that does not fit is deferred without consuming anything
+	if budget.Reserve(resource.MustParse("1"), resource.MustParse("1Gi")) {
+		t.Error("Reserve() = true for an increase exceeding the CPU budget")
+	}
+	if !budget.Reserve(resource.MustParse("0"), resource.MustParse("3Gi")) {
+		t.Error("Reserve() = false for a memory increase within the budget")
+	}
+	if budget.Reserve(resource.MustParse("0"), resource.MustParse("1Mi")) {
+		t.Error("Reserve() = true once the memory budget is used up")
+	}
+
+	// Decreases are always allowed
+	if !budget.Reserve(resource.Quantity{}, resource.Quantity{}) {
+		t.Error("Reserve() = false for a change without increases")
+	}
+
+	// A released reservation can be used again
+	budget.Release(resource.MustParse("1"), resource.Quantity{})
+	if !budget.Reserve(resource.MustParse("1"), resource.Quantity{}) {
+		t.Error("Reserve() = false after the reservation was released")
+	}
+
+	// The full budget is available again in the next interval
+	*now = now.Add(5 * time.Minute)
+	if !budget.Reserve(resource.MustParse("2"), resource.MustParse("4Gi")) {
+		t.Error("Reserve() = false for the full budget after the interval reset")
+	}
+	if got := testutil.ToFloat64(observability.IncreaseBudgetRemaining.WithLabelValues("memory")); got != 0 {
+		t.Errorf("remaining memory = %v, want 0", got)
+	}
+
+	var nilBudget *IncreaseBudget
+	if !nilBudget.Reserve(resource.MustParse("100"), resource.MustParse("100Gi")) {
+		t.Error("a nil budget must allow every increase")
+	}
+}
+
+func TestIncreaseBudget_OversizedIncrease(t *testing.T) {
+	budget, now := newTestIncreaseBudget("1", "0")
+
+	// An increase larger than the whole budget is held even as the first one of an interval
+	if budget.Reserve(resource.MustParse("3"), resource.Quantity{}) {
+		t.Fatal("Reserve() = true for an increase larger than the whole budget")
+	}
+	if !budget.ExceedsLimits(resource.MustParse("3"), resource.Quantity{}) {
+		t.Error("ExceedsLimits() = false for an increase larger than the whole budget")
+	}
+	if got := testutil.ToFloat64(observability.IncreaseBudgetRemaining.WithLabelValues("cpu")); got != 1 {
+		t.Errorf("remaining CPU = %v, want 1", got)
+	}
+
+	// It does not fit in the next interval either, while increases within the budget still do
+	*now = now.Add(5 * time.Minute)
+	if budget.Reserve(resource.MustParse("3"), resource.Quantity{}) {
+		t.Error("Reserve() = true for the oversized increase in the next interval")
+	}
+	if budget.ExceedsLimits(resource.MustParse("1"), resource.Quantity{}) {
+		t.Error("ExceedsLimits() = true for an increase of the whole budget")
+	}
+	if !budget.Reserve(resource.MustParse("1"), resource.Quantity{}) {
+		t.Error("Reserve() = false for an increase of the whole budget")
+	}
+}
+
+func TestIncreaseBudget_UnlimitedResource(t *testing.T) {
+	budget, _ := newTestIncreaseBudget("1", "0")
+	if !budget.Reserve(resource.MustParse("500m"), resource.MustParse("100Gi")) {
+		t.Error("Reserve() = false for memory without a memory budget")
+	}
+}
+
+func TestIncreaseBudget_SetLimits(t *testing.T) {
+	// An unlimited budget, as created at startup without limits, takes limits from a reload
+	budget, _ := newTestIncreaseBudget("0", "0")
+	if !budget.Reserve(resource.MustParse("3"), resource.Quantity{}) {
+		t.Fatal("Reserve() = false without a budget")
+	}
+
+	budget.SetLimits(resource.MustParse("4"), resource.Quantity{})
+	if budget.Reserve(resource.MustParse("2"), resource.Quantity{}) {
+		t.Error("Reserve() = true beyond the reloaded limit, counting what the interval already used")
+	}
+	if !budget.Reserve(resource.MustParse("1"), resource.Quantity{}) {
+		t.Error("Reserve() = false within the reloaded limit")
+	}
+}
+
+func TestRequestIncrease(t *testing.T) {
+	workload := createTestWorkload("app", "sidecar")
+	workload.Object.(*appsv1.Deployment).Spec.Replicas = ptr.To[int32](3)
+	current := map[string]corev1.ResourceRequirements{
+		"app": {Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		}},
+		"sidecar": {},
+	}
+	changes := map[string]*recommendation.Recommendation{
+		"app":     {CPU: resource.MustParse("300m"), Memory: resource.MustParse("256Mi")},
+		"sidecar": {CPU: resource.MustParse("50m")},
+	}
+
+	// The memory decrease of app does not offset anything; every increase counts for each replica
+	cpu, memory := requestIncrease(workload, changes, current)
+	if want := resource.MustParse("750m"); cpu.Cmp(want) != 0 {
+		t.Errorf("CPU increase = %s, want 750m", cpu.String())
+	}
+	if !memory.IsZero() {
+		t.Errorf("memory increase = %s, want none", memory.String())
+	}
+}
+
+func TestProcessWorkload_IncreaseBudget(t *testing.T) {
+	// The test recommendation is 240m CPU and about 307Mi memory
+	tests := []struct {
+		name        string
+		maxCPU      string
+		usedCPU     string
+		request     string
+		memory      string
+		wantStatus  string
+		wantApplied int
+		wantReason  string
+	}{
+		{name: "within the budget", maxCPU: "1", request: "100m", memory: "512Mi", wantStatus: StatusApplied, wantApplied: 1},
+		{name: "budget exhausted", maxCPU: "200m", usedCPU: "100m", request: "100m", memory: "256Mi",
+			wantStatus: StatusRecommended, wantReason: "cluster increase budget exhausted"},
+		{name: "oversized first increase", maxCPU: "100m", request: "100m", memory: "256Mi",
+			wantStatus: StatusRecommended, wantReason: "exceeds the whole budget and is held until it is raised"},
+		{name: "decreases are always allowed", maxCPU: "100m", usedCPU: "100m", request: "1", memory: "512Mi",
+			wantStatus: StatusApplied, wantApplied: 1},
+		{name: "decreases go ahead while the increase is deferred", maxCPU: "100m", usedCPU: "50m", request: "100m", memory: "512Mi",
+			wantStatus: StatusApplied, wantApplied: 1, wantReason: "only decreases applied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload := createTestWorkload(TestContainerName)
+			workload.Object.(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(tt.request),
+				corev1.ResourceMemory: resource.MustParse(tt.memory),
+			}
+			budget, _ := newTestIncreaseBudget(tt.maxCPU, "0")
+			if tt.usedCPU != "" && !budget.Reserve(resource.MustParse(tt.usedCPU), resource.Quantity{}) {
+				t.Fatalf("failed to use %s of the budget", tt.usedCPU)
+			}
+			appEngine := &recordingApplicationEngine{}
+			processor := createTestProcessor(appEngine, nil)
+			processor.SetIncreaseBudget(budget)
+
+			status, err := processor.ProcessWorkload(context.Background(), workload, createTestPolicy(optipodv1alpha1.ModeAuto))
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			if status.Status != tt.wantStatus || len(appEngine.appliedContainers) != tt.wantApplied {
+				t.Errorf("status = %s (%s) with applied %v, want %s with %d applied",
+					status.Status, status.Reason, appEngine.appliedContainers, tt.wantStatus, tt.wantApplied)
+			}
+			if !strings.Contains(status.Reason, tt.wantReason) {
+				t.Errorf("reason = %q, want it to contain %q", status.Reason, tt.wantReason)
+			}
+		})
+	}
+}
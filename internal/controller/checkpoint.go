@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+)
+
+// checkpointRequeueDelay is how soon a reconciliation that ran out of its time budget continues
+const checkpointRequeueDelay = time.Second
+
+// resumeCheckpoint returns the checkpoint a reconciliation of the policy resumes from, or nil
+// when there is none or it was saved for another generation of the policy
+func resumeCheckpoint(pol *optipodv1alpha1.OptimizationPolicy) *optipodv1alpha1.ReconcileCheckpoint {
+	checkpoint := pol.Status.Checkpoint
+	if checkpoint == nil || checkpoint.PolicyGeneration != pol.Generation {
+		return nil
+	}
+	return checkpoint
+}
+
+// resumePosition returns the index of the first workload not processed before the checkpoint
+// was saved, with workloads in the policy's apply order. Other orders than AlphaByName depend on
+// the workloads' size, which may have changed since, so the workloads up to the checkpoint's
+// last workload are skipped; when it is gone, every workload is processed again.
+func resumePosition(workloads []discovery.Workload, checkpoint *optipodv1alpha1.ReconcileCheckpoint, order string) int {
+	if checkpoint == nil {
+		return 0
+	}
+	if order == optipodv1alpha1.ApplyOrderAlphaByName {
+		return sort.Search(len(workloads), func(i int) bool { return !processedBefore(&workloads[i], checkpoint) })
+	}
+	for i, workload := range workloads {
+		if workload.Namespace == checkpoint.LastNamespace && workload.Name == checkpoint.LastName && workload.Kind == checkpoint.LastKind {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// processedBefore reports whether the workload was processed before the checkpoint was saved.
+// It follows the namespace, name and kind order workloads are discovered in.
+func processedBefore(workload *discovery.Workload, checkpoint *optipodv1alpha1.ReconcileCheckpoint) bool {
+	if workload.Namespace != checkpoint.LastNamespace {
+		return workload.Namespace < checkpoint.LastNamespace
+	}
+	if workload.Name != checkpoint.LastName {
+		return workload.Name < checkpoint.LastName
+	}
+	return workload.Kind <= checkpoint.LastKind
+}
+
+// resume seeds the summary with the outcome counted before the checkpoint was saved
+func (s *reconcileSummary) resume(checkpoint *optipodv1alpha1.ReconcileCheckpoint) {
+	s.Processed = checkpoint.Processed
+	s.Applied = checkpoint.Applied
+	s.Skipped = checkpoint.Skipped
+	s.Failed = checkpoint.Failed
+	s.PendingApproval = checkpoint.PendingApproval
+}
+
+// checkpoint records the progress up to and including the last processed workload
+func (s *reconcileSummary) checkpoint(
+	last *discovery.Workload,
+	pol *optipodv1alpha1.OptimizationPolicy,
+	startedAt metav1.Time,
+) *optipodv1alpha1.ReconcileCheckpoint {
+	return &optipodv1alpha1.ReconcileCheckpoint{
+		LastNamespace:    last.Namespace,
+		LastName:         last.Name,
+		LastKind:         last.Kind,
+		PolicyGeneration: pol.Generation,
+		StartedAt:        startedAt,
+		Processed:        s.Processed,
+		Applied:          s.Applied,
+		Skipped:          s.Skipped,
+		Failed:           s.Failed,
+		PendingApproval:  s.PendingApproval,
+	}
+}
+
+// saveCheckpoint stores the progress of a reconciliation that ran out of its time budget, along
+// with the failing workloads recorded so far
+func (r *OptimizationPolicyReconciler) saveCheckpoint(
+	ctx context.Context,
+	pol *optipodv1alpha1.OptimizationPolicy,
+	summary *reconcileSummary,
+) error {
+	return r.updateStatusWithRetry(ctx, pol, "checkpoint", func(latest *optipodv1alpha1.OptimizationPolicy) bool {
+		latest.Status.Checkpoint = summary.Checkpoint
+		latest.Status.FailingWorkloads = summary.FailingWorkloads
+		return true
+	})
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// workloadRecordingApplicationEngine records the workloads it applied changes to
+type workloadRecordingApplicationEngine struct {
+	recordingApplicationEngine
+	appliedWorkloads []string
+}
+
+func (m *workloadRecordingApplicationEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+	m.appliedWorkloads = append(m.appliedWorkloads, workload.Name)
+	return m.recordingApplicationEngine.Apply(ctx, workload, changes, policy)
+}
+
+func TestReconcile_TimeBudgetCheckpointsAndResumes(t *testing.T) {
+	ctx := context.Background()
+	const deployments = 5
+
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	appEngine := &workloadRecordingApplicationEngine{}
+	objects := append(createTestObjects(deployments), policy)
+	reconciler, fakeClient := createTestReconciler(appEngine, objects...)
+	provider := &slowMetricsProvider{delay: 20 * time.Millisecond}
+	reconciler.WorkloadProcessor = NewWorkloadProcessor(provider, recommendation.NewEngine(), appEngine, nil)
+	reconciler.ReconcileBudget = 30 * time.Millisecond
+
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)}
+	stored := &optipodv1alpha1.OptimizationPolicy{}
+
+	// The first reconciliation runs out of budget and checkpoints its progress
+	result, err := reconciler.Reconcile(ctx, request)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != checkpointRequeueDelay {
+		t.Errorf("RequeueAfter = %v, want %v to continue promptly", result.RequeueAfter, checkpointRequeueDelay)
+	}
+	if err := fakeClient.Get(ctx, request.NamespacedName, stored); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	checkpoint := stored.Status.Checkpoint
+	if checkpoint == nil {
+		t.Fatal("no checkpoint saved after the time budget ran out")
+	}
+	firstPass := len(appEngine.appliedWorkloads)
+	if firstPass == 0 || firstPass == deployments {
+		t.Fatalf("first reconciliation applied %d of %d workloads, want partial progress", firstPass, deployments)
+	}
+	if want := appEngine.appliedWorkloads[firstPass-1]; checkpoint.LastName != want || checkpoint.Applied != firstPass {
+		t.Errorf("checkpoint = %+v, want last workload %s with %d applied", checkpoint, want, firstPass)
+	}
+
+	// Following reconciliations resume after the checkpoint until every workload is processed
+	for i := 0; i < deployments && stored.Status.Checkpoint != nil; i++ {
+		if _, err := reconciler.Reconcile(ctx, request); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if err := fakeClient.Get(ctx, request.NamespacedName, stored); err != nil {
+			t.Fatalf("failed to get policy: %v", err)
+		}
+	}
+	if stored.Status.Checkpoint != nil {
+		t.Fatalf("checkpoint = %+v, want it cleared once every workload is processed", stored.Status.Checkpoint)
+	}
+
+	// Every workload was applied exactly once, in order
+	for i, name := range appEngine.appliedWorkloads {
+		if want := fmt.Sprintf("%s-%d", TestWorkloadName, i); name != want || i >= deployments {
+			t.Fatalf("applied workloads = %v, want each of the %d workloads once in order", appEngine.appliedWorkloads, deployments)
+		}
+	}
+	if len(appEngine.appliedWorkloads) != deployments {
+		t.Fatalf("applied workloads = %v, want each of the %d workloads once", appEngine.appliedWorkloads, deployments)
+	}
+	if stored.Status.WorkloadsApplied != deployments {
+		t.Errorf("workloadsApplied = %d, want %d counted across the resumed reconciliations",
+			stored.Status.WorkloadsApplied, deployments)
+	}
+}
+
+func TestResumeCheckpoint_DiscardsOtherGeneration(t *testing.T) {
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Generation = 3
+	policy.Status.Checkpoint = &optipodv1alpha1.ReconcileCheckpoint{
+		LastNamespace:    TestNamespace,
+		LastName:         TestWorkloadName,
+		LastKind:         KindDeployment,
+		PolicyGeneration: 2,
+		StartedAt:        metav1.Now(),
+	}
+	if checkpoint := resumeCheckpoint(policy); checkpoint != nil {
+		t.Errorf("resumeCheckpoint() = %+v, want nil after the policy changed", checkpoint)
+	}
+
+	policy.Status.Checkpoint.PolicyGeneration = 3
+	if checkpoint := resumeCheckpoint(policy); checkpoint == nil {
+		t.Error("resumeCheckpoint() = nil, want the checkpoint of the current generation")
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/metrics"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// slowMetricsProvider simulates metrics backend latency and is safe for concurrent use
+type slowMetricsProvider struct {
+	delay time.Duration
+	calls atomic.Int64
+}
+
+func (m *slowMetricsProvider) GetContainerMetrics(ctx context.Context, namespace, podName, containerName string, window time.Duration) (*metrics.ContainerMetrics, error) {
+	m.calls.Add(1)
+	time.Sleep(m.delay)
+	return createTestMetricsProvider().metricsToReturn, nil
+}
+
+func (m *slowMetricsProvider) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// gatedMetricsProvider holds every metrics call until waitFor calls are in flight at once, and
+// records the most calls that were. Calls give up waiting after a while, so a reconciler that
+// never reaches waitFor concurrent calls fails the test instead of hanging it.
+type gatedMetricsProvider struct {
+	waitFor     int64
+	release     chan struct{}
+	once        sync.Once
+	inFlight    atomic.Int64
+	maxInFlight atomic.Int64
+}
+
+func (m *gatedMetricsProvider) GetContainerMetrics(ctx context.Context, namespace, podName, containerName string, window time.Duration) (*metrics.ContainerMetrics, error) {
+	inFlight := m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+	for {
+		peak := m.maxInFlight.Load()
+		if inFlight <= peak || m.maxInFlight.CompareAndSwap(peak, inFlight) {
+			break
+		}
+	}
+	if inFlight >= m.waitFor {
+		m.once.Do(func() { close(m.release) })
+	}
+
+	select {
+	case <-m.release:
+	case <-time.After(10 * time.Second):
+	}
+	return createTestMetricsProvider().metricsToReturn, nil
+}
+
+func (m *gatedMetricsProvider) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// countingApplicationEngine counts applied containers and is safe for concurrent use
+type countingApplicationEngine struct {
+	applied atomic.Int64
+}
+
+func (m *countingApplicationEngine) CanApply(ctx context.Context, workload *application.Workload, containerName string, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyDecision, error) {
+	return &application.ApplyDecision{CanApply: true, Method: application.InPlace, Reason: "Test decision"}, nil
+}
+
+func (m *countingApplicationEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+	m.applied.Add(int64(len(changes)))
+	return &application.ApplyResult{Method: "ServerSideApply", FieldOwnership: true}, nil
+}
+
+// newConcurrencyTestReconciler builds a reconciler with one policy and one deployment per namespace
+func newConcurrencyTestReconciler(policies int, delay time.Duration) (*OptimizationPolicyReconciler, *countingApplicationEngine, []ctrl.Request) {
+	objects := make([]client.Object, 0, policies*3)
+	requests := make([]ctrl.Request, 0, policies)
+
+	for i := 0; i < policies; i++ {
+		namespace := fmt.Sprintf("team-%d", i)
+		objects = append(objects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+
+		deployment := createTestWorkload(TestContainerName).Object.(*appsv1.Deployment)
+		deployment.Namespace = namespace
+		objects = append(objects, deployment)
+
+		policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+		policy.Name = fmt.Sprintf("policy-%d", i)
+		policy.Namespace = namespace
+		policy.Spec.Selector.Namespaces = &optipodv1alpha1.NamespaceFilter{Allow: []string{namespace}}
+		objects = append(objects, policy)
+
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: policy.Name, Namespace: namespace}})
+	}
+
+	appEngine := &countingApplicationEngine{}
+	reconciler, _ := createTestReconciler(appEngine, objects...)
+	reconciler.Recorder = record.NewFakeRecorder(1000)
+	reconciler.WorkloadProcessor = NewWorkloadProcessor(&slowMetricsProvider{delay: delay}, recommendation.NewEngine(), appEngine, nil)
+
+	return reconciler, appEngine, requests
+}
+
+// reconcileWithWorkers reconciles all requests using the given number of parallel workers,
+// mirroring how controller-runtime dispatches MaxConcurrentReconciles workers
+func reconcileWithWorkers(reconciler *OptimizationPolicyReconciler, requests []ctrl.Request, workers int) error {
+	queue := make(chan ctrl.Request, len(requests))
+	for _, req := range requests {
+		queue <- req
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(requests))
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range queue {
+				if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+					errs <- fmt.Errorf("reconcile %s: %w", req.NamespacedName, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+func TestReconcile_ConcurrentPolicies(t *testing.T) {
+	const policies = 8
+
+	reconciler, appEngine, requests := newConcurrencyTestReconciler(policies, time.Millisecond)
+
+	if err := reconcileWithWorkers(reconciler, requests, 4); err != nil {
+		t.Fatalf("concurrent reconcile failed: %v", err)
+	}
+
+	// Every policy applied its own deployment exactly once
+	if got := appEngine.applied.Load(); got != policies {
+		t.Errorf("applied %d containers, want %d", got, policies)
+	}
+
+	for _, req := range requests {
+		pol := &optipodv1alpha1.OptimizationPolicy{}
+		if err := reconciler.Get(context.Background(), req.NamespacedName, pol); err != nil {
+			t.Fatalf("failed to get policy %s: %v", req.NamespacedName, err)
+		}
+		if pol.Status.WorkloadsProcessed != 1 {
+			t.Errorf("policy %s processed %d workloads, want 1", req.Name, pol.Status.WorkloadsProcessed)
+		}
+	}
+}
+
+func TestReconcile_WorkersOverlap(t *testing.T) {
+	const policies = 8
+	const workers = 4
+
+	reconciler, appEngine, requests := newConcurrencyTestReconciler(policies, 0)
+	gate := &gatedMetricsProvider{waitFor: workers, release: make(chan struct{})}
+	reconciler.WorkloadProcessor = NewWorkloadProcessor(gate, recommendation.NewEngine(), appEngine, nil)
+
+	if err := reconcileWithWorkers(reconciler, requests, workers); err != nil {
+		t.Fatalf("concurrent reconcile failed: %v", err)
+	}
+
+	// Metrics collection only proceeds once every worker is collecting at the same time, which
+	// a reconciler serialising policies never reaches
+	if got := gate.maxInFlight.Load(); got != workers {
+		t.Errorf("at most %d reconciles collected metrics at once, want %d", got, workers)
+	}
+	if got := appEngine.applied.Load(); got != policies {
+		t.Errorf("applied %d containers, want %d", got, policies)
+	}
+}
+
+func BenchmarkReconcile_ConcurrentPolicies(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				reconciler, _, requests := newConcurrencyTestReconciler(16, 5*time.Millisecond)
+				b.StartTimer()
+
+				if err := reconcileWithWorkers(reconciler, requests, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -2,10 +2,19 @@ package controller
 
 // Status constants
 const (
-	StatusSkipped     = "Skipped"
-	StatusError       = "Error"
-	StatusRecommended = "Recommended"
-	StatusApplied     = "Applied"
+	StatusSkipped         = "Skipped"
+	StatusError           = "Error"
+	StatusRecommended     = "Recommended"
+	StatusApplied         = "Applied"
+	StatusPendingApproval = "PendingApproval"
+	StatusSuspicious      = "Suspicious"
+	StatusRollingOut      = "RollingOut"
+	// Decreases held back by updateStrategy.stableDecreaseObservations
+	StatusAwaitingStableDecrease = "AwaitingStableDecrease"
+	// Changes deferred until the workload's own rollout has finished
+	StatusAwaitingStableRollout = "AwaitingStableRollout"
+	// Changes held until the metrics backend is ready after operator startup
+	StatusAwaitingMetricsReady = "AwaitingMetricsReady"
 )
 
 // Workload kind constants
@@ -17,7 +26,14 @@ const (
 
 // Condition type constants
 const (
-	ConditionTypeReady = "Ready"
+	ConditionTypeReady                     = "Ready"
+	ConditionTypeTooManyWorkloads          = "TooManyWorkloads"
+	ConditionTypeInformationalQueriesValid = "InformationalQueriesValid"
+	ConditionTypeApplyFailed               = "ApplyFailed"
+	ConditionTypeSuspiciousRecommendation  = "SuspiciousRecommendation"
+	ConditionTypeOptimizationPaused        = "OptimizationPaused"
+	ConditionTypeMetricsNotFound           = "MetricsNotFound"
+	ConditionTypeMetricsWarmingUp          = "MetricsWarmingUp"
 )
 
 // Test constants
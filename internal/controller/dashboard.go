@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/dashboard"
+	"github.com/optipod/optipod/internal/discovery"
+)
+
+// recordDashboard keeps the result of processing a workload for the dashboard API, along with
+// the change when one was applied
+func (wp *WorkloadProcessor) recordDashboard(
+	workload *discovery.Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	status *optipodv1alpha1.WorkloadStatus,
+) {
+	if wp.dashboardStore == nil || status == nil {
+		return
+	}
+
+	// Current requests are those of the pod template the workload was processed with
+	current := make(map[string]corev1.ResourceList)
+	if containers, err := wp.getContainers(workload, policy); err == nil {
+		for _, container := range containers {
+			current[container.Name] = container.Resources.Requests
+		}
+	}
+
+	ref := dashboard.PolicyRef{Namespace: policy.Namespace, Name: policy.Name}
+	replicas := workloadPods(workload)
+	applied := status.Status == StatusApplied
+
+	view := dashboard.Workload{
+		Policy:             ref,
+		Kind:               workload.Kind,
+		Namespace:          workload.Namespace,
+		Name:               workload.Name,
+		Replicas:           replicas,
+		Status:             status.Status,
+		Reason:             status.Reason,
+		StabilityScore:     status.StabilityScore,
+		Containers:         make([]dashboard.Container, 0, len(status.Recommendations)),
+		Savings:            dashboard.NewSavings(),
+		LastRecommendation: status.LastRecommendation,
+		LastApplied:        status.LastApplied,
+	}
+	var changes []dashboard.ContainerChange
+	for _, rec := range status.Recommendations {
+		container := dashboard.Container{
+			Name:           rec.Container,
+			Current:        requestedResources(current[rec.Container]),
+			Recommended:    dashboard.Resources{CPU: rec.CPU, Memory: rec.Memory},
+			Converging:     rec.Converging,
+			Explanation:    rec.Explanation,
+			ClampedToBound: rec.ClampedToBound,
+			DataQuality:    rec.DataQuality,
+			Accuracy:       rec.Accuracy,
+		}
+		if applied {
+			// Without a convergence rate the recommendation is applied in full
+			to := container.Recommended
+			if rec.AppliedCPU != nil {
+				to.CPU = rec.AppliedCPU
+			}
+			if rec.AppliedMemory != nil {
+				to.Memory = rec.AppliedMemory
+			}
+			container.Applied = &to
+			changes = append(changes, dashboard.ContainerChange{Name: rec.Container, From: container.Current, To: to})
+		}
+
+		if container.Current.CPU != nil && rec.CPU != nil {
+			saved := container.Current.CPU.MilliValue() - rec.CPU.MilliValue()
+			view.Savings.CPU.Add(*resource.NewMilliQuantity(saved*replicas, resource.DecimalSI))
+		}
+		if container.Current.Memory != nil && rec.Memory != nil {
+			saved := container.Current.Memory.Value() - rec.Memory.Value()
+			view.Savings.Memory.Add(*resource.NewQuantity(saved*replicas, resource.BinarySI))
+		}
+		view.Containers = append(view.Containers, container)
+	}
+
+	wp.dashboardStore.Record(view)
+	if applied {
+		wp.dashboardStore.RecordChange(dashboard.Change{
+			Policy:     ref,
+			Kind:       workload.Kind,
+			Namespace:  workload.Namespace,
+			Name:       workload.Name,
+			Containers: changes,
+		})
+	}
+}
+
+// requestedResources returns the CPU and memory of a container's requests
+func requestedResources(requests corev1.ResourceList) dashboard.Resources {
+	var resources dashboard.Resources
+	if cpu, ok := requests[corev1.ResourceCPU]; ok {
+		resources.CPU = &cpu
+	}
+	if memory, ok := requests[corev1.ResourceMemory]; ok {
+		resources.Memory = &memory
+	}
+	return resources
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/dashboard"
+)
+
+func TestProcessWorkload_RecordsDashboard(t *testing.T) {
+	workload := createTestWorkload(TestContainerName)
+	deployment := workload.Object.(*appsv1.Deployment)
+	deployment.Spec.Replicas = ptr.To(int32(2))
+	deployment.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+
+	store := dashboard.NewStore(time.Hour, 10)
+	processor := createTestProcessor(&recordingApplicationEngine{}, nil)
+	processor.SetDashboardStore(store)
+
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	if _, err := processor.ProcessWorkload(context.Background(), workload, policy); err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	workloads := store.Workloads()
+	if len(workloads) != 1 {
+		t.Fatalf("recorded %d workloads, want 1", len(workloads))
+	}
+	got := workloads[0]
+	if got.Policy.Name != policy.Name || got.Status != StatusApplied || got.Replicas != 2 || len(got.Containers) != 1 {
+		t.Fatalf("recorded workload = %+v, want the applied workload", got)
+	}
+	container := got.Containers[0]
+	if container.Current.CPU.Cmp(resource.MustParse("500m")) != 0 || container.Recommended.CPU.Cmp(resource.MustParse("240m")) != 0 ||
+		container.Applied == nil || container.Applied.CPU.Cmp(resource.MustParse("240m")) != 0 {
+		t.Errorf("container = %+v, want 500m current and 240m recommended and applied", container)
+	}
+	if quality := container.DataQuality; quality == nil || quality.CPU == nil || quality.CPU.Samples != 100 ||
+		quality.CPU.PercentileValue.Cmp(resource.MustParse("200m")) != 0 {
+		t.Errorf("data quality = %+v, want the 100 samples and P90 the recommendation was computed from", quality)
+	}
+
+	// Savings are counted across both replicas
+	if got.Savings.CPU.Cmp(resource.MustParse("520m")) != 0 {
+		t.Errorf("CPU savings = %s, want 520m", got.Savings.CPU.String())
+	}
+	if want := (int64(1073741824) - 322122547) * 2; got.Savings.Memory.Value() != want {
+		t.Errorf("memory savings = %d, want %d", got.Savings.Memory.Value(), want)
+	}
+
+	history := store.History()
+	if len(history) != 1 || len(history[0].Containers) != 1 || history[0].Containers[0].From.CPU.Cmp(resource.MustParse("500m")) != 0 {
+		t.Errorf("history = %+v, want the applied change from 500m", history)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+const (
+	// podReasonEvicted is the pod status reason the kubelet sets on pods it evicted
+	podReasonEvicted = "Evicted"
+
+	// disruptionReasonTerminationByKubelet is the reason of the DisruptionTarget condition the
+	// kubelet adds to a pod it is about to evict for node pressure
+	disruptionReasonTerminationByKubelet = "TerminationByKubelet"
+
+	// memoryPressureMessage is part of the kubelet's eviction message when the node ran low on memory
+	memoryPressureMessage = "low on resource: memory"
+)
+
+// countMemoryEvictions returns the number of pods evicted, or being evicted, by the kubelet
+// because their node ran low on memory after since. Evicted pods stay around until they are
+// garbage collected, so evictions from before the workload's last applied change, which already
+// raised memory for them, are left out. A zero since counts every eviction.
+func countMemoryEvictions(pods []corev1.Pod, since time.Time) int32 {
+	var evictions int32
+	for _, pod := range pods {
+		if evictedAt, ok := memoryEvictedAt(pod); ok && (since.IsZero() || evictedAt.After(since)) {
+			evictions++
+		}
+	}
+	return evictions
+}
+
+// memoryEvictedAt reports whether the kubelet evicted the pod, or marked it for eviction, for node
+// memory pressure, and when. The time is zero when the pod does not tell.
+func memoryEvictedAt(pod corev1.Pod) (time.Time, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.DisruptionTarget && condition.Status == corev1.ConditionTrue &&
+			condition.Reason == disruptionReasonTerminationByKubelet &&
+			strings.Contains(condition.Message, memoryPressureMessage) {
+			return condition.LastTransitionTime.Time, true
+		}
+	}
+	if pod.Status.Reason == podReasonEvicted && strings.Contains(pod.Status.Message, memoryPressureMessage) {
+		return podStoppedAt(pod), true
+	}
+	return time.Time{}, false
+}
+
+// podStoppedAt returns the latest time a condition of the pod changed or one of its containers
+// terminated, which for an evicted pod is when it was evicted
+func podStoppedAt(pod corev1.Pod) time.Time {
+	var stopped time.Time
+	for _, condition := range pod.Status.Conditions {
+		if condition.LastTransitionTime.After(stopped) {
+			stopped = condition.LastTransitionTime.Time
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.FinishedAt.After(stopped) {
+			stopped = terminated.FinishedAt.Time
+		}
+	}
+	return stopped
+}
+
+// lastAppliedAt returns when optipod last changed the workload, or the zero time if it never did
+func lastAppliedAt(annotations map[string]string) time.Time {
+	appliedAt, err := time.Parse(time.RFC3339, annotations[optipodv1alpha1.AnnotationLastApplied])
+	if err != nil {
+		return time.Time{}
+	}
+	return appliedAt
+}
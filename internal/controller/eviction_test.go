@@ -0,0 +1,184 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/observability"
+)
+
+const testMemoryEvictionMessage = "The node was low on resource: memory. Threshold quantity: 100Mi, available: 50Mi. " +
+	"Container app was using 600Mi, request is 256Mi, has larger consumption of memory."
+
+func TestCountMemoryEvictions(t *testing.T) {
+	tests := []struct {
+		name   string
+		status corev1.PodStatus
+		want   int32
+	}{
+		{
+			name:   "running pod",
+			status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		{
+			name:   "evicted for memory",
+			status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", Message: testMemoryEvictionMessage},
+			want:   1,
+		},
+		{
+			name: "evicted for disk",
+			status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted",
+				Message: "The node was low on resource: ephemeral-storage."},
+		},
+		{
+			name: "about to be evicted for memory",
+			status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{
+				Type:    corev1.DisruptionTarget,
+				Status:  corev1.ConditionTrue,
+				Reason:  "TerminationByKubelet",
+				Message: testMemoryEvictionMessage,
+			}}},
+			want: 1,
+		},
+		{
+			name: "disrupted by preemption",
+			status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{
+				Type:   corev1.DisruptionTarget,
+				Status: corev1.ConditionTrue,
+				Reason: "PreemptionByScheduler",
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pods := []corev1.Pod{{Status: corev1.PodStatus{Phase: corev1.PodRunning}}, {Status: tt.status}}
+			if got := countMemoryEvictions(pods, time.Time{}); got != tt.want {
+				t.Errorf("countMemoryEvictions() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountMemoryEvictions_SinceLastApplied(t *testing.T) {
+	applied := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	evicted := func(at time.Time) corev1.Pod {
+		return corev1.Pod{Status: corev1.PodStatus{
+			Phase:   corev1.PodFailed,
+			Reason:  "Evicted",
+			Message: testMemoryEvictionMessage,
+			Conditions: []corev1.PodCondition{{
+				Type:               corev1.PodReady,
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: metav1.NewTime(at),
+			}},
+		}}
+	}
+	markedForEviction := corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{
+		Type:               corev1.DisruptionTarget,
+		Status:             corev1.ConditionTrue,
+		Reason:             "TerminationByKubelet",
+		Message:            testMemoryEvictionMessage,
+		LastTransitionTime: metav1.NewTime(applied.Add(time.Minute)),
+	}}}}
+
+	pods := []corev1.Pod{evicted(applied.Add(-time.Hour)), evicted(applied.Add(time.Minute)), markedForEviction}
+	if got := countMemoryEvictions(pods, applied); got != 2 {
+		t.Errorf("countMemoryEvictions() = %d, want the 2 evictions after the last apply", got)
+	}
+	if got := countMemoryEvictions(pods[:1], applied); got != 0 {
+		t.Errorf("countMemoryEvictions() = %d, want an eviction before the last apply left out", got)
+	}
+}
+
+func TestProcessWorkload_EvictionPressure(t *testing.T) {
+	// The test metrics score 22, below the minimum, so only evictions get the workload applied
+	tests := []struct {
+		name        string
+		evicted     bool
+		wantStatus  string
+		wantMemory  string
+		wantApplied int
+	}{
+		{name: "no evictions", wantStatus: StatusRecommended, wantMemory: "322122547"},
+		{name: "evicted for memory", evicted: true, wantStatus: StatusApplied, wantMemory: "640Mi", wantApplied: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload := createTestWorkload(TestContainerName)
+			deployment := workload.Object.(*appsv1.Deployment)
+			deployment.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			}
+			pod := createTestPod(TestPodName)
+			if tt.evicted {
+				pod.Status = corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", Message: testMemoryEvictionMessage}
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(deployment.DeepCopy(), pod).Build()
+
+			policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+			policy.Spec.MinStabilityScore = ptr.To[int32](50)
+			appEngine := &recordingApplicationEngine{}
+			recorder := record.NewFakeRecorder(10)
+			processor := createTestProcessor(appEngine, fakeClient)
+			processor.SetEventRecorder(observability.NewEventRecorder(recorder))
+
+			status, err := processor.ProcessWorkload(context.Background(), workload, policy)
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			if status.Status != tt.wantStatus || len(appEngine.appliedContainers) != tt.wantApplied {
+				t.Errorf("status = %s (%s) with applied %v, want %s with %d applied",
+					status.Status, status.Reason, appEngine.appliedContainers, tt.wantStatus, tt.wantApplied)
+			}
+			rec := findRecommendation(status.Recommendations, TestContainerName)
+			if rec == nil || rec.Memory == nil {
+				t.Fatalf("no memory recommendation for %s: %+v", TestContainerName, status.Recommendations)
+			}
+			if want := resource.MustParse(tt.wantMemory); rec.Memory.Cmp(want) != 0 {
+				t.Errorf("memory recommendation = %s, want %s", rec.Memory.String(), tt.wantMemory)
+			}
+
+			gotEvent := false
+			for len(recorder.Events) > 0 {
+				if strings.Contains(<-recorder.Events, observability.EventReasonEvictionPressure) {
+					gotEvent = true
+				}
+			}
+			if gotEvent != tt.evicted {
+				t.Errorf("%s event recorded = %v, want %v", observability.EventReasonEvictionPressure, gotEvent, tt.evicted)
+			}
+			if tt.evicted && !strings.Contains(status.Reason, observability.EventReasonEvictionPressure) {
+				t.Errorf("reason = %q, want it to name %s", status.Reason, observability.EventReasonEvictionPressure)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// gitOpsMarkers are the prefixes of the label and annotation keys GitOps tools set on the
+// objects they sync, with the name of the tool. Argo CD's default label tracking, the
+// app.kubernetes.io/instance label, is not one of them, as Helm sets it too.
+var gitOpsMarkers = []struct {
+	prefix string
+	tool   string
+}{
+	{prefix: "argocd.argoproj.io/", tool: "Argo CD"},
+	{prefix: "kustomize.toolkit.fluxcd.io/", tool: "Flux"},
+	{prefix: "helm.toolkit.fluxcd.io/", tool: "Flux"},
+}
+
+// gitOpsManager returns the GitOps tool that manages the object and the annotation or label it
+// was recognized by, e.g. "annotation argocd.argoproj.io/tracking-id". Both are empty when no
+// GitOps tool manages the object.
+func gitOpsManager(obj metav1.Object) (tool, marker string) {
+	if obj == nil {
+		return "", ""
+	}
+	for _, source := range []struct {
+		kind string
+		keys map[string]string
+	}{
+		{kind: "annotation", keys: obj.GetAnnotations()},
+		{kind: "label", keys: obj.GetLabels()},
+	} {
+		for _, key := range slices.Sorted(maps.Keys(source.keys)) {
+			for _, m := range gitOpsMarkers {
+				if strings.HasPrefix(key, m.prefix) {
+					return m.tool, source.kind + " " + key
+				}
+			}
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+func TestGitOpsManager(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		labels      map[string]string
+		wantTool    string
+		wantMarker  string
+	}{
+		{name: "unmanaged", labels: map[string]string{"app": "web"}},
+		{
+			name:        "Argo CD annotation tracking",
+			annotations: map[string]string{"argocd.argoproj.io/tracking-id": "web:apps/Deployment:prod/web"},
+			wantTool:    "Argo CD",
+			wantMarker:  "annotation argocd.argoproj.io/tracking-id",
+		},
+		{
+			name:       "Argo CD instance label",
+			labels:     map[string]string{"argocd.argoproj.io/instance": "web"},
+			wantTool:   "Argo CD",
+			wantMarker: "label argocd.argoproj.io/instance",
+		},
+		{
+			name: "Flux Kustomization",
+			labels: map[string]string{
+				"kustomize.toolkit.fluxcd.io/name":      "apps",
+				"kustomize.toolkit.fluxcd.io/namespace": "flux-system",
+			},
+			wantTool:   "Flux",
+			wantMarker: "label kustomize.toolkit.fluxcd.io/name",
+		},
+		{
+			name:       "Flux HelmRelease",
+			labels:     map[string]string{"helm.toolkit.fluxcd.io/name": "web"},
+			wantTool:   "Flux",
+			wantMarker: "label helm.toolkit.fluxcd.io/name",
+		},
+		{
+			name:   "Helm instance label alone",
+			labels: map[string]string{"app.kubernetes.io/instance": "web", "app.kubernetes.io/managed-by": "Helm"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload := createTestWorkload(TestContainerName)
+			workload.Object.SetAnnotations(tt.annotations)
+			workload.Object.SetLabels(tt.labels)
+
+			tool, marker := gitOpsManager(workload.Object)
+			if tool != tt.wantTool || marker != tt.wantMarker {
+				t.Errorf("gitOpsManager() = %q, %q, want %q, %q", tool, marker, tt.wantTool, tt.wantMarker)
+			}
+		})
+	}
+}
+
+func TestProcessWorkload_GitOpsManaged(t *testing.T) {
+	tests := []struct {
+		name        string
+		allow       bool
+		wantStatus  string
+		wantApplied bool
+	}{
+		{name: "recommended by default", wantStatus: StatusRecommended},
+		{name: "applied when the policy opts in", allow: true, wantStatus: StatusApplied, wantApplied: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appEngine := &recordingApplicationEngine{}
+			processor := createTestProcessor(appEngine, nil)
+			policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+			policy.Spec.UpdateStrategy.AllowGitOpsManaged = tt.allow
+
+			workload := createTestWorkload(TestContainerName)
+			workload.Object.SetAnnotations(map[string]string{"argocd.argoproj.io/tracking-id": "web:apps/Deployment:default/web"})
+
+			status, err := processor.ProcessWorkload(context.Background(), workload, policy)
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			if status.Status != tt.wantStatus {
+				t.Errorf("status = %q (%s), want %q", status.Status, status.Reason, tt.wantStatus)
+			}
+			if !tt.allow && !strings.Contains(status.Reason, "managed by Argo CD per annotation argocd.argoproj.io/tracking-id") {
+				t.Errorf("reason = %q, want it to name the GitOps tool and marker", status.Reason)
+			}
+			if applied := len(appEngine.appliedContainers) > 0; applied != tt.wantApplied {
+				t.Errorf("applied = %v, want %v", appEngine.appliedContainers, tt.wantApplied)
+			}
+		})
+	}
+}
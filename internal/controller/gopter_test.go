@@ -80,7 +80,7 @@ type mockApplicationEngine struct {
 	applyResult    *application.ApplyResult
 }
 
-func (m *mockApplicationEngine) CanApply(ctx context.Context, workload *application.Workload, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyDecision, error) {
+func (m *mockApplicationEngine) CanApply(ctx context.Context, workload *application.Workload, containerName string, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyDecision, error) {
 	m.canApplyCalled = true
 	if m.decision != nil {
 		return m.decision, nil
@@ -92,7 +92,7 @@ func (m *mockApplicationEngine) CanApply(ctx context.Context, workload *applicat
 	}, nil
 }
 
-func (m *mockApplicationEngine) Apply(ctx context.Context, workload *application.Workload, containerName string, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+func (m *mockApplicationEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
 	m.applyCalled = true
 	if m.applyError != nil {
 		return nil, m.applyError
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// recordedRecommendation returns the request recommendation last recorded in the workload's
+// annotations for the container resource ("cpu" or "memory")
+func recordedRecommendation(annotations map[string]string, container, resourceName string) (resource.Quantity, bool) {
+	value, ok := annotations[fmt.Sprintf("%s.%s.%s-request", optipodv1alpha1.AnnotationRecommendationPrefix, container, resourceName)]
+	if !ok {
+		return resource.Quantity{}, false
+	}
+	recorded, err := resource.ParseQuantity(value)
+	if err != nil || recorded.Sign() <= 0 {
+		return resource.Quantity{}, false
+	}
+	return recorded, true
+}
+
+// withinBand reports whether value is within percent of recorded, in either direction
+func withinBand(value, recorded resource.Quantity, percent int32) bool {
+	diff := value.MilliValue() - recorded.MilliValue()
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff*100 <= recorded.MilliValue()*int64(percent)
+}
+
+// applyHysteresis keeps the recommendation last recorded for the container for each resource
+// whose new recommendation is within the policy's hysteresis band around it. Recommendations
+// clamped to a bound, and recorded values outside the bounds, are never retained.
+func applyHysteresis(rec *recommendation.Recommendation, annotations map[string]string, container string, policy *optipodv1alpha1.OptimizationPolicy) {
+	hysteresis := policy.Spec.RecommendationHysteresis
+	if hysteresis == nil {
+		return
+	}
+	cpuPercent, memoryPercent := hysteresis.Band()
+	bounds := policy.Spec.ResourceBounds
+
+	retain := func(recommended *resource.Quantity, resourceName string, percent int32, bound optipodv1alpha1.ResourceBound, clamped ...string) {
+		if slices.ContainsFunc(rec.ClampedToBound, func(b string) bool { return slices.Contains(clamped, b) }) {
+			return
+		}
+		recorded, ok := recordedRecommendation(annotations, container, resourceName)
+		if !ok || recorded.Cmp(bound.Min) < 0 || recorded.Cmp(bound.Max) > 0 || recorded.Cmp(*recommended) == 0 ||
+			!withinBand(*recommended, recorded, percent) {
+			return
+		}
+		rec.Explanation += fmt.Sprintf("; %s recommendation %s is within %d%% of the recorded %s, which is kept",
+			resourceName, recommended.String(), percent, recorded.String())
+		*recommended = recorded
+	}
+
+	if policy.OptimizesCPU() {
+		retain(&rec.CPU, "cpu", cpuPercent, bounds.CPU, optipodv1alpha1.BoundCPUMin, optipodv1alpha1.BoundCPUMax)
+	}
+	if policy.OptimizesMemory() {
+		retain(&rec.Memory, "memory", memoryPercent, bounds.Memory, optipodv1alpha1.BoundMemoryMin, optipodv1alpha1.BoundMemoryMax)
+	}
+}
+
+// recommendationAnnotationsUnchanged reports whether updated only differs from the workload's
+// current annotations in the recommendation timestamp and stability score
+func recommendationAnnotationsUnchanged(current, updated map[string]string) bool {
+	volatile := func(key string) bool {
+		return key == optipodv1alpha1.AnnotationLastRecommendation || key == optipodv1alpha1.AnnotationStabilityScore
+	}
+	for key, value := range updated {
+		if !volatile(key) && current[key] != value {
+			return false
+		}
+	}
+	return true
+}
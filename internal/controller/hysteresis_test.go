@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// recordedAnnotations returns the annotations of a recommendation recorded for the test container
+func recordedAnnotations(cpu, memory string) map[string]string {
+	return map[string]string{
+		optipodv1alpha1.AnnotationManaged: "true",
+		optipodv1alpha1.AnnotationPolicy:  "test-policy",
+		optipodv1alpha1.AnnotationRecommendationPrefix + "." + TestContainerName + ".cpu-request":    cpu,
+		optipodv1alpha1.AnnotationRecommendationPrefix + "." + TestContainerName + ".memory-request": memory,
+	}
+}
+
+func TestApplyHysteresis(t *testing.T) {
+	tests := []struct {
+		name       string
+		hysteresis *optipodv1alpha1.RecommendationHysteresis
+		recorded   map[string]string
+		cpu        string
+		memory     string
+		clamped    []string
+		wantCPU    string
+		wantMemory string
+	}{
+		{
+			name:       "within the default band keeps the recorded values",
+			hysteresis: &optipodv1alpha1.RecommendationHysteresis{},
+			recorded:   recordedAnnotations("250m", "300Mi"),
+			cpu:        "240m",
+			memory:     "310Mi",
+			wantCPU:    "250m",
+			wantMemory: "300Mi",
+		},
+		{
+			name:       "outside the band records the new values",
+			hysteresis: &optipodv1alpha1.RecommendationHysteresis{},
+			recorded:   recordedAnnotations("250m", "300Mi"),
+			cpu:        "200m",
+			memory:     "400Mi",
+			wantCPU:    "200m",
+			wantMemory: "400Mi",
+		},
+		{
+			name:       "band width is per resource",
+			hysteresis: &optipodv1alpha1.RecommendationHysteresis{CPUPercent: int32Ptr(0), MemoryPercent: int32Ptr(40)},
+			recorded:   recordedAnnotations("250m", "300Mi"),
+			cpu:        "249m",
+			memory:     "400Mi",
+			wantCPU:    "249m",
+			wantMemory: "300Mi",
+		},
+		{
+			name:       "no hysteresis records the new values",
+			recorded:   recordedAnnotations("250m", "300Mi"),
+			cpu:        "240m",
+			memory:     "310Mi",
+			wantCPU:    "240m",
+			wantMemory: "310Mi",
+		},
+		{
+			name:       "nothing recorded yet",
+			hysteresis: &optipodv1alpha1.RecommendationHysteresis{},
+			cpu:        "240m",
+			memory:     "310Mi",
+			wantCPU:    "240m",
+			wantMemory: "310Mi",
+		},
+		{
+			name:       "a clamped recommendation is not retained",
+			hysteresis: &optipodv1alpha1.RecommendationHysteresis{},
+			recorded:   recordedAnnotations("1950m", "300Mi"),
+			cpu:        "2000m",
+			memory:     "310Mi",
+			clamped:    []string{optipodv1alpha1.BoundCPUMax},
+			wantCPU:    "2000m",
+			wantMemory: "300Mi",
+		},
+		{
+			name:       "a recorded value outside the bounds is not retained",
+			hysteresis: &optipodv1alpha1.RecommendationHysteresis{},
+			recorded:   recordedAnnotations("2010m", "60Mi"),
+			cpu:        "1990m",
+			memory:     "64Mi",
+			wantCPU:    "1990m",
+			wantMemory: "64Mi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createTestPolicy(optipodv1alpha1.ModeRecommend)
+			policy.Spec.RecommendationHysteresis = tt.hysteresis
+			rec := &recommendation.Recommendation{
+				CPU:            resource.MustParse(tt.cpu),
+				Memory:         resource.MustParse(tt.memory),
+				ClampedToBound: tt.clamped,
+			}
+
+			applyHysteresis(rec, tt.recorded, TestContainerName, policy)
+
+			if rec.CPU.Cmp(resource.MustParse(tt.wantCPU)) != 0 || rec.Memory.Cmp(resource.MustParse(tt.wantMemory)) != 0 {
+				t.Errorf("recommendation = %s CPU and %s memory, want %s and %s (%s)",
+					rec.CPU.String(), rec.Memory.String(), tt.wantCPU, tt.wantMemory, rec.Explanation)
+			}
+		})
+	}
+}
+
+func TestProcessWorkload_RecommendationHysteresis(t *testing.T) {
+	// The recommendation is 240m CPU and about 307Mi memory
+	tests := []struct {
+		name           string
+		recorded       map[string]string
+		wantCPU        string
+		wantAnnotation bool
+	}{
+		{
+			name:           "within the band keeps the recorded recommendation without writing",
+			recorded:       recordedAnnotations("250m", "300Mi"),
+			wantCPU:        "250m",
+			wantAnnotation: false,
+		},
+		{
+			name:           "outside the band records the new recommendation",
+			recorded:       recordedAnnotations("100m", "300Mi"),
+			wantCPU:        "240m",
+			wantAnnotation: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			workload := createTestWorkload(TestContainerName)
+			deployment := workload.Object.(*appsv1.Deployment)
+			deployment.Annotations = tt.recorded
+			pod := createTestPod(TestPodName)
+			fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(deployment.DeepCopy(), pod).Build()
+			processor := createTestProcessor(&recordingApplicationEngine{}, fakeClient)
+
+			policy := createTestPolicy(optipodv1alpha1.ModeRecommend)
+			policy.Spec.RecommendationHysteresis = &optipodv1alpha1.RecommendationHysteresis{}
+			status, err := processor.ProcessWorkload(ctx, workload, policy)
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+
+			rec := findRecommendation(status.Recommendations, TestContainerName)
+			if rec == nil || rec.CPU == nil || rec.CPU.Cmp(resource.MustParse(tt.wantCPU)) != 0 {
+				t.Fatalf("recommendation = %+v, want %s CPU", rec, tt.wantCPU)
+			}
+			if rec.Memory == nil || rec.Memory.Cmp(resource.MustParse("300Mi")) != 0 {
+				t.Errorf("memory recommendation = %v, want the recorded 300Mi", rec.Memory)
+			}
+
+			stored := &appsv1.Deployment{}
+			if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), stored); err != nil {
+				t.Fatalf("failed to get deployment: %v", err)
+			}
+			if _, written := stored.Annotations[optipodv1alpha1.AnnotationLastRecommendation]; written != tt.wantAnnotation {
+				t.Errorf("annotations written = %v, want %v", written, tt.wantAnnotation)
+			}
+		})
+	}
+}
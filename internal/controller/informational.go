@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+// errInformationalQueriesUnsupported is returned when the metrics provider cannot evaluate queries
+var errInformationalQueriesUnsupported = errors.New("metrics provider does not support informational queries")
+
+// renderInformationalQuery replaces the workload placeholders of an informational query
+func renderInformationalQuery(query, namespace, name, kind string) string {
+	return strings.NewReplacer(
+		"$namespace", namespace,
+		"$workload", name,
+		"$kind", kind,
+	).Replace(query)
+}
+
+// collectInformationalMetrics evaluates the policy's informational queries for a workload.
+// Failures are reported per query and never affect the recommendation.
+func (wp *WorkloadProcessor) collectInformationalMetrics(
+	ctx context.Context,
+	workload *discovery.Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) []optipodv1alpha1.InformationalMetric {
+	queries := policy.Spec.MetricsConfig.InformationalQueries
+	if len(queries) == 0 {
+		return nil
+	}
+
+	log := logf.FromContext(ctx)
+	querier, supported := wp.metricsProvider.(metrics.InformationalQuerier)
+
+	results := make([]optipodv1alpha1.InformationalMetric, 0, len(queries))
+	for _, query := range queries {
+		result := optipodv1alpha1.InformationalMetric{Name: query.Name}
+
+		if !supported {
+			result.Error = errInformationalQueriesUnsupported.Error()
+			results = append(results, result)
+			continue
+		}
+
+		rendered := renderInformationalQuery(query.Query, workload.Namespace, workload.Name, workload.Kind)
+		value, err := querier.QueryValue(ctx, rendered)
+		if err != nil {
+			log.V(1).Info("Informational query failed",
+				"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+				"query", query.Name,
+				"error", err.Error())
+			result.Error = err.Error()
+		} else {
+			result.Value = strconv.FormatFloat(value, 'g', -1, 64)
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// validateInformationalQueries health checks the metrics backend and verifies it accepts every
+// informational query of the policy. The placeholders are filled with sample values.
+func (wp *WorkloadProcessor) validateInformationalQueries(ctx context.Context, policy *optipodv1alpha1.OptimizationPolicy) error {
+	queries := policy.Spec.MetricsConfig.InformationalQueries
+	if len(queries) == 0 {
+		return nil
+	}
+
+	querier, ok := wp.metricsProvider.(metrics.InformationalQuerier)
+	if !ok {
+		return errInformationalQueriesUnsupported
+	}
+
+	if err := wp.metricsProvider.HealthCheck(ctx); err != nil {
+		return fmt.Errorf("metrics backend is unhealthy: %w", err)
+	}
+
+	var invalid []string
+	for _, query := range queries {
+		rendered := renderInformationalQuery(query.Query, "default", "optipod-validation", KindDeployment)
+		if err := querier.ValidateQuery(ctx, rendered); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", query.Name, err))
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid informational queries: %s", strings.Join(invalid, "; "))
+	}
+
+	return nil
+}
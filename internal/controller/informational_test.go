@@ -0,0 +1,198 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// mockQueryingMetricsProvider answers informational queries with fixed values per query
+type mockQueryingMetricsProvider struct {
+	mockMetricsProvider
+	values  map[string]float64
+	invalid map[string]bool
+	queries []string
+}
+
+func (m *mockQueryingMetricsProvider) QueryValue(ctx context.Context, query string) (float64, error) {
+	m.queries = append(m.queries, query)
+	value, ok := m.values[query]
+	if !ok {
+		return 0, errors.New("query returned no data")
+	}
+	return value, nil
+}
+
+func (m *mockQueryingMetricsProvider) ValidateQuery(ctx context.Context, query string) error {
+	if m.invalid[query] {
+		return errors.New("bad_data: parse error")
+	}
+	return nil
+}
+
+func newInformationalTestProvider() *mockQueryingMetricsProvider {
+	return &mockQueryingMetricsProvider{
+		mockMetricsProvider: *createTestMetricsProvider(),
+		values: map[string]float64{
+			`sum(rate(http_requests_total{namespace="default",deployment="test-workload"}[5m]))`: 12.5,
+		},
+		invalid: map[string]bool{"sum(": true},
+	}
+}
+
+func newInformationalTestPolicy(queries ...optipodv1alpha1.InformationalQuery) *optipodv1alpha1.OptimizationPolicy {
+	policy := createTestPolicy(optipodv1alpha1.ModeRecommend)
+	policy.Spec.MetricsConfig.InformationalQueries = queries
+	return policy
+}
+
+var requestRateQuery = optipodv1alpha1.InformationalQuery{
+	Name:  "request-rate",
+	Query: `sum(rate(http_requests_total{namespace="$namespace",deployment="$workload"}[5m]))`,
+}
+
+func TestCollectInformationalMetrics(t *testing.T) {
+	provider := newInformationalTestProvider()
+	processor := NewWorkloadProcessor(provider, recommendation.NewEngine(), &recordingApplicationEngine{}, nil)
+	policy := newInformationalTestPolicy(requestRateQuery, optipodv1alpha1.InformationalQuery{
+		Name:  "error-rate",
+		Query: `sum(rate(http_errors_total{namespace="$namespace"}[5m]))`,
+	})
+
+	results := processor.collectInformationalMetrics(context.Background(), createTestWorkload(TestContainerName), policy)
+
+	want := []optipodv1alpha1.InformationalMetric{
+		{Name: "request-rate", Value: "12.5"},
+		{Name: "error-rate", Error: "query returned no data"},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("collectInformationalMetrics() = %+v, want %+v", results, want)
+	}
+
+	// Placeholders are replaced with the workload's values
+	if provider.queries[1] != `sum(rate(http_errors_total{namespace="default"}[5m]))` {
+		t.Errorf("query = %s, want the namespace placeholder replaced", provider.queries[1])
+	}
+}
+
+func TestCollectInformationalMetrics_UnsupportedProvider(t *testing.T) {
+	processor := createTestProcessor(&recordingApplicationEngine{}, nil)
+	policy := newInformationalTestPolicy(requestRateQuery)
+
+	results := processor.collectInformationalMetrics(context.Background(), createTestWorkload(TestContainerName), policy)
+	if len(results) != 1 || results[0].Error != errInformationalQueriesUnsupported.Error() {
+		t.Errorf("collectInformationalMetrics() = %+v, want an unsupported provider error", results)
+	}
+}
+
+func TestProcessWorkload_InformationalMetricsDoNotAffectRecommendations(t *testing.T) {
+	ctx := context.Background()
+	workload := createTestWorkload(TestContainerName)
+
+	baseline, err := NewWorkloadProcessor(newInformationalTestProvider(), recommendation.NewEngine(), &recordingApplicationEngine{}, nil).
+		ProcessWorkload(ctx, workload, newInformationalTestPolicy())
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	status, err := NewWorkloadProcessor(newInformationalTestProvider(), recommendation.NewEngine(), &recordingApplicationEngine{}, nil).
+		ProcessWorkload(ctx, workload, newInformationalTestPolicy(requestRateQuery))
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	if len(status.InformationalMetrics) != 1 || status.InformationalMetrics[0].Value != "12.5" {
+		t.Errorf("informational metrics = %+v, want request-rate 12.5", status.InformationalMetrics)
+	}
+	if status.Status != baseline.Status || len(status.Recommendations) != 1 ||
+		!status.Recommendations[0].CPU.Equal(*baseline.Recommendations[0].CPU) ||
+		!status.Recommendations[0].Memory.Equal(*baseline.Recommendations[0].Memory) {
+		t.Errorf("recommendations changed with informational queries: got %+v, want %+v",
+			status.Recommendations, baseline.Recommendations)
+	}
+}
+
+func TestValidateInformationalQueries(t *testing.T) {
+	tests := []struct {
+		name    string
+		queries []optipodv1alpha1.InformationalQuery
+		wantErr string
+	}{
+		{name: "no queries"},
+		{name: "valid query", queries: []optipodv1alpha1.InformationalQuery{requestRateQuery}},
+		{
+			name:    "invalid query",
+			queries: []optipodv1alpha1.InformationalQuery{requestRateQuery, {Name: "broken", Query: "sum("}},
+			wantErr: "broken: bad_data: parse error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := NewWorkloadProcessor(newInformationalTestProvider(), recommendation.NewEngine(), &recordingApplicationEngine{}, nil)
+
+			err := processor.validateInformationalQueries(context.Background(), newInformationalTestPolicy(tt.queries...))
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("validateInformationalQueries() error = %v, want none", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("validateInformationalQueries() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReconcile_InformationalQueriesCondition(t *testing.T) {
+	ctx := context.Background()
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.MetricsConfig.InformationalQueries = []optipodv1alpha1.InformationalQuery{
+		requestRateQuery,
+		{Name: "broken", Query: "sum("},
+	}
+	reconciler, fakeClient := createTestReconciler(&recordingApplicationEngine{}, policy)
+	reconciler.WorkloadProcessor = NewWorkloadProcessor(newInformationalTestProvider(), recommendation.NewEngine(), &recordingApplicationEngine{}, nil)
+
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &optipodv1alpha1.OptimizationPolicy{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), updated); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+
+	condition := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeInformationalQueriesValid)
+	if condition == nil || condition.Reason != "QueriesInvalid" || !strings.Contains(condition.Message, "broken") {
+		t.Errorf("InformationalQueriesValid condition = %+v, want QueriesInvalid naming the broken query", condition)
+	}
+
+	// An invalid informational query does not stop the policy from processing workloads
+	if !meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeReady) {
+		t.Errorf("expected the policy to stay Ready, got %+v", updated.Status.Conditions)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+
+	optipoddiscovery "github.com/optipod/optipod/internal/discovery"
+)
+
+// scaledObjectGVR is the resource of KEDA ScaledObjects
+var scaledObjectGVR = schema.GroupVersionResource{Group: "keda.sh", Version: "v1alpha1", Resource: "scaledobjects"}
+
+// kedaRecheckInterval is how long the presence of the ScaledObject CRD is cached, so installing
+// KEDA after the operator started is picked up without a restart
+const kedaRecheckInterval = 10 * time.Minute
+
+// ScaledObjectFinder finds KEDA ScaledObjects that scale a workload on CPU. It only queries
+// ScaledObjects when the KEDA CRD is installed in the cluster.
+type ScaledObjectFinder struct {
+	dynamicClient   dynamic.Interface
+	discoveryClient discovery.DiscoveryInterface
+
+	mu        sync.Mutex
+	installed bool
+	checkedAt time.Time
+}
+
+// NewScaledObjectFinder creates a finder using the dynamic client for ScaledObjects and the
+// discovery client to detect the KEDA CRD
+func NewScaledObjectFinder(dynamicClient dynamic.Interface, discoveryClient discovery.DiscoveryInterface) *ScaledObjectFinder {
+	return &ScaledObjectFinder{
+		dynamicClient:   dynamicClient,
+		discoveryClient: discoveryClient,
+	}
+}
+
+// FindCPUScaler returns the name of a ScaledObject that targets the workload and has a CPU
+// trigger, or an empty string when there is none or KEDA is not installed
+func (f *ScaledObjectFinder) FindCPUScaler(ctx context.Context, workload *optipoddiscovery.Workload) (string, error) {
+	installed, err := f.kedaInstalled()
+	if err != nil || !installed {
+		return "", err
+	}
+
+	list, err := f.dynamicClient.Resource(scaledObjectGVR).Namespace(workload.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list KEDA ScaledObjects: %w", err)
+	}
+
+	for i := range list.Items {
+		scaledObject := &list.Items[i]
+		if targetsWorkload(scaledObject, workload) && scalesOnCPU(scaledObject) {
+			return scaledObject.GetName(), nil
+		}
+	}
+	return "", nil
+}
+
+// kedaInstalled reports whether the ScaledObject CRD is served, caching the answer for kedaRecheckInterval
+func (f *ScaledObjectFinder) kedaInstalled() (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.checkedAt.IsZero() && time.Since(f.checkedAt) < kedaRecheckInterval {
+		return f.installed, nil
+	}
+
+	resources, err := f.discoveryClient.ServerResourcesForGroupVersion(scaledObjectGVR.GroupVersion().String())
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to detect KEDA: %w", err)
+	}
+
+	f.installed = false
+	if err == nil {
+		for _, resource := range resources.APIResources {
+			if resource.Name == scaledObjectGVR.Resource {
+				f.installed = true
+				break
+			}
+		}
+	}
+	f.checkedAt = time.Now()
+	return f.installed, nil
+}
+
+// targetsWorkload reports whether the ScaledObject's scale target is the workload. KEDA
+// defaults the target kind to Deployment.
+func targetsWorkload(scaledObject *unstructured.Unstructured, workload *optipoddiscovery.Workload) bool {
+	name, _, _ := unstructured.NestedString(scaledObject.Object, "spec", "scaleTargetRef", "name")
+	kind, _, _ := unstructured.NestedString(scaledObject.Object, "spec", "scaleTargetRef", "kind")
+	if kind == "" {
+		kind = KindDeployment
+	}
+	return name == workload.Name && kind == workload.Kind
+}
+
+// scalesOnCPU reports whether any of the ScaledObject's triggers is a CPU trigger
+func scalesOnCPU(scaledObject *unstructured.Unstructured) bool {
+	triggers, _, _ := unstructured.NestedSlice(scaledObject.Object, "spec", "triggers")
+	for _, trigger := range triggers {
+		if t, ok := trigger.(map[string]interface{}); ok && t["type"] == "cpu" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/observability"
+)
+
+// newTestScaledObject returns a ScaledObject targeting the named Deployment with a trigger of the given type
+func newTestScaledObject(name, target, triggerType string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "keda.sh/v1alpha1",
+		"kind":       "ScaledObject",
+		"metadata":   map[string]interface{}{"name": name, "namespace": TestNamespace},
+		"spec": map[string]interface{}{
+			"scaleTargetRef": map[string]interface{}{"name": target},
+			"triggers": []interface{}{
+				map[string]interface{}{"type": triggerType, "metadata": map[string]interface{}{"value": "60"}},
+			},
+		},
+	}}
+}
+
+// createTestCRDClients returns fake clients over the given objects of a custom resource, whose
+// CRD the discovery client serves only when installed is true
+func createTestCRDClients(
+	gvr schema.GroupVersionResource,
+	kind string,
+	installed bool,
+	objects ...runtime.Object,
+) (*fakedynamic.FakeDynamicClient, *fakediscovery.FakeDiscovery) {
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: kind + "List"}, objects...)
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	if installed {
+		discoveryClient.Resources = []*metav1.APIResourceList{{
+			GroupVersion: gvr.GroupVersion().String(),
+			APIResources: []metav1.APIResource{{Name: gvr.Resource, Kind: kind, Namespaced: true}},
+		}}
+	}
+	return dynamicClient, discoveryClient
+}
+
+// newTestScaledObjectFinder returns a finder over the given ScaledObjects, with the KEDA CRD
+// served only when installed is true
+func newTestScaledObjectFinder(installed bool, scaledObjects ...runtime.Object) *ScaledObjectFinder {
+	return NewScaledObjectFinder(createTestCRDClients(scaledObjectGVR, "ScaledObject", installed, scaledObjects...))
+}
+
+func TestProcessWorkload_KEDACPUScaler(t *testing.T) {
+	tests := []struct {
+		name          string
+		installed     bool
+		scaledObjects []runtime.Object
+		wantCPU       string
+		wantEvent     bool
+	}{
+		{
+			name:          "a CPU trigger holds the CPU request",
+			installed:     true,
+			scaledObjects: []runtime.Object{newTestScaledObject("test-scaler", TestWorkloadName, "cpu")},
+			wantCPU:       "1",
+			wantEvent:     true,
+		},
+		{
+			name:          "other triggers allow lowering",
+			installed:     true,
+			scaledObjects: []runtime.Object{newTestScaledObject("test-scaler", TestWorkloadName, "prometheus")},
+			wantCPU:       "240m",
+		},
+		{
+			name:          "a ScaledObject for another workload is ignored",
+			installed:     true,
+			scaledObjects: []runtime.Object{newTestScaledObject("test-scaler", "other-workload", "cpu")},
+			wantCPU:       "240m",
+		},
+		{
+			name:    "KEDA not installed",
+			wantCPU: "240m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload := createTestWorkload(TestContainerName)
+			deployment := workload.Object.(*appsv1.Deployment)
+			deployment.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			processor := createTestProcessor(&recordingApplicationEngine{}, nil)
+			processor.SetEventRecorder(observability.NewEventRecorder(recorder))
+			processor.SetScaledObjectFinder(newTestScaledObjectFinder(tt.installed, tt.scaledObjects...))
+
+			status, err := processor.ProcessWorkload(context.Background(), workload, createTestPolicy(optipodv1alpha1.ModeRecommend))
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			rec := findRecommendation(status.Recommendations, TestContainerName)
+			if rec == nil || rec.CPU == nil {
+				t.Fatalf("no CPU recommendation for %s: %+v", TestContainerName, status.Recommendations)
+			}
+			if want := resource.MustParse(tt.wantCPU); rec.CPU.Cmp(want) != 0 {
+				t.Errorf("CPU recommendation = %s, want %s", rec.CPU.String(), tt.wantCPU)
+			}
+			if held := strings.Contains(rec.Explanation, "test-scaler"); held != tt.wantEvent {
+				t.Errorf("explanation %q mentions the ScaledObject = %v, want %v", rec.Explanation, held, tt.wantEvent)
+			}
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			gotEvent := false
+			for _, event := range events {
+				if strings.Contains(event, observability.EventReasonAutoscalerConstraint) {
+					gotEvent = true
+				}
+			}
+			if gotEvent != tt.wantEvent {
+				t.Errorf("events = %v, want an %s event = %v", events, observability.EventReasonAutoscalerConstraint, tt.wantEvent)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/optipod/optipod/internal/observability"
+)
+
+// ErrNotLeader is returned for a write attempted by a replica that does not hold the leader lease
+var ErrNotLeader = errors.New("not the leader: write rejected")
+
+// LeaderTracker tracks whether this replica holds the leader lease. Status updates, annotation
+// writes and applies are gated on it, so a former leader stops writing as soon as the manager
+// starts stopping, before its reconcilers have returned.
+type LeaderTracker struct {
+	elected <-chan struct{}
+	lost    atomic.Bool
+}
+
+// NewLeaderTracker creates a tracker for the manager's election channel (mgr.Elected()). Without
+// leader election the channel is closed at startup, so every write is allowed.
+func NewLeaderTracker(elected <-chan struct{}) *LeaderTracker {
+	return &LeaderTracker{elected: elected}
+}
+
+// Start reports leadership once elected and gives it up when the manager stops. It runs outside
+// leader election: such runnables are stopped before the leader-elected ones, so writes are
+// rejected before reconcilers see their context cancelled.
+func (t *LeaderTracker) Start(ctx context.Context) error {
+	select {
+	case <-t.elected:
+		observability.LeaderStatus.Set(1)
+	case <-ctx.Done():
+		return nil
+	}
+
+	<-ctx.Done()
+	t.lost.Store(true)
+	observability.LeaderStatus.Set(0)
+	return nil
+}
+
+// NeedLeaderElection returns false so the tracker also runs while waiting for the lease
+func (t *LeaderTracker) NeedLeaderElection() bool {
+	return false
+}
+
+// IsLeader reports whether this replica may write to the cluster. A nil tracker always may.
+func (t *LeaderTracker) IsLeader() bool {
+	if t == nil {
+		return true
+	}
+	if t.lost.Load() {
+		return false
+	}
+	select {
+	case <-t.elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkLeader returns ErrNotLeader unless this replica may write to the cluster
+func (t *LeaderTracker) checkLeader() error {
+	if !t.IsLeader() {
+		return ErrNotLeader
+	}
+	return nil
+}
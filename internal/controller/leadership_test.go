@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/observability"
+)
+
+// newElectedLeaderTracker returns a tracker that holds the lease
+func newElectedLeaderTracker() *LeaderTracker {
+	elected := make(chan struct{})
+	close(elected)
+	return NewLeaderTracker(elected)
+}
+
+func TestLeaderTracker(t *testing.T) {
+	elected := make(chan struct{})
+	tracker := NewLeaderTracker(elected)
+	if tracker.IsLeader() {
+		t.Fatal("IsLeader() = true before the lease is acquired")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = tracker.Start(ctx)
+		close(done)
+	}()
+
+	close(elected)
+	if !tracker.IsLeader() {
+		t.Fatal("IsLeader() = false after the lease is acquired")
+	}
+	deadline := time.Now().Add(time.Second)
+	for testutil.ToFloat64(observability.LeaderStatus) != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := testutil.ToFloat64(observability.LeaderStatus); got != 1 {
+		t.Errorf("optipod_leader = %v while leading, want 1", got)
+	}
+
+	// The manager stopping, e.g. because the lease was lost, ends leadership
+	cancel()
+	<-done
+	if tracker.IsLeader() {
+		t.Error("IsLeader() = true after the manager stopped")
+	}
+	if got := testutil.ToFloat64(observability.LeaderStatus); got != 0 {
+		t.Errorf("optipod_leader = %v after losing the lease, want 0", got)
+	}
+
+	var nilTracker *LeaderTracker
+	if !nilTracker.IsLeader() {
+		t.Error("a nil tracker must allow writes")
+	}
+}
+
+// leadershipLosingApplicationEngine loses the lease while the first workload is being applied
+type leadershipLosingApplicationEngine struct {
+	recordingApplicationEngine
+	tracker *LeaderTracker
+}
+
+func (m *leadershipLosingApplicationEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+	m.tracker.lost.Store(true)
+	return m.recordingApplicationEngine.Apply(ctx, workload, changes, policy)
+}
+
+func TestProcessWorkloads_LeadershipLostDuringApply(t *testing.T) {
+	ctx := context.Background()
+	tracker := newElectedLeaderTracker()
+
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	appEngine := &leadershipLosingApplicationEngine{tracker: tracker}
+	objects := append(createTestObjects(3), policy)
+	reconciler, fakeClient := createTestReconciler(appEngine, objects...)
+	reconciler.LeaderTracker = tracker
+	reconciler.WorkloadProcessor.SetLeaderTracker(tracker)
+
+	summary, err := reconciler.processWorkloadsWithPolicySelection(ctx, policy)
+	if !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v, want ErrNotLeader", err)
+	}
+
+	// The workload being applied when the lease was lost is finished, no other is started
+	if len(appEngine.appliedContainers) != 1 || summary.Applied != 1 {
+		t.Errorf("applied %d containers in %d workloads, want only the in-flight one",
+			len(appEngine.appliedContainers), summary.Applied)
+	}
+
+	// The former leader's status writes are rejected
+	condition := metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Test", Message: "stale"}
+	if err := reconciler.updatePolicyStatus(ctx, policy, condition); !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("updatePolicyStatus() error = %v, want ErrNotLeader", err)
+	}
+	stored := &optipodv1alpha1.OptimizationPolicy{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), stored); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	if len(stored.Status.Conditions) != 0 {
+		t.Errorf("conditions = %+v, want the stale status not written", stored.Status.Conditions)
+	}
+}
+
+func TestProcessWorkload_FormerLeaderDoesNotAnnotate(t *testing.T) {
+	ctx := context.Background()
+	workload := createTestWorkload(TestContainerName)
+	deployment := workload.Object.(*appsv1.Deployment)
+	pod := createTestPod(TestPodName)
+	fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(deployment.DeepCopy(), pod).Build()
+
+	tracker := newElectedLeaderTracker()
+	tracker.lost.Store(true)
+	appEngine := &recordingApplicationEngine{}
+	processor := createTestProcessor(appEngine, fakeClient)
+	processor.SetLeaderTracker(tracker)
+
+	_, err := processor.ProcessWorkload(ctx, workload, createTestPolicy(optipodv1alpha1.ModeAuto))
+	if !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("ProcessWorkload() error = %v, want ErrNotLeader", err)
+	}
+
+	stored := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), stored); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if len(stored.Annotations) != 0 || len(appEngine.appliedContainers) != 0 {
+		t.Errorf("annotations = %v and applied %v, want nothing written by a former leader",
+			stored.Annotations, appEngine.appliedContainers)
+	}
+}
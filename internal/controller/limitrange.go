@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getLimitRanges returns the LimitRanges in a namespace. Without a client (e.g. in tests)
+// there are no LimitRanges to apply.
+func (wp *WorkloadProcessor) getLimitRanges(ctx context.Context, namespace string) ([]corev1.LimitRange, error) {
+	if wp.client == nil {
+		return nil, nil
+	}
+
+	limitRangeList := &corev1.LimitRangeList{}
+	if err := wp.client.List(ctx, limitRangeList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list LimitRanges: %w", err)
+	}
+
+	return limitRangeList.Items, nil
+}
+
+// resolveEffectiveResources combines a container's resource spec with namespace LimitRange
+// defaults, mirroring the LimitRanger admission plugin. Values set in the spec always win.
+// Pods of a workload with `resources: {}` run with these defaulted values, so they are the
+// real baseline recommendations are compared against.
+func resolveEffectiveResources(spec corev1.ResourceRequirements, limitRanges []corev1.LimitRange) corev1.ResourceRequirements {
+	effective := *spec.DeepCopy()
+
+	// Pod defaulting sets a missing request to the limit before admission
+	defaultRequestsFromLimits(&effective)
+
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+
+			for name, value := range item.Default {
+				if _, ok := effective.Limits[name]; !ok {
+					if effective.Limits == nil {
+						effective.Limits = corev1.ResourceList{}
+					}
+					effective.Limits[name] = value.DeepCopy()
+				}
+			}
+
+			for name, value := range item.DefaultRequest {
+				if _, ok := effective.Requests[name]; !ok {
+					if effective.Requests == nil {
+						effective.Requests = corev1.ResourceList{}
+					}
+					effective.Requests[name] = value.DeepCopy()
+				}
+			}
+		}
+	}
+
+	// A LimitRange default limit without a default request also sets the request
+	defaultRequestsFromLimits(&effective)
+
+	return effective
+}
+
+// defaultRequestsFromLimits sets every missing request to the corresponding limit
+func defaultRequestsFromLimits(resources *corev1.ResourceRequirements) {
+	for name, value := range resources.Limits {
+		if _, ok := resources.Requests[name]; !ok {
+			if resources.Requests == nil {
+				resources.Requests = corev1.ResourceList{}
+			}
+			resources.Requests[name] = value.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestLimitRange() *corev1.LimitRange {
+	return &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "defaults",
+			Namespace: TestNamespace,
+		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{
+				{
+					Type: corev1.LimitTypeContainer,
+					Default: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("512Mi"),
+					},
+					DefaultRequest: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("100m"),
+						corev1.ResourceMemory: resource.MustParse("128Mi"),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestResolveEffectiveResources(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        corev1.ResourceRequirements
+		limitRanges []corev1.LimitRange
+		wantCPUReq  string
+		wantMemReq  string
+		wantMemLim  string
+	}{
+		{
+			name:        "empty resources without LimitRange",
+			spec:        corev1.ResourceRequirements{},
+			limitRanges: nil,
+			wantCPUReq:  "0",
+			wantMemReq:  "0",
+			wantMemLim:  "0",
+		},
+		{
+			name:        "empty resources with LimitRange defaults",
+			spec:        corev1.ResourceRequirements{},
+			limitRanges: []corev1.LimitRange{*newTestLimitRange()},
+			wantCPUReq:  "100m",
+			wantMemReq:  "128Mi",
+			wantMemLim:  "512Mi",
+		},
+		{
+			name: "explicit values take precedence over LimitRange defaults",
+			spec: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			limitRanges: []corev1.LimitRange{*newTestLimitRange()},
+			wantCPUReq:  "250m",
+			wantMemReq:  "1Gi",
+			wantMemLim:  "1Gi",
+		},
+		{
+			name: "default limit without default request sets the request",
+			spec: corev1.ResourceRequirements{},
+			limitRanges: []corev1.LimitRange{{
+				Spec: corev1.LimitRangeSpec{
+					Limits: []corev1.LimitRangeItem{{
+						Type:    corev1.LimitTypeContainer,
+						Default: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+					}},
+				},
+			}},
+			wantCPUReq: "0",
+			wantMemReq: "256Mi",
+			wantMemLim: "256Mi",
+		},
+		{
+			name: "pod-level LimitRange items are ignored",
+			spec: corev1.ResourceRequirements{},
+			limitRanges: []corev1.LimitRange{{
+				Spec: corev1.LimitRangeSpec{
+					Limits: []corev1.LimitRangeItem{{
+						Type: corev1.LimitTypePod,
+						Max:  corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+					}},
+				},
+			}},
+			wantCPUReq: "0",
+			wantMemReq: "0",
+			wantMemLim: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effective := resolveEffectiveResources(tt.spec, tt.limitRanges)
+
+			if got := effective.Requests.Cpu(); got.Cmp(resource.MustParse(tt.wantCPUReq)) != 0 {
+				t.Errorf("CPU request = %s, want %s", got.String(), tt.wantCPUReq)
+			}
+			if got := effective.Requests.Memory(); got.Cmp(resource.MustParse(tt.wantMemReq)) != 0 {
+				t.Errorf("memory request = %s, want %s", got.String(), tt.wantMemReq)
+			}
+			if got := effective.Limits.Memory(); got.Cmp(resource.MustParse(tt.wantMemLim)) != 0 {
+				t.Errorf("memory limit = %s, want %s", got.String(), tt.wantMemLim)
+			}
+		})
+	}
+}
+
+func TestResolveEffectiveResources_DoesNotMutateSpec(t *testing.T) {
+	spec := corev1.ResourceRequirements{}
+	_ = resolveEffectiveResources(spec, []corev1.LimitRange{*newTestLimitRange()})
+
+	if spec.Requests != nil || spec.Limits != nil {
+		t.Errorf("expected spec to be unchanged, got %+v", spec)
+	}
+}
+
+func TestGetLimitRanges(t *testing.T) {
+	scheme := createTestScheme()
+
+	tests := []struct {
+		name    string
+		objects []*corev1.LimitRange
+		want    int
+	}{
+		{name: "LimitRange present", objects: []*corev1.LimitRange{newTestLimitRange()}, want: 1},
+		{name: "LimitRange absent", objects: nil, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, obj := range tt.objects {
+				builder = builder.WithObjects(obj)
+			}
+
+			processor := createTestProcessor(&recordingApplicationEngine{}, builder.Build())
+			limitRanges, err := processor.getLimitRanges(context.Background(), TestNamespace)
+			if err != nil {
+				t.Fatalf("getLimitRanges() error = %v", err)
+			}
+			if len(limitRanges) != tt.want {
+				t.Errorf("got %d LimitRanges, want %d", len(limitRanges), tt.want)
+			}
+		})
+	}
+}
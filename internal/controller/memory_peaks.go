@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// memoryPeak is the highest memory usage retained for a container and when it was observed
+type memoryPeak struct {
+	Bytes      int64
+	ObservedAt time.Time
+}
+
+// parseMemoryPeaks parses the memory peaks annotation. Malformed entries are ignored, which
+// starts their peak over.
+func parseMemoryPeaks(value string) map[string]memoryPeak {
+	peaks := make(map[string]memoryPeak)
+	for _, entry := range strings.Split(value, ",") {
+		container, rest, ok := strings.Cut(entry, "=")
+		if !ok || container == "" {
+			continue
+		}
+		bytesValue, observedValue, ok := strings.Cut(rest, "@")
+		if !ok {
+			continue
+		}
+		bytes, err := strconv.ParseInt(bytesValue, 10, 64)
+		if err != nil || bytes < 1 {
+			continue
+		}
+		observed, err := strconv.ParseInt(observedValue, 10, 64)
+		if err != nil {
+			continue
+		}
+		peaks[container] = memoryPeak{Bytes: bytes, ObservedAt: time.Unix(observed, 0)}
+	}
+	return peaks
+}
+
+// formatMemoryPeaks formats the peaks as the memory peaks annotation, sorted by container
+func formatMemoryPeaks(peaks map[string]memoryPeak) string {
+	entries := make([]string, 0, len(peaks))
+	for container, peak := range peaks {
+		entries = append(entries, fmt.Sprintf("%s=%d@%d", container, peak.Bytes, peak.ObservedAt.Unix()))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// retainMemoryPeak returns the container's peak after observing the highest usage of the rolling
+// window at now: a higher usage replaces the peak, as does any usage once the peak has expired.
+// The second result is true when the peak changed and must be stored.
+func retainMemoryPeak(
+	previous memoryPeak,
+	windowMax resource.Quantity,
+	floor *optipodv1alpha1.MemoryPeakFloor,
+	now time.Time,
+) (memoryPeak, bool) {
+	usage := windowMax.Value()
+	if usage < 1 {
+		return previous, false
+	}
+	if usage > previous.Bytes || (previous.Bytes > 0 && floor.Expired(previous.ObservedAt, now)) {
+		return memoryPeak{Bytes: usage, ObservedAt: now.Truncate(time.Second)}, true
+	}
+	return previous, false
+}
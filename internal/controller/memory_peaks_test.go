@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+func TestMemoryPeaksAnnotation(t *testing.T) {
+	peaks := parseMemoryPeaks("app=1073741824@1735689600,bad,sidecar=@1,zero=0@1,stale=5@x,proxy=268435456@1735776000")
+	want := map[string]memoryPeak{
+		"app":   {Bytes: 1 << 30, ObservedAt: time.Unix(1735689600, 0)},
+		"proxy": {Bytes: 256 << 20, ObservedAt: time.Unix(1735776000, 0)},
+	}
+	if len(peaks) != len(want) {
+		t.Fatalf("parseMemoryPeaks() = %v, want %v", peaks, want)
+	}
+	for container, peak := range want {
+		if got := peaks[container]; got.Bytes != peak.Bytes || !got.ObservedAt.Equal(peak.ObservedAt) {
+			t.Errorf("peak of %s = %v, want %v", container, got, peak)
+		}
+	}
+
+	if got := formatMemoryPeaks(peaks); got != "app=1073741824@1735689600,proxy=268435456@1735776000" {
+		t.Errorf("formatMemoryPeaks() = %q, want the entries sorted by container", got)
+	}
+}
+
+func TestRetainMemoryPeak(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	previous := memoryPeak{Bytes: 1 << 30, ObservedAt: now.AddDate(0, 0, -10)}
+	week := &optipodv1alpha1.MemoryPeakFloor{Retention: &metav1.Duration{Duration: 7 * 24 * time.Hour}}
+	lifetime := &optipodv1alpha1.MemoryPeakFloor{}
+
+	tests := []struct {
+		name        string
+		previous    memoryPeak
+		windowMax   string
+		floor       *optipodv1alpha1.MemoryPeakFloor
+		want        memoryPeak
+		wantChanged bool
+	}{
+		{
+			name:        "first observation",
+			windowMax:   "256Mi",
+			floor:       lifetime,
+			want:        memoryPeak{Bytes: 256 << 20, ObservedAt: now},
+			wantChanged: true,
+		},
+		{
+			name:        "higher usage replaces the peak",
+			previous:    previous,
+			windowMax:   "2Gi",
+			floor:       lifetime,
+			want:        memoryPeak{Bytes: 2 << 30, ObservedAt: now},
+			wantChanged: true,
+		},
+		{
+			name:      "lower usage keeps the peak",
+			previous:  previous,
+			windowMax: "256Mi",
+			floor:     lifetime,
+			want:      previous,
+		},
+		{
+			name:        "expired peak is replaced by lower usage",
+			previous:    previous,
+			windowMax:   "256Mi",
+			floor:       week,
+			want:        memoryPeak{Bytes: 256 << 20, ObservedAt: now},
+			wantChanged: true,
+		},
+		{
+			name:      "no usage keeps the peak",
+			previous:  previous,
+			windowMax: "0",
+			floor:     week,
+			want:      previous,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := retainMemoryPeak(tt.previous, resource.MustParse(tt.windowMax), tt.floor, now)
+			if got.Bytes != tt.want.Bytes || !got.ObservedAt.Equal(tt.want.ObservedAt) || changed != tt.wantChanged {
+				t.Errorf("retainMemoryPeak() = %v, %v, want %v, %v", got, changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestProcessWorkload_MemoryPeakFloor(t *testing.T) {
+	ctx := context.Background()
+	workload := createTestWorkload(TestContainerName)
+	deployment := workload.Object.(*appsv1.Deployment)
+	pod := createTestPod(TestPodName)
+	fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(deployment.DeepCopy(), pod).Build()
+
+	// A rare peak of 1Gi is in the rolling window
+	metricsProvider := createTestMetricsProvider()
+	metricsProvider.metricsToReturn.Memory.Max = resource.MustParse("1Gi")
+	processor := NewWorkloadProcessor(metricsProvider, recommendation.NewEngine(), &recordingApplicationEngine{}, fakeClient)
+	policy := createTestPolicy(optipodv1alpha1.ModeRecommend)
+	policy.Spec.MetricsConfig.MemoryPeakFloor = &optipodv1alpha1.MemoryPeakFloor{}
+
+	if _, err := processor.ProcessWorkload(ctx, workload, policy); err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	stored := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), stored); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	peaks := parseMemoryPeaks(stored.Annotations[optipodv1alpha1.AnnotationMemoryPeaks])
+	if peaks[TestContainerName].Bytes != 1<<30 {
+		t.Fatalf("memory peaks annotation = %q, want the 1Gi peak retained", stored.Annotations[optipodv1alpha1.AnnotationMemoryPeaks])
+	}
+
+	// The peak has left the rolling window, but still floors the memory recommendation
+	metricsProvider.metricsToReturn.Memory.Max = resource.MustParse("300Mi")
+	workload = &discovery.Workload{Kind: KindDeployment, Namespace: TestNamespace, Name: TestWorkloadName, Object: stored}
+	status, err := processor.ProcessWorkload(ctx, workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if len(status.Recommendations) != 1 || status.Recommendations[0].Memory.Cmp(resource.MustParse("1Gi")) != 0 {
+		t.Errorf("recommendations = %+v, want memory floored at the retained 1Gi peak", status.Recommendations)
+	}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), stored); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if got := parseMemoryPeaks(stored.Annotations[optipodv1alpha1.AnnotationMemoryPeaks]); got[TestContainerName].Bytes != 1<<30 {
+		t.Errorf("memory peaks annotation = %q, want the 1Gi peak kept", stored.Annotations[optipodv1alpha1.AnnotationMemoryPeaks])
+	}
+
+	// Without the floor the recommendation follows the rolling window
+	policy.Spec.MetricsConfig.MemoryPeakFloor = nil
+	status, err = processor.ProcessWorkload(ctx, workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Recommendations[0].Memory.Cmp(resource.MustParse("1Gi")) >= 0 {
+		t.Errorf("memory = %s without a peak floor, want it below the retained peak", status.Recommendations[0].Memory.String())
+	}
+}
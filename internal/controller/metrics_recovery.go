@@ -0,0 +1,162 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+const (
+	// DefaultMetricsRecoveryStagger is the delay between the reconciles triggered when the metrics
+	// backend recovers
+	DefaultMetricsRecoveryStagger = 5 * time.Second
+
+	// missingMetricsReason prefixes the status reason of a workload skipped for missing metrics
+	missingMetricsReason = "Missing metrics"
+)
+
+// MetricsRecoveryWatcher health checks the metrics backend periodically. When it becomes healthy
+// again, the policies that skipped workloads for missing metrics while it was down are reconciled
+// right away instead of at their next scheduled reconcile. The reconciles are staggered, so a
+// recovery does not start every policy at once.
+type MetricsRecoveryWatcher struct {
+	provider metrics.MetricsProvider
+	interval time.Duration
+	stagger  time.Duration
+	events   chan event.GenericEvent
+
+	mu        sync.Mutex
+	unhealthy bool
+	pending   map[types.NamespacedName]struct{}
+}
+
+// NewMetricsRecoveryWatcher creates a watcher that health checks provider every interval
+func NewMetricsRecoveryWatcher(provider metrics.MetricsProvider, interval time.Duration) *MetricsRecoveryWatcher {
+	return &MetricsRecoveryWatcher{
+		provider: provider,
+		interval: interval,
+		stagger:  DefaultMetricsRecoveryStagger,
+		events:   make(chan event.GenericEvent),
+		pending:  make(map[types.NamespacedName]struct{}),
+	}
+}
+
+// Source returns the source the policy controller watches for the reconciles triggered on recovery
+func (w *MetricsRecoveryWatcher) Source() source.Source {
+	return source.Channel(w.events, &handler.EnqueueRequestForObject{})
+}
+
+// Start health checks the metrics backend until ctx is done
+func (w *MetricsRecoveryWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if recovered := w.check(ctx); len(recovered) > 0 {
+				w.trigger(ctx, recovered)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns true: only the leader reconciles policies
+func (w *MetricsRecoveryWatcher) NeedLeaderElection() bool {
+	return true
+}
+
+// markMissingMetrics records that policy skipped a workload for missing metrics
+func (w *MetricsRecoveryWatcher) markMissingMetrics(policy *optipodv1alpha1.OptimizationPolicy) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending[types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}] = struct{}{}
+}
+
+// check health checks the metrics backend and, when it has just recovered, returns the policies
+// to reconcile in a stable order. They are forgotten, so a later outage starts afresh.
+func (w *MetricsRecoveryWatcher) check(ctx context.Context) []types.NamespacedName {
+	err := w.provider.HealthCheck(ctx)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err != nil {
+		if !w.unhealthy {
+			logf.FromContext(ctx).Info("Metrics backend is unhealthy", "error", err.Error())
+		}
+		w.unhealthy = true
+		return nil
+	}
+	if !w.unhealthy {
+		return nil
+	}
+
+	w.unhealthy = false
+	recovered := make([]types.NamespacedName, 0, len(w.pending))
+	for key := range w.pending {
+		recovered = append(recovered, key)
+	}
+	clear(w.pending)
+	slices.SortFunc(recovered, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	logf.FromContext(ctx).Info("Metrics backend recovered, reconciling policies with skipped workloads",
+		"policies", len(recovered))
+	return recovered
+}
+
+// trigger enqueues a reconcile of each policy, waiting the stagger between them
+func (w *MetricsRecoveryWatcher) trigger(ctx context.Context, policies []types.NamespacedName) {
+	for i, key := range policies {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(w.stagger):
+			}
+		}
+
+		policy := &optipodv1alpha1.OptimizationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case w.events <- event.GenericEvent{Object: policy}:
+		}
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// flakyHealthMetricsProvider reports healthErr from its health check
+type flakyHealthMetricsProvider struct {
+	mockMetricsProvider
+	healthErr error
+}
+
+func (m *flakyHealthMetricsProvider) HealthCheck(ctx context.Context) error {
+	return m.healthErr
+}
+
+func newRecoveryTestPolicy(name string) *optipodv1alpha1.OptimizationPolicy {
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Name = name
+	policy.Namespace = TestNamespace
+	return policy
+}
+
+func TestMetricsRecoveryWatcher_Check(t *testing.T) {
+	ctx := context.Background()
+	provider := &flakyHealthMetricsProvider{}
+	watcher := NewMetricsRecoveryWatcher(provider, time.Minute)
+
+	// Skips while the backend stays healthy are not a recovery
+	watcher.markMissingMetrics(newRecoveryTestPolicy("steady"))
+	if got := watcher.check(ctx); got != nil {
+		t.Fatalf("check() = %v while healthy, want nothing to reconcile", got)
+	}
+
+	provider.healthErr = errors.New("connection refused")
+	if got := watcher.check(ctx); got != nil {
+		t.Fatalf("check() = %v while unhealthy, want nothing to reconcile", got)
+	}
+	watcher.markMissingMetrics(newRecoveryTestPolicy("web"))
+	watcher.markMissingMetrics(newRecoveryTestPolicy("batch"))
+	watcher.markMissingMetrics(newRecoveryTestPolicy("web"))
+	if got := watcher.check(ctx); got != nil {
+		t.Fatalf("check() = %v while still unhealthy, want nothing to reconcile", got)
+	}
+
+	provider.healthErr = nil
+	want := []types.NamespacedName{
+		{Namespace: TestNamespace, Name: "batch"},
+		{Namespace: TestNamespace, Name: "steady"},
+		{Namespace: TestNamespace, Name: "web"},
+	}
+	if got := watcher.check(ctx); !slices.Equal(got, want) {
+		t.Errorf("check() = %v on recovery, want %v", got, want)
+	}
+
+	// The triggered policies are forgotten
+	if got := watcher.check(ctx); got != nil {
+		t.Errorf("check() = %v after the recovery, want nothing to reconcile", got)
+	}
+}
+
+func TestMetricsRecoveryWatcher_TriggerStaggers(t *testing.T) {
+	watcher := NewMetricsRecoveryWatcher(&flakyHealthMetricsProvider{}, time.Minute)
+	watcher.stagger = 50 * time.Millisecond
+	policies := []types.NamespacedName{
+		{Namespace: TestNamespace, Name: "a"},
+		{Namespace: TestNamespace, Name: "b"},
+		{Namespace: TestNamespace, Name: "c"},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.trigger(ctx, policies)
+
+	var last time.Time
+	for i, want := range policies {
+		select {
+		case evt := <-watcher.events:
+			if got := (types.NamespacedName{Namespace: evt.Object.GetNamespace(), Name: evt.Object.GetName()}); got != want {
+				t.Errorf("reconcile %d triggered for %s, want %s", i, got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("reconcile %d of %s was not triggered", i, want)
+		}
+		if i > 0 {
+			if gap := time.Since(last); gap < 40*time.Millisecond {
+				t.Errorf("reconcile %d triggered %v after the previous one, want it staggered", i, gap)
+			}
+		}
+		last = time.Now()
+	}
+}
+
+func TestMetricsRecoveryWatcher_TriggerStopsWithContext(t *testing.T) {
+	watcher := NewMetricsRecoveryWatcher(&flakyHealthMetricsProvider{}, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		watcher.trigger(ctx, []types.NamespacedName{{Namespace: TestNamespace, Name: "a"}})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("trigger() blocked after the context was cancelled")
+	}
+}
+
+func TestProcessWorkload_MarksMissingMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		metricsErr  error
+		wantPending bool
+	}{
+		{name: "skipped for missing metrics", metricsErr: errors.New("metrics backend unavailable"), wantPending: true},
+		{name: "processed", wantPending: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := createTestMetricsProvider()
+			provider.errorToReturn = tt.metricsErr
+			watcher := NewMetricsRecoveryWatcher(provider, time.Minute)
+			processor := NewWorkloadProcessor(provider, recommendation.NewEngine(), &recordingApplicationEngine{}, nil)
+			processor.SetMetricsRecoveryWatcher(watcher)
+
+			policy := newRecoveryTestPolicy("web")
+			if _, err := processor.ProcessWorkload(context.Background(), createTestWorkload(TestContainerName), policy); err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+
+			_, pending := watcher.pending[types.NamespacedName{Namespace: TestNamespace, Name: "web"}]
+			if pending != tt.wantPending {
+				t.Errorf("policy pending a recovery reconcile = %v, want %v", pending, tt.wantPending)
+			}
+		})
+	}
+}
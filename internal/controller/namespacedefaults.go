@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// getNamespaceDefaults returns the NamespaceDefaults in a namespace ordered by name. Without a
+// client, or when the NamespaceDefaults CRD is not installed, there are no defaults to apply.
+func (wp *WorkloadProcessor) getNamespaceDefaults(ctx context.Context, namespace string) ([]optipodv1alpha1.NamespaceDefaults, error) {
+	if wp.client == nil {
+		return nil, nil
+	}
+
+	defaultsList := &optipodv1alpha1.NamespaceDefaultsList{}
+	if err := wp.client.List(ctx, defaultsList, client.InNamespace(namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list NamespaceDefaults: %w", err)
+	}
+
+	sort.Slice(defaultsList.Items, func(i, j int) bool {
+		return defaultsList.Items[i].Name < defaultsList.Items[j].Name
+	})
+
+	return defaultsList.Items, nil
+}
+
+// applyNamespaceDefaults tightens policy bounds with every NamespaceDefaults in the workload
+// namespace. Namespace bounds can only narrow the policy bounds, so the strictest value wins
+// regardless of order. An invalid NamespaceDefaults or a min/max conflict is returned as an error.
+func applyNamespaceDefaults(
+	bounds optipodv1alpha1.ResourceBounds,
+	defaults []optipodv1alpha1.NamespaceDefaults,
+) (optipodv1alpha1.ResourceBounds, error) {
+	merged := bounds
+	for i := range defaults {
+		nsDefaults := &defaults[i]
+		if err := nsDefaults.Validate(); err != nil {
+			return bounds, fmt.Errorf("invalid NamespaceDefaults %s/%s: %w", nsDefaults.Namespace, nsDefaults.Name, err)
+		}
+
+		var err error
+		merged, err = optipodv1alpha1.MergeResourceBounds(merged, nsDefaults.Spec.ResourceBounds)
+		if err != nil {
+			return bounds, fmt.Errorf("NamespaceDefaults %s/%s: %w", nsDefaults.Namespace, nsDefaults.Name, err)
+		}
+	}
+
+	return merged, nil
+}
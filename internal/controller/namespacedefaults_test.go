@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+func newTestNamespaceDefaults(name, cpuMax, memoryMax string) *optipodv1alpha1.NamespaceDefaults {
+	defaults := &optipodv1alpha1.NamespaceDefaults{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: TestNamespace},
+	}
+	if cpuMax != "" {
+		q := resource.MustParse(cpuMax)
+		defaults.Spec.ResourceBounds.CPU = &optipodv1alpha1.NamespaceResourceBound{Max: &q}
+	}
+	if memoryMax != "" {
+		q := resource.MustParse(memoryMax)
+		defaults.Spec.ResourceBounds.Memory = &optipodv1alpha1.NamespaceResourceBound{Max: &q}
+	}
+	return defaults
+}
+
+func TestApplyNamespaceDefaults_StrictestWins(t *testing.T) {
+	bounds := createTestPolicy(optipodv1alpha1.ModeAuto).Spec.ResourceBounds
+
+	// Order must not matter: each NamespaceDefaults can only tighten
+	defaults := []optipodv1alpha1.NamespaceDefaults{
+		*newTestNamespaceDefaults("a", "500m", ""),
+		*newTestNamespaceDefaults("b", "1", "512Mi"),
+	}
+
+	merged, err := applyNamespaceDefaults(bounds, defaults)
+	if err != nil {
+		t.Fatalf("applyNamespaceDefaults() error = %v", err)
+	}
+	if merged.CPU.Max.Cmp(resource.MustParse("500m")) != 0 {
+		t.Errorf("cpu.max = %s, want 500m", merged.CPU.Max.String())
+	}
+	if merged.Memory.Max.Cmp(resource.MustParse("512Mi")) != 0 {
+		t.Errorf("memory.max = %s, want 512Mi", merged.Memory.Max.String())
+	}
+
+	invalid := *newTestNamespaceDefaults("broken", "", "")
+	minAboveMax := resource.MustParse("4")
+	maxValue := resource.MustParse("1")
+	invalid.Spec.ResourceBounds.CPU = &optipodv1alpha1.NamespaceResourceBound{Min: &minAboveMax, Max: &maxValue}
+	if _, err := applyNamespaceDefaults(bounds, []optipodv1alpha1.NamespaceDefaults{invalid}); err == nil {
+		t.Error("expected an error for an invalid NamespaceDefaults")
+	}
+}
+
+func TestProcessWorkload_NamespaceDefaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		defaults   []client.Object
+		selector   *optipodv1alpha1.ContainerSelector
+		wantCPU    string
+		wantMemory string
+		wantErr    bool
+	}{
+		{
+			name:       "no namespace defaults",
+			wantCPU:    "240m",
+			wantMemory: "322122547", // 256Mi * 1.2 in bytes
+		},
+		{
+			name:       "namespace ceiling tightens policy bounds",
+			defaults:   []client.Object{newTestNamespaceDefaults("dev-tier", "150m", "128Mi")},
+			wantCPU:    "150m",
+			wantMemory: "128Mi",
+		},
+		{
+			name:     "namespace ceiling also tightens container selector bounds",
+			defaults: []client.Object{newTestNamespaceDefaults("dev-tier", "150m", "")},
+			selector: &optipodv1alpha1.ContainerSelector{
+				Name: TestContainerName,
+				ResourceBounds: &optipodv1alpha1.ResourceBounds{
+					CPU:    optipodv1alpha1.ResourceBound{Min: resource.MustParse("10m"), Max: resource.MustParse("8")},
+					Memory: optipodv1alpha1.ResourceBound{Min: resource.MustParse("32Mi"), Max: resource.MustParse("200Mi")},
+				},
+			},
+			wantCPU:    "150m",
+			wantMemory: "200Mi",
+		},
+		{
+			name:     "conflicting bounds are surfaced as an error",
+			defaults: []client.Object{newTestNamespaceDefaults("dev-tier", "", "32Mi")},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload := createTestWorkload(TestContainerName)
+			pod := createTestPod(TestPodName)
+			objects := append([]client.Object{workload.Object, pod}, tt.defaults...)
+			fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(objects...).Build()
+
+			policy := createTestPolicy(optipodv1alpha1.ModeRecommend)
+			if tt.selector != nil {
+				policy.Spec.ContainerSelectors = []optipodv1alpha1.ContainerSelector{*tt.selector}
+			}
+
+			processor := createTestProcessor(&recordingApplicationEngine{}, fakeClient)
+			status, err := processor.ProcessWorkload(context.Background(), workload, policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProcessWorkload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if status.Status != StatusError {
+					t.Errorf("status = %q, want %q", status.Status, StatusError)
+				}
+				return
+			}
+
+			rec := findRecommendation(status.Recommendations, TestContainerName)
+			if rec == nil {
+				t.Fatalf("expected a recommendation for %s, got status %q: %s", TestContainerName, status.Status, status.Reason)
+			}
+			if rec.CPU.Cmp(resource.MustParse(tt.wantCPU)) != 0 {
+				t.Errorf("CPU = %s, want %s", rec.CPU.String(), tt.wantCPU)
+			}
+			if rec.Memory.Cmp(resource.MustParse(tt.wantMemory)) != 0 {
+				t.Errorf("Memory = %s, want %s", rec.Memory.String(), tt.wantMemory)
+			}
+		})
+	}
+}
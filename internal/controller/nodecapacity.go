@@ -0,0 +1,203 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// nodeCapacity is the largest allocatable CPU and memory among the nodes a workload can run on,
+// with the nodes providing them
+type nodeCapacity struct {
+	cpu        resource.Quantity
+	cpuNode    string
+	memory     resource.Quantity
+	memoryNode string
+}
+
+// findNodeCapacity returns the largest allocatable CPU and memory among the schedulable nodes
+// matching the workload's pod template. It returns nil when the policy does not cap
+// recommendations at node capacity, or when no node matches.
+func (wp *WorkloadProcessor) findNodeCapacity(
+	ctx context.Context,
+	workload *discovery.Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) *nodeCapacity {
+	if wp.client == nil || policy.Spec.NodeCapacityCap == nil || !policy.Spec.NodeCapacityCap.Enabled {
+		return nil
+	}
+	podSpec, err := workloadPodSpec(workload)
+	if err != nil {
+		return nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := wp.client.List(ctx, nodes); err != nil {
+		// Without the nodes the recommendation is not capped
+		logf.FromContext(ctx).Error(err, "Failed to list nodes",
+			"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name))
+		return nil
+	}
+
+	var capacity *nodeCapacity
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.Spec.Unschedulable || !nodeMatchesPodSpec(node, podSpec) {
+			continue
+		}
+		if capacity == nil {
+			capacity = &nodeCapacity{}
+		}
+		if cpu := node.Status.Allocatable.Cpu(); cpu.Cmp(capacity.cpu) > 0 {
+			capacity.cpu, capacity.cpuNode = cpu.DeepCopy(), node.Name
+		}
+		if memory := node.Status.Allocatable.Memory(); memory.Cmp(capacity.memory) > 0 {
+			capacity.memory, capacity.memoryNode = memory.DeepCopy(), node.Name
+		}
+	}
+	return capacity
+}
+
+// nodeMatchesPodSpec reports whether the pod's nodeSelector and required node affinity allow it
+// to be scheduled on the node. Taints are not considered.
+func nodeMatchesPodSpec(node *corev1.Node, podSpec *corev1.PodSpec) bool {
+	if !labels.SelectorFromSet(podSpec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	affinity := podSpec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil ||
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// Terms are ORed, the requirements of a term are ANDed
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if nodeMatchesTerm(node, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeMatchesTerm reports whether the node matches a node selector term. An empty term or one
+// with an invalid requirement matches no node, as in the scheduler.
+func nodeMatchesTerm(node *corev1.Node, term corev1.NodeSelectorTerm) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	return requirementsMatch(term.MatchExpressions, labels.Set(node.Labels)) &&
+		requirementsMatch(term.MatchFields, labels.Set{"metadata.name": node.Name})
+}
+
+// nodeSelectorOperators maps node selector operators to label selector operators
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// requirementsMatch reports whether the set satisfies all node selector requirements
+func requirementsMatch(requirements []corev1.NodeSelectorRequirement, set labels.Set) bool {
+	for _, requirement := range requirements {
+		operator, ok := nodeSelectorOperators[requirement.Operator]
+		if !ok {
+			return false
+		}
+		r, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil || !r.Matches(set) {
+			return false
+		}
+	}
+	return true
+}
+
+// otherContainerRequests sums the requests of the containers running alongside the named one:
+// the pod's other containers and its native sidecars
+func otherContainerRequests(
+	podSpec *corev1.PodSpec,
+	effectiveResources map[string]corev1.ResourceRequirements,
+	name string,
+) corev1.ResourceList {
+	cpu, memory := resource.Quantity{}, resource.Quantity{}
+	add := func(container corev1.Container) {
+		if container.Name == name {
+			return
+		}
+		requests := effectiveResources[container.Name].Requests
+		cpu.Add(*requests.Cpu())
+		memory.Add(*requests.Memory())
+	}
+	for _, container := range podSpec.Containers {
+		add(container)
+	}
+	for _, container := range podSpec.InitContainers {
+		if isNativeSidecar(container) {
+			add(container)
+		}
+	}
+	return corev1.ResourceList{corev1.ResourceCPU: cpu, corev1.ResourceMemory: memory}
+}
+
+// capAtNodeCapacity lowers the recommendation of the resources the policy optimizes to what fits
+// on the largest matching node, keeping the policy's headroom free and leaving room for the
+// requests of the pod's other containers. It reports whether the recommendation was lowered.
+func capAtNodeCapacity(
+	rec *recommendation.Recommendation,
+	capacity *nodeCapacity,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	others corev1.ResourceList,
+) bool {
+	headroom := int64(policy.Spec.NodeCapacityCap.Headroom())
+	capped := false
+
+	// A pod whose other containers already fill the node cannot be helped by capping
+	cpuCap := capacity.cpu.MilliValue()*(100-headroom)/100 - others.Cpu().MilliValue()
+	if policy.OptimizesCPU() && cpuCap > 0 && rec.CPU.MilliValue() > cpuCap {
+		limit := *resource.NewMilliQuantity(cpuCap, resource.DecimalSI)
+		rec.Explanation += fmt.Sprintf("; CPU capped at %s instead of %s to fit node %s (%s allocatable, %d%% headroom)",
+			limit.String(), rec.CPU.String(), capacity.cpuNode, capacity.cpu.String(), headroom)
+		rec.CPU = limit
+		capped = true
+	}
+
+	memoryCap := capacity.memory.Value()*(100-headroom)/100 - others.Memory().Value()
+	if policy.OptimizesMemory() && memoryCap > 0 && rec.Memory.Value() > memoryCap {
+		limit := *resource.NewQuantity(memoryCap, resource.BinarySI)
+		rec.Explanation += fmt.Sprintf("; memory capped at %s instead of %s to fit node %s (%s allocatable, %d%% headroom)",
+			limit.String(), rec.Memory.String(), capacity.memoryNode, capacity.memory.String(), headroom)
+		rec.Memory = limit
+		capped = true
+	}
+
+	if capped {
+		rec.ClampedToBound = append(rec.ClampedToBound, optipodv1alpha1.BoundNodeCapacity)
+	}
+	return capped
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// testNode returns a node with the given labels and allocatable CPU and memory
+func testNode(name, cpu, memory string, nodeLabels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}},
+	}
+}
+
+func TestNodeMatchesPodSpec(t *testing.T) {
+	node := testNode("node-a", "4", "16Gi", map[string]string{"pool": "batch", "cores": "4"})
+	affinity := func(terms ...corev1.NodeSelectorTerm) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}}
+	}
+	expression := func(key string, operator corev1.NodeSelectorOperator, values ...string) corev1.NodeSelectorTerm {
+		return corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: key, Operator: operator, Values: values},
+		}}
+	}
+
+	tests := []struct {
+		name      string
+		podSpec   corev1.PodSpec
+		wantMatch bool
+	}{
+		{name: "no constraints", wantMatch: true},
+		{
+			name:      "matching node selector",
+			podSpec:   corev1.PodSpec{NodeSelector: map[string]string{"pool": "batch"}},
+			wantMatch: true,
+		},
+		{
+			name:    "other node selector",
+			podSpec: corev1.PodSpec{NodeSelector: map[string]string{"pool": "web"}},
+		},
+		{
+			name: "one of the affinity terms matches",
+			podSpec: corev1.PodSpec{Affinity: affinity(
+				expression("pool", corev1.NodeSelectorOpIn, "web"),
+				expression("cores", corev1.NodeSelectorOpGt, "2"),
+			)},
+			wantMatch: true,
+		},
+		{
+			name:    "no affinity term matches",
+			podSpec: corev1.PodSpec{Affinity: affinity(expression("pool", corev1.NodeSelectorOpNotIn, "batch"))},
+		},
+		{
+			name: "affinity on the node name",
+			podSpec: corev1.PodSpec{Affinity: affinity(corev1.NodeSelectorTerm{MatchFields: []corev1.NodeSelectorRequirement{
+				{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-a"}},
+			}})},
+			wantMatch: true,
+		},
+		{
+			name:    "an empty term matches no node",
+			podSpec: corev1.PodSpec{Affinity: affinity(corev1.NodeSelectorTerm{})},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeMatchesPodSpec(node, &tt.podSpec); got != tt.wantMatch {
+				t.Errorf("nodeMatchesPodSpec() = %v, want %v", got, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestProcessWorkload_NodeCapacityCap(t *testing.T) {
+	newProcessor := func(workload *appsv1.Deployment, nodes ...client.Object) *WorkloadProcessor {
+		pod := createTestPod(TestPodName)
+		objects := append([]client.Object{workload.DeepCopy(), pod}, nodes...)
+		fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(objects...).Build()
+		return createTestProcessor(&recordingApplicationEngine{}, fakeClient)
+	}
+	newWorkload := func() *appsv1.Deployment {
+		workload := createTestWorkload(TestContainerName, "sidecar")
+		deployment := workload.Object.(*appsv1.Deployment)
+		deployment.Spec.Template.Spec.NodeSelector = map[string]string{"pool": "small"}
+		deployment.Spec.Template.Spec.Containers[1].Resources.Requests = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("25m"),
+			corev1.ResourceMemory: resource.MustParse("20Mi"),
+		}
+		return deployment
+	}
+	newPolicy := func(enabled bool) *optipodv1alpha1.OptimizationPolicy {
+		policy := createTestPolicy(optipodv1alpha1.ModeRecommend)
+		policy.Spec.NodeCapacityCap = &optipodv1alpha1.NodeCapacityCap{Enabled: enabled}
+		return policy
+	}
+
+	// The recommendation is 240m CPU and about 307Mi memory. The largest node of the matching
+	// pool leaves 225m CPU and 270Mi memory after the default 10% headroom, less the sidecar's requests.
+	cordoned := testNode("cordoned", "8", "32Gi", map[string]string{"pool": "small"})
+	cordoned.Spec.Unschedulable = true
+	nodes := []client.Object{
+		testNode("small-1", "200m", "256Mi", map[string]string{"pool": "small"}),
+		testNode("small-2", "250m", "300Mi", map[string]string{"pool": "small"}),
+		testNode("large", "8", "32Gi", map[string]string{"pool": "large"}),
+		cordoned,
+	}
+
+	t.Run("capped at the largest matching node", func(t *testing.T) {
+		deployment := newWorkload()
+		processor := newProcessor(deployment, nodes...)
+		workload := createTestWorkload(TestContainerName, "sidecar")
+		workload.Object = deployment
+
+		status, err := processor.ProcessWorkload(context.Background(), workload, newPolicy(true))
+		if err != nil {
+			t.Fatalf("ProcessWorkload() error = %v", err)
+		}
+		rec := findRecommendation(status.Recommendations, TestContainerName)
+		if rec == nil {
+			t.Fatalf("status = %s (%s), want a recommendation", status.Status, status.Reason)
+		}
+		if rec.CPU == nil || rec.CPU.Cmp(resource.MustParse("200m")) != 0 {
+			t.Errorf("CPU recommendation = %v, want 200m", rec.CPU)
+		}
+		if rec.Memory == nil || rec.Memory.Cmp(resource.MustParse("250Mi")) != 0 {
+			t.Errorf("memory recommendation = %v, want 250Mi", rec.Memory)
+		}
+		if !slices.Contains(rec.ClampedToBound, optipodv1alpha1.BoundNodeCapacity) {
+			t.Errorf("clampedToBound = %v, want %s", rec.ClampedToBound, optipodv1alpha1.BoundNodeCapacity)
+		}
+		if !strings.Contains(rec.Explanation, "to fit node small-2") {
+			t.Errorf("explanation = %q, want the node named", rec.Explanation)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		enabled bool
+		nodes   []client.Object
+	}{
+		{name: "disabled", enabled: false, nodes: nodes},
+		{name: "no matching node", enabled: true, nodes: nodes[2:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := newWorkload()
+			processor := newProcessor(deployment, tt.nodes...)
+			workload := createTestWorkload(TestContainerName, "sidecar")
+			workload.Object = deployment
+
+			status, err := processor.ProcessWorkload(context.Background(), workload, newPolicy(tt.enabled))
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			rec := findRecommendation(status.Recommendations, TestContainerName)
+			if rec == nil || rec.CPU == nil || rec.CPU.Cmp(resource.MustParse("240m")) != 0 {
+				t.Fatalf("recommendation = %+v, want the uncapped 240m CPU", rec)
+			}
+			if slices.Contains(rec.ClampedToBound, optipodv1alpha1.BoundNodeCapacity) {
+				t.Errorf("clampedToBound = %v, want no node capacity cap", rec.ClampedToBound)
+			}
+		})
+	}
+}
@@ -18,28 +18,48 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/config"
 	"github.com/optipod/optipod/internal/discovery"
 	"github.com/optipod/optipod/internal/observability"
 	"github.com/optipod/optipod/internal/policy"
 )
 
-// Initialize random generator once at package level to ensure proper jitter
-var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+// Initialize random generator once at package level to ensure proper jitter.
+// rand.Rand is not safe for concurrent use, so access is serialized by rngMu.
+var (
+	rngMu sync.Mutex
+	rng   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// jitterFraction returns a random value in [0.0, 1.0) and is safe for concurrent reconciles
+func jitterFraction() float64 {
+	rngMu.Lock()
+	defer rngMu.Unlock()
+	return rng.Float64()
+}
 
 // OptimizationPolicyReconciler reconciles a OptimizationPolicy object
 type OptimizationPolicyReconciler struct {
@@ -49,16 +69,62 @@ type OptimizationPolicyReconciler struct {
 	WorkloadProcessor *WorkloadProcessor
 	EventRecorder     *observability.EventRecorder
 	PolicySelector    *policy.PolicySelector
+
+	// OperatorConfig provides live operator-wide defaults; it may be hot-reloaded from a ConfigMap
+	OperatorConfig *config.OperatorConfig
+
+	// MaxConcurrentReconciles is the number of policies reconciled in parallel (0 = controller-runtime default of 1)
+	MaxConcurrentReconciles int
+
+	// APIReader reads directly from the API server; workloads are listed through it when DiscoveryPageSize is set
+	APIReader client.Reader
+
+	// DiscoveryPageSize lists workloads in pages of this size through APIReader (0 = one list from the cache)
+	DiscoveryPageSize int64
+
+	// ExcludedNamespaces are never optimized unless a policy names them in its namespace allow list
+	ExcludedNamespaces []string
+
+	// LeaderTracker gates status updates on holding the leader lease (nil = always allowed)
+	LeaderTracker *LeaderTracker
+
+	// ReconcileBudget is how long a reconciliation may process workloads before it checkpoints
+	// its progress in status and requeues to continue (0 = unlimited)
+	ReconcileBudget time.Duration
+
+	// MetricsRecovery triggers reconciles of policies with workloads skipped for missing metrics
+	// when the metrics backend recovers (nil = wait for the next scheduled reconcile)
+	MetricsRecovery *MetricsRecoveryWatcher
+
+	// Quarantine skips workloads whose processing keeps failing, retrying them with backoff
+	// (zero FailureThreshold = every workload is retried every reconciliation)
+	Quarantine QuarantineConfig
+
+	// policySelectorOnce guards lazy initialization of PolicySelector under parallel reconciles
+	policySelectorOnce sync.Once
+
+	// ownership tracks the workloads each policy annotated as their owner
+	ownership ownershipTracker
 }
 
 // +kubebuilder:rbac:groups=optipod.optipod.io,resources=optimizationpolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=optipod.optipod.io,resources=optimizationpolicies/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=optipod.optipod.io,resources=optimizationpolicies/finalizers,verbs=update
+// +kubebuilder:rbac:groups=optipod.optipod.io,resources=namespacedefaults,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
+// +kubebuilder:rbac:groups="",resources=limitranges,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods;nodes,verbs=get;list
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=keda.sh,resources=scaledobjects,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;create;update;delete
+// +kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers/status,verbs=update
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -73,6 +139,11 @@ func (r *OptimizationPolicyReconciler) Reconcile(ctx context.Context, req ctrl.R
 		timer.Observe(duration)
 	}()
 
+	// Emit summaries for aggregated events whose window has elapsed
+	if aggregator, ok := r.Recorder.(*observability.AggregatingRecorder); ok {
+		defer aggregator.Flush()
+	}
+
 	// Fetch the OptimizationPolicy instance
 	optimizationPolicy := &optipodv1alpha1.OptimizationPolicy{}
 	if err := r.Get(ctx, req.NamespacedName, optimizationPolicy); err != nil {
@@ -86,7 +157,19 @@ func (r *OptimizationPolicyReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, err
 	}
 
+	// A deleted policy releases its workloads before it goes away
+	if !optimizationPolicy.DeletionTimestamp.IsZero() {
+		if err := r.finalizePolicy(ctx, optimizationPolicy); err != nil {
+			log.Error(err, "Failed to release the workloads of the deleted policy", "policy", optimizationPolicy.Name)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
 	log.Info("Starting reconciliation", "policy", optimizationPolicy.Name, "namespace", optimizationPolicy.Namespace, "mode", optimizationPolicy.Spec.Mode)
+	if manual := manualReconcileRequest(optimizationPolicy); manual != "" {
+		log.Info("Manual reconcile requested", "policy", optimizationPolicy.Name, "reconcileNow", manual)
+	}
 
 	// Validate the policy
 	if err := r.validatePolicy(ctx, optimizationPolicy); err != nil {
@@ -113,40 +196,72 @@ func (r *OptimizationPolicyReconciler) Reconcile(ctx context.Context, req ctrl.R
 		}
 		observability.ReconciliationErrors.WithLabelValues(optimizationPolicy.Name, "validation_error").Inc()
 
+		if err := r.updatePolicyPhase(ctx, optimizationPolicy, optipodv1alpha1.PolicyPhaseInvalid); err != nil {
+			log.Error(err, "Failed to update policy phase")
+		}
+
 		// Don't requeue on validation errors - user needs to fix the policy
 		return ctrl.Result{}, nil
 	}
 
-	// Policy is valid, update status
-	log.Info("Policy validation passed, updating status to Ready", "policy", optimizationPolicy.Name)
-	if err := r.updatePolicyStatus(ctx, optimizationPolicy, metav1.Condition{
-		Type:               "Ready",
-		Status:             metav1.ConditionTrue,
-		Reason:             "PolicyValid",
-		Message:            "Policy is active and processing workloads",
-		LastTransitionTime: metav1.Now(),
-	}); err != nil {
-		log.Error(err, "Failed to update policy status to Ready")
-		return ctrl.Result{}, err
+	// Policy is valid, mark it Ready unless it already is; the message is refined with
+	// the processing summary once the workloads have been processed
+	if !meta.IsStatusConditionTrue(optimizationPolicy.Status.Conditions, ConditionTypeReady) {
+		log.Info("Policy validation passed, updating status to Ready", "policy", optimizationPolicy.Name)
+		if err := r.updatePolicyStatus(ctx, optimizationPolicy, metav1.Condition{
+			Type:               ConditionTypeReady,
+			Status:             metav1.ConditionTrue,
+			Reason:             "PolicyValid",
+			Message:            "Policy is active and processing workloads",
+			LastTransitionTime: metav1.Now(),
+		}); err != nil {
+			log.Error(err, "Failed to update policy status to Ready")
+			return ctrl.Result{}, err
+		}
+		log.Info("Successfully updated policy status to Ready", "policy", optimizationPolicy.Name)
+	}
+
+	// Informational queries never block processing, an invalid query is only reported
+	if err := r.checkInformationalQueries(ctx, optimizationPolicy); err != nil {
+		log.Error(err, "Failed to update informational queries condition")
 	}
-	log.Info("Successfully updated policy status to Ready", "policy", optimizationPolicy.Name)
 
 	// Use workload-centric processing with policy weights
-	processedCount, discoveredCount, err := r.processWorkloadsWithPolicySelection(ctx, optimizationPolicy)
+	summary, err := r.processWorkloadsWithPolicySelection(ctx, optimizationPolicy)
 	if err != nil {
 		log.Error(err, "Failed to process workloads with policy selection")
 		return ctrl.Result{}, err
 	}
 
+	// Out of time budget: save the progress and continue shortly where processing stopped
+	if summary.Checkpoint != nil {
+		if err := r.saveCheckpoint(ctx, optimizationPolicy, summary); err != nil {
+			log.Error(err, "Failed to save reconciliation checkpoint")
+			return ctrl.Result{}, err
+		}
+		log.Info("Reconcile time budget exhausted, continuing after the last processed workload",
+			"policy", optimizationPolicy.Name,
+			"processed", summary.Processed,
+			"discovered", summary.Discovered,
+			"lastWorkload", fmt.Sprintf("%s/%s", summary.Checkpoint.LastNamespace, summary.Checkpoint.LastName))
+		return ctrl.Result{RequeueAfter: checkpointRequeueDelay}, nil
+	}
+
+	// Calculate requeue interval with adaptive scheduling
+	summary.AdaptiveInterval = r.nextAdaptiveInterval(optimizationPolicy, summary)
+	requeueAfter := r.calculateRequeueInterval(optimizationPolicy, summary)
+
+	// A created or changed policy is summarized once, so misconfiguration shows in kubectl describe
+	if optimizationPolicy.Generation != optimizationPolicy.Status.ObservedGeneration {
+		r.recordPolicySummary(optimizationPolicy, summary)
+	}
+
 	// Update policy status with summary
-	if err := r.updatePolicySummary(ctx, optimizationPolicy, discoveredCount, processedCount); err != nil {
+	if err := r.updatePolicySummary(ctx, optimizationPolicy, summary, requeueAfter); err != nil {
 		log.Error(err, "Failed to update policy summary")
 		return ctrl.Result{}, err
 	}
 
-	// Calculate requeue interval with adaptive scheduling
-	requeueAfter := r.calculateRequeueInterval(optimizationPolicy, discoveredCount, processedCount)
-
 	log.Info("Successfully reconciled OptimizationPolicy", "policy", optimizationPolicy.Name, "requeueAfter", requeueAfter)
 	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
@@ -218,6 +333,9 @@ func (r *OptimizationPolicyReconciler) updatePolicyStatus(ctx context.Context, p
 		}
 
 		// Attempt to update the status
+		if err := r.LeaderTracker.checkLeader(); err != nil {
+			return err
+		}
 		if err := r.Status().Update(ctx, latest); err != nil {
 			if apierrors.IsConflict(err) {
 				// Conflict - retry with exponential backoff
@@ -245,12 +363,192 @@ func (r *OptimizationPolicyReconciler) updatePolicyStatus(ctx context.Context, p
 	return fmt.Errorf("failed to update policy status after %d attempts, last error: %w", maxRetries, lastErr)
 }
 
-// updatePolicySummary updates the policy status with summary information
+// updatePolicySummary updates the policy status with the phase, workload counts, next
+// reconciliation time and a Ready condition summarizing the processing outcome. Classified
+// failures, such as RBAC denials or exceeded quotas, are reported in the ApplyFailed condition,
+// recommendations held back as implausible in the SuspiciousRecommendation condition, and pods
+// without any series in the metrics backend in the MetricsNotFound condition. Changes held by
+// the startup readiness gate are reported in the MetricsWarmingUp condition.
 // Uses retry logic to handle concurrent modification conflicts
-func (r *OptimizationPolicyReconciler) updatePolicySummary(ctx context.Context, pol *optipodv1alpha1.OptimizationPolicy, discovered, processed int) error {
+func (r *OptimizationPolicyReconciler) updatePolicySummary(
+	ctx context.Context,
+	pol *optipodv1alpha1.OptimizationPolicy,
+	summary *reconcileSummary,
+	requeueAfter time.Duration,
+) error {
+	phase := summary.phase(pol)
+	message := summary.readyMessage()
+	applyFailed := summary.applyFailedCondition()
+	suspicious := summary.suspiciousCondition()
+	metricsNotFound := summary.metricsNotFoundCondition()
+	warmingUp := summary.metricsWarmingUpCondition()
+	manual := manualReconcileRequest(pol)
+
+	return r.updateStatusWithRetry(ctx, pol, "summary", func(latest *optipodv1alpha1.OptimizationPolicy) bool {
+		now := metav1.Now()
+		ready := meta.FindStatusCondition(latest.Status.Conditions, ConditionTypeReady)
+		failed := meta.FindStatusCondition(latest.Status.Conditions, ConditionTypeApplyFailed)
+		failedChanged := (applyFailed == nil) != (failed == nil) ||
+			(applyFailed != nil && (failed.Reason != applyFailed.Reason || failed.Message != applyFailed.Message))
+		flagged := meta.FindStatusCondition(latest.Status.Conditions, ConditionTypeSuspiciousRecommendation)
+		flaggedChanged := (suspicious == nil) != (flagged == nil) ||
+			(suspicious != nil && flagged.Message != suspicious.Message)
+		notFound := meta.FindStatusCondition(latest.Status.Conditions, ConditionTypeMetricsNotFound)
+		notFoundChanged := (metricsNotFound == nil) != (notFound == nil) ||
+			(metricsNotFound != nil && notFound.Message != metricsNotFound.Message)
+		warming := meta.FindStatusCondition(latest.Status.Conditions, ConditionTypeMetricsWarmingUp)
+		warmingChanged := (warmingUp == nil) != (warming == nil) ||
+			(warmingUp != nil && warming.Message != warmingUp.Message)
+
+		// Check if update is needed
+		needsUpdate := latest.Status.Phase != phase ||
+			latest.Status.WorkloadsDiscovered != summary.Discovered ||
+			latest.Status.WorkloadsProcessed != summary.Processed ||
+			latest.Status.WorkloadsApplied != summary.Applied ||
+			latest.Status.WorkloadsSkipped != summary.Skipped ||
+			latest.Status.WorkloadsPendingApproval != summary.PendingApproval ||
+			latest.Status.WorkloadsQuarantined != summary.Quarantined ||
+			!apiequality.Semantic.DeepEqual(latest.Status.FailingWorkloads, summary.FailingWorkloads) ||
+			ready == nil || ready.Status != metav1.ConditionTrue || ready.Message != message ||
+			failedChanged || flaggedChanged || notFoundChanged || warmingChanged || manual != "" ||
+			latest.Status.Checkpoint != nil ||
+			latest.Status.ObservedGeneration != pol.Generation ||
+			adaptiveIntervalChanged(latest.Status.AdaptiveInterval, summary.AdaptiveInterval) ||
+			latest.Status.LastReconciliation == nil ||
+			now.Sub(latest.Status.LastReconciliation.Time) > time.Minute
+
+		if !needsUpdate {
+			return false
+		}
+
+		// Update summary
+		latest.Status.Phase = phase
+		latest.Status.WorkloadsDiscovered = summary.Discovered
+		latest.Status.WorkloadsProcessed = summary.Processed
+		latest.Status.WorkloadsApplied = summary.Applied
+		latest.Status.WorkloadsSkipped = summary.Skipped
+		latest.Status.WorkloadsPendingApproval = summary.PendingApproval
+		latest.Status.WorkloadsQuarantined = summary.Quarantined
+		latest.Status.FailingWorkloads = summary.FailingWorkloads
+		latest.Status.Checkpoint = nil
+		latest.Status.ObservedGeneration = pol.Generation
+		latest.Status.LastReconciliation = &now
+		next := metav1.NewTime(now.Add(requeueAfter))
+		latest.Status.NextReconciliation = &next
+		latest.Status.AdaptiveInterval = nil
+		if summary.AdaptiveInterval > 0 {
+			latest.Status.AdaptiveInterval = &metav1.Duration{Duration: summary.AdaptiveInterval}
+		}
+		if manual != "" {
+			latest.Status.LastManualReconcile = manual
+		}
+		meta.SetStatusCondition(&latest.Status.Conditions, metav1.Condition{
+			Type:    ConditionTypeReady,
+			Status:  metav1.ConditionTrue,
+			Reason:  "PolicyValid",
+			Message: message,
+		})
+		if applyFailed != nil {
+			meta.SetStatusCondition(&latest.Status.Conditions, *applyFailed)
+		} else {
+			meta.RemoveStatusCondition(&latest.Status.Conditions, ConditionTypeApplyFailed)
+		}
+		if suspicious != nil {
+			meta.SetStatusCondition(&latest.Status.Conditions, *suspicious)
+		} else {
+			meta.RemoveStatusCondition(&latest.Status.Conditions, ConditionTypeSuspiciousRecommendation)
+		}
+		if metricsNotFound != nil {
+			meta.SetStatusCondition(&latest.Status.Conditions, *metricsNotFound)
+		} else {
+			meta.RemoveStatusCondition(&latest.Status.Conditions, ConditionTypeMetricsNotFound)
+		}
+		if warmingUp != nil {
+			meta.SetStatusCondition(&latest.Status.Conditions, *warmingUp)
+		} else {
+			meta.RemoveStatusCondition(&latest.Status.Conditions, ConditionTypeMetricsWarmingUp)
+		}
+		return true
+	})
+}
+
+// recordWorkloadQuarantined emits the event for a workload quarantined after failing in
+// consecutive reconciliations
+func (r *OptimizationPolicyReconciler) recordWorkloadQuarantined(pol *optipodv1alpha1.OptimizationPolicy, entry *optipodv1alpha1.FailingWorkload) {
+	if r.EventRecorder != nil {
+		r.EventRecorder.RecordWorkloadQuarantined(pol, entry.Name, entry.Namespace,
+			entry.ConsecutiveFailures, entry.QuarantinedUntil.Time, entry.LastError)
+	} else if r.Recorder != nil {
+		r.Recorder.Event(pol, corev1.EventTypeWarning, observability.EventReasonWorkloadQuarantined,
+			fmt.Sprintf("Workload %s/%s failed in %d consecutive reconciliations and is skipped until %s",
+				entry.Namespace, entry.Name, entry.ConsecutiveFailures, entry.QuarantinedUntil.UTC().Format(time.RFC3339)))
+	}
+}
+
+// recordPolicySummary emits the event summarizing the effective configuration of the policy
+func (r *OptimizationPolicyReconciler) recordPolicySummary(pol *optipodv1alpha1.OptimizationPolicy, summary *reconcileSummary) {
+	if r.EventRecorder != nil {
+		r.EventRecorder.RecordPolicySummary(pol, pol.Generation, summary.policyDescription(pol))
+	} else if r.Recorder != nil {
+		r.Recorder.Event(pol, corev1.EventTypeNormal, observability.EventReasonPolicySummary,
+			fmt.Sprintf("Policy generation %d is in effect: %s", pol.Generation, summary.policyDescription(pol)))
+	}
+}
+
+// updatePolicyPhase sets the policy phase if it changed.
+// Uses retry logic to handle concurrent modification conflicts
+func (r *OptimizationPolicyReconciler) updatePolicyPhase(
+	ctx context.Context,
+	pol *optipodv1alpha1.OptimizationPolicy,
+	phase optipodv1alpha1.PolicyPhase,
+) error {
+	return r.updateStatusWithRetry(ctx, pol, "phase", func(latest *optipodv1alpha1.OptimizationPolicy) bool {
+		if latest.Status.Phase == phase {
+			return false
+		}
+		latest.Status.Phase = phase
+		return true
+	})
+}
+
+// checkInformationalQueries validates the policy's informational queries against the metrics
+// backend and reports the result in the InformationalQueriesValid condition
+func (r *OptimizationPolicyReconciler) checkInformationalQueries(ctx context.Context, pol *optipodv1alpha1.OptimizationPolicy) error {
+	condition := metav1.Condition{
+		Type:    ConditionTypeInformationalQueriesValid,
+		Status:  metav1.ConditionTrue,
+		Reason:  "QueriesValid",
+		Message: "All informational queries are accepted by the metrics backend",
+	}
+	hasQueries := len(pol.Spec.MetricsConfig.InformationalQueries) > 0
+
+	if hasQueries && r.WorkloadProcessor != nil {
+		if err := r.WorkloadProcessor.validateInformationalQueries(ctx, pol); err != nil {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "QueriesInvalid"
+			condition.Message = err.Error()
+		}
+	}
+
+	return r.updateStatusWithRetry(ctx, pol, "informational queries", func(latest *optipodv1alpha1.OptimizationPolicy) bool {
+		if !hasQueries {
+			return meta.RemoveStatusCondition(&latest.Status.Conditions, ConditionTypeInformationalQueriesValid)
+		}
+		condition.ObservedGeneration = latest.Generation
+		return meta.SetStatusCondition(&latest.Status.Conditions, condition)
+	})
+}
+
+// updateStatusWithRetry fetches the latest policy, applies mutate and writes the status if mutate
+// reports a change. Conflicts are retried with exponential backoff.
+func (r *OptimizationPolicyReconciler) updateStatusWithRetry(
+	ctx context.Context,
+	pol *optipodv1alpha1.OptimizationPolicy,
+	what string,
+	mutate func(latest *optipodv1alpha1.OptimizationPolicy) bool,
+) error {
 	log := logf.FromContext(ctx)
 
-	// Retry configuration for summary updates
 	const maxRetries = 3
 	const baseDelay = 50 * time.Millisecond
 
@@ -261,30 +559,19 @@ func (r *OptimizationPolicyReconciler) updatePolicySummary(ctx context.Context,
 		if err := r.Get(ctx, client.ObjectKeyFromObject(pol), latest); err != nil {
 			if apierrors.IsNotFound(err) {
 				// Policy was deleted
-				log.Info("Policy was deleted during summary update", "policy", pol.Name)
+				log.Info("Policy was deleted during status update", "policy", pol.Name, "update", what)
 				return nil
 			}
 			return err
 		}
 
-		// Check if update is needed
-		now := metav1.Now()
-		needsUpdate := latest.Status.WorkloadsDiscovered != discovered ||
-			latest.Status.WorkloadsProcessed != processed ||
-			latest.Status.LastReconciliation == nil ||
-			now.Sub(latest.Status.LastReconciliation.Time) > time.Minute
-
-		if !needsUpdate {
-			// No update needed
+		if !mutate(latest) {
 			return nil
 		}
 
-		// Update summary
-		latest.Status.WorkloadsDiscovered = discovered
-		latest.Status.WorkloadsProcessed = processed
-		latest.Status.LastReconciliation = &now
-
-		// Attempt to update the status
+		if err := r.LeaderTracker.checkLeader(); err != nil {
+			return err
+		}
 		if err := r.Status().Update(ctx, latest); err != nil {
 			if apierrors.IsConflict(err) {
 				// Conflict - retry with exponential backoff
@@ -293,8 +580,9 @@ func (r *OptimizationPolicyReconciler) updatePolicySummary(ctx context.Context,
 				if delay > time.Second {
 					delay = time.Second
 				}
-				log.V(1).Info("Conflict updating policy summary, retrying",
+				log.V(1).Info("Conflict updating policy status, retrying",
 					"policy", pol.Name,
+					"update", what,
 					"attempt", attempt+1,
 					"delay", delay)
 				time.Sleep(delay)
@@ -304,12 +592,11 @@ func (r *OptimizationPolicyReconciler) updatePolicySummary(ctx context.Context,
 			return err
 		}
 
-		// Success
 		return nil
 	}
 
 	// All retries exhausted
-	return fmt.Errorf("failed to update policy summary after %d attempts, last error: %w", maxRetries, lastErr)
+	return fmt.Errorf("failed to update policy %s after %d attempts, last error: %w", what, maxRetries, lastErr)
 }
 
 // updateWorkloadTypeCounts updates the workload type counts in the policy status
@@ -372,6 +659,9 @@ func (r *OptimizationPolicyReconciler) updateWorkloadTypeCounts(ctx context.Cont
 		}
 
 		// Attempt to update the status
+		if err := r.LeaderTracker.checkLeader(); err != nil {
+			return err
+		}
 		if err := r.Status().Update(ctx, latest); err != nil {
 			if apierrors.IsConflict(err) {
 				// Conflict - retry with exponential backoff
@@ -404,19 +694,45 @@ func (r *OptimizationPolicyReconciler) updateWorkloadTypeCounts(ctx context.Cont
 	return fmt.Errorf("failed to update workload type counts after %d attempts, last error: %w", maxRetries, lastErr)
 }
 
-// calculateRequeueInterval calculates an adaptive requeue interval based on workload stability
-func (r *OptimizationPolicyReconciler) calculateRequeueInterval(policyObj *optipodv1alpha1.OptimizationPolicy, discovered, processed int) time.Duration {
-	// Base interval from policy
+// baseReconciliationInterval returns the policy's reconciliation interval, falling back to the
+// operator default
+func (r *OptimizationPolicyReconciler) baseReconciliationInterval(policyObj *optipodv1alpha1.OptimizationPolicy) time.Duration {
 	baseInterval := policyObj.Spec.ReconciliationInterval.Duration
+	if baseInterval == 0 && r.OperatorConfig != nil {
+		baseInterval = r.OperatorConfig.GetReconciliationInterval()
+	}
 	if baseInterval == 0 {
 		baseInterval = 5 * time.Minute // Default 5 minutes
 	}
+	return baseInterval
+}
+
+// calculateRequeueInterval calculates an adaptive requeue interval based on workload stability
+func (r *OptimizationPolicyReconciler) calculateRequeueInterval(policyObj *optipodv1alpha1.OptimizationPolicy, summary *reconcileSummary) time.Duration {
+	// Base interval from policy
+	baseInterval := r.baseReconciliationInterval(policyObj)
 
 	// For Disabled mode, use longer intervals
 	if policyObj.Spec.Mode == optipodv1alpha1.ModeDisabled {
 		return baseInterval * 4 // 20 minutes for disabled policies
 	}
 
+	// Changes waiting for a rollout to finish are retried soon rather than an interval later
+	if summary.AwaitingRollout > 0 && baseInterval > stableRolloutRequeueDelay {
+		return stableRolloutRequeueDelay
+	}
+
+	// Changes held by the startup readiness gate are applied soon after metrics start flowing
+	if summary.AwaitingMetricsReady > 0 && baseInterval > autoReadinessRequeueDelay {
+		return autoReadinessRequeueDelay
+	}
+
+	// An adaptive interval replaces the mode and activity based scaling below
+	if summary.AdaptiveInterval > 0 {
+		return summary.AdaptiveInterval + time.Duration(float64(summary.AdaptiveInterval)*0.1*jitterFraction())
+	}
+	discovered, processed := summary.Discovered, summary.Processed
+
 	// For Recommend mode, use slightly longer intervals since no changes are applied
 	if policyObj.Spec.Mode == optipodv1alpha1.ModeRecommend {
 		return baseInterval * 2 // 10 minutes for recommend mode
@@ -434,31 +750,59 @@ func (r *OptimizationPolicyReconciler) calculateRequeueInterval(policyObj *optip
 	}
 
 	// Active processing - use base interval but with some jitter to avoid thundering herd
-	jitter := time.Duration(float64(baseInterval) * 0.1 * (0.5 + 0.5*rng.Float64()))
+	jitter := time.Duration(float64(baseInterval) * 0.1 * (0.5 + 0.5*jitterFraction()))
 	return baseInterval + jitter
 }
 
-// processWorkloadsWithPolicySelection discovers all workloads and processes them with the best matching policy
-func (r *OptimizationPolicyReconciler) processWorkloadsWithPolicySelection(ctx context.Context, triggeringPolicy *optipodv1alpha1.OptimizationPolicy) (int, int, error) {
+// discoverWorkloads lists the workloads matching a policy and returns how many it matches. With a
+// discovery page size they are listed in pages from the API server, since the cache cannot paginate.
+func (r *OptimizationPolicyReconciler) discoverWorkloads(ctx context.Context, pol *optipodv1alpha1.OptimizationPolicy) ([]discovery.Workload, int, error) {
+	defer observability.ObservePhase(pol.Name, observability.PhaseDiscovery, time.Now())
+	// Discovery keeps workloads only up to just past the workload cap and counts the rest, so an
+	// overly broad selector is not held in full
+	opts := discovery.Options{ExcludedNamespaces: r.ExcludedNamespaces, MaxWorkloads: r.maxWorkloads(pol)}
+	if r.DiscoveryPageSize <= 0 || r.APIReader == nil {
+		return discovery.DiscoverAndCountWorkloads(ctx, r.Client, pol, opts)
+	}
+	opts.PageSize = r.DiscoveryPageSize
+	return discovery.DiscoverAndCountWorkloads(ctx, r.APIReader, pol, opts)
+}
+
+// maxWorkloads returns the workload cap of the policy, zero when none is enforced
+func (r *OptimizationPolicyReconciler) maxWorkloads(pol *optipodv1alpha1.OptimizationPolicy) int {
+	defaultMax := 0
+	if r.OperatorConfig != nil {
+		defaultMax = r.OperatorConfig.GetDefaultMaxWorkloads()
+	}
+	return pol.GetMaxWorkloads(defaultMax)
+}
+
+// processWorkloadsWithPolicySelection discovers all workloads, processes them with the best matching
+// policy and returns a summary of the outcome
+func (r *OptimizationPolicyReconciler) processWorkloadsWithPolicySelection(ctx context.Context, triggeringPolicy *optipodv1alpha1.OptimizationPolicy) (*reconcileSummary, error) {
 	log := logf.FromContext(ctx)
 
 	// Initialize policy selector if not already done
-	if r.PolicySelector == nil {
-		r.PolicySelector = policy.NewPolicySelector(r.Client)
-	}
+	r.policySelectorOnce.Do(func() {
+		if r.PolicySelector == nil {
+			r.PolicySelector = policy.NewPolicySelector(r.Client)
+			r.PolicySelector.SetExcludedNamespaces(r.ExcludedNamespaces)
+			r.PolicySelector.SetEventRecorder(r.EventRecorder)
+		}
+	})
 
 	// Discover all workloads that match this policy
 	log.Info("Starting workload discovery", "policy", triggeringPolicy.Name)
-	workloads, err := discovery.DiscoverWorkloads(ctx, r.Client, triggeringPolicy)
+	workloads, matched, err := r.discoverWorkloads(ctx, triggeringPolicy)
 	if err != nil {
 		log.Error(err, "Failed to discover workloads", "policy", triggeringPolicy.Name)
 		r.Recorder.Event(triggeringPolicy, corev1.EventTypeWarning, "DiscoveryFailed",
 			fmt.Sprintf("Failed to discover workloads: %v", err))
 		observability.ReconciliationErrors.WithLabelValues(triggeringPolicy.Name, "discovery_error").Inc()
-		return 0, 0, err
+		return nil, err
 	}
 
-	log.Info("Discovered workloads", "policy", triggeringPolicy.Name, "count", len(workloads))
+	log.Info("Discovered workloads", "policy", triggeringPolicy.Name, "count", matched)
 
 	// Count workloads by type for status reporting
 	workloadTypeCounts := make(map[optipodv1alpha1.WorkloadType]int)
@@ -476,23 +820,80 @@ func (r *OptimizationPolicyReconciler) processWorkloadsWithPolicySelection(ctx c
 	// Track workloads monitored
 	observability.WorkloadsMonitored.WithLabelValues(triggeringPolicy.Namespace, triggeringPolicy.Name).Set(float64(len(workloads)))
 
+	// Enforce the workload cap to guard against overly broad selectors
+	capExceeded := r.checkWorkloadCap(ctx, triggeringPolicy, matched)
+
+	// Honour the cluster-wide emergency brake
+	paused := r.checkOptimizationPaused(ctx, triggeringPolicy)
+
+	summary := &reconcileSummary{Discovered: matched, CapExceeded: capExceeded, Paused: paused}
 	if len(workloads) == 0 {
-		return 0, 0, nil
+		r.releaseOwnership(ctx, triggeringPolicy, nil)
+		observability.WorkloadsQuarantined.WithLabelValues(triggeringPolicy.Namespace, triggeringPolicy.Name).Set(0)
+		return summary, nil
 	}
 
+	// Workloads are processed in the policy's apply order
+	applyOrder := triggeringPolicy.Spec.UpdateStrategy.GetApplyOrder()
+	orderWorkloads(workloads, applyOrder)
+
+	// Resume a reconciliation that ran out of its time budget after its last processed workload
+	startedAt := metav1.Now()
+	checkpoint := resumeCheckpoint(triggeringPolicy)
+	resumeFrom := resumePosition(workloads, checkpoint, applyOrder)
+	if checkpoint != nil {
+		summary.resume(checkpoint)
+		startedAt = checkpoint.StartedAt
+		log.Info("Resuming reconciliation from checkpoint",
+			"policy", triggeringPolicy.Name,
+			"lastWorkload", fmt.Sprintf("%s/%s", checkpoint.LastNamespace, checkpoint.LastName),
+			"processed", checkpoint.Processed)
+	}
+	budgetStart := time.Now()
+	var last *discovery.Workload
+
+	// Workloads failing in consecutive reconciliations are skipped until their quarantine expires
+	quarantine := newWorkloadQuarantine(triggeringPolicy, r.Quarantine, checkpoint != nil)
+
+	// Workloads this policy owns, or whose owner could not be determined, keep its ownership annotation
+	owned := make(map[string]bool, len(workloads))
+
 	// Process each workload with the best matching policy
-	processedCount := 0
-	for _, workload := range workloads {
+	for i, workload := range workloads {
+		if i < resumeFrom {
+			continue
+		}
+
+		// Stop before the next workload when the manager is stopping or leadership is lost.
+		// The workload being applied is always finished, see WorkloadProcessor.ProcessWorkload.
+		if err := ctx.Err(); err != nil {
+			log.Info("Reconciliation interrupted, remaining workloads are processed after restart",
+				"policy", triggeringPolicy.Name,
+				"processed", summary.Processed,
+				"discovered", summary.Discovered)
+			return summary, err
+		}
+
+		// Checkpoint once the time budget is used up; at least one workload is processed per
+		// reconciliation so every reconciliation makes progress
+		if r.ReconcileBudget > 0 && last != nil && time.Since(budgetStart) >= r.ReconcileBudget {
+			summary.Checkpoint = summary.checkpoint(last, triggeringPolicy, startedAt)
+			quarantine.finish(triggeringPolicy, summary, false, time.Now())
+			return summary, nil
+		}
+		last = &workloads[i]
+
 		// Find the best policy for this workload
 		bestPolicy, err := r.PolicySelector.SelectBestPolicy(ctx, &workload)
 		if err != nil {
 			log.Error(err, "Failed to select best policy for workload",
 				"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name))
+			owned[workloadKey(workload.Kind, workload.Namespace, workload.Name)] = true
 			continue
 		}
 
-		// Only process if this policy is the best match
-		if bestPolicy.Name != triggeringPolicy.Name {
+		// Only process if this policy is the best match; policies in different namespaces may share a name
+		if bestPolicy.Name != triggeringPolicy.Name || bestPolicy.Namespace != triggeringPolicy.Namespace {
 			log.V(1).Info("Workload handled by higher priority policy, skipping",
 				"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
 				"triggeringPolicy", triggeringPolicy.Name,
@@ -502,6 +903,24 @@ func (r *OptimizationPolicyReconciler) processWorkloadsWithPolicySelection(ctx c
 			continue
 		}
 
+		// Record the ownership on the workload; only the winning policy annotates it
+		owned[workloadKey(workload.Kind, workload.Namespace, workload.Name)] = true
+		if err := r.annotateOwner(ctx, &workload, triggeringPolicy); err != nil {
+			if errors.Is(err, ErrNotLeader) {
+				return summary, err
+			}
+			log.Error(err, "Failed to annotate workload with its owning policy",
+				"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name))
+		}
+
+		if entry, ok := quarantine.quarantined(&workload, time.Now()); ok {
+			log.V(1).Info("Skipping quarantined workload",
+				"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+				"consecutiveFailures", entry.ConsecutiveFailures,
+				"quarantinedUntil", entry.QuarantinedUntil)
+			continue
+		}
+
 		// Process the workload with this policy
 		if r.WorkloadProcessor != nil {
 			log.Info("Processing workload with selected policy",
@@ -509,32 +928,186 @@ func (r *OptimizationPolicyReconciler) processWorkloadsWithPolicySelection(ctx c
 				"policy", bestPolicy.Name,
 				"weight", bestPolicy.GetWeight())
 
-			_, err := r.WorkloadProcessor.ProcessWorkload(ctx, &workload, bestPolicy)
+			// The pause is checked again for every workload so it halts a reconciliation in progress
+			if !summary.Paused && r.optimizationPaused() {
+				summary.Paused = r.checkOptimizationPaused(ctx, triggeringPolicy)
+			}
+
+			effectivePolicy := bestPolicy
+			if (capExceeded || summary.Paused) && effectivePolicy.Spec.Mode == optipodv1alpha1.ModeAuto {
+				// Refuse to apply while the cap is exceeded or optimization is paused, but keep computing recommendations
+				effectivePolicy = bestPolicy.DeepCopy()
+				effectivePolicy.Spec.Mode = optipodv1alpha1.ModeRecommend
+			}
+
+			status, err := r.WorkloadProcessor.ProcessWorkload(ctx, &workload, effectivePolicy)
+			summary.record(status, err)
 			if err != nil {
 				log.Error(err, "Failed to process workload",
 					"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
 					"policy", bestPolicy.Name)
-				r.Recorder.Event(triggeringPolicy, corev1.EventTypeWarning, "ProcessingFailed",
+				// Classified failures are reported under their cause, e.g. QuotaExceeded
+				reason := application.ErrorReason(err)
+				if reason == "" {
+					reason = "ProcessingFailed"
+				}
+				r.Recorder.Event(triggeringPolicy, corev1.EventTypeWarning, reason,
 					fmt.Sprintf("Failed to process workload %s/%s: %v", workload.Namespace, workload.Name, err))
-				observability.ReconciliationErrors.WithLabelValues(triggeringPolicy.Name, "processing_error").Inc()
+				errorType := application.ErrorType(err)
+				if errorType == "" {
+					errorType = "processing_error"
+				}
+				observability.ReconciliationErrors.WithLabelValues(triggeringPolicy.Name, errorType).Inc()
+				if errors.Is(err, application.ErrRBACDenied) && r.EventRecorder != nil {
+					r.EventRecorder.RecordRBACError(triggeringPolicy, workload.Name, workload.Namespace, "patch")
+				}
+				// A former leader stops; the remaining workloads are processed by the new leader
+				if errors.Is(err, ErrNotLeader) {
+					return summary, err
+				}
+				if entry := quarantine.recordFailure(&workload, err, time.Now()); entry != nil {
+					r.recordWorkloadQuarantined(triggeringPolicy, entry)
+				}
 				continue
 			}
-			processedCount++
+			quarantine.recordSuccess(&workload)
 		}
 	}
+	// A reconciliation resumed from a checkpoint did not see the workloads processed before it,
+	// and one whose discovery stopped at the workload cap did not see the workloads past it
+	complete := checkpoint == nil && !capExceeded
+	quarantine.finish(triggeringPolicy, summary, complete, time.Now())
+	if complete {
+		r.releaseOwnership(ctx, triggeringPolicy, owned)
+	}
 
 	log.Info("Completed workload processing with policy selection",
 		"policy", triggeringPolicy.Name,
-		"discovered", len(workloads),
-		"processed", processedCount)
+		"discovered", summary.Discovered,
+		"processed", summary.Processed,
+		"applied", summary.Applied,
+		"skipped", summary.Skipped)
 
-	return processedCount, len(workloads), nil
+	return summary, nil
 }
 
+// checkWorkloadCap reports whether the discovered workload count exceeds the policy cap
+// and keeps the TooManyWorkloads condition in sync with the result
+func (r *OptimizationPolicyReconciler) checkWorkloadCap(ctx context.Context, pol *optipodv1alpha1.OptimizationPolicy, discovered int) bool {
+	log := logf.FromContext(ctx)
+
+	maxWorkloads := r.maxWorkloads(pol)
+	exceeded := maxWorkloads > 0 && discovered > maxWorkloads
+
+	if !exceeded {
+		// Only clear the condition if it was previously set
+		if meta.FindStatusCondition(pol.Status.Conditions, ConditionTypeTooManyWorkloads) == nil {
+			return false
+		}
+		if err := r.updatePolicyStatus(ctx, pol, metav1.Condition{
+			Type:               ConditionTypeTooManyWorkloads,
+			Status:             metav1.ConditionFalse,
+			Reason:             "WithinCap",
+			Message:            fmt.Sprintf("Policy matches %d workload(s), within the cap of %d", discovered, maxWorkloads),
+			LastTransitionTime: metav1.Now(),
+		}); err != nil {
+			log.Error(err, "Failed to clear TooManyWorkloads condition", "policy", pol.Name)
+		}
+		return false
+	}
+
+	message := fmt.Sprintf("Policy matches %d workloads, exceeding the cap of %d. Changes will not be applied until the selector is narrowed or maxWorkloads is raised",
+		discovered, maxWorkloads)
+	log.Info("Workload cap exceeded, falling back to recommend-only",
+		"policy", pol.Name,
+		"discovered", discovered,
+		"maxWorkloads", maxWorkloads)
+
+	if err := r.updatePolicyStatus(ctx, pol, metav1.Condition{
+		Type:               ConditionTypeTooManyWorkloads,
+		Status:             metav1.ConditionTrue,
+		Reason:             "WorkloadCapExceeded",
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}); err != nil {
+		log.Error(err, "Failed to set TooManyWorkloads condition", "policy", pol.Name)
+	}
+
+	if r.Recorder != nil {
+		r.Recorder.Event(pol, corev1.EventTypeWarning, ConditionTypeTooManyWorkloads, message)
+	}
+
+	return true
+}
+
+// optimizationPaused reports whether optimization is paused cluster-wide
+func (r *OptimizationPolicyReconciler) optimizationPaused() bool {
+	return r.OperatorConfig != nil && r.OperatorConfig.IsOptimizationPaused()
+}
+
+// checkOptimizationPaused reports whether optimization is paused cluster-wide and keeps the
+// OptimizationPaused condition in sync with the result
+func (r *OptimizationPolicyReconciler) checkOptimizationPaused(ctx context.Context, pol *optipodv1alpha1.OptimizationPolicy) bool {
+	log := logf.FromContext(ctx)
+
+	if !r.optimizationPaused() {
+		// Only clear the condition if it was previously set
+		if !meta.IsStatusConditionTrue(pol.Status.Conditions, ConditionTypeOptimizationPaused) {
+			return false
+		}
+		log.Info("Optimization resumed cluster-wide", "policy", pol.Name)
+		if err := r.updatePolicyStatus(ctx, pol, metav1.Condition{
+			Type:               ConditionTypeOptimizationPaused,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Resumed",
+			Message:            "Optimization is no longer paused cluster-wide",
+			LastTransitionTime: metav1.Now(),
+		}); err != nil {
+			log.Error(err, "Failed to clear OptimizationPaused condition", "policy", pol.Name)
+		}
+		return false
+	}
+
+	log.Info("Optimization is paused cluster-wide, falling back to recommend-only", "policy", pol.Name)
+	if err := r.updatePolicyStatus(ctx, pol, metav1.Condition{
+		Type:               ConditionTypeOptimizationPaused,
+		Status:             metav1.ConditionTrue,
+		Reason:             "GlobalPause",
+		Message:            "Optimization is paused cluster-wide. Changes will not be applied until optimization-paused is cleared",
+		LastTransitionTime: metav1.Now(),
+	}); err != nil {
+		log.Error(err, "Failed to set OptimizationPaused condition", "policy", pol.Name)
+	}
+	return true
+}
+
+// manualReconcileRequest returns the policy's optipod.io/reconcile-now annotation when the
+// reconcile it asks for is not recorded in the status yet, or an empty string
+func manualReconcileRequest(pol *optipodv1alpha1.OptimizationPolicy) string {
+	value := pol.Annotations[optipodv1alpha1.AnnotationReconcileNow]
+	if value == pol.Status.LastManualReconcile {
+		return ""
+	}
+	return value
+}
+
+// policyChangePredicate passes the policy changes that need a reconcile: spec changes and label
+// or annotation changes, such as a new optipod.io/reconcile-now value. Status-only updates, which
+// the reconciler writes itself, are filtered out so they do not trigger another reconcile.
+var policyChangePredicate = predicate.Or(
+	predicate.GenerationChangedPredicate{},
+	predicate.AnnotationChangedPredicate{},
+	predicate.LabelChangedPredicate{},
+)
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *OptimizationPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&optipodv1alpha1.OptimizationPolicy{}).
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&optipodv1alpha1.OptimizationPolicy{}, ctrlbuilder.WithPredicates(policyChangePredicate)).
 		Named("optimizationpolicy").
-		Complete(r)
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles})
+	if r.MetricsRecovery != nil {
+		builder = builder.WatchesRawSource(r.MetricsRecovery.Source())
+	}
+	return builder.Complete(r)
 }
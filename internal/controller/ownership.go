@@ -0,0 +1,330 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+)
+
+// OwnershipFinalizer keeps a deleted policy until the managed-by-policy annotations it wrote are
+// removed. It is added when the policy first annotates a workload.
+const OwnershipFinalizer = "optipod.io/release-ownership"
+
+// ownedWorkload is a workload a policy annotated as its owner
+type ownedWorkload struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// ownershipTracker remembers the workloads each policy annotated as their owner, so annotations
+// are released without listing the workloads of the whole cluster. It is safe for concurrent use
+// across reconciles.
+type ownershipTracker struct {
+	mu       sync.Mutex
+	policies map[types.NamespacedName]map[string]ownedWorkload
+}
+
+// add records that the policy annotated the workload
+func (t *ownershipTracker) add(pol types.NamespacedName, workload ownedWorkload) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.policies == nil {
+		t.policies = make(map[types.NamespacedName]map[string]ownedWorkload)
+	}
+	if t.policies[pol] == nil {
+		t.policies[pol] = make(map[string]ownedWorkload)
+	}
+	t.policies[pol][workloadKey(workload.kind, workload.namespace, workload.name)] = workload
+}
+
+// owned returns the workloads the policy annotated, and false when the policy has not been
+// tracked since the operator started
+func (t *ownershipTracker) owned(pol types.NamespacedName) (map[string]ownedWorkload, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	owned, ok := t.policies[pol]
+	return maps.Clone(owned), ok
+}
+
+// set replaces the workloads the policy is known to have annotated
+func (t *ownershipTracker) set(pol types.NamespacedName, owned map[string]ownedWorkload) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.policies == nil {
+		t.policies = make(map[types.NamespacedName]map[string]ownedWorkload)
+	}
+	t.policies[pol] = owned
+}
+
+// forget drops the policy, e.g. once it is deleted
+func (t *ownershipTracker) forget(pol types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.policies, pol)
+}
+
+// policyOwnerRef returns the value of the managed-by-policy annotation for a policy
+func policyOwnerRef(pol *optipodv1alpha1.OptimizationPolicy) string {
+	return pol.Namespace + "/" + pol.Name
+}
+
+// workloadKey identifies a workload across kinds and namespaces
+func workloadKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// annotateOwner records on the workload that pol owns it. Only the annotation is patched, so
+// the pod template is left alone and no rollout is started. The policy gets the ownership
+// finalizer first, so the annotation is removed when the policy is deleted.
+func (r *OptimizationPolicyReconciler) annotateOwner(
+	ctx context.Context,
+	workload *discovery.Workload,
+	pol *optipodv1alpha1.OptimizationPolicy,
+) error {
+	if workload.Object == nil {
+		return nil
+	}
+	if !controllerutil.ContainsFinalizer(pol, OwnershipFinalizer) {
+		if err := r.patchOwnershipFinalizer(ctx, pol, true); err != nil {
+			return err
+		}
+	}
+	r.ownership.add(client.ObjectKeyFromObject(pol),
+		ownedWorkload{kind: workload.Kind, namespace: workload.Namespace, name: workload.Name})
+	return r.setOwnerAnnotation(ctx, workload.Object, policyOwnerRef(pol))
+}
+
+// releaseOwnership removes pol's managed-by-policy annotation, and the VPAs it exported, from the
+// workloads it no longer owns: those it no longer matches and those another policy won. owned
+// holds the workloadKey of every workload pol kept this reconciliation. Failures are logged and
+// retried next reconciliation.
+func (r *OptimizationPolicyReconciler) releaseOwnership(
+	ctx context.Context,
+	pol *optipodv1alpha1.OptimizationPolicy,
+	owned map[string]bool,
+) {
+	if err := r.clearOwnership(ctx, pol, owned); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to remove stale ownership annotations", "policy", pol.Name)
+	}
+	if err := r.WorkloadProcessor.pruneVPAs(ctx, pol, owned); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to delete stale exported VerticalPodAutoscalers", "policy", pol.Name)
+	}
+}
+
+// finalizePolicy removes the managed-by-policy annotations and exported VPAs of a deleted policy,
+// then its ownership finalizer so the deletion completes
+func (r *OptimizationPolicyReconciler) finalizePolicy(ctx context.Context, pol *optipodv1alpha1.OptimizationPolicy) error {
+	if !controllerutil.ContainsFinalizer(pol, OwnershipFinalizer) {
+		return nil
+	}
+	if err := r.clearOwnership(ctx, pol, nil); err != nil {
+		return err
+	}
+	if err := r.WorkloadProcessor.pruneVPAs(ctx, pol, nil); err != nil {
+		return err
+	}
+	if err := r.patchOwnershipFinalizer(ctx, pol, false); err != nil {
+		return err
+	}
+	r.ownership.forget(client.ObjectKeyFromObject(pol))
+	logf.FromContext(ctx).Info("Released the workloads of the deleted policy", "policy", pol.Name)
+	return nil
+}
+
+// patchOwnershipFinalizer adds or removes the ownership finalizer of a policy. The patch is
+// rejected if the policy changed since it was read, e.g. by a new leader, and is then retried
+// on the latest version of the policy.
+func (r *OptimizationPolicyReconciler) patchOwnershipFinalizer(
+	ctx context.Context,
+	pol *optipodv1alpha1.OptimizationPolicy,
+	add bool,
+) error {
+	reread := false
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if reread {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(pol), pol); err != nil {
+				return err
+			}
+		}
+		reread = true
+
+		if err := r.LeaderTracker.checkLeader(); err != nil {
+			return err
+		}
+		patchBase := client.MergeFromWithOptions(pol.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		if add {
+			controllerutil.AddFinalizer(pol, OwnershipFinalizer)
+		} else {
+			controllerutil.RemoveFinalizer(pol, OwnershipFinalizer)
+		}
+		if err := r.Patch(ctx, pol, patchBase); err != nil {
+			return fmt.Errorf("failed to update the finalizers of policy %s: %w", pol.Name, err)
+		}
+		return nil
+	})
+}
+
+// clearOwnership removes pol's managed-by-policy annotation from every workload it annotated
+// that is not in owned. The workloads it annotated are tracked in memory; after an operator
+// restart they are found once by listing the workloads of the cluster.
+func (r *OptimizationPolicyReconciler) clearOwnership(
+	ctx context.Context,
+	pol *optipodv1alpha1.OptimizationPolicy,
+	owned map[string]bool,
+) error {
+	ref := policyOwnerRef(pol)
+	key := client.ObjectKeyFromObject(pol)
+	tracked, ok := r.ownership.owned(key)
+	if !ok {
+		var err error
+		if tracked, err = r.listOwnedWorkloads(ctx, ref); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for k, workload := range tracked {
+		if owned[k] {
+			continue
+		}
+		obj, err := r.getOwnedWorkload(ctx, workload)
+		if apierrors.IsNotFound(err) {
+			delete(tracked, k)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if obj.GetAnnotations()[optipodv1alpha1.AnnotationManagedByPolicy] == ref {
+			logf.FromContext(ctx).Info("Policy no longer owns workload, removing ownership annotation",
+				"workload", fmt.Sprintf("%s/%s", workload.namespace, workload.name),
+				"kind", workload.kind,
+				"policy", ref)
+			if err := r.setOwnerAnnotation(ctx, obj, ""); err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		delete(tracked, k)
+	}
+	r.ownership.set(key, tracked)
+	return errors.Join(errs...)
+}
+
+// getOwnedWorkload reads a workload a policy annotated
+func (r *OptimizationPolicyReconciler) getOwnedWorkload(ctx context.Context, workload ownedWorkload) (client.Object, error) {
+	var obj client.Object
+	switch workload.kind {
+	case KindDeployment:
+		obj = &appsv1.Deployment{}
+	case KindStatefulSet:
+		obj = &appsv1.StatefulSet{}
+	case KindDaemonSet:
+		obj = &appsv1.DaemonSet{}
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %s", workload.kind)
+	}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: workload.namespace, Name: workload.name}, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// listOwnedWorkloads lists the workloads whose managed-by-policy annotation is ref
+func (r *OptimizationPolicyReconciler) listOwnedWorkloads(ctx context.Context, ref string) (map[string]ownedWorkload, error) {
+	lists := []struct {
+		kind string
+		list client.ObjectList
+	}{
+		{KindDeployment, &appsv1.DeploymentList{}},
+		{KindStatefulSet, &appsv1.StatefulSetList{}},
+		{KindDaemonSet, &appsv1.DaemonSetList{}},
+	}
+	owned := make(map[string]ownedWorkload)
+	for _, l := range lists {
+		kind := l.kind
+		if err := r.List(ctx, l.list); err != nil {
+			return nil, fmt.Errorf("failed to list %ss: %w", kind, err)
+		}
+		items, err := apimeta.ExtractList(l.list)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s list: %w", kind, err)
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok || obj.GetAnnotations()[optipodv1alpha1.AnnotationManagedByPolicy] != ref {
+				continue
+			}
+			owned[workloadKey(kind, obj.GetNamespace(), obj.GetName())] =
+				ownedWorkload{kind: kind, namespace: obj.GetNamespace(), name: obj.GetName()}
+		}
+	}
+	return owned, nil
+}
+
+// setOwnerAnnotation sets the managed-by-policy annotation of a workload to value, or removes it
+// when value is empty, with a merge patch of the annotation alone. The patch is rejected if the
+// workload changed since it was read, so a replica that lost leadership without noticing yet
+// cannot overwrite the new leader's ownership; the next reconcile reads the workload again.
+func (r *OptimizationPolicyReconciler) setOwnerAnnotation(ctx context.Context, obj client.Object, value string) error {
+	annotations := obj.GetAnnotations()
+	if annotations[optipodv1alpha1.AnnotationManagedByPolicy] == value {
+		return nil
+	}
+
+	patchBase := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	updated := maps.Clone(annotations)
+	if updated == nil {
+		updated = make(map[string]string, 1)
+	}
+	if value == "" {
+		delete(updated, optipodv1alpha1.AnnotationManagedByPolicy)
+	} else {
+		updated[optipodv1alpha1.AnnotationManagedByPolicy] = value
+	}
+	obj.SetAnnotations(updated)
+
+	if err := r.LeaderTracker.checkLeader(); err != nil {
+		return err
+	}
+	if err := r.Patch(ctx, obj, patchBase); err != nil {
+		return fmt.Errorf("failed to update ownership annotation of %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// ownerAnnotations returns the managed-by-policy annotation of each test deployment
+func ownerAnnotations(t *testing.T, c client.Client, deployments int) []string {
+	t.Helper()
+	owners := make([]string, 0, deployments)
+	for i := 0; i < deployments; i++ {
+		deployment := &appsv1.Deployment{}
+		key := client.ObjectKey{Namespace: TestNamespace, Name: fmt.Sprintf("%s-%d", TestWorkloadName, i)}
+		if err := c.Get(context.Background(), key, deployment); err != nil {
+			t.Fatalf("failed to get deployment: %v", err)
+		}
+		owners = append(owners, deployment.Annotations[optipodv1alpha1.AnnotationManagedByPolicy])
+	}
+	return owners
+}
+
+func TestProcessWorkloads_OwnershipAnnotation(t *testing.T) {
+	ctx := context.Background()
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	objects := append(createTestObjects(2), policy)
+	reconciler, fakeClient := createTestReconciler(&recordingApplicationEngine{}, objects...)
+	// The policy is patched conditionally on the resourceVersion it was read with
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), policy); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+
+	before := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, client.ObjectKey{Namespace: TestNamespace, Name: TestWorkloadName + "-0"}, before); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+
+	if _, err := reconciler.processWorkloadsWithPolicySelection(ctx, policy); err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+	for i, owner := range ownerAnnotations(t, fakeClient, 2) {
+		if owner != TestNamespace+"/test-policy" {
+			t.Errorf("deployment %d owner = %q, want %s/test-policy", i, owner, TestNamespace)
+		}
+	}
+
+	// The annotation is metadata only
+	after := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(before), after); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if !equality.Semantic.DeepEqual(before.Spec.Template, after.Spec.Template) {
+		t.Errorf("pod template changed:\nbefore %+v\nafter  %+v", before.Spec.Template, after.Spec.Template)
+	}
+
+	// A policy that no longer matches its workloads releases them
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), policy); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	policy.Spec.Selector.WorkloadSelector.MatchLabels = map[string]string{"app": "other"}
+	if err := fakeClient.Update(ctx, policy); err != nil {
+		t.Fatalf("failed to update policy: %v", err)
+	}
+	if _, err := reconciler.processWorkloadsWithPolicySelection(ctx, policy); err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+	for i, owner := range ownerAnnotations(t, fakeClient, 2) {
+		if owner != "" {
+			t.Errorf("deployment %d owner = %q after the policy stopped matching, want none", i, owner)
+		}
+	}
+}
+
+func TestProcessWorkloads_OwnershipChange(t *testing.T) {
+	ctx := context.Background()
+	current := createTestPolicy(optipodv1alpha1.ModeAuto)
+	objects := append(createTestObjects(2), current)
+	reconciler, fakeClient := createTestReconciler(&recordingApplicationEngine{}, objects...)
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(current), current); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+
+	if _, err := reconciler.processWorkloadsWithPolicySelection(ctx, current); err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+
+	// A higher weight policy takes the workloads over
+	weight := int32(200)
+	winner := createTestPolicy(optipodv1alpha1.ModeAuto)
+	winner.Name = "winning-policy"
+	winner.Spec.Weight = &weight
+	if err := fakeClient.Create(ctx, winner); err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+
+	// The losing policy releases the workloads without annotating them for the winner
+	if _, err := reconciler.processWorkloadsWithPolicySelection(ctx, current); err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+	for i, owner := range ownerAnnotations(t, fakeClient, 2) {
+		if owner != "" {
+			t.Errorf("deployment %d owner = %q after losing it, want none", i, owner)
+		}
+	}
+
+	if _, err := reconciler.processWorkloadsWithPolicySelection(ctx, winner); err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+	for i, owner := range ownerAnnotations(t, fakeClient, 2) {
+		if owner != TestNamespace+"/winning-policy" {
+			t.Errorf("deployment %d owner = %q, want %s/winning-policy", i, owner, TestNamespace)
+		}
+	}
+
+	// Reconciling the loser again leaves the winner's annotation alone
+	if _, err := reconciler.processWorkloadsWithPolicySelection(ctx, current); err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+	for i, owner := range ownerAnnotations(t, fakeClient, 2) {
+		if owner != TestNamespace+"/winning-policy" {
+			t.Errorf("deployment %d owner = %q after the loser reconciled, want %s/winning-policy", i, owner, TestNamespace)
+		}
+	}
+}
+
+func TestSetOwnerAnnotation_FormerLeader(t *testing.T) {
+	deployment := createTestWorkload(TestContainerName).Object.(*appsv1.Deployment)
+	reconciler, fakeClient := createTestReconciler(&recordingApplicationEngine{}, deployment.DeepCopy())
+	reconciler.LeaderTracker = newElectedLeaderTracker()
+	reconciler.LeaderTracker.lost.Store(true)
+
+	if err := reconciler.setOwnerAnnotation(context.Background(), deployment, "default/test-policy"); err == nil {
+		t.Fatal("setOwnerAnnotation() error = nil, want ErrNotLeader")
+	}
+	stored := &appsv1.Deployment{}
+	if err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(deployment), stored); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if _, ok := stored.Annotations[optipodv1alpha1.AnnotationManagedByPolicy]; ok {
+		t.Errorf("annotations = %v, want no ownership written by a former leader", stored.Annotations)
+	}
+}
+
+func TestSetOwnerAnnotation_StaleWorkload(t *testing.T) {
+	ctx := context.Background()
+	reconciler, fakeClient := createTestReconciler(&recordingApplicationEngine{}, createTestObjects(1)...)
+	stale := &appsv1.Deployment{}
+	key := client.ObjectKey{Namespace: TestNamespace, Name: TestWorkloadName + "-0"}
+	if err := fakeClient.Get(ctx, key, stale); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+
+	// The new leader annotates the workload after this replica read it
+	current := stale.DeepCopy()
+	current.Annotations = map[string]string{optipodv1alpha1.AnnotationManagedByPolicy: "default/new-leader-policy"}
+	if err := fakeClient.Update(ctx, current); err != nil {
+		t.Fatalf("failed to update deployment: %v", err)
+	}
+
+	if err := reconciler.setOwnerAnnotation(ctx, stale, "default/test-policy"); !apierrors.IsConflict(err) {
+		t.Fatalf("setOwnerAnnotation() error = %v, want a conflict", err)
+	}
+	if owners := ownerAnnotations(t, fakeClient, 1); owners[0] != "default/new-leader-policy" {
+		t.Errorf("owner = %q, want the new leader's annotation kept", owners[0])
+	}
+}
+
+func TestReconcile_DeletedPolicyReleasesWorkloads(t *testing.T) {
+	ctx := context.Background()
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	objects := append(createTestObjects(2), policy)
+	reconciler, fakeClient := createTestReconciler(&recordingApplicationEngine{}, objects...)
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), policy); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+
+	if _, err := reconciler.processWorkloadsWithPolicySelection(ctx, policy); err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+	stored := &optipodv1alpha1.OptimizationPolicy{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), stored); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	if !controllerutil.ContainsFinalizer(stored, OwnershipFinalizer) {
+		t.Fatalf("finalizers = %v, want %s once the policy annotated its workloads", stored.Finalizers, OwnershipFinalizer)
+	}
+
+	// After an operator restart the annotated workloads are found again
+	restarted, _ := createTestReconciler(&recordingApplicationEngine{})
+	restarted.Client = fakeClient
+
+	if err := fakeClient.Delete(ctx, stored); err != nil {
+		t.Fatalf("failed to delete policy: %v", err)
+	}
+	if _, err := restarted.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	for i, owner := range ownerAnnotations(t, fakeClient, 2) {
+		if owner != "" {
+			t.Errorf("deployment %d owner = %q after the policy was deleted, want none", i, owner)
+		}
+	}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), stored); !apierrors.IsNotFound(err) {
+		t.Errorf("Get() error = %v, want the policy gone once its finalizer is removed", err)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// metricsScrapeGracePeriod is how long a pod runs before the backend having no series for it is
+// reported as a label mismatch. A younger pod may not have been scraped yet, e.g. with a 1m scrape
+// interval, so its missing metrics are reported like any other.
+const metricsScrapeGracePeriod = 2 * time.Minute
+
+// missingContainerMetrics is a container whose metrics could not be collected
+type missingContainerMetrics struct {
+	name   string
+	reason string
+	auto   bool
+
+	// noMatchingSeries is set when the backend has no series at all for the container's pod
+	noMatchingSeries bool
+
+	// bounds are the container's resource bounds, whose minimums are the default floor
+	bounds optipodv1alpha1.ResourceBounds
+}
+
+// newMissingContainerMetrics records a container whose metrics could not be collected
+func newMissingContainerMetrics(
+	name string,
+	err error,
+	mode optipodv1alpha1.PolicyMode,
+	containerPolicy *optipodv1alpha1.OptimizationPolicy,
+) missingContainerMetrics {
+	return missingContainerMetrics{
+		name:             name,
+		reason:           err.Error(),
+		auto:             mode == optipodv1alpha1.ModeAuto,
+		noMatchingSeries: errors.Is(err, metrics.ErrNoMatchingSeries),
+		bounds:           containerPolicy.Spec.ResourceBounds,
+	}
+}
+
+// awaitingFirstScrape clears noMatchingSeries when the pod the metrics were queried for started
+// within metricsScrapeGracePeriod. A pod whose start time cannot be read keeps it.
+func (m *missingContainerMetrics) awaitingFirstScrape(ctx context.Context, c client.Client, namespace, podName string, now time.Time) {
+	if !m.noMatchingSeries || c == nil {
+		return
+	}
+	pod := &corev1.Pod{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: podName}, pod); err != nil || pod.Status.StartTime == nil {
+		return
+	}
+	if age := now.Sub(pod.Status.StartTime.Time); age < metricsScrapeGracePeriod {
+		m.noMatchingSeries = false
+		m.reason = fmt.Sprintf("no metrics yet for pod %s/%s, which started %s ago", namespace, podName, age.Round(time.Second))
+	}
+}
+
+// flooredContainer is the recommendation raising a container without metrics to the floor
+type flooredContainer struct {
+	containerRec optipodv1alpha1.ContainerRecommendation
+	rec          *recommendation.Recommendation
+	auto         bool
+}
+
+// skippedForMissingMetrics records every container without metrics as holding the workload back
+func skippedForMissingMetrics(missing []missingContainerMetrics) []optipodv1alpha1.ContainerWithoutMetrics {
+	outcomes := make([]optipodv1alpha1.ContainerWithoutMetrics, 0, len(missing))
+	for _, container := range missing {
+		outcomes = append(outcomes, optipodv1alpha1.ContainerWithoutMetrics{
+			Container:        container.name,
+			Outcome:          optipodv1alpha1.OutcomeWorkloadSkipped,
+			Reason:           container.reason,
+			NoMatchingSeries: container.noMatchingSeries,
+		})
+	}
+	return outcomes
+}
+
+// partialMetricsRecommendations applies the policy's partialMetrics action to the containers
+// without metrics of a workload whose other containers were sized. With the Floor action, a
+// container with a request below the floor gets a recommendation raising it to the floor;
+// requests are never lowered, and limits are kept as they are. Every other container is left as
+// it is; the third result lists those the policy applies to, which the apply keeps unchanged.
+func partialMetricsRecommendations(
+	missing []missingContainerMetrics,
+	effectiveResources map[string]corev1.ResourceRequirements,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) ([]optipodv1alpha1.ContainerWithoutMetrics, []flooredContainer, []string) {
+	partial := policy.Spec.PartialMetrics
+	outcomes := make([]optipodv1alpha1.ContainerWithoutMetrics, 0, len(missing))
+	var floored []flooredContainer
+	for _, container := range missing {
+		outcome := optipodv1alpha1.ContainerWithoutMetrics{
+			Container:        container.name,
+			Outcome:          optipodv1alpha1.OutcomeLeftAsIs,
+			Reason:           container.reason,
+			NoMatchingSeries: container.noMatchingSeries,
+		}
+		if partial.GetAction() != optipodv1alpha1.PartialMetricsFloor {
+			outcomes = append(outcomes, outcome)
+			continue
+		}
+
+		cpuFloor, memoryFloor := partial.Floor(container.bounds)
+		requests := effectiveResources[container.name].Requests
+		cpu, cpuRaised := raiseToFloor(requests.Cpu(), cpuFloor)
+		memory, memoryRaised := raiseToFloor(requests.Memory(), memoryFloor)
+		// A request that would stay unset is not written as zero, so the container is left alone
+		raised := (cpuRaised && policy.OptimizesCPU()) || (memoryRaised && policy.OptimizesMemory())
+		unset := (cpu.IsZero() && policy.OptimizesCPU()) || (memory.IsZero() && policy.OptimizesMemory())
+		if !raised || unset {
+			outcomes = append(outcomes, outcome)
+			continue
+		}
+
+		explanation := fmt.Sprintf("No metrics (%s); requests raised to the floor of CPU %s and memory %s",
+			container.reason, cpuFloor.String(), memoryFloor.String())
+		containerRec := optipodv1alpha1.ContainerRecommendation{Container: container.name, Explanation: explanation}
+		if policy.OptimizesCPU() {
+			containerRec.CPU = &cpu
+		}
+		if policy.OptimizesMemory() {
+			containerRec.Memory = &memory
+		}
+		floored = append(floored, flooredContainer{
+			containerRec: containerRec,
+			// Nothing is known to size the limits by, and deriving them from the floor could lower them
+			rec:  &recommendation.Recommendation{CPU: cpu, Memory: memory, Explanation: explanation, KeepLimits: true},
+			auto: container.auto,
+		})
+		outcome.Outcome = optipodv1alpha1.OutcomeRaisedToFloor
+		outcomes = append(outcomes, outcome)
+	}
+
+	var leftAsIs []string
+	for i, container := range missing {
+		if container.auto && outcomes[i].Outcome == optipodv1alpha1.OutcomeLeftAsIs {
+			leftAsIs = append(leftAsIs, container.name)
+		}
+	}
+	return outcomes, floored, leftAsIs
+}
+
+// raiseToFloor returns the larger of a request and its floor, and whether the request was raised
+func raiseToFloor(request *resource.Quantity, floor resource.Quantity) (resource.Quantity, bool) {
+	if request.Cmp(floor) >= 0 {
+		return request.DeepCopy(), false
+	}
+	return floor.DeepCopy(), true
+}
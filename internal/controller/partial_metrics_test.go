@@ -0,0 +1,291 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/metrics"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// partialMetricsProvider has no metrics for the containers in missing, failing with err or a
+// generic error when it is nil
+type partialMetricsProvider struct {
+	*mockMetricsProvider
+	missing map[string]bool
+	err     error
+}
+
+func (m *partialMetricsProvider) GetContainerMetrics(ctx context.Context, namespace, podName, containerName string, window time.Duration) (*metrics.ContainerMetrics, error) {
+	if m.missing[containerName] {
+		if m.err != nil {
+			return nil, m.err
+		}
+		return nil, errors.New("no samples for container")
+	}
+	return m.mockMetricsProvider.GetContainerMetrics(ctx, namespace, podName, containerName, window)
+}
+
+// newPartialMetricsTestWorkload returns a workload with an "app" container and a "proxy"
+// sidecar requesting the given resources
+func newPartialMetricsTestWorkload(proxyCPU, proxyMemory string) *discovery.Workload {
+	workload := createTestWorkload("app", "proxy")
+	containers := workload.Object.(*appsv1.Deployment).Spec.Template.Spec.Containers
+	containers[1].Resources.Requests = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(proxyCPU),
+		corev1.ResourceMemory: resource.MustParse(proxyMemory),
+	}
+	return workload
+}
+
+func TestProcessWorkload_PartialMetrics(t *testing.T) {
+	tests := []struct {
+		name         string
+		partial      *optipodv1alpha1.PartialMetrics
+		missing      []string
+		proxyCPU     string
+		proxyMemory  string
+		wantStatus   string
+		wantOutcome  string
+		wantApplied  []string
+		wantKept     []string
+		wantProxyCPU string
+	}{
+		{
+			name:        "the whole workload is skipped by default",
+			missing:     []string{"proxy"},
+			proxyCPU:    "10m",
+			proxyMemory: "16Mi",
+			wantStatus:  StatusSkipped,
+			wantOutcome: optipodv1alpha1.OutcomeWorkloadSkipped,
+		},
+		{
+			name:        "containers with metrics are sized, the others left as they are",
+			partial:     &optipodv1alpha1.PartialMetrics{Action: optipodv1alpha1.PartialMetricsLeaveAsIs},
+			missing:     []string{"proxy"},
+			proxyCPU:    "10m",
+			proxyMemory: "16Mi",
+			wantStatus:  StatusApplied,
+			wantOutcome: optipodv1alpha1.OutcomeLeftAsIs,
+			wantApplied: []string{"app"},
+			wantKept:    []string{"proxy"},
+		},
+		{
+			name:         "requests below the floor are raised to the resource bounds minimum",
+			partial:      &optipodv1alpha1.PartialMetrics{Action: optipodv1alpha1.PartialMetricsFloor},
+			missing:      []string{"proxy"},
+			proxyCPU:     "10m",
+			proxyMemory:  "16Mi",
+			wantStatus:   StatusApplied,
+			wantOutcome:  optipodv1alpha1.OutcomeRaisedToFloor,
+			wantApplied:  []string{"app", "proxy"},
+			wantProxyCPU: "50m",
+		},
+		{
+			name: "an explicit floor takes precedence over the resource bounds",
+			partial: &optipodv1alpha1.PartialMetrics{
+				Action: optipodv1alpha1.PartialMetricsFloor,
+				CPU:    ptrQuantity("100m"),
+			},
+			missing:      []string{"proxy"},
+			proxyCPU:     "10m",
+			proxyMemory:  "16Mi",
+			wantStatus:   StatusApplied,
+			wantOutcome:  optipodv1alpha1.OutcomeRaisedToFloor,
+			wantApplied:  []string{"app", "proxy"},
+			wantProxyCPU: "100m",
+		},
+		{
+			name:        "requests above the floor are never lowered",
+			partial:     &optipodv1alpha1.PartialMetrics{Action: optipodv1alpha1.PartialMetricsFloor},
+			missing:     []string{"proxy"},
+			proxyCPU:    "500m",
+			proxyMemory: "512Mi",
+			wantStatus:  StatusApplied,
+			wantOutcome: optipodv1alpha1.OutcomeLeftAsIs,
+			wantApplied: []string{"app"},
+			wantKept:    []string{"proxy"},
+		},
+		{
+			name:        "a workload without any metrics is skipped whatever the action",
+			partial:     &optipodv1alpha1.PartialMetrics{Action: optipodv1alpha1.PartialMetricsLeaveAsIs},
+			missing:     []string{"app", "proxy"},
+			proxyCPU:    "10m",
+			proxyMemory: "16Mi",
+			wantStatus:  StatusSkipped,
+			wantOutcome: optipodv1alpha1.OutcomeWorkloadSkipped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+			policy.Spec.PartialMetrics = tt.partial
+
+			missing := make(map[string]bool, len(tt.missing))
+			for _, name := range tt.missing {
+				missing[name] = true
+			}
+			provider := &partialMetricsProvider{mockMetricsProvider: createTestMetricsProvider(), missing: missing}
+			appEngine := &recordingApplicationEngine{}
+			processor := NewWorkloadProcessor(provider, recommendation.NewEngine(), appEngine, nil)
+
+			status, err := processor.ProcessWorkload(context.Background(), newPartialMetricsTestWorkload(tt.proxyCPU, tt.proxyMemory), policy)
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			if status.Status != tt.wantStatus {
+				t.Errorf("status = %q (%s), want %q", status.Status, status.Reason, tt.wantStatus)
+			}
+			if tt.wantStatus == StatusSkipped && !strings.HasPrefix(status.Reason, missingMetricsReason) {
+				t.Errorf("reason = %q, want it to report the missing metrics", status.Reason)
+			}
+
+			if len(status.ContainersWithoutMetrics) != len(tt.missing) {
+				t.Fatalf("containersWithoutMetrics = %+v, want %v", status.ContainersWithoutMetrics, tt.missing)
+			}
+			for _, container := range status.ContainersWithoutMetrics {
+				if !missing[container.Container] || container.Outcome != tt.wantOutcome || container.Reason == "" {
+					t.Errorf("container without metrics = %+v, want outcome %s with a reason", container, tt.wantOutcome)
+				}
+			}
+
+			if !slices.Equal(appEngine.appliedContainers, tt.wantApplied) {
+				t.Errorf("applied containers = %v, want %v", appEngine.appliedContainers, tt.wantApplied)
+			}
+			// Containers left as they are stay in the apply unchanged, so their resources are kept
+			if !slices.Equal(appEngine.keptContainers, tt.wantKept) {
+				t.Errorf("kept containers = %v, want %v", appEngine.keptContainers, tt.wantKept)
+			}
+			if tt.wantProxyCPU != "" {
+				// A container raised to the floor keeps its limits
+				if !slices.Equal(appEngine.keptLimits, []string{"proxy"}) {
+					t.Errorf("containers keeping their limits = %v, want [proxy]", appEngine.keptLimits)
+				}
+				proxy := findRecommendation(status.Recommendations, "proxy")
+				if proxy == nil || proxy.CPU.Cmp(resource.MustParse(tt.wantProxyCPU)) != 0 {
+					t.Errorf("proxy recommendation = %+v, want CPU %s", proxy, tt.wantProxyCPU)
+				}
+			}
+		})
+	}
+}
+
+// ptrQuantity returns a pointer to a parsed quantity
+func ptrQuantity(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+func TestProcessWorkload_NoMatchingSeries(t *testing.T) {
+	provider := &partialMetricsProvider{
+		mockMetricsProvider: createTestMetricsProvider(),
+		missing:             map[string]bool{"proxy": true},
+		err:                 fmt.Errorf("%w for pod default/test-pod", metrics.ErrNoMatchingSeries),
+	}
+	processor := NewWorkloadProcessor(provider, recommendation.NewEngine(), &recordingApplicationEngine{}, nil)
+
+	status, err := processor.ProcessWorkload(context.Background(), newPartialMetricsTestWorkload("10m", "16Mi"),
+		createTestPolicy(optipodv1alpha1.ModeAuto))
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusSkipped {
+		t.Errorf("status = %q (%s), want %q", status.Status, status.Reason, StatusSkipped)
+	}
+	if len(status.ContainersWithoutMetrics) != 1 || !status.ContainersWithoutMetrics[0].NoMatchingSeries {
+		t.Fatalf("containersWithoutMetrics = %+v, want proxy without matching series", status.ContainersWithoutMetrics)
+	}
+
+	// The workload is reported in the policy's MetricsNotFound condition
+	summary := &reconcileSummary{}
+	summary.record(status, nil)
+	condition := summary.metricsNotFoundCondition()
+	if condition == nil || condition.Type != ConditionTypeMetricsNotFound || condition.Reason != "NoMatchingSeries" ||
+		!strings.Contains(condition.Message, TestNamespace+"/"+TestWorkloadName) {
+		t.Errorf("metricsNotFoundCondition() = %+v, want the workload reported", condition)
+	}
+
+	// Containers merely without samples are not
+	provider.err = nil
+	status, err = processor.ProcessWorkload(context.Background(), newPartialMetricsTestWorkload("10m", "16Mi"),
+		createTestPolicy(optipodv1alpha1.ModeAuto))
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	summary = &reconcileSummary{}
+	summary.record(status, nil)
+	if condition := summary.metricsNotFoundCondition(); condition != nil {
+		t.Errorf("metricsNotFoundCondition() = %+v, want nil without a series mismatch", condition)
+	}
+}
+
+func TestProcessWorkload_NoMatchingSeriesForNewPod(t *testing.T) {
+	tests := []struct {
+		name              string
+		podAge            time.Duration
+		wantNoMatchSeries bool
+	}{
+		{name: "a pod not scraped yet is not reported", podAge: 30 * time.Second},
+		{name: "a pod past the scrape grace period is reported", podAge: 10 * time.Minute, wantNoMatchSeries: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := createTestPod(TestPodName)
+			pod.Status.StartTime = &metav1.Time{Time: time.Now().Add(-tt.podAge)}
+			fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(pod).Build()
+
+			provider := &partialMetricsProvider{
+				mockMetricsProvider: createTestMetricsProvider(),
+				missing:             map[string]bool{"proxy": true},
+				err:                 fmt.Errorf("%w for pod %s/%s", metrics.ErrNoMatchingSeries, TestNamespace, TestPodName),
+			}
+			processor := NewWorkloadProcessor(provider, recommendation.NewEngine(), &recordingApplicationEngine{}, fakeClient)
+
+			status, err := processor.ProcessWorkload(context.Background(), newPartialMetricsTestWorkload("10m", "16Mi"),
+				createTestPolicy(optipodv1alpha1.ModeAuto))
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			if status.Status != StatusSkipped {
+				t.Errorf("status = %q (%s), want %q", status.Status, status.Reason, StatusSkipped)
+			}
+			if len(status.ContainersWithoutMetrics) != 1 {
+				t.Fatalf("containersWithoutMetrics = %+v, want proxy", status.ContainersWithoutMetrics)
+			}
+			if got := status.ContainersWithoutMetrics[0].NoMatchingSeries; got != tt.wantNoMatchSeries {
+				t.Errorf("noMatchingSeries = %v (%s), want %v", got, status.ContainersWithoutMetrics[0].Reason, tt.wantNoMatchSeries)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/config"
+)
+
+// pausingApplicationEngine pauses optimization cluster-wide while the first workload is being applied
+type pausingApplicationEngine struct {
+	recordingApplicationEngine
+	config *config.OperatorConfig
+}
+
+func (m *pausingApplicationEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+	if _, err := m.config.ApplyConfigMapData(map[string]string{config.KeyOptimizationPaused: "true"}); err != nil {
+		return nil, err
+	}
+	return m.recordingApplicationEngine.Apply(ctx, workload, changes, policy)
+}
+
+func TestProcessWorkloads_OptimizationPaused(t *testing.T) {
+	ctx := context.Background()
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+
+	appEngine := &recordingApplicationEngine{}
+	objects := append(createTestObjects(2), policy)
+	reconciler, fakeClient := createTestReconciler(appEngine, objects...)
+	reconciler.OperatorConfig = config.NewOperatorConfig()
+	reconciler.OperatorConfig.OptimizationPaused = true
+
+	getCondition := func() *metav1.Condition {
+		t.Helper()
+		stored := &optipodv1alpha1.OptimizationPolicy{}
+		if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), stored); err != nil {
+			t.Fatalf("failed to get policy: %v", err)
+		}
+		policy = stored
+		return meta.FindStatusCondition(stored.Status.Conditions, ConditionTypeOptimizationPaused)
+	}
+
+	// While paused, recommendations are computed but nothing is applied
+	summary, err := reconciler.processWorkloadsWithPolicySelection(ctx, policy)
+	if err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+	if len(appEngine.appliedContainers) != 0 || summary.Applied != 0 {
+		t.Errorf("applied %v while paused, want nothing", appEngine.appliedContainers)
+	}
+	if summary.Processed != 2 || summary.phase(policy) != optipodv1alpha1.PolicyPhaseRecommending {
+		t.Errorf("processed %d workloads in phase %s, want 2 in Recommending", summary.Processed, summary.phase(policy))
+	}
+	if condition := getCondition(); condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != "GlobalPause" {
+		t.Errorf("OptimizationPaused condition = %+v, want True with reason GlobalPause", condition)
+	}
+
+	// Clearing the switch resumes applies and clears the condition
+	if _, err := reconciler.OperatorConfig.ApplyConfigMapData(map[string]string{config.KeyOptimizationPaused: "false"}); err != nil {
+		t.Fatalf("ApplyConfigMapData() error = %v", err)
+	}
+	summary, err = reconciler.processWorkloadsWithPolicySelection(ctx, policy)
+	if err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+	if len(appEngine.appliedContainers) != 2 || summary.Paused {
+		t.Errorf("applied %v after resuming, want both workloads applied", appEngine.appliedContainers)
+	}
+	if condition := getCondition(); condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "Resumed" {
+		t.Errorf("OptimizationPaused condition = %+v, want False with reason Resumed", condition)
+	}
+}
+
+func TestProcessWorkloads_OptimizationPausedDuringReconcile(t *testing.T) {
+	ctx := context.Background()
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+
+	operatorConfig := config.NewOperatorConfig()
+	appEngine := &pausingApplicationEngine{config: operatorConfig}
+	objects := append(createTestObjects(3), policy)
+	reconciler, _ := createTestReconciler(appEngine, objects...)
+	reconciler.OperatorConfig = operatorConfig
+
+	summary, err := reconciler.processWorkloadsWithPolicySelection(ctx, policy)
+	if err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+
+	// The workload being applied when the switch was set is finished, no other is applied
+	if len(appEngine.appliedContainers) != 1 || summary.Applied != 1 || !summary.Paused {
+		t.Errorf("applied %d containers in %d workloads (paused %v), want only the first one",
+			len(appEngine.appliedContainers), summary.Applied, summary.Paused)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/metrics"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// dominantRequestShare is the share of the pod's CPU and memory requests a container must hold
+// for the usage of the whole pod to be attributed to it
+const dominantRequestShare = 0.9
+
+// podMetricsProvider serves a container's metrics from the usage of its whole pod
+type podMetricsProvider struct {
+	metrics.MetricsProvider
+	querier metrics.PodMetricsQuerier
+}
+
+// GetContainerMetrics returns the usage of the container's pod
+func (p podMetricsProvider) GetContainerMetrics(ctx context.Context, namespace, podName, _ string, window time.Duration) (*metrics.ContainerMetrics, error) {
+	return p.querier.GetPodMetrics(ctx, namespace, podName, window)
+}
+
+// collectPodLevelMetrics collects a container's metrics from the usage of its whole pod, for
+// providers whose backend has no per-container metrics. The pod's usage is only attributed to a
+// container running alone in the pod or dominating its requests; otherwise the returned error
+// explains why the container cannot be sized. containerErr is the error of the container's query.
+func (wp *WorkloadProcessor) collectPodLevelMetrics(
+	ctx context.Context,
+	workload *discovery.Workload,
+	podName, containerName string,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	containerErr error,
+) (*recommendation.WindowedMetrics, error) {
+	querier, ok := wp.metricsProvider.(metrics.PodMetricsQuerier)
+	if !ok {
+		return nil, containerErr
+	}
+	podSpec, err := workloadPodSpec(workload)
+	if err != nil {
+		return nil, containerErr
+	}
+	if reason := podUsageAttribution(podSpec, containerName); reason != "" {
+		return nil, fmt.Errorf("%w; %s", containerErr, reason)
+	}
+
+	provider := podMetricsProvider{MetricsProvider: wp.metricsProvider, querier: querier}
+	windowed, err := wp.recommendationEngine.CollectMetrics(ctx, provider, workload.Namespace, podName, containerName, policy)
+	if err != nil {
+		return nil, fmt.Errorf("%w; pod-level metrics: %w", containerErr, err)
+	}
+	return windowed, nil
+}
+
+// podUsageAttribution returns why the usage of the whole pod cannot be attributed to the
+// container, or an empty string when it can: the container runs alone in the pod, or holds at
+// least dominantRequestShare of the pod's CPU and memory requests. Native sidecars count as
+// containers of the pod; run-once init containers are not running alongside them and are never
+// attributed pod usage.
+func podUsageAttribution(podSpec *corev1.PodSpec, containerName string) string {
+	running := slices.Clone(podSpec.Containers)
+	for _, container := range podSpec.InitContainers {
+		if isNativeSidecar(container) {
+			running = append(running, container)
+		}
+	}
+
+	index := slices.IndexFunc(running, func(container corev1.Container) bool { return container.Name == containerName })
+	switch {
+	case index < 0:
+		return fmt.Sprintf("pod-level usage cannot be attributed to init container %s", containerName)
+	case len(running) == 1:
+		return ""
+	case dominatesRequests(running, index):
+		return ""
+	}
+	return fmt.Sprintf("the pod runs %d containers and %s holds less than %.0f%% of their requests, so pod-level usage cannot be attributed to it",
+		len(running), containerName, dominantRequestShare*100)
+}
+
+// dominatesRequests reports whether the container at index holds at least dominantRequestShare of
+// the containers' requests of every resource any of them requests
+func dominatesRequests(containers []corev1.Container, index int) bool {
+	requested := false
+	for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		var total float64
+		for _, container := range containers {
+			if request, ok := container.Resources.Requests[resourceName]; ok {
+				total += request.AsApproximateFloat64()
+			}
+		}
+		if total == 0 {
+			continue
+		}
+		requested = true
+		request := containers[index].Resources.Requests[resourceName]
+		if request.AsApproximateFloat64() < dominantRequestShare*total {
+			return false
+		}
+	}
+	return requested
+}
+
+// workloadPodSpec returns the pod template spec of a workload
+func workloadPodSpec(workload *discovery.Workload) (*corev1.PodSpec, error) {
+	switch obj := workload.Object.(type) {
+	case *appsv1.Deployment:
+		return &obj.Spec.Template.Spec, nil
+	case *appsv1.StatefulSet:
+		return &obj.Spec.Template.Spec, nil
+	case *appsv1.DaemonSet:
+		return &obj.Spec.Template.Spec, nil
+	default:
+		return nil, fmt.Errorf("unsupported workload type: %T", workload.Object)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// podLevelMetricsProvider has no per-container metrics and serves the test metrics for whole pods
+type podLevelMetricsProvider struct {
+	*mockMetricsProvider
+	podQueries int
+}
+
+func (m *podLevelMetricsProvider) GetContainerMetrics(ctx context.Context, namespace, podName, containerName string, window time.Duration) (*metrics.ContainerMetrics, error) {
+	return nil, fmt.Errorf("failed to query CPU metrics: %w", metrics.ErrNoContainerMetrics)
+}
+
+func (m *podLevelMetricsProvider) GetPodMetrics(ctx context.Context, namespace, podName string, window time.Duration) (*metrics.ContainerMetrics, error) {
+	m.podQueries++
+	return m.metricsToReturn, nil
+}
+
+// testContainer returns a container with the given CPU and memory requests, or none when empty
+func testContainer(name, cpu, memory string) corev1.Container {
+	container := corev1.Container{Name: name}
+	if cpu != "" {
+		container.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}
+	}
+	return container
+}
+
+func TestPodUsageAttribution(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	sidecar := testContainer("proxy", "50m", "64Mi")
+	sidecar.RestartPolicy = &always
+
+	tests := []struct {
+		name           string
+		containers     []corev1.Container
+		initContainers []corev1.Container
+		container      string
+		wantAttributed bool
+	}{
+		{
+			name:           "a single container is attributed the pod's usage",
+			containers:     []corev1.Container{testContainer("app", "", "")},
+			container:      "app",
+			wantAttributed: true,
+		},
+		{
+			name:           "a container holding nearly all requests dominates",
+			containers:     []corev1.Container{testContainer("app", "1", "1Gi"), testContainer("log", "50m", "32Mi")},
+			container:      "app",
+			wantAttributed: true,
+		},
+		{
+			name:       "the minor container is not attributed the pod's usage",
+			containers: []corev1.Container{testContainer("app", "1", "1Gi"), testContainer("log", "50m", "32Mi")},
+			container:  "log",
+		},
+		{
+			name:       "comparable containers are not attributed the pod's usage",
+			containers: []corev1.Container{testContainer("app", "500m", "512Mi"), testContainer("worker", "500m", "512Mi")},
+			container:  "app",
+		},
+		{
+			name:       "containers without requests cannot be told apart",
+			containers: []corev1.Container{testContainer("app", "", ""), testContainer("worker", "", "")},
+			container:  "app",
+		},
+		{
+			name:           "a native sidecar is one of the pod's containers",
+			containers:     []corev1.Container{testContainer("app", "200m", "256Mi")},
+			initContainers: []corev1.Container{sidecar},
+			container:      "app",
+		},
+		{
+			name:           "run-once init containers do not count",
+			containers:     []corev1.Container{testContainer("app", "", "")},
+			initContainers: []corev1.Container{testContainer("migrate", "1", "1Gi")},
+			container:      "app",
+			wantAttributed: true,
+		},
+		{
+			name:           "a run-once init container is never attributed the pod's usage",
+			containers:     []corev1.Container{testContainer("app", "", "")},
+			initContainers: []corev1.Container{testContainer("migrate", "", "")},
+			container:      "migrate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podSpec := &corev1.PodSpec{Containers: tt.containers, InitContainers: tt.initContainers}
+			reason := podUsageAttribution(podSpec, tt.container)
+			if attributed := reason == ""; attributed != tt.wantAttributed {
+				t.Errorf("podUsageAttribution() = %q, want attributed = %v", reason, tt.wantAttributed)
+			}
+		})
+	}
+}
+
+func TestProcessWorkload_PodLevelMetrics(t *testing.T) {
+	t.Run("a single container is sized from its pod with lower confidence", func(t *testing.T) {
+		provider := &podLevelMetricsProvider{mockMetricsProvider: createTestMetricsProvider()}
+		processor := NewWorkloadProcessor(provider, recommendation.NewEngine(), &recordingApplicationEngine{}, nil)
+
+		status, err := processor.ProcessWorkload(context.Background(), createTestWorkload(TestContainerName),
+			createTestPolicy(optipodv1alpha1.ModeRecommend))
+		if err != nil {
+			t.Fatalf("ProcessWorkload() error = %v", err)
+		}
+		rec := findRecommendation(status.Recommendations, TestContainerName)
+		if status.Status != StatusRecommended || rec == nil {
+			t.Fatalf("status = %s (%s), want a recommendation", status.Status, status.Reason)
+		}
+		if !rec.LowConfidence || !strings.Contains(rec.Explanation, "lower confidence") {
+			t.Errorf("recommendation = %+v, want it marked lower confidence", rec)
+		}
+		if rec.CPU == nil || rec.CPU.Cmp(resource.MustParse("240m")) != 0 {
+			t.Errorf("CPU recommendation = %v, want 240m from the pod's usage", rec.CPU)
+		}
+		if provider.podQueries == 0 {
+			t.Error("the pod's usage was never queried")
+		}
+	})
+
+	t.Run("a multi-container pod is skipped", func(t *testing.T) {
+		provider := &podLevelMetricsProvider{mockMetricsProvider: createTestMetricsProvider()}
+		processor := NewWorkloadProcessor(provider, recommendation.NewEngine(), &recordingApplicationEngine{}, nil)
+		workload := createTestWorkload("app", "worker")
+		for i := range workload.Object.(*appsv1.Deployment).Spec.Template.Spec.Containers {
+			workload.Object.(*appsv1.Deployment).Spec.Template.Spec.Containers[i].Resources.Requests = corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			}
+		}
+
+		status, err := processor.ProcessWorkload(context.Background(), workload, createTestPolicy(optipodv1alpha1.ModeRecommend))
+		if err != nil {
+			t.Fatalf("ProcessWorkload() error = %v", err)
+		}
+		if status.Status != StatusSkipped || !strings.Contains(status.Reason, "pod-level usage cannot be attributed") {
+			t.Errorf("status = %s (%s), want skipped as pod-level usage cannot be attributed", status.Status, status.Reason)
+		}
+		if provider.podQueries != 0 {
+			t.Errorf("queried the pod's usage %d times, want none", provider.podQueries)
+		}
+	})
+}
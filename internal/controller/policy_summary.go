@@ -0,0 +1,341 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/application"
+)
+
+// reconcileSummary tallies the outcome of processing the workloads matched by a policy
+type reconcileSummary struct {
+	Discovered int
+	Processed  int
+	Applied    int
+	Skipped    int
+	Failed     int
+
+	// PendingApproval counts workloads with a proposal awaiting approval
+	PendingApproval int
+
+	// AwaitingRollout counts workloads whose change waits for their rollout to finish
+	AwaitingRollout int
+
+	// AwaitingMetricsReady counts workloads whose change is held by the startup readiness gate
+	AwaitingMetricsReady int
+
+	// Suspicious lists the workloads (namespace/name) whose recommendation was not applied
+	// because of an implausible CPU to memory ratio
+	Suspicious []string
+
+	// MetricsNotFound lists the workloads (namespace/name) with a container whose pod has no
+	// series at all in the metrics backend
+	MetricsNotFound []string
+
+	// Checkpoint is set when the time budget ran out before every workload was processed
+	Checkpoint *optipodv1alpha1.ReconcileCheckpoint
+
+	// CapExceeded is true when the workload cap forced the policy to recommend-only
+	CapExceeded bool
+
+	// Paused is true when the cluster-wide optimization pause forced the policy to recommend-only
+	Paused bool
+
+	// LowestStabilityScore is the lowest stability score of the processed workloads, nil when
+	// none was scored
+	LowestStabilityScore *int32
+
+	// AdaptiveInterval is the interval picked by the policy's adaptiveInterval, zero when it
+	// has none
+	AdaptiveInterval time.Duration
+
+	// FailingWorkloads lists the workloads failing in consecutive reconciliations, recorded in status
+	FailingWorkloads []optipodv1alpha1.FailingWorkload
+
+	// Quarantined counts the failing workloads skipped until their quarantine expires
+	Quarantined int
+
+	skipReasons    map[string]int
+	failureReasons map[string]int
+}
+
+// record adds the outcome of processing a single workload
+func (s *reconcileSummary) record(status *optipodv1alpha1.WorkloadStatus, err error) {
+	if err != nil {
+		s.Failed++
+		if reason := application.ErrorReason(err); reason != "" {
+			if s.failureReasons == nil {
+				s.failureReasons = make(map[string]int)
+			}
+			s.failureReasons[reason]++
+		}
+		return
+	}
+	s.Processed++
+
+	if status == nil {
+		return
+	}
+	for _, container := range status.ContainersWithoutMetrics {
+		if container.NoMatchingSeries {
+			s.MetricsNotFound = append(s.MetricsNotFound, status.Namespace+"/"+status.Name)
+			break
+		}
+	}
+	if score := status.StabilityScore; score != nil &&
+		(s.LowestStabilityScore == nil || *score < *s.LowestStabilityScore) {
+		lowest := *score
+		s.LowestStabilityScore = &lowest
+	}
+	switch status.Status {
+	case StatusApplied:
+		s.Applied++
+	case StatusPendingApproval:
+		s.PendingApproval++
+	case StatusAwaitingStableRollout:
+		s.AwaitingRollout++
+	case StatusAwaitingMetricsReady:
+		s.AwaitingMetricsReady++
+	case StatusSuspicious:
+		s.Suspicious = append(s.Suspicious, status.Namespace+"/"+status.Name)
+	case StatusSkipped:
+		s.Skipped++
+		if s.skipReasons == nil {
+			s.skipReasons = make(map[string]int)
+		}
+		s.skipReasons[status.Reason]++
+	}
+}
+
+// topSkipReason returns the most common skip reason and how many workloads it affected.
+// Ties are broken alphabetically so the result is stable across reconciles.
+func (s *reconcileSummary) topSkipReason() (string, int) {
+	return topReason(s.skipReasons)
+}
+
+// topFailureReason returns the most common classified failure reason (e.g. RBACDenied) and
+// how many workloads it affected
+func (s *reconcileSummary) topFailureReason() (string, int) {
+	return topReason(s.failureReasons)
+}
+
+// topReason returns the reason with the highest count, breaking ties alphabetically
+func topReason(reasons map[string]int) (string, int) {
+	topReason, topCount := "", 0
+	for reason, count := range reasons {
+		if count > topCount || (count == topCount && reason < topReason) {
+			topReason, topCount = reason, count
+		}
+	}
+	return topReason, topCount
+}
+
+// phase derives the policy phase from its mode and the processing outcome
+func (s *reconcileSummary) phase(pol *optipodv1alpha1.OptimizationPolicy) optipodv1alpha1.PolicyPhase {
+	switch {
+	case pol.Spec.Mode == optipodv1alpha1.ModeDisabled:
+		return optipodv1alpha1.PolicyPhaseDisabled
+	case s.Discovered == 0:
+		return optipodv1alpha1.PolicyPhaseIdle
+	case s.Failed > 0:
+		return optipodv1alpha1.PolicyPhaseDegraded
+	case pol.Spec.Mode == optipodv1alpha1.ModeRecommend || pol.Spec.DryRun || s.CapExceeded || s.Paused:
+		return optipodv1alpha1.PolicyPhaseRecommending
+	default:
+		return optipodv1alpha1.PolicyPhaseActive
+	}
+}
+
+// readyMessage summarizes the processing outcome for the Ready condition
+func (s *reconcileSummary) readyMessage() string {
+	if s.Discovered == 0 {
+		return "Policy is active but matches no workloads"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Policy matches %d workload(s): %d applied, %d skipped, %d failed",
+		s.Discovered, s.Applied, s.Skipped, s.Failed)
+	if s.PendingApproval > 0 {
+		fmt.Fprintf(&b, ", %d pending approval", s.PendingApproval)
+	}
+	if len(s.Suspicious) > 0 {
+		fmt.Fprintf(&b, ", %d suspicious", len(s.Suspicious))
+	}
+	if s.Quarantined > 0 {
+		fmt.Fprintf(&b, ", %d quarantined", s.Quarantined)
+	}
+	if reason, count := s.topSkipReason(); count > 0 {
+		fmt.Fprintf(&b, "; most common skip reason (%d workload(s)): %s", count, reason)
+	}
+	return b.String()
+}
+
+// policyDescription summarizes the effective configuration of the policy and how many workloads
+// it matched, for the event emitted when a policy is created or its spec changes
+func (s *reconcileSummary) policyDescription(pol *optipodv1alpha1.OptimizationPolicy) string {
+	mode := string(pol.Spec.Mode)
+	if pol.Spec.Mode == optipodv1alpha1.ModeAuto {
+		switch {
+		case pol.Spec.DryRun:
+			mode += " (recommend-only: dryRun is set)"
+		case s.Paused:
+			mode += " (recommend-only: optimization is paused cluster-wide)"
+		case s.CapExceeded:
+			mode += " (recommend-only: more workloads matched than maxWorkloads)"
+		}
+	}
+
+	bounds := func(name string, optimized bool, bound optipodv1alpha1.ResourceBound) string {
+		if !optimized {
+			return name + " not optimized"
+		}
+		return fmt.Sprintf("%s %s-%s", name, bound.Min.String(), bound.Max.String())
+	}
+
+	strategy := pol.Spec.UpdateStrategy
+	var methods []string
+	if strategy.AllowInPlaceResize {
+		methods = append(methods, "in-place resize")
+	}
+	if strategy.AllowRecreate {
+		methods = append(methods, "recreate")
+	}
+	if len(methods) == 0 {
+		methods = append(methods, "no update method allowed")
+	}
+	if strategy.UpdateRequestsOnly {
+		methods = append(methods, "requests only")
+	} else {
+		if !strategy.ManagesCPULimit() {
+			methods = append(methods, "CPU limits untouched")
+		}
+		if !strategy.ManagesMemoryLimit() {
+			methods = append(methods, "memory limits untouched")
+		}
+	}
+	if strategy.IncreaseOnly {
+		methods = append(methods, "increase only")
+	}
+	if strategy.ApprovalRequired {
+		methods = append(methods, "approval required")
+	}
+
+	return fmt.Sprintf("mode %s, %s, %s, percentile %s, safety factor %g, update strategy %s; matches %d workload(s)",
+		mode,
+		bounds("CPU", pol.OptimizesCPU(), pol.Spec.ResourceBounds.CPU),
+		bounds("memory", pol.OptimizesMemory(), pol.Spec.ResourceBounds.Memory),
+		pol.Spec.MetricsConfig.Percentile, pol.Spec.MetricsConfig.GetSafetyFactor(),
+		strings.Join(methods, ", "), s.Discovered)
+}
+
+// applyFailedCondition reports classified failures in the ApplyFailed condition, named after
+// the most common failure reason. It returns nil when no failure was classified.
+func (s *reconcileSummary) applyFailedCondition() *metav1.Condition {
+	reason, count := s.topFailureReason()
+	if count == 0 {
+		return nil
+	}
+
+	reasons := make([]string, 0, len(s.failureReasons))
+	for r := range s.failureReasons {
+		reasons = append(reasons, r)
+	}
+	sort.Strings(reasons)
+	counts := make([]string, 0, len(reasons))
+	for _, r := range reasons {
+		counts = append(counts, fmt.Sprintf("%s: %d", r, s.failureReasons[r]))
+	}
+
+	return &metav1.Condition{
+		Type:    ConditionTypeApplyFailed,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: fmt.Sprintf("%d workload(s) failed to process (%s)", s.Failed, strings.Join(counts, ", ")),
+	}
+}
+
+// maxListedWorkloads caps how many workloads the SuspiciousRecommendation and MetricsNotFound
+// conditions name
+const maxListedWorkloads = 5
+
+// listedWorkloads joins the sorted workloads, naming at most maxListedWorkloads of them
+func listedWorkloads(workloads []string) string {
+	workloads = append([]string(nil), workloads...)
+	sort.Strings(workloads)
+	listed := strings.Join(workloads, ", ")
+	if len(workloads) > maxListedWorkloads {
+		listed = fmt.Sprintf("%s and %d more", strings.Join(workloads[:maxListedWorkloads], ", "),
+			len(workloads)-maxListedWorkloads)
+	}
+	return listed
+}
+
+// suspiciousCondition reports workloads whose recommendation was held back because of an
+// implausible CPU to memory ratio. It returns nil when there are none.
+func (s *reconcileSummary) suspiciousCondition() *metav1.Condition {
+	if len(s.Suspicious) == 0 {
+		return nil
+	}
+
+	return &metav1.Condition{
+		Type:   ConditionTypeSuspiciousRecommendation,
+		Status: metav1.ConditionTrue,
+		Reason: "ImplausibleCPUMemoryRatio",
+		Message: fmt.Sprintf("%d workload(s) have a recommendation outside the plausible CPU to memory ratio and were not updated: %s",
+			len(s.Suspicious), listedWorkloads(s.Suspicious)),
+	}
+}
+
+// metricsNotFoundCondition reports workloads whose pods have no series in the metrics backend,
+// which usually means the metric labels do not match the pod names. It returns nil when there
+// are none.
+func (s *reconcileSummary) metricsNotFoundCondition() *metav1.Condition {
+	if len(s.MetricsNotFound) == 0 {
+		return nil
+	}
+
+	return &metav1.Condition{
+		Type:   ConditionTypeMetricsNotFound,
+		Status: metav1.ConditionTrue,
+		Reason: "NoMatchingSeries",
+		Message: fmt.Sprintf("%d workload(s) have pods without any series in the metrics backend, check that the metric labels match the pod names: %s",
+			len(s.MetricsNotFound), listedWorkloads(s.MetricsNotFound)),
+	}
+}
+
+// metricsWarmingUpCondition reports that changes were held because the metrics backend has not
+// been ready since operator startup. It returns nil when no change was held.
+func (s *reconcileSummary) metricsWarmingUpCondition() *metav1.Condition {
+	if s.AwaitingMetricsReady == 0 {
+		return nil
+	}
+
+	return &metav1.Condition{
+		Type:   ConditionTypeMetricsWarmingUp,
+		Status: metav1.ConditionTrue,
+		Reason: "AwaitingMetricsReady",
+		Message: fmt.Sprintf("Changes to %d workload(s) are held until the metrics backend is healthy and has returned metrics since operator startup",
+			s.AwaitingMetricsReady),
+	}
+}
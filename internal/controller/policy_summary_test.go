@@ -0,0 +1,315 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/observability"
+)
+
+func TestReconcileSummary(t *testing.T) {
+	summary := &reconcileSummary{Discovered: 6}
+	summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusApplied}, nil)
+	summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusSkipped, Reason: "Policy is in Recommend mode"}, nil)
+	summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusSkipped, Reason: "Memory decrease could cause pod eviction or OOM"}, nil)
+	summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusSkipped, Reason: "Memory decrease could cause pod eviction or OOM"}, nil)
+	summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusRecommended}, nil)
+	summary.record(nil, errors.New("boom"))
+
+	if summary.Processed != 5 || summary.Applied != 1 || summary.Skipped != 3 || summary.Failed != 1 {
+		t.Errorf("summary = %+v, want 5 processed, 1 applied, 3 skipped, 1 failed", summary)
+	}
+
+	reason, count := summary.topSkipReason()
+	if reason != "Memory decrease could cause pod eviction or OOM" || count != 2 {
+		t.Errorf("topSkipReason() = (%q, %d), want the memory decrease reason twice", reason, count)
+	}
+
+	message := summary.readyMessage()
+	want := "Policy matches 6 workload(s): 1 applied, 3 skipped, 1 failed; " +
+		"most common skip reason (2 workload(s)): Memory decrease could cause pod eviction or OOM"
+	if message != want {
+		t.Errorf("readyMessage() = %q, want %q", message, want)
+	}
+}
+
+func TestReconcileSummaryPhase(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    optipodv1alpha1.PolicyMode
+		dryRun  bool
+		summary reconcileSummary
+		want    optipodv1alpha1.PolicyPhase
+	}{
+		{name: "disabled", mode: optipodv1alpha1.ModeDisabled, summary: reconcileSummary{Discovered: 3}, want: optipodv1alpha1.PolicyPhaseDisabled},
+		{name: "no workloads", mode: optipodv1alpha1.ModeAuto, summary: reconcileSummary{}, want: optipodv1alpha1.PolicyPhaseIdle},
+		{name: "failures", mode: optipodv1alpha1.ModeAuto, summary: reconcileSummary{Discovered: 3, Failed: 1}, want: optipodv1alpha1.PolicyPhaseDegraded},
+		{name: "recommend mode", mode: optipodv1alpha1.ModeRecommend, summary: reconcileSummary{Discovered: 3}, want: optipodv1alpha1.PolicyPhaseRecommending},
+		{name: "cap exceeded", mode: optipodv1alpha1.ModeAuto, summary: reconcileSummary{Discovered: 3, CapExceeded: true}, want: optipodv1alpha1.PolicyPhaseRecommending},
+		{name: "auto mode", mode: optipodv1alpha1.ModeAuto, summary: reconcileSummary{Discovered: 3}, want: optipodv1alpha1.PolicyPhaseActive},
+		{name: "policy dry-run", mode: optipodv1alpha1.ModeAuto, dryRun: true, summary: reconcileSummary{Discovered: 3}, want: optipodv1alpha1.PolicyPhaseRecommending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createTestPolicy(tt.mode)
+			policy.Spec.DryRun = tt.dryRun
+			if got := tt.summary.phase(policy); got != tt.want {
+				t.Errorf("phase() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcile_StatusSummary(t *testing.T) {
+	tests := []struct {
+		name        string
+		appEngine   ApplicationEngine
+		wantApplied int
+		wantSkipped int
+		wantMessage string
+	}{
+		{
+			name:        "applied workloads",
+			appEngine:   &recordingApplicationEngine{},
+			wantApplied: 2,
+			wantMessage: "Policy matches 2 workload(s): 2 applied, 0 skipped, 0 failed",
+		},
+		{
+			name:        "skipped workloads report the most common reason",
+			appEngine:   &mockApplicationEngine{},
+			wantSkipped: 2,
+			wantMessage: "most common skip reason (2 workload(s)): Mock decision",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+			objects := append(createTestObjects(2), policy)
+			reconciler, fakeClient := createTestReconciler(tt.appEngine, objects...)
+
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			updated := &optipodv1alpha1.OptimizationPolicy{}
+			if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), updated); err != nil {
+				t.Fatalf("failed to get policy: %v", err)
+			}
+
+			if updated.Status.Phase != optipodv1alpha1.PolicyPhaseActive {
+				t.Errorf("phase = %s, want Active", updated.Status.Phase)
+			}
+			if updated.Status.WorkloadsDiscovered != 2 {
+				t.Errorf("workloadsDiscovered = %d, want 2", updated.Status.WorkloadsDiscovered)
+			}
+			if updated.Status.WorkloadsApplied != tt.wantApplied || updated.Status.WorkloadsSkipped != tt.wantSkipped {
+				t.Errorf("applied/skipped = %d/%d, want %d/%d", updated.Status.WorkloadsApplied,
+					updated.Status.WorkloadsSkipped, tt.wantApplied, tt.wantSkipped)
+			}
+
+			// The next reconciliation is when the reconciler requeues the policy
+			if updated.Status.NextReconciliation == nil || updated.Status.LastReconciliation == nil {
+				t.Fatalf("expected last and next reconciliation times, got %+v", updated.Status)
+			}
+			scheduled := updated.Status.NextReconciliation.Sub(updated.Status.LastReconciliation.Time)
+			if diff := scheduled - result.RequeueAfter; diff < -time.Second || diff > time.Second {
+				t.Errorf("next reconciliation is %s after the last one, want the requeue interval %s", scheduled, result.RequeueAfter)
+			}
+
+			ready := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeReady)
+			if ready == nil || !strings.Contains(ready.Message, tt.wantMessage) {
+				t.Errorf("Ready condition = %+v, want message containing %q", ready, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestReconcile_InvalidPolicyPhase(t *testing.T) {
+	ctx := context.Background()
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.Mode = "Unknown"
+	reconciler, fakeClient := createTestReconciler(&recordingApplicationEngine{}, policy)
+
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &optipodv1alpha1.OptimizationPolicy{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), updated); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	if updated.Status.Phase != optipodv1alpha1.PolicyPhaseInvalid {
+		t.Errorf("phase = %s, want Invalid", updated.Status.Phase)
+	}
+}
+
+// failingApplicationEngine allows every change and fails to apply it with a fixed error
+type failingApplicationEngine struct {
+	recordingApplicationEngine
+	err error
+}
+
+func (m *failingApplicationEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+	return nil, m.err
+}
+
+func TestReconcileSummary_ApplyFailedCondition(t *testing.T) {
+	summary := &reconcileSummary{Discovered: 4}
+	summary.record(nil, errors.New("boom"))
+	if condition := summary.applyFailedCondition(); condition != nil {
+		t.Errorf("applyFailedCondition() = %+v, want nil for unclassified failures", condition)
+	}
+
+	summary.record(nil, fmt.Errorf("%w: insufficient permissions to update workload", application.ErrRBACDenied))
+	summary.record(nil, fmt.Errorf("%w: insufficient permissions to update workload", application.ErrRBACDenied))
+	summary.record(nil, fmt.Errorf("%w: patch would exceed a resource quota", application.ErrQuotaExceeded))
+
+	condition := summary.applyFailedCondition()
+	want := "4 workload(s) failed to process (QuotaExceeded: 1, RBACDenied: 2)"
+	if condition == nil || condition.Reason != "RBACDenied" || condition.Message != want {
+		t.Errorf("applyFailedCondition() = %+v, want reason RBACDenied and message %q", condition, want)
+	}
+}
+
+func TestReconcile_ApplyFailedCondition(t *testing.T) {
+	ctx := context.Background()
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	objects := append(createTestObjects(2), policy)
+	appEngine := &failingApplicationEngine{err: fmt.Errorf("%w: insufficient permissions to update workload", application.ErrRBACDenied)}
+	reconciler, fakeClient := createTestReconciler(appEngine, objects...)
+
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &optipodv1alpha1.OptimizationPolicy{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), updated); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+
+	condition := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeApplyFailed)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != "RBACDenied" {
+		t.Fatalf("ApplyFailed condition = %+v, want True with reason RBACDenied", condition)
+	}
+
+	// The condition is removed once changes apply again
+	reconciler.WorkloadProcessor = createTestProcessor(&recordingApplicationEngine{}, nil)
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), updated); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	if condition := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeApplyFailed); condition != nil {
+		t.Errorf("ApplyFailed condition = %+v, want it removed", condition)
+	}
+}
+
+func TestReconcileSummary_PendingApproval(t *testing.T) {
+	summary := &reconcileSummary{Discovered: 2}
+	summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusApplied}, nil)
+	summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusPendingApproval, ProposalHash: "0123456789abcdef"}, nil)
+
+	if summary.PendingApproval != 1 || summary.Processed != 2 {
+		t.Errorf("summary = %+v, want 2 processed with 1 pending approval", summary)
+	}
+	want := "Policy matches 2 workload(s): 1 applied, 0 skipped, 0 failed, 1 pending approval"
+	if message := summary.readyMessage(); message != want {
+		t.Errorf("readyMessage() = %q, want %q", message, want)
+	}
+}
+
+// policySummaryEvents drains the recorded events and returns the policy summaries
+func policySummaryEvents(recorder *record.FakeRecorder) []string {
+	var summaries []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			if strings.Contains(event, observability.EventReasonPolicySummary) {
+				summaries = append(summaries, event)
+			}
+		default:
+			return summaries
+		}
+	}
+}
+
+func TestReconcile_PolicySummaryEvent(t *testing.T) {
+	ctx := context.Background()
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Generation = 1
+	objects := append(createTestObjects(2), policy)
+	reconciler, fakeClient := createTestReconciler(&recordingApplicationEngine{}, objects...)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+	reconciler.EventRecorder = observability.NewEventRecorder(recorder)
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)}
+
+	// Only the first reconcile of a generation is summarized
+	for range 2 {
+		if _, err := reconciler.Reconcile(ctx, request); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	events := policySummaryEvents(recorder)
+	if len(events) != 1 {
+		t.Fatalf("got %d policy summary events over two reconciles, want 1: %v", len(events), events)
+	}
+	want := "Policy generation 1 is in effect: mode Auto, CPU 50m-2, memory 64Mi-2Gi, percentile P90, " +
+		"safety factor 1.2, update strategy no update method allowed, requests only; matches 2 workload(s)"
+	if !strings.Contains(events[0], want) {
+		t.Errorf("event = %q, want it to contain %q", events[0], want)
+	}
+
+	// A spec change is summarized again
+	updated := &optipodv1alpha1.OptimizationPolicy{}
+	if err := fakeClient.Get(ctx, request.NamespacedName, updated); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	if updated.Status.ObservedGeneration != 1 {
+		t.Errorf("observedGeneration = %d, want 1", updated.Status.ObservedGeneration)
+	}
+	updated.Spec.DryRun = true
+	updated.Generation = 2
+	if err := fakeClient.Update(ctx, updated); err != nil {
+		t.Fatalf("failed to update policy: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	events = policySummaryEvents(recorder)
+	if len(events) != 1 || !strings.Contains(events[0], "Policy generation 2 is in effect: mode Auto (recommend-only: dryRun is set)") {
+		t.Errorf("events after the spec change = %v, want one summary of generation 2", events)
+	}
+}
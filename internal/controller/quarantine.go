@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/observability"
+)
+
+// QuarantineConfig configures how workloads whose processing keeps failing are quarantined
+type QuarantineConfig struct {
+	// FailureThreshold is the number of consecutive reconciliations a workload may fail in
+	// before it is quarantined (0 = disabled)
+	FailureThreshold int
+
+	// Backoff is how long a workload is first quarantined for, doubled on every further failure
+	Backoff time.Duration
+
+	// MaxBackoff caps how long a workload is quarantined for
+	MaxBackoff time.Duration
+}
+
+// enabled reports whether failing workloads are quarantined
+func (c QuarantineConfig) enabled() bool {
+	return c.FailureThreshold > 0 && c.Backoff > 0
+}
+
+// backoff returns how long a workload that failed in the given number of consecutive
+// reconciliations is quarantined for: Backoff at the threshold, doubled for every failure
+// after it and capped at MaxBackoff
+func (c QuarantineConfig) backoff(failures int32) time.Duration {
+	d := c.Backoff
+	for i := int32(c.FailureThreshold); i < failures && d < c.MaxBackoff; i++ {
+		d *= 2
+	}
+	return max(min(d, c.MaxBackoff), c.Backoff)
+}
+
+// workloadQuarantine tracks the failing workloads of a policy through a reconciliation
+type workloadQuarantine struct {
+	config  QuarantineConfig
+	entries map[string]*optipodv1alpha1.FailingWorkload
+
+	// seen holds the workloads the policy processed or skipped as quarantined
+	seen map[string]bool
+}
+
+// newWorkloadQuarantine loads the failing workloads recorded in the policy status. A changed
+// policy releases them all, since the change may have fixed what made them fail; a
+// reconciliation resuming from a checkpoint keeps those recorded for the same generation.
+func newWorkloadQuarantine(pol *optipodv1alpha1.OptimizationPolicy, config QuarantineConfig, resumed bool) *workloadQuarantine {
+	q := &workloadQuarantine{
+		config:  config,
+		entries: make(map[string]*optipodv1alpha1.FailingWorkload),
+		seen:    make(map[string]bool),
+	}
+	if !config.enabled() || (pol.Generation != pol.Status.ObservedGeneration && !resumed) {
+		return q
+	}
+	for i := range pol.Status.FailingWorkloads {
+		entry := pol.Status.FailingWorkloads[i].DeepCopy()
+		q.entries[workloadKey(entry.Kind, entry.Namespace, entry.Name)] = entry
+	}
+	return q
+}
+
+// quarantined returns the entry of the workload if it is quarantined at now, in which case
+// the workload is skipped
+func (q *workloadQuarantine) quarantined(workload *discovery.Workload, now time.Time) (*optipodv1alpha1.FailingWorkload, bool) {
+	key := workloadKey(workload.Kind, workload.Namespace, workload.Name)
+	q.seen[key] = true
+	entry, ok := q.entries[key]
+	if !ok || entry.QuarantinedUntil == nil || !now.Before(entry.QuarantinedUntil.Time) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// recordFailure counts a failed processing of the workload. It returns the workload's entry
+// when the failure quarantined it, nil otherwise.
+func (q *workloadQuarantine) recordFailure(workload *discovery.Workload, err error, now time.Time) *optipodv1alpha1.FailingWorkload {
+	if !q.config.enabled() {
+		return nil
+	}
+	key := workloadKey(workload.Kind, workload.Namespace, workload.Name)
+	entry, ok := q.entries[key]
+	if !ok {
+		entry = &optipodv1alpha1.FailingWorkload{Kind: workload.Kind, Namespace: workload.Namespace, Name: workload.Name}
+		q.entries[key] = entry
+	}
+	entry.ConsecutiveFailures++
+	entry.LastError = err.Error()
+	entry.QuarantinedUntil = nil
+	if entry.ConsecutiveFailures < int32(q.config.FailureThreshold) {
+		return nil
+	}
+
+	// Status times are stored with second precision, keep the entry comparable to its stored copy
+	until := metav1.NewTime(now.Add(q.config.backoff(entry.ConsecutiveFailures))).Rfc3339Copy()
+	entry.QuarantinedUntil = &until
+	return entry
+}
+
+// recordSuccess releases the workload; its earlier failures no longer count
+func (q *workloadQuarantine) recordSuccess(workload *discovery.Workload) {
+	delete(q.entries, workloadKey(workload.Kind, workload.Namespace, workload.Name))
+}
+
+// failingWorkloads returns the entries to record in the policy status, in the order workloads
+// are discovered in. After a reconciliation that went through every workload, entries of
+// workloads the policy no longer handles are dropped.
+func (q *workloadQuarantine) failingWorkloads(complete bool) []optipodv1alpha1.FailingWorkload {
+	var failing []optipodv1alpha1.FailingWorkload
+	for key, entry := range q.entries {
+		if complete && !q.seen[key] {
+			continue
+		}
+		failing = append(failing, *entry)
+	}
+	sort.Slice(failing, func(i, j int) bool {
+		a, b := failing[i], failing[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Kind < b.Kind
+	})
+	return failing
+}
+
+// finish records the failing workloads and how many of them are quarantined in the summary
+// and the quarantine metric
+func (q *workloadQuarantine) finish(pol *optipodv1alpha1.OptimizationPolicy, summary *reconcileSummary, complete bool, now time.Time) {
+	summary.FailingWorkloads = q.failingWorkloads(complete)
+	summary.Quarantined = 0
+	for _, entry := range summary.FailingWorkloads {
+		if entry.QuarantinedUntil != nil && now.Before(entry.QuarantinedUntil.Time) {
+			summary.Quarantined++
+		}
+	}
+	observability.WorkloadsQuarantined.WithLabelValues(pol.Namespace, pol.Name).Set(float64(summary.Quarantined))
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+func TestQuarantineConfig_Backoff(t *testing.T) {
+	config := QuarantineConfig{FailureThreshold: 3, Backoff: 10 * time.Minute, MaxBackoff: time.Hour}
+	tests := []struct {
+		failures int32
+		want     time.Duration
+	}{
+		{failures: 3, want: 10 * time.Minute},
+		{failures: 4, want: 20 * time.Minute},
+		{failures: 5, want: 40 * time.Minute},
+		{failures: 6, want: time.Hour},
+		{failures: 1000, want: time.Hour},
+	}
+	for _, tt := range tests {
+		if got := config.backoff(tt.failures); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+
+	// A maximum below the initial quarantine does not shorten it
+	config.MaxBackoff = 0
+	if got := config.backoff(5); got != 10*time.Minute {
+		t.Errorf("backoff(5) without a maximum = %v, want 10m", got)
+	}
+}
+
+// quarantineEvents drains the recorded events and returns the quarantine events
+func quarantineEvents(recorder *record.FakeRecorder) []string {
+	var quarantined []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			if strings.Contains(event, "WorkloadQuarantined") {
+				quarantined = append(quarantined, event)
+			}
+		default:
+			return quarantined
+		}
+	}
+}
+
+func TestReconcile_QuarantinesFailingWorkloads(t *testing.T) {
+	ctx := context.Background()
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	objects := append(createTestObjects(2), policy)
+	reconciler, fakeClient := createTestReconciler(&failingApplicationEngine{err: errors.New("admission webhook denied the request")}, objects...)
+	reconciler.Quarantine = QuarantineConfig{FailureThreshold: 2, Backoff: 10 * time.Minute, MaxBackoff: time.Hour}
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)}
+
+	reconcile := func() *optipodv1alpha1.OptimizationPolicy {
+		t.Helper()
+		if _, err := reconciler.Reconcile(ctx, request); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		updated := &optipodv1alpha1.OptimizationPolicy{}
+		if err := fakeClient.Get(ctx, request.NamespacedName, updated); err != nil {
+			t.Fatalf("failed to get policy: %v", err)
+		}
+		return updated
+	}
+
+	// Failures below the threshold are counted but the workloads are retried
+	updated := reconcile()
+	if len(updated.Status.FailingWorkloads) != 2 || updated.Status.WorkloadsQuarantined != 0 {
+		t.Fatalf("status = %d failing and %d quarantined workloads, want 2 failing and none quarantined",
+			len(updated.Status.FailingWorkloads), updated.Status.WorkloadsQuarantined)
+	}
+	for _, entry := range updated.Status.FailingWorkloads {
+		if entry.ConsecutiveFailures != 1 || entry.QuarantinedUntil != nil ||
+			!strings.Contains(entry.LastError, "admission webhook denied") {
+			t.Errorf("entry = %+v, want 1 failure with its error and no quarantine", entry)
+		}
+	}
+	if events := quarantineEvents(recorder); len(events) != 0 {
+		t.Errorf("events below the threshold = %v, want none", events)
+	}
+
+	// Reaching the threshold quarantines them
+	before := time.Now()
+	updated = reconcile()
+	if updated.Status.WorkloadsQuarantined != 2 {
+		t.Fatalf("workloadsQuarantined = %d, want 2", updated.Status.WorkloadsQuarantined)
+	}
+	for _, entry := range updated.Status.FailingWorkloads {
+		if entry.ConsecutiveFailures != 2 || entry.QuarantinedUntil == nil ||
+			entry.QuarantinedUntil.Time.Before(before.Add(10*time.Minute-time.Second)) {
+			t.Errorf("entry = %+v, want 2 failures and a 10m quarantine", entry)
+		}
+	}
+	if events := quarantineEvents(recorder); len(events) != 2 {
+		t.Errorf("got %d quarantine events, want 2: %v", len(events), events)
+	}
+	ready := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeReady)
+	if ready == nil || !strings.Contains(ready.Message, "2 quarantined") {
+		t.Errorf("Ready condition = %+v, want the quarantined workloads counted", ready)
+	}
+
+	// Quarantined workloads are skipped, even though they would succeed now
+	appEngine := &recordingApplicationEngine{}
+	reconciler.WorkloadProcessor = createTestProcessor(appEngine, nil)
+	updated = reconcile()
+	if len(appEngine.appliedContainers) != 0 || updated.Status.WorkloadsQuarantined != 2 {
+		t.Fatalf("applied %v with %d quarantined, want the quarantined workloads skipped",
+			appEngine.appliedContainers, updated.Status.WorkloadsQuarantined)
+	}
+
+	// An expired quarantine releases the workload, which is removed once it succeeds
+	expired := metav1.NewTime(time.Now().Add(-time.Minute)).Rfc3339Copy()
+	updated.Status.FailingWorkloads[0].QuarantinedUntil = &expired
+	if err := fakeClient.Status().Update(ctx, updated); err != nil {
+		t.Fatalf("failed to update policy status: %v", err)
+	}
+	updated = reconcile()
+	if len(appEngine.appliedContainers) != 1 {
+		t.Errorf("applied %v, want only the workload whose quarantine expired", appEngine.appliedContainers)
+	}
+	if len(updated.Status.FailingWorkloads) != 1 || updated.Status.WorkloadsQuarantined != 1 {
+		t.Errorf("status = %+v with %d quarantined, want the released workload removed",
+			updated.Status.FailingWorkloads, updated.Status.WorkloadsQuarantined)
+	}
+}
+
+func TestReconcile_PolicyChangeReleasesQuarantine(t *testing.T) {
+	ctx := context.Background()
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Generation = 1
+	objects := append(createTestObjects(1), policy)
+	reconciler, fakeClient := createTestReconciler(&failingApplicationEngine{err: errors.New("conflict")}, objects...)
+	reconciler.Quarantine = QuarantineConfig{FailureThreshold: 1, Backoff: time.Hour, MaxBackoff: time.Hour}
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)}
+
+	if _, err := reconciler.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	updated := &optipodv1alpha1.OptimizationPolicy{}
+	if err := fakeClient.Get(ctx, request.NamespacedName, updated); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	if updated.Status.WorkloadsQuarantined != 1 {
+		t.Fatalf("workloadsQuarantined = %d, want 1", updated.Status.WorkloadsQuarantined)
+	}
+
+	// A spec change releases the workload right away
+	appEngine := &recordingApplicationEngine{}
+	reconciler.WorkloadProcessor = createTestProcessor(appEngine, nil)
+	updated.Generation = 2
+	if err := fakeClient.Update(ctx, updated); err != nil {
+		t.Fatalf("failed to update policy: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := fakeClient.Get(ctx, request.NamespacedName, updated); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	if len(appEngine.appliedContainers) != 1 {
+		t.Errorf("applied %v, want the released workload processed", appEngine.appliedContainers)
+	}
+	if len(updated.Status.FailingWorkloads) != 0 || updated.Status.WorkloadsQuarantined != 0 {
+		t.Errorf("status = %+v with %d quarantined, want the quarantine released",
+			updated.Status.FailingWorkloads, updated.Status.WorkloadsQuarantined)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// bytesPerGiB converts memory quantities to GiB for the CPU to memory ratio
+const bytesPerGiB = 1 << 30
+
+// implausibleRatio returns a description of the first recommendation whose CPU to memory ratio
+// falls outside the policy's plausible band, or an empty string when every ratio is plausible.
+// Recommendations without both a CPU and a memory value have no ratio and are not checked.
+func implausibleRatio(recommendations []optipodv1alpha1.ContainerRecommendation, ratio *optipodv1alpha1.CPUMemoryRatio) string {
+	minRatio, maxRatio := ratio.Band()
+	for _, rec := range recommendations {
+		if rec.CPU == nil || rec.Memory == nil || rec.Memory.Value() <= 0 {
+			continue
+		}
+		millicoresPerGiB := float64(rec.CPU.MilliValue()) * bytesPerGiB / float64(rec.Memory.Value())
+		if millicoresPerGiB < float64(minRatio) || millicoresPerGiB > float64(maxRatio) {
+			return fmt.Sprintf("container %s: %s CPU / %s memory is %.0fm per GiB, outside the plausible band %dm-%dm per GiB",
+				rec.Container, rec.CPU.String(), rec.Memory.String(), millicoresPerGiB, minRatio, maxRatio)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+func TestImplausibleRatio(t *testing.T) {
+	quantity := func(s string) *resource.Quantity {
+		q := resource.MustParse(s)
+		return &q
+	}
+
+	tests := []struct {
+		name            string
+		cpu, memory     *resource.Quantity
+		ratio           *optipodv1alpha1.CPUMemoryRatio
+		wantImplausible bool
+	}{
+		{name: "typical recommendation", cpu: quantity("250m"), memory: quantity("512Mi")},
+		{name: "4 cores with 64Mi exceeds the default band", cpu: quantity("4000m"), memory: quantity("64Mi"), wantImplausible: true},
+		{name: "memory heavy cache within the default band", cpu: quantity("50m"), memory: quantity("32Gi")},
+		{name: "1m with 4Ti is below the default band", cpu: quantity("1m"), memory: quantity("4Ti"), wantImplausible: true},
+		{name: "CPU only recommendation has no ratio", cpu: quantity("4000m")},
+		{
+			name:            "custom band",
+			cpu:             quantity("2000m"),
+			memory:          quantity("1Gi"),
+			ratio:           &optipodv1alpha1.CPUMemoryRatio{MaxMillicoresPerGiB: ptr.To[int64](1000)},
+			wantImplausible: true,
+		},
+		{
+			name:   "custom minimum only keeps the default maximum",
+			cpu:    quantity("8000m"),
+			memory: quantity("1Gi"),
+			ratio:  &optipodv1alpha1.CPUMemoryRatio{MinMillicoresPerGiB: ptr.To[int64](100)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs := []optipodv1alpha1.ContainerRecommendation{{Container: TestContainerName, CPU: tt.cpu, Memory: tt.memory}}
+			reason := implausibleRatio(recs, tt.ratio)
+			if (reason != "") != tt.wantImplausible {
+				t.Errorf("implausibleRatio() = %q, want implausible %v", reason, tt.wantImplausible)
+			}
+		})
+	}
+}
+
+func TestProcessWorkload_ImplausibleRatioNotApplied(t *testing.T) {
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.CPUMemoryRatio = &optipodv1alpha1.CPUMemoryRatio{MaxMillicoresPerGiB: ptr.To[int64](100)}
+	appEngine := &recordingApplicationEngine{}
+	processor := createTestProcessor(appEngine, nil)
+
+	status, err := processor.ProcessWorkload(context.Background(), createTestWorkload(TestContainerName), policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusSuspicious || !strings.Contains(status.Reason, TestContainerName) {
+		t.Errorf("status = %s (%s), want %s naming the container", status.Status, status.Reason, StatusSuspicious)
+	}
+	if len(status.Recommendations) != 1 {
+		t.Errorf("recommendations = %+v, want the suspicious recommendation kept for investigation", status.Recommendations)
+	}
+	if len(appEngine.appliedContainers) != 0 {
+		t.Errorf("applied %v, want nothing applied", appEngine.appliedContainers)
+	}
+}
+
+func TestReconcileSummary_SuspiciousCondition(t *testing.T) {
+	summary := &reconcileSummary{Discovered: 8}
+	if condition := summary.suspiciousCondition(); condition != nil {
+		t.Errorf("suspiciousCondition() = %+v, want nil without suspicious workloads", condition)
+	}
+
+	for _, name := range []string{"g", "f", "e", "d", "c", "b", "a"} {
+		summary.record(&optipodv1alpha1.WorkloadStatus{Name: name, Namespace: "ns", Status: StatusSuspicious}, nil)
+	}
+	summary.record(&optipodv1alpha1.WorkloadStatus{Name: "ok", Namespace: "ns", Status: StatusApplied}, nil)
+
+	condition := summary.suspiciousCondition()
+	want := "7 workload(s) have a recommendation outside the plausible CPU to memory ratio and were not updated: " +
+		"ns/a, ns/b, ns/c, ns/d, ns/e and 2 more"
+	if condition == nil || condition.Type != ConditionTypeSuspiciousRecommendation || condition.Message != want {
+		t.Errorf("suspiciousCondition() = %+v, want message %q", condition, want)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/cache"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/observability"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// SetRecommendationCache reuses the recommendation last computed for a container while its
+// workload's pod template, policy and usage are unchanged
+func (wp *WorkloadProcessor) SetRecommendationCache(recommendationCache *cache.RecommendationCache) {
+	wp.recommendationCache = recommendationCache
+}
+
+// computeRecommendation computes the recommendation of a container. With a recommendation cache,
+// the one cached for the container is reused when the workload's pod template, the policy and the
+// container context are unchanged and no usage statistic moved more than the hysteresis band since
+// it was computed. Workloads within their startup floor period are always recomputed, since the
+// floor depends on their age.
+func (wp *WorkloadProcessor) computeRecommendation(
+	workload *discovery.Workload,
+	containerName string,
+	windowed *recommendation.WindowedMetrics,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	workloadContext recommendation.WorkloadContext,
+) (*recommendation.Recommendation, error) {
+	templateHash := podTemplateHash(workload)
+	if wp.recommendationCache == nil || templateHash == "" ||
+		(workloadContext.Age != nil && *workloadContext.Age < policy.GetStartupFloorPeriod()) {
+		return wp.recommendationEngine.ComputeBlendedRecommendation(windowed, policy, workloadContext)
+	}
+
+	fingerprint, err := recommendationFingerprint(templateHash, policy, workloadContext)
+	if err != nil {
+		return wp.recommendationEngine.ComputeBlendedRecommendation(windowed, policy, workloadContext)
+	}
+	key := recommendationCacheKey(workload, containerName)
+	cpuPercent, memoryPercent := policy.Spec.RecommendationHysteresis.Band()
+	if rec, ok := wp.recommendationCache.Get(key, fingerprint, windowed, cpuPercent, memoryPercent); ok {
+		observability.RecommendationCacheLookups.WithLabelValues("hit").Inc()
+		return rec, nil
+	}
+	observability.RecommendationCacheLookups.WithLabelValues("miss").Inc()
+
+	rec, err := wp.recommendationEngine.ComputeBlendedRecommendation(windowed, policy, workloadContext)
+	if err != nil {
+		wp.recommendationCache.Invalidate(key)
+		return nil, err
+	}
+	wp.recommendationCache.Set(key, fingerprint, windowed, rec)
+	return rec, nil
+}
+
+// recommendationCacheKey identifies the cached recommendation of a container
+func recommendationCacheKey(workload *discovery.Workload, containerName string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", workload.Namespace, workload.Kind, workload.Name, containerName)
+}
+
+// podTemplateHash returns the hash of the workload's pod template and collision count, or "" for
+// unsupported kinds
+func podTemplateHash(workload *discovery.Workload) string {
+	switch obj := workload.Object.(type) {
+	case *appsv1.Deployment:
+		return computeTemplateHash(&obj.Spec.Template, obj.Status.CollisionCount)
+	case *appsv1.StatefulSet:
+		return computeTemplateHash(&obj.Spec.Template, obj.Status.CollisionCount)
+	case *appsv1.DaemonSet:
+		return computeTemplateHash(&obj.Spec.Template, obj.Status.CollisionCount)
+	default:
+		return ""
+	}
+}
+
+// computeTemplateHash hashes the template in its JSON form, which does not depend on the internal
+// state of quantities, together with the collision count, or returns "" if it cannot be encoded
+func computeTemplateHash(template *corev1.PodTemplateSpec, collisionCount *int32) string {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return ""
+	}
+
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(data)
+
+	// Add collisionCount in the hash if it exists
+	if collisionCount != nil {
+		collisionCountBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint32(collisionCountBytes, uint32(*collisionCount))
+		_, _ = hasher.Write(collisionCountBytes)
+	}
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+}
+
+// recommendationFingerprint identifies everything besides usage a container's recommendation is
+// computed from: the pod template, the effective policy (with ramped safety factor and container
+// bounds) and the container context. The workload age is left out, it only matters within the
+// startup floor period, which is never cached. The policy and context are hashed in their JSON
+// form, which does not depend on the internal state of quantities.
+func recommendationFingerprint(templateHash string, policy *optipodv1alpha1.OptimizationPolicy, workloadContext recommendation.WorkloadContext) (string, error) {
+	workloadContext.Age = nil
+	data, err := json.Marshal(struct {
+		Spec    optipodv1alpha1.OptimizationPolicySpec
+		Context recommendation.WorkloadContext
+	}{policy.Spec, workloadContext})
+	if err != nil {
+		return "", err
+	}
+	hasher := fnv.New64a()
+	_, _ = hasher.Write(data)
+	return templateHash + "-" + strconv.FormatUint(hasher.Sum64(), 16), nil
+}
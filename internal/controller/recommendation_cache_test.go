@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/cache"
+	"github.com/optipod/optipod/internal/metrics"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// newCachedRecommendationUsage returns rolling window usage whose statistics are all cpu and 256Mi
+func newCachedRecommendationUsage(cpu string) *recommendation.WindowedMetrics {
+	stats := func(q resource.Quantity) metrics.ResourceMetrics {
+		return metrics.ResourceMetrics{P50: q, P90: q, P99: q, Max: q, Samples: 100, Coverage: 1}
+	}
+	return &recommendation.WindowedMetrics{Long: &metrics.ContainerMetrics{
+		CPU:    stats(resource.MustParse(cpu)),
+		Memory: stats(resource.MustParse("256Mi")),
+	}}
+}
+
+func TestComputeRecommendation_CacheHitAndMissOnTemplateChange(t *testing.T) {
+	processor := createTestProcessor(&recordingApplicationEngine{}, nil)
+	processor.SetRecommendationCache(cache.NewRecommendationCache(time.Hour))
+	workload := createTestWorkload(TestContainerName)
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	compute := func(cpu string) resource.Quantity {
+		t.Helper()
+		rec, err := processor.computeRecommendation(workload, TestContainerName, newCachedRecommendationUsage(cpu), policy, recommendation.WorkloadContext{})
+		if err != nil {
+			t.Fatalf("computeRecommendation() error = %v", err)
+		}
+		return rec.CPU
+	}
+
+	first := compute("400m")
+
+	// Usage within the 5% default hysteresis band reuses the cached recommendation
+	if got := compute("415m"); got.Cmp(first) != 0 {
+		t.Errorf("CPU with unchanged template = %s, want the cached %s", got.String(), first.String())
+	}
+
+	// A new pod template mid-window recomputes from the current usage
+	workload.Object.(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Image = "test:v2"
+	recomputed := compute("415m")
+	if recomputed.Cmp(first) <= 0 {
+		t.Errorf("CPU after the template changed = %s, want it recomputed above %s", recomputed.String(), first.String())
+	}
+
+	// The recomputed recommendation is cached for the new template
+	if got := compute("410m"); got.Cmp(recomputed) != 0 {
+		t.Errorf("CPU with the new template = %s, want the cached %s", got.String(), recomputed.String())
+	}
+
+	// Usage outside the band recomputes even with the same template
+	if got := compute("600m"); got.Cmp(recomputed) <= 0 {
+		t.Errorf("CPU after usage grew = %s, want it recomputed above %s", got.String(), recomputed.String())
+	}
+}
+
+func TestComputeRecommendation_PolicyChangeMisses(t *testing.T) {
+	processor := createTestProcessor(&recordingApplicationEngine{}, nil)
+	processor.SetRecommendationCache(cache.NewRecommendationCache(time.Hour))
+	workload := createTestWorkload(TestContainerName)
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	usage := newCachedRecommendationUsage("400m")
+
+	before, err := processor.computeRecommendation(workload, TestContainerName, usage, policy, recommendation.WorkloadContext{})
+	if err != nil {
+		t.Fatalf("computeRecommendation() error = %v", err)
+	}
+
+	safetyFactor := 2.0
+	policy.Spec.MetricsConfig.SafetyFactor = &safetyFactor
+	after, err := processor.computeRecommendation(workload, TestContainerName, usage, policy, recommendation.WorkloadContext{})
+	if err != nil {
+		t.Fatalf("computeRecommendation() error = %v", err)
+	}
+	if after.CPU.Cmp(before.CPU) <= 0 {
+		t.Errorf("CPU after raising the safety factor = %s, want it recomputed above %s", after.CPU.String(), before.CPU.String())
+	}
+}
+
+func TestPodTemplateHash(t *testing.T) {
+	workload := createTestWorkload(TestContainerName)
+	deployment := workload.Object.(*appsv1.Deployment)
+	requests := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")}
+	deployment.Spec.Template.Spec.Containers[0].Resources.Requests = requests
+
+	hash := podTemplateHash(workload)
+	if hash == "" || podTemplateHash(workload) != hash {
+		t.Fatalf("podTemplateHash() = %q, want a stable non-empty hash", hash)
+	}
+
+	// Metadata outside the template, such as OptiPod's own annotations, does not change the hash
+	deployment.Annotations = map[string]string{optipodv1alpha1.AnnotationStabilityScore: "90"}
+	if got := podTemplateHash(workload); got != hash {
+		t.Errorf("podTemplateHash() = %q after annotating the deployment, want %q", got, hash)
+	}
+
+	// Printing a quantity caches its string form, which does not change the hash
+	cpu := requests[corev1.ResourceCPU]
+	_ = cpu.String()
+	requests[corev1.ResourceCPU] = cpu
+	if got := podTemplateHash(workload); got != hash {
+		t.Errorf("podTemplateHash() = %q after printing a request, want %q", got, hash)
+	}
+
+	deployment.Status.CollisionCount = ptr.To(int32(1))
+	collided := podTemplateHash(workload)
+	if collided == hash {
+		t.Errorf("podTemplateHash() = %q after a collision, want a new hash", collided)
+	}
+
+	deployment.Spec.Template.Spec.Containers[0].Image = "test:v2"
+	if got := podTemplateHash(workload); got == collided {
+		t.Errorf("podTemplateHash() = %q after changing the image, want a new hash", got)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+func TestPolicyChangePredicate(t *testing.T) {
+	old := createTestPolicy(optipodv1alpha1.ModeAuto)
+	old.Generation = 1
+
+	tests := []struct {
+		name        string
+		update      func(policy *optipodv1alpha1.OptimizationPolicy)
+		wantEnqueue bool
+	}{
+		{
+			name: "reconcile-now annotation set",
+			update: func(policy *optipodv1alpha1.OptimizationPolicy) {
+				policy.Annotations = map[string]string{optipodv1alpha1.AnnotationReconcileNow: "1760500000"}
+			},
+			wantEnqueue: true,
+		},
+		{
+			name: "spec change",
+			update: func(policy *optipodv1alpha1.OptimizationPolicy) {
+				policy.Spec.Mode = optipodv1alpha1.ModeRecommend
+				policy.Generation++
+			},
+			wantEnqueue: true,
+		},
+		{
+			name: "status-only update",
+			update: func(policy *optipodv1alpha1.OptimizationPolicy) {
+				now := metav1.Now()
+				policy.Status.LastReconciliation = &now
+			},
+			wantEnqueue: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := old.DeepCopy()
+			tt.update(updated)
+			if got := policyChangePredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated}); got != tt.wantEnqueue {
+				t.Errorf("Update() = %v, want %v", got, tt.wantEnqueue)
+			}
+		})
+	}
+}
+
+func TestReconcile_RecordsManualReconcile(t *testing.T) {
+	ctx := context.Background()
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Annotations = map[string]string{optipodv1alpha1.AnnotationReconcileNow: "2026-10-15T10:00:00Z"}
+	reconciler, fakeClient := createTestReconciler(&recordingApplicationEngine{}, append(createTestObjects(1), policy)...)
+
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)}
+	if _, err := reconciler.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	stored := &optipodv1alpha1.OptimizationPolicy{}
+	if err := fakeClient.Get(ctx, request.NamespacedName, stored); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	if stored.Status.LastManualReconcile != "2026-10-15T10:00:00Z" {
+		t.Errorf("lastManualReconcile = %q, want the annotation value", stored.Status.LastManualReconcile)
+	}
+	if manualReconcileRequest(stored) != "" {
+		t.Error("the recorded manual reconcile is still reported as requested")
+	}
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/config"
+	"github.com/optipod/optipod/internal/observability"
+)
+
+// createTestScheme returns a scheme with all types used by the reconciler
+func createTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = optipodv1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	return scheme
+}
+
+// createTestObjects returns a namespace with the given number of matching deployments
+func createTestObjects(deployments int) []client.Object {
+	objects := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: TestNamespace}},
+	}
+	for i := 0; i < deployments; i++ {
+		deployment := createTestWorkload(TestContainerName).Object.(*appsv1.Deployment)
+		deployment.Name = fmt.Sprintf("%s-%d", TestWorkloadName, i)
+		objects = append(objects, deployment)
+	}
+	return objects
+}
+
+// createTestReconciler builds a reconciler backed by a fake client
+func createTestReconciler(appEngine ApplicationEngine, objects ...client.Object) (*OptimizationPolicyReconciler, client.Client) {
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(createTestScheme()).
+		WithObjects(objects...).
+		WithStatusSubresource(&optipodv1alpha1.OptimizationPolicy{}).
+		Build()
+
+	return &OptimizationPolicyReconciler{
+		Client:            fakeClient,
+		Scheme:            fakeClient.Scheme(),
+		Recorder:          record.NewFakeRecorder(100),
+		WorkloadProcessor: createTestProcessor(appEngine, nil),
+	}, fakeClient
+}
+
+func TestProcessWorkloads_MaxWorkloadsCap(t *testing.T) {
+	tests := []struct {
+		name           string
+		deployments    int
+		policyMax      *int32
+		defaultMax     int
+		wantDiscovered int
+		wantProcessed  int
+		wantExceeded   bool
+		wantApplyCount int
+	}{
+		{
+			name:           "no cap applies all workloads",
+			deployments:    3,
+			wantDiscovered: 3,
+			wantProcessed:  3,
+			wantExceeded:   false,
+			wantApplyCount: 3,
+		},
+		{
+			name:           "within policy cap applies all workloads",
+			deployments:    3,
+			policyMax:      int32Ptr(3),
+			wantDiscovered: 3,
+			wantProcessed:  3,
+			wantExceeded:   false,
+			wantApplyCount: 3,
+		},
+		{
+			name:           "policy cap exceeded falls back to recommend-only",
+			deployments:    3,
+			policyMax:      int32Ptr(2),
+			wantDiscovered: 3,
+			wantProcessed:  3,
+			wantExceeded:   true,
+			wantApplyCount: 0,
+		},
+		{
+			name:           "operator default cap exceeded falls back to recommend-only",
+			deployments:    3,
+			defaultMax:     1,
+			wantDiscovered: 3,
+			wantProcessed:  2,
+			wantExceeded:   true,
+			wantApplyCount: 0,
+		},
+		{
+			name:           "policy cap overrides operator default",
+			deployments:    3,
+			policyMax:      int32Ptr(5),
+			defaultMax:     1,
+			wantDiscovered: 3,
+			wantProcessed:  3,
+			wantExceeded:   false,
+			wantApplyCount: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+			policy.Spec.MaxWorkloads = tt.policyMax
+
+			appEngine := &recordingApplicationEngine{}
+			objects := append(createTestObjects(tt.deployments), policy)
+			reconciler, fakeClient := createTestReconciler(appEngine, objects...)
+			reconciler.OperatorConfig = &config.OperatorConfig{DefaultMaxWorkloads: tt.defaultMax}
+
+			summary, err := reconciler.processWorkloadsWithPolicySelection(ctx, policy)
+			if err != nil {
+				t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+			}
+
+			// Workloads past the cap are counted but not kept, and the ones kept are only recommended
+			if summary.Discovered != tt.wantDiscovered {
+				t.Errorf("discovered = %d, want %d", summary.Discovered, tt.wantDiscovered)
+			}
+			if summary.Processed != tt.wantProcessed {
+				t.Errorf("processed = %d, want %d", summary.Processed, tt.wantProcessed)
+			}
+			if len(appEngine.appliedContainers) != tt.wantApplyCount {
+				t.Errorf("applied %d containers, want %d", len(appEngine.appliedContainers), tt.wantApplyCount)
+			}
+
+			updated := &optipodv1alpha1.OptimizationPolicy{}
+			if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), updated); err != nil {
+				t.Fatalf("failed to get policy: %v", err)
+			}
+			exceeded := meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeTooManyWorkloads)
+			if exceeded != tt.wantExceeded {
+				t.Errorf("TooManyWorkloads condition = %v, want %v", exceeded, tt.wantExceeded)
+			}
+			if condition := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeTooManyWorkloads); tt.wantExceeded &&
+				!strings.Contains(condition.Message, fmt.Sprintf("matches %d workloads", tt.wantDiscovered)) {
+				t.Errorf("TooManyWorkloads message = %q, want the matched count %d", condition.Message, tt.wantDiscovered)
+			}
+		})
+	}
+}
+
+func TestProcessWorkloads_MaxWorkloadsConditionCleared(t *testing.T) {
+	ctx := context.Background()
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.MaxWorkloads = int32Ptr(1)
+
+	objects := append(createTestObjects(2), policy)
+	reconciler, fakeClient := createTestReconciler(&recordingApplicationEngine{}, objects...)
+
+	if _, err := reconciler.processWorkloadsWithPolicySelection(ctx, policy); err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+
+	// Raise the cap and reconcile again with the latest policy
+	updated := &optipodv1alpha1.OptimizationPolicy{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), updated); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	updated.Spec.MaxWorkloads = int32Ptr(10)
+	if err := fakeClient.Update(ctx, updated); err != nil {
+		t.Fatalf("failed to update policy: %v", err)
+	}
+
+	if _, err := reconciler.processWorkloadsWithPolicySelection(ctx, updated); err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), updated); err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	condition := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeTooManyWorkloads)
+	if condition == nil || condition.Status != metav1.ConditionFalse {
+		t.Errorf("expected TooManyWorkloads condition to be cleared, got %+v", condition)
+	}
+}
+
+func TestProcessWorkloads_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	appEngine := &cancellingApplicationEngine{cancel: cancel}
+	objects := append(createTestObjects(3), policy)
+	reconciler, _ := createTestReconciler(appEngine, objects...)
+
+	summary, err := reconciler.processWorkloadsWithPolicySelection(ctx, policy)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v, want context.Canceled", err)
+	}
+
+	// The workload being applied when the context was cancelled is finished, the rest are left
+	// for the next reconcile
+	if summary.Processed != 1 || summary.Applied != 1 {
+		t.Errorf("processed/applied = %d/%d, want 1/1", summary.Processed, summary.Applied)
+	}
+	if len(appEngine.appliedContainers) != 1 {
+		t.Errorf("applied %d containers, want 1", len(appEngine.appliedContainers))
+	}
+}
+
+// phaseSampleCount returns the number of durations observed for a reconciliation phase of the policy
+func phaseSampleCount(t *testing.T, policy, phase string) uint64 {
+	t.Helper()
+	metric := &dto.Metric{}
+	if err := observability.ReconcilePhaseDuration.WithLabelValues(policy, phase).(prometheus.Metric).Write(metric); err != nil {
+		t.Fatalf("failed to read %s phase duration: %v", phase, err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestProcessWorkloads_PhaseDurations(t *testing.T) {
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	phases := []string{
+		observability.PhaseDiscovery,
+		observability.PhaseMetrics,
+		observability.PhaseRecommendation,
+		observability.PhaseApply,
+	}
+	before := map[string]uint64{}
+	for _, phase := range phases {
+		before[phase] = phaseSampleCount(t, policy.Name, phase)
+	}
+
+	appEngine := &recordingApplicationEngine{}
+	reconciler, _ := createTestReconciler(appEngine, append(createTestObjects(2), policy)...)
+	if _, err := reconciler.processWorkloadsWithPolicySelection(context.Background(), policy); err != nil {
+		t.Fatalf("processWorkloadsWithPolicySelection() error = %v", err)
+	}
+
+	// Discovery runs once per reconciliation, the other phases once per workload
+	want := map[string]uint64{
+		observability.PhaseDiscovery:      1,
+		observability.PhaseMetrics:        2,
+		observability.PhaseRecommendation: 2,
+		observability.PhaseApply:          2,
+	}
+	for _, phase := range phases {
+		if got := phaseSampleCount(t, policy.Name, phase) - before[phase]; got != want[phase] {
+			t.Errorf("%s phase observed %d times, want %d", phase, got, want[phase])
+		}
+	}
+
+	families, err := ctrlmetrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	registered := false
+	for _, family := range families {
+		if family.GetName() == "optipod_reconcile_phase_duration_seconds" {
+			registered = true
+		}
+	}
+	if !registered {
+		t.Error("optipod_reconcile_phase_duration_seconds is not registered")
+	}
+}
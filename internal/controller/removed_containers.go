@@ -0,0 +1,171 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/observability"
+)
+
+// recommendationAnnotationSuffixes are the per-container recommendation annotations, written as
+// <AnnotationRecommendationPrefix>.<container><suffix>
+var recommendationAnnotationSuffixes = []string{".cpu-request", ".memory-request", ".cpu-limit", ".memory-limit"}
+
+// removedContainers returns the containers the workload's annotations hold state for that are no
+// longer in its pod template, sorted by name
+func removedContainers(annotations map[string]string, podSpec *corev1.PodSpec) []string {
+	present := make(map[string]bool, len(podSpec.Containers)+len(podSpec.InitContainers))
+	for _, container := range podSpec.Containers {
+		present[container.Name] = true
+	}
+	for _, container := range podSpec.InitContainers {
+		present[container.Name] = true
+	}
+
+	removed := make(map[string]bool)
+	for key := range annotations {
+		if container, ok := recommendationAnnotationContainer(key); ok && !present[container] {
+			removed[container] = true
+		}
+	}
+	for key := range parseDecreaseObservations(annotations[optipodv1alpha1.AnnotationDecreaseObservations]) {
+		if container, ok := decreaseKeyContainer(key); ok && !present[container] {
+			removed[container] = true
+		}
+	}
+	for container := range parseMemoryPeaks(annotations[optipodv1alpha1.AnnotationMemoryPeaks]) {
+		if !present[container] {
+			removed[container] = true
+		}
+	}
+
+	names := make([]string, 0, len(removed))
+	for container := range removed {
+		names = append(names, container)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// recommendationAnnotationContainer returns the container of a per-container recommendation annotation
+func recommendationAnnotationContainer(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, optipodv1alpha1.AnnotationRecommendationPrefix+".")
+	if !ok {
+		return "", false
+	}
+	for _, suffix := range recommendationAnnotationSuffixes {
+		if container, ok := strings.CutSuffix(rest, suffix); ok && container != "" {
+			return container, true
+		}
+	}
+	return "", false
+}
+
+// decreaseKeyContainer returns the container of a decrease observations key, see decreaseKey
+func decreaseKeyContainer(key string) (string, bool) {
+	for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if container, ok := strings.CutSuffix(key, "."+string(resourceName)); ok && container != "" {
+			return container, true
+		}
+	}
+	return "", false
+}
+
+// removedContainerAnnotations returns the annotation changes dropping the state of the removed
+// containers: their recommendation annotations are removed (empty value) and the decrease
+// observations and memory peaks are rewritten without them
+func removedContainerAnnotations(annotations map[string]string, removed []string) map[string]string {
+	gone := make(map[string]bool, len(removed))
+	for _, container := range removed {
+		gone[container] = true
+	}
+
+	changes := make(map[string]string)
+	for key := range annotations {
+		if container, ok := recommendationAnnotationContainer(key); ok && gone[container] {
+			changes[key] = ""
+		}
+	}
+	if value, ok := annotations[optipodv1alpha1.AnnotationDecreaseObservations]; ok {
+		counts := parseDecreaseObservations(value)
+		for key := range counts {
+			if container, ok := decreaseKeyContainer(key); ok && gone[container] {
+				delete(counts, key)
+			}
+		}
+		changes[optipodv1alpha1.AnnotationDecreaseObservations] = formatDecreaseObservations(counts)
+	}
+	if value, ok := annotations[optipodv1alpha1.AnnotationMemoryPeaks]; ok {
+		peaks := parseMemoryPeaks(value)
+		for container := range peaks {
+			if gone[container] {
+				delete(peaks, container)
+			}
+		}
+		changes[optipodv1alpha1.AnnotationMemoryPeaks] = formatMemoryPeaks(peaks)
+	}
+	return changes
+}
+
+// cleanupRemovedContainers removes the state kept for containers that are no longer in the
+// workload's pod template: their recommendation annotations, decrease observations, memory
+// peaks, cached recommendations and utilization metrics. It returns the cleaned up containers. Nothing is
+// removed when the policy retains the state of removed containers.
+func (wp *WorkloadProcessor) cleanupRemovedContainers(
+	ctx context.Context,
+	workload *discovery.Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) ([]string, error) {
+	if policy.GetRemovedContainers() == optipodv1alpha1.RemovedContainersRetain || workload.Object == nil {
+		return nil, nil
+	}
+	podSpec, err := workloadPodSpec(workload)
+	if err != nil {
+		return nil, nil
+	}
+	annotations := workload.Object.GetAnnotations()
+	removed := removedContainers(annotations, podSpec)
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	if err := wp.setWorkloadAnnotations(ctx, workload, removedContainerAnnotations(annotations, removed), false); err != nil {
+		return nil, fmt.Errorf("failed to remove annotations of removed containers: %w", err)
+	}
+	for _, container := range removed {
+		if wp.recommendationCache != nil {
+			wp.recommendationCache.Invalidate(recommendationCacheKey(workload, container))
+		}
+		for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			observability.RecommendationUtilization.DeleteLabelValues(policy.Name, workload.Namespace, workload.Name,
+				workload.Kind, container, string(resourceName))
+		}
+	}
+	logf.FromContext(ctx).Info("Cleaned up the state of containers removed from the workload",
+		"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+		"containers", removed)
+	return removed, nil
+}
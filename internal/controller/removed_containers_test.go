@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+)
+
+func TestProcessWorkload_RemovedContainers(t *testing.T) {
+	const sidecar = "sidecar"
+
+	tests := []struct {
+		name        string
+		handling    string
+		wantRemoved bool
+	}{
+		{name: "cleanup by default", wantRemoved: true},
+		{name: "retain", handling: optipodv1alpha1.RemovedContainersRetain, wantRemoved: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			workload := createTestWorkload(TestContainerName, sidecar)
+			deployment := workload.Object.(*appsv1.Deployment)
+			pod := createTestPod(TestPodName)
+			fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(deployment.DeepCopy(), pod).Build()
+			processor := createTestProcessor(&recordingApplicationEngine{}, fakeClient)
+
+			policy := createTestPolicy(optipodv1alpha1.ModeRecommend)
+			policy.Spec.RemovedContainers = tt.handling
+			if _, err := processor.ProcessWorkload(ctx, workload, policy); err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+
+			// The sidecar is removed from the template between reconciles
+			stored := &appsv1.Deployment{}
+			if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), stored); err != nil {
+				t.Fatalf("failed to get deployment: %v", err)
+			}
+			if stored.Annotations["optipod.io/recommendation.sidecar.cpu-request"] == "" {
+				t.Fatalf("annotations = %v, want the sidecar recommendation recorded", stored.Annotations)
+			}
+			stored.Annotations[optipodv1alpha1.AnnotationDecreaseObservations] = "sidecar.cpu=2,test-container.memory=1"
+			stored.Annotations[optipodv1alpha1.AnnotationMemoryPeaks] = "sidecar=1024@1,test-container=2048@1"
+			stored.Spec.Template.Spec.Containers = stored.Spec.Template.Spec.Containers[:1]
+			if err := fakeClient.Update(ctx, stored); err != nil {
+				t.Fatalf("failed to update deployment: %v", err)
+			}
+
+			workload = &discovery.Workload{Kind: KindDeployment, Namespace: TestNamespace, Name: TestWorkloadName, Object: stored}
+			status, err := processor.ProcessWorkload(ctx, workload, policy)
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), stored); err != nil {
+				t.Fatalf("failed to get deployment: %v", err)
+			}
+
+			var sidecarKeys []string
+			for key := range stored.Annotations {
+				if strings.HasPrefix(key, "optipod.io/recommendation.sidecar.") {
+					sidecarKeys = append(sidecarKeys, key)
+				}
+			}
+			observations := stored.Annotations[optipodv1alpha1.AnnotationDecreaseObservations]
+			peaks := stored.Annotations[optipodv1alpha1.AnnotationMemoryPeaks]
+			if tt.wantRemoved {
+				if len(status.RemovedContainers) != 1 || status.RemovedContainers[0] != sidecar {
+					t.Errorf("removedContainers = %v, want [sidecar]", status.RemovedContainers)
+				}
+				if len(sidecarKeys) != 0 || observations != "test-container.memory=1" || peaks != "test-container=2048@1" {
+					t.Errorf("sidecar annotations %v, decrease observations %q and memory peaks %q remain, want them cleaned up",
+						sidecarKeys, observations, peaks)
+				}
+			} else if len(status.RemovedContainers) != 0 || len(sidecarKeys) == 0 ||
+				observations != "sidecar.cpu=2,test-container.memory=1" || peaks != "sidecar=1024@1,test-container=2048@1" {
+				t.Errorf("removedContainers %v, sidecar annotations %v, decrease observations %q and memory peaks %q, want them retained",
+					status.RemovedContainers, sidecarKeys, observations, peaks)
+			}
+
+			// The remaining container keeps its state
+			if stored.Annotations["optipod.io/recommendation.test-container.cpu-request"] == "" {
+				t.Errorf("annotations = %v, want the remaining container's recommendation kept", stored.Annotations)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/optipod/optipod/internal/discovery"
+)
+
+// getReplicaCounts returns the number of replicas a workload currently runs and the number it is
+// converging to. The target is the desired replica count of the HPA scaling the workload, clamped
+// to the HPA's replica range, or spec.replicas when no HPA targets it. DaemonSets are not scaled
+// by replica count, so both counts are zero for them.
+func (wp *WorkloadProcessor) getReplicaCounts(ctx context.Context, workload *discovery.Workload) (int32, int32, error) {
+	var specReplicas *int32
+	var statusReplicas int32
+
+	switch obj := workload.Object.(type) {
+	case *appsv1.Deployment:
+		specReplicas, statusReplicas = obj.Spec.Replicas, obj.Status.Replicas
+	case *appsv1.StatefulSet:
+		specReplicas, statusReplicas = obj.Spec.Replicas, obj.Status.Replicas
+	default:
+		return 0, 0, nil
+	}
+
+	// spec.replicas defaults to 1
+	target := int32(1)
+	if specReplicas != nil {
+		target = *specReplicas
+	}
+	current := statusReplicas
+	if current == 0 {
+		current = target
+	}
+
+	if wp.client == nil {
+		return current, target, nil
+	}
+
+	hpa, err := wp.findHPA(ctx, workload)
+	if err != nil {
+		return 0, 0, err
+	}
+	if hpa == nil {
+		return current, target, nil
+	}
+
+	if hpa.Status.CurrentReplicas > 0 {
+		current = hpa.Status.CurrentReplicas
+	}
+	if hpa.Status.DesiredReplicas > 0 {
+		target = hpa.Status.DesiredReplicas
+	}
+
+	minReplicas := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+	if target < minReplicas {
+		target = minReplicas
+	}
+	if hpa.Spec.MaxReplicas > 0 && target > hpa.Spec.MaxReplicas {
+		target = hpa.Spec.MaxReplicas
+	}
+
+	return current, target, nil
+}
+
+// findHPA returns the HorizontalPodAutoscaler whose scale target is the workload, or nil if none
+func (wp *WorkloadProcessor) findHPA(
+	ctx context.Context,
+	workload *discovery.Workload,
+) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := wp.client.List(ctx, hpaList, client.InNamespace(workload.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list HorizontalPodAutoscalers: %w", err)
+	}
+
+	for i := range hpaList.Items {
+		ref := hpaList.Items[i].Spec.ScaleTargetRef
+		if ref.Kind == workload.Kind && ref.Name == workload.Name {
+			return &hpaList.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+)
+
+func newReplicaTestHPA(targetName string, minReplicas *int32, maxReplicas, current, desired int32) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: targetName + "-hpa", Namespace: TestNamespace},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       KindDeployment,
+				Name:       targetName,
+			},
+			MinReplicas: minReplicas,
+			MaxReplicas: maxReplicas,
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: current,
+			DesiredReplicas: desired,
+		},
+	}
+}
+
+func TestGetReplicaCounts(t *testing.T) {
+	replicas := func(n int32) *int32 { return &n }
+
+	tests := []struct {
+		name        string
+		workload    func() *discovery.Workload
+		hpas        []client.Object
+		wantCurrent int32
+		wantTarget  int32
+	}{
+		{
+			name: "deployment without an HPA uses its replica counts",
+			workload: func() *discovery.Workload {
+				workload := createTestWorkload(TestContainerName)
+				deployment := workload.Object.(*appsv1.Deployment)
+				deployment.Spec.Replicas = replicas(5)
+				deployment.Status.Replicas = 3
+				return workload
+			},
+			wantCurrent: 3,
+			wantTarget:  5,
+		},
+		{
+			name: "HPA desired replicas are the target",
+			workload: func() *discovery.Workload {
+				return createTestWorkload(TestContainerName)
+			},
+			hpas:        []client.Object{newReplicaTestHPA(TestWorkloadName, replicas(2), 10, 4, 8)},
+			wantCurrent: 4,
+			wantTarget:  8,
+		},
+		{
+			name: "HPA desired replicas are clamped to the HPA range",
+			workload: func() *discovery.Workload {
+				return createTestWorkload(TestContainerName)
+			},
+			hpas:        []client.Object{newReplicaTestHPA(TestWorkloadName, replicas(2), 6, 4, 9)},
+			wantCurrent: 4,
+			wantTarget:  6,
+		},
+		{
+			name: "HPA for another workload is ignored",
+			workload: func() *discovery.Workload {
+				return createTestWorkload(TestContainerName)
+			},
+			hpas:        []client.Object{newReplicaTestHPA("other", nil, 10, 4, 8)},
+			wantCurrent: 1,
+			wantTarget:  1,
+		},
+		{
+			name: "daemonsets are not scaled by replica count",
+			workload: func() *discovery.Workload {
+				return &discovery.Workload{
+					Kind:      "DaemonSet",
+					Namespace: TestNamespace,
+					Name:      TestWorkloadName,
+					Object:    &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: TestWorkloadName, Namespace: TestNamespace}},
+				}
+			},
+			wantCurrent: 0,
+			wantTarget:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := createTestScheme()
+			_ = autoscalingv2.AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.hpas...).Build()
+			processor := createTestProcessor(&recordingApplicationEngine{}, fakeClient)
+
+			current, target, err := processor.getReplicaCounts(context.Background(), tt.workload())
+			if err != nil {
+				t.Fatalf("getReplicaCounts() error = %v", err)
+			}
+			if current != tt.wantCurrent || target != tt.wantTarget {
+				t.Errorf("getReplicaCounts() = (%d, %d), want (%d, %d)", current, target, tt.wantCurrent, tt.wantTarget)
+			}
+		})
+	}
+}
+
+func TestGetWorkloadContext_ReplicaScaling(t *testing.T) {
+	scheme := createTestScheme()
+	_ = autoscalingv2.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newReplicaTestHPA(TestWorkloadName, nil, 10, 2, 4)).
+		Build()
+	processor := createTestProcessor(&recordingApplicationEngine{}, fakeClient)
+	workload := createTestWorkload(TestContainerName)
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+
+	// Replica counts are only resolved when the policy enables replica scaling
+	workloadContext, _ := processor.getWorkloadContext(context.Background(), workload, policy)
+	if workloadContext.CurrentReplicas != 0 || workloadContext.TargetReplicas != 0 {
+		t.Errorf("expected no replica counts without replica scaling, got %+v", workloadContext)
+	}
+
+	policy.Spec.ReplicaScaling = &optipodv1alpha1.ReplicaScaling{Enabled: true}
+	workloadContext, _ = processor.getWorkloadContext(context.Background(), workload, policy)
+	if workloadContext.CurrentReplicas != 2 || workloadContext.TargetReplicas != 4 {
+		t.Errorf("expected replica counts 2/4 from the HPA, got %d/%d", workloadContext.CurrentReplicas, workloadContext.TargetReplicas)
+	}
+}
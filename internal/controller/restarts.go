@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// restartObservationTTL is how long the restart count of a container whose pod is no longer
+// observed is remembered
+const restartObservationTTL = 24 * time.Hour
+
+// restartTracker counts the container restarts that happened within a window. Pod statuses only
+// carry the cumulative restart count of a container, which grows for as long as the pod lives, so
+// the tracker remembers the count it last observed for each container and records its growth as
+// restarts at the time it was observed. It is safe for concurrent use across reconciles.
+type restartTracker struct {
+	now func() time.Time
+
+	mu         sync.Mutex
+	containers map[restartKey]*observedRestarts
+}
+
+// restartKey identifies a container of a pod
+type restartKey struct {
+	pod       types.UID
+	container string
+}
+
+// observedRestarts is what the tracker knows about the restarts of one container
+type observedRestarts struct {
+	// count is the cumulative restart count last observed
+	count int32
+	// seen is when the container was last observed
+	seen time.Time
+	// restarts are the restarts observed within the window last asked for
+	restarts []restartEvent
+}
+
+// restartEvent is a number of restarts observed at the same time
+type restartEvent struct {
+	at    time.Time
+	count int32
+}
+
+// newRestartTracker creates a tracker that has not observed any container yet
+func newRestartTracker() *restartTracker {
+	return &restartTracker{
+		now:        time.Now,
+		containers: make(map[restartKey]*observedRestarts),
+	}
+}
+
+// observe records the restart counts of the pods' containers and returns how often each container
+// restarted since the given time, summed across the pods. When a container is first observed, its
+// restarts are counted as within the window only if its pod started within the window; otherwise
+// its last termination within the window counts as one restart, since the time of earlier ones is
+// unknown.
+func (t *restartTracker) observe(pods []corev1.Pod, since time.Time) map[string]int32 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	restarts := make(map[string]int32)
+	for _, pod := range pods {
+		// Init container statuses carry the restarts of native sidecars
+		for _, status := range slices.Concat(pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses) {
+			key := restartKey{pod: pod.UID, container: status.Name}
+			observed, ok := t.containers[key]
+			switch {
+			case !ok || status.RestartCount < observed.count:
+				observed = &observedRestarts{count: status.RestartCount}
+				if event, ok := initialRestarts(&pod, status, since); ok {
+					observed.restarts = []restartEvent{event}
+				}
+				t.containers[key] = observed
+			case status.RestartCount > observed.count:
+				observed.restarts = append(observed.restarts, restartEvent{at: now, count: status.RestartCount - observed.count})
+				observed.count = status.RestartCount
+			}
+			observed.seen = now
+
+			observed.restarts = slices.DeleteFunc(observed.restarts, func(event restartEvent) bool {
+				return !event.at.After(since)
+			})
+			for _, event := range observed.restarts {
+				restarts[status.Name] += event.count
+			}
+		}
+	}
+
+	t.prune(now)
+	return restarts
+}
+
+// initialRestarts returns the restarts of a container observed for the first time that are known
+// to have happened after since
+func initialRestarts(pod *corev1.Pod, status corev1.ContainerStatus, since time.Time) (restartEvent, bool) {
+	if status.RestartCount <= 0 {
+		return restartEvent{}, false
+	}
+	if pod.Status.StartTime != nil && pod.Status.StartTime.After(since) {
+		return restartEvent{at: pod.Status.StartTime.Time, count: status.RestartCount}, true
+	}
+	if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.FinishedAt.After(since) {
+		return restartEvent{at: terminated.FinishedAt.Time, count: 1}, true
+	}
+	return restartEvent{}, false
+}
+
+// prune forgets containers not observed within restartObservationTTL, i.e. of deleted pods
+func (t *restartTracker) prune(now time.Time) {
+	for key, observed := range t.containers {
+		if now.Sub(observed.seen) > restartObservationTTL {
+			delete(t.containers, key)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRestartTracker(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	tracker := newRestartTracker()
+	tracker.now = func() time.Time { return now }
+	window := time.Hour
+
+	newPod := func(uid types.UID, started time.Time, restarts int32, lastFinished time.Time) corev1.Pod {
+		status := corev1.ContainerStatus{Name: TestContainerName, RestartCount: restarts}
+		if !lastFinished.IsZero() {
+			status.LastTerminationState.Terminated = &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(lastFinished)}
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{UID: uid},
+			Status: corev1.PodStatus{
+				StartTime:         &metav1.Time{Time: started},
+				ContainerStatuses: []corev1.ContainerStatus{status},
+			},
+		}
+	}
+
+	// A long-running pod restarted 50 times days ago, a new pod restarted twice since it started
+	oldPod := newPod("old", now.Add(-72*time.Hour), 50, now.Add(-48*time.Hour))
+	newerPod := newPod("new", now.Add(-10*time.Minute), 2, now.Add(-time.Minute))
+	if got := tracker.observe([]corev1.Pod{oldPod, newerPod}, now.Add(-window)); got[TestContainerName] != 2 {
+		t.Errorf("first observation = %d restarts, want the 2 of the pod started within the window", got[TestContainerName])
+	}
+
+	// The old pod restarts three times; its earlier restarts stay out of the count
+	now = now.Add(10 * time.Minute)
+	oldPod.Status.ContainerStatuses[0].RestartCount = 53
+	if got := tracker.observe([]corev1.Pod{oldPod, newerPod}, now.Add(-window)); got[TestContainerName] != 5 {
+		t.Errorf("second observation = %d restarts, want 5", got[TestContainerName])
+	}
+
+	// Once the window has passed, the restarts drop out
+	now = now.Add(2 * time.Hour)
+	if got := tracker.observe([]corev1.Pod{oldPod, newerPod}, now.Add(-window)); got[TestContainerName] != 0 {
+		t.Errorf("observation after the window = %d restarts, want 0", got[TestContainerName])
+	}
+}
+
+func TestRestartTracker_FirstObservationOfLongRunningPod(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	tracker := newRestartTracker()
+	tracker.now = func() time.Time { return now }
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{UID: "pod"},
+		Status: corev1.PodStatus{
+			StartTime: &metav1.Time{Time: now.Add(-72 * time.Hour)},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         TestContainerName,
+				RestartCount: 40,
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					FinishedAt: metav1.NewTime(now.Add(-5 * time.Minute)),
+				}},
+			}},
+		},
+	}
+
+	// Only the last restart is known to be within the window
+	if got := tracker.observe([]corev1.Pod{pod}, now.Add(-time.Hour)); got[TestContainerName] != 1 {
+		t.Errorf("observe() = %d restarts, want 1", got[TestContainerName])
+	}
+}
+
+func TestRestartTracker_ForgetsDeletedPods(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	tracker := newRestartTracker()
+	tracker.now = func() time.Time { return now }
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{UID: "pod"},
+		Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: TestContainerName}}},
+	}
+	tracker.observe([]corev1.Pod{pod}, now.Add(-time.Hour))
+
+	now = now.Add(restartObservationTTL + time.Minute)
+	tracker.observe(nil, now.Add(-time.Hour))
+	if len(tracker.containers) != 0 {
+		t.Errorf("tracker still remembers %d containers of deleted pods", len(tracker.containers))
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/optipod/optipod/internal/discovery"
+)
+
+// stableRolloutRequeueDelay is how soon a reconciliation is repeated while a workload waits for
+// its rollout to finish
+const stableRolloutRequeueDelay = time.Minute
+
+// rolloutInProgress describes the rollout the workload's controller has in progress, or returns
+// an empty string when the workload is stable. A rollout is in progress while the controller has
+// not observed the latest spec or pods still run an older revision than the update revision.
+// Pods that are merely unavailable or not ready, e.g. crash looping for lack of memory, do not
+// count: waiting for them would hold back the very change that fixes them.
+//
+// A workload whose controller has not reported a status yet is taken as stable, as are OnDelete
+// workloads, whose rollouts only progress when their pods are deleted, and paused or stalled
+// Deployments, whose rollouts do not progress until someone intervenes.
+func rolloutInProgress(workload *discovery.Workload) string {
+	switch obj := workload.Object.(type) {
+	case *appsv1.Deployment:
+		status := obj.Status
+		if status.ObservedGeneration == 0 || obj.Spec.Paused || rolloutStalled(obj) {
+			return ""
+		}
+		if status.ObservedGeneration < obj.Generation {
+			return "the latest spec is not observed yet"
+		}
+		replicas := ptr.Deref(obj.Spec.Replicas, 1)
+		switch {
+		case status.UpdatedReplicas < replicas:
+			return fmt.Sprintf("%d of %d replicas updated", status.UpdatedReplicas, replicas)
+		case status.Replicas > status.UpdatedReplicas:
+			return fmt.Sprintf("%d old replicas pending termination", status.Replicas-status.UpdatedReplicas)
+		}
+
+	case *appsv1.StatefulSet:
+		status := obj.Status
+		if status.ObservedGeneration == 0 || obj.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+			return ""
+		}
+		if status.ObservedGeneration < obj.Generation {
+			return "the latest spec is not observed yet"
+		}
+		replicas := ptr.Deref(obj.Spec.Replicas, 1)
+
+		// A partition holds back the pods below it, so only those above it are waited for
+		var partition int32
+		if rollingUpdate := obj.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil {
+			partition = ptr.Deref(rollingUpdate.Partition, 0)
+		}
+		switch {
+		case partition > 0 && status.UpdatedReplicas < replicas-partition:
+			return fmt.Sprintf("%d of %d replicas above the partition updated", status.UpdatedReplicas, replicas-partition)
+		case partition <= 0 && status.UpdateRevision != status.CurrentRevision:
+			return fmt.Sprintf("%d of %d replicas updated", status.UpdatedReplicas, replicas)
+		}
+
+	case *appsv1.DaemonSet:
+		status := obj.Status
+		if status.ObservedGeneration == 0 || obj.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+			return ""
+		}
+		if status.ObservedGeneration < obj.Generation {
+			return "the latest spec is not observed yet"
+		}
+		if status.UpdatedNumberScheduled < status.DesiredNumberScheduled {
+			return fmt.Sprintf("%d of %d pods updated", status.UpdatedNumberScheduled, status.DesiredNumberScheduled)
+		}
+	}
+	return ""
+}
+
+// rolloutStalled reports whether the Deployment controller gave up on the rollout because it
+// exceeded its progress deadline
+func rolloutStalled(deployment *appsv1.Deployment) bool {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing {
+			return condition.Status == corev1.ConditionFalse && condition.Reason == "ProgressDeadlineExceeded"
+		}
+	}
+	return false
+}
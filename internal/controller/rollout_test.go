@@ -0,0 +1,282 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+func TestRolloutInProgress(t *testing.T) {
+	deployment := func(status appsv1.DeploymentStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(3))},
+			Status:     status,
+		}
+	}
+	statefulSet := func(strategy appsv1.StatefulSetUpdateStrategy, status appsv1.StatefulSetStatus) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(3)), UpdateStrategy: strategy},
+			Status:     status,
+		}
+	}
+	daemonSet := func(strategy appsv1.DaemonSetUpdateStrategyType, status appsv1.DaemonSetStatus) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       appsv1.DaemonSetSpec{UpdateStrategy: appsv1.DaemonSetUpdateStrategy{Type: strategy}},
+			Status:     status,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		kind   string
+		object interface{}
+		want   string
+	}{
+		{
+			name:   "stable deployment",
+			kind:   KindDeployment,
+			object: deployment(appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3}),
+		},
+		{
+			name:   "deployment without a status",
+			kind:   KindDeployment,
+			object: deployment(appsv1.DeploymentStatus{}),
+		},
+		{
+			name:   "deployment spec not observed",
+			kind:   KindDeployment,
+			object: deployment(appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3}),
+			want:   "the latest spec is not observed yet",
+		},
+		{
+			name:   "deployment replicas being updated",
+			kind:   KindDeployment,
+			object: deployment(appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 1, AvailableReplicas: 3}),
+			want:   "1 of 3 replicas updated",
+		},
+		{
+			name:   "deployment old replicas terminating",
+			kind:   KindDeployment,
+			object: deployment(appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 3, AvailableReplicas: 3}),
+			want:   "1 old replicas pending termination",
+		},
+		{
+			name: "paused deployment",
+			kind: KindDeployment,
+			object: func() *appsv1.Deployment {
+				d := deployment(appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 1, AvailableReplicas: 3})
+				d.Spec.Paused = true
+				return d
+			}(),
+		},
+		{
+			name: "deployment past its progress deadline",
+			kind: KindDeployment,
+			object: deployment(appsv1.DeploymentStatus{
+				ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 1, AvailableReplicas: 3,
+				Conditions: []appsv1.DeploymentCondition{{
+					Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+				}},
+			}),
+		},
+		{
+			name: "deployment progressing",
+			kind: KindDeployment,
+			object: deployment(appsv1.DeploymentStatus{
+				ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 1, AvailableReplicas: 3,
+				Conditions: []appsv1.DeploymentCondition{{
+					Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "ReplicaSetUpdated",
+				}},
+			}),
+			want: "1 of 3 replicas updated",
+		},
+		{
+			name:   "deployment updated replicas not available",
+			kind:   KindDeployment,
+			object: deployment(appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2}),
+		},
+		{
+			name: "stable statefulset",
+			kind: KindStatefulSet,
+			object: statefulSet(appsv1.StatefulSetUpdateStrategy{}, appsv1.StatefulSetStatus{
+				ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 3, CurrentRevision: "rev2", UpdateRevision: "rev2",
+			}),
+		},
+		{
+			name: "statefulset revision rolling",
+			kind: KindStatefulSet,
+			object: statefulSet(appsv1.StatefulSetUpdateStrategy{}, appsv1.StatefulSetStatus{
+				ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "rev1", UpdateRevision: "rev2",
+			}),
+			want: "1 of 3 replicas updated",
+		},
+		{
+			name: "statefulset held by a partition",
+			kind: KindStatefulSet,
+			object: statefulSet(appsv1.StatefulSetUpdateStrategy{
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To(int32(2))},
+			}, appsv1.StatefulSetStatus{
+				ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "rev1", UpdateRevision: "rev2",
+			}),
+		},
+		{
+			name: "statefulset pod not ready",
+			kind: KindStatefulSet,
+			object: statefulSet(appsv1.StatefulSetUpdateStrategy{}, appsv1.StatefulSetStatus{
+				ObservedGeneration: 2, ReadyReplicas: 2, UpdatedReplicas: 3, CurrentRevision: "rev2", UpdateRevision: "rev2",
+			}),
+		},
+		{
+			name: "OnDelete statefulset",
+			kind: KindStatefulSet,
+			object: statefulSet(appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}, appsv1.StatefulSetStatus{
+				ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 0, CurrentRevision: "rev1", UpdateRevision: "rev2",
+			}),
+		},
+		{
+			name: "daemonset pods being updated",
+			kind: KindDaemonSet,
+			object: daemonSet(appsv1.RollingUpdateDaemonSetStrategyType, appsv1.DaemonSetStatus{
+				ObservedGeneration: 2, DesiredNumberScheduled: 5, UpdatedNumberScheduled: 2, NumberAvailable: 5,
+			}),
+			want: "2 of 5 pods updated",
+		},
+		{
+			name: "daemonset pods not available",
+			kind: KindDaemonSet,
+			object: daemonSet(appsv1.RollingUpdateDaemonSetStrategyType, appsv1.DaemonSetStatus{
+				ObservedGeneration: 2, DesiredNumberScheduled: 5, UpdatedNumberScheduled: 5, NumberAvailable: 4,
+			}),
+		},
+		{
+			name: "OnDelete daemonset",
+			kind: KindDaemonSet,
+			object: daemonSet(appsv1.OnDeleteDaemonSetStrategyType, appsv1.DaemonSetStatus{
+				ObservedGeneration: 2, DesiredNumberScheduled: 5, UpdatedNumberScheduled: 0, NumberAvailable: 5,
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload := createTestWorkload("app")
+			workload.Kind = tt.kind
+			switch obj := tt.object.(type) {
+			case *appsv1.Deployment:
+				workload.Object = obj
+			case *appsv1.StatefulSet:
+				workload.Object = obj
+			case *appsv1.DaemonSet:
+				workload.Object = obj
+			}
+			if got := rolloutInProgress(workload); got != tt.want {
+				t.Errorf("rolloutInProgress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessWorkload_AwaitingStableRollout(t *testing.T) {
+	workload := createTestWorkload("app")
+	deployment := workload.Object.(*appsv1.Deployment)
+	deployment.Generation = 2
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 1}
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+
+	// A rollout of the user's deploy defers the change
+	appEngine := &recordingApplicationEngine{}
+	processor := createTestProcessor(appEngine, nil)
+	status, err := processor.ProcessWorkload(context.Background(), workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusAwaitingStableRollout || !strings.Contains(status.Reason, "awaiting stable rollout: 1 old replicas pending termination") {
+		t.Errorf("status = %q (%s), want %q with the rollout progress", status.Status, status.Reason, StatusAwaitingStableRollout)
+	}
+	if len(appEngine.appliedContainers) != 0 || len(status.Recommendations) != 1 {
+		t.Errorf("applied %v with %d recommendations, want the recommendation reported but not applied",
+			appEngine.appliedContainers, len(status.Recommendations))
+	}
+
+	// Once the rollout has finished the change is applied
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	status, err = processor.ProcessWorkload(context.Background(), workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusApplied || len(appEngine.appliedContainers) != 1 {
+		t.Errorf("status = %q with %v applied, want %q", status.Status, appEngine.appliedContainers, StatusApplied)
+	}
+}
+
+func TestCalculateRequeueInterval_AwaitingStableRollout(t *testing.T) {
+	reconciler := &OptimizationPolicyReconciler{}
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.ReconciliationInterval = metav1.Duration{Duration: 10 * time.Minute}
+
+	summary := &reconcileSummary{Discovered: 2, Processed: 2}
+	summary.record(&optipodv1alpha1.WorkloadStatus{Status: StatusAwaitingStableRollout}, nil)
+	if got := reconciler.calculateRequeueInterval(policy, summary); got != stableRolloutRequeueDelay {
+		t.Errorf("calculateRequeueInterval() = %v, want %v while a workload awaits its rollout", got, stableRolloutRequeueDelay)
+	}
+
+	// Shorter intervals are kept
+	policy.Spec.ReconciliationInterval = metav1.Duration{Duration: 30 * time.Second}
+	if got := reconciler.calculateRequeueInterval(policy, summary); got < 30*time.Second || got > 33*time.Second {
+		t.Errorf("calculateRequeueInterval() = %v, want the 30s interval", got)
+	}
+}
+
+func TestProcessWorkload_UrgentChangeSkipsRolloutGate(t *testing.T) {
+	workload := createTestWorkload(TestContainerName)
+	deployment := workload.Object.(*appsv1.Deployment)
+	deployment.Generation = 2
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 1}
+	deployment.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+	// A pod evicted for node memory pressure makes the memory increase urgent
+	pod := createTestPod(TestPodName)
+	pod.Status = corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", Message: testMemoryEvictionMessage}
+	fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(deployment.DeepCopy(), pod).Build()
+
+	appEngine := &recordingApplicationEngine{}
+	processor := createTestProcessor(appEngine, fakeClient)
+	status, err := processor.ProcessWorkload(context.Background(), workload, createTestPolicy(optipodv1alpha1.ModeAuto))
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusApplied || len(appEngine.appliedContainers) != 1 {
+		t.Errorf("status = %q (%s) with %v applied, want the urgent change %q during the rollout",
+			status.Status, status.Reason, appEngine.appliedContainers, StatusApplied)
+	}
+}
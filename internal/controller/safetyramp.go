@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+)
+
+// safetyRampApplies returns the number of stable applies recorded on the workload for the
+// safety ramp. A missing or malformed annotation counts as none, which starts the ramp over.
+func safetyRampApplies(annotations map[string]string) int32 {
+	applies, err := strconv.ParseInt(annotations[optipodv1alpha1.AnnotationSafetyRampApplies], 10, 32)
+	if err != nil || applies < 0 {
+		return 0
+	}
+	return int32(applies)
+}
+
+// withRampedSafetyFactor returns the policy with the safety factor of a workload that has had
+// applies stable applies, when the policy has a safety ramp. The policy itself is never modified.
+func withRampedSafetyFactor(policy *optipodv1alpha1.OptimizationPolicy, applies int32) *optipodv1alpha1.OptimizationPolicy {
+	ramp := policy.Spec.MetricsConfig.SafetyRamp
+	if ramp == nil {
+		return policy
+	}
+	override := policy.DeepCopy()
+	safetyFactor := ramp.SafetyFactor(policy.Spec.MetricsConfig.GetSafetyFactor(), applies)
+	override.Spec.MetricsConfig.SafetyFactor = &safetyFactor
+	return override
+}
+
+// recordSafetyRampApply counts an apply toward the safety ramp when the workload's stability
+// score reaches the ramp's minimum, and returns the number of stable applies. Once the ramp has
+// reached the target safety factor applies are no longer counted.
+func (wp *WorkloadProcessor) recordSafetyRampApply(
+	ctx context.Context,
+	workload *discovery.Workload,
+	ramp *optipodv1alpha1.SafetyRamp,
+	applies, stabilityScore int32,
+) (int32, error) {
+	steps, minStabilityScore := ramp.Steps()
+	if applies >= steps || stabilityScore < minStabilityScore {
+		return applies, nil
+	}
+
+	// The apply changed the workload, so the count is patched without an optimistic lock
+	applies++
+	if err := wp.setWorkloadAnnotation(ctx, workload, optipodv1alpha1.AnnotationSafetyRampApplies,
+		strconv.Itoa(int(applies)), false); err != nil {
+		return applies - 1, fmt.Errorf("failed to record safety ramp apply: %w", err)
+	}
+	return applies, nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+func TestProcessWorkload_SafetyRampNarrowsAcrossApplies(t *testing.T) {
+	tests := []struct {
+		name              string
+		minStabilityScore int32
+		wantCPU           []string
+		wantPhases        []string
+		wantApplies       string
+	}{
+		{
+			// The test metrics have a CPU P90 of 200m, sized with safety factors 1.8, 1.6, 1.4 and 1.2
+			name:        "stable applies narrow the margin",
+			wantCPU:     []string{"360m", "320m", "280m", "240m", "240m"},
+			wantPhases:  []string{"Tightening", "Tightening", "Target", "Target", "Target"},
+			wantApplies: "3",
+		},
+		{
+			name:              "unstable applies keep the initial margin",
+			minStabilityScore: 100,
+			wantCPU:           []string{"360m", "360m", "360m"},
+			wantPhases:        []string{"Initial", "Initial", "Initial"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			workload := createTestWorkload(TestContainerName)
+			deployment := workload.Object.(*appsv1.Deployment)
+			pod := createTestPod(TestPodName)
+			fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(deployment, pod).Build()
+
+			processor := createTestProcessor(&recordingApplicationEngine{}, fakeClient)
+			policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+			policy.Spec.MetricsConfig.SafetyRamp = &optipodv1alpha1.SafetyRamp{
+				InitialSafetyFactor: 1.8,
+				MinStabilityScore:   ptr.To(tt.minStabilityScore),
+			}
+
+			for i, wantCPU := range tt.wantCPU {
+				status, err := processor.ProcessWorkload(ctx, workload, policy)
+				if err != nil {
+					t.Fatalf("apply %d: ProcessWorkload() error = %v", i+1, err)
+				}
+				if status.Status != StatusApplied || len(status.Recommendations) != 1 {
+					t.Fatalf("apply %d: status = %q (%s), want Applied", i+1, status.Status, status.Reason)
+				}
+				if got := status.Recommendations[0].CPU; got.Cmp(resource.MustParse(wantCPU)) != 0 {
+					t.Errorf("apply %d: CPU = %s, want %s", i+1, got.String(), wantCPU)
+				}
+				if status.SafetyRampPhase != tt.wantPhases[i] {
+					t.Errorf("apply %d: phase = %q, want %q", i+1, status.SafetyRampPhase, tt.wantPhases[i])
+				}
+			}
+
+			stored := &appsv1.Deployment{}
+			if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), stored); err != nil {
+				t.Fatalf("failed to get deployment: %v", err)
+			}
+			if got := stored.Annotations[optipodv1alpha1.AnnotationSafetyRampApplies]; got != tt.wantApplies {
+				t.Errorf("%s = %q, want %q", optipodv1alpha1.AnnotationSafetyRampApplies, got, tt.wantApplies)
+			}
+		})
+	}
+}
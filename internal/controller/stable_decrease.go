@@ -0,0 +1,255 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// decreaseKey identifies a container resource in the decrease observations annotation
+func decreaseKey(container string, resourceName corev1.ResourceName) string {
+	return container + "." + string(resourceName)
+}
+
+// isDecrease reports whether the recommendation is below the container's current request of the resource
+func isDecrease(recommended *resource.Quantity, requests corev1.ResourceList, resourceName corev1.ResourceName) bool {
+	current, ok := requests[resourceName]
+	return recommended != nil && ok && !current.IsZero() && recommended.Cmp(current) < 0
+}
+
+// proposedDecreases returns the keys of the container resources whose recommendation is below
+// the current request, for the containers applied in Auto mode
+func proposedDecreases(
+	recommendations []optipodv1alpha1.ContainerRecommendation,
+	autoContainers map[string]bool,
+	effectiveResources map[string]corev1.ResourceRequirements,
+) []string {
+	var decreases []string
+	for _, rec := range recommendations {
+		if !autoContainers[rec.Container] {
+			continue
+		}
+		requests := effectiveResources[rec.Container].Requests
+		if isDecrease(rec.CPU, requests, corev1.ResourceCPU) {
+			decreases = append(decreases, decreaseKey(rec.Container, corev1.ResourceCPU))
+		}
+		if isDecrease(rec.Memory, requests, corev1.ResourceMemory) {
+			decreases = append(decreases, decreaseKey(rec.Container, corev1.ResourceMemory))
+		}
+	}
+	return decreases
+}
+
+// parseDecreaseObservations parses the decrease observations annotation. Malformed entries are
+// ignored, which starts their count over.
+func parseDecreaseObservations(value string) map[string]int32 {
+	counts := make(map[string]int32)
+	for _, entry := range strings.Split(value, ",") {
+		key, count, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(count, 10, 32)
+		if err != nil || n < 1 {
+			continue
+		}
+		counts[key] = int32(n)
+	}
+	return counts
+}
+
+// formatDecreaseObservations formats the counts as the decrease observations annotation, sorted by key
+func formatDecreaseObservations(counts map[string]int32) string {
+	entries := make([]string, 0, len(counts))
+	for key, count := range counts {
+		entries = append(entries, fmt.Sprintf("%s=%d", key, count))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// heldDecreases counts the consecutive reconciles each container resource was proposed a
+// decrease and returns the counts of the decreases that are held back, because they have not
+// been proposed in updateStrategy.stableDecreaseObservations reconciles yet. It returns nil when
+// the policy applies decreases at once.
+func (wp *WorkloadProcessor) heldDecreases(
+	ctx context.Context,
+	workload *discovery.Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	recommendations []optipodv1alpha1.ContainerRecommendation,
+	autoContainers map[string]bool,
+	effectiveResources map[string]corev1.ResourceRequirements,
+) (map[string]int32, error) {
+	required := policy.Spec.UpdateStrategy.StableDecreaseObservations
+	if required == nil || *required <= 1 {
+		return nil, nil
+	}
+
+	previous := parseDecreaseObservations(workload.Object.GetAnnotations()[optipodv1alpha1.AnnotationDecreaseObservations])
+	counts := make(map[string]int32)
+	for _, key := range proposedDecreases(recommendations, autoContainers, effectiveResources) {
+		counts[key] = previous[key] + 1
+	}
+	if err := wp.recordDecreaseObservations(ctx, workload, formatDecreaseObservations(counts)); err != nil {
+		return nil, err
+	}
+
+	held := make(map[string]int32)
+	for key, count := range counts {
+		if count < *required {
+			held[key] = count
+		}
+	}
+	return held, nil
+}
+
+// recordDecreaseObservations stores the decrease observations annotation on the workload,
+// removing it when no decrease is being observed
+func (wp *WorkloadProcessor) recordDecreaseObservations(ctx context.Context, workload *discovery.Workload, value string) error {
+	// The patch is rejected if the workload changed since it was read, so no count is lost
+	if err := wp.setWorkloadAnnotation(ctx, workload, optipodv1alpha1.AnnotationDecreaseObservations, value, true); err != nil {
+		return fmt.Errorf("failed to record decrease observations: %w", err)
+	}
+	return nil
+}
+
+// setWorkloadAnnotation sets an annotation on the workload, or removes it when value is empty.
+// With optimisticLock the patch is rejected if the workload changed since it was read.
+func (wp *WorkloadProcessor) setWorkloadAnnotation(
+	ctx context.Context,
+	workload *discovery.Workload,
+	key, value string,
+	optimisticLock bool,
+) error {
+	return wp.setWorkloadAnnotations(ctx, workload, map[string]string{key: value}, optimisticLock)
+}
+
+// setWorkloadAnnotations sets annotations on the workload in a single patch, removing those
+// with an empty value. With optimisticLock the patch is rejected if the workload changed since
+// it was read.
+func (wp *WorkloadProcessor) setWorkloadAnnotations(
+	ctx context.Context,
+	workload *discovery.Workload,
+	values map[string]string,
+	optimisticLock bool,
+) error {
+	if wp.client == nil || wp.reportOnly {
+		return nil
+	}
+
+	obj, err := wp.getWorkloadObject(workload)
+	if err != nil {
+		return fmt.Errorf("failed to get workload object: %w", err)
+	}
+	annotations := obj.GetAnnotations()
+	changed := false
+	for key, value := range values {
+		if annotations[key] != value {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	var opts []client.MergeFromOption
+	if optimisticLock {
+		opts = append(opts, client.MergeFromWithOptimisticLock{})
+	}
+	patchBase := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), opts...)
+	updated := make(map[string]string, len(annotations)+len(values))
+	for k, annotation := range annotations {
+		updated[k] = annotation
+	}
+	for key, value := range values {
+		if value == "" {
+			delete(updated, key)
+		} else {
+			updated[key] = value
+		}
+	}
+	obj.SetAnnotations(updated)
+
+	if err := wp.leaderTracker.checkLeader(); err != nil {
+		return err
+	}
+	return wp.client.Patch(ctx, obj, patchBase)
+}
+
+// hasUnheldChange reports whether any container applied in Auto mode has a recommendation that
+// differs from its current request and is not a held decrease
+func hasUnheldChange(
+	recommendations []optipodv1alpha1.ContainerRecommendation,
+	autoContainers map[string]bool,
+	effectiveResources map[string]corev1.ResourceRequirements,
+	held map[string]int32,
+) bool {
+	for _, rec := range recommendations {
+		if !autoContainers[rec.Container] {
+			continue
+		}
+		requests := effectiveResources[rec.Container].Requests
+		for resourceName, recommended := range map[corev1.ResourceName]*resource.Quantity{
+			corev1.ResourceCPU:    rec.CPU,
+			corev1.ResourceMemory: rec.Memory,
+		} {
+			if recommended == nil {
+				continue
+			}
+			if _, ok := held[decreaseKey(rec.Container, resourceName)]; ok {
+				continue
+			}
+			if current, ok := requests[resourceName]; !ok || recommended.Cmp(current) != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// holdDecreases keeps the container's held decreases at the current request
+func holdDecreases(appRec *recommendation.Recommendation, container string, current corev1.ResourceRequirements, held map[string]int32) {
+	if _, ok := held[decreaseKey(container, corev1.ResourceCPU)]; ok {
+		appRec.CPU = current.Requests.Cpu().DeepCopy()
+	}
+	if _, ok := held[decreaseKey(container, corev1.ResourceMemory)]; ok {
+		appRec.Memory = current.Requests.Memory().DeepCopy()
+	}
+}
+
+// describeHeldDecreases describes the held decreases and how many observations they have, e.g.
+// "app.cpu 1 of 3"
+func describeHeldDecreases(held map[string]int32, required int32) string {
+	parts := make([]string, 0, len(held))
+	for key, count := range held {
+		parts = append(parts, fmt.Sprintf("%s %d of %d", key, count, required))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/discovery"
+)
+
+// testMemoryRecommendation is the memory recommendation for createTestMetricsProvider, so a
+// container requesting it proposes no memory change
+const testMemoryRecommendation = "322122547"
+
+// changeRecordingApplicationEngine records the changes it applied
+type changeRecordingApplicationEngine struct {
+	recordingApplicationEngine
+	changes []application.ContainerChange
+}
+
+func (m *changeRecordingApplicationEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+	m.changes = append(m.changes, changes...)
+	return m.recordingApplicationEngine.Apply(ctx, workload, changes, policy)
+}
+
+// newStableDecreaseTestProcessor returns a processor backed by a fake client holding the test
+// workload, and the workload as read from the client
+func newStableDecreaseTestProcessor(t *testing.T, appEngine ApplicationEngine) (*WorkloadProcessor, *discovery.Workload) {
+	t.Helper()
+	workload := createTestWorkload(TestContainerName)
+	deployment := workload.Object.(*appsv1.Deployment)
+	pod := createTestPod(TestPodName)
+	fakeClient := fake.NewClientBuilder().WithScheme(createTestScheme()).WithObjects(deployment, pod).Build()
+
+	stored := &appsv1.Deployment{}
+	if err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(deployment), stored); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	workload.Object = stored
+	return createTestProcessor(appEngine, fakeClient), workload
+}
+
+// setTestRequests sets the test container's requests, as discovery would see them
+func setTestRequests(workload *discovery.Workload, cpu, memory string) {
+	workload.Object.(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func TestProcessWorkload_StableDecreaseObservations(t *testing.T) {
+	ctx := context.Background()
+	appEngine := &changeRecordingApplicationEngine{}
+	processor, workload := newStableDecreaseTestProcessor(t, appEngine)
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.UpdateStrategy.StableDecreaseObservations = int32Ptr(3)
+
+	// The recommendation is 240m: a 1 CPU request proposes a decrease, a 100m request an increase
+	steps := []struct {
+		name            string
+		cpuRequest      string
+		wantStatus      string
+		wantApplied     bool
+		wantObservation string
+	}{
+		{name: "first decrease is held", cpuRequest: "1", wantStatus: StatusAwaitingStableDecrease, wantObservation: "test-container.cpu=1"},
+		{name: "an increase applies at once", cpuRequest: "100m", wantStatus: StatusApplied, wantApplied: true},
+		{name: "the decrease starts over", cpuRequest: "1", wantStatus: StatusAwaitingStableDecrease, wantObservation: "test-container.cpu=1"},
+		{name: "second observation is held", cpuRequest: "1", wantStatus: StatusAwaitingStableDecrease, wantObservation: "test-container.cpu=2"},
+		{name: "third observation applies", cpuRequest: "1", wantStatus: StatusApplied, wantApplied: true, wantObservation: "test-container.cpu=3"},
+	}
+
+	for _, step := range steps {
+		setTestRequests(workload, step.cpuRequest, testMemoryRecommendation)
+		appEngine.changes = nil
+
+		status, err := processor.ProcessWorkload(ctx, workload, policy)
+		if err != nil {
+			t.Fatalf("%s: ProcessWorkload() error = %v", step.name, err)
+		}
+		if status.Status != step.wantStatus {
+			t.Errorf("%s: status = %s (%s), want %s", step.name, status.Status, status.Reason, step.wantStatus)
+		}
+		if applied := len(appEngine.changes) > 0; applied != step.wantApplied {
+			t.Errorf("%s: applied %v, want applied = %v", step.name, appEngine.changes, step.wantApplied)
+		} else if applied {
+			if cpu := appEngine.changes[0].Recommendation.CPU; cpu.Cmp(resource.MustParse("240m")) != 0 {
+				t.Errorf("%s: applied CPU %s, want 240m", step.name, cpu.String())
+			}
+		}
+		if got := workload.Object.GetAnnotations()[optipodv1alpha1.AnnotationDecreaseObservations]; got != step.wantObservation {
+			t.Errorf("%s: %s = %q, want %q", step.name, optipodv1alpha1.AnnotationDecreaseObservations, got, step.wantObservation)
+		}
+	}
+}
+
+func TestProcessWorkload_StableDecreaseHeldWithIncrease(t *testing.T) {
+	appEngine := &changeRecordingApplicationEngine{}
+	processor, workload := newStableDecreaseTestProcessor(t, appEngine)
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.UpdateStrategy.StableDecreaseObservations = int32Ptr(2)
+
+	// CPU is lowered, memory raised
+	setTestRequests(workload, "1", "64Mi")
+	status, err := processor.ProcessWorkload(context.Background(), workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusApplied || !strings.Contains(status.Reason, "test-container.cpu 1 of 2") {
+		t.Errorf("status = %s (%s), want Applied with the CPU decrease held", status.Status, status.Reason)
+	}
+	if len(appEngine.changes) != 1 {
+		t.Fatalf("applied %d changes, want 1", len(appEngine.changes))
+	}
+	applied := appEngine.changes[0].Recommendation
+	if applied.CPU.Cmp(resource.MustParse("1")) != 0 {
+		t.Errorf("applied CPU %s, want the current request 1 kept", applied.CPU.String())
+	}
+	if applied.Memory.Cmp(resource.MustParse(testMemoryRecommendation)) != 0 {
+		t.Errorf("applied memory %s, want the increase to %s", applied.Memory.String(), testMemoryRecommendation)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+// staleMetricsReason prefixes the status reason of a workload whose recommendations are not
+// applied because its metrics are older than metricsConfig.maxMetricsAge
+const staleMetricsReason = "StaleMetrics"
+
+// metricsStaleness returns why a container's metrics are stale, or an empty string when they are
+// fresh. Metrics are never stale without a maxMetricsAge or when the provider does not report the
+// time of its newest sample.
+func metricsStaleness(containerName string, containerMetrics *metrics.ContainerMetrics, maxAge *metav1.Duration, now time.Time) string {
+	if maxAge == nil || containerMetrics == nil || containerMetrics.NewestSample.IsZero() {
+		return ""
+	}
+	age := now.Sub(containerMetrics.NewestSample)
+	if age <= maxAge.Duration {
+		return ""
+	}
+	return fmt.Sprintf("newest metrics sample of container %s is %s old, above maxMetricsAge %s",
+		containerName, age.Round(time.Second), maxAge.Duration)
+}
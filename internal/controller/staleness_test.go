@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+func TestProcessWorkload_StaleMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAge      *metav1.Duration
+		sampleAge   time.Duration
+		noTimestamp bool
+		wantStatus  string
+	}{
+		{
+			name:       "fresh metrics are applied",
+			maxAge:     &metav1.Duration{Duration: 30 * time.Minute},
+			sampleAge:  5 * time.Minute,
+			wantStatus: StatusApplied,
+		},
+		{
+			name:       "stale metrics are only recommended",
+			maxAge:     &metav1.Duration{Duration: 30 * time.Minute},
+			sampleAge:  2 * time.Hour,
+			wantStatus: StatusRecommended,
+		},
+		{
+			name:        "metrics without a sample time are never stale",
+			maxAge:      &metav1.Duration{Duration: 30 * time.Minute},
+			noTimestamp: true,
+			wantStatus:  StatusApplied,
+		},
+		{
+			name:       "metrics of any age are applied without a threshold",
+			sampleAge:  24 * time.Hour,
+			wantStatus: StatusApplied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := createTestMetricsProvider()
+			if !tt.noTimestamp {
+				provider.metricsToReturn.NewestSample = time.Now().Add(-tt.sampleAge)
+			}
+			policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+			policy.Spec.MetricsConfig.MaxMetricsAge = tt.maxAge
+
+			appEngine := &recordingApplicationEngine{}
+			processor := NewWorkloadProcessor(provider, recommendation.NewEngine(), appEngine, nil)
+
+			status, err := processor.ProcessWorkload(context.Background(), createTestWorkload(TestContainerName), policy)
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			if status.Status != tt.wantStatus {
+				t.Errorf("status = %q (%s), want %q", status.Status, status.Reason, tt.wantStatus)
+			}
+			if len(status.Recommendations) != 1 {
+				t.Errorf("recommendations = %+v, want one for the container", status.Recommendations)
+			}
+
+			stale := tt.wantStatus == StatusRecommended
+			if got := strings.HasPrefix(status.Reason, staleMetricsReason); got != stale {
+				t.Errorf("reason = %q, want the StaleMetrics reason %v", status.Reason, stale)
+			}
+			if applied := len(appEngine.appliedContainers) > 0; applied == stale {
+				t.Errorf("applied containers = %v with stale metrics %v", appEngine.appliedContainers, stale)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// toleratingApplicationEngine treats the recommendations of the named containers as within the
+// apply tolerance of their current requests
+type toleratingApplicationEngine struct {
+	recordingApplicationEngine
+	withinTolerance map[string]bool
+}
+
+func (m *toleratingApplicationEngine) CanApply(ctx context.Context, workload *application.Workload, containerName string, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyDecision, error) {
+	if m.withinTolerance[containerName] {
+		return &application.ApplyDecision{
+			CanApply: false,
+			Method:   application.Skip,
+			Reason:   "Recommendation within tolerance",
+			Cause:    application.ErrWithinTolerance,
+		}, nil
+	}
+	return m.recordingApplicationEngine.CanApply(ctx, workload, containerName, rec, policy)
+}
+
+func TestProcessWorkload_ApplyTolerance(t *testing.T) {
+	tests := []struct {
+		name            string
+		withinTolerance map[string]bool
+		wantStatus      string
+		wantApplied     []string
+		wantKept        []string
+		wantReason      string
+	}{
+		{
+			name:            "a workload within tolerance is not patched",
+			withinTolerance: map[string]bool{"app": true, "sidecar": true},
+			wantStatus:      StatusRecommended,
+			wantReason:      "Recommendations computed, not applied (within tolerance of the current requests: app, sidecar)",
+		},
+		{
+			name:            "containers beyond tolerance are applied",
+			withinTolerance: map[string]bool{"sidecar": true},
+			wantStatus:      StatusApplied,
+			wantApplied:     []string{"app"},
+			wantKept:        []string{"sidecar"},
+			wantReason:      "; container(s) sidecar within tolerance of the current requests, not changed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appEngine := &toleratingApplicationEngine{withinTolerance: tt.withinTolerance}
+			processor := createTestProcessor(appEngine, nil)
+
+			status, err := processor.ProcessWorkload(context.Background(), createTestWorkload("app", "sidecar"),
+				createTestPolicy(optipodv1alpha1.ModeAuto))
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			if status.Status != tt.wantStatus {
+				t.Errorf("status = %q (%s), want %q", status.Status, status.Reason, tt.wantStatus)
+			}
+			if !strings.HasSuffix(status.Reason, tt.wantReason) {
+				t.Errorf("reason = %q, want it to end with %q", status.Reason, tt.wantReason)
+			}
+			if !slices.Equal(appEngine.appliedContainers, tt.wantApplied) {
+				t.Errorf("applied = %v, want %v", appEngine.appliedContainers, tt.wantApplied)
+			}
+			if !slices.Equal(appEngine.keptContainers, tt.wantKept) {
+				t.Errorf("kept unchanged = %v, want %v", appEngine.keptContainers, tt.wantKept)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	optipoddiscovery "github.com/optipod/optipod/internal/discovery"
+)
+
+// vpaGVR is the resource of VerticalPodAutoscalers
+var vpaGVR = schema.GroupVersionResource{Group: "autoscaling.k8s.io", Version: "v1", Resource: "verticalpodautoscalers"}
+
+// vpaRecheckInterval is how long the presence of the VerticalPodAutoscaler CRD is cached, so
+// installing the VPA after the operator started is picked up without a restart
+const vpaRecheckInterval = 10 * time.Minute
+
+// vpaRecommenderName is the recommender exported VPAs name in spec.recommenders, so the VPA
+// recommender leaves their status to OptiPod
+const vpaRecommenderName = "optipod"
+
+// managedByLabel and managedByOptiPod mark the objects OptiPod created and owns
+const (
+	managedByLabel   = "app.kubernetes.io/managed-by"
+	managedByOptiPod = "optipod"
+)
+
+// VPAExporter writes recommendations to the status of a VerticalPodAutoscaler per workload, so
+// tooling and dashboards built for the VPA can read them. The exported VPAs only carry
+// recommendations: their update mode is Off, so the VPA never changes the workload's pods. It
+// only writes VPAs when the VPA CRD is installed in the cluster, and not in global dry-run mode.
+// Exported VPAs are owned by their workload and deleted with it, or by Prune once their policy no
+// longer manages the workload.
+type VPAExporter struct {
+	dynamicClient   dynamic.Interface
+	discoveryClient discovery.DiscoveryInterface
+
+	mu        sync.Mutex
+	dryRun    bool
+	installed bool
+	checkedAt time.Time
+}
+
+// NewVPAExporter creates an exporter using the dynamic client for VerticalPodAutoscalers and the
+// discovery client to detect the VPA CRD
+func NewVPAExporter(dynamicClient dynamic.Interface, discoveryClient discovery.DiscoveryInterface, dryRun bool) *VPAExporter {
+	return &VPAExporter{
+		dynamicClient:   dynamicClient,
+		discoveryClient: discoveryClient,
+		dryRun:          dryRun,
+	}
+}
+
+// SetDryRun enables or disables global dry-run mode at runtime
+func (e *VPAExporter) SetDryRun(dryRun bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.dryRun = dryRun
+}
+
+// isDryRun returns true if global dry-run mode is enabled
+func (e *VPAExporter) isDryRun() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.dryRun
+}
+
+// Export writes the workload's recommendations to the status of its exported VPA, creating the
+// VPA if needed. policyRef is the namespace/name of the policy managing the workload. It does
+// nothing in global dry-run mode or when the VPA CRD is not installed.
+func (e *VPAExporter) Export(
+	ctx context.Context,
+	workload *optipoddiscovery.Workload,
+	policyRef string,
+	recommendations []optipodv1alpha1.ContainerRecommendation,
+) error {
+	if e.isDryRun() {
+		return nil
+	}
+	installed, err := e.vpaInstalled()
+	if err != nil || !installed {
+		return err
+	}
+
+	vpas := e.dynamicClient.Resource(vpaGVR).Namespace(workload.Namespace)
+	name := exportedVPAName(workload)
+	vpa, err := vpas.Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		vpa, err = vpas.Create(ctx, newExportedVPA(workload, name, policyRef), metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create VerticalPodAutoscaler %s/%s: %w", workload.Namespace, name, err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to get VerticalPodAutoscaler %s/%s: %w", workload.Namespace, name, err)
+	case vpa.GetLabels()[managedByLabel] != managedByOptiPod:
+		return fmt.Errorf("VerticalPodAutoscaler %s/%s exists and is not managed by OptiPod", workload.Namespace, name)
+	case vpa.GetAnnotations()[optipodv1alpha1.AnnotationManagedByPolicy] != policyRef:
+		// The workload moved to another policy since the VPA was created
+		annotations := vpa.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[optipodv1alpha1.AnnotationManagedByPolicy] = policyRef
+		vpa.SetAnnotations(annotations)
+		if vpa, err = vpas.Update(ctx, vpa, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update VerticalPodAutoscaler %s/%s: %w", workload.Namespace, name, err)
+		}
+	}
+
+	if err := unstructured.SetNestedField(vpa.Object, vpaStatus(recommendations), "status"); err != nil {
+		return fmt.Errorf("failed to set VerticalPodAutoscaler status: %w", err)
+	}
+	if _, err := vpas.UpdateStatus(ctx, vpa, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update VerticalPodAutoscaler %s/%s status: %w", workload.Namespace, name, err)
+	}
+	return nil
+}
+
+// Prune deletes the VPAs exported for the policy with namespace/name policyRef whose workload is
+// not in keep, which holds the workloadKey of every workload the policy still manages. A nil keep
+// deletes all of the policy's VPAs, e.g. once it is deleted. It does nothing when the VPA CRD is
+// not installed.
+func (e *VPAExporter) Prune(ctx context.Context, policyRef string, keep map[string]bool) error {
+	installed, err := e.vpaInstalled()
+	if err != nil || !installed {
+		return err
+	}
+
+	list, err := e.dynamicClient.Resource(vpaGVR).List(ctx, metav1.ListOptions{
+		LabelSelector: managedByLabel + "=" + managedByOptiPod,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list exported VerticalPodAutoscalers: %w", err)
+	}
+
+	var errs []error
+	for _, vpa := range list.Items {
+		if vpa.GetAnnotations()[optipodv1alpha1.AnnotationManagedByPolicy] != policyRef {
+			continue
+		}
+		kind, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "kind")
+		target, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "name")
+		if keep[workloadKey(kind, vpa.GetNamespace(), target)] {
+			continue
+		}
+		logf.FromContext(ctx).Info("Policy no longer manages workload, deleting its exported VerticalPodAutoscaler",
+			"vpa", fmt.Sprintf("%s/%s", vpa.GetNamespace(), vpa.GetName()),
+			"policy", policyRef)
+		err := e.dynamicClient.Resource(vpaGVR).Namespace(vpa.GetNamespace()).Delete(ctx, vpa.GetName(), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete VerticalPodAutoscaler %s/%s: %w", vpa.GetNamespace(), vpa.GetName(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// vpaInstalled reports whether the VerticalPodAutoscaler CRD is served, caching the answer for vpaRecheckInterval
+func (e *VPAExporter) vpaInstalled() (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.checkedAt.IsZero() && time.Since(e.checkedAt) < vpaRecheckInterval {
+		return e.installed, nil
+	}
+
+	resources, err := e.discoveryClient.ServerResourcesForGroupVersion(vpaGVR.GroupVersion().String())
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to detect the VerticalPodAutoscaler CRD: %w", err)
+	}
+
+	e.installed = false
+	if err == nil {
+		for _, resource := range resources.APIResources {
+			if resource.Name == vpaGVR.Resource {
+				e.installed = true
+				break
+			}
+		}
+	}
+	e.checkedAt = time.Now()
+	return e.installed, nil
+}
+
+// exportedVPAName returns the name of the VPA the workload's recommendations are exported to. The
+// kind is part of the name, as workloads of different kinds may share a name.
+func exportedVPAName(workload *optipoddiscovery.Workload) string {
+	return fmt.Sprintf("optipod-%s-%s", strings.ToLower(workload.Kind), workload.Name)
+}
+
+// newExportedVPA returns a recommendation-only VPA targeting the workload. It is owned by the
+// workload, so it is garbage collected with it.
+func newExportedVPA(workload *optipoddiscovery.Workload, name, policyRef string) *unstructured.Unstructured {
+	metadata := map[string]interface{}{
+		"name":      name,
+		"namespace": workload.Namespace,
+		"labels":    map[string]interface{}{managedByLabel: managedByOptiPod},
+		"annotations": map[string]interface{}{
+			optipodv1alpha1.AnnotationManagedByPolicy: policyRef,
+		},
+	}
+	if workload.Object != nil && workload.Object.GetUID() != "" {
+		metadata["ownerReferences"] = []interface{}{map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       workload.Kind,
+			"name":       workload.Name,
+			"uid":        string(workload.Object.GetUID()),
+		}}
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": vpaGVR.GroupVersion().String(),
+		"kind":       "VerticalPodAutoscaler",
+		"metadata":   metadata,
+		"spec": map[string]interface{}{
+			"targetRef": map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       workload.Kind,
+				"name":       workload.Name,
+			},
+			"updatePolicy": map[string]interface{}{"updateMode": "Off"},
+			"recommenders": []interface{}{map[string]interface{}{"name": vpaRecommenderName}},
+		},
+	}}
+}
+
+// vpaStatus returns the VPA status holding the recommendations. OptiPod recommends a single
+// request per resource, so the target, bounds and uncapped target are all set to it.
+func vpaStatus(recommendations []optipodv1alpha1.ContainerRecommendation) map[string]interface{} {
+	containerRecommendations := make([]interface{}, 0, len(recommendations))
+	for _, rec := range recommendations {
+		resources := map[string]interface{}{}
+		if rec.CPU != nil {
+			resources["cpu"] = rec.CPU.String()
+		}
+		if rec.Memory != nil {
+			resources["memory"] = rec.Memory.String()
+		}
+		if len(resources) == 0 {
+			continue
+		}
+		containerRecommendations = append(containerRecommendations, map[string]interface{}{
+			"containerName":  rec.Container,
+			"target":         resources,
+			"lowerBound":     resources,
+			"upperBound":     resources,
+			"uncappedTarget": resources,
+		})
+	}
+
+	return map[string]interface{}{
+		"recommendation": map[string]interface{}{"containerRecommendations": containerRecommendations},
+		"conditions": []interface{}{map[string]interface{}{
+			"type":   "RecommendationProvided",
+			"status": "True",
+			"reason": "OptiPodRecommendation",
+		}},
+	}
+}
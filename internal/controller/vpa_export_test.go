@@ -0,0 +1,207 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// newTestVPAExporter returns an exporter over the given VPAs, with the VPA CRD served only when
+// installed is true
+func newTestVPAExporter(installed bool, vpas ...runtime.Object) (*VPAExporter, dynamic.Interface) {
+	dynamicClient, discoveryClient := createTestCRDClients(vpaGVR, "VerticalPodAutoscaler", installed, vpas...)
+	return NewVPAExporter(dynamicClient, discoveryClient, false), dynamicClient
+}
+
+// exportedVPANames lists the names of the VPAs in the test namespace
+func exportedVPANames(t *testing.T, dynamicClient dynamic.Interface) []string {
+	t.Helper()
+	list, err := dynamicClient.Resource(vpaGVR).Namespace(TestNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list VPAs: %v", err)
+	}
+	var names []string
+	for _, vpa := range list.Items {
+		names = append(names, vpa.GetName())
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestProcessWorkload_ExportsVPA(t *testing.T) {
+	ctx := context.Background()
+	vpaName := "optipod-deployment-" + TestWorkloadName
+
+	t.Run("writes the recommendations to a recommendation-only VPA", func(t *testing.T) {
+		exporter, dynamicClient := newTestVPAExporter(true)
+		processor := createTestProcessor(&recordingApplicationEngine{}, nil)
+		processor.SetVPAExporter(exporter)
+
+		// A second pass updates the VPA the first one created
+		for range 2 {
+			if _, err := processor.ProcessWorkload(ctx, createTestWorkload(TestContainerName),
+				createTestPolicy(optipodv1alpha1.ModeRecommend)); err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+		}
+
+		vpa, err := dynamicClient.Resource(vpaGVR).Namespace(TestNamespace).Get(ctx, vpaName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get exported VPA: %v", err)
+		}
+		if mode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode"); mode != "Off" {
+			t.Errorf("updateMode = %q, want Off", mode)
+		}
+		if target, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "name"); target != TestWorkloadName {
+			t.Errorf("targetRef.name = %q, want %q", target, TestWorkloadName)
+		}
+		recommenders, _, _ := unstructured.NestedSlice(vpa.Object, "spec", "recommenders")
+		if len(recommenders) != 1 || recommenders[0].(map[string]interface{})["name"] != vpaRecommenderName {
+			t.Errorf("recommenders = %v, want only %s", recommenders, vpaRecommenderName)
+		}
+
+		containers, _, _ := unstructured.NestedSlice(vpa.Object, "status", "recommendation", "containerRecommendations")
+		if len(containers) != 1 {
+			t.Fatalf("containerRecommendations = %v, want one container", containers)
+		}
+		container := containers[0].(map[string]interface{})
+		if container["containerName"] != TestContainerName {
+			t.Errorf("containerName = %v, want %s", container["containerName"], TestContainerName)
+		}
+		for _, field := range []string{"target", "lowerBound", "upperBound", "uncappedTarget"} {
+			if cpu, _, _ := unstructured.NestedString(container, field, "cpu"); cpu != "240m" {
+				t.Errorf("%s cpu = %q, want 240m", field, cpu)
+			}
+		}
+	})
+
+	nothingExported := []struct {
+		name         string
+		installed    bool
+		globalDryRun bool
+		policyDryRun bool
+	}{
+		{name: "nothing is exported without the VPA CRD", installed: false},
+		{name: "nothing is exported in global dry-run", installed: true, globalDryRun: true},
+		{name: "nothing is exported for a policy in dry-run", installed: true, policyDryRun: true},
+	}
+	for _, tt := range nothingExported {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, dynamicClient := newTestVPAExporter(tt.installed)
+			exporter.SetDryRun(tt.globalDryRun)
+			processor := createTestProcessor(&recordingApplicationEngine{}, nil)
+			processor.SetVPAExporter(exporter)
+
+			policy := createTestPolicy(optipodv1alpha1.ModeRecommend)
+			policy.Spec.DryRun = tt.policyDryRun
+			if _, err := processor.ProcessWorkload(ctx, createTestWorkload(TestContainerName), policy); err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			if names := exportedVPANames(t, dynamicClient); len(names) != 0 {
+				t.Errorf("exported VPAs %v, want none", names)
+			}
+		})
+	}
+
+	t.Run("a VPA not managed by OptiPod is left alone", func(t *testing.T) {
+		foreign := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": vpaGVR.GroupVersion().String(),
+			"kind":       "VerticalPodAutoscaler",
+			"metadata":   map[string]interface{}{"name": vpaName, "namespace": TestNamespace},
+		}}
+		exporter, dynamicClient := newTestVPAExporter(true, foreign)
+
+		err := exporter.Export(ctx, createTestWorkload(TestContainerName), TestNamespace+"/test-policy", nil)
+		if err == nil {
+			t.Fatal("Export() succeeded over a VPA not managed by OptiPod")
+		}
+		vpa, err := dynamicClient.Resource(vpaGVR).Namespace(TestNamespace).Get(ctx, vpaName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get VPA: %v", err)
+		}
+		if _, found, _ := unstructured.NestedMap(vpa.Object, "status"); found {
+			t.Errorf("status = %v, want the VPA unchanged", vpa.Object["status"])
+		}
+	})
+}
+
+func TestVPAExporter_Prune(t *testing.T) {
+	ctx := context.Background()
+	policyRef := TestNamespace + "/test-policy"
+
+	// newExporter returns an exporter with VPAs exported for the web and api workloads of the
+	// policy and for the worker workload of another policy
+	newExporter := func(t *testing.T) (*VPAExporter, dynamic.Interface) {
+		exporter, dynamicClient := newTestVPAExporter(true)
+		for name, ref := range map[string]string{"web": policyRef, "api": policyRef, "worker": TestNamespace + "/other"} {
+			workload := createTestWorkload(TestContainerName)
+			workload.Name = name
+			if err := exporter.Export(ctx, workload, ref, nil); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+		}
+		return exporter, dynamicClient
+	}
+
+	t.Run("deletes the VPAs of workloads the policy no longer manages", func(t *testing.T) {
+		exporter, dynamicClient := newExporter(t)
+		keep := map[string]bool{workloadKey(KindDeployment, TestNamespace, "web"): true}
+		if err := exporter.Prune(ctx, policyRef, keep); err != nil {
+			t.Fatalf("Prune() error = %v", err)
+		}
+		want := []string{"optipod-deployment-web", "optipod-deployment-worker"}
+		if got := exportedVPANames(t, dynamicClient); !slices.Equal(got, want) {
+			t.Errorf("VPAs = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("deletes every VPA of a deleted policy", func(t *testing.T) {
+		exporter, dynamicClient := newExporter(t)
+		if err := exporter.Prune(ctx, policyRef, nil); err != nil {
+			t.Fatalf("Prune() error = %v", err)
+		}
+		want := []string{"optipod-deployment-worker"}
+		if got := exportedVPANames(t, dynamicClient); !slices.Equal(got, want) {
+			t.Errorf("VPAs = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("a workload moved to another policy keeps its VPA", func(t *testing.T) {
+		exporter, dynamicClient := newExporter(t)
+		workload := createTestWorkload(TestContainerName)
+		workload.Name = "api"
+		if err := exporter.Export(ctx, workload, TestNamespace+"/other", nil); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		if err := exporter.Prune(ctx, policyRef, nil); err != nil {
+			t.Fatalf("Prune() error = %v", err)
+		}
+		want := []string{"optipod-deployment-api", "optipod-deployment-worker"}
+		if got := exportedVPANames(t, dynamicClient); !slices.Equal(got, want) {
+			t.Errorf("VPAs = %v, want %v", got, want)
+		}
+	})
+}
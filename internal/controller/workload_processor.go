@@ -18,7 +18,12 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -34,16 +39,37 @@ import (
 
 	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
 	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/audit"
+	"github.com/optipod/optipod/internal/cache"
+	"github.com/optipod/optipod/internal/dashboard"
 	"github.com/optipod/optipod/internal/discovery"
 	"github.com/optipod/optipod/internal/metrics"
 	"github.com/optipod/optipod/internal/observability"
 	"github.com/optipod/optipod/internal/recommendation"
+	"github.com/optipod/optipod/internal/report"
 )
 
+// applyTimeout bounds how long the apply of a workload may keep running after the reconcile
+// context is cancelled. It is shorter than the manager's default graceful shutdown timeout (30s),
+// so an in-flight apply finishes before the process exits.
+const applyTimeout = 20 * time.Second
+
 // ApplicationEngine defines the interface for applying resource changes
 type ApplicationEngine interface {
-	CanApply(ctx context.Context, workload *application.Workload, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyDecision, error)
-	Apply(ctx context.Context, workload *application.Workload, containerName string, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error)
+	CanApply(ctx context.Context, workload *application.Workload, containerName string, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyDecision, error)
+	Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error)
+}
+
+// PartitionAdvancer is implemented by application engines that roll StatefulSet changes out
+// through the rollingUpdate partition
+type PartitionAdvancer interface {
+	AdvancePartition(ctx context.Context, workload *application.Workload, policy *optipodv1alpha1.OptimizationPolicy) (*optipodv1alpha1.PartitionedRolloutStatus, error)
+}
+
+// OnDeleteRolloutAdvancer is implemented by application engines that roll changes to OnDelete
+// workloads out by deleting their pods one at a time
+type OnDeleteRolloutAdvancer interface {
+	AdvanceOnDeleteRollout(ctx context.Context, workload *application.Workload, policy *optipodv1alpha1.OptimizationPolicy) (*optipodv1alpha1.OnDeleteRolloutStatus, error)
 }
 
 // WorkloadProcessor handles the processing of individual workloads
@@ -53,6 +79,20 @@ type WorkloadProcessor struct {
 	applicationEngine    ApplicationEngine
 	metricsProviderType  string
 	client               client.Client
+	impactCollector      *report.Collector
+	leaderTracker        *LeaderTracker
+	eventRecorder        *observability.EventRecorder
+	auditLogger          *audit.Logger
+	scaledObjectFinder   *ScaledObjectFinder
+	increaseBudget       *IncreaseBudget
+	annotationTemplates  []AnnotationTemplate
+	dashboardStore       *dashboard.Store
+	metricsRecovery      *MetricsRecoveryWatcher
+	autoReadiness        *AutoReadinessGate
+	vpaExporter          *VPAExporter
+	recommendationCache  *cache.RecommendationCache
+	restartTracker       *restartTracker
+	reportOnly           bool
 }
 
 // NewWorkloadProcessor creates a new workload processor
@@ -68,15 +108,93 @@ func NewWorkloadProcessor(
 		applicationEngine:    applicationEngine,
 		metricsProviderType:  "metrics-server", // Default, can be made configurable
 		client:               k8sClient,
+		restartTracker:       newRestartTracker(),
 	}
 }
 
+// SetImpactCollector enables recording of proposed changes for the dry-run impact report and recording rules
+func (wp *WorkloadProcessor) SetImpactCollector(collector *report.Collector) {
+	wp.impactCollector = collector
+}
+
+// SetReportOnly keeps the processor from writing annotations to workloads, so recommendations
+// can be computed with read-only access to a cluster
+func (wp *WorkloadProcessor) SetReportOnly(reportOnly bool) {
+	wp.reportOnly = reportOnly
+}
+
+// SetLeaderTracker gates annotation writes and applies on holding the leader lease
+func (wp *WorkloadProcessor) SetLeaderTracker(tracker *LeaderTracker) {
+	wp.leaderTracker = tracker
+}
+
+// SetEventRecorder enables events on workloads, e.g. for an invalid update method annotation
+func (wp *WorkloadProcessor) SetEventRecorder(recorder *observability.EventRecorder) {
+	wp.eventRecorder = recorder
+}
+
+// SetAuditLogger enables an audit record of every change applied to a container
+func (wp *WorkloadProcessor) SetAuditLogger(logger *audit.Logger) {
+	wp.auditLogger = logger
+}
+
+// SetScaledObjectFinder enables holding CPU requests of workloads a KEDA ScaledObject scales on CPU
+func (wp *WorkloadProcessor) SetScaledObjectFinder(finder *ScaledObjectFinder) {
+	wp.scaledObjectFinder = finder
+}
+
+// SetVPAExporter enables exporting recommendations to VerticalPodAutoscaler objects
+func (wp *WorkloadProcessor) SetVPAExporter(exporter *VPAExporter) {
+	wp.vpaExporter = exporter
+}
+
+// SetIncreaseBudget caps the requests applies may add to the cluster per interval across all policies
+func (wp *WorkloadProcessor) SetIncreaseBudget(budget *IncreaseBudget) {
+	wp.increaseBudget = budget
+}
+
+// SetAnnotationTemplates additionally writes recommendations under the custom annotation keys
+// of the templates
+func (wp *WorkloadProcessor) SetAnnotationTemplates(templates []AnnotationTemplate) {
+	wp.annotationTemplates = templates
+}
+
+// SetDashboardStore keeps the result of every processed workload for the dashboard API
+func (wp *WorkloadProcessor) SetDashboardStore(store *dashboard.Store) {
+	wp.dashboardStore = store
+}
+
+// SetMetricsRecoveryWatcher reconciles the policies that skipped workloads for missing metrics as
+// soon as the metrics backend recovers
+func (wp *WorkloadProcessor) SetMetricsRecoveryWatcher(watcher *MetricsRecoveryWatcher) {
+	wp.metricsRecovery = watcher
+}
+
+// SetAutoReadinessGate holds Auto mode changes back until metrics are flowing after startup
+func (wp *WorkloadProcessor) SetAutoReadinessGate(gate *AutoReadinessGate) {
+	wp.autoReadiness = gate
+}
+
 // ProcessWorkload processes a single workload according to the policy
 // It coordinates metrics collection, recommendation computation, and application
 func (wp *WorkloadProcessor) ProcessWorkload(
 	ctx context.Context,
 	workload *discovery.Workload,
 	policy *optipodv1alpha1.OptimizationPolicy,
+) (*optipodv1alpha1.WorkloadStatus, error) {
+	status, err := wp.processWorkload(ctx, workload, policy)
+	wp.recordDashboard(workload, policy, status)
+	if status != nil && status.Status == StatusSkipped && strings.HasPrefix(status.Reason, missingMetricsReason) {
+		wp.metricsRecovery.markMissingMetrics(policy)
+	}
+	return status, err
+}
+
+// processWorkload computes and applies the recommendations of a workload, see ProcessWorkload
+func (wp *WorkloadProcessor) processWorkload(
+	ctx context.Context,
+	workload *discovery.Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
 ) (*optipodv1alpha1.WorkloadStatus, error) {
 	status := &optipodv1alpha1.WorkloadStatus{
 		Name:      workload.Name,
@@ -98,84 +216,342 @@ func (wp *WorkloadProcessor) ProcessWorkload(
 		return nil, fmt.Errorf("unknown policy mode: %s", policy.Spec.Mode)
 	}
 
+	// The safety ramp starts with extra headroom and narrows it with each stable apply
+	rampApplies := safetyRampApplies(workload.Object.GetAnnotations())
+	if ramp := policy.Spec.MetricsConfig.SafetyRamp; ramp != nil {
+		status.SafetyRampPhase = ramp.Phase(rampApplies)
+		status.SafetyRampApplies = rampApplies
+	}
+	policy = withRampedSafetyFactor(policy, rampApplies)
+
 	// Get containers from workload
-	containers, err := wp.getContainers(workload)
+	containers, err := wp.getContainers(workload, policy)
 	if err != nil {
 		status.Status = StatusError
 		status.Reason = fmt.Sprintf("Failed to extract containers: %v", err)
 		return status, err
 	}
 
+	// Containers removed from the template leave no orphaned state behind; a failed cleanup is
+	// retried on the next reconcile
+	removed, err := wp.cleanupRemovedContainers(ctx, workload, policy)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to clean up removed containers",
+			"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name))
+	}
+	status.RemovedContainers = removed
+
+	// Resolve effective resources, since pods of workloads with empty resource blocks
+	// run with the namespace LimitRange defaults
+	limitRanges, err := wp.getLimitRanges(ctx, workload.Namespace)
+	if err != nil {
+		status.Status = StatusError
+		status.Reason = fmt.Sprintf("Failed to resolve LimitRange defaults: %v", err)
+		return status, err
+	}
+	effectiveResources := make(map[string]corev1.ResourceRequirements, len(containers))
+	for _, container := range containers {
+		effectiveResources[container.Name] = resolveEffectiveResources(container.Resources, limitRanges)
+	}
+
+	// Namespace defaults tighten the bounds of every policy for workloads in the namespace
+	namespaceDefaults, err := wp.getNamespaceDefaults(ctx, workload.Namespace)
+	if err != nil {
+		status.Status = StatusError
+		status.Reason = fmt.Sprintf("Failed to resolve namespace defaults: %v", err)
+		return status, err
+	}
+
+	// Gather the workload context used to decide whether startup floors apply
+	workloadContext, recentRestarts := wp.getWorkloadContext(ctx, workload, policy)
+
+	// Autoscalers scaling on CPU utilization measure it against the request, so lowering the
+	// request would make them scale out
+	cpuScaler := wp.findCPUScaler(ctx, workload, policy)
+	cpuHeld := false
+
+	// Recommendations are capped at what the largest node the workload can run on can hold
+	capacity := wp.findNodeCapacity(ctx, workload, policy)
+
+	// Memory raised because pods were evicted for node memory pressure is applied with priority
+	evictionPressure := false
+
 	// Process each container
 	var recommendations []optipodv1alpha1.ContainerRecommendation //nolint:prealloc // Size unknown
 	hasMetricsError := false
 	metricsErrorMsg := ""
+	staleMetrics := ""
+	var missingMetrics []missingContainerMetrics
+	// leftWithoutMetrics are the containers without metrics the partialMetrics action leaves as is
+	var leftWithoutMetrics []string
+
+	// Track which containers are eligible for apply after container selector evaluation
+	autoContainers := make(map[string]bool)
+
+	// Keep computed recommendations so apply and annotations see the observed memory P99
+	computedRecs := make(map[string]*recommendation.Recommendation)
+
+	// Usage and restarts of the processed containers, for the stability score
+	var stabilityMetrics []*metrics.ContainerMetrics
+	var stabilityRestarts int32
+
+	// Memory peaks retained across reconciles floor the memory recommendations
+	peakFloor := policy.Spec.MetricsConfig.MemoryPeakFloor
+	memoryPeaks := parseMemoryPeaks(workload.Object.GetAnnotations()[optipodv1alpha1.AnnotationMemoryPeaks])
+	memoryPeaksChanged := false
 
 	for _, container := range containers {
+		// Excluded containers (e.g. platform-injected sidecars) are never resized
+		if policy.IsContainerExcluded(container.Name) {
+			status.ExcludedContainers = append(status.ExcludedContainers, container.Name)
+			continue
+		}
+
+		// Evaluate container selectors; containers matching nothing are left untouched
+		selector, matched := policy.MatchContainerSelector(container.Name)
+		if !matched {
+			continue
+		}
+
+		containerMode := policy.GetContainerMode(selector)
+		if containerMode == optipodv1alpha1.ModeDisabled {
+			continue
+		}
+
+		containerPolicy := policy
+		if selector != nil && selector.ResourceBounds != nil {
+			// Per-container bounds take precedence over policy-level bounds
+			containerPolicy = policy.DeepCopy()
+			containerPolicy.Spec.ResourceBounds = *selector.ResourceBounds.DeepCopy()
+		}
+
+		if len(namespaceDefaults) > 0 {
+			bounds, err := applyNamespaceDefaults(containerPolicy.Spec.ResourceBounds, namespaceDefaults)
+			if err != nil {
+				status.Status = StatusError
+				status.Reason = fmt.Sprintf("Resource bounds conflict for container %s: %v", container.Name, err)
+				return status, err
+			}
+			if containerPolicy == policy {
+				containerPolicy = policy.DeepCopy()
+			}
+			containerPolicy.Spec.ResourceBounds = bounds
+		}
+
 		// Collect metrics for this container
 		// For simplicity, we'll query metrics for the first pod of the workload
 		podName, err := wp.getFirstPodName(workload)
 		if err != nil {
 			hasMetricsError = true
 			metricsErrorMsg = fmt.Sprintf("Failed to get pod name for container %s: %v", container.Name, err)
+			missingMetrics = append(missingMetrics, newMissingContainerMetrics(container.Name, err, containerMode, containerPolicy))
 			// Log the error for debugging
 			fmt.Printf("DEBUG: Failed to get pod name for workload %s/%s container %s: %v\n",
 				workload.Namespace, workload.Name, container.Name, err)
 			continue
 		}
 
-		// Get rolling window from policy
-		rollingWindow := 24 * time.Hour
-		if policy.Spec.MetricsConfig.RollingWindow.Duration > 0 {
-			rollingWindow = policy.Spec.MetricsConfig.RollingWindow.Duration
-		}
-
 		// Track metrics collection duration
 		metricsTimer := observability.MetricsCollectionDuration.WithLabelValues(wp.metricsProviderType)
 		metricsStartTime := time.Now()
 
-		containerMetrics, err := wp.metricsProvider.GetContainerMetrics(
+		// Collect the rolling window, plus the short window when the policy blends windows
+		containerMetrics, err := wp.recommendationEngine.CollectMetrics(
 			ctx,
+			wp.metricsProvider,
 			workload.Namespace,
 			podName,
 			container.Name,
-			rollingWindow,
+			policy,
 		)
 
+		// Backends without per-container metrics are asked for the usage of the whole pod
+		podLevel := false
+		if errors.Is(err, metrics.ErrNoContainerMetrics) {
+			containerMetrics, err = wp.collectPodLevelMetrics(ctx, workload, podName, container.Name, policy, err)
+			podLevel = err == nil
+		}
+
 		metricsTimer.Observe(time.Since(metricsStartTime).Seconds())
+		observability.ObservePhase(policy.Name, observability.PhaseMetrics, metricsStartTime)
 
 		if err != nil {
 			// Handle missing metrics error
 			hasMetricsError = true
-			metricsErrorMsg = fmt.Sprintf("Failed to collect metrics for container %s: %v", container.Name, err)
+			missing := newMissingContainerMetrics(container.Name, err, containerMode, containerPolicy)
+			missing.awaitingFirstScrape(ctx, wp.client, workload.Namespace, podName, time.Now())
+			metricsErrorMsg = fmt.Sprintf("Failed to collect metrics for container %s: %s", container.Name, missing.reason)
+			missingMetrics = append(missingMetrics, missing)
 			continue
 		}
 
+		wp.autoReadiness.recordMetricsFetch()
+		stabilityMetrics = append(stabilityMetrics, containerMetrics.Long)
+		stabilityRestarts += recentRestarts[container.Name]
+		if staleMetrics == "" {
+			staleMetrics = metricsStaleness(container.Name, containerMetrics.Long, policy.Spec.MetricsConfig.MaxMetricsAge, time.Now())
+		}
+
 		// Compute recommendation
-		rec, err := wp.recommendationEngine.ComputeRecommendation(containerMetrics, policy)
+		containerContext := workloadContext
+		containerContext.Restarts = recentRestarts[container.Name]
+		limits, requests := effectiveResources[container.Name].Limits, effectiveResources[container.Name].Requests
+		containerContext.CPULimit = limits.Cpu().DeepCopy()
+		containerContext.MemoryLimit = limits.Memory().DeepCopy()
+		containerContext.MemoryRequest = requests.Memory().DeepCopy()
+		if peakFloor != nil && policy.OptimizesMemory() {
+			// The usage of the whole pod is not the container's own peak
+			if !podLevel {
+				if peak, changed := retainMemoryPeak(memoryPeaks[container.Name], containerMetrics.Long.Memory.Max,
+					peakFloor, time.Now()); changed {
+					memoryPeaks[container.Name] = peak
+					memoryPeaksChanged = true
+				}
+			}
+			containerContext.MemoryPeak = *resource.NewQuantity(memoryPeaks[container.Name].Bytes, resource.BinarySI)
+		}
+		recommendationStartTime := time.Now()
+		rec, err := wp.computeRecommendation(workload, container.Name, containerMetrics, containerPolicy, containerContext)
+		observability.ObservePhase(policy.Name, observability.PhaseRecommendation, recommendationStartTime)
 		if err != nil {
 			status.Status = StatusError
 			status.Reason = fmt.Sprintf("Failed to compute recommendation for container %s: %v", container.Name, err)
 			return status, err
 		}
 
-		// Store recommendation
+		// Small changes keep the recorded recommendation; autoscaler and node constraints still apply
+		applyHysteresis(rec, workload.Object.GetAnnotations(), container.Name, containerPolicy)
+
+		if cpuScaler != "" && holdCPURequest(rec, effectiveResources[container.Name], cpuScaler) {
+			cpuHeld = true
+		}
+		if capacity != nil {
+			if podSpec, err := workloadPodSpec(workload); err == nil {
+				capAtNodeCapacity(rec, capacity, policy, otherContainerRequests(podSpec, effectiveResources, container.Name))
+			}
+		}
+		if rec.Urgent {
+			evictionPressure = true
+		}
+		if podLevel {
+			rec.Explanation += "; lower confidence: sized from the usage of the whole pod, as the metrics backend has no per-container metrics"
+		}
+
+		// The holds and caps above may have changed the requests since they were computed
+		rec.RecordFinal()
+
+		// Store recommendation; resources the policy does not optimize have none
 		// Make copies of the quantities to avoid any pointer aliasing issues
-		cpuCopy := rec.CPU.DeepCopy()
-		memoryCopy := rec.Memory.DeepCopy()
+		containerRec := optipodv1alpha1.ContainerRecommendation{
+			Container:      container.Name,
+			Explanation:    rec.Explanation,
+			ClampedToBound: rec.ClampedToBound,
+			LowConfidence:  podLevel,
+			DataQuality:    rec.DataQuality,
+		}
+		// Usage observed entirely after the last applied change shows how well its requests fit
+		containerRec.Accuracy = recommendationAccuracy(workload.Object.GetAnnotations(), rec.DataQuality,
+			effectiveResources[container.Name].Requests, policy.Spec.MetricsConfig.GetRollingWindow(), time.Now())
+		if containerRec.Accuracy != nil {
+			recordAccuracy(policy, workload, container.Name, containerRec.Accuracy)
+		}
+		for _, bound := range rec.ClampedToBound {
+			observability.RecommendationsClamped.WithLabelValues(policy.Name, workload.Namespace, workload.Name, workload.Kind, bound).Inc()
+		}
+		if policy.OptimizesCPU() {
+			cpuCopy := rec.CPU.DeepCopy()
+			containerRec.CPU = &cpuCopy
+		}
+		if policy.OptimizesMemory() {
+			memoryCopy := rec.Memory.DeepCopy()
+			containerRec.Memory = &memoryCopy
+		}
+		recommendations = append(recommendations, containerRec)
+
+		computedRecs[container.Name] = rec
+
+		if wp.impactCollector != nil {
+			// Resources the policy does not optimize are reported unchanged
+			effective := effectiveResources[container.Name]
+			change := report.ContainerChange{
+				Namespace:      workload.Namespace,
+				WorkloadKind:   workload.Kind,
+				WorkloadName:   workload.Name,
+				ContainerName:  container.Name,
+				CurrentCPU:     effective.Requests.Cpu().DeepCopy(),
+				CurrentMemory:  effective.Requests.Memory().DeepCopy(),
+				ProposedCPU:    effective.Requests.Cpu().DeepCopy(),
+				ProposedMemory: effective.Requests.Memory().DeepCopy(),
+			}
+			if containerRec.CPU != nil {
+				change.ProposedCPU = rec.CPU.DeepCopy()
+			}
+			if containerRec.Memory != nil {
+				change.ProposedMemory = rec.Memory.DeepCopy()
+			}
+			wp.impactCollector.Record(change)
+		}
+
+		if containerMode == optipodv1alpha1.ModeAuto {
+			autoContainers[container.Name] = true
+		}
+	}
+
+	// A peak that failed to be stored is observed again on the next reconcile while it is in the
+	// rolling window
+	if memoryPeaksChanged {
+		if err := wp.setWorkloadAnnotation(ctx, workload, optipodv1alpha1.AnnotationMemoryPeaks,
+			formatMemoryPeaks(memoryPeaks), false); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to record memory peaks",
+				"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name))
+		}
+	}
+
+	if cpuHeld && wp.eventRecorder != nil {
+		wp.eventRecorder.RecordAutoscalerConstraint(workload.Object, workload.Name, workload.Namespace,
+			fmt.Sprintf("KEDA ScaledObject %s", cpuScaler))
+	}
+	if evictionPressure && wp.eventRecorder != nil {
+		wp.eventRecorder.RecordEvictionPressure(workload.Object, workload.Name, workload.Namespace, workloadContext.MemoryEvictions)
+	}
 
-		recommendations = append(recommendations, optipodv1alpha1.ContainerRecommendation{
-			Container:   container.Name,
-			CPU:         &cpuCopy,
-			Memory:      &memoryCopy,
-			Explanation: rec.Explanation,
-		})
+	// Informational metrics are context only and are reported whatever the outcome
+	status.InformationalMetrics = wp.collectInformationalMetrics(ctx, workload, policy)
+
+	// Containers without metrics hold the whole workload back, unless the policy sizes the
+	// containers that have metrics and handles the others on their own
+	if hasMetricsError {
+		if action := policy.Spec.PartialMetrics.GetAction(); len(recommendations) > 0 && action != optipodv1alpha1.PartialMetricsSkipWorkload {
+			outcomes, floored, leftAsIs := partialMetricsRecommendations(missingMetrics, effectiveResources, policy)
+			status.ContainersWithoutMetrics = outcomes
+			leftWithoutMetrics = leftAsIs
+			for _, floor := range floored {
+				recommendations = append(recommendations, floor.containerRec)
+				computedRecs[floor.containerRec.Container] = floor.rec
+				if floor.auto {
+					autoContainers[floor.containerRec.Container] = true
+				}
+			}
+			hasMetricsError = false
+		} else {
+			status.ContainersWithoutMetrics = skippedForMissingMetrics(missingMetrics)
+		}
+	}
+
+	// If container selectors excluded every container, there is nothing to do
+	if len(recommendations) == 0 && !hasMetricsError {
+		status.Status = StatusSkipped
+		status.Reason = "No containers matched the policy container selectors"
+		if len(status.ExcludedContainers) > 0 {
+			status.Reason = "All containers are excluded or unmatched by the policy container selectors"
+		}
+		return status, nil
 	}
 
 	// If we have metrics errors, prevent changes
 	if hasMetricsError {
 		status.Status = StatusSkipped
-		status.Reason = fmt.Sprintf("Missing metrics: %s", metricsErrorMsg)
+		status.Reason = fmt.Sprintf("%s: %s", missingMetricsReason, metricsErrorMsg)
 		status.Recommendations = recommendations
 		now := metav1.Now()
 		status.LastRecommendation = &now
@@ -187,10 +563,15 @@ func (wp *WorkloadProcessor) ProcessWorkload(
 	now := metav1.Now()
 	status.LastRecommendation = &now
 
+	stabilityScore := recommendation.StabilityScore(stabilityMetrics, stabilityRestarts)
+	status.StabilityScore = &stabilityScore
+	observability.WorkloadStabilityScore.WithLabelValues(policy.Name, workload.Namespace, workload.Name, workload.Kind).
+		Set(float64(stabilityScore))
+
 	// Add annotations to workload for visibility
-	// In test mode (when client is nil), skip annotations to avoid test failures
-	if wp.client != nil {
-		if err := wp.addRecommendationAnnotations(ctx, workload, recommendations, policy); err != nil {
+	// In test mode (when client is nil) and in report-only mode, skip annotations
+	if wp.client != nil && !wp.reportOnly {
+		if err := wp.addRecommendationAnnotations(ctx, workload, recommendations, computedRecs, stabilityScore, policy); err != nil {
 			// Log the error but don't fail the whole operation
 			// The error will be visible in the status
 			status.Status = StatusError
@@ -199,6 +580,17 @@ func (wp *WorkloadProcessor) ProcessWorkload(
 		}
 	}
 
+	// A recommendation with an implausible CPU to memory ratio usually comes from bad metrics;
+	// it stays visible for investigation but is never applied
+	if reason := implausibleRatio(recommendations, policy.Spec.CPUMemoryRatio); reason != "" {
+		status.Status = StatusSuspicious
+		status.Reason = fmt.Sprintf("Recommendation not applied, implausible CPU to memory ratio: %s", reason)
+		return status, nil
+	}
+
+	// Make the recommendations available to VPA-aware tooling
+	wp.exportVPA(ctx, workload, policy, recommendations)
+
 	// In Recommend mode, we only store recommendations (via annotations)
 	if policy.Spec.Mode == optipodv1alpha1.ModeRecommend {
 		status.Status = StatusRecommended
@@ -206,91 +598,742 @@ func (wp *WorkloadProcessor) ProcessWorkload(
 		return status, nil
 	}
 
+	// A policy in dry-run only recommends, whatever its mode
+	if policy.Spec.DryRun {
+		status.Status = StatusRecommended
+		status.Reason = "Recommendations computed, not applied (policy dry-run)"
+		return status, nil
+	}
+
+	// After startup, changes wait until the metrics backend is known to be healthy and serving
+	if !wp.autoReadiness.Ready(ctx) {
+		status.Status = StatusAwaitingMetricsReady
+		status.Reason = "Recommendations computed, not applied (waiting for the metrics backend to become ready after startup)"
+		return status, nil
+	}
+
+	// Container selectors may have narrowed every container to Recommend mode
+	if policy.Spec.Mode == optipodv1alpha1.ModeAuto && len(autoContainers) == 0 {
+		status.Status = StatusRecommended
+		status.Reason = "Recommendations computed, not applied (container selectors set Recommend mode)"
+		return status, nil
+	}
+
+	// Workloads with volatile usage stay recommend-only until their usage settles, unless
+	// evictions call for more memory now
+	if minScore := policy.Spec.MinStabilityScore; policy.Spec.Mode == optipodv1alpha1.ModeAuto &&
+		minScore != nil && stabilityScore < *minScore && !evictionPressure {
+		status.Status = StatusRecommended
+		status.Reason = fmt.Sprintf("Recommendations computed, not applied (stability score %d is below minStabilityScore %d)",
+			stabilityScore, *minScore)
+		return status, nil
+	}
+
+	// Stale metrics may no longer describe the workload's usage, so they are only recommended
+	if staleMetrics != "" && policy.Spec.Mode == optipodv1alpha1.ModeAuto {
+		status.Status = StatusRecommended
+		status.Reason = fmt.Sprintf("%s: recommendations computed, not applied (%s)", staleMetricsReason, staleMetrics)
+		return status, nil
+	}
+
+	// A GitOps tool would revert the changes on its next sync, so the workloads it manages are
+	// only recommended unless the policy opts in
+	if policy.Spec.Mode == optipodv1alpha1.ModeAuto && !policy.Spec.UpdateStrategy.AllowGitOpsManaged {
+		if tool, marker := gitOpsManager(workload.Object); tool != "" {
+			status.Status = StatusRecommended
+			status.Reason = fmt.Sprintf("Recommendations computed, not applied (managed by %s per %s, whose sync would "+
+				"revert changes; set updateStrategy.allowGitOpsManaged to apply them)", tool, marker)
+			return status, nil
+		}
+	}
+
 	// In Auto mode, attempt to apply changes
 	if policy.Spec.Mode == optipodv1alpha1.ModeAuto {
-		// Track apply result for status updates
-		var lastApplyResult *application.ApplyResult
+		// Do not start applying once the manager is stopping or leadership is lost;
+		// nothing has been changed yet, so the workload is left consistent
+		if ctx.Err() != nil {
+			status.Status = StatusSkipped
+			status.Reason = "Apply aborted before any change: operator is shutting down"
+			return status, nil
+		}
+		if err := wp.leaderTracker.checkLeader(); err != nil {
+			status.Status = StatusSkipped
+			status.Reason = "Apply aborted before any change: leadership lost"
+			return status, err
+		}
 
-		// For each container, apply the recommendation
-		for i, rec := range recommendations {
-			// Convert workload to application.Workload format
-			appWorkload, err := wp.convertToApplicationWorkload(workload)
-			if err != nil {
-				status.Status = StatusError
-				status.Reason = fmt.Sprintf("Failed to convert workload: %v", err)
-				return status, err
+		// A partitioned rollout in progress is finished before the next change is applied
+		if progress, err := wp.advancePartition(ctx, workload, policy); err != nil {
+			status.Status = StatusError
+			status.Reason = fmt.Sprintf("Failed to advance partitioned rollout: %v", err)
+			return status, err
+		} else if progress != nil {
+			status.Status = StatusRollingOut
+			status.PartitionedRollout = progress
+			status.Reason = fmt.Sprintf("Partitioned rollout in progress: %d of %d pods updated, partition %d",
+				progress.UpdatedReplicas(), progress.Replicas, progress.Partition)
+			return status, nil
+		}
+
+		// Likewise, the pods of an OnDelete workload are deleted one at a time until all of them
+		// run the update revision
+		if progress, err := wp.advanceOnDeleteRollout(ctx, workload, policy); err != nil {
+			status.Status = StatusError
+			status.Reason = fmt.Sprintf("Failed to advance OnDelete rollout: %v", err)
+			return status, err
+		} else if progress != nil {
+			status.Status = StatusRollingOut
+			status.OnDeleteRollout = progress
+			status.Reason = fmt.Sprintf("OnDelete rollout in progress: %d of %d pods updated", progress.UpdatedPods, progress.Pods)
+			if progress.DisruptionBlocked {
+				status.Reason += "; waiting for a PodDisruptionBudget to allow the next eviction"
+			}
+			return status, nil
+		}
+
+		// Decreases wait until they have been proposed in enough consecutive reconciles, while
+		// increases are applied at once
+		held, err := wp.heldDecreases(ctx, workload, policy, recommendations, autoContainers, effectiveResources)
+		if err != nil {
+			status.Status = StatusError
+			status.Reason = fmt.Sprintf("Failed to record decrease observations: %v", err)
+			return status, err
+		}
+		if len(held) > 0 && !hasUnheldChange(recommendations, autoContainers, effectiveResources, held) {
+			status.Status = StatusAwaitingStableDecrease
+			status.Reason = fmt.Sprintf("Recommendations computed, not applied (awaiting stable decrease: %s observations)",
+				describeHeldDecreases(held, *policy.Spec.UpdateStrategy.StableDecreaseObservations))
+			return status, nil
+		}
+
+		// Once applying has started, finish every container of the workload even if the context is
+		// cancelled mid-apply, so the workload is never left with only part of its containers patched.
+		// Each patch carries the last-applied annotation, so a patched template always has it.
+		applyCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), applyTimeout)
+		defer cancel()
+
+		// Convert workload to application.Workload format
+		appWorkload, err := wp.convertToApplicationWorkload(workload)
+		if err != nil {
+			status.Status = StatusError
+			status.Reason = fmt.Sprintf("Failed to convert workload: %v", err)
+			return status, err
+		}
+		appWorkload.EffectiveResources = effectiveResources
+
+		// Changes that may need approval are rounded, see roundForProposal
+		approvalPossible := policy.Spec.UpdateStrategy.ApprovalRequired ||
+			(policy.Spec.UpdateStrategy.InPlaceBounds != nil && policy.Spec.UpdateStrategy.InPlaceBounds.ApprovalBeyondBounds)
+
+		invalidMethodReported := false
+		beyondInPlaceBounds := false
+		urgent := false
+		suppressedDecreases := make(map[string][]corev1.ResourceName)
+		var withinTolerance []string
+
+		// Decide for every container before changing anything, so the workload is updated
+		// completely or not at all
+		var changes, unchanged []application.ContainerChange //nolint:prealloc // Size unknown
+		appRecs := make(map[string]*recommendation.Recommendation)
+		for _, rec := range recommendations {
+			// Containers narrowed to Recommend mode by a selector are not applied
+			if !autoContainers[rec.Container] {
+				continue
 			}
 
-			// Create recommendation object
-			appRec := &recommendation.Recommendation{
-				CPU:    *rec.CPU,
-				Memory: *rec.Memory,
+			// Create recommendation object; the application engine leaves out resources
+			// the policy does not optimize
+			appRec := &recommendation.Recommendation{}
+			if rec.CPU != nil {
+				appRec.CPU = *rec.CPU
+			}
+			if rec.Memory != nil {
+				appRec.Memory = *rec.Memory
+			}
+			if computed, ok := computedRecs[rec.Container]; ok {
+				appRec.ObservedMemoryP99 = computed.ObservedMemoryP99
+				appRec.MemoryLimit = computed.MemoryLimit
+				appRec.CPULimitRatio = computed.CPULimitRatio
+				appRec.MemoryLimitRatio = computed.MemoryLimitRatio
+				appRec.KeepLimits = computed.KeepLimits
+				appRec.Urgent = computed.Urgent
+			}
+			if approvalPossible {
+				roundForProposal(appRec)
 			}
+			holdDecreases(appRec, rec.Container, effectiveResources[rec.Container], held)
 
 			// Check if we can apply
-			decision, err := wp.applicationEngine.CanApply(ctx, appWorkload, appRec, policy)
+			decision, err := wp.applicationEngine.CanApply(applyCtx, appWorkload, rec.Container, appRec, policy)
 			if err != nil {
 				status.Status = StatusError
 				status.Reason = fmt.Sprintf("Failed to determine if changes can be applied: %v", err)
 				return status, err
 			}
+			if decision.InvalidUpdateMethod != "" && !invalidMethodReported && wp.eventRecorder != nil {
+				wp.eventRecorder.RecordInvalidUpdateMethod(workload.Object, workload.Name, workload.Namespace, decision.InvalidUpdateMethod)
+				invalidMethodReported = true
+			}
+
+			if len(decision.SuppressedDecreases) > 0 {
+				suppressedDecreases[rec.Container] = decision.SuppressedDecreases
+			}
 
+			// A container whose recommendation only lowers requests is left as is under increaseOnly,
+			// and one whose recommendation is within the apply tolerance is not changed. Both stay in
+			// the apply with their current values, which keeps the fields optipod owns on them.
+			if !decision.CanApply && errors.Is(decision.Cause, application.ErrDecreaseSuppressed) {
+				unchanged = append(unchanged, application.ContainerChange{Container: rec.Container, Unchanged: true})
+				continue
+			}
+			if !decision.CanApply && errors.Is(decision.Cause, application.ErrWithinTolerance) {
+				withinTolerance = append(withinTolerance, rec.Container)
+				unchanged = append(unchanged, application.ContainerChange{Container: rec.Container, Unchanged: true})
+				continue
+			}
 			if !decision.CanApply {
 				status.Status = StatusSkipped
 				status.Reason = decision.Reason
+				if cause := application.ErrorReason(decision.Cause); cause != "" && wp.eventRecorder != nil {
+					wp.eventRecorder.RecordApplySkipped(workload.Object, workload.Name, workload.Namespace, cause, decision.Reason)
+				}
 				return status, nil
 			}
 
-			// Apply the changes
-			applyResult, err := wp.applicationEngine.Apply(ctx, appWorkload, rec.Container, appRec, policy)
-			if err != nil {
-				status.Status = StatusError
-				status.Reason = fmt.Sprintf("Failed to apply changes to container %s: %v", rec.Container, err)
+			changes = append(changes, application.ContainerChange{Container: rec.Container, Recommendation: appRec, Method: decision.Method})
+			appRecs[rec.Container] = appRec
+			urgent = urgent || appRec.Urgent
+			beyondInPlaceBounds = beyondInPlaceBounds || decision.BeyondInPlaceBounds
+		}
+
+		if len(changes) == 0 && (len(suppressedDecreases) > 0 || len(withinTolerance) > 0) {
+			var notApplied []string
+			if len(suppressedDecreases) > 0 {
+				notApplied = append(notApplied, fmt.Sprintf("decrease suppressed by increaseOnly: %s",
+					describeSuppressedDecreases(suppressedDecreases)))
+			}
+			if len(withinTolerance) > 0 {
+				notApplied = append(notApplied, fmt.Sprintf("within tolerance of the current requests: %s",
+					strings.Join(withinTolerance, ", ")))
+			}
+			status.Status = StatusRecommended
+			status.Reason = fmt.Sprintf("Recommendations computed, not applied (%s)", strings.Join(notApplied, "; "))
+			return status, nil
+		}
+		// Containers without metrics left as they are stay in the apply with their current values too
+		if len(changes) > 0 {
+			for _, container := range leftWithoutMetrics {
+				unchanged = append(unchanged, application.ContainerChange{Container: container, Unchanged: true})
+			}
+		}
+		changes = append(changes, unchanged...)
+
+		// With approval required, only a proposal approved through the workload's annotation is
+		// applied. Changes within tolerance or suppressed are not proposed.
+		if policy.Spec.UpdateStrategy.ApprovalRequired {
+			if pending, err := wp.awaitApproval(ctx, workload, status, changes); pending || err != nil {
+				return status, err
+			}
+		}
+
+		// Patching a workload its controller is still rolling out would stack a second rollout on
+		// the first, so the change waits until the workload is stable. Urgent changes, which relieve
+		// an active shortage, do not wait.
+		if rollout := rolloutInProgress(workload); rollout != "" && !urgent {
+			status.Status = StatusAwaitingStableRollout
+			status.Reason = fmt.Sprintf("Recommendations computed, not applied (awaiting stable rollout: %s)", rollout)
+			return status, nil
+		}
+
+		// Changes too large to resize in-place can be held for approval before the pods are recreated
+		if beyondInPlaceBounds && !policy.Spec.UpdateStrategy.ApprovalRequired &&
+			policy.Spec.UpdateStrategy.InPlaceBounds != nil && policy.Spec.UpdateStrategy.InPlaceBounds.ApprovalBeyondBounds {
+			if pending, err := wp.awaitApproval(ctx, workload, status, changes); pending || err != nil {
+				if pending {
+					status.Reason += " (change exceeds the in-place bounds)"
+				}
 				return status, err
 			}
+		}
+
+		// Increases count against the cluster-wide budget; once it is used up they wait for the
+		// next interval, or until the budget is raised when they exceed all of it, while decreases
+		// are never held back: they go ahead with the increases held at the current requests
+		cpuIncrease, memoryIncrease := requestIncrease(workload, appRecs, effectiveResources)
+		budgetDeferred := ""
+		if !wp.increaseBudget.Reserve(cpuIncrease, memoryIncrease) {
+			budgetDeferred = fmt.Sprintf("cluster increase budget exhausted: adding CPU %s and memory %s is deferred to the next interval",
+				cpuIncrease.String(), memoryIncrease.String())
+			if wp.increaseBudget.ExceedsLimits(cpuIncrease, memoryIncrease) {
+				budgetDeferred = fmt.Sprintf("cluster increase budget too small: adding CPU %s and memory %s exceeds the whole budget and is held until it is raised",
+					cpuIncrease.String(), memoryIncrease.String())
+			}
+			if !holdIncreases(appRecs, effectiveResources) {
+				status.Status = StatusRecommended
+				status.Reason = fmt.Sprintf("Recommendations computed, not applied (%s)", budgetDeferred)
+				return status, nil
+			}
+			cpuIncrease, memoryIncrease = resource.Quantity{}, resource.Quantity{}
+		}
+
+		// Apply the changes of all containers in a single request
+		applyStartTime := time.Now()
+		applyResult, err := wp.applicationEngine.Apply(applyCtx, appWorkload, changes, policy)
+		observability.ObservePhase(policy.Name, observability.PhaseApply, applyStartTime)
+		if err != nil {
+			wp.increaseBudget.Release(cpuIncrease, memoryIncrease)
+		}
+		if err == nil && applyResult != nil && applyResult.PodResourcesUnchanged {
+			wp.increaseBudget.Release(cpuIncrease, memoryIncrease)
+			status.PodResources = applyResult.PodResources
+			status.Status = StatusRecommended
+			status.Reason = "Recommendations computed, not applied (pod-level resources within tolerance of the current ones)"
+			return status, nil
+		}
+		for i, rec := range recommendations {
+			appRec, ok := appRecs[rec.Container]
+			if !ok {
+				continue
+			}
+			result := containerApplyResult(applyResult, rec.Container)
+			wp.recordAudit(workload, policy, rec, effectiveResources[rec.Container], appRec, result, applyResult, err)
+			if err == nil && policy.Spec.UpdateStrategy.ConvergenceRate != nil {
+				recordConvergence(&recommendations[i], result, policy)
+			}
+		}
+		if err != nil {
+			status.Status = StatusError
+			status.Reason = fmt.Sprintf("Failed to apply changes: %v", err)
+			return status, err
+		}
 
-			// Store the apply result
-			lastApplyResult = applyResult
+		// Update last applied timestamp (only once after all containers)
+		now := metav1.Now()
+		status.LastApplied = &now
 
-			// Update last applied timestamp (only once after all containers)
-			if i == len(recommendations)-1 {
-				now := metav1.Now()
-				status.LastApplied = &now
+		if ramp := policy.Spec.MetricsConfig.SafetyRamp; ramp != nil {
+			applies, err := wp.recordSafetyRampApply(ctx, workload, ramp, rampApplies, stabilityScore)
+			if err != nil {
+				// The apply is not counted, so the safety factor narrows one apply later
+				logf.FromContext(ctx).Error(err, "Failed to record safety ramp apply",
+					"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name))
 			}
+			status.SafetyRampPhase = ramp.Phase(applies)
+			status.SafetyRampApplies = applies
 		}
 
 		// Update status with SSA information
-		if lastApplyResult != nil {
-			status.LastApplyMethod = lastApplyResult.Method
-			status.FieldOwnership = lastApplyResult.FieldOwnership
+		if applyResult != nil {
+			status.LastApplyMethod = applyResult.Method
+			status.FieldOwnership = applyResult.FieldOwnership
+			status.PodResources = applyResult.PodResources
 		}
 
 		status.Status = StatusApplied
 		status.Reason = "Recommendations applied successfully"
+		if evictionPressure {
+			status.Reason = fmt.Sprintf("Recommendations applied with priority (%s): memory raised after %d pod(s) were evicted for node memory pressure",
+				observability.EventReasonEvictionPressure, workloadContext.MemoryEvictions)
+		}
+		for _, rec := range recommendations {
+			if rec.Converging {
+				status.Reason = "Recommendations partially applied, converging toward the recommendation"
+				break
+			}
+		}
+		if applyResult != nil && applyResult.PartitionedRollout != nil {
+			status.PartitionedRollout = applyResult.PartitionedRollout
+			status.Reason += fmt.Sprintf("; rolling out one pod at a time from partition %d",
+				applyResult.PartitionedRollout.Partition)
+		}
+		if applyResult != nil && applyResult.OnDeleteRollout {
+			status.Reason += "; pods are deleted one at a time to roll the change out (OnDelete update strategy)"
+		}
+		if budgetDeferred != "" {
+			status.Reason += fmt.Sprintf("; only decreases applied (%s)", budgetDeferred)
+		}
+		if len(held) > 0 {
+			status.Reason += fmt.Sprintf("; decreases held until stable (%s observations)",
+				describeHeldDecreases(held, *policy.Spec.UpdateStrategy.StableDecreaseObservations))
+		}
+		if len(suppressedDecreases) > 0 {
+			status.Reason += fmt.Sprintf("; decrease suppressed by increaseOnly (%s)", describeSuppressedDecreases(suppressedDecreases))
+		}
+		if len(withinTolerance) > 0 {
+			status.Reason += fmt.Sprintf("; container(s) %s within tolerance of the current requests, not changed",
+				strings.Join(withinTolerance, ", "))
+		}
+		if restarted := restartedContainers(applyResult); len(restarted) > 0 {
+			status.Reason += fmt.Sprintf("; in-place resize restarts container(s) %s per their resizePolicy",
+				strings.Join(restarted, ", "))
+		}
+		if downgraded := qosDowngradedContainers(applyResult); len(downgraded) > 0 {
+			status.Reason += fmt.Sprintf("; container(s) %s changed from Guaranteed to Burstable QoS",
+				strings.Join(downgraded, ", "))
+			if wp.eventRecorder != nil {
+				wp.eventRecorder.RecordQoSDowngrade(workload.Object, workload.Name, workload.Namespace, downgraded)
+			}
+		}
 		return status, nil
 	}
 
 	return status, nil
 }
 
-// getContainers extracts container information from a workload
-func (wp *WorkloadProcessor) getContainers(workload *discovery.Workload) ([]corev1.Container, error) {
-	var containers []corev1.Container
+// advancePartition moves the workload's partitioned rollout forward when the policy rolls
+// StatefulSet changes out one pod at a time. It returns the rollout's progress, or nil when no
+// rollout is in progress.
+func (wp *WorkloadProcessor) advancePartition(
+	ctx context.Context,
+	workload *discovery.Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) (*optipodv1alpha1.PartitionedRolloutStatus, error) {
+	advancer, ok := wp.applicationEngine.(PartitionAdvancer)
+	if !ok || !policy.Spec.UpdateStrategy.PartitionedRollout || workload.Kind != KindStatefulSet {
+		return nil, nil
+	}
+	appWorkload, err := wp.convertToApplicationWorkload(workload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert workload: %w", err)
+	}
+	return advancer.AdvancePartition(ctx, appWorkload, policy)
+}
+
+// advanceOnDeleteRollout moves the rollout of an OnDelete workload forward when the policy deletes
+// its pods one at a time. It returns the rollout's progress, or nil when no rollout is in
+// progress.
+func (wp *WorkloadProcessor) advanceOnDeleteRollout(
+	ctx context.Context,
+	workload *discovery.Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) (*optipodv1alpha1.OnDeleteRolloutStatus, error) {
+	advancer, ok := wp.applicationEngine.(OnDeleteRolloutAdvancer)
+	if !ok || !policy.Spec.UpdateStrategy.OnDeleteRollout || (workload.Kind != KindStatefulSet && workload.Kind != KindDaemonSet) {
+		return nil, nil
+	}
+	appWorkload, err := wp.convertToApplicationWorkload(workload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert workload: %w", err)
+	}
+	return advancer.AdvanceOnDeleteRollout(ctx, appWorkload, policy)
+}
+
+// exportVPA writes the recommendations to the workload's exported VerticalPodAutoscaler when the
+// VPA export is enabled and the policy is not in dry-run. A failed export is logged and does not
+// affect the workload.
+func (wp *WorkloadProcessor) exportVPA(
+	ctx context.Context,
+	workload *discovery.Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	recommendations []optipodv1alpha1.ContainerRecommendation,
+) {
+	if wp.vpaExporter == nil || policy.Spec.DryRun || wp.leaderTracker.checkLeader() != nil {
+		return
+	}
+	if err := wp.vpaExporter.Export(ctx, workload, policyOwnerRef(policy), recommendations); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to export recommendations to a VerticalPodAutoscaler",
+			"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name))
+	}
+}
+
+// pruneVPAs deletes the VerticalPodAutoscalers exported for the policy's workloads that are not
+// in keep, see VPAExporter.Prune. It does nothing when the VPA export is disabled.
+func (wp *WorkloadProcessor) pruneVPAs(ctx context.Context, policy *optipodv1alpha1.OptimizationPolicy, keep map[string]bool) error {
+	if wp == nil || wp.vpaExporter == nil {
+		return nil
+	}
+	if err := wp.leaderTracker.checkLeader(); err != nil {
+		return err
+	}
+	return wp.vpaExporter.Prune(ctx, policyOwnerRef(policy), keep)
+}
+
+// findCPUScaler returns a description of the autoscaler scaling the workload on CPU, or an empty
+// string when there is none or the policy does not optimize CPU
+func (wp *WorkloadProcessor) findCPUScaler(
+	ctx context.Context,
+	workload *discovery.Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) string {
+	if wp.scaledObjectFinder == nil || !policy.OptimizesCPU() {
+		return ""
+	}
+	scaler, err := wp.scaledObjectFinder.FindCPUScaler(ctx, workload)
+	if err != nil {
+		// Without the ScaledObjects the recommendation is not held
+		logf.FromContext(ctx).Error(err, "Failed to look up KEDA ScaledObjects",
+			"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name))
+		return ""
+	}
+	return scaler
+}
+
+// holdCPURequest keeps the CPU recommendation from going below the container's current CPU
+// request while the named ScaledObject scales the workload on CPU. It reports whether the
+// recommendation was raised.
+func holdCPURequest(rec *recommendation.Recommendation, current corev1.ResourceRequirements, scaler string) bool {
+	request, ok := current.Requests[corev1.ResourceCPU]
+	if !ok || rec.CPU.Cmp(request) >= 0 {
+		return false
+	}
+	rec.Explanation += fmt.Sprintf("; CPU request held at %s instead of lowering it to %s because KEDA ScaledObject %s scales on CPU",
+		request.String(), rec.CPU.String(), scaler)
+	rec.CPU = request.DeepCopy()
+	// The held request is above the min bound, so the recommendation is no longer limited by it
+	rec.ClampedToBound = slices.DeleteFunc(rec.ClampedToBound, func(bound string) bool {
+		return bound == optipodv1alpha1.BoundCPUMin
+	})
+	return true
+}
+
+// recordAudit hands the outcome of applying a container's recommendation to the audit logger.
+// Only the resources the policy optimizes are recorded.
+func (wp *WorkloadProcessor) recordAudit(
+	workload *discovery.Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	rec optipodv1alpha1.ContainerRecommendation,
+	current corev1.ResourceRequirements,
+	appRec *recommendation.Recommendation,
+	result application.ContainerResult,
+	applyResult *application.ApplyResult,
+	applyErr error,
+) {
+	if wp.auditLogger == nil {
+		return
+	}
+
+	applied := appRec
+	if result.Applied != nil {
+		applied = result.Applied
+	}
+	record := audit.Record{
+		Actor:             application.FieldManagerName,
+		Action:            audit.ActionApply,
+		Policy:            fmt.Sprintf("%s/%s", policy.Namespace, policy.Name),
+		WorkloadKind:      workload.Kind,
+		WorkloadNamespace: workload.Namespace,
+		WorkloadName:      workload.Name,
+		Container:         rec.Container,
+		Result:            audit.ResultSuccess,
+	}
+	if rec.CPU != nil {
+		record.Old.CPU = current.Requests.Cpu().String()
+		record.New.CPU = applied.CPU.String()
+	}
+	if rec.Memory != nil {
+		record.Old.Memory = current.Requests.Memory().String()
+		record.New.Memory = applied.Memory.String()
+	}
+	if applyErr != nil {
+		record.Result = audit.ResultFailure
+		record.Error = applyErr.Error()
+	} else if applyResult != nil {
+		record.Method = applyResult.Method
+	}
+	wp.auditLogger.Record(record)
+}
+
+// recordConvergence reports the requests applied while the policy converges gradually toward
+// the recommendation
+func recordConvergence(
+	rec *optipodv1alpha1.ContainerRecommendation,
+	result application.ContainerResult,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) {
+	if result.Applied == nil {
+		return
+	}
+	if policy.OptimizesCPU() {
+		cpu := result.Applied.CPU.DeepCopy()
+		rec.AppliedCPU = &cpu
+	}
+	if policy.OptimizesMemory() {
+		memory := result.Applied.Memory.DeepCopy()
+		rec.AppliedMemory = &memory
+	}
+	rec.Converging = !result.Converged
+}
+
+// restartedContainers returns the sorted names of the containers an apply restarts because of
+// their resizePolicy
+func restartedContainers(applyResult *application.ApplyResult) []string {
+	if applyResult == nil {
+		return nil
+	}
+	var restarted []string
+	for name, result := range applyResult.Containers {
+		if result.RestartsContainer {
+			restarted = append(restarted, name)
+		}
+	}
+	slices.Sort(restarted)
+	return restarted
+}
+
+// qosDowngradedContainers returns the sorted names of the Guaranteed containers an apply made
+// Burstable
+func qosDowngradedContainers(applyResult *application.ApplyResult) []string {
+	if applyResult == nil {
+		return nil
+	}
+	var downgraded []string
+	for name, result := range applyResult.Containers {
+		if result.QoSDowngraded {
+			downgraded = append(downgraded, name)
+		}
+	}
+	slices.Sort(downgraded)
+	return downgraded
+}
+
+// describeSuppressedDecreases describes the decreases increaseOnly suppressed by container, sorted
+// by container name, e.g. "app cpu, sidecar cpu/memory"
+func describeSuppressedDecreases(suppressed map[string][]corev1.ResourceName) string {
+	containers := slices.Sorted(maps.Keys(suppressed))
+	descriptions := make([]string, 0, len(containers))
+	for _, container := range containers {
+		names := make([]string, 0, len(suppressed[container]))
+		for _, name := range suppressed[container] {
+			names = append(names, string(name))
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s %s", container, strings.Join(names, "/")))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// containerApplyResult returns the outcome of an apply for one container, which is empty when
+// the apply failed or did not report the container
+func containerApplyResult(applyResult *application.ApplyResult, container string) application.ContainerResult {
+	if applyResult == nil {
+		return application.ContainerResult{}
+	}
+	return applyResult.Containers[container]
+}
+
+// getWorkloadContext returns the workload age when the policy has a startup floor, the number of
+// pods evicted for node memory pressure, and how often each container restarted within the
+// metrics rolling window, summed across the workload's pods. With replica scaling enabled it also
+// returns the current and target replica counts.
+func (wp *WorkloadProcessor) getWorkloadContext(
+	ctx context.Context,
+	workload *discovery.Workload,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) (recommendation.WorkloadContext, map[string]int32) {
+	workloadContext := recommendation.WorkloadContext{}
+	recentRestarts := make(map[string]int32)
+
+	if policy.Spec.ReplicaScaling != nil && policy.Spec.ReplicaScaling.Enabled {
+		current, target, err := wp.getReplicaCounts(ctx, workload)
+		if err != nil {
+			// Without replica counts the per-pod usage is used as-is
+			logf.FromContext(ctx).V(1).Info("Failed to resolve replica counts",
+				"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name), "error", err)
+		}
+		workloadContext.CurrentReplicas = current
+		workloadContext.TargetReplicas = target
+	}
+
+	if policy.Spec.StartupFloor != nil {
+		if created := workload.Object.GetCreationTimestamp(); !created.IsZero() {
+			age := time.Since(created.Time)
+			workloadContext.Age = &age
+		}
+	}
+
+	// The priority class sizes memory headroom; admission resolves it into the pods' priority
+	headroomByPriority := len(policy.Spec.MetricsConfig.PriorityMemoryHeadroom) > 0
+	if headroomByPriority {
+		if podSpec, err := workloadPodSpec(workload); err == nil {
+			workloadContext.PriorityClassName = podSpec.PriorityClassName
+			workloadContext.Priority = podSpec.Priority
+		}
+	}
+
+	// Restarts feed the startup floor and the stability score, evictions raise memory
+	if wp.client == nil {
+		return workloadContext, recentRestarts
+	}
+
+	pods, err := wp.listWorkloadPods(ctx, workload)
+	if err != nil {
+		// Restart and eviction counts only tighten recommendations and lower the stability score,
+		// so proceed without them
+		logf.FromContext(ctx).V(1).Info("Failed to list pods for restart counts",
+			"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name), "error", err)
+		return workloadContext, recentRestarts
+	}
+
+	for _, pod := range pods {
+		if headroomByPriority && workloadContext.Priority == nil && pod.Spec.Priority != nil {
+			priority := *pod.Spec.Priority
+			workloadContext.Priority = &priority
+		}
+	}
+	workloadContext.MemoryEvictions = countMemoryEvictions(pods, lastAppliedAt(workload.Object.GetAnnotations()))
+	if wp.restartTracker != nil {
+		recentRestarts = wp.restartTracker.observe(pods, time.Now().Add(-policy.Spec.MetricsConfig.GetRollingWindow()))
+	}
+
+	return workloadContext, recentRestarts
+}
+
+// listWorkloadPods lists the pods selected by a workload's pod selector
+func (wp *WorkloadProcessor) listWorkloadPods(ctx context.Context, workload *discovery.Workload) ([]corev1.Pod, error) {
+	var labelSelector *metav1.LabelSelector
 
 	switch obj := workload.Object.(type) {
 	case *appsv1.Deployment:
-		containers = obj.Spec.Template.Spec.Containers
+		labelSelector = obj.Spec.Selector
 	case *appsv1.StatefulSet:
-		containers = obj.Spec.Template.Spec.Containers
+		labelSelector = obj.Spec.Selector
 	case *appsv1.DaemonSet:
-		containers = obj.Spec.Template.Spec.Containers
+		labelSelector = obj.Spec.Selector
 	default:
 		return nil, fmt.Errorf("unsupported workload type: %T", workload.Object)
 	}
 
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert label selector: %w", err)
+	}
+
+	podList := &corev1.PodList{}
+	if err := wp.client.List(ctx, podList,
+		client.InNamespace(workload.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	return podList.Items, nil
+}
+
+// getContainers extracts container information from a workload. Native sidecars (init
+// containers with restartPolicy Always) run for the lifetime of the pod and are sized like the
+// other containers; run-once init containers are only included when the policy asks for them.
+// Ephemeral containers, such as those added by kubectl debug, are never sized.
+func (wp *WorkloadProcessor) getContainers(workload *discovery.Workload, policy *optipodv1alpha1.OptimizationPolicy) ([]corev1.Container, error) {
+	podSpec, err := workloadPodSpec(workload)
+	if err != nil {
+		return nil, err
+	}
+
+	containers := slices.Clone(podSpec.Containers)
+	for _, container := range podSpec.InitContainers {
+		if isNativeSidecar(container) || policy.Spec.IncludeInitContainers {
+			containers = append(containers, container)
+		}
+	}
+
 	return containers, nil
 }
 
+// isNativeSidecar reports whether an init container is a native sidecar, which keeps running
+// alongside the other containers instead of running to completion before they start
+func isNativeSidecar(container corev1.Container) bool {
+	return container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
 // getFirstPodName gets the name of the first pod for a workload by querying the actual pods
 func (wp *WorkloadProcessor) getFirstPodName(workload *discovery.Workload) (string, error) {
 	// For StatefulSets, we can use the predictable pod naming
@@ -372,13 +1415,15 @@ func (wp *WorkloadProcessor) convertToApplicationWorkload(workload *discovery.Wo
 
 // addRecommendationAnnotations adds annotations to the workload with recommendation details
 // Uses retry logic with exponential backoff to handle concurrent modification conflicts
-func (wp *WorkloadProcessor) addRecommendationAnnotations(ctx context.Context, workload *discovery.Workload, recommendations []optipodv1alpha1.ContainerRecommendation, policy *optipodv1alpha1.OptimizationPolicy) error {
+func (wp *WorkloadProcessor) addRecommendationAnnotations(ctx context.Context, workload *discovery.Workload, recommendations []optipodv1alpha1.ContainerRecommendation, computedRecs map[string]*recommendation.Recommendation, stabilityScore int32, policy *optipodv1alpha1.OptimizationPolicy) error {
 	if wp.client == nil {
 		return fmt.Errorf("client is nil, cannot add annotations")
 	}
 
 	log := logf.FromContext(ctx)
 
+	customAnnotations := wp.renderAnnotationTemplates(ctx, workload, recommendations, computedRecs, stabilityScore, policy)
+
 	// Retry configuration
 	const maxRetries = 5
 	const baseDelay = 100 * time.Millisecond
@@ -430,6 +1475,7 @@ func (wp *WorkloadProcessor) addRecommendationAnnotations(ctx context.Context, w
 		}
 
 		// Prepare annotations
+		current := maps.Clone(obj.GetAnnotations())
 		annotations := obj.GetAnnotations()
 		if annotations == nil {
 			annotations = make(map[string]string)
@@ -437,8 +1483,12 @@ func (wp *WorkloadProcessor) addRecommendationAnnotations(ctx context.Context, w
 
 		// Add management annotations
 		annotations[optipodv1alpha1.AnnotationManaged] = "true"
-		annotations[optipodv1alpha1.AnnotationPolicy] = policy.Name
+		// A pin naming the policy by namespace is kept as set
+		if !discovery.PinnedTo(obj, policy) {
+			annotations[optipodv1alpha1.AnnotationPolicy] = policy.Name
+		}
 		annotations[optipodv1alpha1.AnnotationLastRecommendation] = time.Now().Format(time.RFC3339)
+		annotations[optipodv1alpha1.AnnotationStabilityScore] = strconv.Itoa(int(stabilityScore))
 
 		// Add per-container recommendations (requests)
 		for _, rec := range recommendations {
@@ -453,24 +1503,54 @@ func (wp *WorkloadProcessor) addRecommendationAnnotations(ctx context.Context, w
 		}
 
 		// Add limit annotations if limits are being updated
-		if !policy.Spec.UpdateStrategy.UpdateRequestsOnly {
+		if !policy.Spec.UpdateStrategy.UpdateRequestsOnly && !policy.Spec.UpdateStrategy.RemoveLimits {
 			for _, rec := range recommendations {
-				if rec.CPU != nil && rec.Memory != nil {
-					// Calculate limits using the same logic as the application engine
-					cpuLimit, memoryLimit := wp.calculateLimitsForAnnotation(rec.CPU, rec.Memory, policy)
+				// Calculate limits using the same logic as the application engine
+				cpuRequest, memoryRequest := &resource.Quantity{}, &resource.Quantity{}
+				if rec.CPU != nil {
+					cpuRequest = rec.CPU
+				}
+				if rec.Memory != nil {
+					memoryRequest = rec.Memory
+				}
+				// Containers keeping their limits have no limits recommended
+				if computed := computedRecs[rec.Container]; computed != nil && computed.KeepLimits {
+					continue
+				}
+				cpuLimit, memoryLimit := wp.calculateLimitsForAnnotation(cpuRequest, memoryRequest, computedRecs[rec.Container], policy)
 
+				if rec.CPU != nil && policy.Spec.UpdateStrategy.ManagesCPULimit() {
 					cpuLimitKey := fmt.Sprintf("%s.%s.cpu-limit", optipodv1alpha1.AnnotationRecommendationPrefix, rec.Container)
 					annotations[cpuLimitKey] = cpuLimit.String()
-
+				}
+				if rec.Memory != nil && policy.Spec.UpdateStrategy.ManagesMemoryLimit() {
 					memoryLimitKey := fmt.Sprintf("%s.%s.memory-limit", optipodv1alpha1.AnnotationRecommendationPrefix, rec.Container)
 					annotations[memoryLimitKey] = memoryLimit.String()
 				}
 			}
 		}
 
+		// Mirror the recommendations under the operator's custom annotation keys
+		for key, value := range customAnnotations {
+			annotations[key] = value
+		}
+
+		// With hysteresis, an unchanged recommendation is not written again
+		if policy.Spec.RecommendationHysteresis != nil && recommendationAnnotationsUnchanged(current, annotations) {
+			log.V(1).Info("Recommendations unchanged, skipping annotation update",
+				"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name))
+			return true, nil
+		}
+
 		// Update annotations
 		obj.SetAnnotations(annotations)
 
+		// A former leader must not write; the update carries the resource version just read,
+		// so a write racing a newer one is rejected as a conflict
+		if err := wp.leaderTracker.checkLeader(); err != nil {
+			return false, err
+		}
+
 		// Attempt to update the workload
 		if err := wp.client.Update(ctx, obj); err != nil {
 			if apierrors.IsConflict(err) {
@@ -497,8 +1577,71 @@ func (wp *WorkloadProcessor) addRecommendationAnnotations(ctx context.Context, w
 	})
 }
 
+// renderAnnotationTemplates renders the custom annotation templates for every recommended
+// container. Annotations rendering an empty value are left out, and a template failing for a
+// workload is logged and skipped, so custom annotations never block the canonical ones.
+func (wp *WorkloadProcessor) renderAnnotationTemplates(
+	ctx context.Context,
+	workload *discovery.Workload,
+	recommendations []optipodv1alpha1.ContainerRecommendation,
+	computedRecs map[string]*recommendation.Recommendation,
+	stabilityScore int32,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) map[string]string {
+	if len(wp.annotationTemplates) == 0 {
+		return nil
+	}
+
+	annotations := make(map[string]string)
+	limitsUpdated := !policy.Spec.UpdateStrategy.UpdateRequestsOnly && !policy.Spec.UpdateStrategy.RemoveLimits
+	for _, rec := range recommendations {
+		data := AnnotationTemplateData{
+			Kind:           workload.Kind,
+			Namespace:      workload.Namespace,
+			Workload:       workload.Name,
+			Container:      rec.Container,
+			Policy:         policy.Name,
+			StabilityScore: stabilityScore,
+		}
+		cpuRequest, memoryRequest := &resource.Quantity{}, &resource.Quantity{}
+		if rec.CPU != nil {
+			cpuRequest = rec.CPU
+			data.CPURequest = rec.CPU.String()
+		}
+		if rec.Memory != nil {
+			memoryRequest = rec.Memory
+			data.MemoryRequest = rec.Memory.String()
+		}
+		if computed := computedRecs[rec.Container]; limitsUpdated && (computed == nil || !computed.KeepLimits) {
+			cpuLimit, memoryLimit := wp.calculateLimitsForAnnotation(cpuRequest, memoryRequest, computedRecs[rec.Container], policy)
+			if rec.CPU != nil && policy.Spec.UpdateStrategy.ManagesCPULimit() {
+				data.CPULimit = cpuLimit.String()
+			}
+			if rec.Memory != nil && policy.Spec.UpdateStrategy.ManagesMemoryLimit() {
+				data.MemoryLimit = memoryLimit.String()
+			}
+		}
+
+		for _, t := range wp.annotationTemplates {
+			key, value, err := t.Render(data)
+			if err != nil {
+				logf.FromContext(ctx).Info("Skipping custom annotation",
+					"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+					"container", rec.Container, "error", err)
+				continue
+			}
+			if value != "" {
+				annotations[key] = value
+			}
+		}
+	}
+	return annotations
+}
+
 // calculateLimitsForAnnotation calculates resource limits for annotation display
-func (wp *WorkloadProcessor) calculateLimitsForAnnotation(cpuRequest, memoryRequest *resource.Quantity, policy *optipodv1alpha1.OptimizationPolicy) (resource.Quantity, resource.Quantity) {
+// computed may be nil; when set it supplies the burst-ratio-derived limit ratios, the
+// percentile-derived memory limit and the P99 floor
+func (wp *WorkloadProcessor) calculateLimitsForAnnotation(cpuRequest, memoryRequest *resource.Quantity, computed *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (resource.Quantity, resource.Quantity) {
 	// Default multipliers
 	cpuMultiplier := 1.0    // CPU limit = recommendation (no headroom by default)
 	memoryMultiplier := 1.1 // Memory limit = recommendation * 1.1 (10% headroom by default)
@@ -513,6 +1656,14 @@ func (wp *WorkloadProcessor) calculateLimitsForAnnotation(cpuRequest, memoryRequ
 		}
 	}
 
+	// Match the application engine: ratios derived from the burst ratio replace the multipliers
+	if computed != nil && computed.CPULimitRatio > 0 {
+		cpuMultiplier = computed.CPULimitRatio
+	}
+	if computed != nil && computed.MemoryLimitRatio > 0 {
+		memoryMultiplier = computed.MemoryLimitRatio
+	}
+
 	// Calculate limits - use MilliValue for CPU to preserve millicores
 	cpuMilliValue := cpuRequest.MilliValue()
 	cpuLimitMilliValue := int64(float64(cpuMilliValue) * cpuMultiplier)
@@ -523,6 +1674,21 @@ func (wp *WorkloadProcessor) calculateLimitsForAnnotation(cpuRequest, memoryRequ
 	memoryLimitValue := int64(float64(memoryValue) * memoryMultiplier)
 	memoryLimit := resource.NewQuantity(memoryLimitValue, memoryRequest.Format)
 
+	if computed == nil {
+		return *cpuLimit, *memoryLimit
+	}
+
+	// Match the application engine: a percentile-derived memory limit replaces the multiplier
+	if !computed.MemoryLimit.IsZero() {
+		limit := computed.MemoryLimit.DeepCopy()
+		memoryLimit = &limit
+	}
+
+	// Match the application engine: memory limits never go below observed P99 usage
+	if !computed.ObservedMemoryP99.IsZero() && memoryLimit.Cmp(computed.ObservedMemoryP99) < 0 {
+		return *cpuLimit, computed.ObservedMemoryP99.DeepCopy()
+	}
+
 	return *cpuLimit, *memoryLimit
 }
 
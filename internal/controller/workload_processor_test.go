@@ -0,0 +1,1018 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/application"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/metrics"
+	"github.com/optipod/optipod/internal/observability"
+	"github.com/optipod/optipod/internal/recommendation"
+)
+
+// recordingApplicationEngine records which containers were applied
+type recordingApplicationEngine struct {
+	appliedContainers []string
+	keptContainers    []string
+	// keptLimits are the applied containers whose limits are kept
+	keptLimits []string
+}
+
+func (m *recordingApplicationEngine) CanApply(ctx context.Context, workload *application.Workload, containerName string, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyDecision, error) {
+	return &application.ApplyDecision{
+		CanApply: true,
+		Method:   application.InPlace,
+		Reason:   "Test decision",
+	}, nil
+}
+
+func (m *recordingApplicationEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+	for _, change := range changes {
+		if change.Unchanged {
+			m.keptContainers = append(m.keptContainers, change.Container)
+			continue
+		}
+		m.appliedContainers = append(m.appliedContainers, change.Container)
+		if change.Recommendation.KeepLimits {
+			m.keptLimits = append(m.keptLimits, change.Container)
+		}
+	}
+	return &application.ApplyResult{
+		Method:         "ServerSideApply",
+		FieldOwnership: true,
+	}, nil
+}
+
+// createTestMetricsProvider returns a metrics provider with the same usage for every container
+func createTestMetricsProvider() *mockMetricsProvider {
+	return &mockMetricsProvider{
+		metricsToReturn: &metrics.ContainerMetrics{
+			CPU: metrics.ResourceMetrics{
+				P50:     resource.MustParse("100m"),
+				P90:     resource.MustParse("200m"),
+				P99:     resource.MustParse("300m"),
+				Samples: 100,
+			},
+			Memory: metrics.ResourceMetrics{
+				P50:     resource.MustParse("128Mi"),
+				P90:     resource.MustParse("256Mi"),
+				P99:     resource.MustParse("512Mi"),
+				Samples: 100,
+			},
+		},
+	}
+}
+
+// createTestWorkload returns the test deployment, labeled app=test, running the named containers
+// without resources
+func createTestWorkload(containerNames ...string) *discovery.Workload {
+	deployment := createTestDeployment(TestWorkloadName, TestNamespace, map[string]string{"app": "test"})
+	deployment.Spec.Template.Spec.Containers = make([]corev1.Container, 0, len(containerNames))
+	for _, name := range containerNames {
+		deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, corev1.Container{
+			Name:  name,
+			Image: "test:latest",
+		})
+	}
+
+	return &discovery.Workload{
+		Kind:      KindDeployment,
+		Namespace: TestNamespace,
+		Name:      TestWorkloadName,
+		Object:    deployment,
+	}
+}
+
+// createTestPod returns a pod of the test workload
+func createTestPod(name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: TestNamespace, Labels: map[string]string{"app": "test"}}}
+}
+
+// createTestPolicy returns a policy in the given mode selecting the test workloads, which only
+// updates requests
+func createTestPolicy(mode optipodv1alpha1.PolicyMode, selectors ...optipodv1alpha1.ContainerSelector) *optipodv1alpha1.OptimizationPolicy {
+	return &optipodv1alpha1.OptimizationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-policy",
+			Namespace: TestNamespace,
+		},
+		Spec: optipodv1alpha1.OptimizationPolicySpec{
+			Mode: mode,
+			Selector: optipodv1alpha1.WorkloadSelector{
+				WorkloadSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "test"},
+				},
+			},
+			MetricsConfig: optipodv1alpha1.MetricsConfig{
+				Provider:   "test",
+				Percentile: "P90",
+			},
+			ResourceBounds: optipodv1alpha1.ResourceBounds{
+				CPU: optipodv1alpha1.ResourceBound{
+					Min: resource.MustParse("50m"),
+					Max: resource.MustParse("2000m"),
+				},
+				Memory: optipodv1alpha1.ResourceBound{
+					Min: resource.MustParse("64Mi"),
+					Max: resource.MustParse("2Gi"),
+				},
+			},
+			UpdateStrategy: optipodv1alpha1.UpdateStrategy{
+				UpdateRequestsOnly: true,
+			},
+			ContainerSelectors: selectors,
+		},
+	}
+}
+
+// createTestProcessor returns a processor using createTestMetricsProvider and the given
+// application engine and client, which may be nil
+func createTestProcessor(appEngine ApplicationEngine, c client.Client) *WorkloadProcessor {
+	return NewWorkloadProcessor(createTestMetricsProvider(), recommendation.NewEngine(), appEngine, c)
+}
+
+func findRecommendation(recs []optipodv1alpha1.ContainerRecommendation, container string) *optipodv1alpha1.ContainerRecommendation {
+	for i := range recs {
+		if recs[i].Container == container {
+			return &recs[i]
+		}
+	}
+	return nil
+}
+
+func TestProcessWorkload_ContainerSelectors(t *testing.T) {
+	appBounds := &optipodv1alpha1.ResourceBounds{
+		CPU: optipodv1alpha1.ResourceBound{
+			Min: resource.MustParse("1"),
+			Max: resource.MustParse("2"),
+		},
+		Memory: optipodv1alpha1.ResourceBound{
+			Min: resource.MustParse("1Gi"),
+			Max: resource.MustParse("2Gi"),
+		},
+	}
+
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto,
+		optipodv1alpha1.ContainerSelector{Name: "app", ResourceBounds: appBounds},
+		optipodv1alpha1.ContainerSelector{Name: "*-sidecar", Mode: optipodv1alpha1.ModeRecommend},
+		optipodv1alpha1.ContainerSelector{Name: "debug-*", Mode: optipodv1alpha1.ModeDisabled},
+	)
+
+	appEngine := &recordingApplicationEngine{}
+	processor := createTestProcessor(appEngine, nil)
+
+	workload := createTestWorkload("app", "istio-sidecar", "debug-shell", "unmatched")
+	status, err := processor.ProcessWorkload(context.Background(), workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	if status.Status != StatusApplied {
+		t.Errorf("status = %q, want %q (reason: %s)", status.Status, StatusApplied, status.Reason)
+	}
+
+	if len(status.Recommendations) != 2 {
+		t.Fatalf("got %d recommendations, want 2 (app and istio-sidecar)", len(status.Recommendations))
+	}
+
+	// Per-container bounds take precedence over policy bounds
+	appRec := findRecommendation(status.Recommendations, "app")
+	if appRec == nil {
+		t.Fatal("missing recommendation for app container")
+	}
+	if appRec.CPU.Cmp(resource.MustParse("1")) != 0 {
+		t.Errorf("app CPU = %s, want clamped to per-container min 1", appRec.CPU.String())
+	}
+	if appRec.Memory.Cmp(resource.MustParse("1Gi")) != 0 {
+		t.Errorf("app memory = %s, want clamped to per-container min 1Gi", appRec.Memory.String())
+	}
+
+	// Containers without per-container bounds fall back to policy bounds
+	sidecarRec := findRecommendation(status.Recommendations, "istio-sidecar")
+	if sidecarRec == nil {
+		t.Fatal("missing recommendation for istio-sidecar container")
+	}
+	if sidecarRec.CPU.Cmp(resource.MustParse("240m")) != 0 {
+		t.Errorf("sidecar CPU = %s, want 240m from policy bounds", sidecarRec.CPU.String())
+	}
+
+	// Only the Auto container is applied
+	if len(appEngine.appliedContainers) != 1 || appEngine.appliedContainers[0] != "app" {
+		t.Errorf("applied containers = %v, want [app]", appEngine.appliedContainers)
+	}
+}
+
+func TestProcessWorkload_ContainerSelectorsRecommendOnly(t *testing.T) {
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto,
+		optipodv1alpha1.ContainerSelector{Name: "*", Mode: optipodv1alpha1.ModeRecommend},
+	)
+
+	appEngine := &recordingApplicationEngine{}
+	processor := createTestProcessor(appEngine, nil)
+
+	status, err := processor.ProcessWorkload(context.Background(), createTestWorkload("app"), policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	if status.Status != StatusRecommended {
+		t.Errorf("status = %q, want %q", status.Status, StatusRecommended)
+	}
+	if len(appEngine.appliedContainers) != 0 {
+		t.Errorf("applied containers = %v, want none", appEngine.appliedContainers)
+	}
+}
+
+func TestProcessWorkload_PolicyDryRun(t *testing.T) {
+	for _, mode := range []optipodv1alpha1.PolicyMode{optipodv1alpha1.ModeAuto, optipodv1alpha1.ModeRecommend} {
+		t.Run(string(mode), func(t *testing.T) {
+			policy := createTestPolicy(mode)
+			policy.Spec.DryRun = true
+
+			appEngine := &recordingApplicationEngine{}
+			processor := createTestProcessor(appEngine, nil)
+
+			status, err := processor.ProcessWorkload(context.Background(), createTestWorkload("app"), policy)
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+
+			if status.Status != StatusRecommended || len(status.Recommendations) != 1 {
+				t.Errorf("status = %q with %d recommendations, want %q with 1", status.Status, len(status.Recommendations), StatusRecommended)
+			}
+			if len(appEngine.appliedContainers) != 0 {
+				t.Errorf("applied containers = %v, want none", appEngine.appliedContainers)
+			}
+		})
+	}
+}
+
+// partitionAdvancingEngine reports the given partitioned rollout progress and starts a rollout on apply
+type partitionAdvancingEngine struct {
+	recordingApplicationEngine
+	progress *optipodv1alpha1.PartitionedRolloutStatus
+	advanced int
+}
+
+func (m *partitionAdvancingEngine) AdvancePartition(ctx context.Context, workload *application.Workload, policy *optipodv1alpha1.OptimizationPolicy) (*optipodv1alpha1.PartitionedRolloutStatus, error) {
+	m.advanced++
+	return m.progress, nil
+}
+
+func (m *partitionAdvancingEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+	result, err := m.recordingApplicationEngine.Apply(ctx, workload, changes, policy)
+	result.PartitionedRollout = &optipodv1alpha1.PartitionedRolloutStatus{Partition: 2, Replicas: 3}
+	return result, err
+}
+
+func TestProcessWorkload_PartitionedRollout(t *testing.T) {
+	deployment := createTestWorkload("app").Object.(*appsv1.Deployment)
+	workload := &discovery.Workload{
+		Kind:      KindStatefulSet,
+		Namespace: TestNamespace,
+		Name:      TestWorkloadName,
+		Object: &appsv1.StatefulSet{
+			ObjectMeta: deployment.ObjectMeta,
+			Spec:       appsv1.StatefulSetSpec{Template: deployment.Spec.Template},
+		},
+	}
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	policy.Spec.UpdateStrategy.PartitionedRollout = true
+
+	// A rollout in progress holds back the next change
+	appEngine := &partitionAdvancingEngine{progress: &optipodv1alpha1.PartitionedRolloutStatus{Partition: 1, Replicas: 3}}
+	processor := createTestProcessor(appEngine, nil)
+	status, err := processor.ProcessWorkload(context.Background(), workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusRollingOut || status.PartitionedRollout == nil || status.PartitionedRollout.Partition != 1 {
+		t.Errorf("status = %q with rollout %+v, want %q at partition 1", status.Status, status.PartitionedRollout, StatusRollingOut)
+	}
+	if !strings.Contains(status.Reason, "2 of 3 pods updated") {
+		t.Errorf("reason = %q, want the rollout progress", status.Reason)
+	}
+	if len(appEngine.appliedContainers) != 0 || len(status.Recommendations) != 1 {
+		t.Errorf("applied %v with %d recommendations, want the recommendation reported but not applied",
+			appEngine.appliedContainers, len(status.Recommendations))
+	}
+
+	// Without a rollout in progress the change is applied and starts a new rollout
+	appEngine.progress = nil
+	status, err = processor.ProcessWorkload(context.Background(), workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusApplied || status.PartitionedRollout == nil || status.PartitionedRollout.Partition != 2 {
+		t.Errorf("status = %q with rollout %+v, want %q starting at partition 2", status.Status, status.PartitionedRollout, StatusApplied)
+	}
+
+	// Deployments are never rolled out through a partition
+	advanced := appEngine.advanced
+	if _, err := processor.ProcessWorkload(context.Background(), createTestWorkload("app"), policy); err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if appEngine.advanced != advanced {
+		t.Error("AdvancePartition() was called for a Deployment")
+	}
+}
+
+// onDeleteRolloutEngine reports the given OnDelete rollout progress and starts a rollout on apply
+type onDeleteRolloutEngine struct {
+	recordingApplicationEngine
+	progress *optipodv1alpha1.OnDeleteRolloutStatus
+	advanced int
+}
+
+func (m *onDeleteRolloutEngine) AdvanceOnDeleteRollout(ctx context.Context, workload *application.Workload, policy *optipodv1alpha1.OptimizationPolicy) (*optipodv1alpha1.OnDeleteRolloutStatus, error) {
+	m.advanced++
+	return m.progress, nil
+}
+
+func (m *onDeleteRolloutEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+	result, err := m.recordingApplicationEngine.Apply(ctx, workload, changes, policy)
+	result.OnDeleteRollout = true
+	return result, err
+}
+
+// onDeleteSkippingEngine refuses every change as the OnDelete strategy would not roll it out
+type onDeleteSkippingEngine struct {
+	recordingApplicationEngine
+}
+
+func (m *onDeleteSkippingEngine) CanApply(ctx context.Context, workload *application.Workload, containerName string, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyDecision, error) {
+	return &application.ApplyDecision{
+		CanApply: false,
+		Method:   application.Skip,
+		Reason:   "DaemonSet uses the OnDelete update strategy, so its pods would keep their resources",
+		Cause:    application.ErrOnDeleteStrategy,
+	}, nil
+}
+
+func TestProcessWorkload_OnDeleteRollout(t *testing.T) {
+	deployment := createTestWorkload("app").Object.(*appsv1.Deployment)
+	workload := &discovery.Workload{
+		Kind:      KindDaemonSet,
+		Namespace: TestNamespace,
+		Name:      TestWorkloadName,
+		Object: &appsv1.DaemonSet{
+			ObjectMeta: deployment.ObjectMeta,
+			Spec: appsv1.DaemonSetSpec{
+				Template:       deployment.Spec.Template,
+				UpdateStrategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType},
+			},
+		},
+	}
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+
+	// Without the opt-in the change is skipped with the engine's reason
+	processor := createTestProcessor(&onDeleteSkippingEngine{}, nil)
+	status, err := processor.ProcessWorkload(context.Background(), workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusSkipped || !strings.Contains(status.Reason, "OnDelete update strategy") {
+		t.Errorf("status = %q (%s), want %q for the OnDelete strategy", status.Status, status.Reason, StatusSkipped)
+	}
+
+	// A rollout in progress holds back the next change
+	policy.Spec.UpdateStrategy.OnDeleteRollout = true
+	policy.Spec.UpdateStrategy.AllowRecreate = true
+	appEngine := &onDeleteRolloutEngine{progress: &optipodv1alpha1.OnDeleteRolloutStatus{UpdatedPods: 1, Pods: 3, DisruptionBlocked: true}}
+	processor = createTestProcessor(appEngine, nil)
+	status, err = processor.ProcessWorkload(context.Background(), workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusRollingOut || status.OnDeleteRollout == nil || status.OnDeleteRollout.UpdatedPods != 1 {
+		t.Errorf("status = %q with rollout %+v, want %q with 1 pod updated", status.Status, status.OnDeleteRollout, StatusRollingOut)
+	}
+	if !strings.Contains(status.Reason, "1 of 3 pods updated") || !strings.Contains(status.Reason, "PodDisruptionBudget") {
+		t.Errorf("reason = %q, want the rollout progress and the blocking disruption budget", status.Reason)
+	}
+	if len(appEngine.appliedContainers) != 0 {
+		t.Errorf("applied %v, want nothing during the rollout", appEngine.appliedContainers)
+	}
+
+	// Without a rollout in progress the change is applied and the pods are deleted afterwards
+	appEngine.progress = nil
+	status, err = processor.ProcessWorkload(context.Background(), workload, policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusApplied || !strings.Contains(status.Reason, "OnDelete update strategy") {
+		t.Errorf("status = %q (%s), want %q with the pods deleted one at a time", status.Status, status.Reason, StatusApplied)
+	}
+
+	// Deployments have no OnDelete strategy
+	advanced := appEngine.advanced
+	if _, err := processor.ProcessWorkload(context.Background(), createTestWorkload("app"), policy); err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if appEngine.advanced != advanced {
+		t.Error("AdvanceOnDeleteRollout() was called for a Deployment")
+	}
+}
+
+func TestProcessWorkload_ClampedToBound(t *testing.T) {
+	tests := []struct {
+		name        string
+		cpuBound    optipodv1alpha1.ResourceBound
+		kedaScaler  bool
+		wantClamped []string
+	}{
+		{
+			name:     "within bounds",
+			cpuBound: optipodv1alpha1.ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("2")},
+		},
+		{
+			name:        "clamped to the max bound",
+			cpuBound:    optipodv1alpha1.ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("200m")},
+			wantClamped: []string{optipodv1alpha1.BoundCPUMax},
+		},
+		{
+			name:        "clamped to the min bound",
+			cpuBound:    optipodv1alpha1.ResourceBound{Min: resource.MustParse("500m"), Max: resource.MustParse("2")},
+			wantClamped: []string{optipodv1alpha1.BoundCPUMin},
+		},
+		{
+			name:       "a KEDA hold above the min bound",
+			cpuBound:   optipodv1alpha1.ResourceBound{Min: resource.MustParse("500m"), Max: resource.MustParse("2")},
+			kedaScaler: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload := createTestWorkload(TestContainerName)
+			workload.Name = "clamp-" + strings.ReplaceAll(tt.name, " ", "-")
+			deployment := workload.Object.(*appsv1.Deployment)
+			deployment.Name = workload.Name
+			deployment.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("1"),
+			}
+			policy := createTestPolicy(optipodv1alpha1.ModeRecommend)
+			policy.Spec.ResourceBounds.CPU = tt.cpuBound
+
+			processor := createTestProcessor(&recordingApplicationEngine{}, nil)
+			if tt.kedaScaler {
+				processor.SetScaledObjectFinder(newTestScaledObjectFinder(true, newTestScaledObject("test-scaler", workload.Name, "cpu")))
+			}
+
+			status, err := processor.ProcessWorkload(context.Background(), workload, policy)
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			rec := findRecommendation(status.Recommendations, TestContainerName)
+			if rec == nil {
+				t.Fatalf("no recommendation for %s: %+v", TestContainerName, status.Recommendations)
+			}
+			if !slices.Equal(rec.ClampedToBound, tt.wantClamped) {
+				t.Errorf("ClampedToBound = %v, want %v", rec.ClampedToBound, tt.wantClamped)
+			}
+
+			for _, bound := range []string{optipodv1alpha1.BoundCPUMin, optipodv1alpha1.BoundCPUMax} {
+				want := 0.0
+				if slices.Contains(tt.wantClamped, bound) {
+					want = 1
+				}
+				counter := observability.RecommendationsClamped.WithLabelValues(policy.Name, workload.Namespace, workload.Name, workload.Kind, bound)
+				if got := testutil.ToFloat64(counter); got != want {
+					t.Errorf("optipod_recommendations_clamped_total{bound=%q} = %v, want %v", bound, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessWorkload_NoContainerMatchesSelectors(t *testing.T) {
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto,
+		optipodv1alpha1.ContainerSelector{Name: "app"},
+	)
+
+	metricsProvider := createTestMetricsProvider()
+	processor := NewWorkloadProcessor(metricsProvider, recommendation.NewEngine(), &recordingApplicationEngine{}, nil)
+
+	status, err := processor.ProcessWorkload(context.Background(), createTestWorkload("envoy"), policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	if status.Status != StatusSkipped {
+		t.Errorf("status = %q, want %q", status.Status, StatusSkipped)
+	}
+	if metricsProvider.getMetricsCalled {
+		t.Error("metrics should not be collected for containers matching no selector")
+	}
+}
+
+func TestProcessWorkload_ExcludeContainers(t *testing.T) {
+	tests := []struct {
+		name         string
+		exclude      []string
+		containers   []string
+		wantApplied  []string
+		wantExcluded []string
+		wantStatus   string
+	}{
+		{
+			name:         "sidecars excluded by exact name and glob",
+			exclude:      []string{"vault-agent", "linkerd-*"},
+			containers:   []string{"app", "vault-agent", "linkerd-proxy"},
+			wantApplied:  []string{"app"},
+			wantExcluded: []string{"vault-agent", "linkerd-proxy"},
+			wantStatus:   StatusApplied,
+		},
+		{
+			name:         "no container matches the exclusions",
+			exclude:      []string{"vault-agent"},
+			containers:   []string{"app", "worker"},
+			wantApplied:  []string{"app", "worker"},
+			wantExcluded: nil,
+			wantStatus:   StatusApplied,
+		},
+		{
+			name:         "every container excluded",
+			exclude:      []string{"*"},
+			containers:   []string{"app"},
+			wantApplied:  nil,
+			wantExcluded: []string{"app"},
+			wantStatus:   StatusSkipped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+			policy.Spec.ExcludeContainers = tt.exclude
+
+			appEngine := &recordingApplicationEngine{}
+			processor := createTestProcessor(appEngine, nil)
+
+			status, err := processor.ProcessWorkload(context.Background(), createTestWorkload(tt.containers...), policy)
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+
+			if status.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", status.Status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(appEngine.appliedContainers, tt.wantApplied) {
+				t.Errorf("applied containers = %v, want %v", appEngine.appliedContainers, tt.wantApplied)
+			}
+			if !reflect.DeepEqual(status.ExcludedContainers, tt.wantExcluded) {
+				t.Errorf("excluded containers = %v, want %v", status.ExcludedContainers, tt.wantExcluded)
+			}
+			for _, excluded := range tt.wantExcluded {
+				if findRecommendation(status.Recommendations, excluded) != nil {
+					t.Errorf("unexpected recommendation for excluded container %q", excluded)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessWorkload_InitContainers(t *testing.T) {
+	tests := []struct {
+		name                  string
+		includeInitContainers bool
+		wantApplied           []string
+	}{
+		{
+			name:        "native sidecars are sized, run-once init containers skipped",
+			wantApplied: []string{"app", "proxy"},
+		},
+		{
+			name:                  "run-once init containers sized when included",
+			includeInitContainers: true,
+			wantApplied:           []string{"app", "migrate", "proxy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload := createTestWorkload("app")
+			deployment := workload.Object.(*appsv1.Deployment)
+			deployment.Spec.Template.Spec.InitContainers = []corev1.Container{
+				{Name: "migrate", Image: "test:latest"},
+				{Name: "proxy", Image: "test:latest", RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways)},
+			}
+
+			policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+			policy.Spec.IncludeInitContainers = tt.includeInitContainers
+
+			appEngine := &recordingApplicationEngine{}
+			processor := createTestProcessor(appEngine, nil)
+
+			status, err := processor.ProcessWorkload(context.Background(), workload, policy)
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+
+			applied := slices.Sorted(slices.Values(appEngine.appliedContainers))
+			if !reflect.DeepEqual(applied, tt.wantApplied) {
+				t.Errorf("applied containers = %v, want %v", applied, tt.wantApplied)
+			}
+			if got := findRecommendation(status.Recommendations, "migrate") != nil; got != tt.includeInitContainers {
+				t.Errorf("recommendation for the run-once init container = %v, want %v", got, tt.includeInitContainers)
+			}
+		})
+	}
+}
+
+func TestProcessWorkload_EphemeralContainers(t *testing.T) {
+	workload := createTestWorkload("app")
+	deployment := workload.Object.(*appsv1.Deployment)
+	deployment.Spec.Template.Spec.EphemeralContainers = []corev1.EphemeralContainer{
+		{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "busybox"}},
+	}
+
+	appEngine := &recordingApplicationEngine{}
+	processor := createTestProcessor(appEngine, nil)
+
+	status, err := processor.ProcessWorkload(context.Background(), workload, createTestPolicy(optipodv1alpha1.ModeAuto))
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if findRecommendation(status.Recommendations, "debugger") != nil {
+		t.Error("the ephemeral container must not be sized")
+	}
+	if !slices.Equal(appEngine.appliedContainers, []string{"app"}) {
+		t.Errorf("applied containers = %v, want only app", appEngine.appliedContainers)
+	}
+}
+
+func TestGetWorkloadContext_StartupFloor(t *testing.T) {
+	workload := createTestWorkload(TestContainerName)
+	deployment := workload.Object.(*appsv1.Deployment)
+	deployment.CreationTimestamp = metav1.NewTime(time.Now().Add(-10 * time.Minute))
+
+	newPod := func(name string, restarts int32, started time.Duration) *corev1.Pod {
+		pod := createTestPod(name)
+		pod.UID = types.UID(name)
+		pod.Status.StartTime = &metav1.Time{Time: time.Now().Add(-started)}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: TestContainerName, RestartCount: restarts}}
+		return pod
+	}
+	otherPod := newPod("other", 100, time.Hour)
+	otherPod.Labels = map[string]string{"app": "other"}
+	// A long-running pod whose restarts all happened before the rolling window
+	oldPod := newPod("pod-old", 50, 30*24*time.Hour)
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(createTestScheme()).
+		WithObjects(newPod("pod-a", 2, time.Hour), newPod("pod-b", 3, time.Hour), oldPod, otherPod).
+		Build()
+	processor := createTestProcessor(&recordingApplicationEngine{}, fakeClient)
+
+	cpu := resource.MustParse("1")
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+
+	// Without a startup floor the age is not looked up, restarts still are for the stability score
+	workloadContext, restarts := processor.getWorkloadContext(context.Background(), workload, policy)
+	if workloadContext.Age != nil || restarts[TestContainerName] != 5 {
+		t.Errorf("expected no age and 5 restarts without a startup floor, got %+v and %v", workloadContext, restarts)
+	}
+
+	policy.Spec.StartupFloor = &optipodv1alpha1.StartupFloor{CPU: &cpu, RestartThreshold: 3}
+	workloadContext, restarts = processor.getWorkloadContext(context.Background(), workload, policy)
+	if workloadContext.Age == nil || *workloadContext.Age < 10*time.Minute {
+		t.Errorf("expected workload age of at least 10m, got %v", workloadContext.Age)
+	}
+	// Only the restarts within the rolling window count, not the cumulative restart counts
+	if restarts[TestContainerName] != 5 {
+		t.Errorf("expected 5 restarts within the window summed across the workload's pods, got %d", restarts[TestContainerName])
+	}
+}
+
+// cancellingApplicationEngine cancels the reconcile context during the first apply, simulating a
+// manager shutdown or leadership loss mid-apply
+type cancellingApplicationEngine struct {
+	recordingApplicationEngine
+	cancel           context.CancelFunc
+	cancelledApplies int
+}
+
+func (m *cancellingApplicationEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+	m.cancel()
+	if ctx.Err() != nil {
+		m.cancelledApplies++
+	}
+	return m.recordingApplicationEngine.Apply(ctx, workload, changes, policy)
+}
+
+func TestProcessWorkload_CancelledMidApply(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	appEngine := &cancellingApplicationEngine{cancel: cancel}
+	processor := createTestProcessor(appEngine, nil)
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+
+	status, err := processor.ProcessWorkload(ctx, createTestWorkload("app", "sidecar"), policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	// Every container is applied even though the context was cancelled after the first one,
+	// so the workload is never left partially patched
+	if !reflect.DeepEqual(appEngine.appliedContainers, []string{"app", "sidecar"}) {
+		t.Errorf("applied containers = %v, want [app sidecar]", appEngine.appliedContainers)
+	}
+	if appEngine.cancelledApplies != 0 {
+		t.Errorf("%d apply calls received a cancelled context, want none", appEngine.cancelledApplies)
+	}
+	if status.Status != StatusApplied || status.LastApplied == nil {
+		t.Errorf("status = %q with last applied %v, want %q with a timestamp", status.Status, status.LastApplied, StatusApplied)
+	}
+}
+
+func TestProcessWorkload_CancelledBeforeApply(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	appEngine := &recordingApplicationEngine{}
+	processor := createTestProcessor(appEngine, nil)
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+
+	status, err := processor.ProcessWorkload(ctx, createTestWorkload("app", "sidecar"), policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	// Nothing is applied once the context is cancelled, so the workload is left untouched
+	if len(appEngine.appliedContainers) != 0 {
+		t.Errorf("applied containers = %v, want none", appEngine.appliedContainers)
+	}
+	if status.Status != StatusSkipped || status.LastApplied != nil {
+		t.Errorf("status = %q with last applied %v, want %q without a timestamp", status.Status, status.LastApplied, StatusSkipped)
+	}
+}
+
+func TestProcessWorkload_ResourceNotOptimized(t *testing.T) {
+	appEngine := &recordingApplicationEngine{}
+	processor := createTestProcessor(appEngine, nil)
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	optimizeMemory := false
+	policy.Spec.OptimizeMemory = &optimizeMemory
+
+	status, err := processor.ProcessWorkload(context.Background(), createTestWorkload("app"), policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	// Memory has no recommendation, so it is neither reported nor applied
+	if len(status.Recommendations) != 1 || status.Recommendations[0].CPU == nil || status.Recommendations[0].Memory != nil {
+		t.Fatalf("recommendations = %+v, want a CPU recommendation only", status.Recommendations)
+	}
+	if status.Status != StatusApplied || len(appEngine.appliedContainers) != 1 {
+		t.Errorf("status = %q with applied containers %v, want the CPU recommendation applied",
+			status.Status, appEngine.appliedContainers)
+	}
+}
+
+// convergingApplicationEngine applies half of every recommendation, as a convergence rate would
+type convergingApplicationEngine struct {
+	recordingApplicationEngine
+}
+
+func (m *convergingApplicationEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+	result := &application.ApplyResult{Method: "ServerSideApply", Containers: make(map[string]application.ContainerResult)}
+	for _, change := range changes {
+		rec := change.Recommendation
+		result.Containers[change.Container] = application.ContainerResult{
+			Applied: &recommendation.Recommendation{
+				CPU:    *resource.NewMilliQuantity(rec.CPU.MilliValue()/2, resource.DecimalSI),
+				Memory: *resource.NewQuantity(rec.Memory.Value()/2, resource.BinarySI),
+			},
+			Converged: false,
+		}
+	}
+	return result, nil
+}
+
+func TestProcessWorkload_ConvergenceProgress(t *testing.T) {
+	processor := createTestProcessor(&convergingApplicationEngine{}, nil)
+	policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+	rate := 0.5
+	policy.Spec.UpdateStrategy.ConvergenceRate = &rate
+
+	status, err := processor.ProcessWorkload(context.Background(), createTestWorkload("app"), policy)
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	if len(status.Recommendations) != 1 {
+		t.Fatalf("recommendations = %+v, want one", status.Recommendations)
+	}
+	rec := status.Recommendations[0]
+	if !rec.Converging || rec.AppliedCPU == nil || rec.AppliedMemory == nil {
+		t.Fatalf("recommendation = %+v, want converging with applied requests", rec)
+	}
+	if rec.AppliedCPU.MilliValue() != rec.CPU.MilliValue()/2 {
+		t.Errorf("applied CPU = %s, want half of the recommendation %s", rec.AppliedCPU.String(), rec.CPU.String())
+	}
+	if status.Status != StatusApplied || !strings.Contains(status.Reason, "converging") {
+		t.Errorf("status = %q (%s), want Applied while converging", status.Status, status.Reason)
+	}
+}
+
+// invalidUpdateMethodApplicationEngine reports an invalid update method annotation on every container
+type invalidUpdateMethodApplicationEngine struct {
+	recordingApplicationEngine
+}
+
+func (m *invalidUpdateMethodApplicationEngine) CanApply(ctx context.Context, workload *application.Workload, containerName string, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyDecision, error) {
+	decision, err := m.recordingApplicationEngine.CanApply(ctx, workload, containerName, rec, policy)
+	decision.InvalidUpdateMethod = "rolling"
+	return decision, err
+}
+
+func TestProcessWorkload_InvalidUpdateMethodEvent(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	appEngine := &invalidUpdateMethodApplicationEngine{}
+	processor := createTestProcessor(appEngine, nil)
+	processor.SetEventRecorder(observability.NewEventRecorder(recorder))
+
+	status, err := processor.ProcessWorkload(context.Background(), createTestWorkload("app", "sidecar"),
+		createTestPolicy(optipodv1alpha1.ModeAuto))
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+
+	// The policy's update strategy is used and the invalid value is reported once per workload
+	if status.Status != StatusApplied || len(appEngine.appliedContainers) != 2 {
+		t.Errorf("status = %q with applied %v, want both containers applied", status.Status, appEngine.appliedContainers)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("recorded %d events, want 1", len(recorder.Events))
+	}
+	if event := <-recorder.Events; !strings.Contains(event, observability.EventReasonInvalidUpdateMethod) ||
+		!strings.Contains(event, "rolling") {
+		t.Errorf("event = %q, want an %s warning naming the value", event, observability.EventReasonInvalidUpdateMethod)
+	}
+}
+
+// unsafeDecreaseApplicationEngine skips every container for an unsafe memory decrease
+type unsafeDecreaseApplicationEngine struct {
+	recordingApplicationEngine
+}
+
+func (m *unsafeDecreaseApplicationEngine) CanApply(ctx context.Context, workload *application.Workload, containerName string, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyDecision, error) {
+	return &application.ApplyDecision{
+		Method: application.Skip,
+		Reason: "Memory decrease could cause pod eviction or OOM",
+		Cause:  application.ErrUnsafeMemoryDecrease,
+	}, nil
+}
+
+func TestProcessWorkload_SkipEventNamesCause(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	appEngine := &unsafeDecreaseApplicationEngine{}
+	processor := createTestProcessor(appEngine, nil)
+	processor.SetEventRecorder(observability.NewEventRecorder(recorder))
+
+	status, err := processor.ProcessWorkload(context.Background(), createTestWorkload(TestContainerName),
+		createTestPolicy(optipodv1alpha1.ModeAuto))
+	if err != nil {
+		t.Fatalf("ProcessWorkload() error = %v", err)
+	}
+	if status.Status != StatusSkipped || len(appEngine.appliedContainers) != 0 {
+		t.Errorf("status = %q with applied %v, want skipped", status.Status, appEngine.appliedContainers)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("recorded %d events, want 1", len(recorder.Events))
+	}
+	if event := <-recorder.Events; !strings.HasPrefix(event, "Normal UnsafeMemoryDecrease ") ||
+		!strings.Contains(event, "eviction or OOM") {
+		t.Errorf("event = %q, want an UnsafeMemoryDecrease event with the skip reason", event)
+	}
+}
+
+func TestProcessWorkload_MinStabilityScore(t *testing.T) {
+	// The test metrics have a P90 twice their median, which scores 22
+	tests := []struct {
+		name        string
+		minScore    *int32
+		wantStatus  string
+		wantApplied int
+	}{
+		{name: "no minimum", wantStatus: StatusApplied, wantApplied: 1},
+		{name: "score above the minimum", minScore: ptr.To[int32](20), wantStatus: StatusApplied, wantApplied: 1},
+		{name: "score below the minimum", minScore: ptr.To[int32](50), wantStatus: StatusRecommended},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createTestPolicy(optipodv1alpha1.ModeAuto)
+			policy.Spec.MinStabilityScore = tt.minScore
+			appEngine := &recordingApplicationEngine{}
+			processor := createTestProcessor(appEngine, nil)
+
+			status, err := processor.ProcessWorkload(context.Background(), createTestWorkload(TestContainerName), policy)
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			if status.StabilityScore == nil || *status.StabilityScore != 22 {
+				t.Errorf("stability score = %v, want 22", status.StabilityScore)
+			}
+			if status.Status != tt.wantStatus || len(appEngine.appliedContainers) != tt.wantApplied {
+				t.Errorf("status = %s (%s) with applied %v, want %s with %d applied",
+					status.Status, status.Reason, appEngine.appliedContainers, tt.wantStatus, tt.wantApplied)
+			}
+		})
+	}
+}
+
+// batchRecordingApplicationEngine records the containers of each apply call and refuses to
+// apply the container named in refuse
+type batchRecordingApplicationEngine struct {
+	recordingApplicationEngine
+	refuse string
+	calls  [][]string
+}
+
+func (m *batchRecordingApplicationEngine) CanApply(ctx context.Context, workload *application.Workload, containerName string, rec *recommendation.Recommendation, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyDecision, error) {
+	if containerName == m.refuse {
+		return &application.ApplyDecision{CanApply: false, Method: application.Skip, Reason: "refused"}, nil
+	}
+	return m.recordingApplicationEngine.CanApply(ctx, workload, containerName, rec, policy)
+}
+
+func (m *batchRecordingApplicationEngine) Apply(ctx context.Context, workload *application.Workload, changes []application.ContainerChange, policy *optipodv1alpha1.OptimizationPolicy) (*application.ApplyResult, error) {
+	containers := make([]string, 0, len(changes))
+	for _, change := range changes {
+		containers = append(containers, change.Container)
+	}
+	m.calls = append(m.calls, containers)
+	return m.recordingApplicationEngine.Apply(ctx, workload, changes, policy)
+}
+
+func TestProcessWorkload_AppliesContainersTogether(t *testing.T) {
+	tests := []struct {
+		name       string
+		refuse     string
+		wantStatus string
+		wantCalls  [][]string
+	}{
+		{
+			name:       "all containers in one apply",
+			wantStatus: StatusApplied,
+			wantCalls:  [][]string{{"app", "sidecar"}},
+		},
+		{
+			name:       "a container that cannot be applied leaves the others untouched",
+			refuse:     "sidecar",
+			wantStatus: StatusSkipped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appEngine := &batchRecordingApplicationEngine{refuse: tt.refuse}
+			processor := createTestProcessor(appEngine, nil)
+
+			status, err := processor.ProcessWorkload(context.Background(), createTestWorkload("app", "sidecar"),
+				createTestPolicy(optipodv1alpha1.ModeAuto))
+			if err != nil {
+				t.Fatalf("ProcessWorkload() error = %v", err)
+			}
+			if status.Status != tt.wantStatus || !reflect.DeepEqual(appEngine.calls, tt.wantCalls) {
+				t.Errorf("status = %s with apply calls %v, want %s with %v", status.Status, appEngine.calls, tt.wantStatus, tt.wantCalls)
+			}
+		})
+	}
+}
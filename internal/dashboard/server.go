@@ -0,0 +1,416 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+const (
+	// PathPrefix is the path the API is served under
+	PathPrefix = "/dashboard/v1/"
+
+	// DefaultPageSize is the number of items per page when the request sets no limit
+	DefaultPageSize = 100
+
+	// MaxPageSize is the largest page a request may ask for
+	MaxPageSize = 1000
+)
+
+// errInvalidContinue is returned for a continue token that was not issued by the API
+var errInvalidContinue = errors.New("invalid continue token")
+
+// WorkloadCounts are the workload counts of a policy's last reconciliation
+type WorkloadCounts struct {
+	Discovered      int `json:"discovered"`
+	Processed       int `json:"processed"`
+	Applied         int `json:"applied"`
+	Skipped         int `json:"skipped"`
+	PendingApproval int `json:"pendingApproval"`
+}
+
+// Policy is an OptimizationPolicy with its status and the savings of its workloads
+type Policy struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Mode      string `json:"mode"`
+	Phase     string `json:"phase,omitempty"`
+
+	// Ready is the message of the policy's Ready condition
+	Ready string `json:"ready,omitempty"`
+
+	Workloads          WorkloadCounts `json:"workloads"`
+	LastReconciliation *metav1.Time   `json:"lastReconciliation,omitempty"`
+	NextReconciliation *metav1.Time   `json:"nextReconciliation,omitempty"`
+
+	// Savings are the total savings of the policy's workloads
+	Savings Savings `json:"savings"`
+}
+
+// List is a page of items. Continue is set when there are more items; passing it back as the
+// continue query parameter returns the next page.
+type List[T any] struct {
+	Items    []T    `json:"items"`
+	Total    int    `json:"total"`
+	Continue string `json:"continue,omitempty"`
+}
+
+// Recommendation traces how the recommendation of one container was computed, from the usage
+// samples to the recommended requests
+type Recommendation struct {
+	Policy    PolicyRef `json:"policy"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Container string    `json:"container"`
+
+	// Recommended are the recommended requests, after clamping to the resource bounds
+	Recommended    Resources `json:"recommended"`
+	ClampedToBound []string  `json:"clampedToBound,omitempty"`
+
+	// DataQuality holds the samples, window coverage, percentile values and safety factor
+	DataQuality *optipodv1alpha1.RecommendationDataQuality `json:"dataQuality,omitempty"`
+	Explanation string                                     `json:"explanation,omitempty"`
+
+	// Accuracy compares the usage since the last applied change with the container's requests
+	Accuracy *optipodv1alpha1.RecommendationAccuracy `json:"accuracy,omitempty"`
+
+	// ObservedAt is when the workload was last processed
+	ObservedAt metav1.Time `json:"observedAt"`
+}
+
+// errorResponse is the body of a failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler serves the dashboard API:
+//
+//	GET /dashboard/v1/policies   policies with their status and savings
+//	GET /dashboard/v1/workloads  workloads with current, recommended and applied requests
+//	GET /dashboard/v1/history    recently applied changes, newest first
+//	GET /dashboard/v1/debug/recommendations
+//	                             per container, the metrics and computation behind the recommendation
+//
+// Every endpoint accepts the namespace and policy query parameters to filter, and limit and
+// continue to paginate. namespace is the namespace of the policy for policies and of the
+// workload otherwise. policy is a policy name, or namespace/name for a single policy.
+// debug/recommendations also accepts container, to preview the recommendations of the
+// containers of that name only.
+//
+// The handler is read-only and unauthenticated; it is meant to be served behind the
+// authenticating metrics server.
+type Handler struct {
+	reader client.Reader
+	store  *Store
+	mux    *http.ServeMux
+}
+
+// NewHandler creates a handler serving policies read through reader and the processing results
+// kept in store
+func NewHandler(reader client.Reader, store *Store) *Handler {
+	h := &Handler{reader: reader, store: store, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET "+PathPrefix+"policies", h.servePolicies)
+	h.mux.HandleFunc("GET "+PathPrefix+"workloads", h.serveWorkloads)
+	h.mux.HandleFunc("GET "+PathPrefix+"history", h.serveHistory)
+	h.mux.HandleFunc("GET "+PathPrefix+"debug/recommendations", h.serveRecommendations)
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) servePolicies(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	policies, err := h.listPolicies(r, q.namespace)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	savings := make(map[PolicyRef]Savings)
+	for _, workload := range h.store.Workloads() {
+		total, ok := savings[workload.Policy]
+		if !ok {
+			total = NewSavings()
+		}
+		total.Add(workload.Savings)
+		savings[workload.Policy] = total
+	}
+
+	items := make([]Policy, 0, len(policies))
+	for _, pol := range policies {
+		ref := PolicyRef{Namespace: pol.Namespace, Name: pol.Name}
+		if !q.matchesPolicy(ref) {
+			continue
+		}
+		view := Policy{
+			Namespace: pol.Namespace,
+			Name:      pol.Name,
+			Mode:      string(pol.Spec.Mode),
+			Phase:     string(pol.Status.Phase),
+			Workloads: WorkloadCounts{
+				Discovered:      pol.Status.WorkloadsDiscovered,
+				Processed:       pol.Status.WorkloadsProcessed,
+				Applied:         pol.Status.WorkloadsApplied,
+				Skipped:         pol.Status.WorkloadsSkipped,
+				PendingApproval: pol.Status.WorkloadsPendingApproval,
+			},
+			LastReconciliation: pol.Status.LastReconciliation,
+			NextReconciliation: pol.Status.NextReconciliation,
+			Savings:            NewSavings(),
+		}
+		if ready := meta.FindStatusCondition(pol.Status.Conditions, "Ready"); ready != nil {
+			view.Ready = ready.Message
+		}
+		if total, ok := savings[ref]; ok {
+			view.Savings = total
+		}
+		items = append(items, view)
+	}
+
+	writePage(w, items, func(p Policy) string { return pageKey(p.Namespace, p.Name) }, q)
+}
+
+func (h *Handler) serveWorkloads(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	items, err := h.listWorkloads(r, q)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writePage(w, items, func(wl Workload) string { return pageKey(wl.Namespace, wl.Kind, wl.Name) }, q)
+}
+
+func (h *Handler) serveRecommendations(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	workloads, err := h.listWorkloads(r, q)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	containerName := r.URL.Query().Get("container")
+	items := make([]Recommendation, 0)
+	for _, workload := range workloads {
+		for _, container := range workload.Containers {
+			if containerName != "" && container.Name != containerName {
+				continue
+			}
+			items = append(items, Recommendation{
+				Policy:         workload.Policy,
+				Kind:           workload.Kind,
+				Namespace:      workload.Namespace,
+				Name:           workload.Name,
+				Container:      container.Name,
+				Recommended:    container.Recommended,
+				ClampedToBound: container.ClampedToBound,
+				DataQuality:    container.DataQuality,
+				Explanation:    container.Explanation,
+				Accuracy:       container.Accuracy,
+				ObservedAt:     workload.ObservedAt,
+			})
+		}
+	}
+	if containerName != "" && len(items) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no workload has a container named %q", containerName))
+		return
+	}
+
+	writePage(w, items, func(rec Recommendation) string {
+		return pageKey(rec.Namespace, rec.Kind, rec.Name, rec.Container)
+	}, q)
+}
+
+func (h *Handler) serveHistory(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	items := make([]Change, 0)
+	for _, change := range h.store.History() {
+		if q.matchesNamespace(change.Namespace) && q.matchesPolicy(change.Policy) {
+			items = append(items, change)
+		}
+	}
+
+	// Changes are newest first, so the key must grow as the sequence falls
+	writePage(w, items, func(c Change) string { return fmt.Sprintf("%019d", math.MaxInt64-c.Sequence) }, q)
+}
+
+// listWorkloads returns the stored workloads passing the query's filters. Workloads of deleted
+// policies are left out.
+func (h *Handler) listWorkloads(r *http.Request, q query) ([]Workload, error) {
+	policies, err := h.listPolicies(r, "")
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[PolicyRef]bool, len(policies))
+	for _, pol := range policies {
+		exists[PolicyRef{Namespace: pol.Namespace, Name: pol.Name}] = true
+	}
+
+	items := make([]Workload, 0)
+	for _, workload := range h.store.Workloads() {
+		if exists[workload.Policy] && q.matchesNamespace(workload.Namespace) && q.matchesPolicy(workload.Policy) {
+			items = append(items, workload)
+		}
+	}
+	return items, nil
+}
+
+// listPolicies lists the policies in namespace, or in all namespaces when it is empty
+func (h *Handler) listPolicies(r *http.Request, namespace string) ([]optipodv1alpha1.OptimizationPolicy, error) {
+	list := &optipodv1alpha1.OptimizationPolicyList{}
+	if err := h.reader.List(r.Context(), list, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+	return list.Items, nil
+}
+
+// query holds the filter and pagination parameters of a request
+type query struct {
+	namespace string
+	policy    string
+	limit     int
+	token     string
+}
+
+// parseQuery reads the filter and pagination parameters of a request
+func parseQuery(r *http.Request) (query, error) {
+	values := r.URL.Query()
+	q := query{
+		namespace: values.Get("namespace"),
+		policy:    values.Get("policy"),
+		limit:     DefaultPageSize,
+		token:     values.Get("continue"),
+	}
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > MaxPageSize {
+			return q, fmt.Errorf("limit must be a number from 1 to %d, got %q", MaxPageSize, limit)
+		}
+		q.limit = n
+	}
+	return q, nil
+}
+
+// matchesNamespace reports whether a workload namespace passes the namespace filter
+func (q query) matchesNamespace(namespace string) bool {
+	return q.namespace == "" || q.namespace == namespace
+}
+
+// matchesPolicy reports whether a policy passes the policy filter, which is a policy name or
+// namespace/name
+func (q query) matchesPolicy(ref PolicyRef) bool {
+	if q.policy == "" {
+		return true
+	}
+	if namespace, name, ok := strings.Cut(q.policy, "/"); ok {
+		return ref.Namespace == namespace && ref.Name == name
+	}
+	return ref.Name == q.policy
+}
+
+// writePage writes the page of items the query asks for. Items are sorted by key first; the
+// continue token is the key of the last item returned, so pages stay consistent while items
+// are added or removed.
+func writePage[T any](w http.ResponseWriter, items []T, key func(T) string, q query) {
+	page, err := paginate(items, key, q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// paginate sorts items by key and returns the page of them after the continue token of the query
+func paginate[T any](items []T, key func(T) string, q query) (List[T], error) {
+	slices.SortStableFunc(items, func(a, b T) int { return strings.Compare(key(a), key(b)) })
+
+	start := 0
+	if q.token != "" {
+		after, err := base64.RawURLEncoding.DecodeString(q.token)
+		if err != nil {
+			return List[T]{}, errInvalidContinue
+		}
+		start = sort.Search(len(items), func(i int) bool { return key(items[i]) > string(after) })
+	}
+	end := min(start+q.limit, len(items))
+
+	page := List[T]{Items: items[start:end], Total: len(items)}
+	if end < len(items) {
+		page.Continue = base64.RawURLEncoding.EncodeToString([]byte(key(items[end-1])))
+	}
+	return page, nil
+}
+
+// pageKey joins the fields items are ordered by into a pagination key. The separator sorts
+// before any character of a name, so keys order like their fields, e.g. namespace "a" before "a-b".
+func pageKey(fields ...string) string {
+	return strings.Join(fields, "\x00")
+}
+
+// writeError writes an error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeJSON writes body as a JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logf.Log.WithName("dashboard").Error(err, "Failed to write response")
+	}
+}
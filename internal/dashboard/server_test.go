@@ -0,0 +1,344 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// newTestHandler returns a handler over a store holding the given workloads and two policies,
+// team-a/web and team-b/batch
+func newTestHandler(t *testing.T, store *Store) *Handler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := optipodv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	web := &optipodv1alpha1.OptimizationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "web"},
+		Spec:       optipodv1alpha1.OptimizationPolicySpec{Mode: optipodv1alpha1.ModeAuto},
+		Status: optipodv1alpha1.OptimizationPolicyStatus{
+			Phase:               optipodv1alpha1.PolicyPhaseActive,
+			WorkloadsDiscovered: 2,
+			Conditions: []metav1.Condition{{
+				Type: "Ready", Status: metav1.ConditionTrue, Reason: "Reconciled", Message: "2 workloads processed",
+			}},
+		},
+	}
+	batch := &optipodv1alpha1.OptimizationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "batch"},
+		Spec:       optipodv1alpha1.OptimizationPolicySpec{Mode: optipodv1alpha1.ModeRecommend},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(web, batch).Build()
+	return NewHandler(reader, store)
+}
+
+// newTestWorkload returns a workload of a policy saving the given CPU and memory
+func newTestWorkload(policy PolicyRef, namespace, name, cpuSaved, memorySaved string) Workload {
+	return Workload{
+		Policy:    policy,
+		Kind:      "Deployment",
+		Namespace: namespace,
+		Name:      name,
+		Replicas:  1,
+		Status:    "Recommended",
+		Savings:   Savings{CPU: resource.MustParse(cpuSaved), Memory: resource.MustParse(memorySaved)},
+	}
+}
+
+// get serves a request and decodes the response into body, returning the status code
+func get(t *testing.T, handler http.Handler, path string, body interface{}) int {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	if err := json.Unmarshal(recorder.Body.Bytes(), body); err != nil {
+		t.Fatalf("failed to decode %s response %q: %v", path, recorder.Body.String(), err)
+	}
+	return recorder.Code
+}
+
+func TestStore_Record(t *testing.T) {
+	store := NewStore(time.Hour, 2)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	web := PolicyRef{Namespace: "team-a", Name: "web"}
+	appliedAt := metav1.NewTime(now)
+	cpu := resource.MustParse("250m")
+	workload := newTestWorkload(web, "team-a", "api", "0", "0")
+	workload.LastApplied = &appliedAt
+	workload.Containers = []Container{{Name: "app", Applied: &Resources{CPU: &cpu}}}
+	store.Record(workload)
+
+	// A later result without a change keeps the last applied change
+	next := newTestWorkload(web, "team-a", "api", "0", "0")
+	next.Containers = []Container{{Name: "app"}}
+	store.Record(next)
+	workloads := store.Workloads()
+	if len(workloads) != 1 || workloads[0].LastApplied == nil || workloads[0].Containers[0].Applied == nil {
+		t.Fatalf("workloads = %+v, want the last applied change kept", workloads)
+	}
+
+	// Workloads not processed within the retention are dropped
+	now = now.Add(2 * time.Hour)
+	if workloads := store.Workloads(); len(workloads) != 0 {
+		t.Errorf("workloads = %+v, want none after the retention", workloads)
+	}
+
+	for _, name := range []string{"a", "b", "c"} {
+		store.RecordChange(Change{Policy: web, Kind: "Deployment", Namespace: "team-a", Name: name})
+	}
+	history := store.History()
+	if len(history) != 2 || history[0].Name != "c" || history[1].Name != "b" {
+		t.Errorf("history = %+v, want the two newest changes, newest first", history)
+	}
+
+	var nilStore *Store
+	nilStore.Record(workload)
+	nilStore.RecordChange(Change{})
+}
+
+func TestHandler_Policies(t *testing.T) {
+	store := NewStore(time.Hour, 10)
+	web := PolicyRef{Namespace: "team-a", Name: "web"}
+	store.Record(newTestWorkload(web, "team-a", "api", "500m", "1Gi"))
+	store.Record(newTestWorkload(web, "team-a", "worker", "-100m", "512Mi"))
+	handler := newTestHandler(t, store)
+
+	var list List[Policy]
+	if code := get(t, handler, "/dashboard/v1/policies", &list); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if list.Total != 2 || len(list.Items) != 2 {
+		t.Fatalf("policies = %+v, want both policies", list)
+	}
+
+	// Policies are ordered by namespace and name
+	got := list.Items[0]
+	if got.Name != "web" || got.Mode != "Auto" || got.Phase != "Active" || got.Ready != "2 workloads processed" ||
+		got.Workloads.Discovered != 2 {
+		t.Errorf("policy = %+v, want web with its status", got)
+	}
+	if got.Savings.CPU.Cmp(resource.MustParse("400m")) != 0 || got.Savings.Memory.Cmp(resource.MustParse("1536Mi")) != 0 {
+		t.Errorf("savings = %s CPU and %s memory, want 400m and 1536Mi",
+			got.Savings.CPU.String(), got.Savings.Memory.String())
+	}
+	if list.Items[1].Savings.CPU.Sign() != 0 {
+		t.Errorf("savings of a policy without workloads = %s, want 0", list.Items[1].Savings.CPU.String())
+	}
+
+	var teamB List[Policy]
+	if get(t, handler, "/dashboard/v1/policies?namespace=team-b", &teamB); len(teamB.Items) != 1 || teamB.Items[0].Name != "batch" {
+		t.Errorf("policies in team-b = %+v, want batch only", teamB.Items)
+	}
+}
+
+func TestHandler_WorkloadsFilterAndPaginate(t *testing.T) {
+	store := NewStore(time.Hour, 10)
+	web := PolicyRef{Namespace: "team-a", Name: "web"}
+	batch := PolicyRef{Namespace: "team-b", Name: "batch"}
+	store.Record(newTestWorkload(web, "team-a", "api", "0", "0"))
+	store.Record(newTestWorkload(web, "team-a", "frontend", "0", "0"))
+	store.Record(newTestWorkload(web, "team-c", "worker", "0", "0"))
+	store.Record(newTestWorkload(batch, "team-b", "job", "0", "0"))
+	store.Record(newTestWorkload(PolicyRef{Namespace: "team-a", Name: "deleted"}, "team-a", "old", "0", "0"))
+	handler := newTestHandler(t, store)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "/dashboard/v1/workloads", want: []string{"api", "frontend", "job", "worker"}},
+		{path: "/dashboard/v1/workloads?namespace=team-a", want: []string{"api", "frontend"}},
+		{path: "/dashboard/v1/workloads?policy=web", want: []string{"api", "frontend", "worker"}},
+		{path: "/dashboard/v1/workloads?policy=team-b/batch", want: []string{"job"}},
+		{path: "/dashboard/v1/workloads?policy=team-a/batch", want: []string{}},
+	}
+	for _, tt := range tests {
+		var list List[Workload]
+		get(t, handler, tt.path, &list)
+		names := make([]string, 0, len(list.Items))
+		for _, workload := range list.Items {
+			names = append(names, workload.Name)
+		}
+		if len(names) != len(tt.want) || list.Total != len(tt.want) {
+			t.Errorf("%s = %v, want %v", tt.path, names, tt.want)
+			continue
+		}
+		for i := range names {
+			if names[i] != tt.want[i] {
+				t.Errorf("%s = %v, want %v", tt.path, names, tt.want)
+				break
+			}
+		}
+	}
+
+	// Pages follow each other through the continue token
+	var names []string
+	path := "/dashboard/v1/workloads?limit=3"
+	for pages := 0; path != ""; pages++ {
+		if pages > 2 {
+			t.Fatal("pagination did not end")
+		}
+		var list List[Workload]
+		get(t, handler, path, &list)
+		for _, workload := range list.Items {
+			names = append(names, workload.Name)
+		}
+		path = ""
+		if list.Continue != "" {
+			path = "/dashboard/v1/workloads?limit=3&continue=" + list.Continue
+		}
+	}
+	if len(names) != 4 || names[0] != "api" || names[3] != "worker" {
+		t.Errorf("paginated workloads = %v, want all four once", names)
+	}
+}
+
+func TestHandler_History(t *testing.T) {
+	store := NewStore(time.Hour, 10)
+	web := PolicyRef{Namespace: "team-a", Name: "web"}
+	for _, name := range []string{"first", "second", "third"} {
+		store.RecordChange(Change{Policy: web, Kind: "Deployment", Namespace: "team-a", Name: name})
+	}
+	handler := newTestHandler(t, store)
+
+	var page List[Change]
+	get(t, handler, "/dashboard/v1/history?limit=2", &page)
+	if len(page.Items) != 2 || page.Items[0].Name != "third" || page.Continue == "" {
+		t.Fatalf("first page = %+v, want the two newest changes and a continue token", page)
+	}
+	var next List[Change]
+	get(t, handler, "/dashboard/v1/history?limit=2&continue="+page.Continue, &next)
+	if len(next.Items) != 1 || next.Items[0].Name != "first" || next.Continue != "" {
+		t.Errorf("second page = %+v, want the oldest change only", next)
+	}
+}
+
+func TestHandler_DebugRecommendations(t *testing.T) {
+	store := NewStore(time.Hour, 10)
+	web := PolicyRef{Namespace: "team-a", Name: "web"}
+	cpu := resource.MustParse("120m")
+	quality := &optipodv1alpha1.RecommendationDataQuality{
+		Percentile:   "P90 percentile",
+		SafetyFactor: 1.2,
+		CPU:          &optipodv1alpha1.ResourceDataQuality{Samples: 2880, WindowCoveragePercent: 100, PercentileValue: &cpu},
+	}
+
+	// The containers of "api" come before "api-2", whatever order they are recorded in
+	api := newTestWorkload(web, "team-a", "api", "0", "0")
+	api.Containers = []Container{{Name: "sidecar"}, {Name: "app", DataQuality: quality, ClampedToBound: []string{"CPUMin"}}}
+	store.Record(api)
+	api2 := newTestWorkload(web, "team-a", "api-2", "0", "0")
+	api2.Containers = []Container{{Name: "app"}}
+	store.Record(api2)
+	handler := newTestHandler(t, store)
+
+	var page List[Recommendation]
+	get(t, handler, "/dashboard/v1/debug/recommendations?limit=2", &page)
+	if page.Total != 3 || len(page.Items) != 2 || page.Continue == "" {
+		t.Fatalf("first page = %+v, want 2 of 3 containers and a continue token", page)
+	}
+	if page.Items[0].Name != "api" || page.Items[0].Container != "app" || page.Items[1].Name != "api" ||
+		page.Items[1].Container != "sidecar" {
+		t.Errorf("first page = %+v, want api/app then api/sidecar", page.Items)
+	}
+	got := page.Items[0].DataQuality
+	if got == nil || got.CPU == nil || got.CPU.Samples != 2880 || got.CPU.PercentileValue.Cmp(cpu) != 0 ||
+		len(page.Items[0].ClampedToBound) != 1 {
+		t.Errorf("api/app = %+v, want its data quality and clamped bound", page.Items[0])
+	}
+
+	var next List[Recommendation]
+	get(t, handler, "/dashboard/v1/debug/recommendations?limit=2&continue="+page.Continue, &next)
+	if len(next.Items) != 1 || next.Items[0].Name != "api-2" || next.Continue != "" {
+		t.Errorf("second page = %+v, want api-2/app only", next)
+	}
+
+	// A container name previews that container of every workload only
+	var app List[Recommendation]
+	get(t, handler, "/dashboard/v1/debug/recommendations?container=app", &app)
+	if app.Total != 2 || app.Items[0].Name != "api" || app.Items[1].Name != "api-2" ||
+		app.Items[0].Container != "app" || app.Items[1].Container != "app" {
+		t.Errorf("container=app = %+v, want api/app and api-2/app", app)
+	}
+
+	var body errorResponse
+	if code := get(t, handler, "/dashboard/v1/debug/recommendations?container=db", &body); code != http.StatusNotFound ||
+		body.Error == "" {
+		t.Errorf("container=db = %d %q, want 404 with an error", code, body.Error)
+	}
+}
+
+func TestHandler_InvalidRequests(t *testing.T) {
+	handler := newTestHandler(t, NewStore(time.Hour, 10))
+
+	for _, path := range []string{
+		"/dashboard/v1/workloads?limit=0",
+		"/dashboard/v1/workloads?limit=abc",
+		"/dashboard/v1/workloads?continue=!!",
+	} {
+		var body errorResponse
+		if code := get(t, handler, path, &body); code != http.StatusBadRequest || body.Error == "" {
+			t.Errorf("%s = %d %q, want 400 with an error", path, code, body.Error)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/dashboard/v1/workloads", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", recorder.Code)
+	}
+}
+
+func TestPaginate_SortsByKey(t *testing.T) {
+	// Items arrive in no particular order, e.g. policies listed from the cache
+	items := []PolicyRef{{Namespace: "b", Name: "x"}, {Namespace: "a-b", Name: "x"}, {Namespace: "a", Name: "y"}, {Namespace: "a", Name: "x"}}
+	key := func(ref PolicyRef) string { return pageKey(ref.Namespace, ref.Name) }
+
+	var got []string
+	q := query{limit: 1}
+	for {
+		page, err := paginate(slices.Clone(items), key, q)
+		if err != nil {
+			t.Fatalf("paginate() error = %v", err)
+		}
+		for _, ref := range page.Items {
+			got = append(got, ref.Namespace+"/"+ref.Name)
+		}
+		if page.Continue == "" {
+			break
+		}
+		q.token = page.Continue
+	}
+
+	if want := []string{"a/x", "a/y", "a-b/x", "b/x"}; !slices.Equal(got, want) {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+}
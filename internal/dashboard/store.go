@@ -0,0 +1,267 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dashboard serves a read-only JSON API over the latest results of the workload
+// processor, so dashboards can show policies, their workloads, savings and recent changes
+// without piecing them together from policy status, annotations and metrics.
+package dashboard
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+const (
+	// DefaultRetention is how long a workload is listed after it was last processed. Workloads
+	// that were deleted or no longer match a policy drop out once it passes.
+	DefaultRetention = 24 * time.Hour
+
+	// DefaultHistorySize is the number of applied changes kept for the history endpoint
+	DefaultHistorySize = 500
+)
+
+// PolicyRef identifies an OptimizationPolicy
+type PolicyRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Resources holds CPU and memory requests. A resource the policy does not optimize, or that is
+// not set, is omitted.
+type Resources struct {
+	CPU    *resource.Quantity `json:"cpu,omitempty"`
+	Memory *resource.Quantity `json:"memory,omitempty"`
+}
+
+// Savings are the requests applying the recommendations frees across all replicas of a
+// workload. They are negative when the recommendations need more than is requested today.
+type Savings struct {
+	CPU    resource.Quantity `json:"cpu"`
+	Memory resource.Quantity `json:"memory"`
+}
+
+// NewSavings returns zero savings
+func NewSavings() Savings {
+	return Savings{
+		CPU:    *resource.NewMilliQuantity(0, resource.DecimalSI),
+		Memory: *resource.NewQuantity(0, resource.BinarySI),
+	}
+}
+
+// Add adds other to the savings
+func (s *Savings) Add(other Savings) {
+	s.CPU.Add(other.CPU)
+	s.Memory.Add(other.Memory)
+}
+
+// Container is the latest recommendation for one container of a workload
+type Container struct {
+	Name string `json:"name"`
+
+	// Current are the requests in the workload's pod template when it was processed
+	Current Resources `json:"current"`
+
+	// Recommended are the recommended requests
+	Recommended Resources `json:"recommended"`
+
+	// Applied are the requests set by the last applied change, which lag behind Recommended
+	// while a convergence rate moves requests gradually
+	Applied *Resources `json:"applied,omitempty"`
+
+	// Converging is true while requests are still moving toward the recommendation
+	Converging bool `json:"converging,omitempty"`
+
+	Explanation string `json:"explanation,omitempty"`
+
+	// ClampedToBound lists the resource bounds the recommendation was clamped to
+	ClampedToBound []string `json:"clampedToBound,omitempty"`
+
+	// DataQuality shows the usage samples and values the recommendation was computed from
+	DataQuality *optipodv1alpha1.RecommendationDataQuality `json:"dataQuality,omitempty"`
+
+	// Accuracy shows how well the requests of the last applied change fit the usage since
+	Accuracy *optipodv1alpha1.RecommendationAccuracy `json:"accuracy,omitempty"`
+}
+
+// Workload is the latest processing result of a workload matched by a policy
+type Workload struct {
+	Policy    PolicyRef `json:"policy"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Replicas  int64     `json:"replicas"`
+
+	// Status and Reason are the outcome of processing, as in the policy's workload status
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+
+	StabilityScore *int32      `json:"stabilityScore,omitempty"`
+	Containers     []Container `json:"containers"`
+	Savings        Savings     `json:"savings"`
+
+	LastRecommendation *metav1.Time `json:"lastRecommendation,omitempty"`
+	LastApplied        *metav1.Time `json:"lastApplied,omitempty"`
+
+	// ObservedAt is when the workload was last processed
+	ObservedAt metav1.Time `json:"observedAt"`
+}
+
+// ContainerChange is the change applied to one container
+type ContainerChange struct {
+	Name string    `json:"name"`
+	From Resources `json:"from"`
+	To   Resources `json:"to"`
+}
+
+// Change is a change applied to a workload
+type Change struct {
+	// Sequence orders changes; later changes have higher numbers
+	Sequence   int64             `json:"sequence"`
+	Time       metav1.Time       `json:"time"`
+	Policy     PolicyRef         `json:"policy"`
+	Kind       string            `json:"kind"`
+	Namespace  string            `json:"namespace"`
+	Name       string            `json:"name"`
+	Containers []ContainerChange `json:"containers"`
+}
+
+// Store keeps the latest processing result of every workload and the most recent applied
+// changes. It is safe for concurrent use, and a nil store records nothing.
+type Store struct {
+	retention   time.Duration
+	historySize int
+	now         func() time.Time
+
+	mu        sync.RWMutex
+	workloads map[string]Workload
+	history   []Change
+	sequence  int64
+}
+
+// NewStore creates a store listing workloads for retention after they were last processed and
+// keeping the last historySize applied changes
+func NewStore(retention time.Duration, historySize int) *Store {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	return &Store{
+		retention:   retention,
+		historySize: historySize,
+		now:         time.Now,
+		workloads:   make(map[string]Workload),
+	}
+}
+
+// Record stores the latest result of a workload, replacing the previous one. When the workload
+// was not changed this time, the requests and time of the last applied change are kept.
+func (s *Store) Record(workload Workload) {
+	if s == nil {
+		return
+	}
+	if workload.ObservedAt.IsZero() {
+		workload.ObservedAt = metav1.NewTime(s.now())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := workloadKey(workload.Namespace, workload.Kind, workload.Name)
+	if previous, ok := s.workloads[key]; ok && workload.LastApplied == nil {
+		workload.LastApplied = previous.LastApplied
+		for i := range workload.Containers {
+			if workload.Containers[i].Applied != nil {
+				continue
+			}
+			for _, container := range previous.Containers {
+				if container.Name == workload.Containers[i].Name {
+					workload.Containers[i].Applied = container.Applied
+				}
+			}
+		}
+	}
+	s.workloads[key] = workload
+}
+
+// RecordChange appends an applied change to the history, dropping the oldest change once the
+// history is full
+func (s *Store) RecordChange(change Change) {
+	if s == nil {
+		return
+	}
+	if change.Time.IsZero() {
+		change.Time = metav1.NewTime(s.now())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sequence++
+	change.Sequence = s.sequence
+	s.history = append(s.history, change)
+	if len(s.history) > s.historySize {
+		s.history = s.history[len(s.history)-s.historySize:]
+	}
+}
+
+// Workloads returns the workloads processed within the retention, ordered by namespace, kind and
+// name
+func (s *Store) Workloads() []Workload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := s.now().Add(-s.retention)
+	keys := make([]string, 0, len(s.workloads))
+	for key, workload := range s.workloads {
+		if workload.ObservedAt.Time.Before(cutoff) {
+			delete(s.workloads, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	workloads := make([]Workload, 0, len(keys))
+	for _, key := range keys {
+		workloads = append(workloads, s.workloads[key])
+	}
+	return workloads
+}
+
+// History returns the recorded changes, newest first
+func (s *Store) History() []Change {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history := make([]Change, 0, len(s.history))
+	for i := len(s.history) - 1; i >= 0; i-- {
+		history = append(history, s.history[i])
+	}
+	return history
+}
+
+// workloadKey generates a unique key for a workload
+func workloadKey(namespace, kind, name string) string {
+	return namespace + "/" + kind + "/" + name
+}
@@ -18,9 +18,14 @@ package discovery
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,75 +42,257 @@ type Workload struct {
 	Object    client.Object
 }
 
+// continueNotSupported is the continue token set by the controller-runtime cache, which ignores
+// Continue and would otherwise silently return only the first page
+const continueNotSupported = "continue-not-supported"
+
+// Options controls how workloads are listed
+type Options struct {
+	// PageSize lists namespaces and workloads in pages of at most PageSize items using the
+	// Limit/Continue list options, so no single response holds a whole cluster (0 = one list).
+	// Paging requires a reader that queries the API server; the manager's cache cannot paginate.
+	PageSize int64
+
+	// ExcludedNamespaces are never discovered unless the policy names them in its allow list
+	ExcludedNamespaces []string
+
+	// MaxWorkloads stops keeping workloads once more than MaxWorkloads are found, so a selector
+	// matching far too many workloads is not held in full. The result then holds the
+	// MaxWorkloads+1 workloads found first and the rest are only counted (0 = no limit).
+	MaxWorkloads int
+}
+
 // DiscoverWorkloads discovers workloads matching the policy selectors
 // It queries Deployments, StatefulSets, and DaemonSets matching label selectors,
 // filters by namespace selectors, applies allow/deny namespace lists with deny precedence,
 // and filters by workload types based on include/exclude filters.
+// Workloads are sorted by namespace, name and kind, so they are processed in a stable order.
 func DiscoverWorkloads(ctx context.Context, c client.Client, policy *optipodv1alpha1.OptimizationPolicy) ([]Workload, error) {
+	return DiscoverWorkloadsWithOptions(ctx, c, policy, Options{})
+}
+
+// DiscoverWorkloadsWithOptions discovers workloads like DiscoverWorkloads, listing them as
+// configured by opts
+func DiscoverWorkloadsWithOptions(
+	ctx context.Context,
+	c client.Reader,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	opts Options,
+) ([]Workload, error) {
+	workloads, _, err := DiscoverAndCountWorkloads(ctx, c, policy, opts)
+	return workloads, err
+}
+
+// DiscoverAndCountWorkloads discovers workloads like DiscoverWorkloadsWithOptions and also
+// returns how many workloads the policy matches. Past opts.MaxWorkloads the remaining workloads
+// are only counted, so the count stays exact without holding an overly broad selector in memory.
+func DiscoverAndCountWorkloads(
+	ctx context.Context,
+	c client.Reader,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	opts Options,
+) ([]Workload, int, error) {
 	var allWorkloads []Workload
+	total := 0
 
 	// Get effective workload types based on include/exclude filters
 	activeTypes := optipodv1alpha1.GetActiveWorkloadTypes(policy.Spec.Selector.WorkloadTypes)
 
 	// Get all namespaces that match the policy
-	namespaces, err := getMatchingNamespaces(ctx, c, policy)
+	namespaces, err := getMatchingNamespaces(ctx, c, policy, opts)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// For each namespace, discover workloads only for active types
 	for _, ns := range namespaces {
-		// Discover Deployments only if active
-		if activeTypes.Contains(optipodv1alpha1.WorkloadTypeDeployment) {
-			deployments, err := discoverDeployments(ctx, c, ns, policy)
-			if err != nil {
-				return nil, err
+		for _, workloadType := range []optipodv1alpha1.WorkloadType{
+			optipodv1alpha1.WorkloadTypeDeployment,
+			optipodv1alpha1.WorkloadTypeStatefulSet,
+			optipodv1alpha1.WorkloadTypeDaemonSet,
+		} {
+			if !activeTypes.Contains(workloadType) {
+				continue
 			}
-			allWorkloads = append(allWorkloads, deployments...)
-		}
 
-		// Discover StatefulSets only if active
-		if activeTypes.Contains(optipodv1alpha1.WorkloadTypeStatefulSet) {
-			statefulSets, err := discoverStatefulSets(ctx, c, ns, policy)
-			if err != nil {
-				return nil, err
+			// Once the cap is exceeded, the rest are counted but not kept
+			if capExceeded(allWorkloads, opts.MaxWorkloads) {
+				count, err := countWorkloads(ctx, c, ns, workloadType, policy, opts.PageSize)
+				if err != nil {
+					return nil, 0, err
+				}
+				total += count
+				continue
 			}
-			allWorkloads = append(allWorkloads, statefulSets...)
-		}
 
-		// Discover DaemonSets only if active
-		if activeTypes.Contains(optipodv1alpha1.WorkloadTypeDaemonSet) {
-			daemonSets, err := discoverDaemonSets(ctx, c, ns, policy)
+			workloads, err := discoverWorkloadType(ctx, c, ns, workloadType, policy, opts.PageSize)
 			if err != nil {
-				return nil, err
+				return nil, 0, err
 			}
-			allWorkloads = append(allWorkloads, daemonSets...)
+			allWorkloads = append(allWorkloads, workloads...)
+			total += len(workloads)
 		}
 	}
 
-	return allWorkloads, nil
+	if capExceeded(allWorkloads, opts.MaxWorkloads) {
+		allWorkloads = allWorkloads[:opts.MaxWorkloads+1]
+	}
+	sortWorkloads(allWorkloads)
+
+	return allWorkloads, total, nil
 }
 
-// getMatchingNamespaces returns namespaces that match the policy selectors
-func getMatchingNamespaces(ctx context.Context, c client.Client, policy *optipodv1alpha1.OptimizationPolicy) ([]string, error) {
-	// List all namespaces
-	namespaceList := &corev1.NamespaceList{}
-	if err := c.List(ctx, namespaceList); err != nil {
-		return nil, err
+// discoverWorkloadType discovers the workloads of one type in a namespace
+func discoverWorkloadType(
+	ctx context.Context,
+	c client.Reader,
+	namespace string,
+	workloadType optipodv1alpha1.WorkloadType,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	pageSize int64,
+) ([]Workload, error) {
+	switch workloadType {
+	case optipodv1alpha1.WorkloadTypeStatefulSet:
+		return discoverStatefulSets(ctx, c, namespace, policy, pageSize)
+	case optipodv1alpha1.WorkloadTypeDaemonSet:
+		return discoverDaemonSets(ctx, c, namespace, policy, pageSize)
+	default:
+		return discoverDeployments(ctx, c, namespace, policy, pageSize)
+	}
+}
+
+// countWorkloads counts the workloads of one type in a namespace matching the workload selector.
+// Paged lists come from the API server and fetch only object metadata; unpaged lists are served
+// from the cache, which already holds the full objects.
+func countWorkloads(
+	ctx context.Context,
+	c client.Reader,
+	namespace string,
+	workloadType optipodv1alpha1.WorkloadType,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	pageSize int64,
+) (int, error) {
+	listOpts := &client.ListOptions{
+		Namespace: namespace,
+	}
+
+	// Apply workload label selector if specified
+	if policy.Spec.Selector.WorkloadSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(policy.Spec.Selector.WorkloadSelector)
+		if err != nil {
+			return 0, err
+		}
+		listOpts.LabelSelector = selector
+	}
+
+	var list client.ObjectList
+	switch {
+	case pageSize > 0:
+		metadataList := &metav1.PartialObjectMetadataList{}
+		metadataList.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind(string(workloadType) + "List"))
+		list = metadataList
+	case workloadType == optipodv1alpha1.WorkloadTypeStatefulSet:
+		list = &appsv1.StatefulSetList{}
+	case workloadType == optipodv1alpha1.WorkloadTypeDaemonSet:
+		list = &appsv1.DaemonSetList{}
+	default:
+		list = &appsv1.DeploymentList{}
+	}
+
+	count := 0
+	err := listPages(ctx, c, list, listOpts, pageSize, func() {
+		count += meta.LenList(list)
+	})
+	return count, err
+}
+
+// capExceeded reports whether more than maxWorkloads workloads were found; a cap of zero is
+// never exceeded
+func capExceeded(workloads []Workload, maxWorkloads int) bool {
+	return maxWorkloads > 0 && len(workloads) > maxWorkloads
+}
+
+// sortWorkloads orders workloads by namespace, name and kind
+func sortWorkloads(workloads []Workload) {
+	sort.Slice(workloads, func(i, j int) bool {
+		a, b := workloads[i], workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Kind < b.Kind
+	})
+}
+
+// listPages lists objects into list, one page of at most pageSize items at a time, and calls
+// visit after each page. list is reused across pages, so visit must copy what it keeps.
+func listPages(
+	ctx context.Context,
+	c client.Reader,
+	list client.ObjectList,
+	listOpts *client.ListOptions,
+	pageSize int64,
+	visit func(),
+) error {
+	listOpts.Limit = pageSize
+	for {
+		if err := c.List(ctx, list, listOpts); err != nil {
+			return err
+		}
+		visit()
+
+		continueToken := list.GetContinue()
+		if pageSize <= 0 || continueToken == "" {
+			return nil
+		}
+		if continueToken == continueNotSupported {
+			return fmt.Errorf("paginated listing is not supported by the cache, use a reader that queries the API server")
+		}
+		listOpts.Continue = continueToken
 	}
+}
 
+// getMatchingNamespaces returns namespaces that match the policy selectors, sorted by name
+func getMatchingNamespaces(
+	ctx context.Context,
+	c client.Reader,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	opts Options,
+) ([]string, error) {
 	var matchingNamespaces []string
 
-	for _, ns := range namespaceList.Items {
-		// Check if namespace matches the selector
-		if namespaceMatches(ns, policy) {
-			matchingNamespaces = append(matchingNamespaces, ns.Name)
+	// List all namespaces
+	namespaceList := &corev1.NamespaceList{}
+	err := listPages(ctx, c, namespaceList, &client.ListOptions{}, opts.PageSize, func() {
+		for _, ns := range namespaceList.Items {
+			// Check if namespace matches the selector
+			if !NamespaceExcluded(ns.Name, opts.ExcludedNamespaces, policy) && namespaceMatches(ns, policy) {
+				matchingNamespaces = append(matchingNamespaces, ns.Name)
+			}
 		}
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	sort.Strings(matchingNamespaces)
 	return matchingNamespaces, nil
 }
 
+// NamespaceExcluded reports whether namespace is in excluded and the policy does not opt into
+// it explicitly by naming it in its namespace allow list
+func NamespaceExcluded(namespace string, excluded []string, policy *optipodv1alpha1.OptimizationPolicy) bool {
+	if !slices.Contains(excluded, namespace) {
+		return false
+	}
+	if policy.Spec.Selector.Namespaces != nil && slices.Contains(policy.Spec.Selector.Namespaces.Allow, namespace) {
+		return false
+	}
+	return true
+}
+
 // namespaceMatches checks if a namespace matches the policy selectors
 func namespaceMatches(ns corev1.Namespace, policy *optipodv1alpha1.OptimizationPolicy) bool {
 	// Apply deny list first (takes precedence)
@@ -145,8 +332,37 @@ func namespaceMatches(ns corev1.Namespace, policy *optipodv1alpha1.OptimizationP
 	return true
 }
 
+// PinnedPolicy returns the policy a workload is pinned to by its optipod.io/policy annotation.
+// The namespace is empty when the annotation only names the policy. The last result is false when
+// the workload is not pinned.
+func PinnedPolicy(obj metav1.Object) (namespace, name string, pinned bool) {
+	if obj == nil {
+		return "", "", false
+	}
+	ref := strings.TrimSpace(obj.GetAnnotations()[optipodv1alpha1.AnnotationPolicy])
+	if ref == "" {
+		return "", "", false
+	}
+	if namespace, name, found := strings.Cut(ref, "/"); found {
+		return namespace, name, true
+	}
+	return "", ref, true
+}
+
+// PinnedTo reports whether the workload's optipod.io/policy annotation refers to the policy
+func PinnedTo(obj metav1.Object, policy *optipodv1alpha1.OptimizationPolicy) bool {
+	namespace, name, pinned := PinnedPolicy(obj)
+	return pinned && name == policy.Name && (namespace == "" || namespace == policy.Namespace)
+}
+
 // discoverDeployments discovers Deployments in a namespace matching the workload selector
-func discoverDeployments(ctx context.Context, c client.Client, namespace string, policy *optipodv1alpha1.OptimizationPolicy) ([]Workload, error) {
+func discoverDeployments(
+	ctx context.Context,
+	c client.Reader,
+	namespace string,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	pageSize int64,
+) ([]Workload, error) {
 	deploymentList := &appsv1.DeploymentList{}
 	listOpts := &client.ListOptions{
 		Namespace: namespace,
@@ -161,26 +377,37 @@ func discoverDeployments(ctx context.Context, c client.Client, namespace string,
 		listOpts.LabelSelector = selector
 	}
 
-	if err := c.List(ctx, deploymentList, listOpts); err != nil {
+	var workloads []Workload
+	err := listPages(ctx, c, deploymentList, listOpts, pageSize, func() {
+		for _, deployment := range deploymentList.Items {
+			if pageSize > 0 {
+				// Managed fields are not used and make up much of each object
+				deployment.ManagedFields = nil
+			}
+			workloads = append(workloads, Workload{
+				Kind:      "Deployment",
+				Namespace: deployment.Namespace,
+				Name:      deployment.Name,
+				Labels:    deployment.Labels,
+				Object:    &deployment,
+			})
+		}
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	var workloads []Workload //nolint:prealloc // Size unknown
-	for _, deployment := range deploymentList.Items {
-		workloads = append(workloads, Workload{
-			Kind:      "Deployment",
-			Namespace: deployment.Namespace,
-			Name:      deployment.Name,
-			Labels:    deployment.Labels,
-			Object:    &deployment,
-		})
-	}
-
 	return workloads, nil
 }
 
 // discoverStatefulSets discovers StatefulSets in a namespace matching the workload selector
-func discoverStatefulSets(ctx context.Context, c client.Client, namespace string, policy *optipodv1alpha1.OptimizationPolicy) ([]Workload, error) {
+func discoverStatefulSets(
+	ctx context.Context,
+	c client.Reader,
+	namespace string,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	pageSize int64,
+) ([]Workload, error) {
 	statefulSetList := &appsv1.StatefulSetList{}
 	listOpts := &client.ListOptions{
 		Namespace: namespace,
@@ -195,26 +422,37 @@ func discoverStatefulSets(ctx context.Context, c client.Client, namespace string
 		listOpts.LabelSelector = selector
 	}
 
-	if err := c.List(ctx, statefulSetList, listOpts); err != nil {
+	var workloads []Workload
+	err := listPages(ctx, c, statefulSetList, listOpts, pageSize, func() {
+		for _, statefulSet := range statefulSetList.Items {
+			if pageSize > 0 {
+				// Managed fields are not used and make up much of each object
+				statefulSet.ManagedFields = nil
+			}
+			workloads = append(workloads, Workload{
+				Kind:      "StatefulSet",
+				Namespace: statefulSet.Namespace,
+				Name:      statefulSet.Name,
+				Labels:    statefulSet.Labels,
+				Object:    &statefulSet,
+			})
+		}
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	var workloads []Workload //nolint:prealloc // Size unknown
-	for _, statefulSet := range statefulSetList.Items {
-		workloads = append(workloads, Workload{
-			Kind:      "StatefulSet",
-			Namespace: statefulSet.Namespace,
-			Name:      statefulSet.Name,
-			Labels:    statefulSet.Labels,
-			Object:    &statefulSet,
-		})
-	}
-
 	return workloads, nil
 }
 
 // discoverDaemonSets discovers DaemonSets in a namespace matching the workload selector
-func discoverDaemonSets(ctx context.Context, c client.Client, namespace string, policy *optipodv1alpha1.OptimizationPolicy) ([]Workload, error) {
+func discoverDaemonSets(
+	ctx context.Context,
+	c client.Reader,
+	namespace string,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	pageSize int64,
+) ([]Workload, error) {
 	daemonSetList := &appsv1.DaemonSetList{}
 	listOpts := &client.ListOptions{
 		Namespace: namespace,
@@ -229,20 +467,25 @@ func discoverDaemonSets(ctx context.Context, c client.Client, namespace string,
 		listOpts.LabelSelector = selector
 	}
 
-	if err := c.List(ctx, daemonSetList, listOpts); err != nil {
+	var workloads []Workload
+	err := listPages(ctx, c, daemonSetList, listOpts, pageSize, func() {
+		for _, daemonSet := range daemonSetList.Items {
+			if pageSize > 0 {
+				// Managed fields are not used and make up much of each object
+				daemonSet.ManagedFields = nil
+			}
+			workloads = append(workloads, Workload{
+				Kind:      "DaemonSet",
+				Namespace: daemonSet.Namespace,
+				Name:      daemonSet.Name,
+				Labels:    daemonSet.Labels,
+				Object:    &daemonSet,
+			})
+		}
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	var workloads []Workload //nolint:prealloc // Size unknown
-	for _, daemonSet := range daemonSetList.Items {
-		workloads = append(workloads, Workload{
-			Kind:      "DaemonSet",
-			Namespace: daemonSet.Namespace,
-			Name:      daemonSet.Name,
-			Labels:    daemonSet.Labels,
-			Object:    &daemonSet,
-		})
-	}
-
 	return workloads, nil
 }
@@ -18,6 +18,11 @@ package discovery
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/leanovate/gopter"
@@ -25,10 +30,12 @@ import (
 	"github.com/leanovate/gopter/prop"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
 )
@@ -1438,3 +1445,313 @@ func TestProperty7_EmptyResultSetHandling(t *testing.T) {
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
+
+// newOrderingTestObjects returns namespaces and workloads created in a scrambled order
+func newOrderingTestObjects() []client.Object {
+	labels := map[string]string{"optimize": "true"}
+	var objects []client.Object
+	for _, ns := range []string{"team-c", "team-a", "team-b"} {
+		objects = append(objects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+		for _, name := range []string{"web", "api", "worker", "cache"} {
+			objects = append(objects,
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: labels}},
+				&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: labels}},
+			)
+		}
+		objects = append(objects, &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: ns, Labels: labels}})
+	}
+	return objects
+}
+
+func newOrderingTestPolicy() *optipodv1alpha1.OptimizationPolicy {
+	return &optipodv1alpha1.OptimizationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: "default"},
+		Spec: optipodv1alpha1.OptimizationPolicySpec{
+			Selector: optipodv1alpha1.WorkloadSelector{
+				WorkloadSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"optimize": "true"}},
+			},
+		},
+	}
+}
+
+// newShufflingClient returns a client whose lists come back in a random order, like the
+// map-backed cache
+func newShufflingClient(t *testing.T, rng *rand.Rand, objects ...client.Object) client.WithWatch {
+	t.Helper()
+
+	return interceptor.NewClient(newPreviewTestClient(objects...).(client.WithWatch), interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if err := c.List(ctx, list, opts...); err != nil {
+				return err
+			}
+			items, err := meta.ExtractList(list)
+			if err != nil {
+				return err
+			}
+			rng.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+			return meta.SetList(list, items)
+		},
+	})
+}
+
+// newPagingClient returns a client that serves lists in pages honouring Limit and Continue, the
+// way the API server does, and records the size of every page it returns
+func newPagingClient(t *testing.T, pageSizes *[]int, objects ...client.Object) client.WithWatch {
+	t.Helper()
+
+	return interceptor.NewClient(newPreviewTestClient(objects...).(client.WithWatch), interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			listOpts := (&client.ListOptions{}).ApplyOptions(opts)
+			if err := c.List(ctx, list, opts...); err != nil {
+				return err
+			}
+			items, err := meta.ExtractList(list)
+			if err != nil {
+				return err
+			}
+
+			start := 0
+			if listOpts.Continue != "" {
+				if start, err = strconv.Atoi(listOpts.Continue); err != nil {
+					return fmt.Errorf("invalid continue token %q", listOpts.Continue)
+				}
+			}
+			end := len(items)
+			if listOpts.Limit > 0 && start+int(listOpts.Limit) < end {
+				end = start + int(listOpts.Limit)
+				list.SetContinue(strconv.Itoa(end))
+			} else {
+				list.SetContinue("")
+			}
+
+			*pageSizes = append(*pageSizes, end-start)
+			return meta.SetList(list, items[start:end])
+		},
+	})
+}
+
+// workloadKeys returns the kind/namespace/name of each workload in order
+func workloadKeys(workloads []Workload) []string {
+	keys := make([]string, len(workloads))
+	for i, w := range workloads {
+		keys[i] = w.Kind + "/" + w.Namespace + "/" + w.Name
+	}
+	return keys
+}
+
+func TestDiscoverWorkloads_DeterministicOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	k8sClient := newShufflingClient(t, rng, newOrderingTestObjects()...)
+
+	first, err := DiscoverWorkloads(context.Background(), k8sClient, newOrderingTestPolicy())
+	if err != nil {
+		t.Fatalf("DiscoverWorkloads() error = %v", err)
+	}
+	if len(first) != 27 {
+		t.Fatalf("discovered %d workloads, want 27", len(first))
+	}
+
+	// Workloads are ordered by namespace, then name, then kind
+	if got := workloadKeys(first)[:4]; !reflect.DeepEqual(got, []string{
+		"DaemonSet/team-a/agent", "Deployment/team-a/api", "StatefulSet/team-a/api", "Deployment/team-a/cache",
+	}) {
+		t.Errorf("first workloads = %v, want sorted by namespace, name and kind", got)
+	}
+
+	for run := 0; run < 10; run++ {
+		workloads, err := DiscoverWorkloads(context.Background(), k8sClient, newOrderingTestPolicy())
+		if err != nil {
+			t.Fatalf("DiscoverWorkloads() error = %v", err)
+		}
+		if !reflect.DeepEqual(workloadKeys(workloads), workloadKeys(first)) {
+			t.Fatalf("run %d order = %v, want %v", run, workloadKeys(workloads), workloadKeys(first))
+		}
+	}
+}
+
+func TestDiscoverWorkloadsWithOptions_Paginated(t *testing.T) {
+	objects := newOrderingTestObjects()
+
+	unpaged, err := DiscoverWorkloads(context.Background(), newPreviewTestClient(objects...), newOrderingTestPolicy())
+	if err != nil {
+		t.Fatalf("DiscoverWorkloads() error = %v", err)
+	}
+
+	var pageSizes []int
+	paged, err := DiscoverWorkloadsWithOptions(context.Background(), newPagingClient(t, &pageSizes, objects...),
+		newOrderingTestPolicy(), Options{PageSize: 2})
+	if err != nil {
+		t.Fatalf("DiscoverWorkloadsWithOptions() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(workloadKeys(paged), workloadKeys(unpaged)) {
+		t.Errorf("paged discovery = %v, want %v", workloadKeys(paged), workloadKeys(unpaged))
+	}
+
+	// No response holds more than a page; 4 deployments per namespace take 2 pages
+	for _, size := range pageSizes {
+		if size > 2 {
+			t.Errorf("received a page of %d objects, want at most 2", size)
+		}
+	}
+	if len(pageSizes) <= 12 {
+		t.Errorf("made %d list calls, want the lists split into pages", len(pageSizes))
+	}
+}
+
+func TestDiscoverWorkloadsWithOptions_CacheCannotPaginate(t *testing.T) {
+	objects := newOrderingTestObjects()
+	k8sClient := interceptor.NewClient(newPreviewTestClient(objects...).(client.WithWatch), interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if err := c.List(ctx, list, opts...); err != nil {
+				return err
+			}
+			// The cache returns every object and this token when a limit is set
+			list.SetContinue("continue-not-supported")
+			return nil
+		},
+	})
+
+	_, err := DiscoverWorkloadsWithOptions(context.Background(), k8sClient, newOrderingTestPolicy(), Options{PageSize: 2})
+	if err == nil || !strings.Contains(err.Error(), "not supported by the cache") {
+		t.Errorf("DiscoverWorkloadsWithOptions() error = %v, want a pagination not supported error", err)
+	}
+}
+
+func TestDiscoverWorkloadsWithOptions_MaxWorkloads(t *testing.T) {
+	workloads, err := DiscoverWorkloadsWithOptions(context.Background(), newPreviewTestClient(newOrderingTestObjects()...),
+		newOrderingTestPolicy(), Options{MaxWorkloads: 5})
+	if err != nil {
+		t.Fatalf("DiscoverWorkloadsWithOptions() error = %v", err)
+	}
+
+	// One workload past the cap tells it is exceeded, and the rest are not kept
+	if len(workloads) != 6 {
+		t.Errorf("discovered %d workloads, want 6", len(workloads))
+	}
+}
+
+func TestDiscoverAndCountWorkloads_CountsPastMaxWorkloads(t *testing.T) {
+	objects := newOrderingTestObjects()
+	all, err := DiscoverWorkloads(context.Background(), newPreviewTestClient(objects...), newOrderingTestPolicy())
+	if err != nil {
+		t.Fatalf("DiscoverWorkloads() error = %v", err)
+	}
+
+	for _, pageSize := range []int64{0, 2} {
+		t.Run(fmt.Sprintf("page size %d", pageSize), func(t *testing.T) {
+			var listed []client.ObjectList
+			k8sClient := interceptor.NewClient(newPreviewTestClient(objects...).(client.WithWatch), interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					listed = append(listed, list)
+					return c.List(ctx, list, opts...)
+				},
+			})
+
+			workloads, total, err := DiscoverAndCountWorkloads(context.Background(), k8sClient, newOrderingTestPolicy(),
+				Options{PageSize: pageSize, MaxWorkloads: 5})
+			if err != nil {
+				t.Fatalf("DiscoverAndCountWorkloads() error = %v", err)
+			}
+
+			if len(workloads) != 6 {
+				t.Errorf("kept %d workloads, want 6", len(workloads))
+			}
+			if total != len(all) {
+				t.Errorf("counted %d workloads, want %d", total, len(all))
+			}
+
+			// Paged discovery counts the workloads past the cap from their metadata only
+			metadataLists := 0
+			for _, list := range listed {
+				if _, ok := list.(*metav1.PartialObjectMetadataList); ok {
+					metadataLists++
+				}
+			}
+			if pageSize > 0 && metadataLists == 0 {
+				t.Error("counted the workloads past the cap from full objects, want metadata-only lists")
+			}
+			if pageSize == 0 && metadataLists > 0 {
+				t.Errorf("made %d metadata-only lists, want the cached typed lists", metadataLists)
+			}
+		})
+	}
+}
+
+func TestDiscoverWorkloadsWithOptions_ExcludedNamespaces(t *testing.T) {
+	labels := map[string]string{"optimize": "true"}
+	objects := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system", Labels: labels}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a", Labels: labels}},
+	}
+	opts := Options{ExcludedNamespaces: []string{"kube-system", "kube-node-lease"}}
+
+	tests := []struct {
+		name       string
+		namespaces *optipodv1alpha1.NamespaceFilter
+		want       []string
+	}{
+		{
+			name: "excluded by default",
+			want: []string{"Deployment/team-a/web"},
+		},
+		{
+			name:       "a deny list does not opt in",
+			namespaces: &optipodv1alpha1.NamespaceFilter{Deny: []string{"team-b"}},
+			want:       []string{"Deployment/team-a/web"},
+		},
+		{
+			name:       "included when allowed explicitly",
+			namespaces: &optipodv1alpha1.NamespaceFilter{Allow: []string{"kube-system", "team-a"}},
+			want:       []string{"Deployment/kube-system/coredns", "Deployment/team-a/web"},
+		},
+		{
+			name:       "deny still takes precedence over the allow list",
+			namespaces: &optipodv1alpha1.NamespaceFilter{Allow: []string{"kube-system"}, Deny: []string{"kube-system"}},
+			want:       []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newOrderingTestPolicy()
+			policy.Spec.Selector.Namespaces = tt.namespaces
+
+			workloads, err := DiscoverWorkloadsWithOptions(context.Background(), newPreviewTestClient(objects...), policy, opts)
+			if err != nil {
+				t.Fatalf("DiscoverWorkloadsWithOptions() error = %v", err)
+			}
+			if got := workloadKeys(workloads); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("discovered %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverWorkloads_PinnedWorkloads(t *testing.T) {
+	pinned := func(name, ref string, labels map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "team-a",
+			Labels:      labels,
+			Annotations: map[string]string{optipodv1alpha1.AnnotationPolicy: ref},
+		}}
+	}
+	objects := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		pinned("web", "test-policy", map[string]string{"optimize": "true"}),
+		pinned("unselected", "test-policy", map[string]string{"app": "unselected"}),
+	}
+
+	// A pin only picks among the policies selecting the workload, it does not widen their selectors
+	workloads, err := DiscoverWorkloads(context.Background(), newPreviewTestClient(objects...), newOrderingTestPolicy())
+	if err != nil {
+		t.Fatalf("DiscoverWorkloads() error = %v", err)
+	}
+	want := []string{"Deployment/team-a/web"}
+	if got := workloadKeys(workloads); !reflect.DeepEqual(got, want) {
+		t.Errorf("discovered %v, want %v", got, want)
+	}
+}
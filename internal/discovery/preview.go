@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// MatchedWorkload identifies a workload selected by a policy
+type MatchedWorkload struct {
+	Kind      string            `json:"kind"`
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// MatchResult is the outcome of a selector dry-run for a single policy
+type MatchResult struct {
+	Policy    string            `json:"policy"`
+	Count     int               `json:"count"`
+	Workloads []MatchedWorkload `json:"workloads"`
+}
+
+// ListMatchedWorkloads returns the workloads a policy currently selects without collecting
+// metrics or applying anything. It runs DiscoverWorkloadsWithOptions so the result is exactly
+// what a reconcile of the policy with the same options would process.
+func ListMatchedWorkloads(ctx context.Context, c client.Client, policy *optipodv1alpha1.OptimizationPolicy, opts Options) (*MatchResult, error) {
+	workloads, err := DiscoverWorkloadsWithOptions(ctx, c, policy, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover workloads: %w", err)
+	}
+
+	result := &MatchResult{
+		Policy:    types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}.String(),
+		Count:     len(workloads),
+		Workloads: make([]MatchedWorkload, 0, len(workloads)),
+	}
+	for _, workload := range workloads {
+		result.Workloads = append(result.Workloads, MatchedWorkload{
+			Kind:      workload.Kind,
+			Namespace: workload.Namespace,
+			Name:      workload.Name,
+			Labels:    workload.Labels,
+		})
+	}
+
+	sort.Slice(result.Workloads, func(i, j int) bool {
+		a, b := result.Workloads[i], result.Workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+
+	return result, nil
+}
+
+// GetPolicy fetches an existing policy by a "namespace/name" reference
+func GetPolicy(ctx context.Context, c client.Client, ref string) (*optipodv1alpha1.OptimizationPolicy, error) {
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found || namespace == "" || name == "" {
+		return nil, fmt.Errorf("invalid policy reference %q: expected namespace/name", ref)
+	}
+
+	policy := &optipodv1alpha1.OptimizationPolicy{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, policy); err != nil {
+		return nil, fmt.Errorf("failed to get policy %s: %w", ref, err)
+	}
+
+	return policy, nil
+}
+
+// DecodePolicy reads a policy manifest in YAML or JSON and validates it the same way
+// the API server does on creation, so selector mistakes are reported before discovery
+func DecodePolicy(r io.Reader) (*optipodv1alpha1.OptimizationPolicy, error) {
+	policy := &optipodv1alpha1.OptimizationPolicy{}
+	if err := utilyaml.NewYAMLOrJSONDecoder(r, 4096).Decode(policy); err != nil {
+		return nil, fmt.Errorf("failed to decode policy: %w", err)
+	}
+
+	if err := policy.ValidateCreate(); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+
+	return policy, nil
+}
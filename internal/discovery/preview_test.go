@@ -0,0 +1,178 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+func newPreviewTestClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = optipodv1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func newPreviewDeployment(namespace, name string, labels map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+	}
+}
+
+func TestListMatchedWorkloads(t *testing.T) {
+	k8sClient := newPreviewTestClient(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		newPreviewDeployment("team-b", "api", map[string]string{"optimize": "true"}),
+		newPreviewDeployment("team-a", "worker", map[string]string{"optimize": "true"}),
+		newPreviewDeployment("team-a", "api", map[string]string{"optimize": "true"}),
+		newPreviewDeployment("team-a", "legacy", map[string]string{"optimize": "false"}),
+		newPreviewDeployment("kube-system", "coredns", map[string]string{"optimize": "true"}),
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "db", Namespace: "team-a", Labels: map[string]string{"optimize": "true"},
+		}},
+	)
+
+	policy := &optipodv1alpha1.OptimizationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "preview", Namespace: "optipod-system"},
+		Spec: optipodv1alpha1.OptimizationPolicySpec{
+			Selector: optipodv1alpha1.WorkloadSelector{
+				WorkloadSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"optimize": "true"}},
+				Namespaces:       &optipodv1alpha1.NamespaceFilter{Deny: []string{"kube-system"}},
+				WorkloadTypes: &optipodv1alpha1.WorkloadTypeFilter{
+					Include: []optipodv1alpha1.WorkloadType{optipodv1alpha1.WorkloadTypeDeployment},
+				},
+			},
+		},
+	}
+
+	result, err := ListMatchedWorkloads(context.Background(), k8sClient, policy, Options{})
+	if err != nil {
+		t.Fatalf("ListMatchedWorkloads() error = %v", err)
+	}
+
+	// The result must agree with the discovery used by reconciles
+	discovered, err := DiscoverWorkloads(context.Background(), k8sClient, policy)
+	if err != nil {
+		t.Fatalf("DiscoverWorkloads() error = %v", err)
+	}
+	if result.Count != len(discovered) {
+		t.Errorf("Count = %d, want %d discovered workloads", result.Count, len(discovered))
+	}
+
+	want := []string{"team-a/Deployment/api", "team-a/Deployment/worker", "team-b/Deployment/api"}
+	got := make([]string, 0, len(result.Workloads))
+	for _, w := range result.Workloads {
+		got = append(got, w.Namespace+"/"+w.Kind+"/"+w.Name)
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("matched workloads = %v, want %v", got, want)
+	}
+	if result.Policy != "optipod-system/preview" {
+		t.Errorf("Policy = %q, want optipod-system/preview", result.Policy)
+	}
+}
+
+func TestGetPolicy(t *testing.T) {
+	k8sClient := newPreviewTestClient(&optipodv1alpha1.OptimizationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+	})
+
+	tests := []struct {
+		name    string
+		ref     string
+		wantErr bool
+	}{
+		{name: "existing policy", ref: "default/existing", wantErr: false},
+		{name: "missing policy", ref: "default/missing", wantErr: true},
+		{name: "missing namespace", ref: "existing", wantErr: true},
+		{name: "empty name", ref: "default/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GetPolicy(context.Background(), k8sClient, tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPolicy(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodePolicy(t *testing.T) {
+	const validPolicy = `
+apiVersion: optipod.optipod.io/v1alpha1
+kind: OptimizationPolicy
+metadata:
+  name: preview
+  namespace: default
+spec:
+  mode: Recommend
+  selector:
+    workloadSelector:
+      matchLabels:
+        optimize: "true"
+  metricsConfig:
+    provider: prometheus
+  resourceBounds:
+    cpu:
+      min: 10m
+      max: "4"
+    memory:
+      min: 32Mi
+      max: 8Gi
+  updateStrategy:
+    updateRequestsOnly: true
+`
+
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  bool
+	}{
+		{name: "valid YAML policy", manifest: validPolicy, wantErr: false},
+		{name: "invalid selector", manifest: strings.Replace(validPolicy, "matchLabels:\n        optimize: \"true\"",
+			"matchExpressions:\n      - key: optimize\n        operator: In", 1), wantErr: true},
+		{name: "malformed manifest", manifest: "spec: [", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := DecodePolicy(strings.NewReader(tt.manifest))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && policy.Name != "preview" {
+				t.Errorf("decoded policy name = %q, want preview", policy.Name)
+			}
+		})
+	}
+}
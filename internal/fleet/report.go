@@ -0,0 +1,273 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fleet builds a read-only recommendation report spanning several clusters. Every
+// cluster runs the same discovery and recommendation pipeline as the operator, in Recommend mode
+// without writing annotations, so nothing in any cluster is changed.
+package fleet
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/controller"
+	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/metrics"
+	"github.com/optipod/optipod/internal/recommendation"
+	"github.com/optipod/optipod/internal/report"
+)
+
+// Cluster is one cluster of the fleet, usually a kubeconfig context
+type Cluster struct {
+	// Name identifies the cluster in the report
+	Name string
+
+	// Client reads the cluster. Writes are sent as server-side dry runs, so they are never persisted.
+	Client client.Client
+
+	// MetricsProvider serves the usage of the cluster's containers
+	MetricsProvider metrics.MetricsProvider
+}
+
+// Options selects the policy the fleet is sized with
+type Options struct {
+	// Policy is applied to every cluster. When nil, PolicyRef is read from each cluster.
+	Policy *optipodv1alpha1.OptimizationPolicy
+
+	// PolicyRef is the namespace/name of a policy that exists in every cluster
+	PolicyRef string
+
+	// Discovery controls how workloads are listed in each cluster
+	Discovery discovery.Options
+
+	// Container limits the report to the container with this name. Workloads without it are
+	// left out, and a cluster where no matched workload has it fails. When empty, every
+	// container is reported.
+	Container string
+}
+
+// Row is the recommendation for one container in the combined report. A workload without
+// recommendations, e.g. because its metrics are missing, has a single row without a container.
+type Row struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Workload  string `json:"workload"`
+	Container string `json:"container,omitempty"`
+
+	CurrentCPU        string `json:"currentCPU,omitempty"`
+	CurrentMemory     string `json:"currentMemory,omitempty"`
+	RecommendedCPU    string `json:"recommendedCPU,omitempty"`
+	RecommendedMemory string `json:"recommendedMemory,omitempty"`
+
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// csvHeader is the header row of the CSV report, in the order of the Row fields
+var csvHeader = []string{
+	"cluster", "namespace", "kind", "workload", "container",
+	"currentCPU", "currentMemory", "recommendedCPU", "recommendedMemory", "status", "reason",
+}
+
+// Recommend computes the recommendations of every cluster and returns them as one report,
+// ordered by cluster. A cluster that fails is left out and its error is returned along with
+// the rows of the other clusters.
+func Recommend(ctx context.Context, clusters []Cluster, opts Options) ([]Row, error) {
+	var rows []Row
+	var errs []error
+	for _, cluster := range clusters {
+		clusterRows, err := recommendCluster(ctx, cluster, opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %w", cluster.Name, err))
+			continue
+		}
+		rows = append(rows, clusterRows...)
+	}
+	return rows, errors.Join(errs...)
+}
+
+// recommendCluster runs discovery and recommendation for a single cluster
+func recommendCluster(ctx context.Context, cluster Cluster, opts Options) ([]Row, error) {
+	policy := opts.Policy
+	if policy == nil {
+		var err error
+		if policy, err = discovery.GetPolicy(ctx, cluster.Client, opts.PolicyRef); err != nil {
+			return nil, err
+		}
+	}
+
+	// Recommend mode never applies, and the report-only processor writes no annotations, so the
+	// report needs only read access to the cluster
+	policy = policy.DeepCopy()
+	policy.Spec.Mode = optipodv1alpha1.ModeRecommend
+
+	workloads, err := discovery.DiscoverWorkloadsWithOptions(ctx, cluster.Client, policy, opts.Discovery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover workloads: %w", err)
+	}
+	if opts.Container != "" {
+		// Only workloads running the container are sized, so no metrics are fetched for the others
+		if workloads = withContainer(workloads, opts.Container); len(workloads) == 0 {
+			return nil, fmt.Errorf("container %q not found in any workload matched by the policy", opts.Container)
+		}
+	}
+
+	collector := report.NewCollector()
+	processor := controller.NewWorkloadProcessor(cluster.MetricsProvider, recommendation.NewEngine(), nil, cluster.Client)
+	processor.SetImpactCollector(collector)
+	processor.SetReportOnly(true)
+
+	statuses := make([]*optipodv1alpha1.WorkloadStatus, 0, len(workloads))
+	for i := range workloads {
+		status, err := processor.ProcessWorkload(ctx, &workloads[i], policy)
+		if status == nil {
+			return nil, fmt.Errorf("failed to process workload %s/%s: %w", workloads[i].Namespace, workloads[i].Name, err)
+		}
+		// Errors of a single workload are reported in its row
+		statuses = append(statuses, status)
+	}
+
+	// The collector holds the effective current requests, including LimitRange defaults
+	current := make(map[string]report.ContainerChange)
+	for _, change := range collector.Changes() {
+		current[containerKey(change.Namespace, change.WorkloadKind, change.WorkloadName, change.ContainerName)] = change
+	}
+
+	var rows []Row
+	for _, status := range statuses {
+		row := Row{
+			Cluster:   cluster.Name,
+			Namespace: status.Namespace,
+			Kind:      status.Kind,
+			Workload:  status.Name,
+			Status:    status.Status,
+			Reason:    status.Reason,
+		}
+		if opts.Container != "" {
+			row.Container = opts.Container
+		}
+		if len(status.Recommendations) == 0 {
+			rows = append(rows, row)
+			continue
+		}
+		recommended := false
+		for _, rec := range status.Recommendations {
+			if opts.Container != "" && rec.Container != opts.Container {
+				continue
+			}
+			recommended = true
+			containerRow := row
+			containerRow.Container = rec.Container
+			if change, ok := current[containerKey(status.Namespace, status.Kind, status.Name, rec.Container)]; ok {
+				containerRow.CurrentCPU = requestString(change.CurrentCPU)
+				containerRow.CurrentMemory = requestString(change.CurrentMemory)
+			}
+			if rec.CPU != nil {
+				containerRow.RecommendedCPU = rec.CPU.String()
+			}
+			if rec.Memory != nil {
+				containerRow.RecommendedMemory = rec.Memory.String()
+			}
+			rows = append(rows, containerRow)
+		}
+		if !recommended {
+			row.Status = controller.StatusSkipped
+			row.Reason = fmt.Sprintf("No recommendation for container %s: it is excluded, not matched by the policy container selectors or has no metrics",
+				opts.Container)
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// withContainer returns the workloads whose pod template has a container or init container
+// with the given name
+func withContainer(workloads []discovery.Workload, name string) []discovery.Workload {
+	var matched []discovery.Workload
+	for _, workload := range workloads {
+		var podSpec *corev1.PodSpec
+		switch obj := workload.Object.(type) {
+		case *appsv1.Deployment:
+			podSpec = &obj.Spec.Template.Spec
+		case *appsv1.StatefulSet:
+			podSpec = &obj.Spec.Template.Spec
+		case *appsv1.DaemonSet:
+			podSpec = &obj.Spec.Template.Spec
+		default:
+			continue
+		}
+		for _, container := range slices.Concat(podSpec.InitContainers, podSpec.Containers) {
+			if container.Name == name {
+				matched = append(matched, workload)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// containerKey identifies a container of a workload
+func containerKey(namespace, kind, name, container string) string {
+	return namespace + "/" + kind + "/" + name + "/" + container
+}
+
+// requestString formats a request, leaving a request that is not set empty
+func requestString(request resource.Quantity) string {
+	if request.IsZero() {
+		return ""
+	}
+	return request.String()
+}
+
+// WriteCSV writes the report as CSV with a header row
+func WriteCSV(w io.Writer, rows []Row) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write([]string{
+			row.Cluster, row.Namespace, row.Kind, row.Workload, row.Container,
+			row.CurrentCPU, row.CurrentMemory, row.RecommendedCPU, row.RecommendedMemory, row.Status, row.Reason,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the report as an indented JSON array
+func WriteJSON(w io.Writer, rows []Row) error {
+	if rows == nil {
+		rows = []Row{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+const testNamespace = "default"
+
+// staticMetricsProvider serves the same usage for every container
+type staticMetricsProvider struct {
+	cpuP90 string
+}
+
+func (p *staticMetricsProvider) GetContainerMetrics(_ context.Context, _, _, _ string, _ time.Duration) (*metrics.ContainerMetrics, error) {
+	cpu := resource.MustParse(p.cpuP90)
+	return &metrics.ContainerMetrics{
+		CPU:    metrics.ResourceMetrics{P50: cpu, P90: cpu, P99: cpu, Samples: 100},
+		Memory: metrics.ResourceMetrics{P50: resource.MustParse("128Mi"), P90: resource.MustParse("128Mi"), P99: resource.MustParse("128Mi"), Samples: 100},
+	}, nil
+}
+
+func (p *staticMetricsProvider) HealthCheck(context.Context) error {
+	return nil
+}
+
+// newTestCluster returns a cluster with one deployment and its pod
+func newTestCluster(t *testing.T, name, cpuP90 string) (Cluster, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = optipodv1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+
+	labels := map[string]string{"app": "api"}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: testNamespace, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					}},
+				}}},
+			},
+		},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: testNamespace, Labels: labels}}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespace, deployment, pod).Build()
+	return Cluster{Name: name, Client: fakeClient, MetricsProvider: &staticMetricsProvider{cpuP90: cpuP90}}, fakeClient
+}
+
+func newTestPolicy(mode optipodv1alpha1.PolicyMode) *optipodv1alpha1.OptimizationPolicy {
+	return &optipodv1alpha1.OptimizationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "fleet", Namespace: testNamespace},
+		Spec: optipodv1alpha1.OptimizationPolicySpec{
+			Mode: mode,
+			Selector: optipodv1alpha1.WorkloadSelector{
+				WorkloadSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			},
+			MetricsConfig: optipodv1alpha1.MetricsConfig{Provider: "test", Percentile: "P90"},
+			ResourceBounds: optipodv1alpha1.ResourceBounds{
+				CPU:    optipodv1alpha1.ResourceBound{Min: resource.MustParse("50m"), Max: resource.MustParse("4")},
+				Memory: optipodv1alpha1.ResourceBound{Min: resource.MustParse("64Mi"), Max: resource.MustParse("4Gi")},
+			},
+			UpdateStrategy: optipodv1alpha1.UpdateStrategy{UpdateRequestsOnly: true},
+		},
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	ctx := context.Background()
+	east, eastClient := newTestCluster(t, "east", "100m")
+	west, _ := newTestCluster(t, "west", "500m")
+
+	// An Auto policy is run in Recommend mode
+	rows, err := Recommend(ctx, []Cluster{east, west}, Options{Policy: newTestPolicy(optipodv1alpha1.ModeAuto)})
+	if err != nil {
+		t.Fatalf("Recommend() error = %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Recommend() returned %d rows, want one per cluster: %+v", len(rows), rows)
+	}
+
+	for i, want := range []struct{ cluster, cpu string }{{"east", "120m"}, {"west", "600m"}} {
+		row := rows[i]
+		if row.Cluster != want.cluster || row.Workload != "api" || row.Container != "app" {
+			t.Errorf("rows[%d] = %+v, want container app of api in %s", i, row, want.cluster)
+		}
+		if row.CurrentCPU != "1" || row.CurrentMemory != "1Gi" {
+			t.Errorf("rows[%d] current = %s/%s, want 1/1Gi", i, row.CurrentCPU, row.CurrentMemory)
+		}
+		if row.RecommendedCPU != want.cpu {
+			t.Errorf("rows[%d] recommended CPU = %s, want %s", i, row.RecommendedCPU, want.cpu)
+		}
+	}
+
+	// Nothing is written to the clusters
+	stored := &appsv1.Deployment{}
+	if err := eastClient.Get(ctx, client.ObjectKey{Namespace: testNamespace, Name: "api"}, stored); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if len(stored.Annotations) != 0 {
+		t.Errorf("annotations = %v, want the deployment unchanged", stored.Annotations)
+	}
+	if got := stored.Spec.Template.Spec.Containers[0].Resources.Requests.Cpu().String(); got != "1" {
+		t.Errorf("CPU request = %s, want 1", got)
+	}
+}
+
+func TestRecommend_ReadOnlyAccess(t *testing.T) {
+	ctx := context.Background()
+	cluster, fakeClient := newTestCluster(t, "east", "100m")
+
+	// Credentials that may only read the cluster are rejected on every write
+	forbidden := func() error {
+		return apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "api", errors.New("read-only"))
+	}
+	cluster.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+		Create: func(context.Context, client.WithWatch, client.Object, ...client.CreateOption) error {
+			return forbidden()
+		},
+		Update: func(context.Context, client.WithWatch, client.Object, ...client.UpdateOption) error {
+			return forbidden()
+		},
+		Patch: func(context.Context, client.WithWatch, client.Object, client.Patch, ...client.PatchOption) error {
+			return forbidden()
+		},
+		Delete: func(context.Context, client.WithWatch, client.Object, ...client.DeleteOption) error {
+			return forbidden()
+		},
+	})
+
+	rows, err := Recommend(ctx, []Cluster{cluster}, Options{Policy: newTestPolicy(optipodv1alpha1.ModeAuto)})
+	if err != nil {
+		t.Fatalf("Recommend() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].Status != "Recommended" || rows[0].RecommendedCPU != "120m" {
+		t.Errorf("rows = %+v, want the recommendation of api", rows)
+	}
+}
+
+func TestRecommend_PolicyRef(t *testing.T) {
+	ctx := context.Background()
+	withPolicy, withPolicyClient := newTestCluster(t, "with-policy", "100m")
+	if err := withPolicyClient.Create(ctx, newTestPolicy(optipodv1alpha1.ModeRecommend)); err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+	withoutPolicy, _ := newTestCluster(t, "without-policy", "100m")
+
+	// A cluster without the policy fails on its own
+	rows, err := Recommend(ctx, []Cluster{withoutPolicy, withPolicy}, Options{PolicyRef: testNamespace + "/fleet"})
+	if err == nil || !strings.Contains(err.Error(), "cluster without-policy") {
+		t.Errorf("Recommend() error = %v, want the cluster without the policy reported", err)
+	}
+	if len(rows) != 1 || rows[0].Cluster != "with-policy" {
+		t.Errorf("rows = %+v, want only the cluster with the policy", rows)
+	}
+}
+
+func TestRecommend_Container(t *testing.T) {
+	ctx := context.Background()
+	cluster, clusterClient := newTestCluster(t, "east", "100m")
+	deployment := &appsv1.Deployment{}
+	if err := clusterClient.Get(ctx, client.ObjectKey{Namespace: testNamespace, Name: "api"}, deployment); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, corev1.Container{Name: "proxy"})
+	if err := clusterClient.Update(ctx, deployment); err != nil {
+		t.Fatalf("failed to update deployment: %v", err)
+	}
+	policy := newTestPolicy(optipodv1alpha1.ModeRecommend)
+
+	// Every container is reported by default
+	rows, err := Recommend(ctx, []Cluster{cluster}, Options{Policy: policy})
+	if err != nil {
+		t.Fatalf("Recommend() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0].Container != "app" || rows[1].Container != "proxy" {
+		t.Fatalf("rows = %+v, want one row per container", rows)
+	}
+
+	// A named container is reported alone
+	rows, err = Recommend(ctx, []Cluster{cluster}, Options{Policy: policy, Container: "proxy"})
+	if err != nil {
+		t.Fatalf("Recommend() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].Container != "proxy" || rows[0].RecommendedCPU != "120m" {
+		t.Errorf("rows = %+v, want only the recommendation of proxy", rows)
+	}
+
+	// A container the policy excludes has a row saying why it has no recommendation
+	excluding := policy.DeepCopy()
+	excluding.Spec.ExcludeContainers = []string{"proxy"}
+	rows, err = Recommend(ctx, []Cluster{cluster}, Options{Policy: excluding, Container: "proxy"})
+	if err != nil {
+		t.Fatalf("Recommend() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].Container != "proxy" || rows[0].RecommendedCPU != "" ||
+		!strings.Contains(rows[0].Reason, "No recommendation for container proxy") {
+		t.Errorf("rows = %+v, want a row without a recommendation for the excluded container", rows)
+	}
+
+	// A container no matched workload has is an error
+	rows, err = Recommend(ctx, []Cluster{cluster}, Options{Policy: policy, Container: "db"})
+	if err == nil || !strings.Contains(err.Error(), `container "db" not found`) {
+		t.Errorf("Recommend() error = %v, want the missing container reported", err)
+	}
+	if len(rows) != 0 {
+		t.Errorf("rows = %+v, want none", rows)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	rows := []Row{
+		{Cluster: "east", Namespace: "default", Kind: "Deployment", Workload: "api", Container: "app",
+			CurrentCPU: "1", CurrentMemory: "1Gi", RecommendedCPU: "120m", RecommendedMemory: "154Mi", Status: "Recommended"},
+		{Cluster: "west", Namespace: "default", Kind: "Deployment", Workload: "web", Status: "Skipped", Reason: "no metrics, yet"},
+	}
+
+	var out bytes.Buffer
+	if err := WriteCSV(&out, rows); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want := "cluster,namespace,kind,workload,container,currentCPU,currentMemory,recommendedCPU,recommendedMemory,status,reason\n" +
+		"east,default,Deployment,api,app,1,1Gi,120m,154Mi,Recommended,\n" +
+		"west,default,Deployment,web,,,,,,Skipped,\"no metrics, yet\"\n"
+	if out.String() != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteJSON(&out, nil); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "[]" {
+		t.Errorf("WriteJSON(nil) = %q, want an empty array", out.String())
+	}
+
+	out.Reset()
+	rows := []Row{{Cluster: "east", Namespace: "default", Kind: "Deployment", Workload: "api", Container: "app", RecommendedCPU: "120m"}}
+	if err := WriteJSON(&out, rows); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded []map[string]string
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if len(decoded) != 1 || decoded[0]["cluster"] != "east" || decoded[0]["recommendedCPU"] != "120m" {
+		t.Errorf("decoded report = %v, want the cluster column and recommendation", decoded)
+	}
+}
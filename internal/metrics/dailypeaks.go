@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// maxRangePoints is the number of points a range query for daily peaks is kept under;
+// Prometheus rejects queries resolving to more than 11,000 points per series
+const maxRangePoints = 10000
+
+// timedSample is a usage sample with the time it was taken
+type timedSample struct {
+	time  time.Time
+	value int64
+}
+
+// queryDailyPeaks runs range queries for CPU (cores) and memory (bytes) usage over the last days
+// days against a PromQL query API and computes the usage percentiles of each day. Samples taken
+// within startupExclusion after a container start are left out.
+func queryDailyPeaks(ctx context.Context, client v1.API, cpuQuery, memoryQuery string, days int, startupExclusion time.Duration) (*DailyPeaks, error) {
+	end := time.Now()
+	start := end.Add(-time.Duration(days) * 24 * time.Hour)
+	queryRange := v1.Range{Start: start, End: end, Step: dailyPeakStep(end.Sub(start))}
+
+	cpuSamples, err := queryTimedSamples(ctx, client, cpuQuery, queryRange, 1000, startupExclusion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CPU samples: %w", err)
+	}
+	memorySamples, err := queryTimedSamples(ctx, client, memoryQuery, queryRange, 1, startupExclusion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query memory samples: %w", err)
+	}
+
+	return &DailyPeaks{
+		CPU:    computeDailyPercentiles(cpuSamples, true),
+		Memory: computeDailyPercentiles(memorySamples, false),
+	}, nil
+}
+
+// dailyPeakStep returns the range query step for a window: 30 seconds, or coarser when the
+// window would otherwise resolve to more than maxRangePoints points
+func dailyPeakStep(window time.Duration) time.Duration {
+	step := 30 * time.Second
+	if minStep := (window / maxRangePoints).Truncate(time.Second) + time.Second; minStep > step {
+		step = minStep
+	}
+	return step
+}
+
+// queryTimedSamples executes a range query and returns the samples of every series, with their
+// values multiplied by scale, leaving out those taken within startupExclusion after a container start
+func queryTimedSamples(ctx context.Context, client v1.API, query string, queryRange v1.Range, scale float64, startupExclusion time.Duration) ([]timedSample, error) {
+	result, _, err := client.QueryRange(ctx, query, queryRange)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	matrix, ok := result.(model.Matrix)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type: %T", result)
+	}
+
+	var samples []timedSample
+	for _, stream := range excludeStartupSamples(matrix, queryRange, startupExclusion) {
+		for _, sample := range stream.Values {
+			samples = append(samples, timedSample{time: sample.Timestamp.Time(), value: int64(float64(sample.Value) * scale)})
+		}
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no samples in result")
+	}
+	return samples, nil
+}
+
+// computeDailyPercentiles groups samples by UTC calendar day and computes the percentiles of
+// each day, oldest first. Days without samples are left out.
+func computeDailyPercentiles(samples []timedSample, isMillicore bool) []ResourceMetrics {
+	byDay := make(map[time.Time][]int64)
+	var days []time.Time
+	for _, sample := range samples {
+		day := sample.time.UTC().Truncate(24 * time.Hour)
+		if _, ok := byDay[day]; !ok {
+			days = append(days, day)
+		}
+		byDay[day] = append(byDay[day], sample.value)
+	}
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+
+	result := make([]ResourceMetrics, 0, len(days))
+	for _, day := range days {
+		result = append(result, computePercentiles(byDay[day], isMillicore))
+	}
+	return result
+}
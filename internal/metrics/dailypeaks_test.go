@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// syntheticDays returns samples every 15 minutes over days UTC days starting at start, with
+// usage base and a value of peaks[day] at noon of each day
+func syntheticDays(start time.Time, base int64, peaks []int64) []timedSample {
+	var samples []timedSample
+	for day, peak := range peaks {
+		for quarter := 0; quarter < 96; quarter++ {
+			sampleTime := start.Add(time.Duration(day)*24*time.Hour + time.Duration(quarter)*15*time.Minute)
+			value := base
+			if quarter == 48 {
+				value = peak
+			}
+			samples = append(samples, timedSample{time: sampleTime, value: value})
+		}
+	}
+	return samples
+}
+
+func TestComputeDailyPercentiles(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	samples := syntheticDays(start, 100, []int64{400, 420, 5000, 410, 430})
+
+	// Samples arriving out of order still land in their own day
+	samples[0], samples[len(samples)-1] = samples[len(samples)-1], samples[0]
+
+	daily := computeDailyPercentiles(samples, true)
+	if len(daily) != 5 {
+		t.Fatalf("got %d days, want 5", len(daily))
+	}
+	wantP99 := []int64{400, 420, 5000, 410, 430}
+	for i, day := range daily {
+		if day.Samples != 96 {
+			t.Errorf("day %d has %d samples, want 96", i, day.Samples)
+		}
+		if day.P50.MilliValue() != 100 {
+			t.Errorf("day %d P50 = %s, want 100m", i, day.P50.String())
+		}
+		// The single peak sample dominates the top percentile of a day with 96 samples
+		if got := day.P99.MilliValue(); got < 100 || got > wantP99[i] {
+			t.Errorf("day %d P99 = %dm, want between 100m and %dm", i, got, wantP99[i])
+		}
+	}
+	if daily[2].P99.Cmp(daily[1].P99) <= 0 {
+		t.Errorf("the spike day P99 %s is not above its neighbour %s", daily[2].P99.String(), daily[1].P99.String())
+	}
+
+	// Days are UTC calendar days, whatever the sample's time zone
+	zone := time.FixedZone("UTC+10", 10*60*60)
+	late := []timedSample{
+		{time: time.Date(2025, 3, 1, 9, 0, 0, 0, zone), value: 1},  // 2025-02-28 23:00 UTC
+		{time: time.Date(2025, 3, 1, 11, 0, 0, 0, zone), value: 2}, // 2025-03-01 01:00 UTC
+	}
+	if got := len(computeDailyPercentiles(late, false)); got != 2 {
+		t.Errorf("got %d days, want 2", got)
+	}
+
+	if got := computeDailyPercentiles(nil, false); len(got) != 0 {
+		t.Errorf("got %d days without samples, want none", len(got))
+	}
+}
+
+func TestDailyPeakStep(t *testing.T) {
+	tests := []struct {
+		window time.Duration
+		want   time.Duration
+	}{
+		{window: 24 * time.Hour, want: 30 * time.Second},
+		{window: 7 * 24 * time.Hour, want: 61 * time.Second},
+		{window: 30 * 24 * time.Hour, want: 260 * time.Second},
+	}
+	for _, tt := range tests {
+		step := dailyPeakStep(tt.window)
+		if step != tt.want {
+			t.Errorf("dailyPeakStep(%s) = %s, want %s", tt.window, step, tt.want)
+		}
+		if points := int(tt.window / step); points > maxRangePoints {
+			t.Errorf("dailyPeakStep(%s) resolves to %d points, want at most %d", tt.window, points, maxRangePoints)
+		}
+	}
+}
+
+func TestPrometheusProvider_GetContainerDailyPeaks(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	var steps []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/query_range", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		steps = append(steps, r.Form.Get("step"))
+
+		// CPU in cores, memory in bytes, over three days
+		value, peak := "0.1", "0.4"
+		if strings.Contains(r.Form.Get("query"), "memory") {
+			value, peak = "1048576", "4194304"
+		}
+		var points []string
+		for _, sample := range syntheticDays(start, 0, []int64{1, 1, 1}) {
+			v := value
+			if sample.value == 1 {
+				v = peak
+			}
+			points = append(points, fmt.Sprintf(`[%d,"%s"]`, sample.time.Unix(), v))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[%s]}]}}`,
+			strings.Join(points, ","))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	provider, err := NewPrometheusProvider(server.URL)
+	if err != nil {
+		t.Fatalf("NewPrometheusProvider() error = %v", err)
+	}
+
+	daily, err := provider.GetContainerDailyPeaks(context.Background(), "default", "web-0", "app", 7)
+	if err != nil {
+		t.Fatalf("GetContainerDailyPeaks() error = %v", err)
+	}
+	if len(daily.CPU) != 3 || len(daily.Memory) != 3 {
+		t.Fatalf("got %d CPU and %d memory days, want 3 each", len(daily.CPU), len(daily.Memory))
+	}
+	if got := daily.CPU[0].P50.MilliValue(); got != 100 {
+		t.Errorf("CPU P50 of the first day = %dm, want 100m", got)
+	}
+	if got := daily.Memory[0].P50.Value(); got != 1048576 {
+		t.Errorf("memory P50 of the first day = %d, want 1Mi", got)
+	}
+
+	// Seven days of samples are queried at a step Prometheus accepts
+	for _, step := range steps {
+		seconds, err := strconv.ParseFloat(step, 64)
+		if err != nil || seconds < 61 {
+			t.Errorf("step = %q, want at least 61s", step)
+		}
+	}
+}
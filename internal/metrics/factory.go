@@ -33,6 +33,9 @@ const (
 
 	// ProviderTypePrometheus uses Prometheus
 	ProviderTypePrometheus ProviderType = "prometheus"
+
+	// ProviderTypeOTelPromQL queries OpenTelemetry metrics through a Prometheus-compatible query API
+	ProviderTypeOTelPromQL ProviderType = "otel-promql"
 )
 
 // ProviderConfig contains configuration for creating a metrics provider.
@@ -43,6 +46,14 @@ type ProviderConfig struct {
 	// PrometheusURL is the URL for Prometheus (required if Type is prometheus)
 	PrometheusURL string
 
+	// OTelURL is the Prometheus-compatible query API URL of the backend storing OpenTelemetry
+	// metrics (required if Type is otel-promql)
+	OTelURL string
+
+	// OTelHealthPath is the health endpoint of the OpenTelemetry metrics backend
+	// (optional, defaults to DefaultOTelHealthPath)
+	OTelHealthPath string
+
 	// Clientset is the Kubernetes clientset (required if Type is metrics-server)
 	Clientset kubernetes.Interface
 
@@ -55,12 +66,34 @@ type ProviderConfig struct {
 
 	// SampleInterval is the interval between samples (optional, defaults to 15 seconds)
 	SampleInterval int // in seconds
+
+	// MetricsServerMode selects sampled percentiles or the instantaneous reading
+	// (optional, defaults to sampled)
+	MetricsServerMode MetricsServerMode
+
+	// RestartAwareMemory computes memory percentiles per segment between container restarts,
+	// taking the highest across segments (optional, defaults to all samples pooled)
+	RestartAwareMemory bool
+
+	// StartupExclusionPeriod leaves usage samples taken within this period after each container
+	// start out of the percentiles (optional, defaults to 0 which keeps all samples).
+	// Only the Prometheus and otel-promql providers support it.
+	StartupExclusionPeriod time.Duration
+
+	// OutlierTrimFraction is the share of the highest samples discarded as outliers before the
+	// percentiles are computed, from 0 to MaxOutlierTrimFraction (optional, defaults to 0 which
+	// keeps all samples)
+	OutlierTrimFraction float64
 }
 
 // NewProvider creates a new MetricsProvider based on the configuration.
 // It returns an error if the provider cannot be initialized, with fallback
 // handling delegated to the caller.
 func NewProvider(config ProviderConfig) (MetricsProvider, error) {
+	if err := validateOutlierTrimFraction(config.OutlierTrimFraction); err != nil {
+		return nil, err
+	}
+
 	switch config.Type {
 	case ProviderTypeMetricsServer:
 		if config.Clientset == nil {
@@ -71,6 +104,7 @@ func NewProvider(config ProviderConfig) (MetricsProvider, error) {
 		}
 
 		// Use custom configuration if provided, otherwise use defaults
+		provider := NewMetricsServerProvider(config.Clientset, config.MetricsClientset)
 		if config.MaxSamples > 0 || config.SampleInterval > 0 {
 			maxSamples := config.MaxSamples
 			if maxSamples == 0 {
@@ -80,15 +114,20 @@ func NewProvider(config ProviderConfig) (MetricsProvider, error) {
 			if sampleInterval == 0 {
 				sampleInterval = 15 // default 15 seconds
 			}
-			return NewMetricsServerProviderWithConfig(
+			provider = NewMetricsServerProviderWithConfig(
 				config.Clientset,
 				config.MetricsClientset,
 				maxSamples,
 				time.Duration(sampleInterval)*time.Second,
-			), nil
+			)
 		}
 
-		return NewMetricsServerProvider(config.Clientset, config.MetricsClientset), nil
+		if err := provider.SetMode(config.MetricsServerMode); err != nil {
+			return nil, err
+		}
+		provider.SetRestartAwareMemory(config.RestartAwareMemory)
+		provider.SetOutlierTrimFraction(config.OutlierTrimFraction)
+		return provider, nil
 
 	case ProviderTypePrometheus:
 		if config.PrometheusURL == "" {
@@ -98,6 +137,22 @@ func NewProvider(config ProviderConfig) (MetricsProvider, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to create prometheus provider: %w", err)
 		}
+		provider.SetRestartAwareMemory(config.RestartAwareMemory)
+		provider.SetStartupExclusionPeriod(config.StartupExclusionPeriod)
+		provider.SetOutlierTrimFraction(config.OutlierTrimFraction)
+		return provider, nil
+
+	case ProviderTypeOTelPromQL:
+		if config.OTelURL == "" {
+			return nil, fmt.Errorf("otel backend URL is required for otel-promql provider")
+		}
+		provider, err := NewOTelPromQLProvider(config.OTelURL, config.OTelHealthPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otel-promql provider: %w", err)
+		}
+		provider.SetRestartAwareMemory(config.RestartAwareMemory)
+		provider.SetStartupExclusionPeriod(config.StartupExclusionPeriod)
+		provider.SetOutlierTrimFraction(config.OutlierTrimFraction)
 		return provider, nil
 
 	default:
@@ -29,12 +29,29 @@ import (
 	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
+// MetricsServerMode selects how the metrics-server provider builds percentiles.
+type MetricsServerMode string
+
+const (
+	// MetricsServerModeSampled collects several samples and computes percentiles from them (default)
+	MetricsServerModeSampled MetricsServerMode = "sampled"
+
+	// MetricsServerModeInstantaneous returns the current reading for every percentile
+	// (P50=P90=P99=current). Useful for demos, not recommended for production sizing.
+	MetricsServerModeInstantaneous MetricsServerMode = "instantaneous"
+)
+
 // MetricsServerProvider implements MetricsProvider using Kubernetes metrics-server.
 type MetricsServerProvider struct {
-	clientset        kubernetes.Interface
-	metricsClientset metricsclientset.Interface
-	maxSamples       int           // Maximum number of samples to collect
-	sampleInterval   time.Duration // Interval between samples
+	clientset          kubernetes.Interface
+	metricsClientset   metricsclientset.Interface
+	maxSamples         int               // Maximum number of samples to collect
+	sampleInterval     time.Duration     // Interval between samples
+	mode               MetricsServerMode // How percentiles are built
+	restartAwareMemory bool              // Compute memory percentiles per restart segment
+
+	// outlierTrimFraction is the share of the highest samples discarded before the percentiles
+	outlierTrimFraction float64
 }
 
 // NewMetricsServerProvider creates a new MetricsServerProvider with default settings.
@@ -45,6 +62,7 @@ func NewMetricsServerProvider(clientset kubernetes.Interface, metricsClientset m
 		metricsClientset: metricsClientset,
 		maxSamples:       10,               // Default: 10 samples for production
 		sampleInterval:   15 * time.Second, // Match metrics-server scrape interval
+		mode:             MetricsServerModeSampled,
 	}
 }
 
@@ -62,14 +80,41 @@ func NewMetricsServerProviderWithConfig(clientset kubernetes.Interface, metricsC
 		metricsClientset: metricsClientset,
 		maxSamples:       maxSamples,
 		sampleInterval:   sampleInterval,
+		mode:             MetricsServerModeSampled,
 	}
 }
 
+// SetMode sets how percentiles are built. An empty mode keeps the sampled default.
+func (m *MetricsServerProvider) SetMode(mode MetricsServerMode) error {
+	switch mode {
+	case "":
+		m.mode = MetricsServerModeSampled
+	case MetricsServerModeSampled, MetricsServerModeInstantaneous:
+		m.mode = mode
+	default:
+		return fmt.Errorf("unknown metrics-server mode: %s", mode)
+	}
+	return nil
+}
+
+// SetOutlierTrimFraction sets the share of the highest samples discarded as outliers before the
+// percentiles are computed. Zero keeps all samples.
+func (m *MetricsServerProvider) SetOutlierTrimFraction(fraction float64) {
+	m.outlierTrimFraction = fraction
+}
+
+// SetRestartAwareMemory sets whether memory percentiles are computed per segment between
+// container restarts instead of over all samples.
+func (m *MetricsServerProvider) SetRestartAwareMemory(enabled bool) {
+	m.restartAwareMemory = enabled
+}
+
 // GetContainerMetrics collects metrics from metrics-server and computes percentiles.
 // Since metrics-server provides point-in-time metrics, we collect multiple samples
 // over a short period to build a time series for percentile computation.
 // Note: We collect a configurable number of samples rather than sampling over the
 // entire rolling window, as that would be impractical (e.g., 1 hour would take 1 hour).
+// In instantaneous mode a single reading is taken, so every percentile equals the current usage.
 func (m *MetricsServerProvider) GetContainerMetrics(ctx context.Context, namespace, podName, containerName string, window time.Duration) (*ContainerMetrics, error) {
 	// Calculate number of samples based on window, but cap at configured maxSamples
 	// This provides enough data for percentile computation without excessive wait time
@@ -80,9 +125,13 @@ func (m *MetricsServerProvider) GetContainerMetrics(ctx context.Context, namespa
 	if numSamples > m.maxSamples {
 		numSamples = m.maxSamples
 	}
+	if m.mode == MetricsServerModeInstantaneous {
+		numSamples = 1
+	}
 
 	cpuSamples := make([]int64, 0, numSamples)
 	memorySamples := make([]int64, 0, numSamples)
+	var newest time.Time
 
 	// Collect samples
 	for i := 0; i < numSamples; i++ {
@@ -110,6 +159,7 @@ func (m *MetricsServerProvider) GetContainerMetrics(ctx context.Context, namespa
 
 		cpuSamples = append(cpuSamples, cpuUsage)
 		memorySamples = append(memorySamples, memoryUsage)
+		newest = podMetrics.Timestamp.Time
 
 		// Wait before next sample (except for the last iteration)
 		if i < numSamples-1 {
@@ -122,12 +172,21 @@ func (m *MetricsServerProvider) GetContainerMetrics(ctx context.Context, namespa
 	}
 
 	// Compute percentiles
-	cpuMetrics := computePercentiles(cpuSamples, true)        // CPU in millicores
-	memoryMetrics := computePercentiles(memorySamples, false) // Memory in bytes
+	cpuMetrics := computeTrimmedPercentiles(cpuSamples, true, m.outlierTrimFraction)        // CPU in millicores
+	memoryMetrics := computeTrimmedPercentiles(memorySamples, false, m.outlierTrimFraction) // Memory in bytes
+	if m.restartAwareMemory {
+		// The sampled readings form a single series that a restart resets
+		memoryMetrics = computeRestartAdjustedPercentiles([][]int64{memorySamples}, m.outlierTrimFraction)
+	}
+
+	// The readings span only a fraction of the window unless it is short
+	cpuMetrics.Coverage = windowCoverage(cpuMetrics.Samples, m.sampleInterval, window)
+	memoryMetrics.Coverage = windowCoverage(memoryMetrics.Samples, m.sampleInterval, window)
 
 	return &ContainerMetrics{
-		CPU:    cpuMetrics,
-		Memory: memoryMetrics,
+		CPU:          cpuMetrics,
+		Memory:       memoryMetrics,
+		NewestSample: newest,
 	}, nil
 }
 
@@ -141,14 +200,22 @@ func (m *MetricsServerProvider) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
-// computePercentiles calculates P50, P90, and P99 from a slice of samples.
+// computePercentiles calculates P50, P90, P99 and the maximum from a slice of samples.
 // If isMillicore is true, values are treated as millicores; otherwise as bytes.
 func computePercentiles(samples []int64, isMillicore bool) ResourceMetrics {
+	return computeTrimmedPercentiles(samples, isMillicore, 0)
+}
+
+// computeTrimmedPercentiles calculates the percentiles like computePercentiles after discarding
+// trimFraction of the highest samples as outliers, see trimOutliers. The maximum and the number
+// of samples are those of all samples.
+func computeTrimmedPercentiles(samples []int64, isMillicore bool, trimFraction float64) ResourceMetrics {
 	if len(samples) == 0 {
 		return ResourceMetrics{
 			P50:     resource.Quantity{},
 			P90:     resource.Quantity{},
 			P99:     resource.Quantity{},
+			Max:     resource.Quantity{},
 			Samples: 0,
 		}
 	}
@@ -158,25 +225,30 @@ func computePercentiles(samples []int64, isMillicore bool) ResourceMetrics {
 	copy(sorted, samples)
 	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
 
+	maxSample := sorted[len(sorted)-1]
+	sorted = trimOutliers(sorted, trimFraction)
 	p50 := percentile(sorted, 50)
 	p90 := percentile(sorted, 90)
 	p99 := percentile(sorted, 99)
 
-	var p50Qty, p90Qty, p99Qty resource.Quantity
+	var p50Qty, p90Qty, p99Qty, maxQty resource.Quantity
 	if isMillicore {
 		p50Qty = *resource.NewMilliQuantity(p50, resource.DecimalSI)
 		p90Qty = *resource.NewMilliQuantity(p90, resource.DecimalSI)
 		p99Qty = *resource.NewMilliQuantity(p99, resource.DecimalSI)
+		maxQty = *resource.NewMilliQuantity(maxSample, resource.DecimalSI)
 	} else {
 		p50Qty = *resource.NewQuantity(p50, resource.BinarySI)
 		p90Qty = *resource.NewQuantity(p90, resource.BinarySI)
 		p99Qty = *resource.NewQuantity(p99, resource.BinarySI)
+		maxQty = *resource.NewQuantity(maxSample, resource.BinarySI)
 	}
 
 	return ResourceMetrics{
 		P50:     p50Qty,
 		P90:     p90Qty,
 		P99:     p99Qty,
+		Max:     maxQty,
 		Samples: len(samples),
 	}
 }
@@ -17,8 +17,18 @@ limitations under the License.
 package metrics
 
 import (
+	"context"
 	"testing"
 	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
 )
 
 // TestSampleCalculation verifies that the number of samples is capped appropriately
@@ -172,3 +182,87 @@ func TestPercentileComputation(t *testing.T) {
 		})
 	}
 }
+
+// TestInstantaneousMode verifies that instantaneous mode takes a single reading and
+// reports it for every percentile, regardless of the window and max samples
+func TestInstantaneousMode(t *testing.T) {
+	podMetrics := &metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-0", Namespace: "default"},
+		Containers: []metricsv1beta1.ContainerMetrics{{
+			Name: "app",
+			Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("250m"),
+				corev1.ResourceMemory: resource.MustParse("300Mi"),
+			},
+		}},
+	}
+
+	// The fake tracker does not map PodMetrics to the "pods" resource, so serve it from a reactor
+	metricsClientset := metricsfake.NewSimpleClientset()
+	metricsClientset.PrependReactor("get", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, podMetrics, nil
+	})
+
+	provider, err := NewProvider(ProviderConfig{
+		Type:              ProviderTypeMetricsServer,
+		Clientset:         fake.NewSimpleClientset(),
+		MetricsClientset:  metricsClientset,
+		MetricsServerMode: MetricsServerModeInstantaneous,
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A 1 hour window would take 10 samples 15s apart in sampled mode
+	got, err := provider.GetContainerMetrics(ctx, "default", "app-0", "app", time.Hour)
+	if err != nil {
+		t.Fatalf("GetContainerMetrics() error = %v", err)
+	}
+
+	if got.CPU.Samples != 1 || got.Memory.Samples != 1 {
+		t.Errorf("samples = %d/%d, want a single reading", got.CPU.Samples, got.Memory.Samples)
+	}
+	if want := (15 * time.Second).Seconds() / time.Hour.Seconds(); got.CPU.Coverage != want || got.Memory.Coverage != want {
+		t.Errorf("coverage = %v/%v, want %v for one reading in an hour", got.CPU.Coverage, got.Memory.Coverage, want)
+	}
+	for name, q := range map[string]resource.Quantity{"P50": got.CPU.P50, "P90": got.CPU.P90, "P99": got.CPU.P99} {
+		if q.MilliValue() != 250 {
+			t.Errorf("CPU %s = %s, want 250m", name, q.String())
+		}
+	}
+	for name, q := range map[string]resource.Quantity{"P50": got.Memory.P50, "P90": got.Memory.P90, "P99": got.Memory.P99} {
+		if q.Value() != 300*1024*1024 {
+			t.Errorf("Memory %s = %s, want 300Mi", name, q.String())
+		}
+	}
+}
+
+// TestMetricsServerModeValidation verifies the mode defaults to sampled and rejects unknown modes
+func TestMetricsServerModeValidation(t *testing.T) {
+	tests := []struct {
+		mode     MetricsServerMode
+		wantMode MetricsServerMode
+		wantErr  bool
+	}{
+		{mode: "", wantMode: MetricsServerModeSampled},
+		{mode: MetricsServerModeSampled, wantMode: MetricsServerModeSampled},
+		{mode: MetricsServerModeInstantaneous, wantMode: MetricsServerModeInstantaneous},
+		{mode: "live", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			provider := NewMetricsServerProvider(fake.NewSimpleClientset(), metricsfake.NewSimpleClientset())
+			err := provider.SetMode(tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetMode(%q) error = %v, wantErr %v", tt.mode, err, tt.wantErr)
+			}
+			if !tt.wantErr && provider.mode != tt.wantMode {
+				t.Errorf("mode = %q, want %q", provider.mode, tt.wantMode)
+			}
+		})
+	}
+}
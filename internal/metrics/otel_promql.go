@@ -0,0 +1,261 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// OTel resource attributes identifying a container
+	otelAttrNamespace = "k8s.namespace.name"
+	otelAttrPod       = "k8s.pod.name"
+	otelAttrContainer = "k8s.container.name"
+
+	// Container metrics as emitted by the collector's kubeletstats receiver
+	otelMetricCPU    = "container.cpu.usage"          // gauge, in cores
+	otelMetricMemory = "container.memory.working_set" // gauge, in bytes
+
+	// Pod metrics as emitted by the collector's kubeletstats receiver
+	otelMetricPodCPU    = "k8s.pod.cpu.usage"          // gauge, in cores
+	otelMetricPodMemory = "k8s.pod.memory.working_set" // gauge, in bytes
+
+	// DefaultOTelHealthPath is the health endpoint queried when none is configured
+	DefaultOTelHealthPath = "/-/healthy"
+)
+
+// OTelPromQLProvider implements MetricsProvider for OpenTelemetry metrics stored in a backend with
+// a Prometheus-compatible query API, e.g. Prometheus with its OTLP receiver, Mimir or Thanos.
+// Metrics keep their OTel names and resource attributes and are queried with PromQL; OTLP itself
+// has no query API, so the backend must serve the Prometheus HTTP API.
+type OTelPromQLProvider struct {
+	client             v1.API
+	httpClient         *http.Client
+	healthURL          string
+	restartAwareMemory bool // Compute memory percentiles per restart segment
+
+	// startupExclusionPeriod is how long after each container start samples are left out
+	startupExclusionPeriod time.Duration
+
+	// outlierTrimFraction is the share of the highest samples discarded before the percentiles
+	outlierTrimFraction float64
+}
+
+// NewOTelPromQLProvider creates a new OTelPromQLProvider for the Prometheus-compatible query API
+// at backendURL.
+// healthPath is the backend's health endpoint, defaulting to DefaultOTelHealthPath.
+func NewOTelPromQLProvider(backendURL, healthPath string) (*OTelPromQLProvider, error) {
+	client, err := api.NewClient(api.Config{
+		Address: backendURL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenTelemetry backend client: %w", err)
+	}
+
+	if healthPath == "" {
+		healthPath = DefaultOTelHealthPath
+	}
+	if !strings.HasPrefix(healthPath, "/") {
+		healthPath = "/" + healthPath
+	}
+
+	return &OTelPromQLProvider{
+		client:     v1.NewAPI(client),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		healthURL:  strings.TrimSuffix(backendURL, "/") + healthPath,
+	}, nil
+}
+
+// SetRestartAwareMemory sets whether memory percentiles are computed per segment between
+// container restarts instead of over all samples.
+func (p *OTelPromQLProvider) SetRestartAwareMemory(enabled bool) {
+	p.restartAwareMemory = enabled
+}
+
+// SetStartupExclusionPeriod sets how long after each container start usage samples are left
+// out of the percentiles. Zero keeps all samples.
+func (p *OTelPromQLProvider) SetStartupExclusionPeriod(period time.Duration) {
+	p.startupExclusionPeriod = period
+}
+
+// SetOutlierTrimFraction sets the share of the highest samples discarded as outliers before the
+// percentiles are computed. Zero keeps all samples.
+func (p *OTelPromQLProvider) SetOutlierTrimFraction(fraction float64) {
+	p.outlierTrimFraction = fraction
+}
+
+// GetContainerMetrics queries the OpenTelemetry backend for container CPU and memory usage
+// over the rolling window and computes percentiles.
+func (p *OTelPromQLProvider) GetContainerMetrics(ctx context.Context, namespace, podName, containerName string, window time.Duration) (*ContainerMetrics, error) {
+	containerMetrics, err := p.usageMetrics(ctx,
+		otelQuery(otelMetricCPU, namespace, podName, containerName),
+		otelQuery(otelMetricMemory, namespace, podName, containerName),
+		window)
+	if err != nil {
+		return nil, containerMetricsError(err)
+	}
+	return containerMetrics, nil
+}
+
+// GetPodMetrics queries the OpenTelemetry backend for the CPU and memory usage of a whole pod
+// over the rolling window and computes percentiles.
+func (p *OTelPromQLProvider) GetPodMetrics(ctx context.Context, namespace, podName string, window time.Duration) (*ContainerMetrics, error) {
+	return p.usageMetrics(ctx,
+		otelPodQuery(otelMetricPodCPU, namespace, podName),
+		otelPodQuery(otelMetricPodMemory, namespace, podName),
+		window)
+}
+
+// usageMetrics runs the CPU and memory usage queries over the rolling window and computes percentiles
+func (p *OTelPromQLProvider) usageMetrics(ctx context.Context, cpuQuery, memoryQuery string, window time.Duration) (*ContainerMetrics, error) {
+	cpuSeries, cpuNewest, err := p.queryRange(ctx, cpuQuery, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CPU metrics: %w", err)
+	}
+
+	// Convert CPU samples from cores to millicores
+	var cpuMillicores []int64
+	for _, samples := range cpuSeries {
+		for _, v := range samples {
+			cpuMillicores = append(cpuMillicores, int64(v*1000))
+		}
+	}
+
+	memorySeries, memoryNewest, err := p.queryRange(ctx, memoryQuery, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query memory metrics: %w", err)
+	}
+
+	memoryBytes := bytesSeries(memorySeries)
+	var memoryMetrics ResourceMetrics
+	if p.restartAwareMemory {
+		memoryMetrics = computeRestartAdjustedPercentiles(memoryBytes, p.outlierTrimFraction)
+	} else {
+		var samples []int64
+		for _, series := range memoryBytes {
+			samples = append(samples, series...)
+		}
+		memoryMetrics = computeTrimmedPercentiles(samples, false, p.outlierTrimFraction)
+	}
+	memoryMetrics.Coverage = windowCoverage(memoryMetrics.Samples, usageQueryStep, window)
+
+	cpuMetrics := computeTrimmedPercentiles(cpuMillicores, true, p.outlierTrimFraction)
+	cpuMetrics.Coverage = windowCoverage(cpuMetrics.Samples, usageQueryStep, window)
+
+	return &ContainerMetrics{
+		CPU:          cpuMetrics,
+		Memory:       memoryMetrics,
+		NewestSample: olderSample(cpuNewest, memoryNewest),
+	}, nil
+}
+
+// GetContainerDailyPeaks queries the OpenTelemetry backend for container CPU and memory usage
+// over the last days days and computes the percentiles of each day.
+func (p *OTelPromQLProvider) GetContainerDailyPeaks(ctx context.Context, namespace, podName, containerName string, days int) (*DailyPeaks, error) {
+	return queryDailyPeaks(ctx, p.client,
+		otelQuery(otelMetricCPU, namespace, podName, containerName),
+		otelQuery(otelMetricMemory, namespace, podName, containerName),
+		days, p.startupExclusionPeriod)
+}
+
+// HealthCheck verifies that the OpenTelemetry backend's health endpoint reports healthy.
+func (p *OTelPromQLProvider) HealthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.healthURL, nil)
+	if err != nil {
+		return fmt.Errorf("otel backend health check failed: %w", err)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("otel backend health check failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("otel backend health check failed: %s returned %s", p.healthURL, resp.Status)
+	}
+	return nil
+}
+
+// QueryValue evaluates an instant query and returns the value of its first sample.
+func (p *OTelPromQLProvider) QueryValue(ctx context.Context, query string) (float64, error) {
+	return queryValue(ctx, p.client, query)
+}
+
+// ValidateQuery verifies that the OpenTelemetry backend accepts the query.
+func (p *OTelPromQLProvider) ValidateQuery(ctx context.Context, query string) error {
+	return validateQuery(ctx, p.client, query)
+}
+
+// queryRange executes a range query and returns the sample values of each non-empty series and
+// the time of the newest sample. A container restarted within the window can have several
+// series; all of them count.
+func (p *OTelPromQLProvider) queryRange(ctx context.Context, query string, window time.Duration) ([][]float64, time.Time, error) {
+	end := time.Now()
+	start := end.Add(-window)
+
+	queryRange := v1.Range{
+		Start: start,
+		End:   end,
+		Step:  usageQueryStep,
+	}
+	result, _, err := p.client.QueryRange(ctx, query, queryRange)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("query failed: %w", err)
+	}
+
+	matrix, ok := result.(model.Matrix)
+	if !ok {
+		return nil, time.Time{}, fmt.Errorf("unexpected result type: %T", result)
+	}
+
+	matrix = excludeStartupSamples(matrix, queryRange, p.startupExclusionPeriod)
+	series := matrixValues(matrix)
+	if len(series) == 0 {
+		return nil, time.Time{}, fmt.Errorf("%w from OpenTelemetry backend", errNoData)
+	}
+
+	return series, newestSample(matrix), nil
+}
+
+// otelQuery builds a selector for an OTel metric of a single container. Metric and attribute
+// names contain dots, so both are quoted.
+func otelQuery(metric, namespace, podName, containerName string) string {
+	return fmt.Sprintf(`{%q, %q=%q, %q=%q, %q=%q}`,
+		metric,
+		otelAttrNamespace, namespace,
+		otelAttrPod, podName,
+		otelAttrContainer, containerName,
+	)
+}
+
+// otelPodQuery builds a selector for an OTel metric of a single pod
+func otelPodQuery(metric, namespace, podName string) string {
+	return fmt.Sprintf(`{%q, %q=%q, %q=%q}`,
+		metric,
+		otelAttrNamespace, namespace,
+		otelAttrPod, podName,
+	)
+}
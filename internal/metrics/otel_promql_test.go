@@ -0,0 +1,203 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newOTelTestBackend serves range queries with the given sample values per metric
+// and records the queries it received
+func newOTelTestBackend(t *testing.T, samples map[string][]string, queries *[]string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/query_range", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		query := r.Form.Get("query")
+		*queries = append(*queries, query)
+
+		var values []string
+		for metric, metricValues := range samples {
+			if strings.Contains(query, fmt.Sprintf("%q", metric)) {
+				values = metricValues
+			}
+		}
+
+		points := make([]string, len(values))
+		for i, v := range values {
+			points[i] = fmt.Sprintf(`[%d,"%s"]`, 1700000000+i*30, v)
+		}
+		result := ""
+		if len(points) > 0 {
+			result = fmt.Sprintf(`{"metric":{},"values":[%s]}`, strings.Join(points, ","))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[%s]}}`, result)
+	})
+	mux.HandleFunc("/-/healthy", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOTelPromQLProvider_GetContainerMetrics(t *testing.T) {
+	var queries []string
+	server := newOTelTestBackend(t, map[string][]string{
+		otelMetricCPU:    {"0.1", "0.2", "0.3"},
+		otelMetricMemory: {"1048576", "2097152", "3145728"},
+	}, &queries)
+
+	provider, err := NewOTelPromQLProvider(server.URL, "")
+	if err != nil {
+		t.Fatalf("NewOTelPromQLProvider() error = %v", err)
+	}
+
+	containerMetrics, err := provider.GetContainerMetrics(context.Background(), "default", "web-0", "app", time.Hour)
+	if err != nil {
+		t.Fatalf("GetContainerMetrics() error = %v", err)
+	}
+
+	if containerMetrics.CPU.Samples != 3 || containerMetrics.CPU.P50.MilliValue() != 200 {
+		t.Errorf("CPU = %d samples with P50 %s, want 3 samples with P50 200m",
+			containerMetrics.CPU.Samples, containerMetrics.CPU.P50.String())
+	}
+	if containerMetrics.Memory.Samples != 3 || containerMetrics.Memory.P50.Value() != 2097152 {
+		t.Errorf("memory = %d samples with P50 %s, want 3 samples with P50 2Mi",
+			containerMetrics.Memory.Samples, containerMetrics.Memory.P50.String())
+	}
+	if want := time.Unix(1700000060, 0); !containerMetrics.NewestSample.Equal(want) {
+		t.Errorf("NewestSample = %v, want the time of the last sample %v", containerMetrics.NewestSample, want)
+	}
+
+	// The container is selected by its OTel resource attributes
+	if len(queries) != 2 {
+		t.Fatalf("backend received %d queries, want 2", len(queries))
+	}
+	for _, want := range []string{
+		`"k8s.namespace.name"="default"`,
+		`"k8s.pod.name"="web-0"`,
+		`"k8s.container.name"="app"`,
+	} {
+		for _, query := range queries {
+			if !strings.Contains(query, want) {
+				t.Errorf("query %s does not select %s", query, want)
+			}
+		}
+	}
+}
+
+func TestOTelPromQLProvider_NoData(t *testing.T) {
+	var queries []string
+	server := newOTelTestBackend(t, nil, &queries)
+
+	provider, err := NewOTelPromQLProvider(server.URL, "")
+	if err != nil {
+		t.Fatalf("NewOTelPromQLProvider() error = %v", err)
+	}
+
+	_, err = provider.GetContainerMetrics(context.Background(), "default", "web-0", "app", time.Hour)
+	if !errors.Is(err, ErrNoContainerMetrics) {
+		t.Errorf("GetContainerMetrics() error = %v, want ErrNoContainerMetrics when the backend has no data for the container", err)
+	}
+}
+
+func TestOTelPromQLProvider_GetPodMetrics(t *testing.T) {
+	var queries []string
+	server := newOTelTestBackend(t, map[string][]string{
+		otelMetricPodCPU:    {"0.3", "0.4", "0.5"},
+		otelMetricPodMemory: {"1048576", "2097152", "3145728"},
+	}, &queries)
+
+	provider, err := NewOTelPromQLProvider(server.URL, "")
+	if err != nil {
+		t.Fatalf("NewOTelPromQLProvider() error = %v", err)
+	}
+
+	podMetrics, err := provider.GetPodMetrics(context.Background(), "default", "web-0", time.Hour)
+	if err != nil {
+		t.Fatalf("GetPodMetrics() error = %v", err)
+	}
+	if podMetrics.CPU.Samples != 3 || podMetrics.CPU.P50.MilliValue() != 400 {
+		t.Errorf("CPU = %d samples with P50 %s, want 3 samples with P50 400m",
+			podMetrics.CPU.Samples, podMetrics.CPU.P50.String())
+	}
+
+	// The pod is selected without a container attribute
+	for _, query := range queries {
+		if !strings.Contains(query, `"k8s.pod.name"="web-0"`) || strings.Contains(query, otelAttrContainer) {
+			t.Errorf("query %s does not select the whole pod", query)
+		}
+	}
+}
+
+func TestOTelPromQLProvider_HealthCheck(t *testing.T) {
+	var queries []string
+	server := newOTelTestBackend(t, nil, &queries)
+
+	tests := []struct {
+		name       string
+		healthPath string
+		wantErr    bool
+	}{
+		{name: "default health path", healthPath: "", wantErr: false},
+		{name: "path without leading slash", healthPath: "-/healthy", wantErr: false},
+		{name: "unknown health path", healthPath: "/ready", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewOTelPromQLProvider(server.URL, tt.healthPath)
+			if err != nil {
+				t.Fatalf("NewOTelPromQLProvider() error = %v", err)
+			}
+
+			err = provider.HealthCheck(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HealthCheck() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewProvider_OTel(t *testing.T) {
+	if _, err := NewProvider(ProviderConfig{Type: ProviderTypeOTelPromQL}); err == nil {
+		t.Error("expected an error without an OpenTelemetry backend URL")
+	}
+
+	provider, err := NewProvider(ProviderConfig{Type: ProviderTypeOTelPromQL, OTelURL: "http://otel-query:9090"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	if _, ok := provider.(*OTelPromQLProvider); !ok {
+		t.Errorf("NewProvider() = %T, want *OTelPromQLProvider", provider)
+	}
+}
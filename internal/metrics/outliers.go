@@ -0,0 +1,48 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+)
+
+// MaxOutlierTrimFraction is the largest share of the highest samples that can be discarded as
+// outliers. Trimming more would size workloads below their regular peaks.
+const MaxOutlierTrimFraction = 0.25
+
+// trimOutliers returns the sorted samples without the highest fraction of them, so a few extreme
+// samples, e.g. of a cron job or a noisy neighbor, do not inflate the upper percentiles. The
+// number of samples discarded is rounded down and at least one sample is kept.
+//
+// Legitimate rare peaks are discarded as well, so a workload whose peaks are short but real can
+// be sized below them.
+func trimOutliers(sorted []int64, fraction float64) []int64 {
+	if fraction <= 0 || len(sorted) == 0 {
+		return sorted
+	}
+	trim := min(int(float64(len(sorted))*fraction), len(sorted)-1)
+	return sorted[:len(sorted)-trim]
+}
+
+// validateOutlierTrimFraction returns an error when the fraction is outside 0 to
+// MaxOutlierTrimFraction or is NaN, which fails every comparison
+func validateOutlierTrimFraction(fraction float64) error {
+	if !(fraction >= 0 && fraction <= MaxOutlierTrimFraction) {
+		return fmt.Errorf("outlier trim fraction must be between 0 and %g, got %g", MaxOutlierTrimFraction, fraction)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+// newOutlierTestSamples returns 100 steady samples of 100 to 199 with the highest replaced by
+// outliers an order of magnitude larger
+func newOutlierTestSamples(outliers int) []int64 {
+	samples := make([]int64, 100)
+	for i := range samples {
+		samples[i] = int64(100 + i)
+	}
+	for i := 0; i < outliers; i++ {
+		samples[len(samples)-1-i] = 10000
+	}
+	return samples
+}
+
+func TestComputeTrimmedPercentiles(t *testing.T) {
+	samples := newOutlierTestSamples(3)
+
+	untrimmed := computePercentiles(samples, true)
+	if untrimmed.P99.MilliValue() != 10000 {
+		t.Fatalf("untrimmed P99 = %dm, want the injected outlier", untrimmed.P99.MilliValue())
+	}
+	if got := computeTrimmedPercentiles(samples, true, 0); got.P99.Cmp(untrimmed.P99) != 0 {
+		t.Errorf("P99 with a zero fraction = %s, want %s", got.P99.String(), untrimmed.P99.String())
+	}
+
+	trimmed := computeTrimmedPercentiles(samples, true, 0.05)
+	if trimmed.P99.MilliValue() >= 10000 {
+		t.Errorf("trimmed P99 = %dm, want the outliers discarded", trimmed.P99.MilliValue())
+	}
+	if trimmed.P50.MilliValue() > untrimmed.P50.MilliValue() {
+		t.Errorf("trimmed P50 = %dm, want at most the untrimmed %dm", trimmed.P50.MilliValue(), untrimmed.P50.MilliValue())
+	}
+
+	// The maximum and the sample count still cover every sample
+	if trimmed.Max.MilliValue() != 10000 || trimmed.Samples != len(samples) {
+		t.Errorf("trimmed Max = %dm over %d samples, want 10000m over %d", trimmed.Max.MilliValue(), trimmed.Samples, len(samples))
+	}
+}
+
+func TestTrimOutliers(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  int
+		fraction float64
+		wantLen  int
+	}{
+		{name: "zero fraction keeps all samples", samples: 100, fraction: 0, wantLen: 100},
+		{name: "discards the fraction of samples", samples: 100, fraction: 0.05, wantLen: 95},
+		{name: "rounds the discarded samples down", samples: 10, fraction: 0.05, wantLen: 10},
+		{name: "keeps at least one sample", samples: 1, fraction: MaxOutlierTrimFraction, wantLen: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted := make([]int64, tt.samples)
+			for i := range sorted {
+				sorted[i] = int64(i)
+			}
+			if got := trimOutliers(sorted, tt.fraction); len(got) != tt.wantLen {
+				t.Errorf("trimOutliers() kept %d samples, want %d", len(got), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestComputeRestartAdjustedPercentiles_TrimsOutliers(t *testing.T) {
+	samples := newOutlierTestSamples(2)
+
+	got := computeRestartAdjustedPercentiles([][]int64{samples}, 0.05)
+	if got.P99.Value() >= 10000 || got.Max.Value() != 10000 {
+		t.Errorf("P99 = %d and Max = %d, want the outliers trimmed from P99 only", got.P99.Value(), got.Max.Value())
+	}
+}
+
+func TestNewProvider_OutlierTrimFraction(t *testing.T) {
+	tests := []struct {
+		fraction float64
+		wantErr  bool
+	}{
+		{fraction: 0},
+		{fraction: 0.01},
+		{fraction: MaxOutlierTrimFraction},
+		{fraction: -0.01, wantErr: true},
+		{fraction: 0.5, wantErr: true},
+		{fraction: math.NaN(), wantErr: true},
+		{fraction: math.Inf(1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		provider, err := NewProvider(ProviderConfig{
+			Type:                ProviderTypeMetricsServer,
+			Clientset:           fake.NewSimpleClientset(),
+			MetricsClientset:    metricsfake.NewSimpleClientset(),
+			OutlierTrimFraction: tt.fraction,
+		})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewProvider() with fraction %g error = %v, wantErr %v", tt.fraction, err, tt.wantErr)
+		}
+		if err == nil && provider.(*MetricsServerProvider).outlierTrimFraction != tt.fraction {
+			t.Errorf("outlierTrimFraction = %g, want %g", provider.(*MetricsServerProvider).outlierTrimFraction, tt.fraction)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package metrics
 
 import (
 	"testing"
+	"time"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -126,7 +127,8 @@ func TestProperty_PercentileBounds(t *testing.T) {
 				return false
 			}
 
-			return true
+			// The maximum is the highest sample
+			return metrics.Max.MilliValue() == max
 		},
 		gen.SliceOfN(10, gen.Int64Range(0, 10000000000)).SuchThat(func(v interface{}) bool {
 			return len(v.([]int64)) > 0
@@ -170,3 +172,26 @@ func TestProperty_PercentileIdenticalValues(t *testing.T) {
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
+
+func TestWindowCoverage(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples int
+		window  time.Duration
+		want    float64
+	}{
+		{name: "no samples", samples: 0, window: time.Hour, want: 0},
+		{name: "no window", samples: 10, window: 0, want: 0},
+		{name: "half the window", samples: 60, window: time.Hour, want: 0.5},
+		{name: "the full window", samples: 120, window: time.Hour, want: 1},
+		{name: "several series are capped at the window", samples: 360, window: time.Hour, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := windowCoverage(tt.samples, usageQueryStep, tt.window); got != tt.want {
+				t.Errorf("windowCoverage(%d, %s) = %v, want %v", tt.samples, tt.window, got, tt.want)
+			}
+		})
+	}
+}
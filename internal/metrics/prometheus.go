@@ -18,6 +18,7 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -28,7 +29,10 @@ import (
 
 // PrometheusProvider implements MetricsProvider using Prometheus.
 type PrometheusProvider struct {
-	client v1.API
+	client                 v1.API
+	restartAwareMemory     bool          // Compute memory percentiles per restart segment
+	startupExclusionPeriod time.Duration // Samples left out after each container start
+	outlierTrimFraction    float64       // Share of the highest samples discarded as outliers
 }
 
 // NewPrometheusProvider creates a new PrometheusProvider.
@@ -45,53 +49,136 @@ func NewPrometheusProvider(prometheusURL string) (*PrometheusProvider, error) {
 	}, nil
 }
 
+// SetRestartAwareMemory sets whether memory percentiles are computed per segment between
+// container restarts instead of over all samples.
+func (p *PrometheusProvider) SetRestartAwareMemory(enabled bool) {
+	p.restartAwareMemory = enabled
+}
+
+// SetStartupExclusionPeriod sets how long after each container start usage samples are left
+// out of the percentiles. Zero keeps all samples.
+func (p *PrometheusProvider) SetStartupExclusionPeriod(period time.Duration) {
+	p.startupExclusionPeriod = period
+}
+
+// SetOutlierTrimFraction sets the share of the highest samples discarded as outliers before the
+// percentiles are computed. Zero keeps all samples.
+func (p *PrometheusProvider) SetOutlierTrimFraction(fraction float64) {
+	p.outlierTrimFraction = fraction
+}
+
 // GetContainerMetrics queries Prometheus for container CPU and memory usage
 // over the rolling window and computes percentiles.
 func (p *PrometheusProvider) GetContainerMetrics(ctx context.Context, namespace, podName, containerName string, window time.Duration) (*ContainerMetrics, error) {
-	// Query CPU usage
 	cpuQuery := fmt.Sprintf(
 		`rate(container_cpu_usage_seconds_total{namespace="%s",pod="%s",container="%s"}[%s])`,
 		namespace, podName, containerName, formatDuration(window),
 	)
-
-	cpuSamples, err := p.queryRange(ctx, cpuQuery, window)
+	memoryQuery := fmt.Sprintf(
+		`container_memory_working_set_bytes{namespace="%s",pod="%s",container="%s"}`,
+		namespace, podName, containerName,
+	)
+	containerMetrics, err := p.usageMetrics(ctx, cpuQuery, memoryQuery, window)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query CPU metrics: %w", err)
+		// A pod without any series is mislabeled rather than missing per-container metrics
+		if errors.Is(err, errNoData) {
+			if matched, diagErr := p.podHasSeries(ctx, namespace, podName, window); diagErr == nil && !matched {
+				return nil, fmt.Errorf("%w for pod %s/%s: no series has the labels namespace=%q and pod=%q, check that pods are not relabeled: %w",
+					ErrNoMatchingSeries, namespace, podName, namespace, podName, err)
+			}
+		}
+		return nil, containerMetricsError(err)
 	}
+	return containerMetrics, nil
+}
 
-	// Convert CPU samples from cores to millicores
-	cpuMillicores := make([]int64, len(cpuSamples))
-	for i, v := range cpuSamples {
-		cpuMillicores[i] = int64(v * 1000)
+// podHasSeries reports whether Prometheus has any container series for the pod within the window
+func (p *PrometheusProvider) podHasSeries(ctx context.Context, namespace, podName string, window time.Duration) (bool, error) {
+	query := fmt.Sprintf(
+		`count(last_over_time(container_memory_working_set_bytes{namespace="%s",pod="%s"}[%s]))`,
+		namespace, podName, formatDuration(window),
+	)
+	result, _, err := p.client.Query(ctx, query, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("query failed: %w", err)
+	}
+	vector, ok := result.(model.Vector)
+	if !ok {
+		return false, fmt.Errorf("unexpected result type: %T", result)
 	}
+	return len(vector) > 0 && vector[0].Value > 0, nil
+}
 
-	// Query memory usage
+// GetPodMetrics queries Prometheus for the CPU and memory usage of a whole pod over the rolling
+// window and computes percentiles. It reads the pod-level series, which have no container label
+// (the pod cgroup in cAdvisor metrics).
+func (p *PrometheusProvider) GetPodMetrics(ctx context.Context, namespace, podName string, window time.Duration) (*ContainerMetrics, error) {
+	cpuQuery := fmt.Sprintf(
+		`sum(rate(container_cpu_usage_seconds_total{namespace="%s",pod="%s",container=""}[%s]))`,
+		namespace, podName, formatDuration(window),
+	)
 	memoryQuery := fmt.Sprintf(
-		`container_memory_working_set_bytes{namespace="%s",pod="%s",container="%s"}`,
-		namespace, podName, containerName,
+		`sum(container_memory_working_set_bytes{namespace="%s",pod="%s",container=""})`,
+		namespace, podName,
 	)
+	return p.usageMetrics(ctx, cpuQuery, memoryQuery, window)
+}
+
+// usageMetrics runs the CPU and memory usage queries over the rolling window and computes percentiles
+func (p *PrometheusProvider) usageMetrics(ctx context.Context, cpuQuery, memoryQuery string, window time.Duration) (*ContainerMetrics, error) {
+	cpuSeries, cpuNewest, err := p.queryRange(ctx, cpuQuery, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CPU metrics: %w", err)
+	}
+
+	// Convert CPU samples of the first series from cores to millicores
+	// (there should only be one series for a specific container)
+	cpuMillicores := make([]int64, len(cpuSeries[0]))
+	for i, v := range cpuSeries[0] {
+		cpuMillicores[i] = int64(v * 1000)
+	}
 
-	memorySamples, err := p.queryRange(ctx, memoryQuery, window)
+	memorySeries, memoryNewest, err := p.queryRange(ctx, memoryQuery, window)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query memory metrics: %w", err)
 	}
 
 	// Convert memory samples to int64 bytes
-	memoryBytes := make([]int64, len(memorySamples))
-	for i, v := range memorySamples {
-		memoryBytes[i] = int64(v)
-	}
+	memoryBytes := bytesSeries(memorySeries)
 
 	// Compute percentiles
-	cpuMetrics := computePercentiles(cpuMillicores, true)
-	memoryMetrics := computePercentiles(memoryBytes, false)
+	cpuMetrics := computeTrimmedPercentiles(cpuMillicores, true, p.outlierTrimFraction)
+	var memoryMetrics ResourceMetrics
+	if p.restartAwareMemory {
+		// A restarted container can be reported as a new series, so all series count
+		memoryMetrics = computeRestartAdjustedPercentiles(memoryBytes, p.outlierTrimFraction)
+	} else {
+		memoryMetrics = computeTrimmedPercentiles(memoryBytes[0], false, p.outlierTrimFraction)
+	}
+	cpuMetrics.Coverage = windowCoverage(cpuMetrics.Samples, usageQueryStep, window)
+	memoryMetrics.Coverage = windowCoverage(memoryMetrics.Samples, usageQueryStep, window)
 
 	return &ContainerMetrics{
-		CPU:    cpuMetrics,
-		Memory: memoryMetrics,
+		CPU:          cpuMetrics,
+		Memory:       memoryMetrics,
+		NewestSample: olderSample(cpuNewest, memoryNewest),
 	}, nil
 }
 
+// GetContainerDailyPeaks queries Prometheus for container CPU and memory usage over the last
+// days days and computes the percentiles of each day.
+func (p *PrometheusProvider) GetContainerDailyPeaks(ctx context.Context, namespace, podName, containerName string, days int) (*DailyPeaks, error) {
+	cpuQuery := fmt.Sprintf(
+		`rate(container_cpu_usage_seconds_total{namespace="%s",pod="%s",container="%s"}[5m])`,
+		namespace, podName, containerName,
+	)
+	memoryQuery := fmt.Sprintf(
+		`container_memory_working_set_bytes{namespace="%s",pod="%s",container="%s"}`,
+		namespace, podName, containerName,
+	)
+	return queryDailyPeaks(ctx, p.client, cpuQuery, memoryQuery, days, p.startupExclusionPeriod)
+}
+
 // HealthCheck verifies that Prometheus is accessible.
 func (p *PrometheusProvider) HealthCheck(ctx context.Context) error {
 	// Query Prometheus build info as a health check
@@ -102,21 +189,32 @@ func (p *PrometheusProvider) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
-// queryRange executes a range query and returns the sample values.
-func (p *PrometheusProvider) queryRange(ctx context.Context, query string, window time.Duration) ([]float64, error) {
+// QueryValue evaluates an instant query and returns the value of its first sample.
+func (p *PrometheusProvider) QueryValue(ctx context.Context, query string) (float64, error) {
+	return queryValue(ctx, p.client, query)
+}
+
+// ValidateQuery verifies that Prometheus accepts the query.
+func (p *PrometheusProvider) ValidateQuery(ctx context.Context, query string) error {
+	return validateQuery(ctx, p.client, query)
+}
+
+// queryRange executes a range query and returns the sample values of each non-empty series and
+// the time of the newest sample.
+func (p *PrometheusProvider) queryRange(ctx context.Context, query string, window time.Duration) ([][]float64, time.Time, error) {
 	end := time.Now()
 	start := end.Add(-window)
 
 	// Use 30-second step for reasonable granularity
-	step := 30 * time.Second
-
-	result, warnings, err := p.client.QueryRange(ctx, query, v1.Range{
+	queryRange := v1.Range{
 		Start: start,
 		End:   end,
-		Step:  step,
-	})
+		Step:  usageQueryStep,
+	}
+
+	result, warnings, err := p.client.QueryRange(ctx, query, queryRange)
 	if err != nil {
-		return nil, fmt.Errorf("query failed: %w", err)
+		return nil, time.Time{}, fmt.Errorf("query failed: %w", err)
 	}
 
 	if len(warnings) > 0 {
@@ -127,25 +225,103 @@ func (p *PrometheusProvider) queryRange(ctx context.Context, query string, windo
 	// Extract values from the result
 	matrix, ok := result.(model.Matrix)
 	if !ok {
-		return nil, fmt.Errorf("unexpected result type: %T", result)
+		return nil, time.Time{}, fmt.Errorf("unexpected result type: %T", result)
 	}
 
 	if len(matrix) == 0 {
-		return nil, fmt.Errorf("no data returned from Prometheus")
+		return nil, time.Time{}, fmt.Errorf("%w from Prometheus", errNoData)
 	}
 
-	// Collect all sample values from the first series
-	// (there should only be one series for a specific container)
-	samples := make([]float64, 0, len(matrix[0].Values))
-	for _, sample := range matrix[0].Values {
-		samples = append(samples, float64(sample.Value))
+	matrix = excludeStartupSamples(matrix, queryRange, p.startupExclusionPeriod)
+	series := matrixValues(matrix)
+	if len(series) == 0 {
+		return nil, time.Time{}, fmt.Errorf("no samples in result")
+	}
+
+	return series, newestSample(matrix), nil
+}
+
+// matrixValues returns the sample values of each series of a range query result, dropping
+// series without samples
+func matrixValues(matrix model.Matrix) [][]float64 {
+	series := make([][]float64, 0, len(matrix))
+	for _, stream := range matrix {
+		if len(stream.Values) == 0 {
+			continue
+		}
+		samples := make([]float64, 0, len(stream.Values))
+		for _, sample := range stream.Values {
+			samples = append(samples, float64(sample.Value))
+		}
+		series = append(series, samples)
+	}
+	return series
+}
+
+// newestSample returns the time of the newest sample of any series of a range query result
+func newestSample(matrix model.Matrix) time.Time {
+	var newest time.Time
+	for _, stream := range matrix {
+		if n := len(stream.Values); n > 0 {
+			if t := stream.Values[n-1].Timestamp.Time(); t.After(newest) {
+				newest = t
+			}
+		}
+	}
+	return newest
+}
+
+// olderSample returns the older of two newest sample times, so metrics computed from both are
+// as recent as their staler part. A zero time is unknown and ignored.
+func olderSample(a, b time.Time) time.Time {
+	switch {
+	case a.IsZero():
+		return b
+	case b.IsZero() || a.Before(b):
+		return a
+	}
+	return b
+}
+
+// bytesSeries converts memory samples of each series to int64 bytes
+func bytesSeries(series [][]float64) [][]int64 {
+	result := make([][]int64, len(series))
+	for i, samples := range series {
+		result[i] = make([]int64, len(samples))
+		for j, v := range samples {
+			result[i][j] = int64(v)
+		}
 	}
+	return result
+}
 
-	if len(samples) == 0 {
-		return nil, fmt.Errorf("no samples in result")
+// queryValue evaluates an instant query against a PromQL query API and returns the value of
+// its first sample.
+func queryValue(ctx context.Context, client v1.API, query string) (float64, error) {
+	result, _, err := client.Query(ctx, query, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("query failed: %w", err)
 	}
 
-	return samples, nil
+	switch value := result.(type) {
+	case model.Vector:
+		if len(value) == 0 {
+			return 0, fmt.Errorf("query returned no data")
+		}
+		return float64(value[0].Value), nil
+	case *model.Scalar:
+		return float64(value.Value), nil
+	default:
+		return 0, fmt.Errorf("unexpected result type: %T", result)
+	}
+}
+
+// validateQuery evaluates an instant query and only reports whether the backend rejected it.
+func validateQuery(ctx context.Context, client v1.API, query string) error {
+	if _, _, err := client.Query(ctx, query, time.Now()); err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	return nil
 }
 
 // formatDuration formats a duration for use in PromQL queries.
@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQueryValue(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/query", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Form.Get("query") {
+		case "sum(rate(http_requests_total[5m]))":
+			_, _ = fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"12.5"]}]}}`)
+		case "absent_metric":
+			_, _ = fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"parse error"}`)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	provider, err := NewPrometheusProvider(server.URL)
+	if err != nil {
+		t.Fatalf("NewPrometheusProvider() error = %v", err)
+	}
+	ctx := context.Background()
+
+	value, err := provider.QueryValue(ctx, "sum(rate(http_requests_total[5m]))")
+	if err != nil || value != 12.5 {
+		t.Errorf("QueryValue() = (%v, %v), want 12.5", value, err)
+	}
+	if _, err := provider.QueryValue(ctx, "absent_metric"); err == nil {
+		t.Error("expected an error for a query without data")
+	}
+
+	// A query without data is valid, a query the backend rejects is not
+	if err := provider.ValidateQuery(ctx, "absent_metric"); err != nil {
+		t.Errorf("ValidateQuery() error = %v for a query without data", err)
+	}
+	if err := provider.ValidateQuery(ctx, "sum("); err == nil {
+		t.Error("expected ValidateQuery() to reject an invalid query")
+	}
+}
+
+func TestGetContainerMetrics_NoMatchingSeries(t *testing.T) {
+	tests := []struct {
+		name             string
+		podSeries        string
+		wantNoMatching   bool
+		wantNoContainers bool
+	}{
+		{
+			name:           "a pod without any series is reported as mislabeled",
+			wantNoMatching: true,
+		},
+		{
+			name:             "a pod with series but none for the container has no container-level metrics",
+			podSeries:        `{"metric":{},"value":[1700000000,"2"]}`,
+			wantNoContainers: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v1/query_range", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprint(w, `{"status":"success","data":{"resultType":"matrix","result":[]}}`)
+			})
+			mux.HandleFunc("/api/v1/query", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[%s]}}`, tt.podSeries)
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			provider, err := NewPrometheusProvider(server.URL)
+			if err != nil {
+				t.Fatalf("NewPrometheusProvider() error = %v", err)
+			}
+
+			_, err = provider.GetContainerMetrics(context.Background(), "default", "app-7d4b9", "app", time.Hour)
+			if err == nil {
+				t.Fatal("expected an error for a query without series")
+			}
+			if errors.Is(err, ErrNoMatchingSeries) != tt.wantNoMatching {
+				t.Errorf("errors.Is(%v, ErrNoMatchingSeries) = %v, want %v", err, !tt.wantNoMatching, tt.wantNoMatching)
+			}
+			if errors.Is(err, ErrNoContainerMetrics) != tt.wantNoContainers {
+				t.Errorf("errors.Is(%v, ErrNoContainerMetrics) = %v, want %v", err, !tt.wantNoContainers, tt.wantNoContainers)
+			}
+		})
+	}
+}
+
+func TestOlderSample(t *testing.T) {
+	older, newer := time.Unix(1700000000, 0), time.Unix(1700000060, 0)
+	tests := []struct {
+		a, b, want time.Time
+	}{
+		{a: older, b: newer, want: older},
+		{a: newer, b: older, want: older},
+		{a: time.Time{}, b: newer, want: newer},
+		{a: older, b: time.Time{}, want: older},
+	}
+	for _, tt := range tests {
+		if got := olderSample(tt.a, tt.b); !got.Equal(tt.want) {
+			t.Errorf("olderSample(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
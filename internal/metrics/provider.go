@@ -18,6 +18,8 @@ package metrics
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -34,10 +36,67 @@ type MetricsProvider interface {
 	HealthCheck(ctx context.Context) error
 }
 
+// InformationalQuerier is implemented by providers that can evaluate arbitrary queries,
+// used for informational metrics that give recommendations context.
+type InformationalQuerier interface {
+	// QueryValue evaluates an instant query and returns the value of its first sample.
+	QueryValue(ctx context.Context, query string) (float64, error)
+
+	// ValidateQuery verifies the backend accepts the query. A query returning no data is valid.
+	ValidateQuery(ctx context.Context, query string) error
+}
+
+// PodMetricsQuerier is implemented by providers that can report usage at pod granularity, used to
+// size containers whose backend has no per-container metrics.
+type PodMetricsQuerier interface {
+	// GetPodMetrics returns CPU and memory usage statistics for a whole pod, summed over its
+	// containers, over the specified time window.
+	GetPodMetrics(ctx context.Context, namespace, podName string, window time.Duration) (*ContainerMetrics, error)
+}
+
+// ErrNoContainerMetrics is returned, wrapped, by GetContainerMetrics when the backend has no usage
+// for the container, e.g. because it only keeps pod-level metrics
+var ErrNoContainerMetrics = errors.New("no container-level metrics")
+
+// ErrNoMatchingSeries is returned, wrapped, by GetContainerMetrics when the backend has no series
+// at all for a pod that exists, which usually means its labels do not match the pod name, e.g.
+// because pods are relabeled
+var ErrNoMatchingSeries = errors.New("no matching series")
+
+// errNoData is returned, wrapped, by range queries that match no series
+var errNoData = errors.New("no data returned")
+
+// containerMetricsError marks the error of a container's usage queries with ErrNoContainerMetrics
+// when a query matched no series
+func containerMetricsError(err error) error {
+	if errors.Is(err, errNoData) {
+		return fmt.Errorf("%w: %w", ErrNoContainerMetrics, err)
+	}
+	return err
+}
+
+// DailyPeakQuerier is implemented by providers that keep timestamped samples over days, used to
+// size to recent daily usage peaks.
+type DailyPeakQuerier interface {
+	// GetContainerDailyPeaks returns the usage percentiles of each of the last days days that
+	// have samples, oldest first. Days are calendar days in UTC.
+	GetContainerDailyPeaks(ctx context.Context, namespace, podName, containerName string, days int) (*DailyPeaks, error)
+}
+
+// DailyPeaks contains the usage percentiles of each day with samples, oldest first.
+type DailyPeaks struct {
+	CPU    []ResourceMetrics
+	Memory []ResourceMetrics
+}
+
 // ContainerMetrics contains resource usage statistics for a single container.
 type ContainerMetrics struct {
 	CPU    ResourceMetrics
 	Memory ResourceMetrics
+
+	// NewestSample is the time of the newest data point the metrics were computed from, the older
+	// of the newest CPU and memory samples. It is zero when the provider does not know.
+	NewestSample time.Time
 }
 
 // ResourceMetrics contains percentile-based statistics for a resource type.
@@ -45,7 +104,23 @@ type ResourceMetrics struct {
 	P50     resource.Quantity // 50th percentile (median)
 	P90     resource.Quantity // 90th percentile
 	P99     resource.Quantity // 99th percentile
+	Max     resource.Quantity // Highest sample
 	Samples int               // Number of data points used to compute percentiles
+
+	// Coverage is the share of the window the samples span, from 0 to 1. Usage observed over a
+	// fraction of the window, e.g. of a new pod or with gaps in scraping, is less representative.
+	Coverage float64
+}
+
+// usageQueryStep is the resolution of the range queries over the rolling window
+const usageQueryStep = 30 * time.Second
+
+// windowCoverage returns the share of window covered by samples taken step apart, capped at 1
+func windowCoverage(samples int, step, window time.Duration) float64 {
+	if samples <= 0 || window <= 0 {
+		return 0
+	}
+	return min(float64(samples)*step.Seconds()/window.Seconds(), 1)
 }
 
 // MetricsError represents an error from the metrics provider.
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+// memoryResetRatio is the fraction of the previous memory sample below which a sample is taken
+// as a container restart. A restarted container starts over with a small working set, while
+// the working set of a running container rarely halves between two samples.
+const memoryResetRatio = 0.5
+
+// splitAtMemoryResets splits a memory usage series into the segments between container restarts
+func splitAtMemoryResets(samples []int64) [][]int64 {
+	var segments [][]int64
+	start := 0
+	for i := 1; i < len(samples); i++ {
+		if float64(samples[i]) < memoryResetRatio*float64(samples[i-1]) {
+			segments = append(segments, samples[start:i])
+			start = i
+		}
+	}
+	if start < len(samples) {
+		segments = append(segments, samples[start:])
+	}
+	return segments
+}
+
+// computeRestartAdjustedPercentiles computes memory percentiles from the usage series of a
+// container that may have restarted within the window. Each series (a restarted container can
+// be reported as a new series) is split at usage resets, and every percentile is the highest
+// across the resulting segments.
+//
+// Pooling all samples would understate the steady-state need, since the low usage right after
+// each restart drags the percentiles down.
+func computeRestartAdjustedPercentiles(series [][]int64, trimFraction float64) ResourceMetrics {
+	var result ResourceMetrics
+	for _, samples := range series {
+		for _, segment := range splitAtMemoryResets(samples) {
+			segmentMetrics := computeTrimmedPercentiles(segment, false, trimFraction)
+			if segmentMetrics.P50.Cmp(result.P50) > 0 {
+				result.P50 = segmentMetrics.P50
+			}
+			if segmentMetrics.P90.Cmp(result.P90) > 0 {
+				result.P90 = segmentMetrics.P90
+			}
+			if segmentMetrics.P99.Cmp(result.P99) > 0 {
+				result.P99 = segmentMetrics.P99
+			}
+			if segmentMetrics.Max.Cmp(result.Max) > 0 {
+				result.Max = segmentMetrics.Max
+			}
+			result.Samples += segmentMetrics.Samples
+		}
+	}
+	return result
+}
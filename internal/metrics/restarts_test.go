@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const mi = 1024 * 1024
+
+func TestSplitAtMemoryResets(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []int64
+		want    [][]int64
+	}{
+		{
+			name: "empty series",
+		},
+		{
+			name:    "steady usage is a single segment",
+			samples: []int64{500 * mi, 520 * mi, 480 * mi, 510 * mi},
+			want:    [][]int64{{500 * mi, 520 * mi, 480 * mi, 510 * mi}},
+		},
+		{
+			name:    "a drop to less than half starts a new segment",
+			samples: []int64{800 * mi, 1000 * mi, 100 * mi, 400 * mi, 900 * mi},
+			want:    [][]int64{{800 * mi, 1000 * mi}, {100 * mi, 400 * mi, 900 * mi}},
+		},
+		{
+			name:    "a drop to exactly half is not a restart",
+			samples: []int64{1000 * mi, 500 * mi},
+			want:    [][]int64{{1000 * mi, 500 * mi}},
+		},
+		{
+			name:    "repeated restarts",
+			samples: []int64{900 * mi, 50 * mi, 950 * mi, 60 * mi},
+			want:    [][]int64{{900 * mi}, {50 * mi, 950 * mi}, {60 * mi}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitAtMemoryResets(tt.samples); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitAtMemoryResets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeRestartAdjustedPercentiles(t *testing.T) {
+	// The container ran at its 1Gi steady state, was OOM-killed and spent the rest of the window
+	// warming up again
+	var samples []int64
+	for i := 0; i < 10; i++ {
+		samples = append(samples, 1024*mi)
+	}
+	for i := int64(1); i <= 12; i++ {
+		samples = append(samples, i*40*mi)
+	}
+
+	pooled := computePercentiles(samples, false)
+	adjusted := computeRestartAdjustedPercentiles([][]int64{samples}, 0)
+
+	if adjusted.Samples != len(samples) {
+		t.Errorf("Samples = %d, want every sample counted (%d)", adjusted.Samples, len(samples))
+	}
+	// Most pooled samples are warm-up, which pulls the pooled median below the steady state
+	if pooled.P50.Value() >= 1024*mi {
+		t.Fatalf("pooled P50 = %s, want the warm-up to pull it below 1Gi", pooled.P50.String())
+	}
+	if adjusted.P50.Value() != 1024*mi || adjusted.P90.Value() != 1024*mi || adjusted.P99.Value() != 1024*mi {
+		t.Errorf("adjusted percentiles = %s/%s/%s, want the 1Gi steady state",
+			adjusted.P50.String(), adjusted.P90.String(), adjusted.P99.String())
+	}
+}
+
+func TestComputeRestartAdjustedPercentiles_TakesHighestSegment(t *testing.T) {
+	// Each series is a separate segment: a container that ran at 800Mi before its restart and
+	// at 200Mi after it is sized for the 800Mi it needed
+	before := []int64{780 * mi, 800 * mi, 820 * mi}
+	after := []int64{190 * mi, 200 * mi, 210 * mi, 200 * mi, 200 * mi}
+
+	adjusted := computeRestartAdjustedPercentiles([][]int64{before, after}, 0)
+
+	if adjusted.P50.Value() != 800*mi {
+		t.Errorf("P50 = %s, want the 800Mi median of the segment before the restart", adjusted.P50.String())
+	}
+	if adjusted.Samples != len(before)+len(after) {
+		t.Errorf("Samples = %d, want %d", adjusted.Samples, len(before)+len(after))
+	}
+}
+
+func TestOTelPromQLProvider_RestartAwareMemory(t *testing.T) {
+	var queries []string
+	server := newOTelTestBackend(t, map[string][]string{
+		otelMetricCPU: {"0.1", "0.1", "0.1", "0.1", "0.1", "0.1"},
+		// The working set resets from 1000Mi to 100Mi when the container restarts
+		otelMetricMemory: {"943718400", "1048576000", "1048576000", "104857600", "209715200", "314572800"},
+	}, &queries)
+
+	provider, err := NewOTelPromQLProvider(server.URL, "")
+	if err != nil {
+		t.Fatalf("NewOTelPromQLProvider() error = %v", err)
+	}
+
+	pooled, err := provider.GetContainerMetrics(context.Background(), "default", "web-0", "app", time.Hour)
+	if err != nil {
+		t.Fatalf("GetContainerMetrics() error = %v", err)
+	}
+
+	provider.SetRestartAwareMemory(true)
+	adjusted, err := provider.GetContainerMetrics(context.Background(), "default", "web-0", "app", time.Hour)
+	if err != nil {
+		t.Fatalf("GetContainerMetrics() error = %v", err)
+	}
+
+	if adjusted.Memory.P50.Value() != 1000*mi {
+		t.Errorf("restart-aware P50 = %s, want the 1000Mi median before the restart", adjusted.Memory.P50.String())
+	}
+	if pooled.Memory.P50.Cmp(adjusted.Memory.P50) >= 0 {
+		t.Errorf("pooled P50 = %s, want it below the restart-aware P50 %s",
+			pooled.Memory.P50.String(), adjusted.Memory.P50.String())
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// excludeStartupSamples drops the samples of each series taken within period after its container
+// started, so startup spikes (e.g. JVM warm-up) do not count toward steady-state usage.
+//
+// A restarted container is reported as a new series, so a series whose first sample comes more
+// than a step after the start of the range began with a container start. Series present from the
+// start of the range started before it, and their startup is not part of the range.
+//
+// When no sample would be left, the matrix is returned unchanged: a container that is still
+// starting up has no steady state to size for yet.
+func excludeStartupSamples(matrix model.Matrix, queryRange v1.Range, period time.Duration) model.Matrix {
+	if period <= 0 {
+		return matrix
+	}
+
+	rangeStart := queryRange.Start.Add(queryRange.Step)
+	filtered := make(model.Matrix, 0, len(matrix))
+	remaining := 0
+	for _, stream := range matrix {
+		if len(stream.Values) == 0 || !stream.Values[0].Timestamp.Time().After(rangeStart) {
+			filtered = append(filtered, stream)
+			remaining += len(stream.Values)
+			continue
+		}
+
+		steadyFrom := stream.Values[0].Timestamp.Time().Add(period)
+		values := make([]model.SamplePair, 0, len(stream.Values))
+		for _, sample := range stream.Values {
+			if !sample.Timestamp.Time().Before(steadyFrom) {
+				values = append(values, sample)
+			}
+		}
+		filtered = append(filtered, &model.SampleStream{Metric: stream.Metric, Values: values})
+		remaining += len(values)
+	}
+
+	if remaining == 0 {
+		return matrix
+	}
+	return filtered
+}
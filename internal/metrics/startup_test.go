@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// startupTestStream returns a series with a sample every 30 seconds from start
+func startupTestStream(start time.Time, values ...float64) *model.SampleStream {
+	stream := &model.SampleStream{Metric: model.Metric{}}
+	for i, v := range values {
+		stream.Values = append(stream.Values, model.SamplePair{
+			Timestamp: model.TimeFromUnix(start.Add(time.Duration(i) * 30 * time.Second).Unix()),
+			Value:     model.SampleValue(v),
+		})
+	}
+	return stream
+}
+
+func TestExcludeStartupSamples(t *testing.T) {
+	rangeStart := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	queryRange := v1.Range{Start: rangeStart, End: rangeStart.Add(time.Hour), Step: 30 * time.Second}
+	restart := rangeStart.Add(20 * time.Minute)
+
+	tests := []struct {
+		name   string
+		matrix model.Matrix
+		period time.Duration
+		want   [][]float64
+	}{
+		{
+			name:   "disabled",
+			matrix: model.Matrix{startupTestStream(restart, 900, 800, 100, 100)},
+			period: 0,
+			want:   [][]float64{{900, 800, 100, 100}},
+		},
+		{
+			name:   "samples after a start within the range are dropped",
+			matrix: model.Matrix{startupTestStream(restart, 900, 800, 100, 100)},
+			period: time.Minute,
+			want:   [][]float64{{100, 100}},
+		},
+		{
+			name: "a series present since the start of the range is kept",
+			matrix: model.Matrix{
+				startupTestStream(rangeStart, 900, 100, 100),
+				startupTestStream(restart, 900, 800, 100, 100),
+			},
+			period: time.Minute,
+			want:   [][]float64{{900, 100, 100}, {100, 100}},
+		},
+		{
+			name:   "a container still starting up keeps its samples",
+			matrix: model.Matrix{startupTestStream(restart, 900, 800)},
+			period: 5 * time.Minute,
+			want:   [][]float64{{900, 800}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matrixValues(excludeStartupSamples(tt.matrix, queryRange, tt.period))
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("excludeStartupSamples() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrometheusProvider_StartupExclusion(t *testing.T) {
+	// The container restarted ten minutes into the window and used 1 core while starting up,
+	// then settled at 100m
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/query_range", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		start, err := strconv.ParseFloat(r.Form.Get("start"), 64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		restart := int64(start) + 600
+
+		startup, steady := "1", "0.1"
+		if strings.Contains(r.Form.Get("query"), "memory") {
+			startup, steady = "536870912", "268435456"
+		}
+		var points []string
+		for i := int64(0); i < 40; i++ {
+			v := steady
+			if i < 6 {
+				v = startup
+			}
+			points = append(points, fmt.Sprintf(`[%d,"%s"]`, restart+i*30, v))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[%s]}]}}`,
+			strings.Join(points, ","))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name        string
+		period      time.Duration
+		wantSamples int
+		wantCPUP99  int64
+		wantMemP99  int64
+	}{
+		{name: "all samples", period: 0, wantSamples: 40, wantCPUP99: 1000, wantMemP99: 536870912},
+		{name: "startup excluded", period: 3 * time.Minute, wantSamples: 34, wantCPUP99: 100, wantMemP99: 268435456},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewPrometheusProvider(server.URL)
+			if err != nil {
+				t.Fatalf("NewPrometheusProvider() error = %v", err)
+			}
+			provider.SetStartupExclusionPeriod(tt.period)
+
+			containerMetrics, err := provider.GetContainerMetrics(context.Background(), "default", "web-0", "app", time.Hour)
+			if err != nil {
+				t.Fatalf("GetContainerMetrics() error = %v", err)
+			}
+			if containerMetrics.CPU.Samples != tt.wantSamples || containerMetrics.CPU.P99.MilliValue() != tt.wantCPUP99 {
+				t.Errorf("CPU = %d samples with P99 %s, want %d samples with P99 %dm",
+					containerMetrics.CPU.Samples, containerMetrics.CPU.P99.String(), tt.wantSamples, tt.wantCPUP99)
+			}
+			if containerMetrics.Memory.Samples != tt.wantSamples || containerMetrics.Memory.P99.Value() != tt.wantMemP99 {
+				t.Errorf("memory = %d samples with P99 %s, want %d samples with P99 %d",
+					containerMetrics.Memory.Samples, containerMetrics.Memory.P99.String(), tt.wantSamples, tt.wantMemP99)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Event reasons that are never aggregated because they signal important one-off actions
+const (
+	// EventReasonRollback indicates a previously applied change was rolled back
+	EventReasonRollback = "Rollback"
+
+	// EventReasonCircuitOpen indicates a circuit breaker opened and processing was halted
+	EventReasonCircuitOpen = "CircuitOpen"
+)
+
+// DefaultEventAggregationWindow is the default window in which identical events are aggregated
+const DefaultEventAggregationWindow = 5 * time.Minute
+
+// AggregatingRecorder wraps a Kubernetes event recorder and deduplicates identical events.
+// The first occurrence of an event is emitted immediately. Repeated identical events within
+// the aggregation window are counted and emitted as a single summary event once the window
+// elapses, similar to kubelet's event aggregation.
+type AggregatingRecorder struct {
+	recorder      record.EventRecorder
+	window        time.Duration
+	neverSuppress map[string]bool
+	now           func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*aggregatedEvent
+}
+
+type aggregatedEvent struct {
+	object     runtime.Object
+	eventType  string
+	reason     string
+	message    string
+	firstSeen  time.Time
+	suppressed int
+}
+
+// NewAggregatingRecorder creates an event recorder that aggregates identical events within
+// the given window. A window of zero or less disables aggregation. Rollback and circuit-open
+// events, plus any additional reasons given, are always emitted.
+func NewAggregatingRecorder(recorder record.EventRecorder, window time.Duration, neverSuppressReasons ...string) *AggregatingRecorder {
+	neverSuppress := map[string]bool{
+		EventReasonRollback:    true,
+		EventReasonCircuitOpen: true,
+	}
+	for _, reason := range neverSuppressReasons {
+		neverSuppress[reason] = true
+	}
+
+	return &AggregatingRecorder{
+		recorder:      recorder,
+		window:        window,
+		neverSuppress: neverSuppress,
+		now:           time.Now,
+		entries:       make(map[string]*aggregatedEvent),
+	}
+}
+
+// Event records an event, aggregating it with identical events seen within the window
+func (ar *AggregatingRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if ar.window <= 0 || ar.neverSuppress[reason] {
+		ar.recorder.Event(object, eventtype, reason, message)
+		return
+	}
+
+	key := aggregationKey(object, eventtype, reason, message)
+	now := ar.now()
+
+	ar.mu.Lock()
+	entry, exists := ar.entries[key]
+	if exists && now.Sub(entry.firstSeen) < ar.window {
+		// Identical event within the window, count it instead of emitting
+		entry.suppressed++
+		ar.mu.Unlock()
+		return
+	}
+
+	// Start a new window for this event
+	var summary *aggregatedEvent
+	if exists && entry.suppressed > 0 {
+		summary = entry
+	}
+	ar.entries[key] = &aggregatedEvent{
+		object:    object,
+		eventType: eventtype,
+		reason:    reason,
+		message:   message,
+		firstSeen: now,
+	}
+	ar.mu.Unlock()
+
+	if summary != nil {
+		ar.emitSummary(summary)
+	}
+	ar.recorder.Event(object, eventtype, reason, message)
+}
+
+// Eventf records a formatted event, aggregating it with identical events seen within the window
+func (ar *AggregatingRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	ar.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf records an annotated event. Annotated events are not aggregated because
+// their annotations may differ between otherwise identical events.
+func (ar *AggregatingRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	ar.recorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+}
+
+// Flush emits summary events for aggregation windows that have elapsed and drops expired entries.
+// It should be called periodically (e.g. at the end of each reconciliation) so that counts for
+// events that stop recurring are still reported.
+func (ar *AggregatingRecorder) Flush() {
+	now := ar.now()
+
+	ar.mu.Lock()
+	var summaries []*aggregatedEvent
+	for key, entry := range ar.entries {
+		if now.Sub(entry.firstSeen) < ar.window {
+			continue
+		}
+		if entry.suppressed > 0 {
+			summaries = append(summaries, entry)
+		}
+		delete(ar.entries, key)
+	}
+	ar.mu.Unlock()
+
+	for _, summary := range summaries {
+		ar.emitSummary(summary)
+	}
+}
+
+// emitSummary emits a single event reporting how many identical events were aggregated
+func (ar *AggregatingRecorder) emitSummary(entry *aggregatedEvent) {
+	message := fmt.Sprintf("%s (repeated %d more time(s) in the last %s)", entry.message, entry.suppressed, ar.window)
+	ar.recorder.Event(entry.object, entry.eventType, entry.reason, message)
+}
+
+// aggregationKey builds a key identifying identical events for the same object
+func aggregationKey(object runtime.Object, eventtype, reason, message string) string {
+	objectKey := fmt.Sprintf("%T", object)
+	if accessor, err := meta.Accessor(object); err == nil {
+		objectKey = fmt.Sprintf("%s/%s/%s/%s", objectKey, accessor.GetNamespace(), accessor.GetName(), accessor.GetUID())
+	}
+	return fmt.Sprintf("%s|%s|%s|%s", objectKey, eventtype, reason, message)
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeClock provides a controllable time source for aggregation tests
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestAggregator(window time.Duration) (*AggregatingRecorder, *mockEventRecorder, *fakeClock) {
+	mockRecorder := &mockEventRecorder{}
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	aggregator := NewAggregatingRecorder(mockRecorder, window)
+	aggregator.now = clock.Now
+	return aggregator, mockRecorder, clock
+}
+
+func newTestPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+	}
+}
+
+func TestAggregatingRecorder_DeduplicatesWithinWindow(t *testing.T) {
+	aggregator, mockRecorder, clock := newTestAggregator(time.Minute)
+	pod := newTestPod("test-pod")
+
+	for i := 0; i < 5; i++ {
+		aggregator.Event(pod, corev1.EventTypeWarning, "ProcessingFailed", "failed to process")
+		clock.now = clock.now.Add(time.Second)
+	}
+
+	if len(mockRecorder.events) != 1 {
+		t.Fatalf("expected 1 event within window, got %d", len(mockRecorder.events))
+	}
+
+	// After the window elapses, the next identical event emits a summary and starts a new window
+	clock.now = clock.now.Add(time.Minute)
+	aggregator.Event(pod, corev1.EventTypeWarning, "ProcessingFailed", "failed to process")
+
+	if len(mockRecorder.events) != 3 {
+		t.Fatalf("expected summary and new event after window, got %d events", len(mockRecorder.events))
+	}
+	if !strings.Contains(mockRecorder.events[1].message, "repeated 4 more time(s)") {
+		t.Errorf("summary event should report the suppressed count, got %q", mockRecorder.events[1].message)
+	}
+	if mockRecorder.events[2].message != "failed to process" {
+		t.Errorf("expected original message after summary, got %q", mockRecorder.events[2].message)
+	}
+}
+
+func TestAggregatingRecorder_DistinctEventsNotAggregated(t *testing.T) {
+	aggregator, mockRecorder, _ := newTestAggregator(time.Minute)
+
+	aggregator.Event(newTestPod("pod-a"), corev1.EventTypeWarning, "ProcessingFailed", "failed")
+	aggregator.Event(newTestPod("pod-b"), corev1.EventTypeWarning, "ProcessingFailed", "failed")
+	aggregator.Event(newTestPod("pod-a"), corev1.EventTypeWarning, "DiscoveryFailed", "failed")
+	aggregator.Event(newTestPod("pod-a"), corev1.EventTypeWarning, "ProcessingFailed", "different message")
+
+	if len(mockRecorder.events) != 4 {
+		t.Errorf("expected 4 distinct events, got %d", len(mockRecorder.events))
+	}
+}
+
+func TestAggregatingRecorder_FlushEmitsExpiredSummaries(t *testing.T) {
+	aggregator, mockRecorder, clock := newTestAggregator(time.Minute)
+	pod := newTestPod("test-pod")
+
+	aggregator.Event(pod, corev1.EventTypeNormal, "WorkloadSkipped", "skipped")
+	aggregator.Event(pod, corev1.EventTypeNormal, "WorkloadSkipped", "skipped")
+	aggregator.Event(pod, corev1.EventTypeNormal, "WorkloadSkipped", "skipped")
+
+	// Flushing before the window elapses emits nothing
+	aggregator.Flush()
+	if len(mockRecorder.events) != 1 {
+		t.Fatalf("expected 1 event before window elapsed, got %d", len(mockRecorder.events))
+	}
+
+	clock.now = clock.now.Add(2 * time.Minute)
+	aggregator.Flush()
+	if len(mockRecorder.events) != 2 {
+		t.Fatalf("expected summary event after flush, got %d events", len(mockRecorder.events))
+	}
+	if !strings.Contains(mockRecorder.events[1].message, "repeated 2 more time(s)") {
+		t.Errorf("unexpected summary message %q", mockRecorder.events[1].message)
+	}
+
+	// Expired entries are dropped so the next event is emitted immediately
+	aggregator.Event(pod, corev1.EventTypeNormal, "WorkloadSkipped", "skipped")
+	if len(mockRecorder.events) != 3 {
+		t.Errorf("expected event to be emitted after flush, got %d events", len(mockRecorder.events))
+	}
+}
+
+func TestAggregatingRecorder_ZeroWindowDisablesAggregation(t *testing.T) {
+	aggregator, mockRecorder, _ := newTestAggregator(0)
+	pod := newTestPod("test-pod")
+
+	for i := 0; i < 3; i++ {
+		aggregator.Event(pod, corev1.EventTypeWarning, "ProcessingFailed", "failed")
+	}
+
+	if len(mockRecorder.events) != 3 {
+		t.Errorf("expected all events with aggregation disabled, got %d", len(mockRecorder.events))
+	}
+}
+
+// Property: Important one-off events (rollback, circuit-open) are never suppressed
+func TestProperty_ImportantEventsNeverSuppressed(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("rollback and circuit-open events are always emitted", prop.ForAll(
+		func(repeats int, useRollback bool, message string) bool {
+			aggregator, mockRecorder, _ := newTestAggregator(time.Hour)
+			pod := newTestPod("test-pod")
+
+			reason := EventReasonCircuitOpen
+			if useRollback {
+				reason = EventReasonRollback
+			}
+
+			for i := 0; i < repeats; i++ {
+				aggregator.Event(pod, corev1.EventTypeWarning, reason, message)
+			}
+
+			return len(mockRecorder.events) == repeats
+		},
+		gen.IntRange(1, 20),
+		gen.Bool(),
+		gen.AlphaString(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
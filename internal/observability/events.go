@@ -18,6 +18,7 @@ package observability
 
 import (
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,6 +32,27 @@ const (
 
 	// EventReasonSSAConflict indicates a field ownership conflict
 	EventReasonSSAConflict = "SSAConflict"
+
+	// EventReasonInvalidUpdateMethod indicates an unknown optipod.io/update-method annotation value
+	EventReasonInvalidUpdateMethod = "InvalidUpdateMethod"
+
+	// EventReasonAutoscalerConstraint indicates CPU requests were not lowered because an autoscaler scales on CPU
+	EventReasonAutoscalerConstraint = "AutoscalerConstraint"
+
+	// EventReasonEvictionPressure indicates memory was raised because pods were evicted for node memory pressure
+	EventReasonEvictionPressure = "EvictionPressure"
+
+	// EventReasonInvalidPinnedPolicy indicates the optipod.io/policy annotation refers to a policy that cannot handle the workload
+	EventReasonInvalidPinnedPolicy = "InvalidPinnedPolicy"
+
+	// EventReasonQoSDowngrade indicates an update made Guaranteed containers Burstable
+	EventReasonQoSDowngrade = "QoSDowngrade"
+
+	// EventReasonPolicySummary summarizes the effective configuration of a created or changed policy
+	EventReasonPolicySummary = "PolicySummary"
+
+	// EventReasonWorkloadQuarantined indicates a workload failing in consecutive reconciliations is skipped for a while
+	EventReasonWorkloadQuarantined = "WorkloadQuarantined"
 )
 
 // EventRecorder wraps the Kubernetes event recorder with OptiPod-specific event creation methods
@@ -93,6 +115,13 @@ func (er *EventRecorder) RecordWorkloadSkipped(object runtime.Object, workloadNa
 	er.recorder.Event(object, corev1.EventTypeNormal, "WorkloadSkipped", message)
 }
 
+// RecordApplySkipped records a change held back for a classified cause, such as an unsafe memory
+// decrease or a recreate cooldown. The cause (e.g. UnsafeMemoryDecrease) is the event reason.
+func (er *EventRecorder) RecordApplySkipped(object runtime.Object, workloadName, namespace, cause, reason string) {
+	message := fmt.Sprintf("Skipped applying recommendations to workload %s/%s: %s", namespace, workloadName, reason)
+	er.recorder.Event(object, corev1.EventTypeNormal, cause, message)
+}
+
 // RecordSSAOwnershipTaken records an event when OptipPod takes field ownership via Server-Side Apply
 func (er *EventRecorder) RecordSSAOwnershipTaken(object runtime.Object, workloadName, namespace, previousOwner string) {
 	var message string
@@ -109,3 +138,45 @@ func (er *EventRecorder) RecordSSAConflict(object runtime.Object, workloadName,
 	message := fmt.Sprintf("Server-Side Apply conflict for workload %s/%s: field manager '%s' owns conflicting fields. Error: %v. Suggestion: Review field ownership or enable Force flag to take ownership", namespace, workloadName, conflictingManager, err)
 	er.recorder.Event(object, corev1.EventTypeWarning, EventReasonSSAConflict, message)
 }
+
+// RecordInvalidUpdateMethod records an event when a workload's update method annotation is not a known method
+func (er *EventRecorder) RecordInvalidUpdateMethod(object runtime.Object, workloadName, namespace, value string) {
+	message := fmt.Sprintf("Ignoring invalid update method %q for workload %s/%s, using the policy's update strategy. Suggestion: Set optipod.io/update-method to recreate, in-place or requests-only", value, namespace, workloadName)
+	er.recorder.Event(object, corev1.EventTypeWarning, EventReasonInvalidUpdateMethod, message)
+}
+
+// RecordAutoscalerConstraint records an event when CPU requests were held because an autoscaler scales the workload on CPU
+func (er *EventRecorder) RecordAutoscalerConstraint(object runtime.Object, workloadName, namespace, autoscaler string) {
+	message := fmt.Sprintf("Not lowering CPU requests of workload %s/%s because %s scales it on CPU utilization, which is measured against the request", namespace, workloadName, autoscaler)
+	er.recorder.Event(object, corev1.EventTypeNormal, EventReasonAutoscalerConstraint, message)
+}
+
+// RecordEvictionPressure records an event when memory requests were raised because pods of the workload were evicted for node memory pressure
+func (er *EventRecorder) RecordEvictionPressure(object runtime.Object, workloadName, namespace string, evictions int32) {
+	message := fmt.Sprintf("Raising memory requests of workload %s/%s because %d pod(s) were evicted or are being evicted for node memory pressure", namespace, workloadName, evictions)
+	er.recorder.Event(object, corev1.EventTypeWarning, EventReasonEvictionPressure, message)
+}
+
+// RecordInvalidPinnedPolicy records an event when a workload's optipod.io/policy annotation refers to a policy that cannot handle it
+func (er *EventRecorder) RecordInvalidPinnedPolicy(object runtime.Object, workloadName, namespace, ref, reason string) {
+	message := fmt.Sprintf("Ignoring pinned policy %q for workload %s/%s because %s, matching policies by selector instead. Suggestion: Set optipod.io/policy to <namespace>/<name> of an enabled policy matching the workload", ref, namespace, workloadName, reason)
+	er.recorder.Event(object, corev1.EventTypeWarning, EventReasonInvalidPinnedPolicy, message)
+}
+
+// RecordQoSDowngrade records an event when an update made Guaranteed containers of the workload Burstable
+func (er *EventRecorder) RecordQoSDowngrade(object runtime.Object, workloadName, namespace string, containers []string) {
+	message := fmt.Sprintf("Updating container(s) %v of workload %s/%s changes their QoS class from Guaranteed to Burstable, as the policy allows QoS class changes. Suggestion: Unset updateStrategy.allowQoSClassChange to keep limits equal to requests", containers, namespace, workloadName)
+	er.recorder.Event(object, corev1.EventTypeWarning, EventReasonQoSDowngrade, message)
+}
+
+// RecordPolicySummary records the effective configuration of a policy after it was created or its spec changed
+func (er *EventRecorder) RecordPolicySummary(object runtime.Object, generation int64, summary string) {
+	message := fmt.Sprintf("Policy generation %d is in effect: %s", generation, summary)
+	er.recorder.Event(object, corev1.EventTypeNormal, EventReasonPolicySummary, message)
+}
+
+// RecordWorkloadQuarantined records an event when a persistently failing workload is skipped until its quarantine expires
+func (er *EventRecorder) RecordWorkloadQuarantined(object runtime.Object, workloadName, namespace string, failures int32, until time.Time, lastErr string) {
+	message := fmt.Sprintf("Workload %s/%s failed in %d consecutive reconciliations and is skipped until %s: %s. Suggestion: Fix the cause and change the policy, or wait for the quarantine to expire", namespace, workloadName, failures, until.UTC().Format(time.RFC3339), lastErr)
+	er.recorder.Event(object, corev1.EventTypeWarning, EventReasonWorkloadQuarantined, message)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logSamplingTick is the interval in which identical log lines are counted for sampling
+const logSamplingTick = time.Second
+
+// LogSampling returns a zap option that samples repetitive log lines, such as the per-workload
+// lines of a large reconcile. Within each second the first initial lines with the same message
+// and level are written, then every thereafter-th one. Warn and error lines are never sampled.
+func LogSampling(initial, thereafter int) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &sampledCore{
+			Core:    core,
+			sampled: zapcore.NewSamplerWithOptions(core, logSamplingTick, initial, thereafter),
+		}
+	})
+}
+
+// sampledCore writes warn and error entries through the wrapped core and samples the others
+type sampledCore struct {
+	zapcore.Core
+	sampled zapcore.Core
+}
+
+func (c *sampledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &sampledCore{Core: c.Core.With(fields), sampled: c.sampled.With(fields)}
+}
+
+func (c *sampledCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level >= zapcore.WarnLevel {
+		return c.Core.Check(entry, checked)
+	}
+	return c.sampled.Check(entry, checked)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogSampling(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core, LogSampling(2, 5)).With(zap.String("policy", "default/test"))
+
+	for range 12 {
+		logger.Debug("Processing workload")
+		logger.Info("Applied recommendation")
+		logger.Error("Failed to apply recommendation")
+	}
+
+	// The first two lines per message are written, then every fifth: the 7th and 12th
+	if got := logs.FilterMessage("Processing workload").Len(); got != 4 {
+		t.Errorf("wrote %d of 12 debug lines, want 4", got)
+	}
+	if got := logs.FilterMessage("Applied recommendation").Len(); got != 4 {
+		t.Errorf("wrote %d of 12 info lines, want 4", got)
+	}
+	if got := logs.FilterMessage("Failed to apply recommendation").Len(); got != 12 {
+		t.Errorf("wrote %d of 12 error lines, want all of them", got)
+	}
+
+	// Fields added with With are kept on sampled lines
+	for _, entry := range logs.FilterMessage("Processing workload").All() {
+		if entry.ContextMap()["policy"] != "default/test" {
+			t.Errorf("sampled line lost its fields: %v", entry.ContextMap())
+		}
+	}
+}
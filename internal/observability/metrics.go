@@ -17,6 +17,8 @@ limitations under the License.
 package observability
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -49,6 +51,15 @@ var (
 		[]string{"namespace", "policy", "reason"},
 	)
 
+	// WorkloadsQuarantined tracks the number of workloads skipped because their processing keeps failing
+	WorkloadsQuarantined = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "optipod_workloads_quarantined",
+			Help: "Number of workloads quarantined after failing in consecutive reconciliations",
+		},
+		[]string{"namespace", "policy"},
+	)
+
 	// ReconciliationDuration tracks the duration of reconciliation cycles
 	ReconciliationDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -59,6 +70,17 @@ var (
 		[]string{"policy"},
 	)
 
+	// ReconcilePhaseDuration tracks the time spent in each phase of a reconciliation. Discovery is
+	// observed once per reconciliation; the other phases once per workload or container.
+	ReconcilePhaseDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "optipod_reconcile_phase_duration_seconds",
+			Help:    "Duration of reconciliation phases (discovery, metrics, recommendation, apply) in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"policy", "phase"},
+	)
+
 	// MetricsCollectionDuration tracks the duration of metrics collection operations
 	MetricsCollectionDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -87,6 +109,15 @@ var (
 		[]string{"policy"},
 	)
 
+	// RecommendationsClamped tracks container recommendations clamped to a resource bound of their policy
+	RecommendationsClamped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "optipod_recommendations_clamped_total",
+			Help: "Total number of container recommendations clamped to a resource bound (CPUMin, CPUMax, MemoryMin or MemoryMax)",
+		},
+		[]string{"policy", "namespace", "workload", "kind", "bound"},
+	)
+
 	// ApplicationsTotal tracks the total number of applications (updates) performed
 	ApplicationsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -104,6 +135,67 @@ var (
 		},
 		[]string{"policy", "namespace", "workload", "kind", "status", "patch_type"},
 	)
+
+	// LeaderStatus reports whether this replica holds the leader lease and may write to the cluster
+	LeaderStatus = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "optipod_leader",
+			Help: "Whether this replica holds the leader lease and may write to the cluster (1) or not (0)",
+		},
+	)
+
+	// AutoReady reports whether the startup readiness gate lets Auto mode apply changes
+	AutoReady = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "optipod_auto_ready",
+			Help: "Whether the metrics backend is healthy and has returned metrics since startup, so Auto mode applies changes (1) or only recommends (0)",
+		},
+	)
+
+	// WorkloadStabilityScore reports the stability score of each processed workload
+	WorkloadStabilityScore = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "optipod_workload_stability_score",
+			Help: "Stability score of the workload's usage, from 0 (volatile) to 100 (steady)",
+		},
+		[]string{"policy", "namespace", "workload", "kind"},
+	)
+
+	// RecommendationUtilization reports how much of each applied request the usage since the change uses
+	RecommendationUtilization = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "optipod_recommendation_utilization_ratio",
+			Help: "Usage at the recommendation's percentile over the container's request, measured once a full rolling window has passed since the last applied change (above 1 = under-provisioned)",
+		},
+		[]string{"policy", "namespace", "workload", "kind", "container", "resource"},
+	)
+
+	// IncreaseBudgetRemaining reports how much of the cluster-wide increase budget is left in the current interval
+	IncreaseBudgetRemaining = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "optipod_increase_budget_remaining",
+			Help: "Requests that may still be added across the cluster in the current budget interval (CPU in cores, memory in bytes)",
+		},
+		[]string{"resource"},
+	)
+
+	// RecommendationCacheLookups tracks lookups of cached recommendations by result (hit or miss)
+	RecommendationCacheLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "optipod_recommendation_cache_lookups_total",
+			Help: "Total number of recommendation cache lookups, by whether a cached recommendation was reused (hit) or recomputed (miss)",
+		},
+		[]string{"result"},
+	)
+
+	// AuditRecordsDropped tracks audit records that never reached the audit sink
+	AuditRecordsDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "optipod_audit_records_dropped_total",
+			Help: "Total number of audit records dropped because the buffer was full or the sink failed",
+		},
+		[]string{"reason"},
+	)
 )
 
 func init() {
@@ -118,12 +210,36 @@ func RegisterMetrics() {
 	_ = metrics.Registry.Register(WorkloadsMonitored)
 	_ = metrics.Registry.Register(WorkloadsUpdated)
 	_ = metrics.Registry.Register(WorkloadsSkipped)
+	_ = metrics.Registry.Register(WorkloadsQuarantined)
 	_ = metrics.Registry.Register(ReconciliationDuration)
+	_ = metrics.Registry.Register(ReconcilePhaseDuration)
 	_ = metrics.Registry.Register(MetricsCollectionDuration)
 	_ = metrics.Registry.Register(ReconciliationErrors)
 	_ = metrics.Registry.Register(RecommendationsTotal)
+	_ = metrics.Registry.Register(RecommendationsClamped)
 	_ = metrics.Registry.Register(ApplicationsTotal)
 	_ = metrics.Registry.Register(SSAPatchTotal)
+	_ = metrics.Registry.Register(LeaderStatus)
+	_ = metrics.Registry.Register(AutoReady)
+	_ = metrics.Registry.Register(AuditRecordsDropped)
+	_ = metrics.Registry.Register(WorkloadStabilityScore)
+	_ = metrics.Registry.Register(RecommendationUtilization)
+	_ = metrics.Registry.Register(IncreaseBudgetRemaining)
+	_ = metrics.Registry.Register(RecommendationCacheLookups)
+}
+
+// Reconciliation phases observed by ReconcilePhaseDuration
+const (
+	PhaseDiscovery      = "discovery"
+	PhaseMetrics        = "metrics"
+	PhaseRecommendation = "recommendation"
+	PhaseApply          = "apply"
+)
+
+// ObservePhase records the time since start as the duration of a reconciliation phase. It is
+// meant to be deferred with time.Now() as start.
+func ObservePhase(policy, phase string, start time.Time) {
+	ReconcilePhaseDuration.WithLabelValues(policy, phase).Observe(time.Since(start).Seconds())
 }
 
 // RecordSSAPatch records an SSA patch operation
@@ -30,6 +30,7 @@ import (
 
 	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
 	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/observability"
 )
 
 // workloadTypeMatches checks if a workload type matches the policy's workload type filter
@@ -46,6 +47,13 @@ func workloadTypeMatches(filter *optipodv1alpha1.WorkloadTypeFilter, workloadKin
 // PolicySelector handles selection of the best policy for a workload when multiple policies match
 type PolicySelector struct {
 	client client.Client
+
+	// excludedNamespaces are only matched by policies that allow them explicitly
+	excludedNamespaces []string
+
+	// eventRecorder reports optipod.io/policy annotations naming a policy that cannot handle the
+	// workload (optional)
+	eventRecorder *observability.EventRecorder
 }
 
 // NewPolicySelector creates a new policy selector
@@ -55,6 +63,18 @@ func NewPolicySelector(c client.Client) *PolicySelector {
 	}
 }
 
+// SetExcludedNamespaces sets the namespaces a policy only matches when it names them in its
+// namespace allow list
+func (ps *PolicySelector) SetExcludedNamespaces(namespaces []string) {
+	ps.excludedNamespaces = namespaces
+}
+
+// SetEventRecorder sets the recorder used to report workloads pinned to a policy that cannot
+// handle them
+func (ps *PolicySelector) SetEventRecorder(recorder *observability.EventRecorder) {
+	ps.eventRecorder = recorder
+}
+
 // PolicyMatch represents a policy that matches a workload along with its weight
 type PolicyMatch struct {
 	Policy *optipodv1alpha1.OptimizationPolicy
@@ -62,7 +82,9 @@ type PolicyMatch struct {
 }
 
 // SelectBestPolicy finds the best policy for a workload based on weights
-// Returns the policy with the highest weight, or an error if no policies match
+// Returns the policy with the highest weight, or an error if no policies match.
+// A workload pinned to a policy by its optipod.io/policy annotation gets that policy, as long as
+// it is enabled and matches the workload.
 func (ps *PolicySelector) SelectBestPolicy(ctx context.Context, workload *discovery.Workload) (*optipodv1alpha1.OptimizationPolicy, error) {
 	log := logf.FromContext(ctx)
 
@@ -72,6 +94,14 @@ func (ps *PolicySelector) SelectBestPolicy(ctx context.Context, workload *discov
 		return nil, fmt.Errorf("failed to list optimization policies: %w", err)
 	}
 
+	// A pinned workload overrides weights, not selectors
+	if pinned := ps.pinnedPolicy(ctx, policyList.Items, workload); pinned != nil {
+		log.V(1).Info("Workload pinned to policy",
+			"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+			"policy", fmt.Sprintf("%s/%s", pinned.Namespace, pinned.Name))
+		return pinned, nil
+	}
+
 	// Find matching policies
 	var matches []PolicyMatch
 	for _, policy := range policyList.Items {
@@ -130,8 +160,82 @@ func (ps *PolicySelector) SelectBestPolicy(ctx context.Context, workload *discov
 	return selectedPolicy, nil
 }
 
+// pinnedPolicy returns the policy the workload is pinned to, or nil when it is not pinned or the
+// pinned policy cannot handle it, in which case a warning is reported and selectors apply
+func (ps *PolicySelector) pinnedPolicy(
+	ctx context.Context,
+	policies []optipodv1alpha1.OptimizationPolicy,
+	workload *discovery.Workload,
+) *optipodv1alpha1.OptimizationPolicy {
+	if workload.Object == nil {
+		return nil
+	}
+	namespace, name, pinned := discovery.PinnedPolicy(workload.Object)
+	if !pinned {
+		return nil
+	}
+
+	var candidates []*optipodv1alpha1.OptimizationPolicy
+	for i := range policies {
+		if discovery.PinnedTo(workload.Object, &policies[i]) {
+			candidates = append(candidates, &policies[i])
+		}
+	}
+
+	var reason string
+	switch {
+	case len(candidates) == 0:
+		reason = "the policy does not exist"
+	case len(candidates) > 1:
+		reason = fmt.Sprintf("%d policies are named %s", len(candidates), name)
+	case candidates[0].Spec.Mode == optipodv1alpha1.ModeDisabled:
+		reason = "the policy is disabled"
+	case !ps.policyMatchesWorkload(ctx, candidates[0], workload):
+		reason = "the policy's selectors or workload types do not match the workload"
+	default:
+		return candidates[0]
+	}
+
+	ref := name
+	if namespace != "" {
+		ref = namespace + "/" + name
+	}
+	logf.FromContext(ctx).Info("Ignoring invalid pinned policy, matching policies by selector",
+		"workload", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name),
+		"pinnedPolicy", ref,
+		"reason", reason)
+	if ps.eventRecorder != nil {
+		ps.eventRecorder.RecordInvalidPinnedPolicy(workload.Object, workload.Name, workload.Namespace, ref, reason)
+	}
+	return nil
+}
+
 // policyMatchesWorkload checks if a policy's selectors match a workload
 func (ps *PolicySelector) policyMatchesWorkload(ctx context.Context, policy *optipodv1alpha1.OptimizationPolicy, workload *discovery.Workload) bool {
+	if !ps.policyScopeIncludes(ctx, policy, workload) {
+		return false
+	}
+
+	// Check workload selector
+	if policy.Spec.Selector.WorkloadSelector != nil {
+		workloadLabels := ps.getWorkloadLabels(workload)
+
+		selector, err := metav1.LabelSelectorAsSelector(policy.Spec.Selector.WorkloadSelector)
+		if err != nil {
+			return false
+		}
+
+		if !selector.Matches(labels.Set(workloadLabels)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// policyScopeIncludes checks if a workload's kind and namespace are in the scope of a policy,
+// i.e. everything policyMatchesWorkload checks except the workload label selector
+func (ps *PolicySelector) policyScopeIncludes(ctx context.Context, policy *optipodv1alpha1.OptimizationPolicy, workload *discovery.Workload) bool {
 	// Check workload type filter first (new)
 	if !workloadTypeMatches(policy.Spec.Selector.WorkloadTypes, workload.Kind) {
 		return false
@@ -139,6 +243,9 @@ func (ps *PolicySelector) policyMatchesWorkload(ctx context.Context, policy *opt
 
 	// Use the same logic as discovery.DiscoverWorkloads but for a single workload
 	// This ensures consistency with the existing workload discovery logic
+	if discovery.NamespaceExcluded(workload.Namespace, ps.excludedNamespaces, policy) {
+		return false
+	}
 
 	// Check namespace selector
 	if policy.Spec.Selector.NamespaceSelector != nil {
@@ -183,20 +290,6 @@ func (ps *PolicySelector) policyMatchesWorkload(ctx context.Context, policy *opt
 		}
 	}
 
-	// Check workload selector
-	if policy.Spec.Selector.WorkloadSelector != nil {
-		workloadLabels := ps.getWorkloadLabels(workload)
-
-		selector, err := metav1.LabelSelectorAsSelector(policy.Spec.Selector.WorkloadSelector)
-		if err != nil {
-			return false
-		}
-
-		if !selector.Matches(labels.Set(workloadLabels)) {
-			return false
-		}
-	}
-
 	return true
 }
 
@@ -18,6 +18,7 @@ package policy
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/leanovate/gopter"
@@ -27,10 +28,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
 	"github.com/optipod/optipod/internal/discovery"
+	"github.com/optipod/optipod/internal/observability"
 )
 
 const (
@@ -547,3 +550,122 @@ func TestProperty14_WeightBasedSelectionWithTypeFiltering(t *testing.T) {
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
+
+func TestPolicyMatcherExcludedNamespaces(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = optipodv1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}}).
+		Build()
+	ps := NewPolicySelector(fakeClient)
+	ps.SetExcludedNamespaces([]string{"kube-system"})
+
+	workload := &discovery.Workload{Kind: "Deployment", Namespace: "kube-system", Name: "coredns"}
+
+	// A policy without an allow list does not match an excluded namespace
+	policy := &optipodv1alpha1.OptimizationPolicy{}
+	if ps.policyMatchesWorkload(context.Background(), policy, workload) {
+		t.Error("a policy without an allow list matched a workload in an excluded namespace")
+	}
+
+	// Naming the namespace in the allow list opts into it
+	policy.Spec.Selector.Namespaces = &optipodv1alpha1.NamespaceFilter{Allow: []string{"kube-system"}}
+	if !ps.policyMatchesWorkload(context.Background(), policy, workload) {
+		t.Error("a policy allowing an excluded namespace did not match its workload")
+	}
+}
+
+func TestSelectBestPolicy_PinnedPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = optipodv1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+
+	newPolicy := func(namespace, name string, weight int32, app string) *optipodv1alpha1.OptimizationPolicy {
+		return &optipodv1alpha1.OptimizationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: optipodv1alpha1.OptimizationPolicySpec{
+				Mode:   optipodv1alpha1.ModeAuto,
+				Weight: &weight,
+				Selector: optipodv1alpha1.WorkloadSelector{
+					WorkloadSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+				},
+			},
+		}
+	}
+	restricted := newPolicy("optipod", "restricted", 1, "web")
+	restricted.Spec.Selector.Namespaces = &optipodv1alpha1.NamespaceFilter{Allow: []string{"team-b"}}
+	disabled := newPolicy("optipod", "disabled", 1, "web")
+	disabled.Spec.Mode = optipodv1alpha1.ModeDisabled
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+			newPolicy("optipod", "production", 100, "web"),
+			newPolicy("optipod", "canary", 1, "web"),
+			newPolicy("optipod", "shared", 1, "web"),
+			newPolicy("team-a", "shared", 1, "web"),
+			newPolicy("optipod", "batch", 1, "batch"),
+			restricted,
+			disabled,
+		).
+		Build()
+
+	tests := []struct {
+		name      string
+		pin       string
+		want      string
+		wantEvent bool
+	}{
+		{name: "not pinned", want: "optipod/production"},
+		{name: "pinned by name", pin: "canary", want: "optipod/canary"},
+		{name: "pinned by namespace and name", pin: "team-a/shared", want: "team-a/shared"},
+		{name: "missing policy", pin: "missing", want: "optipod/production", wantEvent: true},
+		{name: "policy in another namespace", pin: "team-a/canary", want: "optipod/production", wantEvent: true},
+		{name: "ambiguous name", pin: "shared", want: "optipod/production", wantEvent: true},
+		{name: "disabled policy", pin: "disabled", want: "optipod/production", wantEvent: true},
+		{name: "namespace out of scope", pin: "restricted", want: "optipod/production", wantEvent: true},
+		{name: "selector does not match", pin: "batch", want: "optipod/production", wantEvent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			ps := NewPolicySelector(fakeClient)
+			ps.SetEventRecorder(observability.NewEventRecorder(recorder))
+
+			deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: "team-a",
+				Labels:    map[string]string{"app": "web"},
+			}}
+			if tt.pin != "" {
+				deployment.Annotations = map[string]string{optipodv1alpha1.AnnotationPolicy: tt.pin}
+			}
+			workload := &discovery.Workload{Kind: "Deployment", Namespace: "team-a", Name: "web", Object: deployment}
+
+			selected, err := ps.SelectBestPolicy(context.Background(), workload)
+			if err != nil {
+				t.Fatalf("SelectBestPolicy() error = %v", err)
+			}
+			if got := selected.Namespace + "/" + selected.Name; got != tt.want {
+				t.Errorf("SelectBestPolicy() = %s, want %s", got, tt.want)
+			}
+
+			select {
+			case event := <-recorder.Events:
+				if !tt.wantEvent || !strings.Contains(event, observability.EventReasonInvalidPinnedPolicy) {
+					t.Errorf("unexpected event %q", event)
+				}
+			default:
+				if tt.wantEvent {
+					t.Error("no InvalidPinnedPolicy event was recorded")
+				}
+			}
+		})
+	}
+}
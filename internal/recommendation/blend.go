@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+// defaultShortWeight is the short window weight for the Weighted blend rule
+const defaultShortWeight = 0.5
+
+// WindowedMetrics holds the metrics of the rolling window and, when the policy
+// configures window blending, of the short window
+type WindowedMetrics struct {
+	// Long contains the metrics over metricsConfig.rollingWindow
+	Long *metrics.ContainerMetrics
+
+	// Short contains the metrics over metricsConfig.blend.shortWindow (nil = no blending)
+	Short *metrics.ContainerMetrics
+
+	// DailyPeaks contains the usage percentiles of each recent day when the policy sizes to daily
+	// peaks and the provider keeps daily samples (nil = rolling window only)
+	DailyPeaks *metrics.DailyPeaks
+
+	// CPUOverride and MemoryOverride contain the metrics over the reactive override window of
+	// each resource (nil = no override)
+	CPUOverride    *metrics.ContainerMetrics
+	MemoryOverride *metrics.ContainerMetrics
+}
+
+// CollectMetrics fetches the metrics a policy needs from the provider: the rolling window,
+// the short window as a second query when window blending is configured, the reactive override
+// window of each resource that configures one, and the recent daily peaks when the policy sizes
+// to them and the provider keeps daily samples
+func (e *Engine) CollectMetrics(
+	ctx context.Context,
+	provider metrics.MetricsProvider,
+	namespace, podName, containerName string,
+	policy *optipodv1alpha1.OptimizationPolicy,
+) (*WindowedMetrics, error) {
+	metricsConfig := policy.Spec.MetricsConfig
+
+	long, err := provider.GetContainerMetrics(ctx, namespace, podName, containerName, metricsConfig.GetRollingWindow())
+	if err != nil {
+		return nil, err
+	}
+	windowed := &WindowedMetrics{Long: long}
+
+	if metricsConfig.Blend != nil {
+		short, err := provider.GetContainerMetrics(ctx, namespace, podName, containerName, metricsConfig.Blend.ShortWindow.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect short window metrics: %w", err)
+		}
+		windowed.Short = short
+	}
+
+	// Resources with the same override window share a single query
+	if statistics := metricsConfig.ResourceStatistics; statistics != nil {
+		queried := make(map[time.Duration]*metrics.ContainerMetrics)
+		overrideMetrics := func(config *optipodv1alpha1.ResourceStatistic) (*metrics.ContainerMetrics, error) {
+			if config == nil || config.Override == nil {
+				return nil, nil
+			}
+			window := config.Override.Window.Duration
+			if cached, ok := queried[window]; ok {
+				return cached, nil
+			}
+			override, err := provider.GetContainerMetrics(ctx, namespace, podName, containerName, window)
+			if err != nil {
+				return nil, fmt.Errorf("failed to collect override window metrics: %w", err)
+			}
+			queried[window] = override
+			return override, nil
+		}
+		if windowed.CPUOverride, err = overrideMetrics(statistics.CPU); err != nil {
+			return nil, err
+		}
+		if windowed.MemoryOverride, err = overrideMetrics(statistics.Memory); err != nil {
+			return nil, err
+		}
+	}
+
+	// Providers without daily samples fall back to the rolling window when computing
+	if querier, ok := provider.(metrics.DailyPeakQuerier); ok && metricsConfig.DailyPeaks != nil {
+		daily, err := querier.GetContainerDailyPeaks(ctx, namespace, podName, containerName, metricsConfig.DailyPeaks.GetDays())
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect daily peaks: %w", err)
+		}
+		windowed.DailyPeaks = daily
+	}
+
+	return windowed, nil
+}
+
+// blendWindows combines the rolling window percentiles with the short window according to the
+// blend rule and returns the blended CPU and memory values plus an explanation note.
+// Without a short window (or without short window samples) the rolling window values are returned unchanged.
+func blendWindows(
+	cpuLong, memoryLong resource.Quantity,
+	short *metrics.ContainerMetrics,
+	blend *optipodv1alpha1.BlendConfig,
+) (resource.Quantity, resource.Quantity, string) {
+	if blend == nil || short == nil {
+		return cpuLong, memoryLong, ""
+	}
+
+	if short.CPU.Samples == 0 && short.Memory.Samples == 0 {
+		return cpuLong, memoryLong, fmt.Sprintf("; short window %s has no samples, using the rolling window only",
+			blend.ShortWindow.Duration)
+	}
+
+	shortPercentile := blend.ShortPercentile
+	if shortPercentile == "" {
+		shortPercentile = "P99"
+	}
+	cpuShort := selectPercentile(short.CPU, shortPercentile)
+	memoryShort := selectPercentile(short.Memory, shortPercentile)
+
+	var cpu, memory resource.Quantity
+	var rule string
+	switch blend.Rule {
+	case optipodv1alpha1.BlendRuleWeighted:
+		weight := defaultShortWeight
+		if blend.ShortWeight != nil {
+			weight = *blend.ShortWeight
+		}
+		cpu = weightedQuantity(cpuShort, cpuLong, weight)
+		memory = weightedQuantity(memoryShort, memoryLong, weight)
+		rule = fmt.Sprintf("Weighted rule (short window weight %.2f)", weight)
+	default:
+		cpu = maxQuantity(cpuShort, cpuLong)
+		memory = maxQuantity(memoryShort, memoryLong)
+		rule = "Max rule"
+	}
+
+	note := fmt.Sprintf("; blended with %s percentile of the %s window (CPU: %s, Memory: %s) using the %s to CPU: %s, Memory: %s",
+		shortPercentile, blend.ShortWindow.Duration, cpuShort.String(), memoryShort.String(), rule, cpu.String(), memory.String())
+
+	return cpu, memory, note
+}
+
+// weightedQuantity returns weight*short + (1-weight)*long in the format of the long quantity
+func weightedQuantity(short, long resource.Quantity, weight float64) resource.Quantity {
+	result := multiplyQuantity(long, 1-weight)
+	shortPart := multiplyQuantity(short, weight)
+	result.Add(shortPart)
+	return result
+}
+
+// maxQuantity returns the larger of two quantities
+func maxQuantity(a, b resource.Quantity) resource.Quantity {
+	if a.Cmp(b) >= 0 {
+		return a.DeepCopy()
+	}
+	return b.DeepCopy()
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+// windowMetricsProvider returns different metrics per window and records the queried windows
+type windowMetricsProvider struct {
+	byWindow map[time.Duration]*metrics.ContainerMetrics
+	windows  []time.Duration
+}
+
+func (p *windowMetricsProvider) GetContainerMetrics(ctx context.Context, namespace, podName, containerName string, window time.Duration) (*metrics.ContainerMetrics, error) {
+	p.windows = append(p.windows, window)
+	return p.byWindow[window], nil
+}
+
+func (p *windowMetricsProvider) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+func newBlendTestMetrics(cpuP90, cpuP99, memoryP90, memoryP99 string, samples int) *metrics.ContainerMetrics {
+	return &metrics.ContainerMetrics{
+		CPU: metrics.ResourceMetrics{
+			P50:     resource.MustParse("10m"),
+			P90:     resource.MustParse(cpuP90),
+			P99:     resource.MustParse(cpuP99),
+			Samples: samples,
+		},
+		Memory: metrics.ResourceMetrics{
+			P50:     resource.MustParse("16Mi"),
+			P90:     resource.MustParse(memoryP90),
+			P99:     resource.MustParse(memoryP99),
+			Samples: samples,
+		},
+	}
+}
+
+func newBlendTestPolicy(blend *optipodv1alpha1.BlendConfig) *optipodv1alpha1.OptimizationPolicy {
+	policy := createTestPolicy()
+	policy.Spec.MetricsConfig.RollingWindow = metav1.Duration{Duration: 24 * time.Hour}
+	policy.Spec.MetricsConfig.SafetyFactor = ptr.To(1.0)
+	policy.Spec.MetricsConfig.Blend = blend
+	policy.Spec.ResourceBounds = optipodv1alpha1.ResourceBounds{
+		CPU:    optipodv1alpha1.ResourceBound{Min: resource.MustParse("1m"), Max: resource.MustParse("8")},
+		Memory: optipodv1alpha1.ResourceBound{Min: resource.MustParse("1Mi"), Max: resource.MustParse("16Gi")},
+	}
+	return policy
+}
+
+func TestCollectMetrics(t *testing.T) {
+	long := newBlendTestMetrics("100m", "150m", "128Mi", "192Mi", 100)
+	short := newBlendTestMetrics("300m", "400m", "256Mi", "320Mi", 10)
+
+	tests := []struct {
+		name        string
+		blend       *optipodv1alpha1.BlendConfig
+		wantWindows []time.Duration
+		wantShort   bool
+	}{
+		{
+			name:        "single window without blending",
+			blend:       nil,
+			wantWindows: []time.Duration{24 * time.Hour},
+		},
+		{
+			name:        "rolling and short window with blending",
+			blend:       &optipodv1alpha1.BlendConfig{ShortWindow: metav1.Duration{Duration: time.Hour}},
+			wantWindows: []time.Duration{24 * time.Hour, time.Hour},
+			wantShort:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &windowMetricsProvider{byWindow: map[time.Duration]*metrics.ContainerMetrics{
+				24 * time.Hour: long,
+				time.Hour:      short,
+			}}
+
+			windowed, err := NewEngine().CollectMetrics(context.Background(), provider, "default", "pod", "app", newBlendTestPolicy(tt.blend))
+			if err != nil {
+				t.Fatalf("CollectMetrics() error = %v", err)
+			}
+			if !reflect.DeepEqual(provider.windows, tt.wantWindows) {
+				t.Errorf("queried windows = %v, want %v", provider.windows, tt.wantWindows)
+			}
+			if windowed.Long != long {
+				t.Error("expected rolling window metrics in Long")
+			}
+			if (windowed.Short != nil) != tt.wantShort {
+				t.Errorf("Short set = %v, want %v", windowed.Short != nil, tt.wantShort)
+			}
+		})
+	}
+}
+
+func TestComputeBlendedRecommendation(t *testing.T) {
+	long := newBlendTestMetrics("100m", "150m", "128Mi", "192Mi", 100)
+	spiky := newBlendTestMetrics("300m", "400m", "100Mi", "512Mi", 10)
+	weight := 0.25
+
+	tests := []struct {
+		name         string
+		blend        *optipodv1alpha1.BlendConfig
+		short        *metrics.ContainerMetrics
+		wantCPU      string
+		wantMemory   string
+		wantInReason string
+	}{
+		{
+			name:       "no blending uses the rolling window",
+			blend:      nil,
+			short:      nil,
+			wantCPU:    "100m",
+			wantMemory: "128Mi",
+		},
+		{
+			name:         "max of short P99 and long P90",
+			blend:        &optipodv1alpha1.BlendConfig{ShortWindow: metav1.Duration{Duration: time.Hour}},
+			short:        spiky,
+			wantCPU:      "400m",
+			wantMemory:   "512Mi",
+			wantInReason: "using the Max rule",
+		},
+		{
+			name: "max keeps the long window when it is higher",
+			blend: &optipodv1alpha1.BlendConfig{
+				ShortWindow:     metav1.Duration{Duration: time.Hour},
+				ShortPercentile: "P90",
+			},
+			short:      spiky,
+			wantCPU:    "300m",
+			wantMemory: "128Mi",
+		},
+		{
+			name: "weighted average",
+			blend: &optipodv1alpha1.BlendConfig{
+				ShortWindow: metav1.Duration{Duration: time.Hour},
+				Rule:        optipodv1alpha1.BlendRuleWeighted,
+				ShortWeight: &weight,
+			},
+			short:        spiky,
+			wantCPU:      "175m",  // 0.25*400m + 0.75*100m
+			wantMemory:   "224Mi", // 0.25*512Mi + 0.75*128Mi
+			wantInReason: "short window weight 0.25",
+		},
+		{
+			name:         "short window without samples falls back to the rolling window",
+			blend:        &optipodv1alpha1.BlendConfig{ShortWindow: metav1.Duration{Duration: time.Hour}},
+			short:        newBlendTestMetrics("900m", "900m", "1Gi", "1Gi", 0),
+			wantCPU:      "100m",
+			wantMemory:   "128Mi",
+			wantInReason: "has no samples",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windowed := &WindowedMetrics{Long: long, Short: tt.short}
+			rec, err := NewEngine().ComputeBlendedRecommendation(windowed, newBlendTestPolicy(tt.blend), WorkloadContext{})
+			if err != nil {
+				t.Fatalf("ComputeBlendedRecommendation() error = %v", err)
+			}
+
+			if want := resource.MustParse(tt.wantCPU); rec.CPU.Cmp(want) != 0 {
+				t.Errorf("CPU = %s, want %s", rec.CPU.String(), tt.wantCPU)
+			}
+			if want := resource.MustParse(tt.wantMemory); rec.Memory.Cmp(want) != 0 {
+				t.Errorf("Memory = %s, want %s", rec.Memory.String(), tt.wantMemory)
+			}
+			if tt.wantInReason != "" && !strings.Contains(rec.Explanation, tt.wantInReason) {
+				t.Errorf("explanation %q does not contain %q", rec.Explanation, tt.wantInReason)
+			}
+		})
+	}
+}
+
+func TestComputeBlendedRecommendation_ObservedMemoryP99(t *testing.T) {
+	long := newBlendTestMetrics("100m", "150m", "128Mi", "192Mi", 100)
+	short := newBlendTestMetrics("100m", "150m", "128Mi", "1Gi", 10)
+	policy := newBlendTestPolicy(&optipodv1alpha1.BlendConfig{ShortWindow: metav1.Duration{Duration: time.Hour}})
+
+	rec, err := NewEngine().ComputeBlendedRecommendation(&WindowedMetrics{Long: long, Short: short}, policy, WorkloadContext{})
+	if err != nil {
+		t.Fatalf("ComputeBlendedRecommendation() error = %v", err)
+	}
+
+	// A memory spike seen only in the short window must still protect the memory limit
+	if want := resource.MustParse("1Gi"); rec.ObservedMemoryP99.Cmp(want) != 0 {
+		t.Errorf("ObservedMemoryP99 = %s, want 1Gi", rec.ObservedMemoryP99.String())
+	}
+}
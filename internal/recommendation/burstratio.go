@@ -0,0 +1,43 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+// burstRatioLimits returns the policy's burst ratio limit configuration, or nil when limits are
+// not derived from the burst ratio or not updated at all
+func burstRatioLimits(policy *optipodv1alpha1.OptimizationPolicy) *optipodv1alpha1.BurstRatioLimits {
+	strategy := policy.Spec.UpdateStrategy
+	if strategy.LimitConfig == nil || strategy.UpdateRequestsOnly || strategy.RemoveLimits {
+		return nil
+	}
+	return strategy.LimitConfig.BurstRatioLimits
+}
+
+// burstRatio returns the observed P99 / P90 burst ratio of a resource, clamped to the configured
+// band. Without P90 usage the minimum ratio is used.
+func burstRatio(usage metrics.ResourceMetrics, limits *optipodv1alpha1.BurstRatioLimits) float64 {
+	minRatio, maxRatio := limits.Band()
+	ratio := minRatio
+	if p90 := usage.P90.AsApproximateFloat64(); p90 > 0 {
+		ratio = usage.P99.AsApproximateFloat64() / p90
+	}
+	return min(max(ratio, minRatio), maxRatio)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+// newBurstRatioTestPolicy returns a policy deriving limits from the burst ratio within the band
+func newBurstRatioTestPolicy(minRatio, maxRatio float64) *optipodv1alpha1.OptimizationPolicy {
+	policy := createTestPolicy()
+	policy.Spec.MetricsConfig.SafetyFactor = ptr.To(1.0)
+	policy.Spec.ResourceBounds = optipodv1alpha1.ResourceBounds{
+		CPU:    optipodv1alpha1.ResourceBound{Min: resource.MustParse("1m"), Max: resource.MustParse("100")},
+		Memory: optipodv1alpha1.ResourceBound{Min: *resource.NewQuantity(1, resource.BinarySI), Max: resource.MustParse("1Ti")},
+	}
+	policy.Spec.UpdateStrategy = optipodv1alpha1.UpdateStrategy{
+		LimitConfig: &optipodv1alpha1.LimitConfig{
+			BurstRatioLimits: &optipodv1alpha1.BurstRatioLimits{MinRatio: &minRatio, MaxRatio: &maxRatio},
+		},
+	}
+	return policy
+}
+
+// newBurstRatioTestMetrics returns metrics whose P99 is ratio times P90 for both resources
+func newBurstRatioTestMetrics(cpuP90Milli, memP90 int64, ratio float64) *metrics.ContainerMetrics {
+	return &metrics.ContainerMetrics{
+		CPU: metrics.ResourceMetrics{
+			P50:     *resource.NewMilliQuantity(cpuP90Milli/2, resource.DecimalSI),
+			P90:     *resource.NewMilliQuantity(cpuP90Milli, resource.DecimalSI),
+			P99:     *resource.NewMilliQuantity(int64(float64(cpuP90Milli)*ratio), resource.DecimalSI),
+			Samples: 100,
+		},
+		Memory: metrics.ResourceMetrics{
+			P50:     *resource.NewQuantity(memP90/2, resource.BinarySI),
+			P90:     *resource.NewQuantity(memP90, resource.BinarySI),
+			P99:     *resource.NewQuantity(int64(float64(memP90)*ratio), resource.BinarySI),
+			Samples: 100,
+		},
+	}
+}
+
+func TestBurstRatio(t *testing.T) {
+	limits := &optipodv1alpha1.BurstRatioLimits{}
+	tests := []struct {
+		name string
+		p90  string
+		p99  string
+		want float64
+	}{
+		{name: "within the band", p90: "100m", p99: "200m", want: 2.0},
+		{name: "steady usage is raised to the minimum", p90: "100m", p99: "100m", want: optipodv1alpha1.DefaultMinBurstRatio},
+		{name: "spiky usage is capped at the maximum", p90: "100m", p99: "1", want: optipodv1alpha1.DefaultMaxBurstRatio},
+		{name: "no P90 usage uses the minimum", p90: "0", p99: "100m", want: optipodv1alpha1.DefaultMinBurstRatio},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := metrics.ResourceMetrics{P90: resource.MustParse(tt.p90), P99: resource.MustParse(tt.p99)}
+			if got := burstRatio(usage, limits); got != tt.want {
+				t.Errorf("burstRatio() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Property: Burst ratio limits stay within the band
+// For any observed usage, the limit ratios are within [minRatio, maxRatio] and do not decrease
+// as the observed P99 / P90 burst ratio grows
+func TestProperty_BurstRatioLimits(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("limit ratios are clamped to the band", prop.ForAll(
+		func(cpuP90, memP90 int64, ratio, minRatio, width float64) bool {
+			maxRatio := min(minRatio+width, 10.0)
+			rec, err := NewEngine().ComputeRecommendation(newBurstRatioTestMetrics(cpuP90, memP90, ratio), newBurstRatioTestPolicy(minRatio, maxRatio))
+			if err != nil {
+				return false
+			}
+			return rec.CPULimitRatio >= minRatio && rec.CPULimitRatio <= maxRatio &&
+				rec.MemoryLimitRatio >= minRatio && rec.MemoryLimitRatio <= maxRatio
+		},
+		gen.Int64Range(10, 10000),
+		gen.Int64Range(1024*1024, 1024*1024*1024),
+		gen.Float64Range(1.0, 12.0),
+		gen.Float64Range(1.0, 10.0),
+		gen.Float64Range(0, 5.0),
+	))
+
+	properties.Property("limit ratios are monotonic in the burst ratio", prop.ForAll(
+		func(memP90 int64, ratio, extra float64) bool {
+			policy := newBurstRatioTestPolicy(optipodv1alpha1.DefaultMinBurstRatio, optipodv1alpha1.DefaultMaxBurstRatio)
+			lower, err := NewEngine().ComputeRecommendation(newBurstRatioTestMetrics(1000, memP90, ratio), policy)
+			if err != nil {
+				return false
+			}
+			higher, err := NewEngine().ComputeRecommendation(newBurstRatioTestMetrics(1000, memP90, ratio+extra), policy)
+			if err != nil {
+				return false
+			}
+			return higher.CPULimitRatio >= lower.CPULimitRatio && higher.MemoryLimitRatio >= lower.MemoryLimitRatio
+		},
+		gen.Int64Range(1024*1024, 1024*1024*1024),
+		gen.Float64Range(1.0, 5.0),
+		gen.Float64Range(0, 5.0),
+	))
+
+	properties.Property("updateRequestsOnly computes no limit ratios", prop.ForAll(
+		func(ratio float64) bool {
+			policy := newBurstRatioTestPolicy(optipodv1alpha1.DefaultMinBurstRatio, optipodv1alpha1.DefaultMaxBurstRatio)
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = true
+			rec, err := NewEngine().ComputeRecommendation(newBurstRatioTestMetrics(1000, 1024*1024*1024, ratio), policy)
+			return err == nil && rec.CPULimitRatio == 0 && rec.MemoryLimitRatio == 0
+		},
+		gen.Float64Range(1.0, 5.0),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
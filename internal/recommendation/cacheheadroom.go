@@ -0,0 +1,39 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// addCacheHeadroom adds the configured page cache headroom to the memory recommendation, along
+// with a note for the explanation. The working set the recommendation is computed from excludes
+// reclaimable cache, so the headroom is added on top of the safety factor rather than folded
+// into it. A percentage is taken of the observed usage, before the safety factor.
+func addCacheHeadroom(memory, usage resource.Quantity, config *optipodv1alpha1.CacheHeadroom) (resource.Quantity, string) {
+	headroom := config.Headroom(usage)
+	if headroom.IsZero() {
+		return memory, ""
+	}
+	withHeadroom := memory.DeepCopy()
+	withHeadroom.Add(headroom)
+	return withHeadroom, fmt.Sprintf("; %s cache headroom added to memory", headroom.String())
+}
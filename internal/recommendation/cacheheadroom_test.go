@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+func TestComputeRecommendation_CacheHeadroom(t *testing.T) {
+	usage := &metrics.ContainerMetrics{
+		CPU:    metrics.ResourceMetrics{P50: resource.MustParse("100m"), P90: resource.MustParse("200m"), P99: resource.MustParse("300m"), Samples: 100},
+		Memory: metrics.ResourceMetrics{P50: resource.MustParse("128Mi"), P90: resource.MustParse("256Mi"), P99: resource.MustParse("300Mi"), Samples: 100},
+	}
+	percent := func(p int32) *int32 { return &p }
+	amount := func(s string) *resource.Quantity { q := resource.MustParse(s); return &q }
+
+	tests := []struct {
+		name        string
+		headroom    *optipodv1alpha1.CacheHeadroom
+		maxMemory   string
+		wantMemory  int64 // bytes
+		wantNote    string
+		wantClamped bool
+	}{
+		{
+			name:       "no headroom",
+			maxMemory:  "8Gi",
+			wantMemory: 322122547, // P90 256Mi * 1.2
+		},
+		{
+			name:       "percentage of the observed usage",
+			headroom:   &optipodv1alpha1.CacheHeadroom{Percent: percent(50)},
+			maxMemory:  "8Gi",
+			wantMemory: 322122547 + 134217728, // plus 50% of P90 256Mi
+			wantNote:   "128Mi cache headroom added to memory",
+		},
+		{
+			name:       "absolute amount",
+			headroom:   &optipodv1alpha1.CacheHeadroom{Amount: amount("512Mi")},
+			maxMemory:  "8Gi",
+			wantMemory: 322122547 + 536870912,
+			wantNote:   "512Mi cache headroom added to memory",
+		},
+		{
+			name:        "clamped to the memory bound",
+			headroom:    &optipodv1alpha1.CacheHeadroom{Amount: amount("512Mi")},
+			maxMemory:   "512Mi",
+			wantMemory:  536870912,
+			wantClamped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createTestPolicy()
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = false
+			policy.Spec.ResourceBounds.Memory.Max = resource.MustParse(tt.maxMemory)
+			policy.Spec.CacheHeadroom = tt.headroom
+
+			rec, err := NewEngine().ComputeRecommendation(usage, policy)
+			if err != nil {
+				t.Fatalf("ComputeRecommendation() error = %v", err)
+			}
+			if got := rec.Memory.Value(); got != tt.wantMemory {
+				t.Errorf("memory = %d, want %d", got, tt.wantMemory)
+			}
+			// Headroom is memory only
+			if got := rec.CPU.MilliValue(); got != 240 {
+				t.Errorf("CPU = %dm, want 240m", got)
+			}
+			if tt.wantNote != "" && !strings.Contains(rec.Explanation, tt.wantNote) {
+				t.Errorf("explanation %q does not mention %q", rec.Explanation, tt.wantNote)
+			}
+			if clamped := slices.Contains(rec.ClampedToBound, optipodv1alpha1.BoundMemoryMax); clamped != tt.wantClamped {
+				t.Errorf("ClampedToBound = %v, want memory max clamped %v", rec.ClampedToBound, tt.wantClamped)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+// minDailyPeakDays is the number of days with samples needed to size to daily peaks; with fewer
+// days a single spike would still drive the median
+const minDailyPeakDays = 3
+
+// dailyPeakBase returns the median of the daily peaks in place of the rolling window percentiles,
+// along with a description of the source for the explanation. Without daily peaks from the
+// provider or with fewer than minDailyPeakDays days of samples, the rolling window values are
+// returned unchanged with a note saying why.
+func dailyPeakBase(
+	cpuWindow, memoryWindow resource.Quantity,
+	daily *metrics.DailyPeaks,
+	config *optipodv1alpha1.DailyPeaksConfig,
+) (resource.Quantity, resource.Quantity, string, string) {
+	if config == nil {
+		return cpuWindow, memoryWindow, "", ""
+	}
+	if daily == nil {
+		return cpuWindow, memoryWindow, "", "; the metrics provider keeps no daily samples, using the rolling window only"
+	}
+
+	days := min(len(daily.CPU), len(daily.Memory))
+	if days < minDailyPeakDays {
+		return cpuWindow, memoryWindow, "", fmt.Sprintf(
+			"; only %d day(s) of samples for daily peaks, at least %d needed, using the rolling window only",
+			days, minDailyPeakDays)
+	}
+
+	percentile := config.GetPercentile()
+	source := fmt.Sprintf("median of %d daily %s peaks", days, percentile)
+	return medianPeak(daily.CPU, percentile), medianPeak(daily.Memory, percentile), source, ""
+}
+
+// medianPeak returns the median of the percentile of each day. With an even number of days the
+// higher of the two middle peaks is used, erring towards covering the peaks.
+func medianPeak(days []metrics.ResourceMetrics, percentile string) resource.Quantity {
+	peaks := make([]resource.Quantity, 0, len(days))
+	for _, day := range days {
+		peaks = append(peaks, selectPercentile(day, percentile))
+	}
+	slices.SortFunc(peaks, func(a, b resource.Quantity) int { return a.Cmp(b) })
+	return peaks[len(peaks)/2].DeepCopy()
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+// dailyPeakMetricsProvider serves the rolling window metrics and daily peaks, and records the
+// number of days queried
+type dailyPeakMetricsProvider struct {
+	windowMetricsProvider
+	daily      *metrics.DailyPeaks
+	queriedFor int
+}
+
+func (p *dailyPeakMetricsProvider) GetContainerDailyPeaks(ctx context.Context, namespace, podName, containerName string, days int) (*metrics.DailyPeaks, error) {
+	p.queriedFor = days
+	return p.daily, nil
+}
+
+// newDailyPeaks returns days whose P99 CPU is cpuPeaks[i] millicores and P99 memory
+// memoryPeaks[i] mebibytes, on a steady usage of 50m and 64Mi
+func newDailyPeaks(cpuPeaks, memoryPeaks []int64) *metrics.DailyPeaks {
+	daily := &metrics.DailyPeaks{}
+	for _, peak := range cpuPeaks {
+		daily.CPU = append(daily.CPU, metrics.ResourceMetrics{
+			P50:     resource.MustParse("50m"),
+			P90:     *resource.NewMilliQuantity(peak/2, resource.DecimalSI),
+			P99:     *resource.NewMilliQuantity(peak, resource.DecimalSI),
+			Samples: 96,
+		})
+	}
+	for _, peak := range memoryPeaks {
+		daily.Memory = append(daily.Memory, metrics.ResourceMetrics{
+			P50:     resource.MustParse("64Mi"),
+			P90:     *resource.NewQuantity(peak<<20/2, resource.BinarySI),
+			P99:     *resource.NewQuantity(peak<<20, resource.BinarySI),
+			Samples: 96,
+		})
+	}
+	return daily
+}
+
+func TestCollectMetrics_DailyPeaks(t *testing.T) {
+	long := newBlendTestMetrics("100m", "2", "128Mi", "2Gi", 100)
+	daily := newDailyPeaks([]int64{300, 320, 310}, []int64{256, 260, 250})
+
+	policy := newBlendTestPolicy(nil)
+	policy.Spec.MetricsConfig.DailyPeaks = &optipodv1alpha1.DailyPeaksConfig{Days: 5}
+	provider := &dailyPeakMetricsProvider{
+		windowMetricsProvider: windowMetricsProvider{byWindow: map[time.Duration]*metrics.ContainerMetrics{24 * time.Hour: long}},
+		daily:                 daily,
+	}
+
+	windowed, err := NewEngine().CollectMetrics(context.Background(), provider, "default", "pod", "app", policy)
+	if err != nil {
+		t.Fatalf("CollectMetrics() error = %v", err)
+	}
+	if windowed.DailyPeaks != daily || provider.queriedFor != 5 {
+		t.Errorf("daily peaks = %v queried for %d days, want the provider's for 5 days", windowed.DailyPeaks, provider.queriedFor)
+	}
+
+	// Without the option, daily peaks are never queried
+	provider.queriedFor = 0
+	windowed, err = NewEngine().CollectMetrics(context.Background(), provider, "default", "pod", "app", newBlendTestPolicy(nil))
+	if err != nil {
+		t.Fatalf("CollectMetrics() error = %v", err)
+	}
+	if windowed.DailyPeaks != nil || provider.queriedFor != 0 {
+		t.Errorf("daily peaks queried without dailyPeaks configured")
+	}
+}
+
+func TestComputeBlendedRecommendation_DailyPeaks(t *testing.T) {
+	// A week with one extreme spike day: the rolling window P90 is pulled up by it, while the
+	// median of the daily peaks reflects a typical day
+	long := newBlendTestMetrics("900m", "2", "1Gi", "2Gi", 2016)
+	week := newDailyPeaks(
+		[]int64{300, 320, 2000, 310, 330, 305, 315},
+		[]int64{256, 260, 2048, 250, 270, 255, 265},
+	)
+
+	tests := []struct {
+		name            string
+		dailyPeaks      *optipodv1alpha1.DailyPeaksConfig
+		daily           *metrics.DailyPeaks
+		wantCPU         string
+		wantMemory      string
+		wantExplanation string
+	}{
+		{
+			name:            "rolling window percentile without the option",
+			daily:           week,
+			wantCPU:         "900m",
+			wantMemory:      "1Gi",
+			wantExplanation: "Computed from P90 percentile",
+		},
+		{
+			name:            "median of the daily P99 peaks ignores the spike day",
+			dailyPeaks:      &optipodv1alpha1.DailyPeaksConfig{},
+			daily:           week,
+			wantCPU:         "315m",
+			wantMemory:      "260Mi",
+			wantExplanation: "Computed from median of 7 daily P99 peaks",
+		},
+		{
+			name:            "median of the daily P90 peaks",
+			dailyPeaks:      &optipodv1alpha1.DailyPeaksConfig{Percentile: percentileP90},
+			daily:           week,
+			wantCPU:         "157m",
+			wantMemory:      "130Mi",
+			wantExplanation: "Computed from median of 7 daily P90 peaks",
+		},
+		{
+			name:            "an even number of days takes the higher middle peak",
+			dailyPeaks:      &optipodv1alpha1.DailyPeaksConfig{},
+			daily:           newDailyPeaks([]int64{300, 2000, 310, 330}, []int64{256, 2048, 250, 270}),
+			wantCPU:         "330m",
+			wantMemory:      "270Mi",
+			wantExplanation: "Computed from median of 4 daily P99 peaks",
+		},
+		{
+			name:            "too few days fall back to the rolling window",
+			dailyPeaks:      &optipodv1alpha1.DailyPeaksConfig{},
+			daily:           newDailyPeaks([]int64{300, 2000}, []int64{256, 2048}),
+			wantCPU:         "900m",
+			wantMemory:      "1Gi",
+			wantExplanation: "only 2 day(s) of samples for daily peaks",
+		},
+		{
+			name:            "a provider without daily samples falls back to the rolling window",
+			dailyPeaks:      &optipodv1alpha1.DailyPeaksConfig{},
+			wantCPU:         "900m",
+			wantMemory:      "1Gi",
+			wantExplanation: "keeps no daily samples",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newBlendTestPolicy(nil)
+			policy.Spec.MetricsConfig.DailyPeaks = tt.dailyPeaks
+
+			rec, err := NewEngine().ComputeBlendedRecommendation(&WindowedMetrics{Long: long, DailyPeaks: tt.daily}, policy, WorkloadContext{})
+			if err != nil {
+				t.Fatalf("ComputeBlendedRecommendation() error = %v", err)
+			}
+			if rec.CPU.Cmp(resource.MustParse(tt.wantCPU)) != 0 || rec.Memory.Cmp(resource.MustParse(tt.wantMemory)) != 0 {
+				t.Errorf("recommendation = CPU %s, memory %s, want %s, %s", rec.CPU.String(), rec.Memory.String(), tt.wantCPU, tt.wantMemory)
+			}
+			if !strings.Contains(rec.Explanation, tt.wantExplanation) {
+				t.Errorf("explanation %q does not contain %q", rec.Explanation, tt.wantExplanation)
+			}
+
+			// OOM protection still uses the rolling window, spikes included
+			if rec.ObservedMemoryP99.Cmp(resource.MustParse("2Gi")) != 0 {
+				t.Errorf("ObservedMemoryP99 = %s, want the rolling window's 2Gi", rec.ObservedMemoryP99.String())
+			}
+		})
+	}
+}
@@ -18,6 +18,9 @@ package recommendation
 
 import (
 	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 
@@ -27,9 +30,93 @@ import (
 
 // Recommendation represents a computed resource recommendation for a container
 type Recommendation struct {
+	// CPU and Memory are the recommended requests. A resource the policy does not optimize
+	// has no recommendation and is left zero.
 	CPU         resource.Quantity
 	Memory      resource.Quantity
 	Explanation string
+
+	// ObservedMemoryP99 is the observed P99 memory usage, used as a floor for memory limits
+	ObservedMemoryP99 resource.Quantity
+
+	// MemoryLimit is the memory limit derived from LimitConfig.MemoryLimitPercentile.
+	// Zero means the limit is derived from the memory request and multiplier.
+	MemoryLimit resource.Quantity
+
+	// CPULimitRatio and MemoryLimitRatio are the limit to request ratios derived from the observed
+	// burst ratio, see LimitConfig.BurstRatioLimits. They replace the limit multipliers when set;
+	// zero means the multipliers are used.
+	CPULimitRatio    float64
+	MemoryLimitRatio float64
+
+	// KeepLimits leaves the container's current limits in place instead of deriving them from the
+	// requests, e.g. for a container without metrics whose requests are raised to a floor
+	KeepLimits bool
+
+	// Urgent is set when the recommendation relieves an active shortage, such as pods evicted for
+	// node memory pressure. Urgent recommendations are applied in full, without convergence steps.
+	Urgent bool
+
+	// ClampedToBound lists the resource bounds the recommendation was clamped to, e.g.
+	// optipodv1alpha1.BoundCPUMax
+	ClampedToBound []string
+
+	// DataQuality shows the samples, percentile values and safety factor the recommendation was
+	// computed from, and breaks each request down from the observed usage to its final value
+	DataQuality *optipodv1alpha1.RecommendationDataQuality
+}
+
+// RecordFinal records the recommended requests as the final values of the breakdown in
+// DataQuality. Adjustments to CPU or Memory after the recommendation was computed record them again.
+func (r *Recommendation) RecordFinal() {
+	if r.DataQuality == nil {
+		return
+	}
+	if r.DataQuality.CPU != nil {
+		cpu := r.CPU.DeepCopy()
+		r.DataQuality.CPU.Final = &cpu
+	}
+	if r.DataQuality.Memory != nil {
+		memory := r.Memory.DeepCopy()
+		r.DataQuality.Memory.Final = &memory
+	}
+}
+
+// WorkloadContext describes the runtime state of the workload a recommendation is computed for
+type WorkloadContext struct {
+	// Age is the time since the workload was created (nil = unknown)
+	Age *time.Duration
+
+	// Restarts is how often the container restarted across the workload's pods within the
+	// metrics rolling window
+	Restarts int32
+
+	// CurrentReplicas is the number of replicas the usage metrics were observed with (0 = unknown)
+	CurrentReplicas int32
+
+	// TargetReplicas is the number of replicas the workload is converging to (0 = unknown)
+	TargetReplicas int32
+
+	// CPULimit and MemoryLimit are the container's current limits (zero = no limit). Usage close
+	// to a limit is capped by it, so the limits are used to detect under-reported usage.
+	CPULimit    resource.Quantity
+	MemoryLimit resource.Quantity
+
+	// MemoryRequest is the container's current memory request (zero = no request)
+	MemoryRequest resource.Quantity
+
+	// MemoryEvictions is the number of the workload's pods evicted, or about to be evicted, by the
+	// kubelet because their node ran low on memory
+	MemoryEvictions int32
+
+	// PriorityClassName and Priority are the priority class and resolved priority of the
+	// workload's pods (nil = unknown), for the policy's priority memory headroom
+	PriorityClassName string
+	Priority          *int32
+
+	// MemoryPeak is the highest memory usage of the container retained across reconciles (zero =
+	// none), the floor of the memory recommendation when the policy sets a memory peak floor
+	MemoryPeak resource.Quantity
 }
 
 // Engine computes resource recommendations based on metrics and policy configuration
@@ -45,30 +132,142 @@ func (e *Engine) ComputeRecommendation(
 	containerMetrics *metrics.ContainerMetrics,
 	policy *optipodv1alpha1.OptimizationPolicy,
 ) (*Recommendation, error) {
-	if containerMetrics == nil {
+	return e.ComputeRecommendationForWorkload(containerMetrics, policy, WorkloadContext{})
+}
+
+// ComputeRecommendationForWorkload computes optimal resource requests like ComputeRecommendation,
+// additionally enforcing the policy startup floor when the workload context calls for it
+func (e *Engine) ComputeRecommendationForWorkload(
+	containerMetrics *metrics.ContainerMetrics,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	workload WorkloadContext,
+) (*Recommendation, error) {
+	return e.ComputeBlendedRecommendation(&WindowedMetrics{Long: containerMetrics}, policy, workload)
+}
+
+// ComputeBlendedRecommendation computes optimal resource requests like ComputeRecommendationForWorkload,
+// blending in the short window metrics when the policy configures window blending
+func (e *Engine) ComputeBlendedRecommendation(
+	windowed *WindowedMetrics,
+	policy *optipodv1alpha1.OptimizationPolicy,
+	workload WorkloadContext,
+) (*Recommendation, error) {
+	if windowed == nil || windowed.Long == nil {
 		return nil, fmt.Errorf("container metrics cannot be nil")
 	}
 	if policy == nil {
 		return nil, fmt.Errorf("policy cannot be nil")
 	}
+	containerMetrics := windowed.Long
 
-	// Select percentile based on policy configuration
-	cpuPercentile := selectPercentile(containerMetrics.CPU, policy.Spec.MetricsConfig.Percentile)
-	memoryPercentile := selectPercentile(containerMetrics.Memory, policy.Spec.MetricsConfig.Percentile)
+	// Select percentile based on policy configuration, or the statistic configured for the resource
+	var cpuStatistic, memoryStatistic *optipodv1alpha1.ResourceStatistic
+	if statistics := policy.Spec.MetricsConfig.ResourceStatistics; statistics != nil {
+		cpuStatistic, memoryStatistic = statistics.CPU, statistics.Memory
+	}
+	cpuPercentile := selectPercentile(containerMetrics.CPU, resourceStatistic(cpuStatistic, policy.Spec.MetricsConfig.Percentile))
+	memoryPercentile := selectPercentile(containerMetrics.Memory, resourceStatistic(memoryStatistic, policy.Spec.MetricsConfig.Percentile))
 
-	// Apply safety factor
-	safetyFactor := 1.2 // default
-	if policy.Spec.MetricsConfig.SafetyFactor != nil {
-		safetyFactor = *policy.Spec.MetricsConfig.SafetyFactor
+	// Size to the typical daily peak, so one-off spikes in the rolling window are ignored
+	cpuPercentile, memoryPercentile, dailyPeakSource, dailyPeakNote := dailyPeakBase(
+		cpuPercentile, memoryPercentile, windowed.DailyPeaks, policy.Spec.MetricsConfig.DailyPeaks)
+
+	// Combine with the short window so recent spikes are not averaged away
+	cpuBase, memoryBase, blendNote := blendWindows(cpuPercentile, memoryPercentile, windowed.Short, policy.Spec.MetricsConfig.Blend)
+
+	// Reactive override windows can only raise the values, so sustained increases are picked up
+	// without waiting for them to dominate the rolling window
+	var cpuOverride, memoryOverride *metrics.ResourceMetrics
+	if windowed.CPUOverride != nil {
+		cpuOverride = &windowed.CPUOverride.CPU
+	}
+	if windowed.MemoryOverride != nil {
+		memoryOverride = &windowed.MemoryOverride.Memory
+	}
+	var cpuOverrideNote, memoryOverrideNote string
+	cpuBase, cpuOverrideNote = applyOverride("CPU", cpuBase, cpuOverride, cpuStatistic)
+	memoryBase, memoryOverrideNote = applyOverride("memory", memoryBase, memoryOverride, memoryStatistic)
+
+	// Usage capped by the container's limits under-reports the demand, so prefer the limit-based
+	// signal when the container runs against its limits
+	cpuP99, memoryP99 := containerMetrics.CPU.P99, containerMetrics.Memory.P99
+	if windowed.Short != nil {
+		if windowed.Short.CPU.P99.Cmp(cpuP99) > 0 {
+			cpuP99 = windowed.Short.CPU.P99
+		}
+		if windowed.Short.Memory.P99.Cmp(memoryP99) > 0 {
+			memoryP99 = windowed.Short.Memory.P99
+		}
 	}
+	var cpuPressureNote, memoryPressureNote string
+	cpuBase, cpuPressureNote = sizeFromPressure("CPU", cpuBase, cpuP99, workload.CPULimit)
+	memoryBase, memoryPressureNote = sizeFromPressure("memory", memoryBase, memoryP99, workload.MemoryLimit)
+
+	// Spread the total demand over the replicas the workload is converging to
+	cpuBase, memoryBase, replicaNote := scaleByReplicas(cpuBase, memoryBase, policy.Spec.ReplicaScaling, workload)
+
+	// Apply safety factor
+	safetyFactor := policy.Spec.MetricsConfig.GetSafetyFactor()
 
-	cpuWithSafety := multiplyQuantity(cpuPercentile, safetyFactor)
-	memoryWithSafety := multiplyQuantity(memoryPercentile, safetyFactor)
+	// Pods of higher priority get more memory headroom
+	memorySafetyFactor, priorityHeadroom := policy.Spec.MetricsConfig.MemorySafetyFactor(workload.PriorityClassName, workload.Priority)
+
+	cpuWithSafety := multiplyQuantity(cpuBase, safetyFactor)
+	memoryWithSafety := multiplyQuantity(memoryBase, memorySafetyFactor)
+
+	// Leave room for page cache, which the working set excludes
+	memoryWithSafety, cacheHeadroomNote := addCacheHeadroom(memoryWithSafety, memoryBase, policy.Spec.CacheHeadroom)
 
 	// Clamp to bounds
 	cpuRecommendation := clampToBounds(cpuWithSafety, policy.Spec.ResourceBounds.CPU)
 	memoryRecommendation := clampToBounds(memoryWithSafety, policy.Spec.ResourceBounds.Memory)
 
+	// Enforce startup floors so startup-heavy apps are not starved while starting or crash-looping
+	startupNote := ""
+	if active, reason := startupFloorActive(policy, workload); active {
+		var applied []string
+		floor := policy.Spec.StartupFloor
+		if floor.CPU != nil && policy.OptimizesCPU() && cpuRecommendation.Cmp(*floor.CPU) < 0 {
+			cpuRecommendation = floor.CPU.DeepCopy()
+			applied = append(applied, fmt.Sprintf("CPU %s", floor.CPU.String()))
+		}
+		if floor.Memory != nil && policy.OptimizesMemory() && memoryRecommendation.Cmp(*floor.Memory) < 0 {
+			memoryRecommendation = floor.Memory.DeepCopy()
+			applied = append(applied, fmt.Sprintf("memory %s", floor.Memory.String()))
+		}
+		if len(applied) > 0 {
+			startupNote = fmt.Sprintf("; startup floor applied (%s) because %s", strings.Join(applied, ", "), reason)
+		}
+	}
+
+	// A rare but real peak keeps protecting the container after it leaves the rolling window
+	peakFloorNote := ""
+	if policy.Spec.MetricsConfig.MemoryPeakFloor != nil && policy.OptimizesMemory() {
+		memoryRecommendation, peakFloorNote = applyMemoryPeakFloor(memoryRecommendation, workload.MemoryPeak,
+			containerMetrics.Memory.Max, policy.Spec.ResourceBounds.Memory)
+	}
+
+	// Spikes seen only in the short window still count towards OOM protection
+	observedMemoryP99 := containerMetrics.Memory.P99.DeepCopy()
+	if windowed.Short != nil && windowed.Short.Memory.P99.Cmp(observedMemoryP99) > 0 {
+		observedMemoryP99 = windowed.Short.Memory.P99.DeepCopy()
+	}
+
+	// The kubelet evicts pods using more memory than they request first, so evictions for node
+	// memory pressure mean the request is too low whatever the percentiles say
+	evictionNote := ""
+	urgent := false
+	if workload.MemoryEvictions > 0 && policy.OptimizesMemory() {
+		var raised bool
+		memoryRecommendation, raised = raiseForEviction(memoryRecommendation, observedMemoryP99, workload.MemoryRequest,
+			policy.Spec.ResourceBounds.Memory)
+		if raised {
+			evictionNote = fmt.Sprintf("; memory raised to %s because %d pod(s) were evicted for node memory pressure",
+				memoryRecommendation.String(), workload.MemoryEvictions)
+		}
+		urgent = true
+	}
+
 	// Debug: Log the final values
 	fmt.Printf("DEBUG ENGINE: CPU recommendation: %s (millivalue=%d, value=%d, format=%v)\n",
 		cpuRecommendation.String(), cpuRecommendation.MilliValue(), cpuRecommendation.Value(), cpuRecommendation.Format)
@@ -80,23 +279,227 @@ func (e *Engine) ComputeRecommendation(
 	if percentileStr == "" {
 		percentileStr = "P90"
 	}
+	source := percentileStr + " percentile"
+	if dailyPeakSource != "" {
+		source = dailyPeakSource
+	}
+	var observed, bounds []string
+	if policy.OptimizesCPU() {
+		observed = append(observed, fmt.Sprintf("CPU: %s", cpuPercentile.String()))
+		bounds = append(bounds, fmt.Sprintf("CPU: %s-%s",
+			policy.Spec.ResourceBounds.CPU.Min.String(), policy.Spec.ResourceBounds.CPU.Max.String()))
+	}
+	if policy.OptimizesMemory() {
+		observed = append(observed, fmt.Sprintf("Memory: %s", memoryPercentile.String()))
+		bounds = append(bounds, fmt.Sprintf("Memory: %s-%s",
+			policy.Spec.ResourceBounds.Memory.Min.String(), policy.Spec.ResourceBounds.Memory.Max.String()))
+	}
 	explanation := fmt.Sprintf(
-		"Computed from %s percentile (CPU: %s, Memory: %s) with safety factor %.2f, clamped to bounds (CPU: %s-%s, Memory: %s-%s)",
-		percentileStr,
-		cpuPercentile.String(),
-		memoryPercentile.String(),
+		"Computed from %s (%s) with safety factor %.2f, clamped to bounds (%s)",
+		source,
+		strings.Join(observed, ", "),
 		safetyFactor,
-		policy.Spec.ResourceBounds.CPU.Min.String(),
-		policy.Spec.ResourceBounds.CPU.Max.String(),
-		policy.Spec.ResourceBounds.Memory.Min.String(),
-		policy.Spec.ResourceBounds.Memory.Max.String(),
+		strings.Join(bounds, ", "),
 	)
+	explanation += dailyPeakNote + statisticNote(policy.Spec.MetricsConfig.ResourceStatistics) + blendNote
+	if policy.OptimizesCPU() {
+		explanation += cpuOverrideNote + cpuPressureNote
+	}
+	if policy.OptimizesMemory() {
+		explanation += memoryOverrideNote + memoryPressureNote + cacheHeadroomNote
+	}
+	if policy.OptimizesMemory() && priorityHeadroom != nil {
+		explanation += priorityHeadroomNote(memorySafetyFactor, priorityHeadroom, workload)
+	}
+	explanation += replicaNote + startupNote + peakFloorNote + evictionNote
+
+	// Report the bounds the recommendation sits at because the computed value was beyond them
+	var clamped []string
+	var cpuClamped, memoryClamped string
+	if policy.OptimizesCPU() {
+		var note string
+		if cpuClamped, note = clampedBound("CPU", cpuWithSafety, cpuRecommendation, policy.Spec.ResourceBounds.CPU,
+			optipodv1alpha1.BoundCPUMin, optipodv1alpha1.BoundCPUMax); cpuClamped != "" {
+			clamped = append(clamped, cpuClamped)
+			explanation += note
+		}
+	}
+	if policy.OptimizesMemory() {
+		var note string
+		if memoryClamped, note = clampedBound("memory", memoryWithSafety, memoryRecommendation, policy.Spec.ResourceBounds.Memory,
+			optipodv1alpha1.BoundMemoryMin, optipodv1alpha1.BoundMemoryMax); memoryClamped != "" {
+			clamped = append(clamped, memoryClamped)
+			explanation += note
+		}
+	}
+
+	// Resources the policy does not optimize get no recommendation, so they are never patched
+	rec := &Recommendation{
+		Urgent:         urgent,
+		ClampedToBound: clamped,
+		DataQuality:    &optipodv1alpha1.RecommendationDataQuality{Percentile: source, SafetyFactor: safetyFactor},
+	}
+	if policy.OptimizesCPU() {
+		rec.CPU = cpuRecommendation
+		rec.DataQuality.CPU = resourceDataQuality(containerMetrics.CPU, cpuPercentile, cpuBase, cpuWithSafety, cpuClamped)
+	} else {
+		explanation += "; CPU not optimized by policy"
+	}
+	if policy.OptimizesMemory() {
+		rec.Memory = memoryRecommendation
+		rec.ObservedMemoryP99 = observedMemoryP99
+		rec.DataQuality.Memory = resourceDataQuality(containerMetrics.Memory, memoryPercentile, memoryBase, memoryWithSafety, memoryClamped)
+		if priorityHeadroom != nil {
+			rec.DataQuality.Memory.SafetyFactor = memorySafetyFactor
+		}
+	} else {
+		explanation += "; memory not optimized by policy"
+	}
+
+	// Limits follow the observed burst ratio instead of the multipliers
+	if burst := burstRatioLimits(policy); burst != nil {
+		if policy.OptimizesCPU() && policy.Spec.UpdateStrategy.ManagesCPULimit() {
+			rec.CPULimitRatio = burstRatio(containerMetrics.CPU, burst)
+			explanation += fmt.Sprintf("; CPU limit at %.2fx the request from the observed P99/P90 burst ratio", rec.CPULimitRatio)
+		}
+		if policy.OptimizesMemory() && policy.Spec.UpdateStrategy.ManagesMemoryLimit() {
+			rec.MemoryLimitRatio = burstRatio(containerMetrics.Memory, burst)
+			explanation += fmt.Sprintf("; memory limit at %.2fx the request from the observed P99/P90 burst ratio", rec.MemoryLimitRatio)
+		}
+	}
 
-	return &Recommendation{
-		CPU:         cpuRecommendation,
-		Memory:      memoryRecommendation,
-		Explanation: explanation,
-	}, nil
+	updatesLimits := !policy.Spec.UpdateStrategy.UpdateRequestsOnly && !policy.Spec.UpdateStrategy.RemoveLimits
+	if policy.OptimizesCPU() && updatesLimits && policy.Spec.UpdateStrategy.ManagesCPULimit() {
+		rec.DataQuality.CPU.LimitMultiplier = cpuLimitMultiplier(policy)
+		if rec.CPULimitRatio > 0 {
+			rec.DataQuality.CPU.LimitMultiplier = rec.CPULimitRatio
+		}
+	}
+
+	if policy.OptimizesMemory() && updatesLimits && policy.Spec.UpdateStrategy.ManagesMemoryLimit() {
+		multiplier := memoryLimitMultiplier(policy)
+		if rec.MemoryLimitRatio > 0 {
+			multiplier = rec.MemoryLimitRatio
+		}
+		memoryLimit := multiplyQuantity(memoryRecommendation, multiplier)
+		rec.DataQuality.Memory.LimitMultiplier = multiplier
+
+		// Derive the memory limit from a higher percentile instead of the request multiplier
+		if limitConfig := policy.Spec.UpdateStrategy.LimitConfig; limitConfig != nil && limitConfig.MemoryLimitPercentile != "" {
+			limitPercentile := selectPercentile(containerMetrics.Memory, limitConfig.MemoryLimitPercentile)
+			memoryLimit = multiplyQuantity(limitPercentile, memorySafetyFactor)
+
+			// A limit below the request is rejected by the API server
+			if memoryLimit.Cmp(memoryRecommendation) < 0 {
+				memoryLimit = memoryRecommendation.DeepCopy()
+			}
+			rec.MemoryLimit = memoryLimit.DeepCopy()
+			rec.DataQuality.Memory.LimitMultiplier = 0
+
+			explanation += fmt.Sprintf("; memory limit computed from %s percentile (%s) with safety factor %.2f",
+				limitConfig.MemoryLimitPercentile, limitPercentile.String(), memorySafetyFactor)
+		}
+
+		// Memory limits below observed P99 usage would cause OOM kills, so they are raised to P99
+		if memoryLimit.Cmp(observedMemoryP99) < 0 {
+			explanation += fmt.Sprintf("; memory limit raised from %s to observed P99 %s to prevent OOM",
+				memoryLimit.String(), observedMemoryP99.String())
+		}
+	}
+
+	rec.Explanation = explanation
+	rec.RecordFinal()
+	return rec, nil
+}
+
+// scaleByReplicas converts per-pod usage observed at the current replica count into the per-pod
+// share of the total demand at the target replica count. CPU is always scaled, memory only when
+// the policy asks for it. Without replica scaling or replica counts the values are returned unchanged.
+func scaleByReplicas(
+	cpu, memory resource.Quantity,
+	replicaScaling *optipodv1alpha1.ReplicaScaling,
+	workload WorkloadContext,
+) (resource.Quantity, resource.Quantity, string) {
+	if replicaScaling == nil || !replicaScaling.Enabled {
+		return cpu, memory, ""
+	}
+	if workload.CurrentReplicas <= 0 || workload.TargetReplicas <= 0 || workload.CurrentReplicas == workload.TargetReplicas {
+		return cpu, memory, ""
+	}
+
+	factor := float64(workload.CurrentReplicas) / float64(workload.TargetReplicas)
+	scaled := "CPU"
+	cpu = multiplyQuantity(cpu, factor)
+	if replicaScaling.ScaleMemory {
+		memory = multiplyQuantity(memory, factor)
+		scaled = "CPU and memory"
+	}
+
+	note := fmt.Sprintf("; %s scaled by %d/%d replicas (total demand spread over the target replica count)",
+		scaled, workload.CurrentReplicas, workload.TargetReplicas)
+	return cpu, memory, note
+}
+
+// priorityHeadroomNote explains the memory safety factor raised by a priority memory headroom entry
+func priorityHeadroomNote(memorySafetyFactor float64, entry *optipodv1alpha1.PriorityMemoryHeadroom, workload WorkloadContext) string {
+	if entry.PriorityClassName != "" {
+		return fmt.Sprintf("; memory sized with safety factor %.2f for priority class %s", memorySafetyFactor, entry.PriorityClassName)
+	}
+	return fmt.Sprintf("; memory sized with safety factor %.2f for priority %d (at least %d)",
+		memorySafetyFactor, *workload.Priority, *entry.MinPriority)
+}
+
+// startupFloorActive reports whether the policy startup floor applies to the workload and why
+func startupFloorActive(policy *optipodv1alpha1.OptimizationPolicy, workload WorkloadContext) (bool, string) {
+	floor := policy.Spec.StartupFloor
+	if floor == nil {
+		return false, ""
+	}
+
+	if period := policy.GetStartupFloorPeriod(); workload.Age != nil && *workload.Age < period {
+		return true, fmt.Sprintf("workload age %s is within the startup period %s",
+			workload.Age.Round(time.Second), period)
+	}
+
+	if floor.RestartThreshold > 0 && workload.Restarts >= floor.RestartThreshold {
+		return true, fmt.Sprintf("container restarted %d times (threshold %d)", workload.Restarts, floor.RestartThreshold)
+	}
+
+	return false, ""
+}
+
+// cpuLimitMultiplier returns the CPU limit multiplier from the policy, defaulting to 1.0
+func cpuLimitMultiplier(policy *optipodv1alpha1.OptimizationPolicy) float64 {
+	if policy.Spec.UpdateStrategy.LimitConfig != nil && policy.Spec.UpdateStrategy.LimitConfig.CPULimitMultiplier != nil {
+		return *policy.Spec.UpdateStrategy.LimitConfig.CPULimitMultiplier
+	}
+	return 1.0
+}
+
+// memoryLimitMultiplier returns the memory limit multiplier from the policy, defaulting to 1.1
+func memoryLimitMultiplier(policy *optipodv1alpha1.OptimizationPolicy) float64 {
+	if policy.Spec.UpdateStrategy.LimitConfig != nil && policy.Spec.UpdateStrategy.LimitConfig.MemoryLimitMultiplier != nil {
+		return *policy.Spec.UpdateStrategy.LimitConfig.MemoryLimitMultiplier
+	}
+	return 1.1
+}
+
+// resourceDataQuality describes the usage of one resource a recommendation was computed from and
+// the steps from it to the computed value and the bound it was clamped to
+func resourceDataQuality(
+	usage metrics.ResourceMetrics,
+	percentileValue, base, computed resource.Quantity,
+	clampedTo string,
+) *optipodv1alpha1.ResourceDataQuality {
+	percentileValue, base, computed = percentileValue.DeepCopy(), base.DeepCopy(), computed.DeepCopy()
+	return &optipodv1alpha1.ResourceDataQuality{
+		Samples:               int32(min(usage.Samples, math.MaxInt32)),
+		WindowCoveragePercent: int32(math.Round(100 * usage.Coverage)),
+		PercentileValue:       &percentileValue,
+		Base:                  &base,
+		Computed:              &computed,
+		ClampedTo:             clampedTo,
+	}
 }
 
 // selectPercentile selects the appropriate percentile value based on configuration
@@ -106,6 +509,8 @@ func selectPercentile(resourceMetrics metrics.ResourceMetrics, percentile string
 		return resourceMetrics.P50
 	case "P99":
 		return resourceMetrics.P99
+	case optipodv1alpha1.StatisticMax:
+		return resourceMetrics.Max
 	case "P90", "":
 		return resourceMetrics.P90
 	default:
@@ -145,3 +550,24 @@ func clampToBounds(value resource.Quantity, bounds optipodv1alpha1.ResourceBound
 	// Otherwise return value
 	return value.DeepCopy()
 }
+
+// clampedBound returns minBound or maxBound, with an explanation note, when the computed value
+// was beyond that bound and the recommendation still equals it. A recommendation moved off the
+// bound afterwards, e.g. by a startup floor, was not limited by it.
+func clampedBound(
+	resourceName string,
+	computed, recommendation resource.Quantity,
+	bounds optipodv1alpha1.ResourceBound,
+	minBound, maxBound string,
+) (string, string) {
+	switch {
+	case computed.Cmp(bounds.Min) < 0 && recommendation.Cmp(bounds.Min) == 0:
+		return minBound, fmt.Sprintf("; %s clamped to the min bound %s (computed %s)",
+			resourceName, bounds.Min.String(), computed.String())
+	case computed.Cmp(bounds.Max) > 0 && recommendation.Cmp(bounds.Max) == 0:
+		return maxBound, fmt.Sprintf("; %s clamped to the max bound %s (computed %s)",
+			resourceName, bounds.Max.String(), computed.String())
+	default:
+		return "", ""
+	}
+}
@@ -17,13 +17,17 @@ limitations under the License.
 package recommendation
 
 import (
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
 	"github.com/optipod/optipod/internal/metrics"
@@ -33,6 +37,25 @@ const (
 	percentileP90 = "P90"
 )
 
+// createTestPolicy returns an Auto policy recommending the P90 within bounds of 10m-4 CPU and
+// 32Mi-8Gi memory, updating requests only
+func createTestPolicy() *optipodv1alpha1.OptimizationPolicy {
+	return &optipodv1alpha1.OptimizationPolicy{
+		Spec: optipodv1alpha1.OptimizationPolicySpec{
+			Mode: optipodv1alpha1.ModeAuto,
+			MetricsConfig: optipodv1alpha1.MetricsConfig{
+				Provider:   "prometheus",
+				Percentile: percentileP90,
+			},
+			ResourceBounds: optipodv1alpha1.ResourceBounds{
+				CPU:    optipodv1alpha1.ResourceBound{Min: resource.MustParse("10m"), Max: resource.MustParse("4")},
+				Memory: optipodv1alpha1.ResourceBound{Min: resource.MustParse("32Mi"), Max: resource.MustParse("8Gi")},
+			},
+			UpdateStrategy: optipodv1alpha1.UpdateStrategy{UpdateRequestsOnly: true},
+		},
+	}
+}
+
 // Feature: k8s-workload-rightsizing, Property 4: Bounds enforcement
 // Validates: Requirements 2.1, 2.2, 2.3, 2.4, 2.5
 //
@@ -264,3 +287,512 @@ func TestProperty_SafetyFactorApplication(t *testing.T) {
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
+
+// Property: Memory limit floor is recorded in the recommendation
+// For any recommendation where limits are managed, the observed P99 memory is carried on the
+// recommendation, and the explanation notes when the limit must be raised to it
+func TestProperty_MemoryLimitFloorExplanation(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("explanation notes memory limit raised to observed P99", prop.ForAll(
+		func(memP90, memP99 int64, updateRequestsOnly bool) bool {
+			containerMetrics := &metrics.ContainerMetrics{
+				CPU: metrics.ResourceMetrics{
+					P50:     resource.MustParse("100m"),
+					P90:     resource.MustParse("200m"),
+					P99:     resource.MustParse("300m"),
+					Samples: 100,
+				},
+				Memory: metrics.ResourceMetrics{
+					P50:     *resource.NewQuantity(memP90/2, resource.BinarySI),
+					P90:     *resource.NewQuantity(memP90, resource.BinarySI),
+					P99:     *resource.NewQuantity(memP99, resource.BinarySI),
+					Samples: 100,
+				},
+			}
+
+			policy := createTestPolicy()
+			policy.Spec.MetricsConfig.SafetyFactor = ptr.To(1.0)
+			policy.Spec.ResourceBounds.CPU.Max = resource.MustParse("100")
+			policy.Spec.ResourceBounds.Memory = optipodv1alpha1.ResourceBound{
+				Min: *resource.NewQuantity(1, resource.BinarySI),
+				Max: resource.MustParse("1Ti"),
+			}
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = updateRequestsOnly
+
+			rec, err := NewEngine().ComputeRecommendation(containerMetrics, policy)
+			if err != nil {
+				return false
+			}
+
+			if rec.ObservedMemoryP99.Value() != memP99 {
+				return false
+			}
+
+			// Default memory multiplier is 1.1
+			limitBelowP99 := int64(float64(rec.Memory.Value())*1.1) < memP99
+			noted := strings.Contains(rec.Explanation, "memory limit raised")
+
+			return noted == (!updateRequestsOnly && limitBelowP99)
+		},
+		gen.Int64Range(1024*1024, 1024*1024*1024),
+		gen.Int64Range(1024*1024, 4*1024*1024*1024),
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// Property: Percentile-derived memory limits
+// For any policy with a memory limit percentile, the memory limit is computed from that
+// percentile with the safety factor applied, independent of the memory request multiplier,
+// and is never below the memory request
+func TestProperty_MemoryLimitPercentile(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("memory limit derives from the limit percentile", prop.ForAll(
+		func(memP90, memP99 int64, safetyFactor float64, updateRequestsOnly bool) bool {
+			containerMetrics := &metrics.ContainerMetrics{
+				CPU: metrics.ResourceMetrics{
+					P50:     resource.MustParse("100m"),
+					P90:     resource.MustParse("200m"),
+					P99:     resource.MustParse("300m"),
+					Samples: 100,
+				},
+				Memory: metrics.ResourceMetrics{
+					P50:     *resource.NewQuantity(memP90/2, resource.BinarySI),
+					P90:     *resource.NewQuantity(memP90, resource.BinarySI),
+					P99:     *resource.NewQuantity(memP90+memP99, resource.BinarySI),
+					Samples: 100,
+				},
+			}
+
+			multiplier := 5.0
+			policy := createTestPolicy()
+			policy.Spec.MetricsConfig.SafetyFactor = &safetyFactor
+			policy.Spec.ResourceBounds.CPU.Max = resource.MustParse("100")
+			policy.Spec.ResourceBounds.Memory = optipodv1alpha1.ResourceBound{
+				Min: *resource.NewQuantity(1, resource.BinarySI),
+				Max: resource.MustParse("1Ti"),
+			}
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = updateRequestsOnly
+			policy.Spec.UpdateStrategy.LimitConfig = &optipodv1alpha1.LimitConfig{
+				MemoryLimitMultiplier: &multiplier,
+				MemoryLimitPercentile: "P99",
+			}
+
+			rec, err := NewEngine().ComputeRecommendation(containerMetrics, policy)
+			if err != nil {
+				return false
+			}
+
+			// Limits are not computed when only requests are managed
+			if updateRequestsOnly {
+				return rec.MemoryLimit.IsZero()
+			}
+
+			expected := int64(float64(memP90+memP99) * safetyFactor)
+			if expected < rec.Memory.Value() {
+				expected = rec.Memory.Value()
+			}
+
+			return rec.MemoryLimit.Value() == expected &&
+				rec.MemoryLimit.Cmp(rec.Memory) >= 0 &&
+				strings.Contains(rec.Explanation, "memory limit computed from P99 percentile")
+		},
+		gen.Int64Range(1024*1024, 1024*1024*1024),
+		gen.Int64Range(0, 1024*1024*1024),
+		gen.Float64Range(1.0, 2.0),
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestComputeRecommendationForWorkload_StartupFloor(t *testing.T) {
+	containerMetrics := &metrics.ContainerMetrics{
+		CPU: metrics.ResourceMetrics{
+			P50:     resource.MustParse("50m"),
+			P90:     resource.MustParse("100m"),
+			P99:     resource.MustParse("150m"),
+			Samples: 100,
+		},
+		Memory: metrics.ResourceMetrics{
+			P50:     resource.MustParse("64Mi"),
+			P90:     resource.MustParse("128Mi"),
+			P99:     resource.MustParse("192Mi"),
+			Samples: 100,
+		},
+	}
+
+	cpuFloor := resource.MustParse("1")
+	memoryFloor := resource.MustParse("1Gi")
+	young := 10 * time.Minute
+	old := 48 * time.Hour
+
+	tests := []struct {
+		name         string
+		floor        *optipodv1alpha1.StartupFloor
+		workload     WorkloadContext
+		wantFloor    bool
+		wantInReason string
+	}{
+		{
+			name:      "no startup floor configured",
+			floor:     nil,
+			workload:  WorkloadContext{Age: &young},
+			wantFloor: false,
+		},
+		{
+			name:         "workload within the startup period",
+			floor:        &optipodv1alpha1.StartupFloor{CPU: &cpuFloor, Memory: &memoryFloor},
+			workload:     WorkloadContext{Age: &young},
+			wantFloor:    true,
+			wantInReason: "within the startup period 1h0m0s",
+		},
+		{
+			name:      "workload past the startup period",
+			floor:     &optipodv1alpha1.StartupFloor{CPU: &cpuFloor, Memory: &memoryFloor},
+			workload:  WorkloadContext{Age: &old},
+			wantFloor: false,
+		},
+		{
+			name:      "unknown workload age",
+			floor:     &optipodv1alpha1.StartupFloor{CPU: &cpuFloor, Memory: &memoryFloor},
+			workload:  WorkloadContext{},
+			wantFloor: false,
+		},
+		{
+			name:         "frequent restarts past the startup period",
+			floor:        &optipodv1alpha1.StartupFloor{CPU: &cpuFloor, Memory: &memoryFloor, RestartThreshold: 3},
+			workload:     WorkloadContext{Age: &old, Restarts: 5},
+			wantFloor:    true,
+			wantInReason: "restarted 5 times (threshold 3)",
+		},
+		{
+			name:      "restarts below the threshold",
+			floor:     &optipodv1alpha1.StartupFloor{CPU: &cpuFloor, Memory: &memoryFloor, RestartThreshold: 3},
+			workload:  WorkloadContext{Age: &old, Restarts: 2},
+			wantFloor: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createTestPolicy()
+			policy.Spec.StartupFloor = tt.floor
+
+			rec, err := NewEngine().ComputeRecommendationForWorkload(containerMetrics, policy, tt.workload)
+			if err != nil {
+				t.Fatalf("ComputeRecommendationForWorkload() error = %v", err)
+			}
+
+			floored := rec.CPU.Cmp(cpuFloor) == 0 && rec.Memory.Cmp(memoryFloor) == 0
+			if floored != tt.wantFloor {
+				t.Errorf("floor applied = %v, want %v (CPU %s, memory %s)", floored, tt.wantFloor, rec.CPU.String(), rec.Memory.String())
+			}
+
+			noted := strings.Contains(rec.Explanation, "startup floor applied")
+			if noted != tt.wantFloor {
+				t.Errorf("explanation notes startup floor = %v, want %v: %s", noted, tt.wantFloor, rec.Explanation)
+			}
+			if tt.wantInReason != "" && !strings.Contains(rec.Explanation, tt.wantInReason) {
+				t.Errorf("explanation %q does not contain %q", rec.Explanation, tt.wantInReason)
+			}
+		})
+	}
+}
+
+func TestComputeRecommendationForWorkload_ReplicaScaling(t *testing.T) {
+	containerMetrics := &metrics.ContainerMetrics{
+		CPU: metrics.ResourceMetrics{
+			P50:     resource.MustParse("50m"),
+			P90:     resource.MustParse("100m"),
+			P99:     resource.MustParse("150m"),
+			Samples: 100,
+		},
+		Memory: metrics.ResourceMetrics{
+			P50:     resource.MustParse("64Mi"),
+			P90:     resource.MustParse("128Mi"),
+			P99:     resource.MustParse("192Mi"),
+			Samples: 100,
+		},
+	}
+
+	// P90 with the default 1.2 safety factor
+	const unscaledCPU, unscaledMemory = 120, 161061273
+
+	tests := []struct {
+		name           string
+		replicaScaling *optipodv1alpha1.ReplicaScaling
+		workload       WorkloadContext
+		wantCPU        int64 // millicores
+		wantMemory     int64 // bytes
+		wantNote       bool
+	}{
+		{
+			name:           "replica scaling disabled by default",
+			replicaScaling: nil,
+			workload:       WorkloadContext{CurrentReplicas: 2, TargetReplicas: 4},
+			wantCPU:        unscaledCPU,
+			wantMemory:     unscaledMemory,
+		},
+		{
+			name:           "scaling up shrinks the per-pod CPU request",
+			replicaScaling: &optipodv1alpha1.ReplicaScaling{Enabled: true},
+			workload:       WorkloadContext{CurrentReplicas: 2, TargetReplicas: 4},
+			wantCPU:        60,
+			wantMemory:     unscaledMemory,
+			wantNote:       true,
+		},
+		{
+			name:           "scaling down grows the per-pod request including memory",
+			replicaScaling: &optipodv1alpha1.ReplicaScaling{Enabled: true, ScaleMemory: true},
+			workload:       WorkloadContext{CurrentReplicas: 4, TargetReplicas: 2},
+			wantCPU:        240,
+			wantMemory:     322122547,
+			wantNote:       true,
+		},
+		{
+			name:           "steady replica count",
+			replicaScaling: &optipodv1alpha1.ReplicaScaling{Enabled: true, ScaleMemory: true},
+			workload:       WorkloadContext{CurrentReplicas: 3, TargetReplicas: 3},
+			wantCPU:        unscaledCPU,
+			wantMemory:     unscaledMemory,
+		},
+		{
+			name:           "unknown replica counts",
+			replicaScaling: &optipodv1alpha1.ReplicaScaling{Enabled: true},
+			workload:       WorkloadContext{},
+			wantCPU:        unscaledCPU,
+			wantMemory:     unscaledMemory,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createTestPolicy()
+			policy.Spec.ReplicaScaling = tt.replicaScaling
+
+			rec, err := NewEngine().ComputeRecommendationForWorkload(containerMetrics, policy, tt.workload)
+			if err != nil {
+				t.Fatalf("ComputeRecommendationForWorkload() error = %v", err)
+			}
+
+			if rec.CPU.MilliValue() != tt.wantCPU {
+				t.Errorf("CPU = %dm, want %dm", rec.CPU.MilliValue(), tt.wantCPU)
+			}
+			if rec.Memory.Value() != tt.wantMemory {
+				t.Errorf("Memory = %d, want %d", rec.Memory.Value(), tt.wantMemory)
+			}
+
+			noted := strings.Contains(rec.Explanation, "replicas (total demand spread over the target replica count)")
+			if noted != tt.wantNote {
+				t.Errorf("explanation notes replica scaling = %v, want %v: %s", noted, tt.wantNote, rec.Explanation)
+			}
+		})
+	}
+}
+
+func TestComputeRecommendation_ResourcesNotOptimized(t *testing.T) {
+	containerMetrics := &metrics.ContainerMetrics{
+		CPU:    metrics.ResourceMetrics{P50: resource.MustParse("50m"), P90: resource.MustParse("100m"), P99: resource.MustParse("150m"), Samples: 100},
+		Memory: metrics.ResourceMetrics{P50: resource.MustParse("64Mi"), P90: resource.MustParse("128Mi"), P99: resource.MustParse("192Mi"), Samples: 100},
+	}
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name           string
+		optimizeCPU    *bool
+		optimizeMemory *bool
+		wantNote       string
+	}{
+		{name: "both optimized by default"},
+		{name: "CPU only", optimizeCPU: &enabled, optimizeMemory: &disabled, wantNote: "memory not optimized by policy"},
+		{name: "memory only", optimizeCPU: &disabled, wantNote: "CPU not optimized by policy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createTestPolicy()
+			// Limits are computed, so a memory limit would be derived if memory were optimized
+			policy.Spec.UpdateStrategy = optipodv1alpha1.UpdateStrategy{
+				LimitConfig: &optipodv1alpha1.LimitConfig{MemoryLimitPercentile: "P99"},
+			}
+			policy.Spec.OptimizeCPU = tt.optimizeCPU
+			policy.Spec.OptimizeMemory = tt.optimizeMemory
+
+			rec, err := NewEngine().ComputeRecommendation(containerMetrics, policy)
+			if err != nil {
+				t.Fatalf("ComputeRecommendation() error = %v", err)
+			}
+
+			if rec.CPU.IsZero() == policy.OptimizesCPU() {
+				t.Errorf("CPU recommendation = %s, want one only when CPU is optimized", rec.CPU.String())
+			}
+			if rec.Memory.IsZero() == policy.OptimizesMemory() {
+				t.Errorf("memory recommendation = %s, want one only when memory is optimized", rec.Memory.String())
+			}
+			if !policy.OptimizesMemory() && (!rec.MemoryLimit.IsZero() || !rec.ObservedMemoryP99.IsZero()) {
+				t.Errorf("memory limit inputs = %s and %s, want none when memory is not optimized",
+					rec.MemoryLimit.String(), rec.ObservedMemoryP99.String())
+			}
+			if tt.wantNote != "" && !strings.Contains(rec.Explanation, tt.wantNote) {
+				t.Errorf("explanation %q does not contain %q", rec.Explanation, tt.wantNote)
+			}
+		})
+	}
+}
+
+func TestComputeRecommendation_ClampedToBound(t *testing.T) {
+	ptr := func(q string) *resource.Quantity { v := resource.MustParse(q); return &v }
+	young := 10 * time.Minute
+
+	tests := []struct {
+		name            string
+		cpuBounds       [2]string
+		memoryBounds    [2]string
+		optimizeCPU     *bool
+		startupFloor    *optipodv1alpha1.StartupFloor
+		wantClamped     []string
+		wantExplanation string
+	}{
+		{name: "within bounds", cpuBounds: [2]string{"10m", "1"}, memoryBounds: [2]string{"64Mi", "1Gi"}},
+		{
+			name: "CPU at the max bound", cpuBounds: [2]string{"10m", "50m"}, memoryBounds: [2]string{"64Mi", "1Gi"},
+			wantClamped: []string{optipodv1alpha1.BoundCPUMax}, wantExplanation: "CPU clamped to the max bound 50m (computed 100m)",
+		},
+		{
+			name: "memory at the min bound", cpuBounds: [2]string{"10m", "1"}, memoryBounds: [2]string{"512Mi", "1Gi"},
+			wantClamped: []string{optipodv1alpha1.BoundMemoryMin}, wantExplanation: "memory clamped to the min bound 512Mi (computed 128Mi)",
+		},
+		{
+			name: "CPU at the min bound and memory at the max bound", cpuBounds: [2]string{"500m", "1"}, memoryBounds: [2]string{"64Mi", "100Mi"},
+			wantClamped: []string{optipodv1alpha1.BoundCPUMin, optipodv1alpha1.BoundMemoryMax},
+		},
+		{
+			name: "a startup floor above the min bound", cpuBounds: [2]string{"500m", "2"}, memoryBounds: [2]string{"64Mi", "1Gi"},
+			startupFloor: &optipodv1alpha1.StartupFloor{CPU: ptr("1")},
+		},
+		{
+			name: "resources not optimized are never clamped", cpuBounds: [2]string{"500m", "1"}, memoryBounds: [2]string{"64Mi", "100Mi"},
+			optimizeCPU: new(bool), wantClamped: []string{optipodv1alpha1.BoundMemoryMax},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newBlendTestPolicy(nil)
+			policy.Spec.ResourceBounds.CPU = optipodv1alpha1.ResourceBound{Min: resource.MustParse(tt.cpuBounds[0]), Max: resource.MustParse(tt.cpuBounds[1])}
+			policy.Spec.ResourceBounds.Memory = optipodv1alpha1.ResourceBound{Min: resource.MustParse(tt.memoryBounds[0]), Max: resource.MustParse(tt.memoryBounds[1])}
+			policy.Spec.OptimizeCPU = tt.optimizeCPU
+			policy.Spec.StartupFloor = tt.startupFloor
+
+			rec, err := NewEngine().ComputeRecommendationForWorkload(
+				newBlendTestMetrics("100m", "150m", "128Mi", "192Mi", 100), policy, WorkloadContext{Age: &young})
+			if err != nil {
+				t.Fatalf("ComputeRecommendationForWorkload() error = %v", err)
+			}
+			if !slices.Equal(rec.ClampedToBound, tt.wantClamped) {
+				t.Errorf("ClampedToBound = %v, want %v", rec.ClampedToBound, tt.wantClamped)
+			}
+			if !strings.Contains(rec.Explanation, tt.wantExplanation) {
+				t.Errorf("explanation %q does not contain %q", rec.Explanation, tt.wantExplanation)
+			}
+			if len(tt.wantClamped) == 0 && strings.Contains(rec.Explanation, "clamped to the") {
+				t.Errorf("explanation %q reports a clamp", rec.Explanation)
+			}
+
+			// The breakdown reports the same clamps per resource
+			var breakdown []string
+			for _, quality := range []*optipodv1alpha1.ResourceDataQuality{rec.DataQuality.CPU, rec.DataQuality.Memory} {
+				if quality != nil && quality.ClampedTo != "" {
+					breakdown = append(breakdown, quality.ClampedTo)
+				}
+			}
+			if !slices.Equal(breakdown, tt.wantClamped) {
+				t.Errorf("breakdown clamped to %v, want %v", breakdown, tt.wantClamped)
+			}
+		})
+	}
+}
+
+func TestComputeRecommendation_DataQuality(t *testing.T) {
+	policy := newBlendTestPolicy(nil)
+	safetyFactor := 1.5
+	policy.Spec.MetricsConfig.SafetyFactor = &safetyFactor
+	policy.Spec.ResourceBounds.CPU.Max = resource.MustParse("100m")
+	containerMetrics := newBlendTestMetrics("100m", "150m", "128Mi", "192Mi", 1440)
+	containerMetrics.CPU.Coverage = 0.5
+	containerMetrics.Memory.Coverage = 0.5
+
+	rec, err := NewEngine().ComputeRecommendation(containerMetrics, policy)
+	if err != nil {
+		t.Fatalf("ComputeRecommendation() error = %v", err)
+	}
+	quality := rec.DataQuality
+	if quality == nil || quality.Percentile != "P90 percentile" || quality.SafetyFactor != safetyFactor {
+		t.Fatalf("DataQuality = %+v, want the P90 percentile with safety factor 1.5", quality)
+	}
+
+	// The computed CPU is beyond the max bound, the recommendation is clamped to it
+	cpu := quality.CPU
+	if cpu == nil || cpu.Samples != 1440 || cpu.WindowCoveragePercent != 50 ||
+		cpu.PercentileValue.Cmp(resource.MustParse("100m")) != 0 || cpu.Computed.Cmp(resource.MustParse("150m")) != 0 {
+		t.Errorf("CPU data quality = %+v, want 1440 samples over 50%% of the window, P90 100m computed as 150m", cpu)
+	}
+	if rec.CPU.Cmp(resource.MustParse("100m")) != 0 {
+		t.Errorf("CPU = %s, want the max bound 100m", rec.CPU.String())
+	}
+	if cpu != nil && (cpu.Base.Cmp(resource.MustParse("100m")) != 0 || cpu.ClampedTo != optipodv1alpha1.BoundCPUMax ||
+		cpu.Final.Cmp(rec.CPU) != 0 || cpu.LimitMultiplier != 0) {
+		t.Errorf("CPU breakdown = base %v, clamped to %q, final %v, limit multiplier %v, want 100m clamped to CPUMax, final 100m and no limit",
+			cpu.Base, cpu.ClampedTo, cpu.Final, cpu.LimitMultiplier)
+	}
+	if memory := quality.Memory; memory == nil || memory.Computed.Cmp(resource.MustParse("192Mi")) != 0 ||
+		memory.ClampedTo != "" || memory.Final.Cmp(rec.Memory) != 0 {
+		t.Errorf("memory data quality = %+v, want 128Mi computed as 192Mi and kept as the final value", memory)
+	}
+
+	// Updating limits reports the multiplier the limits are derived from
+	cpuMultiplier := 2.0
+	policy.Spec.UpdateStrategy.UpdateRequestsOnly = false
+	policy.Spec.UpdateStrategy.LimitConfig = &optipodv1alpha1.LimitConfig{CPULimitMultiplier: &cpuMultiplier}
+	rec, err = NewEngine().ComputeRecommendation(containerMetrics, policy)
+	if err != nil {
+		t.Fatalf("ComputeRecommendation() error = %v", err)
+	}
+	if rec.DataQuality.CPU.LimitMultiplier != 2.0 || rec.DataQuality.Memory.LimitMultiplier != 1.1 {
+		t.Errorf("limit multipliers = CPU %v, memory %v, want 2.0 and the default 1.1",
+			rec.DataQuality.CPU.LimitMultiplier, rec.DataQuality.Memory.LimitMultiplier)
+	}
+	policy.Spec.UpdateStrategy.UpdateRequestsOnly = true
+	policy.Spec.UpdateStrategy.LimitConfig = nil
+
+	// Resources the policy does not optimize are not described
+	policy.Spec.OptimizeMemory = new(bool)
+	rec, err = NewEngine().ComputeRecommendation(containerMetrics, policy)
+	if err != nil {
+		t.Fatalf("ComputeRecommendation() error = %v", err)
+	}
+	if rec.DataQuality.Memory != nil {
+		t.Errorf("memory data quality = %+v, want none when memory is not optimized", rec.DataQuality.Memory)
+	}
+}
+
+func TestRecommendation_RecordFinal(t *testing.T) {
+	rec, err := NewEngine().ComputeRecommendation(newBlendTestMetrics("100m", "150m", "128Mi", "192Mi", 100), newBlendTestPolicy(nil))
+	if err != nil {
+		t.Fatalf("ComputeRecommendation() error = %v", err)
+	}
+
+	// An adjustment after the recommendation was computed, e.g. a cap at node capacity
+	rec.CPU = resource.MustParse("80m")
+	rec.RecordFinal()
+	if rec.DataQuality.CPU.Final.Cmp(rec.CPU) != 0 {
+		t.Errorf("final CPU = %v, want the adjusted request %s", rec.DataQuality.CPU.Final, rec.CPU.String())
+	}
+
+	// Recommendations without a breakdown are left alone
+	(&Recommendation{}).RecordFinal()
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// evictionMemoryBump is the factor applied to the larger of the observed P99 memory usage and the
+// current request when pods are evicted for node memory pressure
+const evictionMemoryBump = 1.25
+
+// raiseForEviction returns the memory recommendation raised to evictionMemoryBump times the larger
+// of the observed P99 usage and the current request, clamped to the maximum bound. The second
+// result is true when the recommendation was raised.
+func raiseForEviction(
+	memory, observedP99, request resource.Quantity,
+	bounds optipodv1alpha1.ResourceBound,
+) (resource.Quantity, bool) {
+	base := observedP99
+	if request.Cmp(base) > 0 {
+		base = request
+	}
+	floor := multiplyQuantity(base, evictionMemoryBump)
+	if !bounds.Max.IsZero() && floor.Cmp(bounds.Max) > 0 {
+		floor = bounds.Max.DeepCopy()
+	}
+	if memory.Cmp(floor) >= 0 {
+		return memory, false
+	}
+	return floor, true
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+func TestComputeRecommendationForWorkload_MemoryEvictions(t *testing.T) {
+	containerMetrics := &metrics.ContainerMetrics{
+		CPU:    metrics.ResourceMetrics{P50: resource.MustParse("100m"), P90: resource.MustParse("200m"), P99: resource.MustParse("300m"), Samples: 100},
+		Memory: metrics.ResourceMetrics{P50: resource.MustParse("128Mi"), P90: resource.MustParse("256Mi"), P99: resource.MustParse("300Mi"), Samples: 100},
+	}
+
+	tests := []struct {
+		name       string
+		workload   WorkloadContext
+		maxMemory  string
+		wantMemory string
+		wantUrgent bool
+		wantRaised bool
+	}{
+		{
+			name:       "no evictions",
+			workload:   WorkloadContext{MemoryRequest: resource.MustParse("256Mi")},
+			wantMemory: "322122547", // P90 256Mi * 1.2
+		},
+		{
+			name:       "raised above observed P99",
+			workload:   WorkloadContext{MemoryRequest: resource.MustParse("200Mi"), MemoryEvictions: 2},
+			wantMemory: "375Mi", // P99 300Mi * 1.25
+			wantUrgent: true,
+			wantRaised: true,
+		},
+		{
+			name:       "raised above the current request",
+			workload:   WorkloadContext{MemoryRequest: resource.MustParse("400Mi"), MemoryEvictions: 1},
+			wantMemory: "500Mi", // request 400Mi * 1.25
+			wantUrgent: true,
+			wantRaised: true,
+		},
+		{
+			name:       "clamped to the maximum bound",
+			workload:   WorkloadContext{MemoryRequest: resource.MustParse("400Mi"), MemoryEvictions: 1},
+			maxMemory:  "450Mi",
+			wantMemory: "450Mi",
+			wantUrgent: true,
+			wantRaised: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxMemory := "8Gi"
+			if tt.maxMemory != "" {
+				maxMemory = tt.maxMemory
+			}
+			policy := createTestPolicy()
+			policy.Spec.ResourceBounds.Memory.Max = resource.MustParse(maxMemory)
+
+			rec, err := NewEngine().ComputeRecommendationForWorkload(containerMetrics, policy, tt.workload)
+			if err != nil {
+				t.Fatalf("ComputeRecommendationForWorkload() error = %v", err)
+			}
+			if want := resource.MustParse(tt.wantMemory); rec.Memory.Cmp(want) != 0 {
+				t.Errorf("memory = %s, want %s", rec.Memory.String(), tt.wantMemory)
+			}
+			if rec.Urgent != tt.wantUrgent {
+				t.Errorf("urgent = %v, want %v", rec.Urgent, tt.wantUrgent)
+			}
+			if raised := strings.Contains(rec.Explanation, "evicted for node memory pressure"); raised != tt.wantRaised {
+				t.Errorf("explanation %q mentions the evictions = %v, want %v", rec.Explanation, raised, tt.wantRaised)
+			}
+		})
+	}
+}
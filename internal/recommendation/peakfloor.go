@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+)
+
+// applyMemoryPeakFloor raises the memory recommendation to the retained peak, or the highest
+// usage of the rolling window when it is higher, clamped to the maximum bound. It returns the
+// recommendation along with a note for the explanation when it was raised.
+func applyMemoryPeakFloor(
+	memory, retainedPeak, windowMax resource.Quantity,
+	bounds optipodv1alpha1.ResourceBound,
+) (resource.Quantity, string) {
+	floor := retainedPeak
+	if windowMax.Cmp(floor) > 0 {
+		floor = windowMax
+	}
+	if !bounds.Max.IsZero() && floor.Cmp(bounds.Max) > 0 {
+		floor = bounds.Max
+	}
+	if floor.IsZero() || memory.Cmp(floor) >= 0 {
+		return memory, ""
+	}
+	return floor.DeepCopy(), fmt.Sprintf("; memory raised from %s to the retained peak %s", memory.String(), floor.String())
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+func TestComputeRecommendationForWorkload_MemoryPeakFloor(t *testing.T) {
+	containerMetrics := &metrics.ContainerMetrics{
+		CPU:    metrics.ResourceMetrics{P50: resource.MustParse("100m"), P90: resource.MustParse("200m"), P99: resource.MustParse("300m"), Samples: 100},
+		Memory: metrics.ResourceMetrics{P50: resource.MustParse("128Mi"), P90: resource.MustParse("256Mi"), P99: resource.MustParse("300Mi"), Samples: 100},
+	}
+
+	tests := []struct {
+		name       string
+		floor      *optipodv1alpha1.MemoryPeakFloor
+		peak       string
+		windowMax  string
+		maxMemory  string
+		wantMemory string
+		wantNote   bool
+	}{
+		{
+			name:       "no floor configured",
+			windowMax:  "300Mi",
+			peak:       "1Gi",
+			wantMemory: "322122547", // P90 256Mi * 1.2
+		},
+		{
+			name:       "raised to the retained peak",
+			windowMax:  "300Mi",
+			floor:      &optipodv1alpha1.MemoryPeakFloor{},
+			peak:       "1Gi",
+			wantMemory: "1Gi",
+			wantNote:   true,
+		},
+		{
+			name:       "raised to the window maximum above the retained peak",
+			windowMax:  "320Mi",
+			floor:      &optipodv1alpha1.MemoryPeakFloor{},
+			peak:       "200Mi",
+			wantMemory: "320Mi",
+			wantNote:   true,
+		},
+		{
+			name:       "peak below the recommendation",
+			windowMax:  "300Mi",
+			floor:      &optipodv1alpha1.MemoryPeakFloor{},
+			peak:       "100Mi",
+			wantMemory: "322122547",
+		},
+		{
+			name:       "clamped to the maximum bound",
+			windowMax:  "300Mi",
+			floor:      &optipodv1alpha1.MemoryPeakFloor{},
+			peak:       "4Gi",
+			maxMemory:  "2Gi",
+			wantMemory: "2Gi",
+			wantNote:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxMemory := "8Gi"
+			if tt.maxMemory != "" {
+				maxMemory = tt.maxMemory
+			}
+			metricsForTest := *containerMetrics
+			metricsForTest.Memory.Max = resource.MustParse(tt.windowMax)
+			policy := createTestPolicy()
+			policy.Spec.MetricsConfig.MemoryPeakFloor = tt.floor
+			policy.Spec.ResourceBounds.Memory.Max = resource.MustParse(maxMemory)
+
+			rec, err := NewEngine().ComputeRecommendationForWorkload(&metricsForTest, policy,
+				WorkloadContext{MemoryPeak: resource.MustParse(tt.peak)})
+			if err != nil {
+				t.Fatalf("ComputeRecommendationForWorkload() error = %v", err)
+			}
+			if want := resource.MustParse(tt.wantMemory); rec.Memory.Cmp(want) != 0 {
+				t.Errorf("memory = %s, want %s", rec.Memory.String(), want.String())
+			}
+			if got := strings.Contains(rec.Explanation, "retained peak"); got != tt.wantNote {
+				t.Errorf("explanation %q mentions the retained peak = %v, want %v", rec.Explanation, got, tt.wantNote)
+			}
+		})
+	}
+}
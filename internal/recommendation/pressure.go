@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// limitPressureThreshold is the share of its limit at which a container's P99 usage is treated as
+// capped by the limit. CPU usage of a throttled container cannot exceed its limit, so the usage
+// then under-reports the demand; memory usage this close to the limit is about to be OOM killed.
+const limitPressureThreshold = 0.9
+
+// sizeFromPressure returns the usage to size a resource from, choosing between two signals:
+//
+//   - request-based: the observed usage percentile, which is what the container used
+//   - limit-based: when P99 usage is at or above limitPressureThreshold of the current limit, the
+//     usage the container needs for its P99 to fall back below the threshold
+//
+// The higher-pressure signal wins, so throttled containers are never sized from their capped usage.
+// The returned note names the signal that drove the result; it is empty when the container has no
+// limit, since only the request-based signal exists then.
+func sizeFromPressure(name string, usage, p99, limit resource.Quantity) (resource.Quantity, string) {
+	if limit.IsZero() {
+		return usage, ""
+	}
+
+	pressure := float64(p99.MilliValue()) / float64(limit.MilliValue())
+	required := multiplyQuantity(p99, 1/limitPressureThreshold)
+	if pressure < limitPressureThreshold || required.Cmp(usage) <= 0 {
+		return usage, fmt.Sprintf("; %s sized from request-based utilization (P99 %s is %.0f%% of the %s limit)",
+			name, p99.String(), pressure*100, limit.String())
+	}
+	return required, fmt.Sprintf("; %s sized from limit-based utilization: P99 %s is %.0f%% of the %s limit, "+
+		"so usage is capped by the limit and %s is needed to bring it below %.0f%%",
+		name, p99.String(), pressure*100, limit.String(), required.String(), limitPressureThreshold*100)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+func TestComputeRecommendationForWorkload_LimitPressure(t *testing.T) {
+	healthy := &metrics.ContainerMetrics{
+		CPU:    metrics.ResourceMetrics{P50: resource.MustParse("100m"), P90: resource.MustParse("200m"), P99: resource.MustParse("300m"), Samples: 100},
+		Memory: metrics.ResourceMetrics{P50: resource.MustParse("128Mi"), P90: resource.MustParse("256Mi"), P99: resource.MustParse("300Mi"), Samples: 100},
+	}
+	// CPU pinned at its 1 core limit, memory at 98% of its 512Mi limit
+	constrained := &metrics.ContainerMetrics{
+		CPU:    metrics.ResourceMetrics{P50: resource.MustParse("900m"), P90: resource.MustParse("950m"), P99: resource.MustParse("1"), Samples: 100},
+		Memory: metrics.ResourceMetrics{P50: resource.MustParse("400Mi"), P90: resource.MustParse("480Mi"), P99: resource.MustParse("500Mi"), Samples: 100},
+	}
+	limits := WorkloadContext{CPULimit: resource.MustParse("1"), MemoryLimit: resource.MustParse("512Mi")}
+
+	tests := []struct {
+		name        string
+		metrics     *metrics.ContainerMetrics
+		workload    WorkloadContext
+		wantCPU     int64 // millicores
+		wantMemory  int64 // bytes
+		wantSignals []string
+	}{
+		{
+			name:       "healthy container without limits",
+			metrics:    healthy,
+			wantCPU:    240,       // P90 200m * 1.2
+			wantMemory: 322122547, // P90 256Mi * 1.2
+		},
+		{
+			name:        "healthy container well below its limits",
+			metrics:     healthy,
+			workload:    limits,
+			wantCPU:     240,
+			wantMemory:  322122547,
+			wantSignals: []string{"CPU sized from request-based utilization", "memory sized from request-based utilization"},
+		},
+		{
+			name:        "throttled container is sized from its limits",
+			metrics:     constrained,
+			workload:    limits,
+			wantCPU:     1333,      // P99 1 / 0.9 * 1.2
+			wantMemory:  699050666, // P99 500Mi / 0.9 * 1.2
+			wantSignals: []string{"CPU sized from limit-based utilization", "memory sized from limit-based utilization"},
+		},
+		{
+			name:       "constrained usage without limits is not capped",
+			metrics:    constrained,
+			wantCPU:    1140,      // P90 950m * 1.2
+			wantMemory: 603979776, // P90 480Mi * 1.2
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := NewEngine().ComputeRecommendationForWorkload(tt.metrics, createTestPolicy(), tt.workload)
+			if err != nil {
+				t.Fatalf("ComputeRecommendationForWorkload() error = %v", err)
+			}
+
+			if rec.CPU.MilliValue() != tt.wantCPU {
+				t.Errorf("CPU = %dm, want %dm", rec.CPU.MilliValue(), tt.wantCPU)
+			}
+			if rec.Memory.Value() != tt.wantMemory {
+				t.Errorf("Memory = %d, want %d", rec.Memory.Value(), tt.wantMemory)
+			}
+			for _, signal := range tt.wantSignals {
+				if !strings.Contains(rec.Explanation, signal) {
+					t.Errorf("explanation does not name the signal %q: %s", signal, rec.Explanation)
+				}
+			}
+			if len(tt.wantSignals) == 0 && strings.Contains(rec.Explanation, "utilization") {
+				t.Errorf("explanation names a signal without limits: %s", rec.Explanation)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+func TestComputeRecommendation_PriorityMemoryHeadroom(t *testing.T) {
+	usage := &metrics.ContainerMetrics{
+		CPU:    metrics.ResourceMetrics{P50: resource.MustParse("100m"), P90: resource.MustParse("200m"), P99: resource.MustParse("300m"), Samples: 100},
+		Memory: metrics.ResourceMetrics{P50: resource.MustParse("128Mi"), P90: resource.MustParse("256Mi"), P99: resource.MustParse("300Mi"), Samples: 100},
+	}
+	priority := func(p int32) *int32 { return &p }
+	headroom := []optipodv1alpha1.PriorityMemoryHeadroom{
+		{PriorityClassName: "system-cluster-critical", MemorySafetyFactor: 2.0},
+		{PriorityClassName: "batch-low", MemorySafetyFactor: 1.0},
+		{MinPriority: priority(1000), MemorySafetyFactor: 1.5},
+		{MinPriority: priority(100000), MemorySafetyFactor: 1.75},
+	}
+
+	tests := []struct {
+		name       string
+		headroom   []optipodv1alpha1.PriorityMemoryHeadroom
+		className  string
+		priority   *int32
+		wantMemory int64 // bytes
+		wantNote   string
+	}{
+		{
+			name:       "no mapping keeps the uniform safety factor",
+			className:  "system-cluster-critical",
+			priority:   priority(2000000000),
+			wantMemory: 322122547, // P90 256Mi * 1.2
+		},
+		{
+			name:       "unknown priority",
+			headroom:   headroom,
+			wantMemory: 322122547,
+		},
+		{
+			name:       "priority class name takes precedence",
+			headroom:   headroom,
+			className:  "system-cluster-critical",
+			priority:   priority(500),
+			wantMemory: 536870912, // 256Mi * 2.0
+			wantNote:   "memory sized with safety factor 2.00 for priority class system-cluster-critical",
+		},
+		{
+			name:       "highest threshold the priority reaches",
+			headroom:   headroom,
+			className:  "high",
+			priority:   priority(200000),
+			wantMemory: 469762048, // 256Mi * 1.75
+			wantNote:   "memory sized with safety factor 1.75 for priority 200000 (at least 100000)",
+		},
+		{
+			name:       "lower threshold",
+			headroom:   headroom,
+			priority:   priority(5000),
+			wantMemory: 402653184, // 256Mi * 1.5
+		},
+		{
+			name:       "below every threshold",
+			headroom:   headroom,
+			priority:   priority(10),
+			wantMemory: 322122547,
+		},
+		{
+			name:       "never below the safety factor",
+			headroom:   headroom,
+			className:  "batch-low",
+			priority:   priority(200000),
+			wantMemory: 322122547,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createTestPolicy()
+			policy.Spec.UpdateStrategy.UpdateRequestsOnly = false
+			policy.Spec.MetricsConfig.PriorityMemoryHeadroom = tt.headroom
+			workload := WorkloadContext{PriorityClassName: tt.className, Priority: tt.priority}
+
+			rec, err := NewEngine().ComputeRecommendationForWorkload(usage, policy, workload)
+			if err != nil {
+				t.Fatalf("ComputeRecommendationForWorkload() error = %v", err)
+			}
+			if got := rec.Memory.Value(); got != tt.wantMemory {
+				t.Errorf("memory = %d, want %d", got, tt.wantMemory)
+			}
+			// Priority headroom is memory only
+			if got := rec.CPU.MilliValue(); got != 240 {
+				t.Errorf("CPU = %dm, want 240m", got)
+			}
+			if tt.wantNote != "" && !strings.Contains(rec.Explanation, tt.wantNote) {
+				t.Errorf("explanation %q does not mention %q", rec.Explanation, tt.wantNote)
+			}
+			if raised := rec.DataQuality.Memory.SafetyFactor != 0; raised != (tt.wantMemory != 322122547) {
+				t.Errorf("memory data quality safety factor = %v, want it set only when raised", rec.DataQuality.Memory.SafetyFactor)
+			}
+		})
+	}
+}
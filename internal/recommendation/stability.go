@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"math"
+
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+const (
+	// p90ZScore is how many standard deviations the 90th percentile lies above the median of a
+	// normal distribution, used to estimate the spread of usage from its percentiles
+	p90ZScore = 1.2816
+
+	// stabilityFullCoverageSamples is the number of samples below which a workload's usage is
+	// considered too sparsely observed to be fully trusted
+	stabilityFullCoverageSamples = 10
+)
+
+// StabilityScore rates from 0 (volatile) to 100 (steady) how safe the usage of a workload's
+// containers is to size automatically. It combines three factors:
+//   - variability: the coefficient of variation of usage, estimated from the median and the
+//     90th percentile since raw samples are not retained; the most variable resource counts
+//   - coverage: the share of stabilityFullCoverageSamples backing the sparsest resource
+//   - restarts: each restart of the workload's containers within the rolling window lowers the score
+func StabilityScore(containers []*metrics.ContainerMetrics, restarts int32) int32 {
+	if len(containers) == 0 {
+		return 0
+	}
+
+	variation := 0.0
+	samples := math.MaxInt
+	for _, container := range containers {
+		for _, usage := range []metrics.ResourceMetrics{container.CPU, container.Memory} {
+			variation = math.Max(variation, coefficientOfVariation(usage))
+			samples = min(samples, usage.Samples)
+		}
+	}
+
+	coverage := math.Min(float64(samples)/stabilityFullCoverageSamples, 1)
+	restartFactor := 1 / (1 + float64(max(restarts, 0)))
+
+	return int32(math.Round(100 * (1 - math.Min(variation, 1)) * coverage * restartFactor))
+}
+
+// coefficientOfVariation estimates the standard deviation of usage relative to its median,
+// assuming roughly normally distributed usage
+func coefficientOfVariation(usage metrics.ResourceMetrics) float64 {
+	median := usage.P50.AsApproximateFloat64()
+	spread := usage.P90.AsApproximateFloat64() - median
+	if spread <= 0 {
+		return 0
+	}
+	if median <= 0 {
+		// Usage that is usually idle but sometimes not is as volatile as it gets
+		return 1
+	}
+	return spread / (p90ZScore * median)
+}
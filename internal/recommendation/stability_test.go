@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+// newStabilityTestMetrics returns container metrics with the same medians and P90s for CPU and memory
+func newStabilityTestMetrics(p50, p90 string, samples int) *metrics.ContainerMetrics {
+	usage := metrics.ResourceMetrics{
+		P50:     resource.MustParse(p50),
+		P90:     resource.MustParse(p90),
+		P99:     resource.MustParse(p90),
+		Samples: samples,
+	}
+	return &metrics.ContainerMetrics{CPU: usage, Memory: usage}
+}
+
+func TestStabilityScore(t *testing.T) {
+	steady := newStabilityTestMetrics("100m", "110m", 100)
+
+	tests := []struct {
+		name       string
+		containers []*metrics.ContainerMetrics
+		restarts   int32
+		want       int32
+	}{
+		{
+			name:       "steady usage",
+			containers: []*metrics.ContainerMetrics{steady},
+			want:       92, // coefficient of variation 0.1 / 1.2816
+		},
+		{
+			name:       "constant usage",
+			containers: []*metrics.ContainerMetrics{newStabilityTestMetrics("100m", "100m", 100)},
+			want:       100,
+		},
+		{
+			name:       "the most volatile container counts",
+			containers: []*metrics.ContainerMetrics{steady, newStabilityTestMetrics("100m", "200m", 100)},
+			want:       22, // coefficient of variation 1 / 1.2816
+		},
+		{
+			name:       "sparse samples",
+			containers: []*metrics.ContainerMetrics{newStabilityTestMetrics("100m", "110m", 5)},
+			want:       46,
+		},
+		{
+			name:       "restarts",
+			containers: []*metrics.ContainerMetrics{steady},
+			restarts:   1,
+			want:       46,
+		},
+		{
+			name:       "mostly idle with bursts",
+			containers: []*metrics.ContainerMetrics{newStabilityTestMetrics("0", "100m", 100)},
+			want:       0,
+		},
+		{
+			name: "no containers",
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StabilityScore(tt.containers, tt.restarts); got != tt.want {
+				t.Errorf("StabilityScore() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+// resourceStatistic returns the statistic the rolling window value of a resource is taken from:
+// the resource's own statistic when configured, the policy percentile otherwise
+func resourceStatistic(config *optipodv1alpha1.ResourceStatistic, percentile string) string {
+	if config != nil && config.Statistic != "" {
+		return config.Statistic
+	}
+	return percentile
+}
+
+// statisticNote describes the statistics the resources are computed from for the explanation,
+// when a resource does not use the policy percentile
+func statisticNote(statistics *optipodv1alpha1.ResourceStatistics) string {
+	if statistics == nil {
+		return ""
+	}
+	note := ""
+	if statistics.CPU != nil && statistics.CPU.Statistic != "" {
+		note += fmt.Sprintf("; CPU from the %s of the rolling window", statistics.CPU.Statistic)
+	}
+	if statistics.Memory != nil && statistics.Memory.Statistic != "" {
+		note += fmt.Sprintf("; memory from the %s of the rolling window", statistics.Memory.Statistic)
+	}
+	return note
+}
+
+// applyOverride raises base to the override statistic of the short window when that is higher,
+// along with a note for the explanation. The override never lowers base, and a short window
+// without samples leaves it unchanged.
+func applyOverride(
+	resourceName string,
+	base resource.Quantity,
+	short *metrics.ResourceMetrics,
+	config *optipodv1alpha1.ResourceStatistic,
+) (resource.Quantity, string) {
+	if config == nil || config.Override == nil || short == nil || short.Samples == 0 {
+		return base, ""
+	}
+	statistic := config.Override.GetStatistic()
+	recent := selectPercentile(*short, statistic)
+	if recent.Cmp(base) <= 0 {
+		return base, ""
+	}
+	return recent.DeepCopy(), fmt.Sprintf("; %s raised from %s to %s by the %s of the %s override window",
+		resourceName, base.String(), recent.String(), statistic, config.Override.Window.Duration)
+}
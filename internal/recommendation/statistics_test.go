@@ -0,0 +1,232 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optipodv1alpha1 "github.com/optipod/optipod/api/v1alpha1"
+	"github.com/optipod/optipod/internal/metrics"
+)
+
+// newStatisticsTestMetrics returns metrics with the given CPU P50/P90 and memory P90/max
+func newStatisticsTestMetrics(cpuP50, cpuP90, memoryP90, memoryMax string, samples int) *metrics.ContainerMetrics {
+	return &metrics.ContainerMetrics{
+		CPU: metrics.ResourceMetrics{
+			P50:     resource.MustParse(cpuP50),
+			P90:     resource.MustParse(cpuP90),
+			P99:     resource.MustParse(cpuP90),
+			Max:     resource.MustParse(cpuP90),
+			Samples: samples,
+		},
+		Memory: metrics.ResourceMetrics{
+			P50:     resource.MustParse(memoryP90),
+			P90:     resource.MustParse(memoryP90),
+			P99:     resource.MustParse(memoryP90),
+			Max:     resource.MustParse(memoryMax),
+			Samples: samples,
+		},
+	}
+}
+
+func newOverride(window time.Duration, statistic string) *optipodv1alpha1.ReactiveOverride {
+	return &optipodv1alpha1.ReactiveOverride{Window: metav1.Duration{Duration: window}, Statistic: statistic}
+}
+
+func TestCollectMetrics_OverrideWindows(t *testing.T) {
+	tests := []struct {
+		name        string
+		statistics  *optipodv1alpha1.ResourceStatistics
+		wantWindows []time.Duration
+	}{
+		{
+			name:        "statistic without override",
+			statistics:  &optipodv1alpha1.ResourceStatistics{Memory: &optipodv1alpha1.ResourceStatistic{Statistic: optipodv1alpha1.StatisticMax}},
+			wantWindows: []time.Duration{24 * time.Hour},
+		},
+		{
+			name: "one override",
+			statistics: &optipodv1alpha1.ResourceStatistics{
+				CPU: &optipodv1alpha1.ResourceStatistic{Override: newOverride(15*time.Minute, "")},
+			},
+			wantWindows: []time.Duration{24 * time.Hour, 15 * time.Minute},
+		},
+		{
+			name: "overrides sharing a window are queried once",
+			statistics: &optipodv1alpha1.ResourceStatistics{
+				CPU:    &optipodv1alpha1.ResourceStatistic{Override: newOverride(15*time.Minute, "")},
+				Memory: &optipodv1alpha1.ResourceStatistic{Override: newOverride(15*time.Minute, "")},
+			},
+			wantWindows: []time.Duration{24 * time.Hour, 15 * time.Minute},
+		},
+		{
+			name: "overrides with different windows",
+			statistics: &optipodv1alpha1.ResourceStatistics{
+				CPU:    &optipodv1alpha1.ResourceStatistic{Override: newOverride(15*time.Minute, "")},
+				Memory: &optipodv1alpha1.ResourceStatistic{Override: newOverride(time.Hour, "")},
+			},
+			wantWindows: []time.Duration{24 * time.Hour, 15 * time.Minute, time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &windowMetricsProvider{byWindow: map[time.Duration]*metrics.ContainerMetrics{
+				24 * time.Hour:   newStatisticsTestMetrics("50m", "100m", "256Mi", "1Gi", 100),
+				15 * time.Minute: newStatisticsTestMetrics("400m", "500m", "256Mi", "256Mi", 30),
+				time.Hour:        newStatisticsTestMetrics("400m", "500m", "256Mi", "256Mi", 120),
+			}}
+			policy := newBlendTestPolicy(nil)
+			policy.Spec.MetricsConfig.ResourceStatistics = tt.statistics
+
+			windowed, err := NewEngine().CollectMetrics(context.Background(), provider, "default", "pod", "app", policy)
+			if err != nil {
+				t.Fatalf("CollectMetrics() error = %v", err)
+			}
+			if !reflect.DeepEqual(provider.windows, tt.wantWindows) {
+				t.Errorf("queried windows = %v, want %v", provider.windows, tt.wantWindows)
+			}
+			if tt.statistics.CPU != nil && windowed.CPUOverride == nil {
+				t.Error("expected override window metrics in CPUOverride")
+			}
+			if tt.statistics.Memory != nil && tt.statistics.Memory.Override != nil && windowed.MemoryOverride == nil {
+				t.Error("expected override window metrics in MemoryOverride")
+			}
+		})
+	}
+}
+
+func TestComputeBlendedRecommendation_ResourceStatistics(t *testing.T) {
+	// A week of usage: CPU P90 100m, memory P90 256Mi with a 1Gi maximum
+	long := newStatisticsTestMetrics("50m", "100m", "256Mi", "1Gi", 1000)
+
+	// Memory sized to the weekly max, CPU reacting to the median of the last 15 minutes
+	weeklyMaxMemory := &optipodv1alpha1.ResourceStatistics{
+		CPU:    &optipodv1alpha1.ResourceStatistic{Override: newOverride(15*time.Minute, "")},
+		Memory: &optipodv1alpha1.ResourceStatistic{Statistic: optipodv1alpha1.StatisticMax, Override: newOverride(15*time.Minute, "")},
+	}
+
+	tests := []struct {
+		name       string
+		statistics *optipodv1alpha1.ResourceStatistics
+		override   *metrics.ContainerMetrics
+		blend      *optipodv1alpha1.BlendConfig
+		short      *metrics.ContainerMetrics
+		wantCPU    string
+		wantMemory string
+		wantNotes  []string
+	}{
+		{
+			name:       "policy percentile without statistics",
+			wantCPU:    "100m",
+			wantMemory: "256Mi",
+		},
+		{
+			name:       "memory from the weekly max, CPU unchanged by a quiet override window",
+			statistics: weeklyMaxMemory,
+			override:   newStatisticsTestMetrics("80m", "300m", "128Mi", "128Mi", 30),
+			wantCPU:    "100m",
+			wantMemory: "1Gi",
+			wantNotes:  []string{"memory from the Max of the rolling window"},
+		},
+		{
+			name:       "sustained CPU increase raises CPU",
+			statistics: weeklyMaxMemory,
+			override:   newStatisticsTestMetrics("400m", "500m", "128Mi", "128Mi", 30),
+			wantCPU:    "400m",
+			wantMemory: "1Gi",
+			wantNotes:  []string{"CPU raised from 100m to 400m by the P50 of the 15m0s override window"},
+		},
+		{
+			name:       "memory override above the weekly max raises memory",
+			statistics: weeklyMaxMemory,
+			override:   newStatisticsTestMetrics("50m", "50m", "2Gi", "2Gi", 30),
+			wantCPU:    "100m",
+			wantMemory: "2Gi",
+			wantNotes:  []string{"memory raised from 1Gi to 2Gi by the P50 of the 15m0s override window"},
+		},
+		{
+			name:       "override window without samples is ignored",
+			statistics: weeklyMaxMemory,
+			override:   newStatisticsTestMetrics("400m", "500m", "2Gi", "2Gi", 0),
+			wantCPU:    "100m",
+			wantMemory: "1Gi",
+		},
+		{
+			name: "override applies after the blend",
+			statistics: &optipodv1alpha1.ResourceStatistics{
+				CPU: &optipodv1alpha1.ResourceStatistic{Override: newOverride(15*time.Minute, optipodv1alpha1.StatisticMax)},
+			},
+			blend:      &optipodv1alpha1.BlendConfig{ShortWindow: metav1.Duration{Duration: time.Hour}},
+			short:      newStatisticsTestMetrics("150m", "200m", "300Mi", "300Mi", 120),
+			override:   newStatisticsTestMetrics("150m", "600m", "128Mi", "128Mi", 30),
+			wantCPU:    "600m",
+			wantMemory: "300Mi",
+			wantNotes:  []string{"CPU raised from 200m to 600m by the Max of the 15m0s override window"},
+		},
+		{
+			name: "override below the blend does not lower it",
+			statistics: &optipodv1alpha1.ResourceStatistics{
+				CPU: &optipodv1alpha1.ResourceStatistic{Override: newOverride(15*time.Minute, "")},
+			},
+			blend:      &optipodv1alpha1.BlendConfig{ShortWindow: metav1.Duration{Duration: time.Hour}},
+			short:      newStatisticsTestMetrics("150m", "200m", "300Mi", "300Mi", 120),
+			override:   newStatisticsTestMetrics("150m", "600m", "128Mi", "128Mi", 30),
+			wantCPU:    "200m",
+			wantMemory: "300Mi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newBlendTestPolicy(tt.blend)
+			policy.Spec.MetricsConfig.ResourceStatistics = tt.statistics
+			windowed := &WindowedMetrics{Long: long, Short: tt.short}
+			if tt.statistics != nil {
+				if tt.statistics.CPU != nil {
+					windowed.CPUOverride = tt.override
+				}
+				if tt.statistics.Memory != nil {
+					windowed.MemoryOverride = tt.override
+				}
+			}
+
+			rec, err := NewEngine().ComputeBlendedRecommendation(windowed, policy, WorkloadContext{})
+			if err != nil {
+				t.Fatalf("ComputeBlendedRecommendation() error = %v", err)
+			}
+			if rec.CPU.Cmp(resource.MustParse(tt.wantCPU)) != 0 {
+				t.Errorf("CPU = %s, want %s", rec.CPU.String(), tt.wantCPU)
+			}
+			if rec.Memory.Cmp(resource.MustParse(tt.wantMemory)) != 0 {
+				t.Errorf("memory = %s, want %s", rec.Memory.String(), tt.wantMemory)
+			}
+			for _, note := range tt.wantNotes {
+				if !strings.Contains(rec.Explanation, note) {
+					t.Errorf("explanation %q does not mention %q", rec.Explanation, note)
+				}
+			}
+		})
+	}
+}
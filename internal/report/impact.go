@@ -0,0 +1,350 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultInterval is the default interval between impact report writes
+	DefaultInterval = 5 * time.Minute
+
+	// DefaultConfigMapName is the default name of the ConfigMap holding the impact report
+	DefaultConfigMapName = "optipod-dry-run-report"
+
+	// ReportDataKey is the ConfigMap data key under which the JSON report is stored
+	ReportDataKey = "report.json"
+
+	// DefaultRetention is how long a container's proposed change is kept after it was last
+	// recorded. Workloads that were deleted or no longer match a policy drop out once it passes.
+	DefaultRetention = 24 * time.Hour
+)
+
+// ContainerChange describes the proposed change for a single container
+type ContainerChange struct {
+	Namespace     string
+	WorkloadKind  string
+	WorkloadName  string
+	ContainerName string
+
+	CurrentCPU     resource.Quantity
+	CurrentMemory  resource.Quantity
+	ProposedCPU    resource.Quantity
+	ProposedMemory resource.Quantity
+}
+
+// NamespaceSummary holds the aggregated impact for a single namespace
+type NamespaceSummary struct {
+	Workloads       int    `json:"workloads"`
+	Containers      int    `json:"containers"`
+	CPUIncreases    int    `json:"cpuIncreases"`
+	CPUDecreases    int    `json:"cpuDecreases"`
+	MemoryIncreases int    `json:"memoryIncreases"`
+	MemoryDecreases int    `json:"memoryDecreases"`
+	CPUDelta        string `json:"cpuDelta"`
+	MemoryDelta     string `json:"memoryDelta"`
+}
+
+// Summary is the cluster-wide impact report of the most recent recommendations
+type Summary struct {
+	GeneratedAt metav1.Time `json:"generatedAt"`
+	NamespaceSummary
+	Namespaces map[string]NamespaceSummary `json:"namespaces"`
+}
+
+// Collector accumulates the latest proposed change for every container seen
+// during reconciliation. It is safe for concurrent use.
+type Collector struct {
+	retention time.Duration
+	now       func() time.Time
+
+	mu         sync.Mutex
+	changes    map[string]ContainerChange
+	recordedAt map[string]time.Time
+}
+
+// NewCollector creates an empty impact collector keeping changes for DefaultRetention
+func NewCollector() *Collector {
+	return &Collector{
+		retention:  DefaultRetention,
+		now:        time.Now,
+		changes:    make(map[string]ContainerChange),
+		recordedAt: make(map[string]time.Time),
+	}
+}
+
+// Record stores the proposed change for a container, replacing any earlier one
+func (c *Collector) Record(change ContainerChange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := changeKey(change)
+	c.changes[key] = change
+	c.recordedAt[key] = c.now()
+}
+
+// pruneLocked drops the changes not recorded within the retention. c.mu must be held.
+func (c *Collector) pruneLocked() {
+	cutoff := c.now().Add(-c.retention)
+	for key, recordedAt := range c.recordedAt {
+		if recordedAt.Before(cutoff) {
+			delete(c.changes, key)
+			delete(c.recordedAt, key)
+		}
+	}
+}
+
+// Summarize aggregates the recorded changes into a cluster-wide summary
+func (c *Collector) Summarize(now time.Time) *Summary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pruneLocked()
+
+	type accumulator struct {
+		summary   NamespaceSummary
+		workloads map[string]bool
+		cpuDelta  resource.Quantity
+		memDelta  resource.Quantity
+	}
+
+	newAccumulator := func() *accumulator {
+		return &accumulator{
+			workloads: make(map[string]bool),
+			cpuDelta:  *resource.NewMilliQuantity(0, resource.DecimalSI),
+			memDelta:  *resource.NewQuantity(0, resource.BinarySI),
+		}
+	}
+
+	total := newAccumulator()
+	perNamespace := make(map[string]*accumulator)
+
+	for _, change := range c.changes {
+		ns, ok := perNamespace[change.Namespace]
+		if !ok {
+			ns = newAccumulator()
+			perNamespace[change.Namespace] = ns
+		}
+
+		workloadKey := fmt.Sprintf("%s/%s/%s", change.Namespace, change.WorkloadKind, change.WorkloadName)
+		for _, acc := range []*accumulator{total, ns} {
+			acc.workloads[workloadKey] = true
+			acc.summary.Containers++
+
+			switch change.ProposedCPU.Cmp(change.CurrentCPU) {
+			case 1:
+				acc.summary.CPUIncreases++
+			case -1:
+				acc.summary.CPUDecreases++
+			}
+			switch change.ProposedMemory.Cmp(change.CurrentMemory) {
+			case 1:
+				acc.summary.MemoryIncreases++
+			case -1:
+				acc.summary.MemoryDecreases++
+			}
+
+			acc.cpuDelta.Add(change.ProposedCPU)
+			acc.cpuDelta.Sub(change.CurrentCPU)
+			acc.memDelta.Add(change.ProposedMemory)
+			acc.memDelta.Sub(change.CurrentMemory)
+		}
+	}
+
+	finish := func(acc *accumulator) NamespaceSummary {
+		acc.summary.Workloads = len(acc.workloads)
+		acc.summary.CPUDelta = acc.cpuDelta.String()
+		acc.summary.MemoryDelta = acc.memDelta.String()
+		return acc.summary
+	}
+
+	summary := &Summary{
+		GeneratedAt:      metav1.NewTime(now),
+		NamespaceSummary: finish(total),
+		Namespaces:       make(map[string]NamespaceSummary, len(perNamespace)),
+	}
+	for name, acc := range perNamespace {
+		summary.Namespaces[name] = finish(acc)
+	}
+
+	return summary
+}
+
+// Changes returns the changes recorded within the retention, ordered by namespace, workload and
+// container
+func (c *Collector) Changes() []ContainerChange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pruneLocked()
+
+	keys := make([]string, 0, len(c.changes))
+	for key := range c.changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	changes := make([]ContainerChange, 0, len(keys))
+	for _, key := range keys {
+		changes = append(changes, c.changes[key])
+	}
+	return changes
+}
+
+// changeKey generates a unique key for a container change
+func changeKey(change ContainerChange) string {
+	return fmt.Sprintf("%s/%s/%s/%s", change.Namespace, change.WorkloadKind, change.WorkloadName, change.ContainerName)
+}
+
+// Reporter periodically writes the collector's summary to a ConfigMap and the log.
+// It implements manager.Runnable so it can be added to the controller manager.
+type Reporter struct {
+	collector *Collector
+	client    client.Client
+	reader    client.Reader
+	interval  time.Duration
+	namespace string
+	name      string
+	now       func() time.Time
+}
+
+// NewReporter creates a reporter. When namespace or name is empty the report is
+// only logged as a structured summary. The ConfigMap is read back through reader, which should
+// bypass the manager's cache: the operator may not list or watch ConfigMaps.
+func NewReporter(collector *Collector, c client.Client, reader client.Reader, interval time.Duration, namespace, name string) *Reporter {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Reporter{
+		collector: collector,
+		client:    c,
+		reader:    reader,
+		interval:  interval,
+		namespace: namespace,
+		name:      name,
+		now:       time.Now,
+	}
+}
+
+// Start writes a report every interval until the context is cancelled
+func (r *Reporter) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.WriteReport(ctx); err != nil {
+				log.Error(err, "Failed to write dry-run impact report")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection ensures only the leader writes the report
+func (r *Reporter) NeedLeaderElection() bool {
+	return true
+}
+
+// WriteReport logs the current summary and, if configured, stores it in the target ConfigMap
+func (r *Reporter) WriteReport(ctx context.Context) error {
+	log := logf.FromContext(ctx)
+	summary := r.collector.Summarize(r.now())
+
+	namespaces := make([]string, 0, len(summary.Namespaces))
+	for ns := range summary.Namespaces {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	log.Info("Dry-run impact report",
+		"workloads", summary.Workloads,
+		"containers", summary.Containers,
+		"cpuIncreases", summary.CPUIncreases,
+		"cpuDecreases", summary.CPUDecreases,
+		"memoryIncreases", summary.MemoryIncreases,
+		"memoryDecreases", summary.MemoryDecreases,
+		"cpuDelta", summary.CPUDelta,
+		"memoryDelta", summary.MemoryDelta,
+		"namespaces", namespaces)
+
+	if r.client == nil || r.namespace == "" || r.name == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal impact report: %w", err)
+	}
+
+	if err := writeConfigMapKey(ctx, r.reader, r.client, r.namespace, r.name, ReportDataKey, string(data)); err != nil {
+		return fmt.Errorf("failed to write impact report: %w", err)
+	}
+
+	return nil
+}
+
+// writeConfigMapKey stores a value under a single data key of a ConfigMap, creating the ConfigMap
+// if needed. The ConfigMap is read through reader and written through c.
+func writeConfigMapKey(ctx context.Context, reader client.Reader, c client.Writer, namespace, name, key, value string) error {
+	configMap := &corev1.ConfigMap{}
+	err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, configMap)
+	if apierrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "optipod",
+				},
+			},
+			Data: map[string]string{key: value},
+		}
+		if err := c.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create ConfigMap %s/%s: %w", namespace, name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ConfigMap %s/%s: %w", namespace, name, err)
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[key] = value
+	if err := c.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update ConfigMap %s/%s: %w", namespace, name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newChange(namespace, workload, container, curCPU, curMem, propCPU, propMem string) ContainerChange {
+	return ContainerChange{
+		Namespace:      namespace,
+		WorkloadKind:   "Deployment",
+		WorkloadName:   workload,
+		ContainerName:  container,
+		CurrentCPU:     resource.MustParse(curCPU),
+		CurrentMemory:  resource.MustParse(curMem),
+		ProposedCPU:    resource.MustParse(propCPU),
+		ProposedMemory: resource.MustParse(propMem),
+	}
+}
+
+func TestCollector_Summarize(t *testing.T) {
+	collector := NewCollector()
+	collector.Record(newChange("team-a", "api", "app", "500m", "512Mi", "250m", "768Mi"))
+	collector.Record(newChange("team-a", "api", "sidecar", "100m", "64Mi", "100m", "32Mi"))
+	collector.Record(newChange("team-b", "worker", "app", "1", "1Gi", "1500m", "1Gi"))
+
+	summary := collector.Summarize(time.Now())
+
+	if summary.Workloads != 2 {
+		t.Errorf("expected 2 workloads, got %d", summary.Workloads)
+	}
+	if summary.Containers != 3 {
+		t.Errorf("expected 3 containers, got %d", summary.Containers)
+	}
+	if summary.CPUIncreases != 1 || summary.CPUDecreases != 1 {
+		t.Errorf("expected 1 CPU increase and 1 decrease, got %d and %d", summary.CPUIncreases, summary.CPUDecreases)
+	}
+	if summary.MemoryIncreases != 1 || summary.MemoryDecreases != 1 {
+		t.Errorf("expected 1 memory increase and 1 decrease, got %d and %d", summary.MemoryIncreases, summary.MemoryDecreases)
+	}
+
+	cpuDelta := resource.MustParse(summary.CPUDelta)
+	if cpuDelta.MilliValue() != 250 {
+		t.Errorf("expected CPU delta of 250m, got %s", summary.CPUDelta)
+	}
+	memDelta := resource.MustParse(summary.MemoryDelta)
+	if memDelta.Value() != 224*1024*1024 {
+		t.Errorf("expected memory delta of 224Mi, got %s", summary.MemoryDelta)
+	}
+
+	teamA, ok := summary.Namespaces["team-a"]
+	if !ok {
+		t.Fatal("expected a breakdown for namespace team-a")
+	}
+	if teamA.Workloads != 1 || teamA.Containers != 2 {
+		t.Errorf("expected team-a to have 1 workload and 2 containers, got %d and %d", teamA.Workloads, teamA.Containers)
+	}
+	teamACPU := resource.MustParse(teamA.CPUDelta)
+	if teamACPU.MilliValue() != -250 {
+		t.Errorf("expected team-a CPU delta of -250m, got %s", teamA.CPUDelta)
+	}
+}
+
+func TestCollector_RecordReplacesEarlierChange(t *testing.T) {
+	collector := NewCollector()
+	collector.Record(newChange("default", "api", "app", "100m", "128Mi", "200m", "128Mi"))
+	collector.Record(newChange("default", "api", "app", "100m", "128Mi", "50m", "128Mi"))
+
+	summary := collector.Summarize(time.Now())
+
+	if summary.Containers != 1 {
+		t.Errorf("expected repeated recommendations to be counted once, got %d containers", summary.Containers)
+	}
+	if summary.CPUIncreases != 0 || summary.CPUDecreases != 1 {
+		t.Errorf("expected only the latest change to count, got %d increases and %d decreases",
+			summary.CPUIncreases, summary.CPUDecreases)
+	}
+}
+
+func TestReporter_WriteReport(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	// The operator may not list or watch ConfigMaps, so reading through the cached client fails
+	cachedClient := interceptor.NewClient(k8sClient, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			return apierrors.NewForbidden(corev1.Resource("configmaps"), key.Name, errors.New("cannot list configmaps"))
+		},
+	})
+
+	collector := NewCollector()
+	collector.Record(newChange("default", "api", "app", "100m", "128Mi", "200m", "256Mi"))
+
+	reporter := NewReporter(collector, cachedClient, k8sClient, time.Minute, "optipod-system", DefaultConfigMapName)
+	ctx := context.Background()
+
+	// First write creates the ConfigMap, second write updates it
+	for i := 0; i < 2; i++ {
+		if err := reporter.WriteReport(ctx); err != nil {
+			t.Fatalf("write %d failed: %v", i, err)
+		}
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: "optipod-system", Name: DefaultConfigMapName}
+	if err := k8sClient.Get(ctx, key, configMap); err != nil {
+		t.Fatalf("expected report ConfigMap to exist: %v", err)
+	}
+
+	var summary Summary
+	if err := json.Unmarshal([]byte(configMap.Data[ReportDataKey]), &summary); err != nil {
+		t.Fatalf("failed to parse report: %v", err)
+	}
+	if summary.Workloads != 1 || summary.CPUIncreases != 1 || summary.MemoryIncreases != 1 {
+		t.Errorf("unexpected report contents: %+v", summary)
+	}
+	if _, ok := summary.Namespaces["default"]; !ok {
+		t.Error("expected per-namespace breakdown in the report")
+	}
+}
+
+func TestReporter_LogOnlyWithoutTarget(t *testing.T) {
+	collector := NewCollector()
+	reporter := NewReporter(collector, nil, nil, 0, "", "")
+
+	if reporter.interval != DefaultInterval {
+		t.Errorf("expected default interval %v, got %v", DefaultInterval, reporter.interval)
+	}
+	if err := reporter.WriteReport(context.Background()); err != nil {
+		t.Errorf("expected log-only report to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// RulesDataKey is the ConfigMap data key under which the recording rules file is stored
+	RulesDataKey = "optipod-recommendations.rules.yaml"
+
+	// RuleGroupName is the name of the generated Prometheus rule group
+	RuleGroupName = "optipod-recommendations"
+)
+
+// Recorded series. Each container gets one series per metric, labelled with
+// namespace, workload_kind, workload and container.
+const (
+	RecommendedCPURecord    = "optipod:recommendation_cpu_cores"
+	RecommendedMemoryRecord = "optipod:recommendation_memory_bytes"
+	CurrentCPURecord        = "optipod:current_cpu_request_cores"
+	CurrentMemoryRecord     = "optipod:current_memory_request_bytes"
+)
+
+// RenderRecordingRules renders the changes as a Prometheus rule file. Each recording rule
+// evaluates to a constant, so Prometheus stores the recommendation as a regular series on
+// every evaluation and it can be queried historically and alerted on (e.g. drift between
+// the current request and the recommendation).
+func RenderRecordingRules(changes []ContainerChange) string {
+	var b strings.Builder
+	b.WriteString("# Generated by OptiPod. Do not edit.\n")
+	b.WriteString("groups:\n")
+	fmt.Fprintf(&b, "- name: %s\n", RuleGroupName)
+
+	if len(changes) == 0 {
+		b.WriteString("  rules: []\n")
+		return b.String()
+	}
+
+	b.WriteString("  rules:\n")
+	for _, change := range changes {
+		writeRule(&b, RecommendedCPURecord, cpuCores(change.ProposedCPU), change)
+		writeRule(&b, RecommendedMemoryRecord, strconv.FormatInt(change.ProposedMemory.Value(), 10), change)
+		writeRule(&b, CurrentCPURecord, cpuCores(change.CurrentCPU), change)
+		writeRule(&b, CurrentMemoryRecord, strconv.FormatInt(change.CurrentMemory.Value(), 10), change)
+	}
+
+	return b.String()
+}
+
+// writeRule writes a single constant recording rule for a container
+func writeRule(b *strings.Builder, record, value string, change ContainerChange) {
+	fmt.Fprintf(b, "  - record: %s\n", record)
+	fmt.Fprintf(b, "    expr: vector(%s)\n", value)
+	b.WriteString("    labels:\n")
+	fmt.Fprintf(b, "      namespace: %s\n", strconv.Quote(change.Namespace))
+	fmt.Fprintf(b, "      workload_kind: %s\n", strconv.Quote(change.WorkloadKind))
+	fmt.Fprintf(b, "      workload: %s\n", strconv.Quote(change.WorkloadName))
+	fmt.Fprintf(b, "      container: %s\n", strconv.Quote(change.ContainerName))
+}
+
+// cpuCores formats a CPU quantity in cores
+func cpuCores(q resource.Quantity) string {
+	return strconv.FormatFloat(float64(q.MilliValue())/1000, 'f', -1, 64)
+}
+
+// RulesWriter periodically writes the latest recommendations to a ConfigMap as Prometheus
+// recording rules. It implements manager.Runnable so it can be added to the controller manager.
+type RulesWriter struct {
+	collector *Collector
+	client    client.Client
+	reader    client.Reader
+	interval  time.Duration
+	namespace string
+	name      string
+}
+
+// NewRulesWriter creates a writer for the given ConfigMap. The ConfigMap is read back through
+// reader, which should bypass the manager's cache: the operator may not list or watch ConfigMaps.
+func NewRulesWriter(collector *Collector, c client.Client, reader client.Reader, interval time.Duration, namespace, name string) *RulesWriter {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &RulesWriter{
+		collector: collector,
+		client:    c,
+		reader:    reader,
+		interval:  interval,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+// Start writes the recording rules every interval until the context is cancelled
+func (w *RulesWriter) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := w.WriteRules(ctx); err != nil {
+				log.Error(err, "Failed to write recommendation recording rules")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection ensures only the leader writes the rules
+func (w *RulesWriter) NeedLeaderElection() bool {
+	return true
+}
+
+// WriteRules renders the collected recommendations and stores them in the target ConfigMap. The
+// rule set is rebuilt on every write, so workloads that are gone drop out with their recommendations.
+func (w *RulesWriter) WriteRules(ctx context.Context) error {
+	changes := w.collector.Changes()
+	if err := writeConfigMapKey(ctx, w.reader, w.client, w.namespace, w.name, RulesDataKey, RenderRecordingRules(changes)); err != nil {
+		return fmt.Errorf("failed to write recording rules: %w", err)
+	}
+
+	logf.FromContext(ctx).V(1).Info("Wrote recommendation recording rules",
+		"configMap", w.namespace+"/"+w.name, "containers", len(changes))
+	return nil
+}
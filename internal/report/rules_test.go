@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRenderRecordingRules(t *testing.T) {
+	rules := RenderRecordingRules([]ContainerChange{
+		newChange("team-a", "api", "app", "500m", "512Mi", "250m", "768Mi"),
+	})
+
+	wantLines := []string{
+		"- name: " + RuleGroupName,
+		"  - record: " + RecommendedCPURecord,
+		"    expr: vector(0.25)",
+		"  - record: " + RecommendedMemoryRecord,
+		"    expr: vector(805306368)",
+		"  - record: " + CurrentCPURecord,
+		"    expr: vector(0.5)",
+		"  - record: " + CurrentMemoryRecord,
+		"    expr: vector(536870912)",
+		`      namespace: "team-a"`,
+		`      workload_kind: "Deployment"`,
+		`      workload: "api"`,
+		`      container: "app"`,
+	}
+	for _, line := range wantLines {
+		if !strings.Contains(rules, line+"\n") {
+			t.Errorf("rendered rules missing line %q:\n%s", line, rules)
+		}
+	}
+
+	if got := strings.Count(rules, "- record:"); got != 4 {
+		t.Errorf("expected 4 recording rules for one container, got %d", got)
+	}
+}
+
+func TestRenderRecordingRules_Empty(t *testing.T) {
+	rules := RenderRecordingRules(nil)
+	if !strings.Contains(rules, "  rules: []\n") {
+		t.Errorf("expected an empty rule list, got:\n%s", rules)
+	}
+}
+
+func TestCollector_Changes(t *testing.T) {
+	collector := NewCollector()
+	collector.Record(newChange("team-b", "api", "app", "100m", "64Mi", "100m", "64Mi"))
+	collector.Record(newChange("team-a", "worker", "app", "100m", "64Mi", "100m", "64Mi"))
+	collector.Record(newChange("team-a", "api", "app", "100m", "64Mi", "100m", "64Mi"))
+
+	changes := collector.Changes()
+	got := make([]string, 0, len(changes))
+	for _, change := range changes {
+		got = append(got, change.Namespace+"/"+change.WorkloadName)
+	}
+
+	want := "team-a/api,team-a/worker,team-b/api"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Changes() order = %v, want %s", got, want)
+	}
+}
+
+func TestRulesWriter_WriteRules(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	collector := NewCollector()
+	writer := NewRulesWriter(collector, k8sClient, k8sClient, time.Minute, "monitoring", "optipod-rules")
+	ctx := context.Background()
+
+	// The first write creates the ConfigMap, the second picks up new recommendations
+	if err := writer.WriteRules(ctx); err != nil {
+		t.Fatalf("first write failed: %v", err)
+	}
+	collector.Record(newChange("default", "api", "app", "100m", "128Mi", "200m", "256Mi"))
+	if err := writer.WriteRules(ctx); err != nil {
+		t.Fatalf("second write failed: %v", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "monitoring", Name: "optipod-rules"}, configMap); err != nil {
+		t.Fatalf("expected rules ConfigMap to exist: %v", err)
+	}
+	if !strings.Contains(configMap.Data[RulesDataKey], `workload: "api"`) {
+		t.Errorf("expected rules for the recorded workload, got:\n%s", configMap.Data[RulesDataKey])
+	}
+
+	// A workload not recorded within the retention, e.g. because it was deleted, drops out of the rules
+	now := time.Now()
+	collector.now = func() time.Time { return now.Add(DefaultRetention / 2) }
+	collector.Record(newChange("default", "worker", "app", "100m", "128Mi", "200m", "256Mi"))
+	collector.now = func() time.Time { return now.Add(DefaultRetention + time.Minute) }
+	if err := writer.WriteRules(ctx); err != nil {
+		t.Fatalf("third write failed: %v", err)
+	}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "monitoring", Name: "optipod-rules"}, configMap); err != nil {
+		t.Fatalf("expected rules ConfigMap to exist: %v", err)
+	}
+	if rules := configMap.Data[RulesDataKey]; strings.Contains(rules, `workload: "api"`) || !strings.Contains(rules, `workload: "worker"`) {
+		t.Errorf("expected only the recently recorded workload in the rules, got:\n%s", rules)
+	}
+}